@@ -0,0 +1,106 @@
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// assertionFailedExitCode is returned when a --fail-on condition holds, ahead of any
+// outcome's exit code so CI can tell a regression from an ordinary failed scenario
+const assertionFailedExitCode = 4
+
+// assertion is a --fail-on condition on a result stat, e.g. penetration>0.1
+type assertion struct {
+	Stat      string  `json:"stat"`
+	Op        string  `json:"op"`
+	Threshold float64 `json:"threshold"`
+}
+
+// assertionResult is an assertion checked against a finished run
+type assertionResult struct {
+	assertion
+	Value  float64 `json:"value"`
+	Failed bool    `json:"failed"`
+}
+
+// Longer operators first so ">=" isn't read as ">"
+var assertionOps = []string{">=", "<=", "==", "!=", ">", "<"}
+
+// parseAssertion parses "<stat><op><value>", e.g. "penetration>0.1"
+func parseAssertion(spec string) (assertion, error) {
+	spec = strings.TrimSpace(spec)
+	for _, op := range assertionOps {
+		i := strings.Index(spec, op)
+		if i <= 0 {
+			continue
+		}
+		threshold, err := strconv.ParseFloat(strings.TrimSpace(spec[i+len(op):]), 64)
+		if err != nil {
+			return assertion{}, fmt.Errorf("invalid --fail-on %q: %w", spec, err)
+		}
+		return assertion{Stat: strings.TrimSpace(spec[:i]), Op: op, Threshold: threshold}, nil
+	}
+	return assertion{}, fmt.Errorf("invalid --fail-on %q: expected <stat><op><value>, e.g. penetration>0.1", spec)
+}
+
+// parseAssertions parses every --fail-on value
+func parseAssertions(specs []string) ([]assertion, error) {
+	assertions := make([]assertion, 0, len(specs))
+	for _, spec := range specs {
+		a, err := parseAssertion(spec)
+		if err != nil {
+			return nil, err
+		}
+		assertions = append(assertions, a)
+	}
+	return assertions, nil
+}
+
+// holds reports whether the failure condition is met by value
+func (a assertion) holds(value float64) bool {
+	switch a.Op {
+	case ">":
+		return value > a.Threshold
+	case ">=":
+		return value >= a.Threshold
+	case "<":
+		return value < a.Threshold
+	case "<=":
+		return value <= a.Threshold
+	case "==":
+		return value == a.Threshold
+	case "!=":
+		return value != a.Threshold
+	}
+	return false
+}
+
+func (a assertion) String() string {
+	return fmt.Sprintf("%s%s%g", a.Stat, a.Op, a.Threshold)
+}
+
+// checkAssertions evaluates assertions against a run's stats. A stat the run didn't
+// report is an error rather than a pass, so a typo can't silently disable a gate.
+func checkAssertions(assertions []assertion, stats map[string]float64) ([]assertionResult, error) {
+	results := make([]assertionResult, 0, len(assertions))
+	for _, a := range assertions {
+		value, ok := stats[a.Stat]
+		if !ok {
+			return nil, fmt.Errorf("--fail-on %s: the run reported no %q stat", a, a.Stat)
+		}
+		results = append(results, assertionResult{assertion: a, Value: value, Failed: a.holds(value)})
+	}
+	return results, nil
+}
+
+// runExitCode is the status a run ends with: its outcome's, unless a --fail-on
+// condition held
+func runExitCode(outcome string, checks []assertionResult) int {
+	for _, check := range checks {
+		if check.Failed {
+			return assertionFailedExitCode
+		}
+	}
+	return outcomeExitCodes[outcome]
+}
@@ -0,0 +1,105 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+func TestParseAssertion(t *testing.T) {
+	tests := []struct {
+		spec    string
+		want    assertion
+		wantErr string
+	}{
+		{spec: "penetration>0.1", want: assertion{Stat: "penetration", Op: ">", Threshold: 0.1}},
+		{spec: "penetration>=0.1", want: assertion{Stat: "penetration", Op: ">=", Threshold: 0.1}},
+		{spec: "uas_eliminated<5", want: assertion{Stat: "uas_eliminated", Op: "<", Threshold: 5}},
+		{spec: "uas_eliminated<=5", want: assertion{Stat: "uas_eliminated", Op: "<=", Threshold: 5}},
+		{spec: "counter_uas_losses==0", want: assertion{Stat: "counter_uas_losses", Op: "==", Threshold: 0}},
+		{spec: "counter_uas_losses!=0", want: assertion{Stat: "counter_uas_losses", Op: "!=", Threshold: 0}},
+		{spec: "  penetration >= 0.25 ", want: assertion{Stat: "penetration", Op: ">=", Threshold: 0.25}},
+		{spec: "penetration>high", wantErr: "invalid --fail-on"},
+		{spec: "penetration", wantErr: "expected <stat><op><value>"},
+		{spec: ">0.1", wantErr: "expected <stat><op><value>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parseAssertion(tt.spec)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got != tt.want {
+				t.Errorf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestCheckAssertions(t *testing.T) {
+	stats := map[string]float64{"penetration": 0.1, "uas_eliminated": 5}
+	tests := []struct {
+		spec   string
+		failed bool
+	}{
+		{spec: "penetration>0.1", failed: false},
+		{spec: "penetration>=0.1", failed: true},
+		{spec: "penetration<0.1", failed: false},
+		{spec: "penetration<=0.1", failed: true},
+		{spec: "uas_eliminated==5", failed: true},
+		{spec: "uas_eliminated!=5", failed: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			a, err := parseAssertion(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			results, err := checkAssertions([]assertion{a}, stats)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(results) != 1 || results[0].Failed != tt.failed || results[0].Value != stats[a.Stat] {
+				t.Errorf("expected failed=%v, got %+v", tt.failed, results)
+			}
+		})
+	}
+
+	if _, err := checkAssertions([]assertion{{Stat: "penetraton", Op: ">", Threshold: 0.1}}, stats); err == nil {
+		t.Error("expected a stat the run didn't report to be an error")
+	}
+}
+
+func TestRunExitCode(t *testing.T) {
+	passed := []assertionResult{{assertion: assertion{Stat: "penetration", Op: ">", Threshold: 0.1}, Value: 0.05}}
+	failed := []assertionResult{passed[0], {assertion: assertion{Stat: "uas_eliminated", Op: "<", Threshold: 5}, Value: 3, Failed: true}}
+
+	tests := []struct {
+		name    string
+		outcome string
+		checks  []assertionResult
+		want    int
+	}{
+		{name: "success", outcome: simulation.OutcomeSuccess, want: 0},
+		{name: "partial", outcome: simulation.OutcomePartial, checks: passed, want: 3},
+		{name: "failed check on success", outcome: simulation.OutcomeSuccess, checks: failed, want: assertionFailedExitCode},
+		{name: "failed check overrides failure", outcome: simulation.OutcomeFailure, checks: failed, want: assertionFailedExitCode},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := runExitCode(tt.outcome, tt.checks); got != tt.want {
+				t.Errorf("expected exit code %d, got %d", tt.want, got)
+			}
+		})
+	}
+}
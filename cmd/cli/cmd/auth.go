@@ -0,0 +1,349 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/auth"
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/config"
+	"github.com/picogrid/legion-simulations/pkg/credentials"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+var authCmd = &cobra.Command{
+	Use:   "auth",
+	Short: "Manage stored Legion credentials",
+	Long: `Store Legion API keys in the OS keychain (macOS Keychain or libsecret) or, when no
+keychain is available, in a passphrase-encrypted file at ~/.legion-sim/credentials.enc.
+
+Stored keys are used by "run" for the matching environment, so API keys no longer need to
+live in plaintext .env files. Set LEGION_CREDENTIAL_STORE=file to force the encrypted file.`,
+}
+
+var authLoginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Store an API key for an environment",
+	RunE:  authLogin,
+}
+
+var authLogoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: "Remove the stored API key for an environment",
+	RunE:  authLogout,
+}
+
+var authStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show where each environment's credentials come from",
+	RunE:  authStatus,
+}
+
+func init() {
+	authLoginCmd.Flags().Bool("no-verify", false, "store the key without testing it against Legion")
+	authLoginCmd.Flags().Bool("api-key-stdin", false, "read the API key from stdin instead of prompting")
+
+	authCmd.AddCommand(authLoginCmd)
+	authCmd.AddCommand(authLogoutCmd)
+	authCmd.AddCommand(authStatusCmd)
+	addOutputFlag(authStatusCmd)
+}
+
+// credentialStatus is where one environment's credentials come from, with the key masked
+type credentialStatus struct {
+	Environment string `json:"environment"`
+	URL         string `json:"url"`
+	Source      string `json:"source"`
+	Key         string `json:"key,omitempty"`
+}
+
+func authLogin(cmd *cobra.Command, _ []string) error {
+	env, err := selectConfiguredEnvironment("Select environment to log in to:")
+	if err != nil {
+		return err
+	}
+
+	var apiKey string
+	if fromStdin, _ := cmd.Flags().GetBool("api-key-stdin"); fromStdin {
+		if _, err := fmt.Fscanln(os.Stdin, &apiKey); err != nil {
+			return fmt.Errorf("failed to read API key from stdin: %w", err)
+		}
+	} else {
+		keyPrompt := &survey.Password{
+			Message: fmt.Sprintf("API key for %s:", env.Name),
+		}
+		if err := survey.AskOne(keyPrompt, &apiKey, survey.WithValidator(survey.Required)); err != nil {
+			return err
+		}
+	}
+
+	if noVerify, _ := cmd.Flags().GetBool("no-verify"); !noVerify {
+		legionClient, err := client.NewLegionClient(env.URL, apiKey)
+		if err != nil {
+			return fmt.Errorf("failed to create Legion client: %w", err)
+		}
+		logger.Progress("Testing API key against Legion...")
+		if err := legionClient.ValidateConnection(context.Background()); err != nil {
+			return fmt.Errorf("API key rejected by %s: %w", env.URL, err)
+		}
+	}
+
+	store, err := credentials.Open(promptPassphrase)
+	if err != nil {
+		return fmt.Errorf("failed to open credential store: %w", err)
+	}
+	if err := store.Set(env.Name, apiKey); err != nil {
+		return err
+	}
+
+	logger.Successf("Stored API key for %s in %s", env.Name, store.Name())
+	return nil
+}
+
+func authLogout(_ *cobra.Command, _ []string) error {
+	env, err := selectConfiguredEnvironment("Select environment to log out of:")
+	if err != nil {
+		return err
+	}
+
+	store, err := credentials.Open(promptPassphrase)
+	if err != nil {
+		return fmt.Errorf("failed to open credential store: %w", err)
+	}
+	if err := store.Delete(env.Name); err != nil {
+		if errors.Is(err, credentials.ErrNotFound) {
+			logger.Infof("No stored credentials for %s", env.Name)
+			return nil
+		}
+		return err
+	}
+
+	logger.Successf("Removed stored API key for %s", env.Name)
+	return nil
+}
+
+func authStatus(cmd *cobra.Command, _ []string) error {
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if output == "json" {
+		_, restore := jsonStdout()
+		defer restore()
+	}
+	cfg, err := config.LoadEnvironments()
+	if err != nil {
+		return fmt.Errorf("failed to load environments: %w", err)
+	}
+
+	store, storeErr := credentials.Open(promptPassphrase)
+	if storeErr != nil {
+		logger.Warnf("Credential store unavailable: %v", storeErr)
+	}
+
+	statuses := make([]credentialStatus, 0, len(cfg.Environments)+1)
+	for _, env := range cfg.Environments {
+		source, key := "OAuth (interactive)", ""
+		if value := client.GetAPIKey(env.APIKey); value != "" {
+			source, key = "env $"+env.APIKey, credentials.Mask(value)
+		} else if store != nil {
+			if value, err := store.Get(env.Name); err == nil {
+				source, key = "credential store", credentials.Mask(value)
+			} else if !errors.Is(err, credentials.ErrNotFound) {
+				source = "error: " + err.Error()
+			} else if session, ok := storedSession(store, env.URL); ok {
+				source = sessionStatus(session)
+			} else if env.APIKey != "" {
+				source = "env $" + env.APIKey + " (unset)"
+			}
+		}
+		statuses = append(statuses, credentialStatus{env.Name, env.URL, source, key})
+	}
+
+	if os.Getenv("LEGION_API_KEY") != "" {
+		statuses = append(statuses, credentialStatus{"(LEGION_URL)", os.Getenv("LEGION_URL"), "env $LEGION_API_KEY",
+			credentials.Mask(os.Getenv("LEGION_API_KEY"))})
+	}
+
+	if output == "json" {
+		report := struct {
+			Store        string             `json:"store,omitempty"`
+			Environments []credentialStatus `json:"environments"`
+		}{Environments: statuses}
+		if store != nil {
+			report.Store = store.Name()
+		}
+		return writeJSON(out, report)
+	}
+
+	if store != nil {
+		_, _ = fmt.Fprintf(out, "Credential store: %s\n\n", store.Name())
+	}
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "ENVIRONMENT\tURL\tCREDENTIAL SOURCE\tKEY")
+	_, _ = fmt.Fprintln(w, "-----------\t---\t-----------------\t---")
+	for _, status := range statuses {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", status.Environment, status.URL, status.Source, status.Key)
+	}
+	return w.Flush()
+}
+
+// sessionStatus describes a stored "login" session for status output
+func sessionStatus(session auth.Session) string {
+	switch {
+	case session.RefreshExpired():
+		return "login (expired)"
+	case session.RefreshExpiresAt.IsZero():
+		return "login (offline token)"
+	default:
+		return "login (until " + session.RefreshExpiresAt.Local().Format(time.DateTime) + ")"
+	}
+}
+
+// selectConfiguredEnvironment resolves --profile, LEGION_PROFILE or the selected profile,
+// or prompts for a configured environment
+func selectConfiguredEnvironment(message string) (*config.Environment, error) {
+	cfg, err := config.LoadEnvironments()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load environments: %w", err)
+	}
+	if len(cfg.Environments) == 0 {
+		return nil, fmt.Errorf("no environments configured; run 'legion-sim env add' first")
+	}
+
+	selected := defaultProfile(cfg)
+	if selected == "" {
+		names := make([]string, len(cfg.Environments))
+		for i, env := range cfg.Environments {
+			names[i] = env.Name
+		}
+		prompt := &survey.Select{
+			Message: message,
+			Options: names,
+		}
+		if err := survey.AskOne(prompt, &selected); err != nil {
+			return nil, err
+		}
+	}
+
+	return cfg.Find(selected)
+}
+
+// storedAPIKey returns the API key saved by "auth login" for an environment, if any
+func storedAPIKey(environment string) string {
+	store, err := openCredentialStore()
+	if err != nil {
+		logger.Debugf("Credential store unavailable: %v", err)
+		return ""
+	}
+	apiKey, err := store.Get(environment)
+	if err != nil {
+		if !errors.Is(err, credentials.ErrNotFound) {
+			logger.Warnf("Failed to read stored credentials for %s: %v", environment, err)
+		}
+		return ""
+	}
+	return apiKey
+}
+
+// openCredentialStore opens the credential store, prompting for the file passphrase
+// unless running headless
+func openCredentialStore() (credentials.Store, error) {
+	// Headless runs can only open the encrypted file with LEGION_CREDENTIALS_PASSPHRASE
+	passphrase := credentials.PassphraseFunc(promptPassphrase)
+	if headless {
+		passphrase = nil
+	}
+	return credentials.Open(passphrase)
+}
+
+// sessionKey is the credential store entry holding the "login" session for a Legion URL.
+// Sessions are keyed by URL rather than profile so --url and LEGION_URL runs find them.
+func sessionKey(legionURL string) string {
+	return "oauth:" + strings.TrimRight(legionURL, "/")
+}
+
+// storedSession returns the session saved by "login" for a Legion URL, if any
+func storedSession(store credentials.Store, legionURL string) (auth.Session, bool) {
+	data, err := store.Get(sessionKey(legionURL))
+	if err != nil {
+		if !errors.Is(err, credentials.ErrNotFound) {
+			logger.Warnf("Failed to read stored login for %s: %v", legionURL, err)
+		}
+		return auth.Session{}, false
+	}
+	session, err := auth.ParseSession(data)
+	if err != nil {
+		logger.Warnf("Ignoring stored login for %s: %v", legionURL, err)
+		return auth.Session{}, false
+	}
+	return session, true
+}
+
+// saveSession stores a "login" session for a Legion URL
+func saveSession(store credentials.Store, legionURL string, session auth.Session) error {
+	data, err := session.Marshal()
+	if err != nil {
+		return err
+	}
+	return store.Set(sessionKey(legionURL), data)
+}
+
+// storedTokenManager restores the session "login" stored for a Legion URL. Refreshed
+// tokens are written back to the store so the next run picks them up. It returns nil
+// when there is no usable session.
+func storedTokenManager(legionURL string) *auth.TokenManager {
+	store, err := openCredentialStore()
+	if err != nil {
+		logger.Debugf("Credential store unavailable: %v", err)
+		return nil
+	}
+	session, ok := storedSession(store, legionURL)
+	if !ok {
+		return nil
+	}
+	if session.RefreshExpired() {
+		logger.Warnf("Stored login for %s has expired; run 'legion-sim login' again", legionURL)
+		return nil
+	}
+
+	tokenManager := auth.NewTokenManagerFromSession(session)
+	tokenManager.OnRefresh(func(session auth.Session) {
+		if err := saveSession(store, legionURL, session); err != nil {
+			logger.Warnf("Failed to store refreshed login: %v", err)
+		}
+	})
+	logger.Infof("Using stored login for %s from %s", legionURL, store.Name())
+	return tokenManager
+}
+
+// promptPassphrase asks for the encrypted credentials file passphrase
+func promptPassphrase(confirm bool) (string, error) {
+	var passphrase string
+	prompt := &survey.Password{
+		Message: "Credentials file passphrase:",
+	}
+	if err := survey.AskOne(prompt, &passphrase, survey.WithValidator(survey.Required)); err != nil {
+		return "", err
+	}
+
+	if confirm {
+		var again string
+		if err := survey.AskOne(&survey.Password{Message: "Confirm passphrase:"}, &again); err != nil {
+			return "", err
+		}
+		if again != passphrase {
+			return "", fmt.Errorf("passphrases do not match")
+		}
+	}
+	return passphrase, nil
+}
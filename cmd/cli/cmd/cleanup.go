@@ -0,0 +1,268 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/cleanup"
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/runner"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+var cleanupCmd = &cobra.Command{
+	Use:   "cleanup",
+	Short: "Delete simulation-created entities and feeds from an organization",
+	Long: `Delete the entities and feed definitions simulations leave in an organization without
+starting a run, e.g. after a run was killed before it could clean up.
+
+Select what to delete with -s, which uses the naming patterns of that simulation, with
+--prefix for entity name prefixes and --feed for substrings of feed names, or with
+--tag key=value to match a top-level metadata value (such as a run_id). Tags narrow
+the patterns when both are given. Patterns only match names in the --run-id namespace,
+or names outside any namespace without it, so one user's cleanup leaves another's
+namespaced run alone. Everything matched is listed and confirmed before it is deleted;
+--dry-run only lists it.`,
+	Example: `  legion-sim cleanup -s "Drone Swarm Combat" --dry-run
+  legion-sim cleanup --prefix UAS-W --feed cuas_health_telemetry_
+  legion-sim cleanup --tag run_id=4f0c2a9e-1b7d-4c55-9a7e-2d9f8e6b1c30 --yes
+  legion-sim cleanup -s "Drone Swarm Combat" --run-id alice --yes
+  legion-sim cleanup --headless --env staging -s "Drone Swarm Combat" --yes`,
+	RunE: runCleanup,
+}
+
+func init() {
+	cleanupCmd.Flags().StringP("simulation", "s", "", "delete what this simulation's runs create")
+	cleanupCmd.Flags().StringArray("prefix", nil, "delete entities whose name starts with this prefix (repeatable)")
+	cleanupCmd.Flags().StringArray("feed", nil, "delete feed definitions whose name contains this pattern (repeatable)")
+	cleanupCmd.Flags().StringArray("tag", nil, "only delete entities and feeds whose metadata has key=value (repeatable)")
+	cleanupCmd.Flags().String("run-id", "", "only delete names in this run ID's namespace, as given to run --run-id")
+	cleanupCmd.Flags().Bool("dry-run", false, "list what would be deleted without deleting it")
+	cleanupCmd.Flags().BoolP("yes", "y", false, "delete without asking for confirmation")
+	cleanupCmd.Flags().BoolVar(&headless, "headless", false, "never prompt; take the environment and organization from flags and LEGION_* variables")
+	addOutputFlag(cleanupCmd)
+	_ = cleanupCmd.RegisterFlagCompletionFunc("simulation", completeSimulations)
+}
+
+// cleanupReport is what cleanup -o json writes: what matched and, unless it was a dry
+// run or cancelled, what was deleted
+type cleanupReport struct {
+	Entities []cleanupItem   `json:"entities"`
+	Feeds    []cleanupItem   `json:"feeds"`
+	Deleted  *cleanup.Result `json:"deleted,omitempty"`
+}
+
+// cleanupItem is one entity or feed definition a cleanup matched
+type cleanupItem struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+}
+
+func runCleanup(cmd *cobra.Command, _ []string) error {
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	sel, err := cleanupSelector(cmd)
+	if err != nil {
+		return err
+	}
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	yes, _ := cmd.Flags().GetBool("yes")
+	if headless && !dryRun && !yes {
+		return errHeadless("confirmation", "pass --yes to delete, or --dry-run to list what would be deleted")
+	}
+
+	out := cmd.OutOrStdout()
+	if output == "json" {
+		_, restore := jsonStdout()
+		defer restore()
+	}
+
+	envConfig, apiKey, err := selectEnvironment()
+	if err != nil {
+		return fmt.Errorf("failed to select environment: %w", err)
+	}
+	legionClient, err := connectLegion(envConfig, apiKey)
+	if err != nil {
+		return err
+	}
+	org, err := selectOrganization(cmd, envConfig, legionClient)
+	if err != nil {
+		return fmt.Errorf("failed to select organization: %w", err)
+	}
+	orgID, err := uuid.Parse(org)
+	if err != nil {
+		return fmt.Errorf("invalid organization ID format: %w", err)
+	}
+
+	ctx := context.Background()
+	logger.Progress("Searching for entities and feed definitions to delete...")
+	plan, err := cleanup.Find(ctx, legionClient, orgID, sel)
+	if err != nil {
+		return fmt.Errorf("failed to search organization: %w", err)
+	}
+	report := newCleanupReport(plan)
+	if output == "json" {
+		defer func() { _ = writeJSON(out, report) }()
+	} else {
+		writePlan(out, plan)
+	}
+	if dryRun || (len(plan.Entities) == 0 && len(plan.Feeds) == 0) {
+		return nil
+	}
+
+	if !yes {
+		confirm := false
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Delete %d entities and %d feed definitions?", len(plan.Entities), len(plan.Feeds)),
+			Default: false,
+		}
+		if err := survey.AskOne(prompt, &confirm); err != nil {
+			return err
+		}
+		if !confirm {
+			logger.Info("Cleanup cancelled")
+			return nil
+		}
+	}
+
+	result := cleanup.Delete(ctx, legionClient, orgID, plan)
+	report.Deleted = &result
+	logger.Successf("Deleted %d entities and %d feed definitions", result.EntitiesDeleted, result.FeedsDeleted)
+	if result.Failed > 0 {
+		return fmt.Errorf("%d deletions failed (rerun with --log-level debug for details)", result.Failed)
+	}
+	return nil
+}
+
+// cleanupOnExit offers to delete what an interrupted run created, its entities and their
+// feed definitions, rather than leave them in Legion. --cleanup-on-exit answers without
+// a prompt; a headless run without it keeps them.
+func cleanupOnExit(cmd *cobra.Command, legionClient *client.Legion, orgID string, result *runner.Result) {
+	if len(result.Entities) == 0 {
+		return
+	}
+	remove, _ := cmd.Flags().GetBool("cleanup-on-exit")
+	if !cmd.Flags().Changed("cleanup-on-exit") {
+		if headless {
+			logger.Warnf("The interrupted run left %d entities in Legion; pass --cleanup-on-exit to delete them, or run legion-sim cleanup", len(result.Entities))
+			return
+		}
+		prompt := &survey.Confirm{
+			Message: fmt.Sprintf("Delete the %d entities this run created and their feeds?", len(result.Entities)),
+			Default: true,
+		}
+		if err := survey.AskOne(prompt, &remove); err != nil {
+			logger.Warnf("Keeping the run's entities: %v", err)
+			return
+		}
+	}
+	if !remove {
+		logger.Infof("Keeping %d entities in Legion; delete them later with legion-sim cleanup", len(result.Entities))
+		return
+	}
+
+	id, err := uuid.Parse(orgID)
+	if err != nil {
+		logger.Warnf("Cannot clean up: invalid organization ID: %v", err)
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Minute)
+	defer cancel()
+
+	logger.Progress("Deleting the run's entities and feed definitions...")
+	plan, err := cleanup.ForRun(ctx, legionClient, id, result.Entities)
+	if err != nil {
+		logger.Warnf("Some of the run's feeds may be left behind: %v", err)
+	}
+	deleted := cleanup.Delete(ctx, legionClient, id, plan)
+	logger.Successf("Deleted %d entities and %d feed definitions", deleted.EntitiesDeleted, deleted.FeedsDeleted)
+	if deleted.Failed > 0 {
+		logger.Warnf("%d deletions failed; finish with legion-sim cleanup (rerun with --log-level debug for details)", deleted.Failed)
+		return
+	}
+	result.Entities = nil
+}
+
+// cleanupSelector builds the selector from -s, --prefix, --feed and --tag
+func cleanupSelector(cmd *cobra.Command) (cleanup.Selector, error) {
+	var sel cleanup.Selector
+	if name, _ := cmd.Flags().GetString("simulation"); name != "" {
+		sim, err := simulation.DefaultRegistry.Get(name)
+		if err != nil {
+			return sel, err
+		}
+		cleaner, ok := sim.(simulation.Cleaner)
+		if !ok {
+			return sel, fmt.Errorf("simulation %s does not name what it creates; use --prefix, --feed or --tag", name)
+		}
+		patterns := cleaner.CleanupPatterns()
+		sel.EntityPrefixes = append(sel.EntityPrefixes, patterns.EntityPrefixes...)
+		sel.FeedPatterns = append(sel.FeedPatterns, patterns.FeedPatterns...)
+	}
+
+	prefixes, _ := cmd.Flags().GetStringArray("prefix")
+	feeds, _ := cmd.Flags().GetStringArray("feed")
+	tags, _ := cmd.Flags().GetStringArray("tag")
+	sel.EntityPrefixes = append(sel.EntityPrefixes, prefixes...)
+	sel.FeedPatterns = append(sel.FeedPatterns, feeds...)
+
+	sel.RunID, _ = cmd.Flags().GetString("run-id")
+	if sel.RunID != "" {
+		if err := simulation.ValidateRunID(sel.RunID); err != nil {
+			return sel, err
+		}
+	}
+
+	var err error
+	if sel.Tags, err = cleanup.ParseTags(tags); err != nil {
+		return sel, err
+	}
+	if sel.Empty() {
+		return sel, fmt.Errorf("nothing selected: give -s, --prefix, --feed or --tag")
+	}
+	return sel, nil
+}
+
+// newCleanupReport lists a plan's entities and feed definitions for -o json
+func newCleanupReport(plan *cleanup.Plan) *cleanupReport {
+	report := &cleanupReport{
+		Entities: make([]cleanupItem, 0, len(plan.Entities)),
+		Feeds:    make([]cleanupItem, 0, len(plan.Feeds)),
+	}
+	for _, entity := range plan.Entities {
+		report.Entities = append(report.Entities, cleanupItem{entity.ID.String(), entity.Name})
+	}
+	for _, feed := range plan.Feeds {
+		report.Feeds = append(report.Feeds, cleanupItem{feed.ID.String(), feed.FeedName})
+	}
+	return report
+}
+
+// writePlan lists what a cleanup would delete
+func writePlan(w io.Writer, plan *cleanup.Plan) {
+	if len(plan.Entities) == 0 && len(plan.Feeds) == 0 {
+		_, _ = fmt.Fprintln(w, "Nothing to clean up")
+		return
+	}
+	if len(plan.Entities) > 0 {
+		_, _ = fmt.Fprintf(w, "Entities (%d):\n", len(plan.Entities))
+		for _, entity := range plan.Entities {
+			_, _ = fmt.Fprintf(w, "  %s  %s\n", entity.ID, entity.Name)
+		}
+	}
+	if len(plan.Feeds) > 0 {
+		_, _ = fmt.Fprintf(w, "Feed definitions (%d):\n", len(plan.Feeds))
+		for _, feed := range plan.Feeds {
+			_, _ = fmt.Fprintf(w, "  %s  %s\n", feed.ID, feed.FeedName)
+		}
+	}
+}
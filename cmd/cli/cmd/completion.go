@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/config"
+	"github.com/picogrid/legion-simulations/pkg/daemon"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+	"github.com/picogrid/legion-simulations/pkg/utils"
+)
+
+var completionCmd = &cobra.Command{
+	Use:   "completion <bash|zsh|fish|powershell>",
+	Short: "Generate a shell completion script",
+	Long: `Generate a completion script for bash, zsh, fish or PowerShell.
+
+Besides commands and flags, completion covers registered simulation names (-s),
+configured environments (--env), config and parameters files (--config, -p,
+validate) and simulation parameters (--explain).`,
+	Example: `  # bash, current shell
+  source <(legion-sim completion bash)
+
+  # zsh, every new shell (compinit must be enabled)
+  legion-sim completion zsh > "${fpath[1]}/_legion-sim"
+
+  # fish
+  legion-sim completion fish > ~/.config/fish/completions/legion-sim.fish
+
+  # PowerShell, added to $PROFILE
+  legion-sim completion powershell | Out-String | Invoke-Expression`,
+	ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+	Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+	DisableFlagsInUseLine: true,
+	RunE:                  generateCompletion,
+}
+
+func generateCompletion(cmd *cobra.Command, args []string) error {
+	out := cmd.OutOrStdout()
+	switch args[0] {
+	case "bash":
+		return cmd.Root().GenBashCompletionV2(out, true)
+	case "zsh":
+		return cmd.Root().GenZshCompletion(out)
+	case "fish":
+		return cmd.Root().GenFishCompletion(out, true)
+	case "powershell":
+		return cmd.Root().GenPowerShellCompletionWithDesc(out)
+	}
+	return fmt.Errorf("unsupported shell %q (use bash, zsh, fish or powershell)", args[0])
+}
+
+// completeSimulations completes registered simulation names, described from their
+// simulation.yaml where one is found
+func completeSimulations(_ *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	descriptions := make(map[string]string)
+	if simInfos, err := utils.DiscoverSimulations(); err == nil {
+		for _, info := range simInfos {
+			descriptions[info.Config.Name] = info.Config.Description
+		}
+	}
+
+	names := simulation.DefaultRegistry.List()
+	sort.Strings(names)
+	completions := make([]cobra.Completion, 0, len(names))
+	for _, name := range names {
+		completions = append(completions, cobra.CompletionWithDesc(name, descriptions[name]))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeEnvironments completes the names of configured environments
+func completeEnvironments(_ *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	cfg, err := config.LoadEnvironments()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	completions := make([]cobra.Completion, 0, len(cfg.Environments))
+	for _, env := range cfg.Environments {
+		completions = append(completions, cobra.CompletionWithDesc(env.Name, env.URL))
+	}
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeScenarioFiles completes parameters files
+func completeScenarioFiles(_ *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	return []cobra.Completion{"yaml", "yml"}, cobra.ShellCompDirectiveFilterFileExt
+}
+
+// completeParameters completes parameter names of the simulation given with -s, or of
+// every simulation when none is
+func completeParameters(cmd *cobra.Command, _ []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	simInfos, err := utils.DiscoverSimulations()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	simName, _ := cmd.Flags().GetString("simulation")
+
+	seen := make(map[string]bool)
+	var completions []cobra.Completion
+	for _, info := range simInfos {
+		if simName != "" && info.Config.Name != simName {
+			continue
+		}
+		for _, param := range info.Config.Parameters {
+			if seen[param.Name] {
+				continue
+			}
+			seen[param.Name] = true
+			completions = append(completions, cobra.CompletionWithDesc(param.Name, firstSentence(param.Description)))
+		}
+	}
+	sort.Strings(completions)
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// firstSentence trims a description to its first sentence for completion menus
+func firstSentence(description string) string {
+	description = strings.TrimSpace(description)
+	for i := 0; i < len(description); {
+		end := strings.Index(description[i:], ". ")
+		if end < 0 {
+			break
+		}
+		end += i
+		// Abbreviations such as "e.g." don't end the sentence
+		if !strings.HasSuffix(description[:end], "e.g") && !strings.HasSuffix(description[:end], "i.e") {
+			return description[:end]
+		}
+		i = end + 2
+	}
+	return strings.TrimSuffix(description, ".")
+}
+
+// completeSockets completes the control sockets of running daemons
+func completeSockets(_ *cobra.Command, args []string, _ string) ([]cobra.Completion, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	sockets, err := daemon.Sockets()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	return sockets, cobra.ShellCompDirectiveNoFileComp
+}
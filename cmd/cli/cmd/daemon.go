@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/daemon"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// startDaemon runs this command again in the background, headless and serving status on
+// a control socket, and reports where to find it
+func startDaemon(cmd *cobra.Command) error {
+	if dashboard, _ := cmd.Flags().GetBool("dashboard"); dashboard {
+		return fmt.Errorf("--dashboard needs a terminal and cannot be used with --daemon")
+	}
+
+	socket, _ := cmd.Flags().GetString("socket")
+	if socket == "" {
+		dir, err := daemon.Dir()
+		if err != nil {
+			return err
+		}
+		socket = filepath.Join(dir, uuid.NewString()[:8]+daemon.SocketExt)
+	}
+	socket, err := filepath.Abs(socket)
+	if err != nil {
+		return fmt.Errorf("invalid socket path: %w", err)
+	}
+
+	// The daemon has no terminal to prompt on. A later --socket overrides one already given.
+	args := make([]string, 0, len(os.Args)+3)
+	for _, arg := range os.Args[1:] {
+		if arg == "--daemon" || strings.HasPrefix(arg, "--daemon=") {
+			continue
+		}
+		args = append(args, arg)
+	}
+	args = append(args, "--headless", "--no-color", "--socket", socket)
+
+	logPath := daemon.LogPath(socket)
+	pid, err := daemon.Start(args, logPath)
+	if err != nil {
+		return err
+	}
+	logger.Successf("Simulation running in the background (pid %d)", pid)
+	logger.Infof("Socket: %s", socket)
+	logger.Infof("Log:    %s", logPath)
+	logger.Infof("Check on it with: legion-sim status %s", socket)
+	return nil
+}
+
+// serveStatus answers "legion-sim status" on a control socket for the rest of the run.
+// The returned function closes the socket.
+func serveStatus(socket string, sim simulation.Simulation) (func(), error) {
+	started := time.Now()
+	reporter, _ := sim.(simulation.StatusReporter)
+	server, err := daemon.Listen(socket, func() daemon.Report {
+		report := daemon.Report{PID: os.Getpid(), Simulation: sim.Name(), Started: started}
+		if reporter != nil {
+			report.Status = reporter.Status()
+		} else {
+			report.Status.Phase = "running"
+		}
+		return report
+	})
+	if err != nil {
+		return nil, err
+	}
+	return func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+		defer cancel()
+		_ = server.Shutdown(ctx)
+		_ = os.Remove(socket)
+	}, nil
+}
@@ -0,0 +1,229 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"golang.org/x/term"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+	"github.com/picogrid/legion-simulations/pkg/spectate"
+)
+
+// dashboardRefresh is how often the run dashboard is redrawn
+const dashboardRefresh = time.Second
+
+// dashboardLogLines is how many log lines the dashboard keeps for its log pane
+const dashboardLogLines = 200
+
+// dashboardFinalLines is how many log lines are reprinted when the dashboard closes
+const dashboardFinalLines = 10
+
+// Terminal control sequences
+const (
+	enterAltScreen = "\033[?1049h\033[?25l" // Switch to the alternate screen and hide the cursor
+	leaveAltScreen = "\033[?25h\033[?1049l" // Show the cursor and return to the main screen
+	cursorHome     = "\033[H"
+	clearLine      = "\033[K"
+	clearBelow     = "\033[J"
+)
+
+// ctrlC is the byte a raw terminal sends for Ctrl+C
+const ctrlC = 3
+
+// ansiEscape matches color codes, which the log pane strips before truncating lines
+var ansiEscape = regexp.MustCompile(`\x1b\[[0-9;]*m`)
+
+// logTail keeps the last lines logged while the dashboard is up
+type logTail struct {
+	mu      sync.Mutex
+	lines   []string
+	partial string
+}
+
+// Write splits logged output into lines, keeping the newest dashboardLogLines
+func (t *logTail) Write(p []byte) (int, error) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	text := t.partial + string(p)
+	lines := strings.Split(text, "\n")
+	t.partial = lines[len(lines)-1]
+	for _, line := range lines[:len(lines)-1] {
+		t.lines = append(t.lines, ansiEscape.ReplaceAllString(line, ""))
+	}
+	if over := len(t.lines) - dashboardLogLines; over > 0 {
+		t.lines = append([]string(nil), t.lines[over:]...)
+	}
+	return len(p), nil
+}
+
+// last returns up to n of the newest lines
+func (t *logTail) last(n int) []string {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if n <= 0 {
+		return nil
+	}
+	return append([]string(nil), t.lines[max(len(t.lines)-n, 0):]...)
+}
+
+// startDashboard replaces the scrolling log with a live dashboard redrawn on the
+// alternate screen until the run reports an outcome. Everything logged or printed in
+// the meantime is shown in a pane; output goes back to the terminal once the dashboard
+// closes, which the returned function does early when the run ends without an outcome.
+// When the simulation can pause, p pauses and resumes it. It is safe to call more than
+// once.
+func startDashboard(sim simulation.Watchable) func() {
+	tty := os.Stdout
+	tail := &logTail{}
+	logger.SetOutput(tail)
+
+	// Catch direct prints too, such as section banners
+	pr, pw, err := os.Pipe()
+	copied := make(chan struct{})
+	if err == nil {
+		os.Stdout = pw
+		go func() {
+			defer close(copied)
+			_, _ = io.Copy(tail, pr)
+		}()
+	} else {
+		close(copied)
+	}
+	_, _ = fmt.Fprint(tty, enterAltScreen)
+	pausable, _ := sim.(simulation.Pausable)
+	restoreKeys := readDashboardKeys(pausable)
+
+	done := make(chan struct{})
+	finished := make(chan struct{})
+	go func() {
+		defer close(finished)
+		ticker := time.NewTicker(dashboardRefresh)
+		defer ticker.Stop()
+		for {
+			snapshot := sim.Snapshot()
+			drawDashboard(tty, snapshot, tail, pausable != nil)
+			if snapshot.Status == spectate.StatusComplete {
+				return
+			}
+			select {
+			case <-done:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+
+	var once sync.Once
+	stop := func() {
+		once.Do(func() {
+			close(done)
+			<-finished
+			restoreKeys()
+			_, _ = fmt.Fprint(tty, leaveAltScreen)
+			os.Stdout = tty
+			logger.SetOutput(tty)
+			if pw != nil {
+				_ = pw.Close()
+				<-copied
+				_ = pr.Close()
+			}
+
+			// Leave the final picture and the newest log lines in the scrollback
+			spectate.Render(tty, sim.Snapshot(), spectate.RenderOptions{Live: true})
+			_, _ = color.New(color.Bold).Fprintln(tty, "\nLog")
+			for _, line := range tail.last(dashboardFinalLines) {
+				_, _ = fmt.Fprintln(tty, line)
+			}
+			_, _ = fmt.Fprintln(tty)
+		})
+	}
+	go func() {
+		<-finished
+		stop()
+	}()
+	return stop
+}
+
+// readDashboardKeys puts the terminal in raw mode and acts on single keys: p pauses and
+// resumes the run, Ctrl+C interrupts it as usual. The returned function restores the
+// terminal. Nothing is read when the simulation cannot pause or stdin is not a terminal.
+func readDashboardKeys(sim simulation.Pausable) func() {
+	fd := int(os.Stdin.Fd())
+	if sim == nil || !term.IsTerminal(fd) {
+		return func() {}
+	}
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return func() {}
+	}
+
+	go func() {
+		key := make([]byte, 1)
+		for {
+			if _, err := os.Stdin.Read(key); err != nil {
+				return
+			}
+			switch key[0] {
+			case 'p', 'P', ' ':
+				togglePause(sim, "keyboard")
+			case ctrlC:
+				// Raw mode delivers Ctrl+C as a key rather than a signal
+				if self, err := os.FindProcess(os.Getpid()); err == nil {
+					_ = self.Signal(os.Interrupt)
+				}
+			}
+		}
+	}()
+
+	var once sync.Once
+	return func() {
+		once.Do(func() { _ = term.Restore(fd, state) })
+	}
+}
+
+// drawDashboard redraws the snapshot sized to the terminal, filling the rows left
+// below it with the newest log lines
+func drawDashboard(tty *os.File, snapshot spectate.Snapshot, tail *logTail, pausable bool) {
+	width, height, err := term.GetSize(int(tty.Fd()))
+	if err != nil {
+		width, height = 100, 40
+	}
+	opts := spectate.RenderOptions{
+		Live:      true,
+		MapWidth:  min(61, width-1),
+		MapHeight: min(21, max(height/3, 7)),
+		MaxUnits:  8,
+		MaxEvents: 5,
+	}
+
+	var frame bytes.Buffer
+	spectate.Render(&frame, snapshot, opts)
+	if pausable {
+		hint := "p pause · Ctrl+C stop"
+		if snapshot.Status == spectate.StatusPaused {
+			hint = "p resume · Ctrl+C stop"
+		}
+		_, _ = color.New(color.Faint).Fprintln(&frame, hint)
+	}
+	_, _ = color.New(color.Bold).Fprintln(&frame, "\nLog")
+	rows := height - strings.Count(frame.String(), "\n") - 1
+	for _, line := range tail.last(rows) {
+		if runes := []rune(line); len(runes) > width-1 {
+			line = string(runes[:width-1])
+		}
+		frame.WriteString(line + "\n")
+	}
+
+	// Carriage returns too, since reading keys leaves the terminal in raw mode
+	out := strings.ReplaceAll(strings.TrimSuffix(frame.String(), "\n"), "\n", clearLine+"\r\n")
+	_, _ = fmt.Fprint(tty, cursorHome+out+clearLine+clearBelow)
+}
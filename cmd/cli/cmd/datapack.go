@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/datapack"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+var datapackCmd = &cobra.Command{
+	Use:   "datapack",
+	Short: "Manage versioned scenario data packs",
+	Long: `Data packs hold model data (capability catalogs, Pk tables, terrain) that scenarios
+reference by name and version with the data_pack parameter, e.g.
+"cuas-baseline@1.2.0#sha256:<hex>". Packs are fetched from LEGION_DATA_PACK_SOURCE
+(an http(s) URL or a directory) and cached in ~/.legion-sim/packs.
+
+Runs fetch the packs they need automatically; pull them ahead of time for offline use.`,
+}
+
+var datapackPullCmd = &cobra.Command{
+	Use:     "pull <name@version[#sha256:hex]>...",
+	Short:   "Download data packs into the local cache",
+	Example: `  LEGION_DATA_PACK_SOURCE=https://packs.example.com legion-sim datapack pull cuas-baseline@1.2.0`,
+	Args:    cobra.MinimumNArgs(1),
+	RunE:    datapackPull,
+}
+
+var datapackListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "List cached data packs with their checksums",
+	Args:  cobra.NoArgs,
+	RunE:  datapackList,
+}
+
+func init() {
+	datapackCmd.AddCommand(datapackPullCmd)
+	datapackCmd.AddCommand(datapackListCmd)
+	addOutputFlag(datapackPullCmd)
+	addOutputFlag(datapackListCmd)
+}
+
+// pulledPack is a data pack pull -o json fetched and where it is cached
+type pulledPack struct {
+	datapack.Ref
+	Path string `json:"path"`
+}
+
+func datapackPull(cmd *cobra.Command, args []string) error {
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if output == "json" {
+		_, restore := jsonStdout()
+		defer restore()
+	}
+	store, err := datapack.DefaultStore()
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	pulled := make([]pulledPack, 0, len(args))
+	for _, arg := range args {
+		ref, err := datapack.ParseRef(arg)
+		if err != nil {
+			return err
+		}
+		pack, err := store.Resolve(ctx, ref)
+		if err != nil {
+			return err
+		}
+		logger.Successf("%s cached at %s", pack.Ref, pack.Path)
+		pulled = append(pulled, pulledPack{pack.Ref, pack.Path})
+	}
+	if output == "json" {
+		return writeJSON(out, pulled)
+	}
+	return nil
+}
+
+func datapackList(cmd *cobra.Command, _ []string) error {
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	store, err := datapack.DefaultStore()
+	if err != nil {
+		return err
+	}
+	refs, err := store.List()
+	if err != nil {
+		return err
+	}
+	if output == "json" {
+		if refs == nil {
+			refs = []datapack.Ref{}
+		}
+		return writeJSON(cmd.OutOrStdout(), refs)
+	}
+	if len(refs) == 0 {
+		fmt.Println("No data packs cached")
+		return nil
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tVERSION\tSHA256")
+	_, _ = fmt.Fprintln(w, "----\t-------\t------")
+	for _, ref := range refs {
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", ref.Name, ref.Version, ref.SHA256)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,415 @@
+package cmd
+
+import (
+	"context"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/config"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// Doctor check results
+const (
+	checkPass = "pass"
+	checkWarn = "warn"
+	checkFail = "fail"
+	checkSkip = "skip"
+)
+
+// doctorTimeout bounds each call a check makes
+const doctorTimeout = 30 * time.Second
+
+// slowLatency is the median /v3/me round trip above which runs are likely to fall behind
+const slowLatency = 500 * time.Millisecond
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Check that Legion is reachable and the run's organization can be written to",
+	Long: `Run the checks a simulation depends on before starting one, and explain how to fix
+what fails:
+
+  - the environment resolves and Legion answers over the network
+  - the API key or login session is accepted
+  - the organization exists and you are a member of it
+  - you can create an entity, create a feed on it and ingest a message
+  - API latency is low enough for position updates to keep up
+
+The entity and feed the checks create are named legion-sim-doctor-<id> and deleted
+again. The environment and organization are chosen as for "run". Exits non-zero
+when a check fails.`,
+	Example: `  legion-sim doctor
+  legion-sim doctor --profile staging --org "1st MARDIV" --headless -o json`,
+	Args: cobra.NoArgs,
+	RunE: runDoctor,
+}
+
+// doctorCheck is the result of one check
+type doctorCheck struct {
+	Name   string `json:"name"`
+	Status string `json:"status"`
+	Detail string `json:"detail,omitempty"`
+	Hint   string `json:"hint,omitempty"`
+}
+
+// doctor collects check results; once a check fails, the ones that depend on it are skipped
+type doctor struct {
+	checks []doctorCheck
+}
+
+func init() {
+	addOutputFlag(doctorCmd)
+	doctorCmd.Flags().BoolVar(&headless, "headless", false, "never prompt; take the environment and organization from flags and LEGION_* variables")
+	doctorCmd.Flags().Int("samples", 5, "requests to time for the latency check")
+}
+
+func runDoctor(cmd *cobra.Command, _ []string) error {
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	out := cmd.OutOrStdout()
+	if output == "json" {
+		_, restore := jsonStdout()
+		defer restore()
+	}
+	samples, _ := cmd.Flags().GetInt("samples")
+	if samples < 1 {
+		return fmt.Errorf("--samples must be at least 1")
+	}
+
+	d := &doctor{}
+	d.run(cmd, samples)
+
+	failed := 0
+	for _, check := range d.checks {
+		if check.Status == checkFail {
+			failed++
+		}
+	}
+	if failed > 0 {
+		exitCode = 1
+	}
+
+	if output == "json" {
+		return writeJSON(out, struct {
+			Healthy bool          `json:"healthy"`
+			Checks  []doctorCheck `json:"checks"`
+		}{failed == 0, d.checks})
+	}
+	printDoctor(out, d.checks, failed)
+	return nil
+}
+
+// run performs the checks in order, stopping at the first one later checks depend on
+func (d *doctor) run(cmd *cobra.Command, samples int) {
+	env, apiKey, err := selectEnvironment()
+	if err != nil {
+		d.fail("Environment", err, "add one with 'legion-sim env add', or pass --url or --profile")
+		d.skip("Connectivity", "Authentication", "Organization", "Create entity", "Create feed", "Ingest", "Latency")
+		return
+	}
+	d.pass("Environment", fmt.Sprintf("%s (%s)", env.Name, env.URL))
+
+	if !d.checkConnectivity(env) {
+		d.skip("Authentication", "Organization", "Create entity", "Create feed", "Ingest", "Latency")
+		return
+	}
+
+	legionClient, err := connectLegion(env, apiKey)
+	if err != nil {
+		d.fail("Authentication", err, authHint(err))
+		d.skip("Organization", "Create entity", "Create feed", "Ingest", "Latency")
+		return
+	}
+	d.checkIdentity(legionClient)
+
+	orgID, ok := d.checkOrganization(cmd, env, legionClient)
+	if ok {
+		d.checkWrites(legionClient, orgID)
+	} else {
+		d.skip("Create entity", "Create feed", "Ingest")
+	}
+	d.checkLatency(legionClient, samples)
+}
+
+// checkConnectivity makes an unauthenticated request so network problems are told
+// apart from rejected credentials: any HTTP response means Legion is reachable
+func (d *doctor) checkConnectivity(env *config.Environment) bool {
+	httpClient := &http.Client{Timeout: doctorTimeout}
+	start := time.Now()
+	resp, err := httpClient.Get(strings.TrimSuffix(env.URL, "/") + "/v3/me")
+	if err != nil {
+		d.fail("Connectivity", err, networkHint(err))
+		return false
+	}
+	_ = resp.Body.Close()
+	d.pass("Connectivity", fmt.Sprintf("HTTP %d in %s", resp.StatusCode, time.Since(start).Round(time.Millisecond)))
+	return true
+}
+
+// checkIdentity records who the credentials belong to
+func (d *doctor) checkIdentity(legionClient *client.Legion) {
+	ctx, cancel := context.WithTimeout(context.Background(), doctorTimeout)
+	defer cancel()
+	me, err := legionClient.GetMe(ctx)
+	if err != nil {
+		d.pass("Authentication", "credentials accepted")
+		return
+	}
+	d.pass("Authentication", "signed in as "+me.Email)
+}
+
+// checkOrganization resolves the organization as a run would and checks membership
+func (d *doctor) checkOrganization(cmd *cobra.Command, env *config.Environment, legionClient *client.Legion) (string, bool) {
+	orgID, err := selectOrganization(cmd, env, legionClient)
+	if err != nil {
+		d.fail("Organization", err, "pass --org with a name or ID from your organizations, or set LEGION_ORG_ID")
+		return "", false
+	}
+
+	ctx, cancel := context.WithTimeout(client.WithOrgID(context.Background(), orgID), doctorTimeout)
+	defer cancel()
+	org, err := legionClient.GetOrganization(ctx)
+	if err != nil {
+		d.fail("Organization", err, requestHint(err, "you are not a member of organization "+orgID+"; ask its admin for access or pick another with --org"))
+		return "", false
+	}
+	d.pass("Organization", fmt.Sprintf("%s (%s)", org.Name, orgID))
+	return orgID, true
+}
+
+// checkWrites creates an entity and a feed, ingests a message into the feed and removes
+// both again, as every run does at a larger scale
+func (d *doctor) checkWrites(legionClient *client.Legion, orgID string) {
+	orgUUID, err := uuid.Parse(orgID)
+	if err != nil {
+		d.fail("Create entity", err, "")
+		d.skip("Create feed", "Ingest")
+		return
+	}
+	ctx := client.WithOrgID(context.Background(), orgID)
+	name := "legion-sim-doctor-" + uuid.NewString()[:8]
+
+	category := models.CategoryDEVICE
+	entityType := "Sensor"
+	status := "ACTIVE"
+	entity, took, err := timed(ctx, func(ctx context.Context) (*models.EntityResponse, error) {
+		return legionClient.CreateEntity(ctx, &models.CreateEntityRequest{
+			OrganizationID: &orgUUID,
+			Name:           &name,
+			Category:       &category,
+			Type:           &entityType,
+			Status:         &status,
+		})
+	})
+	if err != nil {
+		d.fail("Create entity", err, requestHint(err, "your role in this organization cannot create entities; ask an admin for a role that can"))
+		d.skip("Create feed", "Ingest")
+		return
+	}
+	d.pass("Create entity", fmt.Sprintf("%s in %s", name, took))
+	defer d.cleanup(ctx, "entity "+entity.ID.String(), func(ctx context.Context) error {
+		return legionClient.DeleteEntity(ctx, entity.ID.String())
+	})
+
+	feedCategory := models.MessageCategoryMESSAGE
+	dataType := "application/json"
+	isActive := true
+	feed, took, err := timed(ctx, func(ctx context.Context) (*models.FeedDefinitionResponse, error) {
+		return legionClient.CreateFeedDefinition(ctx, &models.CreateFeedDefinitionRequest{
+			Category:    &feedCategory,
+			FeedName:    &name,
+			EntityID:    entity.ID,
+			DataType:    &dataType,
+			Description: "Created and deleted by legion-sim doctor",
+			IsActive:    &isActive,
+		})
+	})
+	if err != nil {
+		d.fail("Create feed", err, requestHint(err, "your role in this organization cannot create feeds; runs that publish telemetry will fail"))
+		d.skip("Ingest")
+		return
+	}
+	d.pass("Create feed", fmt.Sprintf("%s in %s", name, took))
+	defer d.cleanup(ctx, "feed "+feed.ID.String(), func(ctx context.Context) error {
+		return legionClient.DeleteFeedDefinition(ctx, feed.ID.String())
+	})
+
+	payload := json.RawMessage(`{"check":"legion-sim doctor"}`)
+	recordedAt := time.Now()
+	_, took, err = timed(ctx, func(ctx context.Context) (struct{}, error) {
+		return struct{}{}, legionClient.IngestFeedData(ctx, &models.IngestFeedDataRequest{
+			EntityID:         &entity.ID,
+			FeedDefinitionID: &feed.ID,
+			RecordedAt:       &recordedAt,
+			Payload:          &payload,
+		})
+	})
+	if err != nil {
+		d.fail("Ingest", err, requestHint(err, "your role in this organization cannot ingest feed data; health telemetry and the threat board will not be published"))
+		return
+	}
+	d.pass("Ingest", "message accepted in "+took.String())
+}
+
+// cleanup deletes something a check created, warning with its ID if that fails so it
+// can be removed by hand
+func (d *doctor) cleanup(ctx context.Context, what string, remove func(context.Context) error) {
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+	if err := remove(ctx); err != nil {
+		d.checks = append(d.checks, doctorCheck{
+			Name:   "Cleanup",
+			Status: checkWarn,
+			Detail: fmt.Sprintf("could not delete %s: %v", what, err),
+			Hint:   "delete it with 'legion-sim cleanup --prefix legion-sim-doctor-'",
+		})
+	}
+}
+
+// checkLatency times authenticated round trips and warns when the median is slow
+func (d *doctor) checkLatency(legionClient *client.Legion, samples int) {
+	durations := make([]time.Duration, 0, samples)
+	for i := 0; i < samples; i++ {
+		_, took, err := timed(context.Background(), legionClient.GetMe)
+		if err != nil {
+			d.fail("Latency", err, requestHint(err, ""))
+			return
+		}
+		durations = append(durations, took)
+	}
+	sort.Slice(durations, func(i, j int) bool { return durations[i] < durations[j] })
+	median, slowest := durations[len(durations)/2], durations[len(durations)-1]
+
+	detail := fmt.Sprintf("median %s, max %s over %d requests", median, slowest, samples)
+	if median > slowLatency {
+		d.checks = append(d.checks, doctorCheck{
+			Name:   "Latency",
+			Status: checkWarn,
+			Detail: detail,
+			Hint:   "position updates may fall behind; run closer to Legion, use a longer update_interval or fewer entities",
+		})
+		return
+	}
+	d.pass("Latency", detail)
+}
+
+// timed calls fn with a per-call timeout and returns how long it took, to the millisecond
+func timed[T any](ctx context.Context, fn func(context.Context) (T, error)) (T, time.Duration, error) {
+	ctx, cancel := context.WithTimeout(ctx, doctorTimeout)
+	defer cancel()
+	start := time.Now()
+	v, err := fn(ctx)
+	return v, time.Since(start).Round(time.Millisecond), err
+}
+
+func (d *doctor) pass(name, detail string) {
+	d.checks = append(d.checks, doctorCheck{Name: name, Status: checkPass, Detail: detail})
+}
+
+func (d *doctor) fail(name string, err error, hint string) {
+	d.checks = append(d.checks, doctorCheck{Name: name, Status: checkFail, Detail: err.Error(), Hint: hint})
+}
+
+func (d *doctor) skip(names ...string) {
+	for _, name := range names {
+		d.checks = append(d.checks, doctorCheck{Name: name, Status: checkSkip})
+	}
+}
+
+// networkHint explains a request that never got an HTTP response
+func networkHint(err error) string {
+	var dnsErr *net.DNSError
+	var certErr *x509.UnknownAuthorityError
+	var hostErr x509.HostnameError
+	switch {
+	case errors.As(err, &dnsErr):
+		return "the host name does not resolve; check the URL with 'legion-sim env list' or --url"
+	case errors.As(err, &certErr), errors.As(err, &hostErr):
+		return "Legion's TLS certificate is not trusted here; install your organization's CA or check the URL"
+	case errors.Is(err, context.DeadlineExceeded), isTimeout(err):
+		return "Legion did not answer in time; check VPN, proxy (HTTPS_PROXY) and firewall settings"
+	default:
+		return "check the URL, and that this machine can reach it (VPN, proxy, firewall)"
+	}
+}
+
+// isTimeout reports whether err is a network timeout
+func isTimeout(err error) bool {
+	var netErr net.Error
+	return errors.As(err, &netErr) && netErr.Timeout()
+}
+
+// authHint explains a failed login or rejected credentials
+func authHint(err error) string {
+	if errors.Is(err, client.ErrUnauthorized) {
+		return "the API key or session was rejected; run 'legion-sim login', or check the key with 'legion-sim auth status'"
+	}
+	if errors.Is(err, errHeadlessInput) {
+		return ""
+	}
+	return requestHint(err, "")
+}
+
+// requestHint explains a failed API call; denied is the hint for a 401 or 403
+func requestHint(err error, denied string) string {
+	switch {
+	case errors.Is(err, client.ErrUnauthorized) && denied != "":
+		return denied
+	case errors.Is(err, client.ErrRateLimited):
+		return "Legion is rate limiting these credentials; wait a minute, or check what else is using the key"
+	case errors.Is(err, context.DeadlineExceeded), isTimeout(err):
+		return "Legion did not answer within " + doctorTimeout.String() + "; it may be overloaded, try again shortly"
+	}
+	return ""
+}
+
+// printDoctor writes the checks as a list with a mark per result and hints under failures
+func printDoctor(w io.Writer, checks []doctorCheck, failed int) {
+	width := 0
+	for _, check := range checks {
+		width = max(width, len(check.Name))
+	}
+	for _, check := range checks {
+		var mark string
+		switch check.Status {
+		case checkPass:
+			mark = color.GreenString("✓")
+		case checkWarn:
+			mark = color.YellowString("!")
+		case checkFail:
+			mark = color.RedString("✗")
+		default:
+			mark = color.HiBlackString("-")
+		}
+		detail := check.Detail
+		if check.Status == checkSkip {
+			detail = color.HiBlackString("skipped")
+		}
+		_, _ = fmt.Fprintf(w, "%s %-*s  %s\n", mark, width, check.Name, detail)
+		if check.Hint != "" {
+			_, _ = fmt.Fprintf(w, "  %*s  → %s\n", width, "", check.Hint)
+		}
+	}
+
+	_, _ = fmt.Fprintln(w)
+	if failed > 0 {
+		_, _ = color.New(color.FgRed).Fprintf(w, "%d check(s) failed\n", failed)
+		return
+	}
+	_, _ = color.New(color.FgGreen).Fprintln(w, "Ready to run simulations")
+}
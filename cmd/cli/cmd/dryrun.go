@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// connectDryRun returns a client answered in memory, for --dry-run, and the organization
+// to run as: --org, LEGION_ORG_ID, an organization_id parameter, or a made-up one. An
+// organization given by name gets a made-up ID, since there is no Legion to look it up
+// in. Nothing is prompted for and no environment is needed.
+func connectDryRun(cmd *cobra.Command) (*client.Legion, *client.Memory, string, error) {
+	orgID := orgFlag
+	if _, err := uuid.Parse(orgID); orgID != "" && err != nil {
+		logger.Infof("Dry run: organization %s stands in as a made-up ID", orgID)
+		orgID = uuid.NewString()
+	}
+	if orgID == "" {
+		orgID = os.Getenv("LEGION_ORG_ID")
+	}
+	if orgID == "" {
+		orgID = os.Getenv("LEGION_ORGANIZATION_ID")
+	}
+	if orgID == "" {
+		orgID = parameterOverride(cmd, "organization_id")
+	}
+	if orgID == "" {
+		orgID = uuid.NewString()
+	} else if _, err := uuid.Parse(orgID); err != nil {
+		return nil, nil, "", fmt.Errorf("invalid organization ID format: %w", err)
+	}
+
+	legionClient, memory := client.NewMemoryClient()
+	legionClient.SetLogBodies(logBodies)
+	logger.Warn("Dry run: Legion is simulated in memory; nothing is sent and no API quota is used")
+	logger.Infof("Using organization ID %s", orgID)
+	return legionClient, memory, orgID, nil
+}
+
+// logDryRun reports what a dry run sent to the in-memory Legion
+func logDryRun(memory *client.Memory) {
+	summary := memory.Summary()
+	if summary.Requests == 0 {
+		return
+	}
+	logger.Infof("Dry run: %d API calls answered in memory; %d entities, %d locations, %d feed definitions and %d feed messages",
+		summary.Requests, summary.Entities, summary.Locations, summary.FeedDefinitions, summary.FeedMessages)
+
+	routes := make([]string, 0, len(summary.ByRoute))
+	for route := range summary.ByRoute {
+		routes = append(routes, route)
+	}
+	sort.Slice(routes, func(i, j int) bool {
+		if summary.ByRoute[routes[i]] != summary.ByRoute[routes[j]] {
+			return summary.ByRoute[routes[i]] > summary.ByRoute[routes[j]]
+		}
+		return routes[i] < routes[j]
+	})
+	lines := make([]string, len(routes))
+	for i, route := range routes {
+		lines[i] = fmt.Sprintf("  %6d  %s", summary.ByRoute[route], route)
+	}
+	if len(lines) > 0 {
+		logger.Infof("Calls by route:\n%s", strings.Join(lines, "\n"))
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"text/tabwriter"
 
 	"github.com/AlecAivazis/survey/v2"
+	"github.com/google/uuid"
 	"github.com/spf13/cobra"
 
 	"github.com/picogrid/legion-simulations/pkg/config"
@@ -35,33 +36,71 @@ var envRemoveCmd = &cobra.Command{
 	RunE:  removeEnvironment,
 }
 
+var envUseCmd = &cobra.Command{
+	Use:   "use [name]",
+	Short: "Select the profile used when --profile is not given",
+	Long: `Select the environment profile commands use when neither --profile nor
+LEGION_PROFILE is given. Without a name, clears the selection.`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeEnvironments,
+	RunE:              useEnvironment,
+}
+
 func init() {
 	envCmd.AddCommand(envListCmd)
 	envCmd.AddCommand(envAddCmd)
 	envCmd.AddCommand(envRemoveCmd)
+	envCmd.AddCommand(envUseCmd)
+	addOutputFlag(envListCmd)
+}
+
+// listedEnvironment is a configured environment as env list -o json writes it
+type listedEnvironment struct {
+	config.Environment
+	Selected bool `json:"selected"`
 }
 
 func listEnvironments(cmd *cobra.Command, args []string) error {
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
 	cfg, err := config.LoadEnvironments()
 	if err != nil {
 		return fmt.Errorf("failed to load environments: %w", err)
 	}
 
+	if output == "json" {
+		envs := make([]listedEnvironment, len(cfg.Environments))
+		for i, env := range cfg.Environments {
+			envs[i] = listedEnvironment{env, env.Name == cfg.Selected}
+		}
+		return writeJSON(cmd.OutOrStdout(), envs)
+	}
+
 	if len(cfg.Environments) == 0 {
 		fmt.Println("No environments configured")
 		return nil
 	}
 
 	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "NAME\tURL\tAUTHENTICATION")
-	_, _ = fmt.Fprintln(w, "----\t---\t--------------")
+	_, _ = fmt.Fprintln(w, "NAME\tURL\tAUTHENTICATION\tORGANIZATION")
+	_, _ = fmt.Fprintln(w, "----\t---\t--------------\t------------")
 
 	for _, env := range cfg.Environments {
 		authInfo := "OAuth (Interactive)"
 		if env.APIKey != "" {
 			authInfo = fmt.Sprintf("API Key (%s)", env.APIKey)
 		}
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\n", env.Name, env.URL, authInfo)
+		name := env.Name
+		if env.Name == cfg.Selected {
+			name += " *"
+		}
+		orgID := env.OrgID
+		if orgID == "" {
+			orgID = "-"
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n", name, env.URL, authInfo, orgID)
 	}
 
 	return w.Flush()
@@ -124,6 +163,23 @@ func addEnvironment(cmd *cobra.Command, args []string) error {
 		env.APIKey = ""
 	}
 
+	// Prompt for the default organization
+	orgPrompt := &survey.Input{
+		Message: "Default organization ID (optional):",
+		Help:    "Organization used when LEGION_ORG_ID and the organization_id parameter are not set",
+	}
+	validateOrg := func(ans interface{}) error {
+		if value, _ := ans.(string); value != "" {
+			if _, err := uuid.Parse(value); err != nil {
+				return fmt.Errorf("invalid organization ID format: %w", err)
+			}
+		}
+		return nil
+	}
+	if err := survey.AskOne(orgPrompt, &env.OrgID, survey.WithValidator(validateOrg)); err != nil {
+		return err
+	}
+
 	// Add to config
 	cfg.Environments = append(cfg.Environments, env)
 
@@ -186,6 +242,9 @@ func removeEnvironment(cmd *cobra.Command, args []string) error {
 		}
 	}
 	cfg.Environments = newEnvs
+	if cfg.Selected == selected {
+		cfg.Selected = ""
+	}
 
 	// Save config
 	if err := config.SaveEnvironments(cfg); err != nil {
@@ -195,3 +254,31 @@ func removeEnvironment(cmd *cobra.Command, args []string) error {
 	fmt.Printf("Environment %s removed successfully\n", selected)
 	return nil
 }
+
+func useEnvironment(cmd *cobra.Command, args []string) error {
+	cfg, err := config.LoadEnvironments()
+	if err != nil {
+		return fmt.Errorf("failed to load environments: %w", err)
+	}
+
+	if len(args) == 0 {
+		cfg.Selected = ""
+	} else {
+		env, err := cfg.Find(args[0])
+		if err != nil {
+			return err
+		}
+		cfg.Selected = env.Name
+	}
+
+	if err := config.SaveEnvironments(cfg); err != nil {
+		return fmt.Errorf("failed to save environments: %w", err)
+	}
+
+	if cfg.Selected == "" {
+		fmt.Println("Profile selection cleared")
+	} else {
+		fmt.Printf("Using profile %s\n", cfg.Selected)
+	}
+	return nil
+}
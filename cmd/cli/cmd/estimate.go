@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/config"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// estimateOutput is an estimate as run --estimate -o json writes it
+type estimateOutput struct {
+	Simulation string `json:"simulation"`
+	Duration   string `json:"duration"`
+	simulation.Estimate
+	TotalCalls    int      `json:"total_calls"`
+	QuotaWarnings []string `json:"quota_warnings,omitempty"`
+}
+
+// estimateSimulation configures a simulation offline and prints its expected Legion load,
+// as text or JSON
+func estimateSimulation(cmd *cobra.Command, format string) error {
+	out := cmd.OutOrStdout()
+	if format == "json" {
+		_, restore := jsonStdout()
+		defer restore()
+	}
+
+	r, err := configureSimulation(cmd, "")
+	if err != nil {
+		return err
+	}
+	sim := r.Simulation()
+
+	estimator, ok := sim.(simulation.Estimator)
+	if !ok {
+		return fmt.Errorf("%s does not support load estimates", sim.Name())
+	}
+	est := estimator.Estimate()
+	quota := estimateQuota()
+	if format == "json" {
+		return writeJSON(out, estimateOutput{
+			Simulation:    sim.Name(),
+			Duration:      est.Duration.String(),
+			Estimate:      est,
+			TotalCalls:    est.TotalCalls(),
+			QuotaWarnings: est.QuotaWarnings(quota.CallsPerMinute, quota.FeedMessagesPerMinute, quota.Entities),
+		})
+	}
+	if err := printEstimate(est); err != nil {
+		return err
+	}
+	checkEstimate(est, quota)
+	return nil
+}
+
+// printEstimate prints the per-source breakdown and totals
+func printEstimate(est simulation.Estimate) error {
+	logger.LogSection(fmt.Sprintf("Expected Legion load over %s", est.Duration))
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "SOURCE\tCALLS\tPER MINUTE")
+	_, _ = fmt.Fprintln(w, "------\t-----\t----------")
+	_, _ = fmt.Fprintf(w, "Setup\t%d\t-\n", est.SetupCalls)
+	for _, line := range est.Breakdown {
+		rate := "-"
+		if line.CallsPerMinute > 0 {
+			rate = fmt.Sprintf("%.0f", line.CallsPerMinute)
+		}
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", line.Source, line.Calls, rate)
+	}
+	_, _ = fmt.Fprintf(w, "Total\t%d\t%.0f\n", est.TotalCalls(), est.PeakCallsPerMinute)
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	fmt.Printf("\nEntities created: %d\nFeed messages: %d (%.0f/min)\n", est.Entities, est.FeedMessages, est.FeedMessagesPerMinute)
+	for _, note := range est.Notes {
+		fmt.Printf("Note: %s\n", note)
+	}
+	return nil
+}
+
+// checkEstimate prints a one-line load summary and warns about any quota the run will exceed
+func checkEstimate(est simulation.Estimate, quota config.Quota) {
+	logger.Infof("Expected Legion load: %d entities, %d API calls (peak %.0f/min), %d feed messages",
+		est.Entities, est.TotalCalls(), est.PeakCallsPerMinute, est.FeedMessages)
+	for _, warning := range est.QuotaWarnings(quota.CallsPerMinute, quota.FeedMessagesPerMinute, quota.Entities) {
+		logger.Warnf("Quota: %s", warning)
+	}
+}
+
+// estimateQuota returns the quota of the profile named by --profile, LEGION_PROFILE or
+// "env use", if any
+func estimateQuota() config.Quota {
+	envConfig, err := config.LoadEnvironments()
+	if err != nil {
+		return config.Quota{}
+	}
+	name := defaultProfile(envConfig)
+	if name == "" {
+		return config.Quota{}
+	}
+	env, err := envConfig.Find(name)
+	if err != nil {
+		logger.Warnf("%v; quotas not checked", err)
+		return config.Quota{}
+	}
+	return env.Quota
+}
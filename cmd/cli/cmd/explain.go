@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+	"github.com/picogrid/legion-simulations/pkg/utils"
+)
+
+// explainedParameter is a parameter as run --explain -o json writes it
+type explainedParameter struct {
+	Simulation string `json:"simulation"`
+	simulation.Parameter
+	Environment string `json:"environment"`
+}
+
+// explainParameter describes a simulation parameter, as text or JSON: its type, default,
+// bounds, the environment variable that sets it and its full description. simName
+// narrows the search to one simulation; name may also be given as its LEGION_* variable.
+func explainParameter(w io.Writer, simName, name, format string) error {
+	simInfos, err := utils.DiscoverSimulations()
+	if err != nil {
+		return fmt.Errorf("failed to discover simulations: %w", err)
+	}
+
+	name = strings.ToLower(strings.TrimPrefix(strings.ToUpper(name), "LEGION_"))
+	found := false
+	var similar []string
+	for _, info := range simInfos {
+		if simName != "" && info.Config.Name != simName {
+			continue
+		}
+		found = found || info.Config.Name == simName
+		for _, param := range info.Config.Parameters {
+			if param.Name == name && format == "json" {
+				return writeJSON(w, explainedParameter{info.Config.Name, param, "LEGION_" + strings.ToUpper(param.Name)})
+			}
+			if param.Name == name {
+				return writeParameter(w, info.Config.Name, param)
+			}
+			if strings.Contains(param.Name, name) {
+				similar = append(similar, param.Name)
+			}
+		}
+	}
+	if simName != "" && !found {
+		return fmt.Errorf("simulation configuration not found for %s", simName)
+	}
+
+	if len(similar) > 0 {
+		sort.Strings(similar)
+		return fmt.Errorf("unknown parameter %q; did you mean: %s", name, strings.Join(similar, ", "))
+	}
+	return fmt.Errorf("unknown parameter %q", name)
+}
+
+// writeParameter prints one parameter's help
+func writeParameter(w io.Writer, simName string, param simulation.Parameter) error {
+	_, _ = fmt.Fprintf(w, "%s (%s)\n\n", param.Name, simName)
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintf(tw, "  Type:\t%s\n", param.Type)
+	_, _ = fmt.Fprintf(tw, "  Default:\t%s\n", formatDefault(param.Default))
+	if param.Required {
+		_, _ = fmt.Fprintln(tw, "  Required:\tyes")
+	}
+	if param.Min != nil || param.Max != nil {
+		_, _ = fmt.Fprintf(tw, "  Range:\t%s to %s\n", formatBound(param.Min, "-∞"), formatBound(param.Max, "∞"))
+	}
+	if len(param.Options) > 0 {
+		_, _ = fmt.Fprintf(tw, "  Options:\t%s\n", strings.Join(param.Options, ", "))
+	}
+	_, _ = fmt.Fprintf(tw, "  Environment:\tLEGION_%s\n", strings.ToUpper(param.Name))
+	if err := tw.Flush(); err != nil {
+		return err
+	}
+
+	if param.Description != "" {
+		_, _ = fmt.Fprintf(w, "\n%s\n", wrapText(param.Description, "  ", 80))
+	}
+	return nil
+}
+
+// wrapText word-wraps text to width, indenting every line
+func wrapText(text, indent string, width int) string {
+	var sb strings.Builder
+	line := indent
+	for _, word := range strings.Fields(text) {
+		if line != indent && len(line)+1+len(word) > width {
+			sb.WriteString(line + "\n")
+			line = indent
+		}
+		if line != indent {
+			line += " "
+		}
+		line += word
+	}
+	sb.WriteString(line)
+	return sb.String()
+}
+
+// formatDefault renders a parameter default, showing empty strings explicitly
+func formatDefault(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "none"
+	case string:
+		return fmt.Sprintf("%q", v)
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// formatBound renders a range bound, or unbounded when it is unset
+func formatBound(value interface{}, unbounded string) string {
+	if value == nil {
+		return unbounded
+	}
+	return fmt.Sprint(value)
+}
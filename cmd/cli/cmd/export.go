@@ -0,0 +1,120 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// Laydown export formats
+const (
+	exportGeoJSON = "geojson"
+	exportKML     = "kml"
+)
+
+var exportCmd = &cobra.Command{
+	Use:   "export",
+	Short: "Export where a run deploys its entities as GeoJSON or KML",
+	Long: `Export the laydown of a configured run without connecting to Legion: the defended
+asset and faction objectives, the Counter-UAS systems, and the launch sites or spawn
+ring threats start from. Open the file in QGIS, Google Earth or any GIS tool to check a
+scenario before running it.
+
+Parameters come from --params, --set and --run-id as for "run"; names are namespaced
+with the run ID. The format follows the file extension (.geojson, .json or .kml) unless
+--format is given, and defaults to GeoJSON on stdout.`,
+	Example: `  legion-sim export -s "Drone Swarm Combat" -p scenario.yaml -o laydown.kml
+  legion-sim export -s "Drone Swarm Combat" --set launch_sites="North:15:0:6" --headless > laydown.geojson`,
+	Args: cobra.NoArgs,
+	RunE: exportLaydown,
+}
+
+func init() {
+	exportCmd.Flags().StringP("simulation", "s", "", "simulation name to export")
+	exportCmd.Flags().StringP("params", "p", "", "parameters file (YAML or JSON)")
+	exportCmd.Flags().StringArray("set", nil, "set a parameter, e.g. --set num_waves=3; overrides --params (repeatable)")
+	exportCmd.Flags().String("run-id", "", "namespace exported names as <run-id>/<name>, as the run would")
+	exportCmd.Flags().BoolVar(&headless, "headless", false, "never prompt; take every input from flags, --params and LEGION_* variables")
+	exportCmd.Flags().StringP("output", "o", "", "write the laydown to file instead of stdout")
+	exportCmd.Flags().String("format", "", "geojson or kml (default from the --output extension, else geojson)")
+	_ = exportCmd.RegisterFlagCompletionFunc("simulation", completeSimulations)
+	_ = exportCmd.RegisterFlagCompletionFunc("params", completeScenarioFiles)
+	_ = exportCmd.RegisterFlagCompletionFunc("set", completeParameters)
+	_ = exportCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions([]cobra.Completion{exportGeoJSON, exportKML}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func exportLaydown(cmd *cobra.Command, _ []string) error {
+	outputFile, _ := cmd.Flags().GetString("output")
+	format, err := exportFormat(cmd, outputFile)
+	if err != nil {
+		return err
+	}
+
+	var out io.Writer = os.Stdout
+	if outputFile == "" {
+		// Keep stdout for the laydown
+		stdout, restore := jsonStdout()
+		defer restore()
+		out = stdout
+	}
+
+	r, err := configureSimulation(cmd, "")
+	if err != nil {
+		return err
+	}
+	sim := r.Simulation()
+
+	mapper, ok := sim.(simulation.Mapper)
+	if !ok {
+		return fmt.Errorf("%s does not support laydown export", sim.Name())
+	}
+	laydown := mapper.Laydown()
+
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+
+	if format == exportKML {
+		err = laydown.WriteKML(out)
+	} else {
+		err = laydown.WriteGeoJSON(out)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to write laydown: %w", err)
+	}
+
+	if outputFile != "" {
+		logger.Successf("Wrote %d features to %s", len(laydown.Features), outputFile)
+	}
+	return nil
+}
+
+// exportFormat returns --format, or the format the output file's extension implies
+func exportFormat(cmd *cobra.Command, outputFile string) (string, error) {
+	format, _ := cmd.Flags().GetString("format")
+	if format == "" {
+		switch strings.ToLower(filepath.Ext(outputFile)) {
+		case ".kml":
+			return exportKML, nil
+		default:
+			return exportGeoJSON, nil
+		}
+	}
+	format = strings.ToLower(format)
+	if format != exportGeoJSON && format != exportKML {
+		return "", fmt.Errorf("unknown export format %q (use geojson or kml)", format)
+	}
+	return format, nil
+}
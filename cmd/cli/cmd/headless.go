@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+	"github.com/picogrid/legion-simulations/pkg/utils"
+)
+
+// headless is set by run --headless: every input comes from flags, files and the
+// environment, and anything that would prompt is an error instead
+var headless bool
+
+// errHeadlessInput is wrapped by every error a headless run returns in place of a prompt
+var errHeadlessInput = errors.New("--headless")
+
+// errHeadless explains what a headless run is missing in place of a prompt
+func errHeadless(missing, hint string) error {
+	return fmt.Errorf("%w: no %s given (%s)", errHeadlessInput, missing, hint)
+}
+
+// setParameters parses --set name=value flags, converting values of declared parameters
+// to their type. Names may also be given as their LEGION_* variable.
+func setParameters(values []string, declared []simulation.Parameter) (map[string]interface{}, error) {
+	types := make(map[string]simulation.Parameter, len(declared))
+	for _, param := range declared {
+		types[param.Name] = param
+	}
+
+	params := make(map[string]interface{}, len(values))
+	for _, value := range values {
+		name, raw, ok := strings.Cut(value, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid --set %q: expected name=value", value)
+		}
+		name = normalizeParameterName(name)
+		param, ok := types[name]
+		if !ok {
+			params[name] = raw
+			continue
+		}
+		converted, err := utils.ParseParameterValue(raw, param)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --set %s: %w", name, err)
+		}
+		params[name] = converted
+	}
+	return params, nil
+}
+
+// normalizeParameterName accepts a parameter by name or by its LEGION_* variable
+func normalizeParameterName(name string) string {
+	return strings.ToLower(strings.TrimPrefix(strings.ToUpper(strings.TrimSpace(name)), "LEGION_"))
+}
+
+// parameterOverride returns a string parameter given with --set or in the --params file,
+// for values needed before the simulation is configured
+func parameterOverride(cmd *cobra.Command, name string) string {
+	sets, _ := cmd.Flags().GetStringArray("set")
+	if params, err := setParameters(sets, nil); err == nil {
+		if value, ok := params[name].(string); ok && value != "" {
+			return value
+		}
+	}
+	if paramsFile, _ := cmd.Flags().GetString("params"); paramsFile != "" {
+		if params, err := utils.LoadParameterFile(paramsFile, nil); err == nil {
+			if value, ok := params[name].(string); ok && value != "" {
+				return value
+			}
+		}
+	}
+	return ""
+}
+
+// checkHeadlessAuth fails a headless OAuth login that would prompt for credentials
+func checkHeadlessAuth() error {
+	if !headless || (os.Getenv("LEGION_EMAIL") != "" && os.Getenv("LEGION_PASSWORD") != "") {
+		return nil
+	}
+	return errHeadless("credentials", "use an API key, run 'legion-sim login' first, or set LEGION_EMAIL and LEGION_PASSWORD")
+}
@@ -2,46 +2,154 @@ package cmd
 
 import (
 	"fmt"
-	"os"
+	"io"
+	"sort"
+	"strings"
 	"text/tabwriter"
 
 	"github.com/spf13/cobra"
 
+	"github.com/picogrid/legion-simulations/pkg/simulation"
 	"github.com/picogrid/legion-simulations/pkg/utils"
 )
 
 var listCmd = &cobra.Command{
-	Use:   "list",
-	Short: "List available simulations",
-	Long:  `List all available simulations with their descriptions`,
-	RunE:  listSimulations,
+	Use:   "list [simulation]",
+	Short: "List available simulations and their parameters",
+	Long: `List every registered simulation with its description and parameter schema: each
+parameter's type, default, valid range or options, and the environment variable that
+sets it. Name a simulation to list only that one. Use run --explain for a parameter's
+full description.`,
+	Example: `  legion-sim list
+  legion-sim list "Drone Swarm Combat"
+  legion-sim list -o json`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeSimulations,
+	RunE:              listSimulations,
+}
+
+func init() {
+	addOutputFlag(listCmd)
+}
+
+// listedSimulation is a registered simulation and the schema from its simulation.yaml
+type listedSimulation struct {
+	Name        string                 `json:"name"`
+	Description string                 `json:"description"`
+	Version     string                 `json:"version,omitempty"`
+	Category    string                 `json:"category,omitempty"`
+	Parameters  []simulation.Parameter `json:"parameters"`
 }
 
 func listSimulations(cmd *cobra.Command, args []string) error {
-	// Discover available simulations
-	simInfos, err := utils.DiscoverSimulations()
+	output, err := outputFormat(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to discover simulations: %w", err)
+		return err
 	}
 
-	if len(simInfos) == 0 {
-		fmt.Println("No simulations found")
-		return nil
+	sims, err := registeredSimulations(simulation.DefaultRegistry)
+	if err != nil {
+		return err
+	}
+	if len(args) == 1 {
+		var match []listedSimulation
+		for _, sim := range sims {
+			if sim.Name == args[0] {
+				match = append(match, sim)
+			}
+		}
+		if len(match) == 0 {
+			return fmt.Errorf("simulation %s not found", args[0])
+		}
+		sims = match
 	}
 
-	// Create tabwriter for formatted output
-	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
-	_, _ = fmt.Fprintln(w, "NAME\tVERSION\tCATEGORY\tDESCRIPTION")
-	_, _ = fmt.Fprintln(w, "----\t-------\t--------\t-----------")
+	if output == "json" {
+		return writeJSON(cmd.OutOrStdout(), sims)
+	}
+	if len(sims) == 0 {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No simulations registered")
+		return nil
+	}
+	return writeSimulations(cmd.OutOrStdout(), sims)
+}
 
+// registeredSimulations lists the simulations in registry by name, with the version,
+// category and parameters of the simulation.yaml of the same name where one is found
+func registeredSimulations(registry *simulation.Registry) ([]listedSimulation, error) {
+	configs := make(map[string]simulation.SimulationConfig)
+	simInfos, err := utils.DiscoverSimulations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover simulations: %w", err)
+	}
 	for _, info := range simInfos {
-		_, _ = fmt.Fprintf(w, "%s\t%s\t%s\t%s\n",
-			info.Config.Name,
-			info.Config.Version,
-			info.Config.Category,
-			info.Config.Description,
-		)
+		configs[info.Config.Name] = info.Config
 	}
 
-	return w.Flush()
+	names := registry.List()
+	sort.Strings(names)
+	sims := make([]listedSimulation, 0, len(names))
+	for _, name := range names {
+		sim, err := registry.Get(name)
+		if err != nil {
+			return nil, err
+		}
+		config := configs[name]
+		sims = append(sims, listedSimulation{
+			Name:        name,
+			Description: sim.Description(),
+			Version:     config.Version,
+			Category:    config.Category,
+			Parameters:  config.Parameters,
+		})
+	}
+	return sims, nil
+}
+
+// writeSimulations prints each simulation with a table of its parameters
+func writeSimulations(w io.Writer, sims []listedSimulation) error {
+	for i, sim := range sims {
+		if i > 0 {
+			_, _ = fmt.Fprintln(w)
+		}
+		header := sim.Name
+		if sim.Version != "" {
+			header += " v" + sim.Version
+		}
+		if sim.Category != "" {
+			header += " (" + sim.Category + ")"
+		}
+		_, _ = fmt.Fprintf(w, "%s\n%s\n\n", header, wrapText(sim.Description, "  ", 80))
+
+		if len(sim.Parameters) == 0 {
+			_, _ = fmt.Fprintln(w, "  No parameter schema found")
+			continue
+		}
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "  PARAMETER\tTYPE\tDEFAULT\tVALID\tENVIRONMENT")
+		for _, param := range sim.Parameters {
+			name := param.Name
+			if param.Required {
+				name += " (required)"
+			}
+			_, _ = fmt.Fprintf(tw, "  %s\t%s\t%s\t%s\tLEGION_%s\n",
+				name, param.Type, formatDefault(param.Default), validValues(param), strings.ToUpper(param.Name))
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validValues describes a parameter's options or range, or "-" when it has neither
+func validValues(param simulation.Parameter) string {
+	switch {
+	case len(param.Options) > 0:
+		return strings.Join(param.Options, "|")
+	case param.Min != nil || param.Max != nil:
+		return formatBound(param.Min, "-∞") + " to " + formatBound(param.Max, "∞")
+	default:
+		return "-"
+	}
 }
@@ -0,0 +1,139 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"runtime"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/auth"
+	"github.com/picogrid/legion-simulations/pkg/credentials"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+var loginCmd = &cobra.Command{
+	Use:   "login",
+	Short: "Log in to Legion in a browser and keep the session for later runs",
+	Long: `Log in to Legion with the OAuth device flow. The CLI shows a code and opens the
+Legion login page; approve the login there, from this machine or any other, and the
+tokens are stored in the credential store (OS keychain or the encrypted file, as for
+"auth login").
+
+Later runs against the same Legion URL use the stored session instead of prompting for
+a password, including --headless runs. The access token is refreshed in the background
+during a run and the refreshed tokens are stored again, so long scenarios keep their
+session. Run "login" again once the session can no longer be refreshed.
+
+Set LEGION_OAUTH_CLIENT_ID or --client-id when the device flow is enabled on a different
+Keycloak client than the default.`,
+	Args: cobra.NoArgs,
+	RunE: login,
+}
+
+var logoutCmd = &cobra.Command{
+	Use:   "logout",
+	Short: `Remove the session stored by "login"`,
+	Args:  cobra.NoArgs,
+	RunE:  logout,
+}
+
+func init() {
+	loginCmd.Flags().Bool("no-browser", false, "print the login URL instead of opening a browser")
+	loginCmd.Flags().String("client-id", os.Getenv("LEGION_OAUTH_CLIENT_ID"), "Keycloak client with the device flow enabled")
+}
+
+func login(cmd *cobra.Command, _ []string) error {
+	env, _, err := selectEnvironment()
+	if err != nil {
+		return err
+	}
+	// Open the store first so a passphrase prompt doesn't wait until after the browser login
+	store, err := openCredentialStore()
+	if err != nil {
+		return fmt.Errorf("failed to open credential store: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(cmd.Context(), os.Interrupt)
+	defer stop()
+
+	clientID, _ := cmd.Flags().GetString("client-id")
+	noBrowser, _ := cmd.Flags().GetBool("no-browser")
+	tokenManager, err := auth.AuthenticateDeviceWithLegion(ctx, env.URL, clientID, func(device *auth.DeviceAuthorization) {
+		loginURL := device.VerificationURIComplete
+		if loginURL == "" {
+			loginURL = device.VerificationURI
+		}
+		logger.Infof("To log in to %s, open %s", env.URL, loginURL)
+		logger.Infof("and confirm the code %s", device.UserCode)
+		if !noBrowser && !headless {
+			if err := openBrowser(loginURL); err != nil {
+				logger.Debugf("Could not open a browser: %v", err)
+			}
+		}
+		logger.Progress("Waiting for the login to be approved...")
+	})
+	if err != nil {
+		if errors.Is(err, context.Canceled) {
+			return fmt.Errorf("login cancelled")
+		}
+		return fmt.Errorf("failed to log in: %w", err)
+	}
+
+	legionClient, err := auth.CreateAuthenticatedClient(env.URL, tokenManager)
+	if err != nil {
+		return fmt.Errorf("failed to create authenticated client: %w", err)
+	}
+	if err := legionClient.ValidateConnection(ctx); err != nil {
+		return fmt.Errorf("login succeeded but Legion rejected the token: %w", err)
+	}
+
+	session := tokenManager.Session()
+	if err := saveSession(store, env.URL, session); err != nil {
+		return fmt.Errorf("failed to store session: %w", err)
+	}
+	logger.Successf("Logged in to %s; session stored in %s", env.URL, store.Name())
+	if !session.RefreshExpiresAt.IsZero() {
+		logger.Infof("Runs can refresh the session until %s", session.RefreshExpiresAt.Local().Format(time.DateTime))
+	}
+	return nil
+}
+
+func logout(_ *cobra.Command, _ []string) error {
+	env, _, err := selectEnvironment()
+	if err != nil {
+		return err
+	}
+	store, err := openCredentialStore()
+	if err != nil {
+		return fmt.Errorf("failed to open credential store: %w", err)
+	}
+	if err := store.Delete(sessionKey(env.URL)); err != nil {
+		if errors.Is(err, credentials.ErrNotFound) {
+			logger.Infof("No stored login for %s", env.URL)
+			return nil
+		}
+		return err
+	}
+	logger.Successf("Removed stored login for %s", env.URL)
+	return nil
+}
+
+// openBrowser opens a URL in the default browser
+func openBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch runtime.GOOS {
+	case "darwin":
+		cmd = exec.Command("open", url)
+	case "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+	return cmd.Start()
+}
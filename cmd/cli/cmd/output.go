@@ -0,0 +1,48 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// addOutputFlag gives a command -o text|json
+func addOutputFlag(cmd *cobra.Command) {
+	cmd.Flags().StringP("output", "o", "text", "output format (text, json); json writes the result to stdout and logs to stderr")
+	_ = cmd.RegisterFlagCompletionFunc("output", cobra.FixedCompletions([]string{"text", "json"}, cobra.ShellCompDirectiveNoFileComp))
+}
+
+// outputFormat returns the command's -o, checked
+func outputFormat(cmd *cobra.Command) (string, error) {
+	output, _ := cmd.Flags().GetString("output")
+	output = strings.ToLower(output)
+	if output != "text" && output != "json" {
+		return "", fmt.Errorf("unknown output format %q (use text or json)", output)
+	}
+	return output, nil
+}
+
+// jsonStdout keeps stdout for a command's JSON result: logs, prompts and anything else
+// printed go to stderr until restore is called. It returns the real stdout.
+func jsonStdout() (stdout io.Writer, restore func()) {
+	out := os.Stdout
+	os.Stdout = os.Stderr
+	logger.SetOutput(os.Stderr)
+	return out, func() {
+		os.Stdout = out
+		logger.SetOutput(out)
+	}
+}
+
+// writeJSON writes v to w as indented JSON
+func writeJSON(w io.Writer, v interface{}) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(v)
+}
@@ -0,0 +1,355 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"text/template"
+
+	"github.com/spf13/cobra"
+	"gopkg.in/yaml.v3"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+	"github.com/picogrid/legion-simulations/pkg/utils"
+)
+
+var packageCmd = &cobra.Command{
+	Use:   "package",
+	Short: "Package a scenario for running outside the CLI",
+	Long: `Generate deployment artifacts for running a simulation unattended.
+
+With --k8s, emits a Kubernetes Job (or CronJob when --schedule is set) that runs
+the scenario non-interactively: parameters are supplied through a ConfigMap,
+credentials through a Secret, logs are written to stdout as JSON, and a metrics
+endpoint is exposed for scraping.`,
+	Example: `  legion-sim package --k8s -s "Drone Swarm Combat" -p scenario.yaml > job.yaml
+  legion-sim package --k8s -s "Drone Swarm Combat" --schedule "0 6 * * *" -o cronjob.yaml`,
+	RunE: packageSimulation,
+}
+
+func init() {
+	packageCmd.Flags().Bool("k8s", false, "generate a Kubernetes Job/CronJob manifest")
+	packageCmd.Flags().StringP("simulation", "s", "", "simulation name to package")
+	packageCmd.Flags().StringP("params", "p", "", "parameters file (YAML map of parameter name to value)")
+	packageCmd.Flags().StringP("output", "o", "", "write manifest to file instead of stdout")
+	packageCmd.Flags().String("name", "", "resource name (default derived from the simulation name)")
+	packageCmd.Flags().String("namespace", "default", "Kubernetes namespace")
+	packageCmd.Flags().String("image", "legion-sim:latest", "container image with the legion-sim binary")
+	packageCmd.Flags().String("schedule", "", "cron schedule; generates a CronJob instead of a Job")
+	packageCmd.Flags().String("secret", "legion-credentials", "Secret providing LEGION_URL and LEGION_API_KEY")
+	packageCmd.Flags().String("org-id", "", "Legion organization ID (LEGION_ORG_ID)")
+	packageCmd.Flags().String("artifact-url", "", "object storage destination for run outputs (LEGION_ARTIFACT_URL)")
+	packageCmd.Flags().String("cpu", "1", "CPU limit")
+	packageCmd.Flags().String("memory", "1Gi", "memory limit")
+	packageCmd.Flags().String("cpu-request", "250m", "CPU request")
+	packageCmd.Flags().String("memory-request", "256Mi", "memory request")
+	packageCmd.Flags().Int("metrics-port", 9090, "port for the in-pod metrics endpoint")
+	packageCmd.Flags().Int("backoff-limit", 0, "Job retry limit")
+	_ = packageCmd.RegisterFlagCompletionFunc("simulation", completeSimulations)
+	_ = packageCmd.RegisterFlagCompletionFunc("params", completeScenarioFiles)
+}
+
+// k8sManifest is the template input for Kubernetes packaging
+type k8sManifest struct {
+	Name          string
+	Namespace     string
+	Image         string
+	Simulation    string
+	Schedule      string
+	Secret        string
+	OrgID         string
+	ArtifactURL   string
+	CPU           string
+	Memory        string
+	CPURequest    string
+	MemoryRequest string
+	MetricsPort   int
+	BackoffLimit  int
+	Params        []envVar
+	Args          []string
+}
+
+type envVar struct {
+	Name  string
+	Value string
+}
+
+var k8sTemplate = template.Must(template.New("k8s").Funcs(template.FuncMap{
+	"quote":   yamlQuote,
+	"indent":  func(n int, s string) string { return strings.ReplaceAll(s, "\n", "\n"+strings.Repeat(" ", n)) },
+	"include": func(string, interface{}) (string, error) { return "", nil }, // Bound per render
+}).Parse(`apiVersion: v1
+kind: ConfigMap
+metadata:
+  name: {{ .Name }}-params
+  namespace: {{ .Namespace }}
+  labels:
+    app.kubernetes.io/name: legion-sim
+    app.kubernetes.io/instance: {{ .Name }}
+data:
+  LEGION_SKIP_PROMPTS: "true"
+{{- if .OrgID }}
+  LEGION_ORG_ID: {{ quote .OrgID }}
+{{- end }}
+{{- if .ArtifactURL }}
+  LEGION_ARTIFACT_URL: {{ quote .ArtifactURL }}
+{{- end }}
+{{- range .Params }}
+  {{ .Name }}: {{ quote .Value }}
+{{- end }}
+---
+{{- define "podspec" }}
+restartPolicy: Never
+containers:
+  - name: legion-sim
+    image: {{ .Image }}
+    args:
+{{- range .Args }}
+      - {{ quote . }}
+{{- end }}
+    envFrom:
+      - configMapRef:
+          name: {{ .Name }}-params
+      - secretRef:
+          name: {{ .Secret }}
+    ports:
+      - name: metrics
+        containerPort: {{ .MetricsPort }}
+    readinessProbe:
+      httpGet:
+        path: /healthz
+        port: metrics
+    resources:
+      requests:
+        cpu: {{ quote .CPURequest }}
+        memory: {{ quote .MemoryRequest }}
+      limits:
+        cpu: {{ quote .CPU }}
+        memory: {{ quote .Memory }}
+{{- end }}
+{{- if .Schedule }}
+apiVersion: batch/v1
+kind: CronJob
+metadata:
+  name: {{ .Name }}
+  namespace: {{ .Namespace }}
+  labels:
+    app.kubernetes.io/name: legion-sim
+    app.kubernetes.io/instance: {{ .Name }}
+spec:
+  schedule: {{ quote .Schedule }}
+  concurrencyPolicy: Forbid
+  jobTemplate:
+    spec:
+      backoffLimit: {{ .BackoffLimit }}
+      template:
+        metadata:
+          labels:
+            app.kubernetes.io/name: legion-sim
+            app.kubernetes.io/instance: {{ .Name }}
+          annotations:
+            prometheus.io/scrape: "true"
+            prometheus.io/port: "{{ .MetricsPort }}"
+        spec:
+          {{- indent 10 (include "podspec" .) }}
+{{- else }}
+apiVersion: batch/v1
+kind: Job
+metadata:
+  name: {{ .Name }}
+  namespace: {{ .Namespace }}
+  labels:
+    app.kubernetes.io/name: legion-sim
+    app.kubernetes.io/instance: {{ .Name }}
+spec:
+  backoffLimit: {{ .BackoffLimit }}
+  template:
+    metadata:
+      labels:
+        app.kubernetes.io/name: legion-sim
+        app.kubernetes.io/instance: {{ .Name }}
+      annotations:
+        prometheus.io/scrape: "true"
+        prometheus.io/port: "{{ .MetricsPort }}"
+    spec:
+      {{- indent 6 (include "podspec" .) }}
+{{- end }}
+`))
+
+func packageSimulation(cmd *cobra.Command, _ []string) error {
+	k8s, _ := cmd.Flags().GetBool("k8s")
+	if !k8s {
+		return fmt.Errorf("no packaging target selected (supported: --k8s)")
+	}
+
+	simName, _ := cmd.Flags().GetString("simulation")
+	if simName == "" {
+		return fmt.Errorf("--simulation is required")
+	}
+
+	simConfig, err := findSimulationConfig(simName)
+	if err != nil {
+		return err
+	}
+
+	manifest := k8sManifest{Simulation: simName}
+	manifest.Name, _ = cmd.Flags().GetString("name")
+	manifest.Namespace, _ = cmd.Flags().GetString("namespace")
+	manifest.Image, _ = cmd.Flags().GetString("image")
+	manifest.Schedule, _ = cmd.Flags().GetString("schedule")
+	manifest.Secret, _ = cmd.Flags().GetString("secret")
+	manifest.OrgID, _ = cmd.Flags().GetString("org-id")
+	manifest.ArtifactURL, _ = cmd.Flags().GetString("artifact-url")
+	manifest.CPU, _ = cmd.Flags().GetString("cpu")
+	manifest.Memory, _ = cmd.Flags().GetString("memory")
+	manifest.CPURequest, _ = cmd.Flags().GetString("cpu-request")
+	manifest.MemoryRequest, _ = cmd.Flags().GetString("memory-request")
+	manifest.MetricsPort, _ = cmd.Flags().GetInt("metrics-port")
+	manifest.BackoffLimit, _ = cmd.Flags().GetInt("backoff-limit")
+
+	if manifest.Name == "" {
+		manifest.Name = k8sName(simName)
+	}
+
+	paramsFile, _ := cmd.Flags().GetString("params")
+	if paramsFile != "" {
+		manifest.Params, err = paramsToEnv(paramsFile, simConfig)
+		if err != nil {
+			return err
+		}
+	}
+
+	manifest.Args = []string{
+		"run",
+		"--simulation", simName,
+		"--log-format", "json",
+		"--no-color",
+		"--metrics-addr", fmt.Sprintf(":%d", manifest.MetricsPort),
+	}
+
+	var out io.Writer = os.Stdout
+	outputFile, _ := cmd.Flags().GetString("output")
+	if outputFile != "" {
+		f, err := os.Create(outputFile)
+		if err != nil {
+			return fmt.Errorf("failed to create output file: %w", err)
+		}
+		defer func() { _ = f.Close() }()
+		out = f
+	}
+
+	if err := renderK8sManifest(out, manifest); err != nil {
+		return err
+	}
+
+	if outputFile != "" {
+		logger.Successf("Wrote Kubernetes manifest to %s", outputFile)
+	}
+	return nil
+}
+
+// renderK8sManifest executes the manifest template, resolving the shared pod spec
+func renderK8sManifest(w io.Writer, manifest k8sManifest) error {
+	tmpl, err := k8sTemplate.Clone()
+	if err != nil {
+		return fmt.Errorf("failed to prepare manifest template: %w", err)
+	}
+	tmpl.Funcs(template.FuncMap{
+		"include": func(name string, data interface{}) (string, error) {
+			var b strings.Builder
+			if err := tmpl.ExecuteTemplate(&b, name, data); err != nil {
+				return "", err
+			}
+			return b.String(), nil
+		},
+	})
+
+	if err := tmpl.Execute(w, manifest); err != nil {
+		return fmt.Errorf("failed to render manifest: %w", err)
+	}
+	return nil
+}
+
+// findSimulationConfig returns the discovered configuration for a simulation
+func findSimulationConfig(simName string) (*simulation.SimulationConfig, error) {
+	simInfos, err := utils.DiscoverSimulations()
+	if err != nil {
+		return nil, fmt.Errorf("failed to discover simulations: %w", err)
+	}
+	for _, info := range simInfos {
+		if info.Config.Name == simName {
+			cfg := info.Config
+			return &cfg, nil
+		}
+	}
+	return nil, fmt.Errorf("simulation configuration not found for %s", simName)
+}
+
+// paramsToEnv converts a YAML parameter file into LEGION_* environment variables
+func paramsToEnv(path string, simConfig *simulation.SimulationConfig) ([]envVar, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parameters file: %w", err)
+	}
+
+	var values map[string]interface{}
+	if err := yaml.Unmarshal(data, &values); err != nil {
+		return nil, fmt.Errorf("failed to parse parameters file: %w", err)
+	}
+
+	envNames := make(map[string]string, len(simConfig.Parameters))
+	for _, param := range simConfig.Parameters {
+		envNames[param.Name] = "LEGION_" + strings.ToUpper(param.Name)
+	}
+
+	vars := make([]envVar, 0, len(values))
+	for key, value := range values {
+		name, ok := envNames[key]
+		if !ok {
+			return nil, fmt.Errorf("unknown parameter %q for simulation %s", key, simConfig.Name)
+		}
+
+		var str string
+		switch v := value.(type) {
+		case string:
+			str = v
+		case []interface{}, map[string]interface{}:
+			encoded, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("failed to encode parameter %s: %w", key, err)
+			}
+			str = string(encoded)
+		default:
+			str = fmt.Sprint(v)
+		}
+		vars = append(vars, envVar{Name: name, Value: str})
+	}
+
+	sort.Slice(vars, func(i, j int) bool { return vars[i].Name < vars[j].Name })
+	return vars, nil
+}
+
+var k8sNameInvalid = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// k8sName converts a simulation name into a valid Kubernetes resource name
+func k8sName(name string) string {
+	slug := strings.Trim(k8sNameInvalid.ReplaceAllString(strings.ToLower(name), "-"), "-")
+	if len(slug) > 52 { // Leave room for CronJob-generated suffixes
+		slug = strings.TrimRight(slug[:52], "-")
+	}
+	if slug == "" {
+		slug = "legion-sim"
+	}
+	return slug
+}
+
+// yamlQuote renders a string as a double-quoted YAML scalar
+func yamlQuote(s string) string {
+	encoded, _ := json.Marshal(s)
+	return string(encoded)
+}
@@ -0,0 +1,19 @@
+package cmd
+
+import (
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// togglePause pauses a running simulation, or resumes it when paused
+func togglePause(sim simulation.Pausable, source string) {
+	if sim.Paused() {
+		if sim.Resume(source) {
+			logger.Info("Resuming simulation...")
+		}
+		return
+	}
+	if sim.Pause(source) {
+		logger.Warn("Pausing simulation...")
+	}
+}
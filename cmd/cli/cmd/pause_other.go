@@ -0,0 +1,10 @@
+//go:build !unix
+
+package cmd
+
+import "github.com/picogrid/legion-simulations/pkg/simulation"
+
+// pauseOnSignal does nothing where there is no SIGUSR1; the dashboard can still pause
+func pauseOnSignal(simulation.Pausable) func() {
+	return func() {}
+}
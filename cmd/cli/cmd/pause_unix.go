@@ -0,0 +1,34 @@
+//go:build unix
+
+package cmd
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// pauseOnSignal pauses or resumes the simulation on every SIGUSR1 until the returned
+// function is called
+func pauseOnSignal(sim simulation.Pausable) func() {
+	usr1 := make(chan os.Signal, 1)
+	signal.Notify(usr1, syscall.SIGUSR1)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-usr1:
+				togglePause(sim, "signal")
+			}
+		}
+	}()
+	return func() {
+		signal.Stop(usr1)
+		close(done)
+	}
+}
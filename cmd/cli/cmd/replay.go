@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/runlog"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+var replayCmd = &cobra.Command{
+	Use:   "replay <file>",
+	Short: "Push a recorded run's entity updates to Legion again",
+	Long: `Re-create a recorded run in Legion without recomputing it, e.g. to demo or debrief a
+run or to reproduce what an operator saw.
+
+Runs started with record_replay write Replay_<run>_<time>.jsonl.gz next to the AAR. It
+holds every entity the run created, each position it reported and each status,
+affiliation or metadata change, timed from the start of the run. Replay creates the
+entities afresh in the selected organization and sends the same updates at the recorded
+pace, or faster with --speed. The run's events are logged as they come due. Feed
+messages are not recorded and are not replayed.
+
+Replayed entities are deleted when the replay ends or is interrupted, unless --keep is set.`,
+	Example: `  legion-sim replay reports/Replay_1a2b3c4d_20260314_233000.jsonl.gz
+  legion-sim replay --speed 4 reports/Replay_1a2b3c4d_20260314_233000.jsonl.gz
+  legion-sim replay --headless --env staging --keep Replay_1a2b3c4d_20260314_233000.jsonl.gz`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	replayCmd.Flags().Float64("speed", 1, "playback rate (2 is twice the recorded pace, 0 sends as fast as Legion accepts)")
+	replayCmd.Flags().Bool("keep", false, "leave the replayed entities in Legion afterwards")
+	replayCmd.Flags().BoolVar(&headless, "headless", false, "never prompt; take the environment and organization from flags and LEGION_* variables")
+	addOutputFlag(replayCmd)
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	speed, _ := cmd.Flags().GetFloat64("speed")
+	if speed < 0 {
+		return fmt.Errorf("invalid speed %g: must be 0 or more", speed)
+	}
+	keep, _ := cmd.Flags().GetBool("keep")
+
+	out := cmd.OutOrStdout()
+	if output == "json" {
+		_, restore := jsonStdout()
+		defer restore()
+	}
+
+	log, err := runlog.Load(args[0])
+	if err != nil {
+		return err
+	}
+	pace := "as fast as Legion accepts"
+	if speed > 0 {
+		pace = fmt.Sprintf("over %s", time.Duration(float64(log.Duration())/speed).Round(time.Second))
+	}
+	logger.Infof("Replaying %s run %s from %s: %d records %s",
+		log.Header.Simulation, log.Header.RunID, log.Header.Started.Format(time.RFC3339), len(log.Records), pace)
+
+	envConfig, apiKey, err := selectEnvironment()
+	if err != nil {
+		return fmt.Errorf("failed to select environment: %w", err)
+	}
+	legionClient, err := connectLegion(envConfig, apiKey)
+	if err != nil {
+		return err
+	}
+	org, err := selectOrganization(cmd, envConfig, legionClient)
+	if err != nil {
+		return fmt.Errorf("failed to select organization: %w", err)
+	}
+	orgID, err := uuid.Parse(org)
+	if err != nil {
+		return fmt.Errorf("invalid organization ID format: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	stats, err := runlog.Replay(ctx, legionClient, orgID, log, runlog.Options{
+		Speed:   speed,
+		Keep:    keep,
+		OnEvent: logReplayEvent,
+	})
+	logger.Infof("Replayed %d entities, %d positions, %d changes and %d deletions (%d events)",
+		stats.Entities, stats.Locations, stats.Patches, stats.Deletes, stats.Events)
+	if output == "json" {
+		if writeErr := writeJSON(out, struct {
+			runlog.Header
+			Records     int          `json:"records"`
+			Interrupted bool         `json:"interrupted"`
+			Stats       runlog.Stats `json:"stats"`
+		}{log.Header, len(log.Records), errors.Is(err, context.Canceled), stats}); writeErr != nil {
+			return writeErr
+		}
+	}
+	if errors.Is(err, context.Canceled) {
+		logger.Info("Replay interrupted")
+		return nil
+	}
+	if err != nil {
+		return fmt.Errorf("replay failed: %w", err)
+	}
+	if stats.Skipped > 0 {
+		logger.Warnf("Skipped %d records for entities the recording never created", stats.Skipped)
+	}
+	if stats.Failed > 0 {
+		return fmt.Errorf("%d replayed writes failed (rerun with --log-level debug for details)", stats.Failed)
+	}
+	logger.Success("Replay complete")
+	return nil
+}
+
+// logReplayEvent narrates a recorded event at the level it was logged at
+func logReplayEvent(event simulation.Event) {
+	switch event.Severity {
+	case "critical", "error":
+		logger.Errorf("[%s] %s", event.Type, event.Message)
+	case "warning":
+		logger.Warnf("[%s] %s", event.Type, event.Message)
+	case "debug":
+		logger.Debugf("[%s] %s", event.Type, event.Message)
+	default:
+		logger.Infof("[%s] %s", event.Type, event.Message)
+	}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// aarFormats and aarDetailLevels are what the AAR generator writes
+var (
+	aarFormats      = []string{"json", "html", "markdown"}
+	aarDetailLevels = []string{"summary", "detailed", "full"}
+)
+
+var reportCmd = &cobra.Command{
+	Use:   "report",
+	Short: "Regenerate a drone swarm run's After Action Report from its event log",
+	Long: `Regenerate the After Action Report of a finished drone swarm run from the event log it
+saved, e.g. as HTML for a debrief or with the full event log for an investigation,
+without running it again.
+
+Runs with save_events (on by default) write Events_<run>_<time>.json next to the AAR.
+It holds every event the run logged and its metrics. The report is rebuilt from it in
+each --format, at --detail, and written to --output-dir, which defaults to the log's
+directory. Recommendations are ranked against the archived AARs there that were
+generated before the run started, up to --history of them. Attachments the run's AAR
+listed are listed again, with fresh digests, if they are still in place.`,
+	Example: `  legion-sim report --from reports/Events_1a2b3c4d_20260314_233000.json --format html
+  legion-sim report --from Events_1a2b3c4d_20260314_233000.json --format json,markdown --detail full -d debrief/`,
+	Args: cobra.NoArgs,
+	RunE: runReport,
+}
+
+func init() {
+	reportCmd.Flags().String("from", "", "event log written by a run with save_events (required)")
+	reportCmd.Flags().StringSlice("format", []string{"html"}, "report formats to write (json, html, markdown)")
+	reportCmd.Flags().String("detail", "detailed", "detail level (summary, detailed, full); full adds the complete event log")
+	reportCmd.Flags().StringP("output-dir", "d", "", "directory to write the reports to (default: the event log's directory)")
+	reportCmd.Flags().Int("history", reporting.DefaultHistoryRuns, "earlier AARs in the output directory to rank recommendations against (0 ranks this run alone)")
+	addOutputFlag(reportCmd)
+	_ = reportCmd.MarkFlagRequired("from")
+	_ = reportCmd.MarkFlagFilename("from", "json")
+	_ = reportCmd.MarkFlagDirname("output-dir")
+	_ = reportCmd.RegisterFlagCompletionFunc("format", cobra.FixedCompletions(aarFormats, cobra.ShellCompDirectiveNoFileComp))
+	_ = reportCmd.RegisterFlagCompletionFunc("detail", cobra.FixedCompletions(aarDetailLevels, cobra.ShellCompDirectiveNoFileComp))
+}
+
+func runReport(cmd *cobra.Command, _ []string) error {
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	from, _ := cmd.Flags().GetString("from")
+	formats, _ := cmd.Flags().GetStringSlice("format")
+	for i, format := range formats {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if format == "md" {
+			format = "markdown"
+		}
+		if !slices.Contains(aarFormats, format) {
+			return fmt.Errorf("unknown format %q (use %s)", format, strings.Join(aarFormats, ", "))
+		}
+		formats[i] = format
+	}
+	detail, _ := cmd.Flags().GetString("detail")
+	detail = strings.ToLower(detail)
+	if !slices.Contains(aarDetailLevels, detail) {
+		return fmt.Errorf("unknown detail level %q (use %s)", detail, strings.Join(aarDetailLevels, ", "))
+	}
+	history, _ := cmd.Flags().GetInt("history")
+	if history < 0 {
+		return fmt.Errorf("invalid history %d: must be 0 or more", history)
+	}
+	outputDir, _ := cmd.Flags().GetString("output-dir")
+	if outputDir == "" {
+		outputDir = filepath.Dir(from)
+	}
+
+	out := cmd.OutOrStdout()
+	if output == "json" {
+		_, restore := jsonStdout()
+		defer restore()
+	}
+
+	dump, err := reporting.LoadEventDump(from)
+	if err != nil {
+		return err
+	}
+	logger.Infof("Regenerating the AAR from %s: %d events, %s to %s",
+		filepath.Base(from), len(dump.Events), dump.Start.Format("2006-01-02 15:04:05"), dump.End.Format("15:04:05"))
+
+	aarConfig := reporting.AARConfig{
+		OutputDir:     outputDir,
+		IncludeGraphs: true,
+		DetailLevel:   detail,
+		DataPack:      dump.DataPack,
+		HistoryBefore: dump.Start,
+	}
+	if history > 0 {
+		aarConfig.HistoryDir, aarConfig.HistoryRuns = outputDir, history
+	}
+	generator := reporting.NewAARGenerator(dump.Logger(), aarConfig)
+	for _, path := range append(dump.Attachments, from) {
+		if attachment := findAttachment(path, filepath.Dir(from)); attachment != "" {
+			generator.AddAttachment(attachment)
+		} else {
+			logger.Warnf("Attachment %s is no longer there; leaving it out of the report", path)
+		}
+	}
+
+	aar, err := generator.GenerateAAR()
+	if err != nil {
+		return fmt.Errorf("failed to generate AAR: %w", err)
+	}
+	reports := make(map[string]string, len(formats))
+	for _, format := range formats {
+		path, err := generator.SaveAs(aar, format)
+		if err != nil {
+			return fmt.Errorf("failed to save %s AAR: %w", format, err)
+		}
+		reports[format] = path
+	}
+	if output == "json" {
+		return writeJSON(out, struct {
+			EventLog     string            `json:"event_log"`
+			SimulationID string            `json:"simulation_id"`
+			Events       int               `json:"events"`
+			Reports      map[string]string `json:"reports"` // Path by format
+		}{from, dump.SimulationID, len(dump.Events), reports})
+	}
+	return nil
+}
+
+// findAttachment returns where an attachment listed by a run is now: the path it was
+// written to, or the same file name next to the event log if the reports were moved
+func findAttachment(path, dir string) string {
+	for _, candidate := range []string{path, filepath.Join(dir, filepath.Base(path))} {
+		if _, err := os.Stat(candidate); err == nil {
+			return candidate
+		}
+	}
+	return ""
+}
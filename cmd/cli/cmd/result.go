@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/runner"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// Exit codes for run outcomes; 1 is left for errors
+var outcomeExitCodes = map[string]int{
+	simulation.OutcomeSuccess:   0,
+	simulation.OutcomeCompleted: 0,
+	simulation.OutcomeFailure:   2,
+	simulation.OutcomePartial:   3,
+	simulation.OutcomeStopped:   130,
+}
+
+// exitCode is set by a run whose outcome should end the process with a non-zero status
+var exitCode int
+
+// ExitCode is the status the process should exit with after a command that returned
+// no error
+func ExitCode() int {
+	return exitCode
+}
+
+// printResult writes a run's result and any --fail-on checks to w as a readable summary
+// or as JSON
+func printResult(w io.Writer, result *runner.Result, checks []assertionResult, format string) error {
+	if format == "json" {
+		return writeJSON(w, struct {
+			*runner.Result
+			Assertions []assertionResult `json:"assertions,omitempty"`
+		}{result, checks})
+	}
+
+	logger.LogSection(fmt.Sprintf("%s: %s", result.Simulation, result.Outcome))
+	if result.Summary != "" {
+		_, _ = fmt.Fprintln(w, result.Summary)
+	}
+	if result.Termination != "" {
+		_, _ = fmt.Fprintf(w, "Ended by: %s\n", result.Termination)
+	}
+	_, _ = fmt.Fprintf(w, "Duration: %s\n", result.Duration().Round(time.Second))
+	if result.Seed != 0 {
+		_, _ = fmt.Fprintf(w, "Seed: %d\n", result.Seed)
+	}
+
+	if len(result.Stats) > 0 {
+		names := make([]string, 0, len(result.Stats))
+		for name := range result.Stats {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw)
+		for _, name := range names {
+			_, _ = fmt.Fprintf(tw, "%s\t%g\n", name, result.Stats[name])
+		}
+		if err := tw.Flush(); err != nil {
+			return err
+		}
+	}
+
+	for _, path := range result.Artifacts {
+		_, _ = fmt.Fprintf(w, "Wrote %s\n", path)
+	}
+	if len(result.Entities) > 0 {
+		_, _ = fmt.Fprintf(w, "Entities left in Legion: %d\n", len(result.Entities))
+	}
+	for _, check := range checks {
+		status := "pass"
+		if check.Failed {
+			status = "FAIL"
+		}
+		_, _ = fmt.Fprintf(w, "--fail-on %s: %s (%s = %g)\n", check.assertion, status, check.Stat, check.Value)
+	}
+	return nil
+}
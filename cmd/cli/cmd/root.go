@@ -1,17 +1,26 @@
 package cmd
 
 import (
+	"os"
+	"strconv"
+
 	"github.com/picogrid/legion-simulations/pkg/logger"
 	"github.com/spf13/cobra"
 	"github.com/spf13/viper"
 )
 
 var (
-	cfgFile  string
-	envName  string
-	envURL   string
-	logLevel string
-	noColor  bool
+	cfgFile   string
+	envName   string
+	envURL    string
+	orgFlag   string
+	logLevel  string
+	logFormat string
+	logLevels string
+	levelFile string
+	noColor   bool
+	progress  string
+	logBodies bool
 )
 
 // rootCmd represents the base command when called without any subcommands
@@ -28,15 +37,48 @@ func init() {
 
 	// Global flags
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.legion-sim/config.yaml)")
-	rootCmd.PersistentFlags().StringVar(&envName, "env", "", "environment name to use")
+	rootCmd.PersistentFlags().StringVar(&envName, "profile", "", "environment profile to use (default $LEGION_PROFILE, then the selected profile)")
+	rootCmd.PersistentFlags().StringVar(&envName, "env", "", "environment name to use; same as --profile")
 	rootCmd.PersistentFlags().StringVar(&envURL, "url", "", "Legion API URL (overrides environment)")
+	rootCmd.PersistentFlags().StringVar(&orgFlag, "org", "", "organization to use, by name or ID (overrides LEGION_ORG_ID and the profile)")
 	rootCmd.PersistentFlags().StringVar(&logLevel, "log-level", "info", "log level (debug, info, warn, error)")
+	rootCmd.PersistentFlags().StringVar(&logFormat, "log-format", "text", "log output format (text, json)")
+	rootCmd.PersistentFlags().StringVar(&logLevels, "log-levels", "", "per-module log levels, e.g. client=debug,behavior=warn (modules: client, buffer, behavior, engagement)")
+	rootCmd.PersistentFlags().StringVar(&levelFile, "log-levels-file", "", "file holding per-module log levels; re-read on SIGHUP during a run")
 	rootCmd.PersistentFlags().BoolVar(&noColor, "no-color", false, "disable colored output")
+	rootCmd.PersistentFlags().BoolVar(&logBodies, "log-bodies", false, "log every Legion request and response in full, with credentials and personal details redacted (default $LEGION_LOG_BODIES)")
+	rootCmd.PersistentFlags().StringVar(&progress, "progress", "", "run progress: auto (live on a terminal), live, log or quiet (each quarter of the run, for CI) (default $LEGION_PROGRESS, then auto)")
+	_ = rootCmd.RegisterFlagCompletionFunc("profile", completeEnvironments)
+	_ = rootCmd.RegisterFlagCompletionFunc("env", completeEnvironments)
+	_ = rootCmd.RegisterFlagCompletionFunc("progress", cobra.FixedCompletions([]cobra.Completion{logger.ProgressAuto, logger.ProgressLive, logger.ProgressLog, logger.ProgressQuiet}, cobra.ShellCompDirectiveNoFileComp))
+	_ = rootCmd.MarkPersistentFlagFilename("config", "yaml", "yml")
+	_ = rootCmd.MarkPersistentFlagFilename("log-levels-file")
 
 	// Add commands
 	rootCmd.AddCommand(runCmd)
 	rootCmd.AddCommand(listCmd)
 	rootCmd.AddCommand(envCmd)
+	rootCmd.AddCommand(packageCmd)
+	rootCmd.AddCommand(authCmd)
+	rootCmd.AddCommand(loginCmd)
+	rootCmd.AddCommand(logoutCmd)
+	rootCmd.AddCommand(spectateCmd)
+	rootCmd.AddCommand(tuneCmd)
+	rootCmd.AddCommand(datapackCmd)
+	rootCmd.AddCommand(worldCmd)
+	rootCmd.AddCommand(tilesCmd)
+	rootCmd.AddCommand(exportCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(cleanupCmd)
+	rootCmd.AddCommand(replayCmd)
+	rootCmd.AddCommand(reportCmd)
+	rootCmd.AddCommand(validateCmd)
+	rootCmd.AddCommand(statusCmd)
+	rootCmd.AddCommand(doctorCmd)
+	rootCmd.AddCommand(sweepCmd)
+	rootCmd.AddCommand(scenarioCmd)
+	rootCmd.AddCommand(completionCmd)
+	rootCmd.CompletionOptions.DisableDefaultCmd = true
 }
 
 // Execute runs the root command
@@ -49,6 +91,21 @@ func initConfig() {
 	// Configure logger based on flags
 	logger.SetLevel(logger.ParseLevel(logLevel))
 	logger.SetNoColor(noColor)
+	logger.SetFormat(logFormat)
+	applyLogLevels()
+	if !logBodies {
+		logBodies, _ = strconv.ParseBool(os.Getenv("LEGION_LOG_BODIES"))
+	}
+	if logBodies {
+		// Bodies are logged at debug level under the client module
+		logger.SetModuleLevel(logger.ModuleClient, logger.DebugLevel)
+	}
+	if progress == "" {
+		progress = os.Getenv("LEGION_PROGRESS")
+	}
+	if err := logger.SetProgressMode(progress); err != nil {
+		logger.Warnf("Ignoring progress mode: %v", err)
+	}
 
 	if cfgFile != "" {
 		// Use config file from the flag
@@ -65,3 +122,22 @@ func initConfig() {
 	// If a config file is found, read it in
 	_ = viper.ReadInConfig()
 }
+
+// applyLogLevels applies per-module levels from --log-levels, LEGION_LOG_LEVELS or --log-levels-file
+func applyLogLevels() {
+	spec := logLevels
+	if spec == "" {
+		spec = os.Getenv("LEGION_LOG_LEVELS")
+	}
+	if levelFile != "" {
+		data, err := os.ReadFile(levelFile)
+		if err != nil {
+			logger.Warnf("Failed to read log levels file: %v", err)
+		} else {
+			spec += "," + string(data)
+		}
+	}
+	if err := logger.ApplyLevels(spec); err != nil {
+		logger.Warnf("Ignoring log levels: %v", err)
+	}
+}
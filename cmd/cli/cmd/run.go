@@ -3,19 +3,25 @@ package cmd
 import (
 	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/signal"
 	"strings"
 	"syscall"
+	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/google/uuid"
 	"github.com/spf13/cobra"
+	"golang.org/x/term"
 
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/scenario"
 	"github.com/picogrid/legion-simulations/pkg/auth"
 	"github.com/picogrid/legion-simulations/pkg/client"
 	"github.com/picogrid/legion-simulations/pkg/config"
 	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/metrics"
+	"github.com/picogrid/legion-simulations/pkg/runner"
 	"github.com/picogrid/legion-simulations/pkg/simulation"
 	"github.com/picogrid/legion-simulations/pkg/utils"
 
@@ -29,13 +35,61 @@ import (
 var runCmd = &cobra.Command{
 	Use:   "run",
 	Short: "Run a simulation",
-	Long:  `Run a simulation interactively or with specified parameters`,
-	RunE:  runSimulation,
+	Long: `Run a simulation interactively or with specified parameters.
+
+With --headless nothing is prompted for, so runs can start from CI or cron without a
+terminal. The environment comes from --profile, --url, LEGION_URL or LEGION_PROFILE, the
+organization from --org, LEGION_ORG_ID, an organization_id parameter or the profile,
+and the simulation from -s. --org and LEGION_ORG_ID take an organization name or ID. Parameters
+are taken from --set, then the --params file, then LEGION_* variables, then defaults;
+a required parameter with none of these fails the run.
+
+Send SIGUSR1 (kill -USR1 <pid>), or press p on the --dashboard, to pause a run and
+again to resume it. While paused nothing moves and the scenario clock stops, but
+entities keep reporting to Legion.
+
+--daemon starts the run headless in the background and returns. Its output goes to a
+log next to its control socket in ~/.legion-sim/daemons; "legion-sim status" reports
+its phase, stats and update buffer health over the socket.
+
+--dry-run runs against an in-memory Legion instead of a server: no environment or login
+is needed and no API quota is used. Entities, locations and feed definitions are kept
+for the run so the simulation sees its own writes, and the AAR is written as usual.
+The calls it would have made are summarized at the end.
+
+Ctrl-C stops the run cleanly: the simulation loop ends and pending updates are flushed
+to Legion. You are then asked whether to delete the entities and feeds the run created;
+--cleanup-on-exit answers yes without asking, and a headless run without it keeps them.
+A second Ctrl-C exits at once.`,
+	Example: `  legion-sim run
+  legion-sim run --headless --env staging -s "Drone Swarm Combat" -p params.json
+  legion-sim run --headless --url https://legion.example.com -s simple --set num_entities=20 --set duration=5m
+  legion-sim run --dry-run --headless -s "Drone Swarm Combat" -p scenario.yaml
+  legion-sim run --daemon --profile staging -s "Drone Swarm Combat" -p params.json`,
+	RunE: runSimulation,
 }
 
 func init() {
 	runCmd.Flags().StringP("simulation", "s", "", "simulation name to run")
-	runCmd.Flags().StringP("params", "p", "", "parameters file (YAML)")
+	runCmd.Flags().StringP("params", "p", "", "parameters file (YAML or JSON)")
+	runCmd.Flags().StringArray("set", nil, "set a parameter, e.g. --set num_waves=3; overrides --params (repeatable)")
+	runCmd.Flags().Int64("seed", 0, "seed every random draw so the run can be repeated exactly; overrides --params and --set")
+	runCmd.Flags().String("run-id", "", "namespace every entity and feed name as <run-id>/<name> so runs can share an organization")
+	runCmd.Flags().BoolVar(&headless, "headless", false, "never prompt; take every input from flags, --params and LEGION_* variables (for CI and cron)")
+	runCmd.Flags().String("metrics-addr", "", "serve Prometheus metrics on this address (e.g. :9090)")
+	runCmd.Flags().Bool("estimate", false, "print the expected Legion API load for the parameters and exit without connecting")
+	runCmd.Flags().StringSlice("fail-on", nil, "exit with status 4 when a result stat meets a condition, e.g. penetration>0.1 (repeatable)")
+	addOutputFlag(runCmd)
+	runCmd.Flags().Bool("dashboard", false, "show a live dashboard of threats, systems, engagements and API errors instead of the scrolling log")
+	runCmd.Flags().Bool("daemon", false, "run in the background, headless, and return; check on it with legion-sim status")
+	runCmd.Flags().String("socket", "", "serve status for legion-sim status on this Unix socket (default with --daemon: ~/.legion-sim/daemons/<id>.sock)")
+	runCmd.Flags().Bool("dry-run", false, "answer Legion calls from memory instead of a server, to try scenarios, behaviors and AARs offline")
+	runCmd.Flags().Bool("cleanup-on-exit", false, "when interrupted, delete the entities and feeds the run created without asking (=false keeps them)")
+	runCmd.Flags().String("explain", "", "describe a parameter (type, default, range, environment variable) and exit; narrow with -s")
+	_ = runCmd.RegisterFlagCompletionFunc("simulation", completeSimulations)
+	_ = runCmd.RegisterFlagCompletionFunc("params", completeScenarioFiles)
+	_ = runCmd.RegisterFlagCompletionFunc("explain", completeParameters)
+	_ = runCmd.RegisterFlagCompletionFunc("set", completeParameters)
 }
 
 func runSimulation(cmd *cobra.Command, _ []string) error {
@@ -43,115 +97,417 @@ func runSimulation(cmd *cobra.Command, _ []string) error {
 		return fmt.Errorf("failed to load simulations: %w", err)
 	}
 
-	envConfig, apiKey, err := selectEnvironment()
+	output, err := outputFormat(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to select environment: %w", err)
+		return err
 	}
 
-	var legionClient *client.Legion
+	if param, _ := cmd.Flags().GetString("explain"); param != "" {
+		simName, _ := cmd.Flags().GetString("simulation")
+		return explainParameter(cmd.OutOrStdout(), simName, param, output)
+	}
 
-	// Check if we should use OAuth authentication
-	if apiKey == "" || strings.ToLower(apiKey) == "oauth" {
-		// Use the new function that fetches auth config from Legion
-		tokenManager, err := auth.AuthenticateUserWithLegion(context.Background(), envConfig.URL)
+	if estimateOnly, _ := cmd.Flags().GetBool("estimate"); estimateOnly {
+		return estimateSimulation(cmd, output)
+	}
+
+	failOn, _ := cmd.Flags().GetStringSlice("fail-on")
+	assertions, err := parseAssertions(failOn)
+	if err != nil {
+		return err
+	}
+
+	if background, _ := cmd.Flags().GetBool("daemon"); background {
+		return startDaemon(cmd)
+	}
+
+	// Keep stdout for the JSON result; everything else, including prompts, goes to stderr
+	resultOut := io.Writer(os.Stdout)
+	if output == "json" {
+		var restore func()
+		resultOut, restore = jsonStdout()
+		defer restore()
+	}
+
+	if metricsAddr, _ := cmd.Flags().GetString("metrics-addr"); metricsAddr != "" {
+		server := metrics.Serve(metricsAddr)
+		defer func() {
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = server.Shutdown(shutdownCtx)
+		}()
+	}
+
+	// SIGHUP re-reads --log-levels-file, or toggles debug logging without one. With --params
+	// it reloads the parameters file instead, which carries its own log_level.
+	paramsFile, _ := cmd.Flags().GetString("params")
+	if levelFile != "" || paramsFile == "" {
+		stopReload := logger.ReloadOnSignal(levelFile)
+		defer stopReload()
+	}
+
+	var (
+		legionClient *client.Legion
+		memory       *client.Memory
+		orgID        string
+		quota        config.Quota
+	)
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		legionClient, memory, orgID, err = connectDryRun(cmd)
+		if err != nil {
+			return err
+		}
+		defer logDryRun(memory)
+		quota = estimateQuota()
+	} else {
+		envConfig, apiKey, err := selectEnvironment()
 		if err != nil {
-			return fmt.Errorf("failed to authenticate: %w", err)
+			return fmt.Errorf("failed to select environment: %w", err)
 		}
 
-		legionClient, err = auth.CreateAuthenticatedClient(envConfig.URL, tokenManager)
+		legionClient, err = connectLegion(envConfig, apiKey)
 		if err != nil {
-			return fmt.Errorf("failed to create authenticated client: %w", err)
+			return err
 		}
-	} else {
-		legionClient, err = client.NewLegionClient(envConfig.URL, apiKey)
+
+		// Get organizations and let user select
+		orgID, err = selectOrganization(cmd, envConfig, legionClient)
 		if err != nil {
-			return fmt.Errorf("failed to create Legion client: %w", err)
+			return fmt.Errorf("failed to select organization: %w", err)
 		}
+		quota = envConfig.Quota
 	}
 
-	logger.Progress("Testing connection to Legion...")
-	if err := legionClient.ValidateConnection(context.Background()); err != nil {
-		return fmt.Errorf("failed to connect to Legion: %w", err)
+	r, err := configureSimulation(cmd, orgID)
+	if err != nil {
+		return err
 	}
-	logger.Success("Successfully connected to Legion")
+	sim := r.Simulation()
+
+	// Flag runs likely to hit rate limits before they start
+	if estimator, ok := sim.(simulation.Estimator); ok {
+		checkEstimate(estimator.Estimate(), quota)
+	}
+
+	// SIGHUP also re-reads --params into a running simulation
+	if paramsFile != "" {
+		if reloader, ok := sim.(simulation.Reloader); ok {
+			stopParams := reloadParamsOnSignal(paramsFile, sim.Name(), reloader)
+			defer stopParams()
+		}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+
+	// stopped is closed once an interrupted run has stopped and flushed its pending updates
+	interrupted, stopped := make(chan struct{}), make(chan struct{})
+	go func() {
+		<-sigChan
+		close(interrupted)
+		defer close(stopped)
+		logger.Warn("\nReceived interrupt signal, stopping simulation...")
+		err := r.Stop()
+		if err != nil {
+			logger.Errorf("Failed to stop simulation: %v", err)
+			return
+		}
+		cancel()
+	}()
 
-	// Get organizations and let user select
-	orgID, err := selectOrganization(legionClient)
+	runLabels := metrics.Labels{"simulation": sim.Name()}
+	metrics.Default.Set("legion_sim_start_time_seconds", "Unix time the simulation run started", runLabels, float64(time.Now().Unix()))
+	metrics.Default.Set("legion_sim_running", "Whether a simulation run is in progress", runLabels, 1)
+	defer metrics.Default.Set("legion_sim_running", "Whether a simulation run is in progress", runLabels, 0)
+
+	if socket, _ := cmd.Flags().GetString("socket"); socket != "" {
+		closeSocket, err := serveStatus(socket, sim)
+		if err != nil {
+			return err
+		}
+		defer closeSocket()
+	}
+
+	// SIGUSR1 pauses and resumes the run
+	if pausable, ok := sim.(simulation.Pausable); ok {
+		stopPause := pauseOnSignal(pausable)
+		defer stopPause()
+	}
+
+	logger.LogSection(fmt.Sprintf("Starting %s", sim.Name()))
+	if dashboard, _ := cmd.Flags().GetBool("dashboard"); dashboard {
+		if stop := dashboardFor(sim, output); stop != nil {
+			defer stop()
+		}
+	}
+	result, err := r.Run(ctx, legionClient)
+	select {
+	case <-interrupted:
+		// A second interrupt now exits at once, leaving what the run created
+		signal.Stop(sigChan)
+		<-stopped
+		if result != nil && memory == nil {
+			cleanupOnExit(cmd, legionClient, orgID, result)
+		}
+	default:
+	}
 	if err != nil {
-		return fmt.Errorf("failed to select organization: %w", err)
+		metrics.Default.Add("legion_sim_runs_total", "Completed simulation runs by result", metrics.Labels{"simulation": sim.Name(), "result": "failure"}, 1)
+		if output == "json" && result != nil {
+			_ = printResult(resultOut, result, nil, output)
+		}
+		return err
 	}
 
-	simName, err := selectSimulation(cmd)
+	metrics.Default.Add("legion_sim_runs_total", "Completed simulation runs by result", metrics.Labels{"simulation": sim.Name(), "result": "success"}, 1)
+
+	exitCode = outcomeExitCodes[result.Outcome]
+	checks, err := checkAssertions(assertions, result.Stats)
+	if err != nil {
+		_ = printResult(resultOut, result, nil, output)
+		return err
+	}
+	for _, check := range checks {
+		if check.Failed {
+			logger.Errorf("--fail-on %s: %s is %g", check.assertion, check.Stat, check.Value)
+		}
+	}
+	exitCode = runExitCode(result.Outcome, checks)
+	return printResult(resultOut, result, checks, output)
+}
+
+// dashboardFor starts the live dashboard when the simulation supports one and stdout is
+// a terminal showing text logs, or explains why the log is shown instead
+func dashboardFor(sim simulation.Simulation, output string) func() {
+	watchable, ok := sim.(simulation.Watchable)
+	switch {
+	case !ok:
+		logger.Warnf("%s has no live dashboard; showing the log", sim.Name())
+	case output != "text" || logger.IsJSON() || !term.IsTerminal(int(os.Stdout.Fd())):
+		logger.Warn("The dashboard needs a terminal and text output; showing the log")
+	default:
+		return startDashboard(watchable)
+	}
+	return nil
+}
+
+// configureSimulation selects a simulation, prompts for its parameters and returns a
+// runner with it configured
+func configureSimulation(cmd *cobra.Command, orgID string) (*runner.Runner, error) {
+	simConfig, params, err := simulationParameters(cmd, nil)
 	if err != nil {
-		return fmt.Errorf("failed to select simulation: %w", err)
+		return nil, err
 	}
+	return runner.New(simConfig.Name, runner.WithParams(params), runner.WithOrganization(orgID))
+}
 
-	sim, err := simulation.DefaultRegistry.Get(simName)
+// simulationParameters selects a simulation and collects its parameters from --params,
+// --set and prompts. Parameters named in given are set by the caller and not prompted for.
+func simulationParameters(cmd *cobra.Command, given []string) (*simulation.SimulationConfig, map[string]interface{}, error) {
+	simName, err := selectSimulation(cmd)
 	if err != nil {
-		return fmt.Errorf("failed to get simulation: %w", err)
+		return nil, nil, fmt.Errorf("failed to select simulation: %w", err)
 	}
 
-	simInfos, err := utils.DiscoverSimulations()
+	simConfig, err := findSimulationConfig(simName)
 	if err != nil {
-		return fmt.Errorf("failed to discover simulations: %w", err)
+		return nil, nil, err
 	}
 
-	var simConfig *simulation.SimulationConfig
-	for _, info := range simInfos {
-		if info.Config.Name == simName {
-			simConfig = &info.Config
-			break
+	// Values from a scenario, --params and --set are used as given, later ones winning;
+	// only the rest are prompted for
+	fileParams := map[string]interface{}{}
+	if activeScenario != nil {
+		fileParams, err = utils.ConvertParameters(activeScenario.Params(), simConfig.Parameters)
+		if err != nil {
+			return nil, nil, fmt.Errorf("scenario %s: %w", activeScenario.Name, err)
 		}
 	}
-
-	if simConfig == nil {
-		return fmt.Errorf("simulation configuration not found for %s", simName)
+	if paramsFile, _ := cmd.Flags().GetString("params"); paramsFile != "" {
+		loaded, err := utils.LoadParameterFile(paramsFile, simConfig.Parameters)
+		if err != nil {
+			return nil, nil, err
+		}
+		for name, value := range loaded {
+			fileParams[name] = value
+		}
+	}
+	sets, _ := cmd.Flags().GetStringArray("set")
+	setParams, err := setParameters(sets, simConfig.Parameters)
+	if err != nil {
+		return nil, nil, err
+	}
+	for name, value := range setParams {
+		fileParams[name] = value
+	}
+	if cmd.Flags().Changed("seed") {
+		if !declaresParameter(simConfig.Parameters, simulation.SeedParameter) {
+			return nil, nil, fmt.Errorf("%s does not take a seed", simConfig.Name)
+		}
+		seed, _ := cmd.Flags().GetInt64("seed")
+		fileParams[simulation.SeedParameter] = int(seed)
+	}
+	if runID, _ := cmd.Flags().GetString("run-id"); runID != "" {
+		if !declaresParameter(simConfig.Parameters, simulation.RunIDParameter) {
+			return nil, nil, fmt.Errorf("%s does not take a run ID", simConfig.Name)
+		}
+		if err := simulation.ValidateRunID(runID); err != nil {
+			return nil, nil, err
+		}
+		fileParams[simulation.RunIDParameter] = runID
+	}
+	skip := make(map[string]bool, len(given))
+	for _, name := range given {
+		skip[name] = true
 	}
 
 	// Filter out organization_id from parameters since we already have it
 	filteredParams := make([]simulation.Parameter, 0, len(simConfig.Parameters))
 	for _, param := range simConfig.Parameters {
+		if _, ok := fileParams[param.Name]; ok || skip[param.Name] {
+			continue
+		}
 		if param.Name != "organization_id" {
 			filteredParams = append(filteredParams, param)
 		}
 	}
 
-	params, err := utils.PromptForParameters(filteredParams)
+	// A scenario is complete as written; what it leaves out takes its default
+	prompt := utils.PromptForParameters
+	if headless || activeScenario != nil {
+		prompt = utils.ParametersWithoutPrompts
+	}
+	params, err := prompt(filteredParams)
 	if err != nil {
-		return fmt.Errorf("failed to get parameters: %w", err)
+		return nil, nil, fmt.Errorf("failed to get parameters: %w", err)
+	}
+	for name, value := range fileParams {
+		params[name] = value
 	}
 
-	// Add organization ID to parameters
-	params["organization_id"] = orgID
+	return simConfig, params, nil
+}
 
-	if err := sim.Configure(params); err != nil {
-		return fmt.Errorf("failed to configure simulation: %w", err)
+// reloadParamsOnSignal re-reads the parameters file on every SIGHUP and hands it to the
+// running simulation, logging what was applied and what needs a restart
+func reloadParamsOnSignal(path, simName string, reloader simulation.Reloader) func() {
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	done := make(chan struct{})
+
+	go func() {
+		for {
+			select {
+			case <-done:
+				return
+			case <-hup:
+			}
+
+			var declared []simulation.Parameter
+			if simConfig, err := findSimulationConfig(simName); err == nil {
+				declared = simConfig.Parameters
+			}
+			params, err := utils.LoadParameterFile(path, declared)
+			if err != nil {
+				logger.Errorf("Parameter reload failed: %v", err)
+				continue
+			}
+
+			result, err := reloader.Reload(params, "SIGHUP")
+			if err != nil {
+				logger.Errorf("Parameter reload failed: %v", err)
+				continue
+			}
+			logReloadResult(result)
+		}
+	}()
+
+	return func() {
+		signal.Stop(hup)
+		close(done)
 	}
+}
 
-	ctx, cancel := context.WithCancel(context.Background())
-	defer cancel()
+// logReloadResult reports a reload's applied and rejected changes
+func logReloadResult(result simulation.ReloadResult) {
+	if len(result.Applied) == 0 && len(result.Rejected) == 0 {
+		logger.Info("Parameters reloaded: no changes")
+		return
+	}
+	for _, change := range result.Applied {
+		logger.Successf("Reloaded %s: %s -> %s", change.Name, change.Old, change.New)
+	}
+	for _, change := range result.Rejected {
+		logger.Warnf("Not reloaded %s: %s", change.Name, change.Reason)
+	}
+}
 
-	sigChan := make(chan os.Signal, 1)
-	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+// connectLegion creates a client for the environment, logging in with OAuth when there
+// is no API key, and checks that Legion answers
+func connectLegion(envConfig *config.Environment, apiKey string) (*client.Legion, error) {
+	var legionClient *client.Legion
+	var err error
 
-	go func() {
-		<-sigChan
-		logger.Warn("\nReceived interrupt signal, stopping simulation...")
-		err := sim.Stop()
+	// Check if we should use OAuth authentication
+	if apiKey == "" || strings.ToLower(apiKey) == "oauth" {
+		// A session from "legion-sim login" needs no prompt, headless or not
+		tokenManager := storedTokenManager(envConfig.URL)
+		if tokenManager == nil {
+			if err := checkHeadlessAuth(); err != nil {
+				return nil, err
+			}
+			// Use the new function that fetches auth config from Legion
+			tokenManager, err = auth.AuthenticateUserWithLegion(context.Background(), envConfig.URL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to authenticate: %w", err)
+			}
+		}
+		// Keep the session alive through long runs, including quiet stretches with no requests
+		tokenManager.StartAutoRefresh(context.Background())
+
+		cfg := legionConfig(envConfig)
+		cfg.TokenManager = tokenManager
+		legionClient, err = client.NewClient(cfg)
 		if err != nil {
-			logger.Errorf("Failed to stop simulation: %v", err)
-			return
+			return nil, fmt.Errorf("failed to create authenticated client: %w", err)
 		}
-		cancel()
-	}()
+	} else {
+		cfg := legionConfig(envConfig)
+		cfg.APIKey = apiKey
+		legionClient, err = client.NewClient(cfg)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Legion client: %w", err)
+		}
+	}
 
-	logger.LogSection(fmt.Sprintf("Starting %s", sim.Name()))
-	if err := sim.Run(ctx, legionClient); err != nil {
-		return fmt.Errorf("simulation failed: %w", err)
+	logger.Progress("Testing connection to Legion...")
+	if err := legionClient.ValidateConnection(context.Background()); err != nil {
+		return nil, fmt.Errorf("failed to connect to Legion: %w", err)
 	}
+	logger.Success("Successfully connected to Legion")
+	return legionClient, nil
+}
 
-	logger.Success("Simulation completed successfully")
-	return nil
+// legionConfig is the client configuration for an environment, with the profile's HTTP
+// settings applied
+func legionConfig(envConfig *config.Environment) client.Config {
+	settings := envConfig.HTTP
+	return client.Config{
+		BaseURL:   envConfig.URL,
+		Timeout:   settings.Timeout,
+		LogBodies: logBodies,
+		Transport: client.TransportConfig{
+			MaxConnsPerHost:     settings.MaxConnsPerHost,
+			MaxIdleConnsPerHost: settings.MaxIdleConnsPerHost,
+			IdleConnTimeout:     settings.IdleConnTimeout,
+			KeepAlive:           settings.KeepAlive,
+			DisableKeepAlives:   settings.DisableKeepAlives,
+			DisableHTTP2:        settings.DisableHTTP2,
+		},
+	}
 }
 
 func loadSimulations() error {
@@ -163,6 +519,9 @@ func loadSimulations() error {
 	return nil
 }
 
+// selectEnvironment resolves the Legion environment and API key, in order from --url,
+// --profile, LEGION_URL, LEGION_PROFILE and the profile selected with "env use",
+// prompting when none of them is set
 func selectEnvironment() (*config.Environment, string, error) {
 	// Check if URL is provided via flag or environment variable
 	if envURL != "" {
@@ -172,6 +531,17 @@ func selectEnvironment() (*config.Environment, string, error) {
 		}, "", nil
 	}
 
+	// Load environment configurations
+	envConfig, err := config.LoadEnvironments()
+	if err != nil {
+		return nil, "", err
+	}
+
+	// An explicit profile beats LEGION_URL, which a flat .env may set for every run
+	if envName != "" {
+		return profileEnvironment(envConfig, envName)
+	}
+
 	// Check for environment variables
 	if legionURL := os.Getenv("LEGION_URL"); legionURL != "" {
 		apiKey := os.Getenv("LEGION_API_KEY")
@@ -181,21 +551,12 @@ func selectEnvironment() (*config.Environment, string, error) {
 		}, apiKey, nil
 	}
 
-	// Load environment configurations
-	envConfig, err := config.LoadEnvironments()
-	if err != nil {
-		return nil, "", err
+	if name := defaultProfile(envConfig); name != "" {
+		return profileEnvironment(envConfig, name)
 	}
 
-	// Check if environment is specified via flag
-	if envName != "" {
-		for _, env := range envConfig.Environments {
-			if env.Name == envName {
-				apiKey := client.GetAPIKey(env.APIKey)
-				return &env, apiKey, nil
-			}
-		}
-		return nil, "", fmt.Errorf("environment %s not found", envName)
+	if headless {
+		return nil, "", errHeadless("environment", "use --profile, --url, LEGION_URL or LEGION_PROFILE")
 	}
 
 	// Interactive selection
@@ -245,6 +606,9 @@ func selectEnvironment() (*config.Environment, string, error) {
 	for _, env := range envConfig.Environments {
 		if env.Name == selected {
 			apiKey := client.GetAPIKey(env.APIKey)
+			if apiKey == "" {
+				apiKey = storedAPIKey(env.Name)
+			}
 			if apiKey == "" && env.APIKey != "" {
 				// Prompt for API key if env var is not set
 				var key string
@@ -263,12 +627,49 @@ func selectEnvironment() (*config.Environment, string, error) {
 	return nil, "", fmt.Errorf("environment not found")
 }
 
+// profileEnvironment returns a named profile and its API key
+func profileEnvironment(envConfig *config.Config, name string) (*config.Environment, string, error) {
+	env, err := envConfig.Find(name)
+	if err != nil {
+		return nil, "", err
+	}
+	apiKey := client.GetAPIKey(env.APIKey)
+	if apiKey == "" {
+		apiKey = storedAPIKey(env.Name)
+	}
+	logger.Infof("Using profile %s (%s)", env.Name, env.URL)
+	return env, apiKey, nil
+}
+
+// profileName returns the profile named by --profile or LEGION_PROFILE, if any
+func profileName() string {
+	if envName != "" {
+		return envName
+	}
+	return os.Getenv("LEGION_PROFILE")
+}
+
+// defaultProfile returns the profile to use when --profile is not given: LEGION_PROFILE,
+// otherwise the one selected with "env use"
+func defaultProfile(envConfig *config.Config) string {
+	if name := profileName(); name != "" {
+		return name
+	}
+	return envConfig.Selected
+}
+
 func selectSimulation(cmd *cobra.Command) (string, error) {
 	// Check if simulation is specified via flag
 	simName, _ := cmd.Flags().GetString("simulation")
 	if simName != "" {
 		return simName, nil
 	}
+	if activeScenario != nil {
+		return scenario.SimulationName, nil
+	}
+	if headless {
+		return "", errHeadless("simulation", "use -s")
+	}
 
 	// Discover available simulations
 	simInfos, err := utils.DiscoverSimulations()
@@ -306,37 +707,68 @@ func selectSimulation(cmd *cobra.Command) (string, error) {
 	return selected, nil
 }
 
-func selectOrganization(legionClient *client.Legion) (string, error) {
+// selectOrganization picks the organization to run in: --org, LEGION_ORG_ID, an
+// organization_id parameter, the profile's org_id, or a prompt. --org, the variables
+// and the prompt take a name as well as an ID; names are looked up in Legion.
+func selectOrganization(cmd *cobra.Command, env *config.Environment, legionClient *client.Legion) (string, error) {
+	if orgFlag != "" {
+		return resolveOrganization(legionClient, orgFlag, "--org")
+	}
+
 	// Check if organization ID is provided via environment variable
 	if orgID := os.Getenv("LEGION_ORG_ID"); orgID != "" {
-		logger.Infof("Using organization ID from LEGION_ORG_ID: %s", orgID)
-		return orgID, nil
+		return resolveOrganization(legionClient, orgID, "LEGION_ORG_ID")
 	}
 
 	if orgID := os.Getenv("LEGION_ORGANIZATION_ID"); orgID != "" {
-		logger.Infof("Using organization ID from LEGION_ORGANIZATION_ID: %s", orgID)
+		return resolveOrganization(legionClient, orgID, "LEGION_ORGANIZATION_ID")
+	}
+
+	if orgID := parameterOverride(cmd, "organization_id"); orgID != "" {
+		if _, err := uuid.Parse(orgID); err != nil {
+			return "", fmt.Errorf("invalid organization ID format: %w", err)
+		}
+		logger.Infof("Using organization ID from parameters: %s", orgID)
 		return orgID, nil
 	}
 
-	// For now, we'll prompt for the organization ID
-	// In the future, we can enhance this to fetch the list of organizations
-	// when the Legion API client supports it properly
+	if env != nil && env.OrgID != "" {
+		if _, err := uuid.Parse(env.OrgID); err != nil {
+			return "", fmt.Errorf("invalid organization ID in profile %s: %w", env.Name, err)
+		}
+		logger.Infof("Using organization ID from profile %s: %s", env.Name, env.OrgID)
+		return env.OrgID, nil
+	}
+	if headless {
+		return "", errHeadless("organization", "use --org, or set LEGION_ORG_ID, an organization_id parameter or the profile's org_id")
+	}
 
-	var orgID string
+	var org string
 	orgPrompt := &survey.Input{
-		Message: "Enter organization ID:",
-		Help:    "Enter your Legion organization ID (UUID format). You can find this in the Legion UI or set LEGION_ORG_ID environment variable.",
+		Message: "Enter organization name or ID:",
+		Help:    "Enter your Legion organization's name or ID (UUID). Set --org or LEGION_ORG_ID to skip this prompt.",
 	}
-
-	if err := survey.AskOne(orgPrompt, &orgID, survey.WithValidator(survey.Required)); err != nil {
+	if err := survey.AskOne(orgPrompt, &org, survey.WithValidator(survey.Required)); err != nil {
 		return "", err
 	}
+	return resolveOrganization(legionClient, org, "prompt")
+}
 
-	// Validate it's a valid UUID
-	if _, err := uuid.Parse(orgID); err != nil {
-		return "", fmt.Errorf("invalid organization ID format: %w", err)
+// resolveOrganization returns the ID of an organization given by ID or by name, which
+// is looked up among the organizations the user belongs to
+func resolveOrganization(legionClient *client.Legion, org, source string) (string, error) {
+	org = strings.TrimSpace(org)
+	if _, err := uuid.Parse(org); err == nil {
+		logger.Infof("Using organization ID from %s: %s", source, org)
+		return org, nil
 	}
 
-	logger.Infof("Using organization ID: %s", orgID)
-	return orgID, nil
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+	found, err := legionClient.FindOrganization(ctx, org)
+	if err != nil {
+		return "", fmt.Errorf("%s: %w", source, err)
+	}
+	logger.Infof("Using organization %s from %s: %s", found.OrganizationName, source, found.OrganizationId)
+	return found.OrganizationId.String(), nil
 }
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"golang.org/x/term"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/spectate"
+)
+
+var spectateCmd = &cobra.Command{
+	Use:   "spectate <url>",
+	Short: "Watch a running simulation read-only",
+	Long: `Connect to a simulation's spectator endpoint (enabled with the spectator_addr parameter)
+and render its dashboard and map locally.
+
+Spectating needs no Legion credentials and cannot change the run.`,
+	Example: `  legion-sim spectate http://sim-host:7500
+  legion-sim spectate http://sim-host:7500 --once`,
+	Args: cobra.ExactArgs(1),
+	RunE: spectateSimulation,
+}
+
+func init() {
+	spectateCmd.Flags().Bool("once", false, "print a single snapshot and exit")
+	spectateCmd.Flags().Int("map-width", 61, "map width in columns")
+	spectateCmd.Flags().Int("map-height", 21, "map height in rows")
+}
+
+func spectateSimulation(cmd *cobra.Command, args []string) error {
+	url := args[0]
+	once, _ := cmd.Flags().GetBool("once")
+	opts := spectate.RenderOptions{}
+	opts.MapWidth, _ = cmd.Flags().GetInt("map-width")
+	opts.MapHeight, _ = cmd.Flags().GetInt("map-height")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// Redraw in place on a terminal; append when piped
+	redraw := term.IsTerminal(int(os.Stdout.Fd())) && !once
+
+	complete := false
+	render := func(snapshot spectate.Snapshot) bool {
+		if redraw {
+			fmt.Print("\033[H\033[2J")
+		}
+		spectate.Render(os.Stdout, snapshot, opts)
+		complete = snapshot.Status == spectate.StatusComplete
+		return !once && !complete
+	}
+
+	backoff := time.Second
+	for {
+		err := spectate.Stream(ctx, url, func(snapshot spectate.Snapshot) bool {
+			backoff = time.Second
+			return render(snapshot)
+		})
+		if once && err != nil {
+			return err
+		}
+		if complete || once || ctx.Err() != nil {
+			return nil
+		}
+
+		if err != nil {
+			logger.Warnf("%v; reconnecting in %s", err, backoff)
+		} else {
+			logger.Warnf("Spectator stream closed; reconnecting in %s", backoff)
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, 30*time.Second)
+	}
+}
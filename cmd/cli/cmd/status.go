@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/daemon"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+var statusCmd = &cobra.Command{
+	Use:   "status [socket]",
+	Short: "Report on simulations running as daemons",
+	Long: `Report the phase, stats and update buffer health of simulations started with
+"run --daemon". Without a socket, every daemon in ~/.legion-sim/daemons is queried;
+sockets left behind by daemons that are no longer running are removed.`,
+	Example: `  legion-sim status
+  legion-sim status ~/.legion-sim/daemons/3f9c1a2b.sock -o json`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeSockets,
+	RunE:              showStatus,
+}
+
+// daemonStatus is one daemon's report, or why it could not be had
+type daemonStatus struct {
+	Socket string         `json:"socket"`
+	Log    string         `json:"log,omitempty"`
+	Report *daemon.Report `json:"report,omitempty"`
+	Error  string         `json:"error,omitempty"`
+}
+
+func init() {
+	addOutputFlag(statusCmd)
+}
+
+func showStatus(cmd *cobra.Command, args []string) error {
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+
+	sockets := args
+	if len(sockets) == 0 {
+		var err error
+		if sockets, err = daemon.Sockets(); err != nil {
+			return err
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	statuses := make([]daemonStatus, 0, len(sockets))
+	for _, socket := range sockets {
+		status := daemonStatus{Socket: socket}
+		if _, err := os.Stat(daemon.LogPath(socket)); err == nil {
+			status.Log = daemon.LogPath(socket)
+		}
+		report, err := daemon.Query(ctx, socket)
+		switch {
+		case err != nil && len(args) == 0:
+			// Listed from the daemon directory: the daemon has exited without cleaning up
+			_ = os.Remove(socket)
+			status.Error = "not running; removed stale socket"
+		case err != nil:
+			status.Error = err.Error()
+			exitCode = 1
+		default:
+			status.Report = &report
+		}
+		statuses = append(statuses, status)
+	}
+
+	if output == "json" {
+		return writeJSON(cmd.OutOrStdout(), statuses)
+	}
+	if len(statuses) == 0 {
+		_, _ = fmt.Fprintln(cmd.OutOrStdout(), "No simulations running as daemons")
+		return nil
+	}
+	for i, status := range statuses {
+		if i > 0 {
+			_, _ = fmt.Fprintln(cmd.OutOrStdout())
+		}
+		printDaemonStatus(cmd.OutOrStdout(), status)
+	}
+	return nil
+}
+
+// printDaemonStatus writes one daemon's report as text
+func printDaemonStatus(w io.Writer, status daemonStatus) {
+	if status.Report == nil {
+		_, _ = fmt.Fprintf(w, "%s\n  %s\n", status.Socket, color.RedString(status.Error))
+		return
+	}
+
+	report := status.Report
+	_, _ = fmt.Fprintf(w, "%s (pid %d, running %s)\n", color.New(color.Bold).Sprint(report.Simulation),
+		report.PID, time.Since(report.Started).Round(time.Second))
+	_, _ = fmt.Fprintf(w, "  Socket:  %s\n", status.Socket)
+	if status.Log != "" {
+		_, _ = fmt.Fprintf(w, "  Log:     %s\n", status.Log)
+	}
+	_, _ = fmt.Fprintf(w, "  Phase:   %s (tick %d)\n", report.Status.Phase, report.Status.Tick)
+
+	if len(report.Status.Stats) > 0 {
+		names := make([]string, 0, len(report.Status.Stats))
+		for name := range report.Status.Stats {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		_, _ = fmt.Fprintln(w, "  Stats:")
+		for _, name := range names {
+			_, _ = fmt.Fprintf(w, "    %-24s %g\n", name, report.Status.Stats[name])
+		}
+	}
+
+	if buffer := report.Status.Buffer; buffer != nil {
+		state := color.GreenString(buffer.State)
+		switch buffer.State {
+		case simulation.BufferDegraded:
+			state = color.YellowString(buffer.State)
+		case simulation.BufferStalled:
+			state = color.RedString(buffer.State)
+		}
+		lastFlush := "never"
+		if !buffer.LastFlush.IsZero() {
+			lastFlush = time.Since(buffer.LastFlush).Round(time.Second).String() + " ago"
+		}
+		_, _ = fmt.Fprintf(w, "  Buffer:  %s, %d pending, %d sent, %d failed, %d shed, last flush %s\n",
+			state, buffer.Pending, buffer.Sent, buffer.Failed, buffer.Shed, lastFlush)
+		if buffer.LastError != "" {
+			_, _ = fmt.Fprintf(w, "  Last error: %s\n", buffer.LastError)
+		}
+	}
+}
@@ -0,0 +1,494 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/runner"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+	"github.com/picogrid/legion-simulations/pkg/utils"
+)
+
+// outcomeError marks a sweep run that failed rather than finishing with an outcome
+const outcomeError = "error"
+
+// sweepOutcomes orders the outcome columns of the summary
+var sweepOutcomes = []string{
+	simulation.OutcomeSuccess,
+	simulation.OutcomeCompleted,
+	simulation.OutcomePartial,
+	simulation.OutcomeFailure,
+	simulation.OutcomeStopped,
+	outcomeError,
+}
+
+var sweepCmd = &cobra.Command{
+	Use:   "sweep",
+	Short: "Run a simulation over a grid of parameters and compare the outcomes",
+	Long: `Run a simulation once for every combination of the values given with --vary, e.g. to
+see how penetration changes with the threat count and the kinetic ratio.
+
+Each --vary names a parameter and a comma-separated list of values; the grid is every
+combination of them, and --repeat runs each combination several times. Parameters not
+varied come from --set, --params, LEGION_* variables and prompts, as with run, and are
+the same for every run.
+
+Runs go one after another unless --parallel allows more at once. Parallel runs share
+the organization, so cleanup_existing is turned off for them, and their logs interleave.
+
+When every run has finished, the outcomes of each combination are counted and the
+--stats columns averaged into one table, or written as JSON with -o json. The sweep
+exits with status 1 if any run failed to complete.
+
+With --dry-run every run goes to one in-memory Legion instead of a server, as with run.`,
+	Example: `  legion-sim sweep -s "Drone Swarm Combat" -p params.json --vary num_uas_threats=10,20,40 --vary engagement_type_mix=0.3,0.7
+  legion-sim sweep --headless --env staging -s "Drone Swarm Combat" --vary waves=1,2,3 --repeat 3 --parallel 2 -o json`,
+	RunE: runSweep,
+}
+
+func init() {
+	sweepCmd.Flags().StringP("simulation", "s", "", "simulation name to run")
+	sweepCmd.Flags().StringP("params", "p", "", "parameters file (YAML or JSON) shared by every run")
+	sweepCmd.Flags().StringArray("set", nil, "set a parameter for every run, e.g. --set duration=5m (repeatable)")
+	sweepCmd.Flags().StringArray("vary", nil, "a parameter and the values to sweep it over, e.g. --vary num_uas_threats=10,20,40 (repeatable)")
+	sweepCmd.Flags().Int("repeat", 1, "runs of each combination")
+	sweepCmd.Flags().Int64("seed", 0, "seed the runs so every combination sees the same draws: repeat n uses seed+n-1")
+	sweepCmd.Flags().String("run-id", "", "namespace the runs' entity and feed names as <run-id>/<name>")
+	sweepCmd.Flags().Int("parallel", 1, "runs in progress at once")
+	sweepCmd.Flags().StringSlice("stats", []string{"penetration", "uas_eliminated", "counter_uas_losses"}, "result stats to average in the summary table")
+	addOutputFlag(sweepCmd)
+	sweepCmd.Flags().Bool("dry-run", false, "answer Legion calls from memory instead of a server")
+	sweepCmd.Flags().BoolVar(&headless, "headless", false, "never prompt; take every input from flags, --params and LEGION_* variables")
+	_ = sweepCmd.RegisterFlagCompletionFunc("simulation", completeSimulations)
+	_ = sweepCmd.RegisterFlagCompletionFunc("params", completeScenarioFiles)
+	_ = sweepCmd.RegisterFlagCompletionFunc("set", completeParameters)
+	_ = sweepCmd.RegisterFlagCompletionFunc("vary", completeParameters)
+}
+
+// sweepAxis is a varied parameter and the values it takes
+type sweepAxis struct {
+	name   string
+	values []interface{}
+}
+
+// sweepRun is the result of one run in a sweep
+type sweepRun struct {
+	Repeat      int                `json:"repeat"`
+	Seed        int64              `json:"seed,omitempty"`
+	Outcome     string             `json:"outcome"`
+	Termination string             `json:"termination,omitempty"`
+	Summary     string             `json:"summary,omitempty"`
+	Duration    string             `json:"duration"`
+	Stats       map[string]float64 `json:"stats,omitempty"`
+	Error       string             `json:"error,omitempty"`
+}
+
+// statSummary aggregates a stat over a combination's runs
+type statSummary struct {
+	Mean float64 `json:"mean"`
+	Min  float64 `json:"min"`
+	Max  float64 `json:"max"`
+}
+
+// sweepPoint is one combination of varied values and the runs made with it
+type sweepPoint struct {
+	Params   map[string]interface{} `json:"params"`
+	Outcomes map[string]int         `json:"outcomes"`
+	Stats    map[string]statSummary `json:"stats,omitempty"`
+	Runs     []sweepRun             `json:"runs"`
+}
+
+// sweepJob is a single configured run waiting its turn
+type sweepJob struct {
+	point  int
+	repeat int
+	runner *runner.Runner
+}
+
+func runSweep(cmd *cobra.Command, _ []string) error {
+	if err := loadSimulations(); err != nil {
+		return fmt.Errorf("failed to load simulations: %w", err)
+	}
+
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	repeat, _ := cmd.Flags().GetInt("repeat")
+	if repeat < 1 {
+		return fmt.Errorf("invalid repeat %d: must be at least 1", repeat)
+	}
+	parallel, _ := cmd.Flags().GetInt("parallel")
+	if parallel < 1 {
+		return fmt.Errorf("invalid parallel %d: must be at least 1", parallel)
+	}
+	vary, _ := cmd.Flags().GetStringArray("vary")
+	if len(vary) == 0 {
+		return fmt.Errorf("nothing to sweep: give at least one --vary name=value,...")
+	}
+	statNames, _ := cmd.Flags().GetStringSlice("stats")
+
+	// Keep stdout for the JSON summary; everything else, including prompts, goes to stderr
+	resultOut := io.Writer(os.Stdout)
+	if output == "json" {
+		var restore func()
+		resultOut, restore = jsonStdout()
+		defer restore()
+	}
+
+	varied := make([]string, 0, len(vary))
+	for _, value := range vary {
+		name, _, _ := strings.Cut(value, "=")
+		varied = append(varied, normalizeParameterName(name))
+	}
+
+	var (
+		legionClient *client.Legion
+		orgID        string
+	)
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		var memory *client.Memory
+		legionClient, memory, orgID, err = connectDryRun(cmd)
+		if err != nil {
+			return err
+		}
+		defer logDryRun(memory)
+	} else {
+		envConfig, apiKey, err := selectEnvironment()
+		if err != nil {
+			return fmt.Errorf("failed to select environment: %w", err)
+		}
+		legionClient, err = connectLegion(envConfig, apiKey)
+		if err != nil {
+			return err
+		}
+		orgID, err = selectOrganization(cmd, envConfig, legionClient)
+		if err != nil {
+			return fmt.Errorf("failed to select organization: %w", err)
+		}
+	}
+
+	simConfig, params, err := simulationParameters(cmd, varied)
+	if err != nil {
+		return err
+	}
+	axes, err := parseSweepAxes(vary, simConfig.Parameters)
+	if err != nil {
+		return err
+	}
+	if parallel > 1 && declaresParameter(simConfig.Parameters, "cleanup_existing") {
+		if cleanup, _ := params["cleanup_existing"].(bool); cleanup {
+			logger.Warn("cleanup_existing is off for parallel runs so they don't delete each other's entities")
+		}
+		params["cleanup_existing"] = false
+	}
+	if parallel > 1 && progress != logger.ProgressQuiet {
+		// Parallel runs would fight over a live progress line
+		_ = logger.SetProgressMode(logger.ProgressLog)
+	}
+
+	// Configure every run before any starts, so a bad combination fails the sweep up front
+	grid := sweepGrid(axes)
+	points := make([]sweepPoint, len(grid))
+	jobs := make([]sweepJob, 0, len(grid)*repeat)
+	for i, values := range grid {
+		points[i] = sweepPoint{Params: values, Outcomes: map[string]int{}}
+		for rep := 1; rep <= repeat; rep++ {
+			options := []runner.Option{runner.WithParams(params), runner.WithParams(values), runner.WithOrganization(orgID)}
+			// The same repeat of every combination gets the same seed, so combinations
+			// differ only in the varied parameters
+			if seed, _ := params[simulation.SeedParameter].(int); seed != 0 {
+				options = append(options, runner.WithParams(map[string]interface{}{simulation.SeedParameter: seed + rep - 1}))
+			}
+			r, err := runner.New(simConfig.Name, options...)
+			if err != nil {
+				return fmt.Errorf("%s: %w", describeSweepPoint(axes, values), err)
+			}
+			jobs = append(jobs, sweepJob{point: i, repeat: rep, runner: r})
+		}
+	}
+	logger.Infof("Sweeping %s over %d combinations, %d runs, %d at a time",
+		simConfig.Name, len(grid), len(jobs), min(parallel, len(jobs)))
+
+	runs := executeSweep(jobs, parallel, legionClient, func(job sweepJob) string {
+		return describeSweepPoint(axes, points[job.point].Params)
+	})
+	failed := 0
+	for i, run := range runs {
+		point := &points[jobs[i].point]
+		point.Runs = append(point.Runs, run)
+		point.Outcomes[run.Outcome]++
+		if run.Outcome == outcomeError {
+			failed++
+		}
+	}
+	for i := range points {
+		points[i].Stats = summarizeStats(points[i].Runs)
+	}
+
+	if failed > 0 {
+		logger.Errorf("%d of %d runs failed to complete", failed, len(runs))
+		exitCode = 1
+	}
+	if output == "json" {
+		return writeJSON(resultOut, struct {
+			Simulation string       `json:"simulation"`
+			Varied     []string     `json:"varied"`
+			Repeat     int          `json:"repeat"`
+			Points     []sweepPoint `json:"points"`
+		}{simConfig.Name, varied, repeat, points})
+	}
+	return printSweep(resultOut, simConfig.Name, axes, points, statNames)
+}
+
+// executeSweep runs the jobs, at most parallel at a time, and returns their results in
+// job order. An interrupt stops the runs in progress and skips the rest.
+func executeSweep(jobs []sweepJob, parallel int, legion *client.Legion, label func(sweepJob) string) []sweepRun {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	var (
+		mu      sync.Mutex
+		running = make(map[*runner.Runner]bool)
+	)
+	done := make(chan struct{})
+	defer close(done)
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(sigChan)
+	go func() {
+		select {
+		case <-sigChan:
+		case <-done:
+			return
+		}
+		logger.Warn("\nReceived interrupt signal, stopping the sweep...")
+		cancel()
+		mu.Lock()
+		defer mu.Unlock()
+		for r := range running {
+			if err := r.Stop(); err != nil {
+				logger.Errorf("Failed to stop simulation: %v", err)
+			}
+		}
+	}()
+
+	runs := make([]sweepRun, len(jobs))
+	slots := make(chan struct{}, parallel)
+	var wg sync.WaitGroup
+	for i, job := range jobs {
+		slots <- struct{}{}
+		if ctx.Err() != nil {
+			runs[i] = sweepRun{Repeat: job.repeat, Outcome: simulation.OutcomeStopped, Termination: simulation.TerminationStopped, Summary: "Not run; the sweep was interrupted"}
+			<-slots
+			continue
+		}
+
+		mu.Lock()
+		running[job.runner] = true
+		mu.Unlock()
+		logger.LogSection(fmt.Sprintf("Run %d/%d: %s (repeat %d)", i+1, len(jobs), label(job), job.repeat))
+
+		wg.Add(1)
+		go func(i int, job sweepJob) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			// Nothing reads a sweep run's events; the runner drops them once the buffer fills
+			result, err := job.runner.Run(ctx, legion)
+			mu.Lock()
+			delete(running, job.runner)
+			mu.Unlock()
+
+			run := sweepRun{Repeat: job.repeat}
+			if result != nil {
+				run.Outcome = result.Outcome
+				run.Termination = result.Termination
+				run.Seed = result.Seed
+				run.Summary = result.Summary
+				run.Duration = result.Duration().Round(time.Second).String()
+				run.Stats = result.Stats
+			}
+			if err != nil {
+				logger.Errorf("Run %d (%s) failed: %v", i+1, label(job), err)
+				run.Outcome = outcomeError
+				run.Error = client.Describe(err)
+			}
+			runs[i] = run
+		}(i, job)
+	}
+	wg.Wait()
+	return runs
+}
+
+// parseSweepAxes parses --vary values of the form name=v1,v2,... against the
+// simulation's declared parameters
+func parseSweepAxes(values []string, declared []simulation.Parameter) ([]sweepAxis, error) {
+	types := make(map[string]simulation.Parameter, len(declared))
+	for _, param := range declared {
+		types[param.Name] = param
+	}
+
+	axes := make([]sweepAxis, 0, len(values))
+	seen := make(map[string]bool, len(values))
+	for _, value := range values {
+		name, raw, ok := strings.Cut(value, "=")
+		if !ok || strings.TrimSpace(raw) == "" {
+			return nil, fmt.Errorf("invalid --vary %q: expected name=value,...", value)
+		}
+		name = normalizeParameterName(name)
+		param, ok := types[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid --vary %s: not a parameter of this simulation", name)
+		}
+		if name == "organization_id" {
+			return nil, fmt.Errorf("invalid --vary %s: every run uses the selected organization", name)
+		}
+		if seen[name] {
+			return nil, fmt.Errorf("invalid --vary %s: given more than once", name)
+		}
+		seen[name] = true
+
+		axis := sweepAxis{name: name}
+		for _, item := range strings.Split(raw, ",") {
+			converted, err := utils.ParseParameterValue(strings.TrimSpace(item), param)
+			if err != nil {
+				return nil, fmt.Errorf("invalid --vary %s: %w", name, err)
+			}
+			axis.values = append(axis.values, converted)
+		}
+		axes = append(axes, axis)
+	}
+	return axes, nil
+}
+
+// sweepGrid returns every combination of the axes' values, varying the last axis fastest
+func sweepGrid(axes []sweepAxis) []map[string]interface{} {
+	grid := []map[string]interface{}{{}}
+	for _, axis := range axes {
+		next := make([]map[string]interface{}, 0, len(grid)*len(axis.values))
+		for _, partial := range grid {
+			for _, value := range axis.values {
+				combo := make(map[string]interface{}, len(partial)+1)
+				for name, v := range partial {
+					combo[name] = v
+				}
+				combo[axis.name] = value
+				next = append(next, combo)
+			}
+		}
+		grid = next
+	}
+	return grid
+}
+
+// summarizeStats aggregates every stat reported by the runs that completed
+func summarizeStats(runs []sweepRun) map[string]statSummary {
+	values := make(map[string][]float64)
+	for _, run := range runs {
+		if run.Error != "" {
+			continue // A failed run's partial stats would skew the summary
+		}
+		for name, value := range run.Stats {
+			values[name] = append(values[name], value)
+		}
+	}
+	if len(values) == 0 {
+		return nil
+	}
+
+	stats := make(map[string]statSummary, len(values))
+	for name, vs := range values {
+		summary := statSummary{Min: math.Inf(1), Max: math.Inf(-1)}
+		for _, v := range vs {
+			summary.Mean += v
+			summary.Min = math.Min(summary.Min, v)
+			summary.Max = math.Max(summary.Max, v)
+		}
+		summary.Mean /= float64(len(vs))
+		stats[name] = summary
+	}
+	return stats
+}
+
+// printSweep writes the comparative table: one row per combination with its outcome
+// counts and the mean of each requested stat
+func printSweep(w io.Writer, simName string, axes []sweepAxis, points []sweepPoint, statNames []string) error {
+	logger.LogSection(fmt.Sprintf("%s sweep: %d combinations", simName, len(points)))
+
+	var outcomes []string
+	for _, outcome := range sweepOutcomes {
+		for _, point := range points {
+			if point.Outcomes[outcome] > 0 {
+				outcomes = append(outcomes, outcome)
+				break
+			}
+		}
+	}
+
+	header := make([]string, 0, len(axes)+len(outcomes)+len(statNames)+1)
+	for _, axis := range axes {
+		header = append(header, strings.ToUpper(axis.name))
+	}
+	header = append(header, "RUNS")
+	for _, outcome := range outcomes {
+		header = append(header, strings.ToUpper(outcome))
+	}
+	for _, name := range statNames {
+		header = append(header, strings.ToUpper(name))
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, strings.Join(header, "\t"))
+	for _, point := range points {
+		row := make([]string, 0, len(header))
+		for _, axis := range axes {
+			row = append(row, fmt.Sprint(point.Params[axis.name]))
+		}
+		row = append(row, fmt.Sprint(len(point.Runs)))
+		for _, outcome := range outcomes {
+			row = append(row, fmt.Sprint(point.Outcomes[outcome]))
+		}
+		for _, name := range statNames {
+			if stat, ok := point.Stats[name]; ok {
+				row = append(row, fmt.Sprintf("%.4g", stat.Mean))
+			} else {
+				row = append(row, "-")
+			}
+		}
+		_, _ = fmt.Fprintln(tw, strings.Join(row, "\t"))
+	}
+	return tw.Flush()
+}
+
+// describeSweepPoint renders a combination as name=value pairs in axis order
+func describeSweepPoint(axes []sweepAxis, values map[string]interface{}) string {
+	parts := make([]string, 0, len(axes))
+	for _, axis := range axes {
+		parts = append(parts, fmt.Sprintf("%s=%v", axis.name, values[axis.name]))
+	}
+	return strings.Join(parts, " ")
+}
+
+// declaresParameter reports whether a simulation has the named parameter
+func declaresParameter(declared []simulation.Parameter, name string) bool {
+	for _, param := range declared {
+		if param.Name == name {
+			return true
+		}
+	}
+	return false
+}
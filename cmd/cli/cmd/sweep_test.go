@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+func TestParseSweepAxes(t *testing.T) {
+	declared := []simulation.Parameter{
+		{Name: "organization_id", Type: "string"},
+		{Name: "num_uas_threats", Type: "integer"},
+		{Name: "time_scale", Type: "float"},
+	}
+
+	tests := []struct {
+		name    string
+		values  []string
+		want    []sweepAxis
+		wantErr string
+	}{
+		{
+			name:   "typed values",
+			values: []string{"num_uas_threats=10, 20", "LEGION_TIME_SCALE=1.5"},
+			want: []sweepAxis{
+				{name: "num_uas_threats", values: []interface{}{10, 20}},
+				{name: "time_scale", values: []interface{}{1.5}},
+			},
+		},
+		{name: "duplicate axis", values: []string{"num_uas_threats=10", "num_uas_threats=20"}, wantErr: "given more than once"},
+		{name: "unknown parameter", values: []string{"num_threats=10"}, wantErr: "not a parameter"},
+		{name: "organization", values: []string{"organization_id=abc"}, wantErr: "selected organization"},
+		{name: "no values", values: []string{"num_uas_threats="}, wantErr: "expected name=value"},
+		{name: "bad value", values: []string{"num_uas_threats=10,many"}, wantErr: "invalid --vary num_uas_threats"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			axes, err := parseSweepAxes(tt.values, declared)
+			if tt.wantErr != "" {
+				if err == nil || !strings.Contains(err.Error(), tt.wantErr) {
+					t.Errorf("expected an error containing %q, got %v", tt.wantErr, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(axes, tt.want) {
+				t.Errorf("expected %+v, got %+v", tt.want, axes)
+			}
+		})
+	}
+}
+
+func TestSweepGridVariesLastAxisFastest(t *testing.T) {
+	grid := sweepGrid([]sweepAxis{
+		{name: "waves", values: []interface{}{1, 2}},
+		{name: "time_scale", values: []interface{}{1.0, 2.0, 4.0}},
+	})
+
+	want := []map[string]interface{}{
+		{"waves": 1, "time_scale": 1.0},
+		{"waves": 1, "time_scale": 2.0},
+		{"waves": 1, "time_scale": 4.0},
+		{"waves": 2, "time_scale": 1.0},
+		{"waves": 2, "time_scale": 2.0},
+		{"waves": 2, "time_scale": 4.0},
+	}
+	if !reflect.DeepEqual(grid, want) {
+		t.Errorf("expected %v, got %v", want, grid)
+	}
+
+	if grid := sweepGrid(nil); len(grid) != 1 || len(grid[0]) != 0 {
+		t.Errorf("expected a single run with no axes, got %v", grid)
+	}
+}
+
+func TestSummarizeStats(t *testing.T) {
+	runs := []sweepRun{
+		{Outcome: simulation.OutcomeSuccess, Stats: map[string]float64{"penetration": 0.1, "uas_eliminated": 9}},
+		{Outcome: simulation.OutcomeFailure, Stats: map[string]float64{"penetration": 0.5, "uas_eliminated": 5}},
+		{Outcome: outcomeError, Error: "Legion unavailable"},
+		{Outcome: outcomeError, Error: "context canceled", Stats: map[string]float64{"penetration": 1, "uas_eliminated": 0}},
+		{Outcome: simulation.OutcomePartial, Stats: map[string]float64{"penetration": 0.3}},
+	}
+
+	stats := summarizeStats(runs)
+	want := map[string]statSummary{
+		"penetration":    {Min: 0.1, Max: 0.5, Mean: 0.3},
+		"uas_eliminated": {Min: 5, Max: 9, Mean: 7},
+	}
+	if len(stats) != len(want) {
+		t.Fatalf("expected %d stats, got %v", len(want), stats)
+	}
+	for name, w := range want {
+		got := stats[name]
+		if got.Min != w.Min || got.Max != w.Max || got.Mean < w.Mean-1e-9 || got.Mean > w.Mean+1e-9 {
+			t.Errorf("%s: expected %+v, got %+v", name, w, got)
+		}
+	}
+
+	if stats := summarizeStats([]sweepRun{{Outcome: outcomeError, Error: "failed"}}); stats != nil {
+		t.Errorf("expected no summary when every run errored, got %v", stats)
+	}
+}
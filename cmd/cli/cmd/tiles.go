@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/tiles"
+)
+
+var tilesCmd = &cobra.Command{
+	Use:   "tiles",
+	Short: "Explore recorded runs as vector tiles",
+}
+
+var tilesServeCmd = &cobra.Command{
+	Use:   "serve <recording>",
+	Short: "Serve a run recording as vector tiles for QGIS or kepler.gl",
+	Long: `Serve a run recording (reports/Recording_*.jsonl.gz) as Mapbox vector tiles.
+Layers are the recorded tracks, engagements, events (detections and leakers) and
+coverage envelopes, plus a heatmap binning every point event for the zoom level.
+
+In QGIS add a Vector Tiles connection with the URL
+http://<host>:<port>/tiles/{z}/{x}/{y}.mvt. In kepler.gl add a vector tile layer
+from http://<host>:<port>/tilejson.json.`,
+	Example: `  legion-sim tiles serve reports/Recording_1a2b3c4d_20250301_120000.jsonl.gz
+  legion-sim tiles serve --addr :9000 reports/Recording_1a2b3c4d_20250301_120000.jsonl.gz`,
+	Args: cobra.ExactArgs(1),
+	RunE: serveTiles,
+}
+
+func init() {
+	tilesServeCmd.Flags().String("addr", tiles.DefaultListenAddr, "listen address")
+	tilesCmd.AddCommand(tilesServeCmd)
+}
+
+func serveTiles(cmd *cobra.Command, args []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+
+	rec, err := tiles.Load(args[0])
+	if err != nil {
+		return err
+	}
+	logger.Infof("Loaded %s: layers %v", rec.Name, rec.Layers())
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := tiles.Serve(rec, addr)
+	<-ctx.Done()
+
+	logger.Info("Shutting down tile server")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
@@ -0,0 +1,155 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/control"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/utils"
+)
+
+var tuneCmd = &cobra.Command{
+	Use:   "tune <url>",
+	Short: "Adjust parameters of a running simulation",
+	Long: `Connect to a simulation's control endpoint (enabled with the control_addr parameter)
+and change whitelisted parameters while it runs.
+
+Without --set, opens an interactive panel. Every change is recorded as an inject in the
+simulation's after-action report.
+
+--reload sends a parameters file to the simulation. Logging, behavior weights and alert
+thresholds are applied live; anything that needs a restart is reported and left alone.`,
+	Example: `  legion-sim tune http://sim-host:7600
+  legion-sim tune http://sim-host:7600 --list
+  legion-sim tune http://sim-host:7600 --set success_rate_modifier=0.7 --set update_decimation=4
+  legion-sim tune http://sim-host:7600 --reload params.yaml`,
+	Args: cobra.ExactArgs(1),
+	RunE: tuneSimulation,
+}
+
+func init() {
+	tuneCmd.Flags().Bool("list", false, "print the tunable parameters and exit")
+	tuneCmd.Flags().StringArray("set", nil, "set a parameter (name=value, repeatable)")
+	tuneCmd.Flags().String("reload", "", "send a parameters file (YAML) to the running simulation")
+	tuneCmd.Flags().String("token", "", "control token (defaults to LEGION_CONTROL_TOKEN)")
+}
+
+func tuneSimulation(cmd *cobra.Command, args []string) error {
+	url := args[0]
+	list, _ := cmd.Flags().GetBool("list")
+	sets, _ := cmd.Flags().GetStringArray("set")
+	reloadFile, _ := cmd.Flags().GetString("reload")
+	token, _ := cmd.Flags().GetString("token")
+	if token == "" {
+		token = os.Getenv("LEGION_CONTROL_TOKEN")
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if reloadFile != "" {
+		params, err := utils.LoadParameterFile(reloadFile, nil)
+		if err != nil {
+			return err
+		}
+		result, err := control.Reload(ctx, url, token, params)
+		if err != nil {
+			return fmt.Errorf("failed to reload parameters: %w", err)
+		}
+		logReloadResult(result)
+		return nil
+	}
+
+	if len(sets) > 0 {
+		for _, set := range sets {
+			name, raw, ok := strings.Cut(set, "=")
+			if !ok {
+				return fmt.Errorf("invalid --set %q: expected name=value", set)
+			}
+			value, err := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+			if err != nil {
+				return fmt.Errorf("invalid value for %s: %w", name, err)
+			}
+			param, err := control.SetParam(ctx, url, token, strings.TrimSpace(name), value)
+			if err != nil {
+				return fmt.Errorf("failed to set %s: %w", name, err)
+			}
+			fmt.Printf("%s = %g\n", param.Name, param.Value)
+		}
+		return nil
+	}
+
+	params, err := control.ListParams(ctx, url)
+	if err != nil {
+		return err
+	}
+	if list {
+		return printParams(params)
+	}
+	return tunePanel(url, token, params)
+}
+
+// printParams prints the tunable parameters as a table
+func printParams(params []control.Param) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "NAME\tVALUE\tRANGE\tDESCRIPTION")
+	_, _ = fmt.Fprintln(w, "----\t-----\t-----\t-----------")
+	for _, p := range params {
+		_, _ = fmt.Fprintf(w, "%s\t%g\t%g-%g\t%s\n", p.Name, p.Value, p.Min, p.Max, p.Description)
+	}
+	return w.Flush()
+}
+
+// tunePanel lets the facilitator pick and change parameters until they choose Done
+func tunePanel(url, token string, params []control.Param) error {
+	const done = "Done"
+	for {
+		options := make([]string, 0, len(params)+1)
+		for _, p := range params {
+			options = append(options, fmt.Sprintf("%s (%g)", p.Name, p.Value))
+		}
+		options = append(options, done)
+
+		var choice int
+		if err := survey.AskOne(&survey.Select{Message: "Parameter to change:", Options: options}, &choice); err != nil {
+			return err
+		}
+		if choice == len(params) {
+			return nil
+		}
+
+		selected := params[choice]
+		var raw string
+		prompt := &survey.Input{
+			Message: fmt.Sprintf("%s (%g-%g):", selected.Name, selected.Min, selected.Max),
+			Default: strconv.FormatFloat(selected.Value, 'g', -1, 64),
+			Help:    selected.Description,
+		}
+		if err := survey.AskOne(prompt, &raw, survey.WithValidator(func(ans interface{}) error {
+			_, err := strconv.ParseFloat(strings.TrimSpace(ans.(string)), 64)
+			return err
+		})); err != nil {
+			return err
+		}
+		value, _ := strconv.ParseFloat(strings.TrimSpace(raw), 64)
+
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		updated, err := control.SetParam(ctx, url, token, selected.Name, value)
+		cancel()
+		if err != nil {
+			logger.Warnf("%v", err)
+			continue
+		}
+		params[choice] = updated
+		fmt.Printf("%s set to %g\n", updated.Name, updated.Value)
+	}
+}
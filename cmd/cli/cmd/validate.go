@@ -0,0 +1,73 @@
+package cmd
+
+import (
+	"github.com/fatih/color"
+	"github.com/spf13/cobra"
+
+	swarmconfig "github.com/picogrid/legion-simulations/cmd/drone-swarm/config"
+)
+
+// invalidConfigExitCode is returned when validate finds problems
+const invalidConfigExitCode = 1
+
+var validateCmd = &cobra.Command{
+	Use:   "validate <file>",
+	Short: "Check a drone-swarm config file without running it",
+	Long: `Load a drone-swarm YAML config and report every problem with it, each at the
+file and line that sets the field:
+
+  - YAML that does not parse, unknown keys and values of the wrong type
+  - everything the simulation's own validation rejects
+  - fields that contradict each other, such as a spawn radius inside the
+    engagement radius or more waves than threats
+
+Exits non-zero when anything is found, so it can gate config changes in CI.`,
+	Example:           `  legion-sim validate cmd/drone-swarm/config.yaml`,
+	Args:              cobra.ExactArgs(1),
+	ValidArgsFunction: completeScenarioFiles,
+	RunE:              validateConfig,
+}
+
+func init() {
+	addOutputFlag(validateCmd)
+}
+
+func validateConfig(cmd *cobra.Command, args []string) error {
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	path := args[0]
+	problems, err := swarmconfig.CheckFile(path)
+	if err != nil {
+		return err
+	}
+	if len(problems) > 0 {
+		exitCode = invalidConfigExitCode
+	}
+	if output == "json" {
+		if problems == nil {
+			problems = []swarmconfig.Problem{}
+		}
+		return writeJSON(cmd.OutOrStdout(), struct {
+			File     string                `json:"file"`
+			Valid    bool                  `json:"valid"`
+			Problems []swarmconfig.Problem `json:"problems"`
+		}{path, len(problems) == 0, problems})
+	}
+	if len(problems) == 0 {
+		_, _ = color.New(color.FgGreen).Printf("✓ %s is valid\n", path)
+		return nil
+	}
+
+	red := color.New(color.FgRed)
+	for _, problem := range problems {
+		_, _ = red.Println(problem)
+	}
+	noun := "problems"
+	if len(problems) == 1 {
+		noun = "problem"
+	}
+	_, _ = red.Printf("\n%d %s in %s\n", len(problems), noun, path)
+	return nil
+}
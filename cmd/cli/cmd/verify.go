@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"text/tabwriter"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/artifacts"
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify <manifest>",
+	Short: "Verify a signed run's artifacts are unmodified",
+	Long: `Check a run manifest's Ed25519 signature, then re-hash every file it lists.
+
+Runs started with signing_key write Manifest_<run>_<time>.json and a detached
+.sig next to the AAR. Verification fails when the signature does not match the
+public key, or when any listed file is missing or differs from its recorded
+SHA-256 digest.`,
+	Example: `  legion-sim verify --key signing.pub reports/Manifest_1a2b3c4d_20260314_233000.json
+  legion-sim verify --key signing.pub --dir ./downloaded-run Manifest_1a2b3c4d_20260314_233000.json`,
+	Args: cobra.ExactArgs(1),
+	RunE: verifyArtifacts,
+}
+
+func init() {
+	verifyCmd.Flags().String("key", "", "Ed25519 public key (PKIX PEM, e.g. from openssl pkey -pubout)")
+	verifyCmd.Flags().String("signature", "", "detached signature (default <manifest>.sig)")
+	verifyCmd.Flags().String("dir", "", "directory holding the artifacts (default the manifest's directory)")
+	_ = verifyCmd.MarkFlagRequired("key")
+	_ = verifyCmd.MarkFlagFilename("key", "pub", "pem")
+	_ = verifyCmd.MarkFlagDirname("dir")
+	addOutputFlag(verifyCmd)
+}
+
+func verifyArtifacts(cmd *cobra.Command, args []string) error {
+	output, err := outputFormat(cmd)
+	if err != nil {
+		return err
+	}
+	manifestPath := args[0]
+	keyPath, _ := cmd.Flags().GetString("key")
+	signaturePath, _ := cmd.Flags().GetString("signature")
+	if signaturePath == "" {
+		signaturePath = manifestPath + ".sig"
+	}
+	dir, _ := cmd.Flags().GetString("dir")
+	if dir == "" {
+		dir = filepath.Dir(manifestPath)
+	}
+
+	public, err := artifacts.LoadPublicKey(keyPath)
+	if err != nil {
+		return err
+	}
+	manifest, checks, err := artifacts.VerifyBundle(manifestPath, signaturePath, dir, public)
+	if manifest == nil {
+		return err
+	}
+
+	if output == "json" {
+		report := struct {
+			RunID      string                `json:"run_id"`
+			Simulation string                `json:"simulation"`
+			KeyID      string                `json:"key_id,omitempty"`
+			CreatedAt  time.Time             `json:"created_at"`
+			Verified   bool                  `json:"verified"`
+			Error      string                `json:"error,omitempty"`
+			Files      []artifacts.FileCheck `json:"files"`
+		}{manifest.RunID, manifest.Simulation, manifest.KeyID, manifest.CreatedAt, err == nil, "", checks}
+		if err != nil {
+			report.Error = err.Error()
+		}
+		if writeErr := writeJSON(cmd.OutOrStdout(), report); writeErr != nil {
+			return writeErr
+		}
+		return err
+	}
+
+	fmt.Printf("Run %s (%s), signed by key %s at %s\n\n", manifest.RunID, manifest.Simulation,
+		manifest.KeyID, manifest.CreatedAt.Format("2006-01-02 15:04:05 MST"))
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(w, "FILE\tSIZE\tSTATUS")
+	_, _ = fmt.Fprintln(w, "----\t----\t------")
+	for _, check := range checks {
+		_, _ = fmt.Fprintf(w, "%s\t%d\t%s\n", check.Name, check.Size, check.Status)
+	}
+	if flushErr := w.Flush(); flushErr != nil {
+		return flushErr
+	}
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("\nAll %d artifacts match the signed manifest\n", len(checks))
+	return nil
+}
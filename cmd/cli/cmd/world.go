@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"context"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/world"
+)
+
+var worldCmd = &cobra.Command{
+	Use:   "world",
+	Short: "Share world state between concurrently running simulations",
+}
+
+var worldServeCmd = &cobra.Command{
+	Use:   "serve",
+	Short: "Host a shared world for simulations in other processes",
+	Long: `Serve a shared world state that simulations join with the world_url parameter.
+Each simulation publishes the assets it owns and sees everyone else's, so different
+scenarios can run in the same area and interact, e.g. a convoy published as a defended
+asset becomes a target for a drone swarm run.
+
+Assets disappear when their simulation withdraws them or stops publishing.`,
+	Example: `  legion-sim world serve --addr :7700
+  legion-sim run -s "Track Traffic Demo" -p convoy.yaml     # world_url: http://host:7700, world_role: defended
+  legion-sim run -s "Drone Swarm Combat" -p swarm.yaml      # world_url: http://host:7700`,
+	Args: cobra.NoArgs,
+	RunE: serveWorld,
+}
+
+func init() {
+	worldServeCmd.Flags().String("addr", world.DefaultListenAddr, "listen address")
+	worldServeCmd.Flags().Duration("ttl", world.DefaultTTL, "drop assets not republished within this long (0 keeps them until withdrawn)")
+	worldCmd.AddCommand(worldServeCmd)
+}
+
+func serveWorld(cmd *cobra.Command, _ []string) error {
+	addr, _ := cmd.Flags().GetString("addr")
+	ttl, _ := cmd.Flags().GetDuration("ttl")
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := world.NewLocal(ttl).Serve(addr)
+	<-ctx.Done()
+
+	logger.Info("Shutting down world service")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
@@ -6,6 +6,7 @@ import (
 
 	"github.com/joho/godotenv"
 	"github.com/picogrid/legion-simulations/cmd/cli/cmd"
+	"github.com/picogrid/legion-simulations/pkg/client"
 )
 
 func main() {
@@ -13,10 +14,13 @@ func main() {
 	_ = godotenv.Load()
 
 	if err := cmd.Execute(); err != nil {
-		_, err := fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		_, err := fmt.Fprintf(os.Stderr, "Error: %s\n", client.Describe(err))
 		if err != nil {
 			return
 		}
 		os.Exit(1)
 	}
+	if code := cmd.ExitCode(); code != 0 {
+		os.Exit(code)
+	}
 }
@@ -0,0 +1,162 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Problem is one thing wrong with a configuration. File, Line and Column are set when
+// it was found in a file; Line is 0 when the file does not set the field.
+type Problem struct {
+	File    string `json:"file"`
+	Line    int    `json:"line,omitempty"`
+	Column  int    `json:"column,omitempty"`
+	Field   string `json:"field,omitempty"` // YAML path, e.g. swarm_config.wave_count; empty for the whole file
+	Message string `json:"message"`
+}
+
+// String formats the problem as file:line:column: field: message
+func (p Problem) String() string {
+	var sb strings.Builder
+	if p.File != "" {
+		sb.WriteString(p.File)
+		if p.Line > 0 {
+			sb.WriteString(":" + strconv.Itoa(p.Line))
+			if p.Column > 0 {
+				sb.WriteString(":" + strconv.Itoa(p.Column))
+			}
+		}
+		sb.WriteString(": ")
+	}
+	if p.Field != "" {
+		sb.WriteString(p.Field + ": ")
+	}
+	sb.WriteString(p.Message)
+	return sb.String()
+}
+
+// yamlLine matches the line prefix yaml puts on decoding errors
+var yamlLine = regexp.MustCompile(`^(?:yaml: )?line (\d+): (.*)$`)
+
+// unknownField matches yaml's error for a key the configuration does not have
+var unknownField = regexp.MustCompile(`^field (\S+) not found in type \S+$`)
+
+// CheckFile reads a config file and reports every problem with it: YAML that does not
+// parse, keys the configuration does not have, values of the wrong type, everything
+// Validate rejects and fields that contradict each other. Each problem is located at
+// the line that sets the field. The error is only for a file that cannot be read.
+func CheckFile(path string) ([]Problem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("error reading config file: %w", err)
+	}
+
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return locate(path, nil, decodeProblems(err)), nil
+	}
+	if len(root.Content) == 0 {
+		return []Problem{{File: path, Message: "config file is empty"}}, nil
+	}
+
+	// Unknown keys are most often typos of real ones, which would silently keep their defaults
+	var config SimulationConfig
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	problems := decodeProblems(decoder.Decode(&config))
+
+	if err := config.Defaults.CenterLocation.Resolve(); err != nil {
+		problems = append(problems, Problem{Field: "defaults.center_location.mgrs", Message: err.Error()})
+	}
+	problems = append(problems, config.Problems()...)
+	problems = append(problems, config.ConsistencyProblems()...)
+	problems = locate(path, &root, problems)
+
+	// In file order, with problems for fields the file leaves unset last
+	sort.SliceStable(problems, func(i, j int) bool {
+		li, lj := problems[i].Line, problems[j].Line
+		if li == 0 || lj == 0 {
+			return lj == 0 && li != 0
+		}
+		return li < lj
+	})
+	return problems, nil
+}
+
+// decodeProblems splits a yaml error into one problem per line it names
+func decodeProblems(err error) []Problem {
+	if err == nil || errors.Is(err, io.EOF) {
+		return nil
+	}
+	messages := []string{err.Error()}
+	var typeErr *yaml.TypeError
+	if errors.As(err, &typeErr) {
+		messages = typeErr.Errors
+	}
+
+	problems := make([]Problem, 0, len(messages))
+	for _, message := range messages {
+		problem := Problem{Message: message}
+		if m := yamlLine.FindStringSubmatch(message); m != nil {
+			problem.Line, _ = strconv.Atoi(m[1])
+			problem.Message = m[2]
+		}
+		if m := unknownField.FindStringSubmatch(problem.Message); m != nil {
+			problem.Message = fmt.Sprintf("unknown key %q", m[1])
+		}
+		problems = append(problems, problem)
+	}
+	return problems
+}
+
+// locate sets each problem's file and, for problems naming a field, the line and
+// column of the deepest part of the field's path the file sets
+func locate(path string, root *yaml.Node, problems []Problem) []Problem {
+	for i := range problems {
+		problems[i].File = path
+		if problems[i].Field == "" || root == nil {
+			continue
+		}
+		if node := findField(root, problems[i].Field); node != nil {
+			problems[i].Line, problems[i].Column = node.Line, node.Column
+		}
+	}
+	return problems
+}
+
+// findField returns the key node for a dotted field path, or for the nearest enclosing
+// section when the field itself is not set
+func findField(root *yaml.Node, field string) *yaml.Node {
+	node := root
+	if node.Kind == yaml.DocumentNode && len(node.Content) > 0 {
+		node = node.Content[0]
+	}
+
+	var found *yaml.Node
+	for _, part := range strings.Split(field, ".") {
+		if node.Kind != yaml.MappingNode {
+			break
+		}
+		var next *yaml.Node
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			if node.Content[i].Value == part {
+				found, next = node.Content[i], node.Content[i+1]
+				break
+			}
+		}
+		if next == nil {
+			break
+		}
+		node = next
+	}
+	return found
+}
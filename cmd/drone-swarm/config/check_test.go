@@ -0,0 +1,88 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCheckFileReportsEveryProblem(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	data := `simulation:
+  name: "drone-swarm"
+  update_interval: 3s
+swarm_config:
+  wave_count: 30
+  wave_cont: 3
+  speed_range:
+    min: 200
+    max: 50
+defense_config:
+  engagement_radius_km: 5
+defaults:
+  num_counter_uas_systems: five
+  num_uas_threats: 20
+advanced:
+  spawn_radius_km: 4
+target_priority:
+  distance_weight: 1
+engagement:
+  kinetic_success_rate_range: {min: 0.7, max: 0.9}
+  ew_success_rate_range: {min: 0.5, max: 0.7}
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	problems, err := CheckFile(path)
+	if err != nil {
+		t.Fatalf("CheckFile: %v", err)
+	}
+	want := []struct {
+		line    int
+		message string
+	}{
+		{5, "30 waves need at least 30 UAS threats"},
+		{6, `unknown key "wave_cont"`},
+		{7, "speed range min must be less than max"},
+		{13, "cannot unmarshal"},
+		{13, "number of Counter-UAS systems must be positive"},
+		{16, "spawn radius 4km must be beyond the engagement radius 5km"},
+	}
+	if len(problems) != len(want) {
+		t.Fatalf("expected %d problems, got %d: %v", len(want), len(problems), problems)
+	}
+	for i, w := range want {
+		if problems[i].Line != w.line || !strings.Contains(problems[i].Message, w.message) {
+			t.Errorf("problem %d: expected line %d %q, got %s", i, w.line, w.message, problems[i])
+		}
+		if !strings.HasPrefix(problems[i].String(), path+":") {
+			t.Errorf("problem %d should name the file: %s", i, problems[i])
+		}
+	}
+}
+
+func TestCheckFileAcceptsShippedConfig(t *testing.T) {
+	problems, err := CheckFile("../config.yaml")
+	if err != nil {
+		t.Fatalf("CheckFile: %v", err)
+	}
+	if len(problems) > 0 {
+		t.Errorf("expected the shipped config to be valid, got %v", problems)
+	}
+}
+
+func TestCheckFileSyntaxError(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("simulation:\n  name: [unclosed\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	problems, err := CheckFile(path)
+	if err != nil {
+		t.Fatalf("CheckFile: %v", err)
+	}
+	if len(problems) != 1 || problems[0].Line == 0 {
+		t.Errorf("expected one located syntax problem, got %v", problems)
+	}
+}
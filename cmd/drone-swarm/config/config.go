@@ -1,8 +1,11 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/geo"
 )
 
 // SimulationConfig holds the complete simulation configuration
@@ -53,6 +56,20 @@ type Location struct {
 	Latitude  float64 `yaml:"latitude"`
 	Longitude float64 `yaml:"longitude"`
 	Altitude  float64 `yaml:"altitude"`
+	MGRS      string  `yaml:"mgrs,omitempty"` // Grid reference; overrides latitude/longitude when set
+}
+
+// Resolve fills latitude and longitude from the MGRS grid reference, if one is set
+func (l *Location) Resolve() error {
+	if l.MGRS == "" {
+		return nil
+	}
+	pos, err := geo.ParseMGRS(l.MGRS)
+	if err != nil {
+		return err
+	}
+	l.Latitude, l.Longitude = pos.Lat, pos.Lon
+	return nil
 }
 
 // SpeedRange defines a range of speeds
@@ -158,63 +175,84 @@ type TerminationConfig struct {
 type PerformanceConfig struct {
 	WorkerPoolSize          int           `yaml:"worker_pool_size"`
 	BatchSize               int           `yaml:"batch_size"`
-	APIRateLimit            int           `yaml:"api_rate_limit"`
+	APIRateLimit            int           `yaml:"api_rate_limit"` // Legion API calls per second (0 is unlimited)
 	UpdateFlushInterval     time.Duration `yaml:"update_flush_interval"`
 	MaxConcurrentGoroutines int           `yaml:"max_concurrent_goroutines"`
 }
 
-// Validate checks if the configuration is valid
+// Validate checks if the configuration is valid, returning the first problem found
 func (c *SimulationConfig) Validate() error {
-	if c.Simulation.Name == "" {
-		return fmt.Errorf("simulation name is required")
-	}
-
-	if c.Simulation.UpdateInterval <= 0 {
-		return fmt.Errorf("update interval must be positive")
+	if problems := c.Problems(); len(problems) > 0 {
+		return errors.New(problems[0].Message)
 	}
+	return nil
+}
 
-	if c.Defaults.NumCounterUASSystems <= 0 {
-		return fmt.Errorf("number of Counter-UAS systems must be positive")
+// Problems lists every rule Validate checks that the configuration breaks
+func (c *SimulationConfig) Problems() []Problem {
+	var problems []Problem
+	check := func(ok bool, field, message string) {
+		if !ok {
+			problems = append(problems, Problem{Field: field, Message: message})
+		}
 	}
 
-	if c.Defaults.NumUASThreats <= 0 {
-		return fmt.Errorf("number of UAS threats must be positive")
-	}
+	check(c.Simulation.Name != "", "simulation.name", "simulation name is required")
+	check(c.Simulation.UpdateInterval > 0, "simulation.update_interval", "update interval must be positive")
+	check(c.Defaults.NumCounterUASSystems > 0, "defaults.num_counter_uas_systems", "number of Counter-UAS systems must be positive")
+	check(c.Defaults.NumUASThreats > 0, "defaults.num_uas_threats", "number of UAS threats must be positive")
+	check(c.Performance.APIRateLimit >= 0, "performance.api_rate_limit", "API rate limit cannot be negative")
 
 	// Validate probability ranges
-	if c.SwarmConfig.EvasionProbability < 0 || c.SwarmConfig.EvasionProbability > 1 {
-		return fmt.Errorf("evasion probability must be between 0.0 and 1.0")
-	}
-
-	if c.DefenseConfig.KineticRatio < 0 || c.DefenseConfig.KineticRatio > 1 {
-		return fmt.Errorf("kinetic ratio must be between 0.0 and 1.0")
-	}
-
-	if c.Defaults.EngagementTypeMix < 0 || c.Defaults.EngagementTypeMix > 1 {
-		return fmt.Errorf("engagement type mix must be between 0.0 and 1.0")
-	}
+	check(c.SwarmConfig.EvasionProbability >= 0 && c.SwarmConfig.EvasionProbability <= 1,
+		"swarm_config.evasion_probability", "evasion probability must be between 0.0 and 1.0")
+	check(c.DefenseConfig.KineticRatio >= 0 && c.DefenseConfig.KineticRatio <= 1,
+		"defense_config.kinetic_ratio", "kinetic ratio must be between 0.0 and 1.0")
+	check(c.Defaults.EngagementTypeMix >= 0 && c.Defaults.EngagementTypeMix <= 1,
+		"defaults.engagement_type_mix", "engagement type mix must be between 0.0 and 1.0")
 
 	// Validate speed ranges
-	if c.SwarmConfig.SpeedRange.Min >= c.SwarmConfig.SpeedRange.Max {
-		return fmt.Errorf("speed range min must be less than max")
-	}
+	check(c.SwarmConfig.SpeedRange.Min < c.SwarmConfig.SpeedRange.Max,
+		"swarm_config.speed_range", "speed range min must be less than max")
 
 	// Validate success rate ranges
-	if c.Engagement.KineticSuccessRateRange.Min >= c.Engagement.KineticSuccessRateRange.Max {
-		return fmt.Errorf("kinetic success rate range min must be less than max")
-	}
-
-	if c.Engagement.EWSuccessRateRange.Min >= c.Engagement.EWSuccessRateRange.Max {
-		return fmt.Errorf("EW success rate range min must be less than max")
-	}
+	check(c.Engagement.KineticSuccessRateRange.Min < c.Engagement.KineticSuccessRateRange.Max,
+		"engagement.kinetic_success_rate_range", "kinetic success rate range min must be less than max")
+	check(c.Engagement.EWSuccessRateRange.Min < c.Engagement.EWSuccessRateRange.Max,
+		"engagement.ew_success_rate_range", "EW success rate range min must be less than max")
 
 	// Validate priority weights sum to reasonable values
 	weightSum := c.TargetPriority.DistanceWeight + c.TargetPriority.SpeedWeight + c.TargetPriority.RoleWeight
-	if weightSum <= 0 {
-		return fmt.Errorf("target priority weights must sum to a positive value")
+	check(weightSum > 0, "target_priority", "target priority weights must sum to a positive value")
+
+	return problems
+}
+
+// ConsistencyProblems checks fields that are each valid alone but contradict each other
+func (c *SimulationConfig) ConsistencyProblems() []Problem {
+	var problems []Problem
+	defense := c.DefenseConfig
+
+	// Threats spawned inside the engagement envelope are engaged before they are seen coming
+	if c.Advanced.SpawnRadiusKm > 0 && c.Advanced.SpawnRadiusKm <= defense.EngagementRadiusKm {
+		problems = append(problems, Problem{Field: "advanced.spawn_radius_km", Message: fmt.Sprintf(
+			"spawn radius %gkm must be beyond the engagement radius %gkm", c.Advanced.SpawnRadiusKm, defense.EngagementRadiusKm)})
+	}
+	if defense.DetectionRadiusKm > 0 && defense.DetectionRadiusKm < defense.EngagementRadiusKm {
+		problems = append(problems, Problem{Field: "defense_config.detection_radius_km", Message: fmt.Sprintf(
+			"detection radius %gkm is inside the engagement radius %gkm", defense.DetectionRadiusKm, defense.EngagementRadiusKm)})
 	}
 
-	return nil
+	// Every wave needs at least one threat
+	waves := c.SwarmConfig.WaveCount
+	switch {
+	case waves < 0:
+		problems = append(problems, Problem{Field: "swarm_config.wave_count", Message: "wave count must not be negative"})
+	case waves > c.Defaults.NumUASThreats && c.Defaults.NumUASThreats > 0:
+		problems = append(problems, Problem{Field: "swarm_config.wave_count", Message: fmt.Sprintf(
+			"%d waves need at least %d UAS threats, but num_uas_threats is %d", waves, waves, c.Defaults.NumUASThreats)})
+	}
+	return problems
 }
 
 // String returns a human-readable representation of the configuration
@@ -9,6 +9,8 @@ import (
 	"time"
 
 	"gopkg.in/yaml.v3"
+
+	"github.com/picogrid/legion-simulations/pkg/geo"
 )
 
 // LoadConfig loads configuration from a YAML file
@@ -30,6 +32,11 @@ func LoadConfig(path string) (*SimulationConfig, error) {
 		return nil, fmt.Errorf("error parsing config file: %w", err)
 	}
 
+	// Convert grid references to latitude/longitude
+	if err := config.Defaults.CenterLocation.Resolve(); err != nil {
+		return nil, fmt.Errorf("invalid center_location: %w", err)
+	}
+
 	// Validate configuration
 	if err := config.Validate(); err != nil {
 		return nil, fmt.Errorf("invalid configuration: %w", err)
@@ -140,6 +147,14 @@ func MergeWithCLIOverrides(config *SimulationConfig, overrides map[string]interf
 			if alt, ok := value.(float64); ok {
 				config.Defaults.CenterLocation.Altitude = alt
 			}
+		case "center_mgrs":
+			if ref, ok := value.(string); ok && ref != "" {
+				if pos, err := geo.ParseMGRS(ref); err == nil {
+					config.Defaults.CenterLocation.MGRS = ref
+					config.Defaults.CenterLocation.Latitude = pos.Lat
+					config.Defaults.CenterLocation.Longitude = pos.Lon
+				}
+			}
 		case "formation_type":
 			if formation, ok := value.(string); ok {
 				validFormations := []string{"distributed", "concentrated", "waves"}
@@ -274,6 +289,14 @@ func MergeWithEnvironment(config *SimulationConfig) {
 		}
 	}
 
+	if ref := os.Getenv("CENTER_MGRS"); ref != "" {
+		if pos, err := geo.ParseMGRS(ref); err == nil {
+			config.Defaults.CenterLocation.MGRS = ref
+			config.Defaults.CenterLocation.Latitude = pos.Lat
+			config.Defaults.CenterLocation.Longitude = pos.Lon
+		}
+	}
+
 	// Override engagement parameters
 	if kineticRatio := os.Getenv("ENGAGEMENT_TYPE_MIX"); kineticRatio != "" {
 		if ratio, err := strconv.ParseFloat(kineticRatio, 64); err == nil && ratio >= 0 && ratio <= 1 {
@@ -16,6 +16,7 @@ import (
 	"github.com/picogrid/legion-simulations/pkg/client"
 	"github.com/picogrid/legion-simulations/pkg/logger"
 	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
 )
 
 // Entity types
@@ -57,6 +58,7 @@ type SimulationController struct {
 	swarmController   *SwarmController
 	updateBuffer      *core.UpdateBuffer
 	engagementCalc    *core.EngagementCalculator
+	rng               *rand.Rand
 	simLogger         *reporting.SimulationLogger
 	startTime         time.Time
 	endTime           time.Time
@@ -85,8 +87,9 @@ type SimulationConfig struct {
 	TargetRadiusKm       float64
 	WaveCount            int
 	WaveDelay            time.Duration
-	DefensePlacement     string // "ring", "cluster", "line"
-	FormationType        string // "distributed", "concentrated", "waves"
+	DefensePlacement     string     // "ring", "cluster", "line"
+	FormationType        string     // "distributed", "concentrated", "waves"
+	Rand                 *rand.Rand // Source for every random draw (nil draws an unseeded one)
 }
 
 // Location represents a geographic location
@@ -159,9 +162,13 @@ func (sc *SimulationController) Initialize(ctx context.Context) error {
 	sc.startTime = time.Now()
 
 	// Initialize components
+	sc.rng = sc.config.Rand
+	if sc.rng == nil {
+		sc.rng, _ = simulation.NewRand(0)
+	}
 	sc.systemController = NewSystemController()
-	sc.swarmController = NewSwarmController()
-	sc.engagementCalc = core.NewEngagementCalculator()
+	sc.swarmController = NewSwarmController(simulation.DeriveRand(sc.rng))
+	sc.engagementCalc = core.NewEngagementCalculator(simulation.DeriveRand(sc.rng))
 	sc.updateBuffer = core.NewUpdateBuffer(sc.legionClient, sc.organizationID, 50, time.Second)
 
 	// Initialize logger
@@ -373,7 +380,8 @@ func (sc *SimulationController) generateAAR() error {
 		return fmt.Errorf("failed to generate AAR: %w", err)
 	}
 
-	return aarGen.SaveAAR(aar)
+	_, err = aarGen.SaveAAR(aar)
+	return err
 }
 
 // createCounterUASSystems creates Counter-UAS system entities in Legion
@@ -408,8 +416,8 @@ func (sc *SimulationController) createCounterUASSystems(ctx context.Context) err
 			}
 		case "cluster":
 			// Random placement within target radius
-			angle := rand.Float64() * 2 * math.Pi
-			radius := rand.Float64() * sc.config.TargetRadiusKm * 1000
+			angle := sc.rng.Float64() * 2 * math.Pi
+			radius := sc.rng.Float64() * sc.config.TargetRadiusKm * 1000
 			position = &models.GeomPoint{
 				Type:        &pointType,
 				Coordinates: []float64{centerX + radius*math.Cos(angle), centerY + radius*math.Sin(angle), centerZ},
@@ -431,7 +439,7 @@ func (sc *SimulationController) createCounterUASSystems(ctx context.Context) err
 
 		// Create system instance
 		name := fmt.Sprintf("CUAS-%s-%d", engagementType, i+1)
-		system := NewCounterUASSystem(name, position, engagementType)
+		system := NewCounterUASSystem(name, position, engagementType, sc.rng)
 
 		// Create entity in Legion
 		metadata, err := json.Marshal(system.GetMetadata())
@@ -511,7 +519,7 @@ func (sc *SimulationController) createUASThreats(ctx context.Context) error {
 			}
 
 			// Random spawn position outside spawn radius
-			angle := rand.Float64() * 2 * math.Pi
+			angle := sc.rng.Float64() * 2 * math.Pi
 			spawnDistance := sc.config.SpawnRadiusKm * 1000
 			pointTypeSpawn := "Point"
 			position := &models.GeomPoint{
@@ -519,13 +527,13 @@ func (sc *SimulationController) createUASThreats(ctx context.Context) error {
 				Coordinates: []float64{
 					centerX + spawnDistance*math.Cos(angle),
 					centerY + spawnDistance*math.Sin(angle),
-					centerZ + 100 + rand.Float64()*400, // 100-500m altitude
+					centerZ + 100 + sc.rng.Float64()*400, // 100-500m altitude
 				},
 			}
 
 			// Create threat instance
 			name := fmt.Sprintf("UAS-W%d-%d", wave+1, i+1)
-			threat := NewUASThreat(name, position, wave, formationRole)
+			threat := NewUASThreat(name, position, wave, formationRole, sc.rng)
 
 			// Create entity in Legion
 			metadata, err := json.Marshal(threat.GetMetadata())
@@ -749,9 +757,9 @@ func (sc *SimulationController) updateCounterUASBehavior(ctx context.Context, sy
 		// Engagement is processed, transition to cooldown
 		system.mu.Lock()
 		if system.EngagementType == "kinetic" {
-			system.CooldownRemaining = 5 + rand.Intn(3) // 5-7 seconds
+			system.CooldownRemaining = 5 + sc.rng.Intn(3) // 5-7 seconds
 		} else {
-			system.CooldownRemaining = 8 + rand.Intn(3) // 8-10 seconds
+			system.CooldownRemaining = 8 + sc.rng.Intn(3) // 8-10 seconds
 		}
 		system.Status = CounterUASStatusCooldown
 		system.CurrentTarget = nil
@@ -771,11 +779,11 @@ func (sc *SimulationController) updateCounterUASBehavior(ctx context.Context, sy
 
 // Helper functions
 
-// NewCounterUASSystem creates a new Counter-UAS system
-func NewCounterUASSystem(name string, position *models.GeomPoint, engagementType string) *CounterUASSystem {
-	successRate := 0.7 + rand.Float64()*0.2 // 0.7-0.9 for kinetic
+// NewCounterUASSystem creates a new Counter-UAS system, drawing its success rate from rng
+func NewCounterUASSystem(name string, position *models.GeomPoint, engagementType string, rng *rand.Rand) *CounterUASSystem {
+	successRate := 0.7 + rng.Float64()*0.2 // 0.7-0.9 for kinetic
 	if engagementType == "electronic_warfare" {
-		successRate = 0.5 + rand.Float64()*0.2 // 0.5-0.7 for EW
+		successRate = 0.5 + rng.Float64()*0.2 // 0.5-0.7 for EW
 	}
 
 	ammoCapacity := -1 // Unlimited for EW
@@ -802,12 +810,12 @@ func NewCounterUASSystem(name string, position *models.GeomPoint, engagementType
 	}
 }
 
-// NewUASThreat creates a new UAS threat
-func NewUASThreat(name string, position *models.GeomPoint, waveNumber int, formationRole string) *UASThreat {
-	speedKph := 50.0 + rand.Float64()*150.0   // 50-200 kph
-	autonomyLevel := rand.Float64()           // 0.0-1.0
-	evasionCapability := rand.Float64() > 0.3 // 70% have evasion
-	attackVector := rand.Float64() * 360.0    // 0-360 degrees
+// NewUASThreat creates a new UAS threat, drawing its performance from rng
+func NewUASThreat(name string, position *models.GeomPoint, waveNumber int, formationRole string, rng *rand.Rand) *UASThreat {
+	speedKph := 50.0 + rng.Float64()*150.0   // 50-200 kph
+	autonomyLevel := rng.Float64()           // 0.0-1.0
+	evasionCapability := rng.Float64() > 0.3 // 70% have evasion
+	attackVector := rng.Float64() * 360.0    // 0-360 degrees
 
 	// Calculate initial velocity based on attack vector
 	velocityMagnitude := speedKph / 3.6 // Convert to m/s
@@ -998,11 +1006,6 @@ func (sc *SimulationController) canEngage(system *CounterUASSystem, threat *UAST
 		return false
 	}
 
-	// Check if EW can affect this target
-	if system.EngagementType == "electronic_warfare" && threat.AutonomyLevel >= 0.5 {
-		return false
-	}
-
 	return true
 }
 
@@ -1026,6 +1029,7 @@ func (sc *SimulationController) processEngagement(_ context.Context, system *Cou
 		SpeedKph:          threat.SpeedKph,
 		EvasionCapability: threat.EvasionCapability,
 		Status:            threat.Status,
+		Nav:               core.NavStackForAutonomy(threat.AutonomyLevel),
 	}
 
 	// Calculate environmental modifiers
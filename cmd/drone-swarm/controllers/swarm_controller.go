@@ -11,6 +11,7 @@ import (
 	"github.com/picogrid/legion-simulations/cmd/drone-swarm/core"
 	"github.com/picogrid/legion-simulations/pkg/logger"
 	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
 )
 
 // SwarmController manages UAS threat coordination and wave management
@@ -20,6 +21,7 @@ type SwarmController struct {
 	formations     map[string]Formation
 	targetLocation *models.GeomPoint
 	updateBuffer   *core.UpdateBuffer
+	rng            *rand.Rand
 	mu             sync.RWMutex
 }
 
@@ -91,23 +93,29 @@ func (f *LineFormation) GetSpacing() float64 { return f.Spacing }
 type DistributedFormation struct {
 	MinSpacing float64
 	MaxSpacing float64
+	Rand       *rand.Rand // Source for each slot's jitter
 }
 
 func (f *DistributedFormation) GetSpacing() float64 { return f.MinSpacing }
 
-// NewSwarmController creates a new swarm controller
-func NewSwarmController() *SwarmController {
+// NewSwarmController creates a new swarm controller drawing evasion and formation
+// jitter from rng (nil draws an unseeded one)
+func NewSwarmController(rng *rand.Rand) *SwarmController {
+	if rng == nil {
+		rng, _ = simulation.NewRand(0)
+	}
 	sc := &SwarmController{
 		uasThreats: make(map[uuid.UUID]*UASThreat),
 		waves:      make([]*WaveState, 0),
 		formations: make(map[string]Formation),
+		rng:        rng,
 	}
 
 	// Register default formations
 	sc.formations["v"] = &VFormation{Spacing: 50.0, Angle: 45.0}
 	sc.formations["wedge"] = &WedgeFormation{Spacing: 40.0, Depth: 30.0}
 	sc.formations["line"] = &LineFormation{Spacing: 60.0}
-	sc.formations["distributed"] = &DistributedFormation{MinSpacing: 100.0, MaxSpacing: 200.0}
+	sc.formations["distributed"] = &DistributedFormation{MinSpacing: 100.0, MaxSpacing: 200.0, Rand: rng}
 
 	return sc
 }
@@ -354,9 +362,9 @@ func (sc *SwarmController) applyEvasionBehavior(threat *UASThreat, deltaTime flo
 
 	// Random evasive maneuver
 	evasionForce := 10.0
-	threat.Velocity.Coordinates[0] += (rand.Float64()*2 - 1) * evasionForce
-	threat.Velocity.Coordinates[1] += (rand.Float64()*2 - 1) * evasionForce
-	threat.Velocity.Coordinates[2] += (rand.Float64()*0.5 - 0.25) * evasionForce // Less vertical evasion
+	threat.Velocity.Coordinates[0] += (sc.rng.Float64()*2 - 1) * evasionForce
+	threat.Velocity.Coordinates[1] += (sc.rng.Float64()*2 - 1) * evasionForce
+	threat.Velocity.Coordinates[2] += (sc.rng.Float64()*0.5 - 0.25) * evasionForce // Less vertical evasion
 
 	// Update status
 	threat.Status = UASStatusEvading
@@ -517,13 +525,13 @@ func (f *LineFormation) GetTargetPosition(index int, center Vector3D, heading fl
 
 func (f *DistributedFormation) GetTargetPosition(index int, center Vector3D, heading float64) Vector3D {
 	// Random distributed formation
-	angle := rand.Float64() * 2 * math.Pi
-	distance := f.MinSpacing + rand.Float64()*(f.MaxSpacing-f.MinSpacing)
+	angle := f.Rand.Float64() * 2 * math.Pi
+	distance := f.MinSpacing + f.Rand.Float64()*(f.MaxSpacing-f.MinSpacing)
 
 	return Vector3D{
 		X: center.X + distance*math.Cos(angle),
 		Y: center.Y + distance*math.Sin(angle),
-		Z: center.Z + (rand.Float64()*100 - 50), // Random altitude variation
+		Z: center.Z + (f.Rand.Float64()*100 - 50), // Random altitude variation
 	}
 }
 
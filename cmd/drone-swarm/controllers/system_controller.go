@@ -167,11 +167,6 @@ func (sc *SystemController) GetHighestPriorityTarget(systemID uuid.UUID) (*UASTh
 			continue
 		}
 
-		// Skip if EW system can't affect high autonomy targets
-		if system.EngagementType == "electronic_warfare" && threat.AutonomyLevel >= 0.5 {
-			continue
-		}
-
 		// Calculate priority
 		distancePriority := 1.0 - (distance / system.DetectionRadiusKm)
 		speedPriority := threat.SpeedKph / 200.0
@@ -185,6 +180,11 @@ func (sc *SystemController) GetHighestPriorityTarget(systemID uuid.UUID) (*UASTh
 
 		priority := distancePriority*0.5 + speedPriority*0.3 + rolePriority*0.2
 
+		// EW prefers targets that depend on jammable links
+		if system.EngagementType == "electronic_warfare" {
+			priority *= core.NavStackForAutonomy(threat.AutonomyLevel).JamSusceptibility(false)
+		}
+
 		if bestTarget == nil || priority > bestPriority {
 			bestTarget = threat
 			bestPriority = priority
@@ -298,12 +298,10 @@ func (sc *SystemController) OptimizeTargetAssignments() map[uuid.UUID]uuid.UUID
 				continue
 			}
 
-			// Skip if EW can't affect high autonomy
-			if system.EngagementType == "electronic_warfare" && threat.AutonomyLevel >= 0.5 {
-				continue
-			}
-
 			priority := 1.0 - (distance / system.DetectionRadiusKm)
+			if system.EngagementType == "electronic_warfare" {
+				priority *= core.NavStackForAutonomy(threat.AutonomyLevel).JamSusceptibility(false)
+			}
 			if priority > bestPriority {
 				bestTarget = threatID
 				bestPriority = priority
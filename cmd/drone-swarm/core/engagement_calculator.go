@@ -13,7 +13,7 @@ import (
 type EngagementCalculator struct {
 	kineticSuccessRange [2]float64 // min, max success rates for kinetic
 	ewSuccessRange      [2]float64 // min, max success rates for electronic warfare
-	autonomyThreshold   float64    // autonomy level below which EW is effective
+	rng                 *rand.Rand // Source for engagement rolls
 	mu                  sync.RWMutex
 }
 
@@ -29,12 +29,12 @@ type EngagementResult struct {
 	Timestamp         time.Time
 }
 
-// NewEngagementCalculator creates a new engagement calculator
-func NewEngagementCalculator() *EngagementCalculator {
+// NewEngagementCalculator creates a new engagement calculator rolling with rng
+func NewEngagementCalculator(rng *rand.Rand) *EngagementCalculator {
 	return &EngagementCalculator{
+		rng:                 rng,
 		kineticSuccessRange: [2]float64{0.7, 0.9}, // 70-90% success rate for kinetic
 		ewSuccessRange:      [2]float64{0.5, 0.7}, // 50-70% success rate for EW
-		autonomyThreshold:   0.5,                  // EW only affects drones with autonomy < 0.5
 	}
 }
 
@@ -69,28 +69,15 @@ func (ec *EngagementCalculator) CalculateEngagement(
 		// Kinetic engagement: 70-90% success rate
 		baseSuccessProb = attacker.SuccessRate
 	case "electronic_warfare":
-		// EW engagement: Check if target can be jammed
-		if target.AutonomyLevel >= ec.autonomyThreshold {
-			// High autonomy drones are immune to jamming
-			return &EngagementResult{
-				AttackerID:     attacker.ID,
-				TargetID:       target.ID,
-				Success:        false,
-				EngagementType: attacker.EngagementType,
-				Distance:       distance,
-				TargetAutonomy: target.AutonomyLevel,
-				Timestamp:      time.Now(),
-			}
-		}
-		// EW is effective against low autonomy targets
-		baseSuccessProb = attacker.SuccessRate
+		// EW engagement: effect depends on which links the target is relying on
+		baseSuccessProb = attacker.SuccessRate * target.Nav.JamSusceptibility(target.Terminal)
 	}
 
 	// Apply environmental and distance modifiers
 	successProb := ec.applyModifiers(baseSuccessProb, distance, attacker.EngagementRangeKm, environmental)
 
 	// Roll for success
-	success := rand.Float64() < successProb
+	success := ec.rng.Float64() < successProb
 
 	result := &EngagementResult{
 		AttackerID:        attacker.ID,
@@ -159,6 +146,8 @@ type UASInfo struct {
 	SpeedKph          float64
 	EvasionCapability bool
 	Status            string
+	Nav               NavStack // Navigation and control links fitted
+	Terminal          bool     // In terminal guidance near its target
 }
 
 // Modifiers contains environmental and situational modifiers
@@ -187,11 +176,6 @@ func (ec *EngagementCalculator) CanEngage(attacker CounterUASInfo, target UASInf
 		return false
 	}
 
-	// Check if EW can affect this target
-	if attacker.EngagementType == "electronic_warfare" && target.AutonomyLevel >= ec.autonomyThreshold {
-		return false
-	}
-
 	return true
 }
 
@@ -210,15 +194,14 @@ func (ec *EngagementCalculator) GetSuccessRate(engagementType string) float64 {
 		return 0.5 // Default for unknown types
 	}
 
-	return minRate + rand.Float64()*(maxRate-minRate)
+	return minRate + ec.rng.Float64()*(maxRate-minRate)
 }
 
 // UpdateConfiguration allows updating the engagement calculator configuration
-func (ec *EngagementCalculator) UpdateConfiguration(kineticMin, kineticMax, ewMin, ewMax, autonomyThreshold float64) {
+func (ec *EngagementCalculator) UpdateConfiguration(kineticMin, kineticMax, ewMin, ewMax float64) {
 	ec.mu.Lock()
 	defer ec.mu.Unlock()
 
 	ec.kineticSuccessRange = [2]float64{kineticMin, kineticMax}
 	ec.ewSuccessRange = [2]float64{ewMin, ewMax}
-	ec.autonomyThreshold = autonomyThreshold
 }
@@ -0,0 +1,71 @@
+package core
+
+// Navigation and control links a UAS can depend on
+const (
+	LinkRC              = "rc"           // Radio control and video downlink
+	LinkGNSS            = "gnss"         // Satellite navigation
+	LinkInertial        = "inertial"     // Onboard inertial navigation
+	LinkOpticalTerminal = "optical_flow" // Camera-based terminal guidance
+)
+
+// TerminalRangeKm is the distance from its target inside which a UAS switches to terminal guidance
+const TerminalRangeKm = 1.5
+
+// NavStack describes which navigation and control links a UAS carries
+type NavStack struct {
+	RCLink          bool
+	GNSS            bool
+	Inertial        bool
+	OpticalTerminal bool
+}
+
+// NavStackForAutonomy maps an autonomy level to a representative nav stack:
+// manual FPV, GNSS waypoint with RC override, GNSS/INS, and GNSS/INS with optical terminal homing
+func NavStackForAutonomy(autonomy float64) NavStack {
+	switch {
+	case autonomy < 0.25:
+		return NavStack{RCLink: true}
+	case autonomy < 0.5:
+		return NavStack{RCLink: true, GNSS: true}
+	case autonomy < 0.75:
+		return NavStack{GNSS: true, Inertial: true}
+	default:
+		return NavStack{GNSS: true, Inertial: true, OpticalTerminal: true}
+	}
+}
+
+// ActiveLinks returns the links in use; optical guidance only engages in the terminal phase
+func (n NavStack) ActiveLinks(terminal bool) []string {
+	var links []string
+	if n.RCLink {
+		links = append(links, LinkRC)
+	}
+	if n.GNSS {
+		links = append(links, LinkGNSS)
+	}
+	if n.Inertial {
+		links = append(links, LinkInertial)
+	}
+	if n.OpticalTerminal && terminal {
+		links = append(links, LinkOpticalTerminal)
+	}
+	return links
+}
+
+// JamSusceptibility returns the fraction of an EW engagement's effect that lands given
+// the links active at the time. RF links (RC, GNSS) can be jammed; inertial and optical
+// guidance cannot, so they determine how well the UAS keeps flying under jamming.
+func (n NavStack) JamSusceptibility(terminal bool) float64 {
+	switch {
+	case terminal && n.OpticalTerminal:
+		return 0.1 // Homing visually, no RF dependence left
+	case n.Inertial:
+		return 0.4 // Jamming GNSS leaves it drifting on inertial
+	case n.RCLink && !n.GNSS:
+		return 1.0 // Loses control and video together
+	case n.RCLink || n.GNSS:
+		return 0.9 // Every guidance source is RF
+	default:
+		return 0.2
+	}
+}
@@ -0,0 +1,35 @@
+package core
+
+import "testing"
+
+func TestJamSusceptibilityDependsOnActiveLinks(t *testing.T) {
+	tests := []struct {
+		name     string
+		autonomy float64
+		terminal bool
+		want     float64
+	}{
+		{"manual FPV", 0.1, false, 1.0},
+		{"GNSS waypoint with RC", 0.4, false, 0.9},
+		{"GNSS/INS", 0.6, false, 0.4},
+		{"optical homing in cruise", 0.9, false, 0.4},
+		{"optical homing in terminal phase", 0.9, true, 0.1},
+	}
+
+	for _, tt := range tests {
+		got := NavStackForAutonomy(tt.autonomy).JamSusceptibility(tt.terminal)
+		if got != tt.want {
+			t.Errorf("%s: expected %.1f, got %.1f", tt.name, tt.want, got)
+		}
+	}
+}
+
+func TestActiveLinksOpticalOnlyInTerminalPhase(t *testing.T) {
+	nav := NavStackForAutonomy(0.9)
+	if links := nav.ActiveLinks(false); len(links) != 2 {
+		t.Errorf("expected gnss and inertial in cruise, got %v", links)
+	}
+	if links := nav.ActiveLinks(true); len(links) != 3 || links[2] != LinkOpticalTerminal {
+		t.Errorf("expected optical guidance in terminal phase, got %v", links)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
 )
 
 // SwarmBehaviorEngine manages UAS threat swarm behaviors for attacking defended positions
@@ -123,8 +125,9 @@ type Objective struct {
 	Status   string
 }
 
-// NewSwarmBehaviorEngine creates a new behavior engine for UAS threat swarms
-func NewSwarmBehaviorEngine() *SwarmBehaviorEngine {
+// NewSwarmBehaviorEngine creates a new behavior engine for UAS threat swarms, drawing
+// random behavior from rng
+func NewSwarmBehaviorEngine(rng *rand.Rand) *SwarmBehaviorEngine {
 	engine := &SwarmBehaviorEngine{
 		behaviors:       make(map[string]Behavior),
 		activeBehaviors: make(map[string]string),
@@ -133,13 +136,14 @@ func NewSwarmBehaviorEngine() *SwarmBehaviorEngine {
 	}
 
 	// Register UAS threat-specific behaviors
-	engine.registerThreatBehaviors()
+	engine.registerThreatBehaviors(rng)
 
 	return engine
 }
 
-// registerThreatBehaviors sets up UAS threat swarm behaviors
-func (e *SwarmBehaviorEngine) registerThreatBehaviors() {
+// registerThreatBehaviors sets up UAS threat swarm behaviors. Each random behavior gets
+// its own stream from rng, since behaviors run in map order.
+func (e *SwarmBehaviorEngine) registerThreatBehaviors(rng *rand.Rand) {
 	// Core swarm coordination behaviors
 	e.behaviors["separation"] = &SeparationBehavior{Weight: 1.5, MinDistance: 30.0}
 	e.behaviors["cohesion"] = &CohesionBehavior{Weight: 1.0}
@@ -151,12 +155,12 @@ func (e *SwarmBehaviorEngine) registerThreatBehaviors() {
 	e.behaviors["objective_approach"] = &ObjectiveApproachBehavior{Weight: 2.0}
 
 	// Defensive behaviors
-	e.behaviors["evasion"] = &EvasionBehavior{Weight: 4.0}
+	e.behaviors["evasion"] = &EvasionBehavior{Weight: 4.0, Rand: simulation.DeriveRand(rng)}
 	e.behaviors["jamming_response"] = &JammingResponseBehavior{Weight: 3.5}
 
 	// Formation behaviors
 	e.behaviors["formation"] = &FormationBehavior{Weight: 2.0}
-	e.behaviors["role_based"] = &RoleBasedBehavior{Weight: 2.2}
+	e.behaviors["role_based"] = &RoleBasedBehavior{Weight: 2.2, Rand: simulation.DeriveRand(rng)}
 
 	// Set default weights
 	e.behaviorWeights["separation"] = 1.0
@@ -551,6 +555,7 @@ func (b *AttackVectorBehavior) Calculate(swarm *Swarm, env *Environment) []Force
 // EvasionBehavior performs evasive maneuvers when under fire
 type EvasionBehavior struct {
 	Weight float64
+	Rand   *rand.Rand // Source for the evasion jink
 }
 
 func (b *EvasionBehavior) GetPriority() float64 { return b.Weight }
@@ -597,9 +602,9 @@ func (b *EvasionBehavior) Calculate(swarm *Swarm, env *Environment) []Force {
 
 					// Random evasion direction
 					evadeDir := Vector3D{
-						X: -toThreat.Y + (rand.Float64()-0.5)*0.5,
-						Y: toThreat.X + (rand.Float64()-0.5)*0.5,
-						Z: (rand.Float64() - 0.5) * 0.3, // Some vertical evasion
+						X: -toThreat.Y + (b.Rand.Float64()-0.5)*0.5,
+						Y: toThreat.X + (b.Rand.Float64()-0.5)*0.5,
+						Z: (b.Rand.Float64() - 0.5) * 0.3, // Some vertical evasion
 					}.Normalize()
 
 					// Stronger evasion when closer
@@ -612,9 +617,9 @@ func (b *EvasionBehavior) Calculate(swarm *Swarm, env *Environment) []Force {
 		// Add some randomness for unpredictability
 		if status == "UNDER_FIRE" {
 			randomForce := Vector3D{
-				X: (rand.Float64() - 0.5),
-				Y: (rand.Float64() - 0.5),
-				Z: (rand.Float64() - 0.5) * 0.5,
+				X: (b.Rand.Float64() - 0.5),
+				Y: (b.Rand.Float64() - 0.5),
+				Z: (b.Rand.Float64() - 0.5) * 0.5,
 			}.Normalize().Scale(0.3)
 			evadeForce = evadeForce.Add(randomForce)
 		}
@@ -735,6 +740,7 @@ func (b *ObjectiveApproachBehavior) Calculate(swarm *Swarm, env *Environment) []
 // RoleBasedBehavior adjusts behavior based on drone role (leader/follower/scout)
 type RoleBasedBehavior struct {
 	Weight float64
+	Rand   *rand.Rand // Source for formation offsets
 }
 
 func (b *RoleBasedBehavior) GetPriority() float64 { return b.Weight }
@@ -775,9 +781,9 @@ func (b *RoleBasedBehavior) Calculate(swarm *Swarm, env *Environment) []Force {
 			if leader, exists := leaders[waveNum]; exists {
 				// Maintain formation relative to leader
 				idealOffset := Vector3D{
-					X: (rand.Float64() - 0.5) * 50, // 50m spread
-					Y: (rand.Float64() - 0.5) * 50,
-					Z: (rand.Float64() - 0.5) * 10,
+					X: (b.Rand.Float64() - 0.5) * 50, // 50m spread
+					Y: (b.Rand.Float64() - 0.5) * 50,
+					Z: (b.Rand.Float64() - 0.5) * 10,
 				}
 				idealPos := leader.Position.Add(idealOffset)
 				roleForce = idealPos.Subtract(drone.Position).Scale(0.3)
@@ -795,7 +801,7 @@ func (b *RoleBasedBehavior) Calculate(swarm *Swarm, env *Environment) []Force {
 					X: -toObjective.Y,
 					Y: toObjective.X,
 					Z: 0,
-				}.Scale((rand.Float64() - 0.5) * 100)
+				}.Scale((b.Rand.Float64() - 0.5) * 100)
 
 				idealPos := swarm.CenterMass.Add(scoutOffset).Add(lateral)
 				roleForce = idealPos.Subtract(drone.Position).Scale(0.4)
@@ -0,0 +1,104 @@
+package core
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// TimingError bounds the clock error of entities with a degraded timing source, such as
+// a GPS receiver in holdover or being spoofed. Each degraded entity draws its own fixed
+// offset and drift within the bounds; jitter is drawn again for every timestamp.
+type TimingError struct {
+	Offset   time.Duration // Largest fixed offset, drawn uniformly within ±Offset
+	DriftPPM float64       // Largest drift in parts per million, accumulating from the start of the run
+	Jitter   time.Duration // 1-sigma error on each timestamp
+	Share    float64       // Fraction of entities with degraded timing, 0.0-1.0
+}
+
+// Enabled reports whether any entity's timestamps are perturbed
+func (e TimingError) Enabled() bool {
+	return e.Share > 0 && (e.Offset != 0 || e.DriftPPM != 0 || e.Jitter != 0)
+}
+
+// EntityClock is one entity's clock error
+type EntityClock struct {
+	Degraded bool
+	Offset   time.Duration
+	DriftPPM float64
+}
+
+// Error returns the clock's systematic error elapsed into the run, before jitter
+func (c EntityClock) Error(elapsed time.Duration) time.Duration {
+	return c.Offset + time.Duration(float64(elapsed)*c.DriftPPM/1e6)
+}
+
+// TimingModel perturbs the RecordedAt timestamps an entity reports. A nil model leaves
+// timestamps untouched.
+type TimingModel struct {
+	bounds TimingError
+	start  time.Time
+	clocks map[uuid.UUID]EntityClock
+	rng    *rand.Rand
+	mu     sync.Mutex
+}
+
+// NewTimingModel creates a model whose drift accumulates from start, drawing clock
+// errors and jitter from rng
+func NewTimingModel(bounds TimingError, start time.Time, rng *rand.Rand) *TimingModel {
+	return &TimingModel{bounds: bounds, start: start, clocks: make(map[uuid.UUID]EntityClock), rng: rng}
+}
+
+// Start returns the time drift accumulates from
+func (m *TimingModel) Start() time.Time {
+	return m.start
+}
+
+// Clock returns an entity's clock error, drawing it on first use
+func (m *TimingModel) Clock(entityID uuid.UUID) EntityClock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	clock, ok := m.clocks[entityID]
+	if !ok {
+		if m.rng.Float64() < m.bounds.Share {
+			clock = EntityClock{
+				Degraded: true,
+				Offset:   time.Duration((2*m.rng.Float64() - 1) * float64(m.bounds.Offset)),
+				DriftPPM: (2*m.rng.Float64() - 1) * m.bounds.DriftPPM,
+			}
+		}
+		m.clocks[entityID] = clock
+	}
+	return clock
+}
+
+// Clocks returns the clock errors drawn so far, one per entity that has reported
+func (m *TimingModel) Clocks() map[uuid.UUID]EntityClock {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	clocks := make(map[uuid.UUID]EntityClock, len(m.clocks))
+	for id, clock := range m.clocks {
+		clocks[id] = clock
+	}
+	return clocks
+}
+
+// Stamp returns the timestamp an entity with its clock error would report for t
+func (m *TimingModel) Stamp(entityID uuid.UUID, t time.Time) time.Time {
+	if m == nil {
+		return t
+	}
+	clock := m.Clock(entityID)
+	if !clock.Degraded {
+		return t
+	}
+
+	stamped := t.Add(clock.Error(t.Sub(m.start)))
+	if m.bounds.Jitter > 0 {
+		stamped = stamped.Add(time.Duration(m.rng.NormFloat64() * float64(m.bounds.Jitter)))
+	}
+	return stamped
+}
@@ -0,0 +1,41 @@
+package core
+
+import (
+	"math/rand"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestTimingModelStamps(t *testing.T) {
+	start := time.Unix(1_700_000_000, 0)
+	m := NewTimingModel(TimingError{Offset: 50 * time.Millisecond, DriftPPM: 20, Share: 1}, start, rand.New(rand.NewSource(1)))
+
+	id := uuid.New()
+	clock := m.Clock(id)
+	if !clock.Degraded || clock.Offset < -50*time.Millisecond || clock.Offset > 50*time.Millisecond ||
+		clock.DriftPPM < -20 || clock.DriftPPM > 20 {
+		t.Fatalf("expected a clock within the bounds, got %+v", clock)
+	}
+	if m.Clock(id) != clock {
+		t.Error("expected an entity to keep its clock")
+	}
+
+	// Without jitter the error is the offset plus drift accumulated since the start
+	at := start.Add(time.Hour)
+	want := clock.Offset + time.Duration(float64(time.Hour)*clock.DriftPPM/1e6)
+	if got := m.Stamp(id, at).Sub(at); got != want {
+		t.Errorf("expected a %s error an hour in, got %s", want, got)
+	}
+
+	// Healthy entities and a nil model report true time
+	healthy := NewTimingModel(TimingError{Offset: time.Second, Share: 0}, start, rand.New(rand.NewSource(1)))
+	if got := healthy.Stamp(id, at); !got.Equal(at) {
+		t.Errorf("expected true time from a healthy clock, got %s off", got.Sub(at))
+	}
+	var none *TimingModel
+	if got := none.Stamp(id, at); !got.Equal(at) {
+		t.Error("expected a nil model to leave timestamps alone")
+	}
+}
@@ -3,6 +3,7 @@ package core
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"sync"
 	"time"
@@ -13,6 +14,20 @@ import (
 	"github.com/picogrid/legion-simulations/pkg/models"
 )
 
+// bufferLog logs flush activity under the "buffer" module level
+var bufferLog = logger.Module(logger.ModuleBuffer)
+
+// LocationSource tags every location the buffer writes to Legion
+const LocationSource = "Drone-Swarm-Simulation"
+
+// PublishPolicy sets how often each entity's updates go to Legion. Updates held back
+// are coalesced and go out on the entity's next turn; status and affiliation changes
+// are never held.
+type PublishPolicy interface {
+	// Interval returns the least time between sends for an entity; 0 sends every flush
+	Interval(entityID uuid.UUID) time.Duration
+}
+
 // UpdateBuffer manages batched updates to Legion API
 type UpdateBuffer struct {
 	client        *client.Legion
@@ -21,8 +36,15 @@ type UpdateBuffer struct {
 	maxBatchSize  int
 	flushInterval time.Duration
 	lastFlush     time.Time
+	stats         UpdateStats
+	sent          map[uuid.UUID]*SentRecord
+	timing        *TimingModel  // Perturbs location timestamps (nil sends true time)
+	policy        PublishPolicy // Per-entity send intervals (nil sends every flush)
+	lastSend      map[uuid.UUID]time.Time
+	held          int // Updates the policy left pending at the last flush
 	mu            sync.Mutex
 	stopChan      chan struct{}
+	stopOnce      sync.Once
 	wg            sync.WaitGroup
 }
 
@@ -31,16 +53,29 @@ type EntityUpdate struct {
 	EntityID     uuid.UUID
 	Position     *models.GeomPoint
 	Status       *string
+	Affiliation  *models.Affiliation
 	Metadata     map[string]interface{}
 	LastModified time.Time
 }
 
+// SentRecord is what the buffer has successfully written to Legion for one entity
+type SentRecord struct {
+	Positions      int               // Locations created
+	FirstSentAt    time.Time         // RecordedAt of the first location
+	LastPosition   *models.GeomPoint // Copy of the most recent location
+	LastRecordedAt time.Time
+	LastStatus     string // Most recent status patched, empty if never sent
+}
+
 // UpdateStats tracks update statistics
 type UpdateStats struct {
 	TotalUpdates     int64
 	BatchesSent      int64
 	UpdatesSent      int64
 	UpdatesFailed    int64
+	UpdatesShed      int64 // Position or metadata writes dropped by the API budget
+	UpdatesDeferred  int64 // Flushes that held an entity's update back for its publish interval
+	FlushesPaused    int64 // Flushes skipped while the client's circuit breaker was open
 	AverageBatchSize float64
 	LastBatchTime    time.Time
 	LastError        error
@@ -55,10 +90,26 @@ func NewUpdateBuffer(client *client.Legion, orgID string, maxBatchSize int, flus
 		maxBatchSize:  maxBatchSize,
 		flushInterval: flushInterval,
 		lastFlush:     time.Now(),
+		sent:          make(map[uuid.UUID]*SentRecord),
+		lastSend:      make(map[uuid.UUID]time.Time),
 		stopChan:      make(chan struct{}),
 	}
 }
 
+// SetTiming perturbs the RecordedAt of every location sent with the model's clock errors
+func (ub *UpdateBuffer) SetTiming(timing *TimingModel) {
+	ub.mu.Lock()
+	defer ub.mu.Unlock()
+	ub.timing = timing
+}
+
+// SetPolicy paces each entity's updates by the policy's interval
+func (ub *UpdateBuffer) SetPolicy(policy PublishPolicy) {
+	ub.mu.Lock()
+	defer ub.mu.Unlock()
+	ub.policy = policy
+}
+
 // Start begins the automatic flush goroutine
 func (ub *UpdateBuffer) Start(ctx context.Context) {
 	ub.wg.Add(1)
@@ -76,19 +127,58 @@ func (ub *UpdateBuffer) Start(ctx context.Context) {
 				return
 			case <-ticker.C:
 				if err := ub.Flush(ctx); err != nil {
-					logger.Errorf("Error flushing updates: %v", err)
+					bufferLog.Errorf("Error flushing updates: %v", err)
 				}
 			}
 		}
 	}()
 }
 
-// Stop stops the update buffer
+// Stop stops the automatic flush goroutine, waiting for a flush in progress to finish.
+// It is safe to call more than once.
 func (ub *UpdateBuffer) Stop() {
-	close(ub.stopChan)
+	ub.stopOnce.Do(func() { close(ub.stopChan) })
 	ub.wg.Wait()
 }
 
+// drainAttempts is how many times Drain resends updates that failed
+const drainAttempts = 3
+
+// drainRetryDelay is how long Drain waits before resending failed updates
+const drainRetryDelay = 250 * time.Millisecond
+
+// Drain stops the automatic flush and sends everything still pending, held updates
+// included, retrying failures a few times. It returns an error naming how many
+// updates were left unsent when ctx ends first or the retries run out.
+func (ub *UpdateBuffer) Drain(ctx context.Context) error {
+	ub.Stop()
+
+	var err error
+	for attempt := 0; attempt < drainAttempts; attempt++ {
+		if attempt > 0 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(drainRetryDelay):
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+		err = ub.ForceFlush(ctx)
+		if ub.GetPendingCount() == 0 {
+			return nil
+		}
+	}
+	pending := ub.GetPendingCount()
+	if ctx.Err() != nil {
+		return fmt.Errorf("%d updates unsent: %w", pending, ctx.Err())
+	}
+	if err != nil {
+		return fmt.Errorf("%d updates unsent after %d attempts: %w", pending, drainAttempts, err)
+	}
+	return fmt.Errorf("%d updates unsent after %d attempts", pending, drainAttempts)
+}
+
 // QueuePositionUpdate queues a position update
 func (ub *UpdateBuffer) QueuePositionUpdate(entityID uuid.UUID, position *models.GeomPoint) {
 	ub.mu.Lock()
@@ -106,12 +196,12 @@ func (ub *UpdateBuffer) QueuePositionUpdate(entityID uuid.UUID, position *models
 	update.Position = position
 	update.LastModified = time.Now()
 
-	// Check if we should flush
-	if len(ub.updates) >= ub.maxBatchSize {
+	// Check if we should flush; updates held by the policy don't count toward a batch
+	if len(ub.updates)-ub.held >= ub.maxBatchSize && !ub.paused() {
 		go func() {
 			ctx := context.Background()
 			if err := ub.Flush(ctx); err != nil {
-				logger.Errorf("Error auto-flushing updates: %v", err)
+				bufferLog.Errorf("Error auto-flushing updates: %v", err)
 			}
 		}()
 	}
@@ -135,6 +225,24 @@ func (ub *UpdateBuffer) QueueStatusUpdate(entityID uuid.UUID, status string) {
 	update.LastModified = time.Now()
 }
 
+// QueueAffiliationUpdate queues an affiliation change so the entity's symbology follows its classification
+func (ub *UpdateBuffer) QueueAffiliationUpdate(entityID uuid.UUID, affiliation models.Affiliation) {
+	ub.mu.Lock()
+	defer ub.mu.Unlock()
+
+	update, exists := ub.updates[entityID]
+	if !exists {
+		update = &EntityUpdate{
+			EntityID: entityID,
+			Metadata: make(map[string]interface{}),
+		}
+		ub.updates[entityID] = update
+	}
+
+	update.Affiliation = &affiliation
+	update.LastModified = time.Now()
+}
+
 // QueueMetadataUpdate queues a metadata update
 func (ub *UpdateBuffer) QueueMetadataUpdate(entityID uuid.UUID, key string, value interface{}) {
 	ub.mu.Lock()
@@ -153,8 +261,13 @@ func (ub *UpdateBuffer) QueueMetadataUpdate(entityID uuid.UUID, key string, valu
 	update.LastModified = time.Now()
 }
 
-// Flush sends all pending updates to Legion
+// Flush sends pending updates to Legion, holding back those the publish policy says
+// are not yet due
 func (ub *UpdateBuffer) Flush(ctx context.Context) error {
+	return ub.flush(ctx, false)
+}
+
+func (ub *UpdateBuffer) flush(ctx context.Context, force bool) error {
 	ub.mu.Lock()
 
 	if len(ub.updates) == 0 {
@@ -162,33 +275,77 @@ func (ub *UpdateBuffer) Flush(ctx context.Context) error {
 		return nil
 	}
 
-	// Copy updates and clear buffer
+	// Legion is failing; keep buffering until the breaker lets a call through
+	if ub.paused() {
+		ub.stats.FlushesPaused++
+		ub.mu.Unlock()
+		return nil
+	}
+
+	// Take the updates that are due and leave the rest pending
+	now := time.Now()
 	updates := make(map[uuid.UUID]*EntityUpdate)
 	for k, v := range ub.updates {
+		if !force && ub.holds(k, v, now) {
+			ub.stats.UpdatesDeferred++
+			continue
+		}
 		updates[k] = v
+		delete(ub.updates, k)
+		ub.lastSend[k] = now
 	}
-	ub.updates = make(map[uuid.UUID]*EntityUpdate)
-	ub.lastFlush = time.Now()
+	ub.held = len(ub.updates)
+	ub.lastFlush = now
 
 	ub.mu.Unlock()
 
-	// Process updates with context awareness
+	if len(updates) == 0 {
+		return nil
+	}
+
+	if ctx.Err() != nil {
+		ub.requeue(updates)
+		return ctx.Err()
+	}
+
+	// Positions, the bulk of every flush, go out first as one pipelined batch
+	failed := ub.sendPositions(ctx, updates)
+	if ctx.Err() != nil {
+		// Cancelled mid-batch; re-queue what didn't land, patches included
+		ub.mu.Lock()
+		for id, u := range updates {
+			if _, ok := failed[id]; ok || u.hasPatch() {
+				ub.updates[id] = u
+			}
+		}
+		ub.mu.Unlock()
+		return ctx.Err()
+	}
+
+	// Process patches with context awareness
 	var wg sync.WaitGroup
 	errChan := make(chan error, len(updates))
+	for id, err := range failed {
+		errChan <- err
+		// Re-queue failed update, its patch with it
+		ub.mu.Lock()
+		ub.updates[id] = updates[id]
+		ub.mu.Unlock()
+	}
 
 	// Limit concurrent API calls
 	semaphore := make(chan struct{}, 10)
 
 	for entityID, update := range updates {
+		if _, ok := failed[entityID]; ok || !update.hasPatch() {
+			continue
+		}
+
 		// Check context before starting new goroutine
 		select {
 		case <-ctx.Done():
 			// Context cancelled, re-queue all remaining updates
-			ub.mu.Lock()
-			for id, u := range updates {
-				ub.updates[id] = u
-			}
-			ub.mu.Unlock()
+			ub.requeue(updates)
 			return ctx.Err()
 		default:
 		}
@@ -209,7 +366,7 @@ func (ub *UpdateBuffer) Flush(ctx context.Context) error {
 				defer func() { <-semaphore }()
 			}
 
-			if err := ub.sendUpdate(ctx, id, u); err != nil {
+			if err := ub.sendPatch(ctx, id, u); err != nil {
 				// Only re-queue if not cancelled
 				if ctx.Err() == nil {
 					errChan <- err
@@ -239,23 +396,102 @@ func (ub *UpdateBuffer) Flush(ctx context.Context) error {
 
 	close(errChan)
 
-	// Collect errors
-	var errors []error
+	// Collect errors; updates the circuit breaker turned away weren't sent and wait for Legion
+	var errs []error
+	held := 0
 	for err := range errChan {
-		errors = append(errors, err)
+		if errors.Is(err, client.ErrCircuitOpen) {
+			held++
+			continue
+		}
+		errs = append(errs, err)
 	}
 
-	if len(errors) > 0 {
-		logger.Errorf("Failed to send %d/%d updates", len(errors), len(updates))
-		return errors[0] // Return first error
+	ub.recordBatch(len(updates)-held, errs)
+
+	if len(errs) > 0 {
+		bufferLog.Errorf("Failed to send %d/%d updates", len(errs), len(updates))
+		return errs[0] // Return first error
 	}
 
-	logger.Infof("Successfully flushed %d updates", len(updates))
+	bufferLog.Debugf("Successfully flushed %d updates", len(updates))
 	return nil
 }
 
-// sendUpdate sends a single update to Legion
-func (ub *UpdateBuffer) sendUpdate(ctx context.Context, entityID uuid.UUID, update *EntityUpdate) error {
+// requeue puts updates taken for a flush back in the queue
+func (ub *UpdateBuffer) requeue(updates map[uuid.UUID]*EntityUpdate) {
+	ub.mu.Lock()
+	defer ub.mu.Unlock()
+	for id, u := range updates {
+		ub.updates[id] = u
+	}
+}
+
+// hasPatch reports whether an update changes anything besides the entity's position
+func (u *EntityUpdate) hasPatch() bool {
+	return u.Status != nil || u.Affiliation != nil || len(u.Metadata) > 0
+}
+
+// sendPositions writes the position of every update that has one. Legion takes one
+// location per call, so they go through the client's pipelined batch rather than one
+// flush goroutine each. It returns the error for each entity whose position failed;
+// positions shed by the API budget are counted but not failures, since the next one
+// catches up.
+func (ub *UpdateBuffer) sendPositions(ctx context.Context, updates map[uuid.UUID]*EntityUpdate) map[uuid.UUID]error {
+	ub.mu.Lock()
+	timing := ub.timing
+	ub.mu.Unlock()
+
+	var ids []uuid.UUID
+	var batch []client.EntityLocation
+	for id, update := range updates {
+		if update.Position == nil {
+			continue
+		}
+		recordedAt := timing.Stamp(id, time.Now())
+		ids = append(ids, id)
+		batch = append(batch, client.EntityLocation{
+			EntityID: id.String(),
+			Location: &models.CreateEntityLocationRequest{
+				Position:   update.Position,
+				Source:     LocationSource,
+				RecordedAt: &recordedAt,
+			},
+		})
+	}
+	if len(batch) == 0 {
+		return nil
+	}
+
+	_, errs := ub.client.CreateEntityLocations(client.WithOrgID(ctx, ub.orgID), batch, 0)
+	failed := make(map[uuid.UUID]error)
+	for i, err := range errs {
+		id, position, recordedAt := ids[i], updates[ids[i]].Position, *batch[i].Location.RecordedAt
+		switch {
+		case errors.Is(err, client.ErrBudgetExceeded):
+			// Shedding positions is how the budget decimates updates; the next one catches up
+			ub.recordShed()
+		case err != nil:
+			failed[id] = err
+		default:
+			ub.recordSent(id, func(r *SentRecord) {
+				if r.Positions == 0 {
+					r.FirstSentAt = recordedAt
+				}
+				r.Positions++
+				r.LastPosition = &models.GeomPoint{
+					Type:        position.Type,
+					Coordinates: append([]float64(nil), position.Coordinates...),
+				}
+				r.LastRecordedAt = recordedAt
+			})
+		}
+	}
+	return failed
+}
+
+// sendPatch sends an update's status, affiliation and metadata changes to Legion
+func (ub *UpdateBuffer) sendPatch(ctx context.Context, entityID uuid.UUID, update *EntityUpdate) error {
 	// Check context before sending
 	select {
 	case <-ctx.Done():
@@ -263,74 +499,130 @@ func (ub *UpdateBuffer) sendUpdate(ctx context.Context, entityID uuid.UUID, upda
 	default:
 	}
 
-	// Update position if changed
-	if update.Position != nil {
-		recordedAt := time.Now()
-		req := &models.CreateEntityLocationRequest{
-			Position:   update.Position,
-			Source:     "Drone-Swarm-Simulation",
-			RecordedAt: &recordedAt,
-		}
+	// Only the changed fields are sent so concurrent updates don't clobber each other
+	req := &models.EntityPatch{
+		Status:      update.Status,
+		Affiliation: update.Affiliation,
+	}
 
-		orgCtx := client.WithOrgID(ctx, ub.orgID)
-		if _, err := ub.client.CreateEntityLocation(orgCtx, entityID.String(), req); err != nil {
-			// Check if error is due to context cancellation
-			if ctx.Err() != nil {
-				return ctx.Err()
-			}
-			return err
+	// Add metadata if changed
+	if len(update.Metadata) > 0 {
+		// Convert metadata to JSON
+		metadataJSON, err := json.Marshal(update.Metadata)
+		if err != nil {
+			return fmt.Errorf("failed to marshal metadata: %w", err)
 		}
+		rawMessage := json.RawMessage(metadataJSON)
+		req.Metadata = &rawMessage
 	}
 
-	// Update status and/or metadata if changed
-	if update.Status != nil || len(update.Metadata) > 0 {
-		req := &models.UpdateEntityRequest{
-			ID: entityID,
+	orgCtx := client.WithOrgID(ctx, ub.orgID)
+	_, err := ub.client.PatchEntity(orgCtx, entityID.String(), req)
+	if errors.Is(err, client.ErrBudgetExceeded) {
+		ub.recordShed() // Metadata-only patches; status changes are never shed
+		return nil
+	}
+	if err != nil {
+		// Check if error is due to context cancellation
+		if ctx.Err() != nil {
+			return ctx.Err()
 		}
+		return fmt.Errorf("failed to update entity: %w", err)
+	}
+	if update.Status != nil {
+		status := *update.Status
+		ub.recordSent(entityID, func(r *SentRecord) { r.LastStatus = status })
+	}
+	return nil
+}
 
-		// Add status if changed
-		if update.Status != nil {
-			req.Status = *update.Status
-		}
+// paused reports whether the client's circuit breaker is open, so a flush would only
+// fail. Once it is half-open the next flush goes out and its first call is the probe.
+func (ub *UpdateBuffer) paused() bool {
+	if ub.client == nil || ub.client.CircuitBreaker() == nil {
+		return false
+	}
+	return ub.client.CircuitBreaker().State() == client.CircuitOpen
+}
 
-		// Add metadata if changed
-		if len(update.Metadata) > 0 {
-			// Convert metadata to JSON
-			metadataJSON, err := json.Marshal(update.Metadata)
-			if err != nil {
-				return fmt.Errorf("failed to marshal metadata: %w", err)
-			}
-			rawMessage := json.RawMessage(metadataJSON)
-			req.Metadata = &rawMessage
-		}
+// holds reports whether the policy keeps an entity's update back at now. Callers hold ub.mu.
+func (ub *UpdateBuffer) holds(entityID uuid.UUID, update *EntityUpdate, now time.Time) bool {
+	if ub.policy == nil || update.Status != nil || update.Affiliation != nil {
+		return false
+	}
+	last, sent := ub.lastSend[entityID]
+	return sent && now.Sub(last) < ub.policy.Interval(entityID)
+}
 
-		orgCtx := client.WithOrgID(ctx, ub.orgID)
-		if _, err := ub.client.UpdateEntity(orgCtx, entityID.String(), req); err != nil {
-			// Check if error is due to context cancellation
-			if ctx.Err() != nil {
-				return ctx.Err()
-			}
-			return fmt.Errorf("failed to update entity: %w", err)
-		}
+// recordShed counts a write the API budget dropped
+func (ub *UpdateBuffer) recordShed() {
+	ub.mu.Lock()
+	defer ub.mu.Unlock()
+	ub.stats.UpdatesShed++
+}
+
+// recordSent applies a successful write to the entity's sent record
+func (ub *UpdateBuffer) recordSent(entityID uuid.UUID, apply func(*SentRecord)) {
+	ub.mu.Lock()
+	defer ub.mu.Unlock()
+
+	record, exists := ub.sent[entityID]
+	if !exists {
+		record = &SentRecord{}
+		ub.sent[entityID] = record
 	}
+	apply(record)
+}
 
-	return nil
+// Sent returns what has been written to Legion for an entity
+func (ub *UpdateBuffer) Sent(entityID uuid.UUID) (SentRecord, bool) {
+	ub.mu.Lock()
+	defer ub.mu.Unlock()
+
+	record, exists := ub.sent[entityID]
+	if !exists {
+		return SentRecord{}, false
+	}
+	return *record, true
 }
 
-// GetStats returns current buffer statistics
-func (ub *UpdateBuffer) GetStats() UpdateStats {
+// recordBatch accumulates the outcome of one flush into the running statistics
+func (ub *UpdateBuffer) recordBatch(size int, errors []error) {
 	ub.mu.Lock()
 	defer ub.mu.Unlock()
 
-	return UpdateStats{
-		TotalUpdates:  int64(len(ub.updates)),
-		LastBatchTime: ub.lastFlush,
+	ub.stats.BatchesSent++
+	ub.stats.UpdatesSent += int64(size - len(errors))
+	ub.stats.UpdatesFailed += int64(len(errors))
+	ub.stats.AverageBatchSize += (float64(size) - ub.stats.AverageBatchSize) / float64(ub.stats.BatchesSent)
+	if len(errors) > 0 {
+		ub.stats.LastError = errors[0]
 	}
 }
 
-// ForceFlush immediately flushes all pending updates
+// GetStats returns current buffer statistics; TotalUpdates is the number still pending
+func (ub *UpdateBuffer) GetStats() UpdateStats {
+	ub.mu.Lock()
+	defer ub.mu.Unlock()
+
+	stats := ub.stats
+	stats.TotalUpdates = int64(len(ub.updates))
+	stats.LastBatchTime = ub.lastFlush
+	return stats
+}
+
+// ForceFlush immediately flushes all pending updates, including those the publish
+// policy is holding back
 func (ub *UpdateBuffer) ForceFlush(ctx context.Context) error {
-	return ub.Flush(ctx)
+	return ub.flush(ctx, true)
+}
+
+// Discard drops any pending updates for an entity, e.g. before it is deleted
+func (ub *UpdateBuffer) Discard(entityID uuid.UUID) {
+	ub.mu.Lock()
+	defer ub.mu.Unlock()
+	delete(ub.updates, entityID)
+	delete(ub.lastSend, entityID)
 }
 
 // GetPendingCount returns the number of pending updates
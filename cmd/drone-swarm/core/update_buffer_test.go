@@ -0,0 +1,134 @@
+package core
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// fixedPolicy paces every entity at one interval
+type fixedPolicy time.Duration
+
+func (p fixedPolicy) Interval(uuid.UUID) time.Duration { return time.Duration(p) }
+
+func TestUpdateBufferHolds(t *testing.T) {
+	ub := NewUpdateBuffer(nil, "", 50, time.Second)
+	id := uuid.New()
+	now := time.Now()
+	position := &EntityUpdate{EntityID: id, Metadata: map[string]interface{}{}}
+
+	if ub.holds(id, position, now) {
+		t.Error("without a policy nothing should be held")
+	}
+
+	ub.SetPolicy(fixedPolicy(2 * time.Second))
+	if ub.holds(id, position, now) {
+		t.Error("an entity never sent should go at once")
+	}
+
+	ub.lastSend[id] = now.Add(-time.Second)
+	if !ub.holds(id, position, now) {
+		t.Error("an update inside the interval should be held")
+	}
+	status := "DESTROYED"
+	if ub.holds(id, &EntityUpdate{EntityID: id, Status: &status}, now) {
+		t.Error("status changes should never be held")
+	}
+
+	ub.lastSend[id] = now.Add(-2 * time.Second)
+	if ub.holds(id, position, now) {
+		t.Error("an update due by its interval should go")
+	}
+}
+
+func TestUpdateBufferDrain(t *testing.T) {
+	ub := NewUpdateBuffer(nil, "", 50, time.Second)
+	ub.Start(context.Background())
+	if err := ub.Drain(context.Background()); err != nil {
+		t.Fatalf("draining an empty buffer: %v", err)
+	}
+	ub.Stop() // Already stopped by Drain
+
+	ub = NewUpdateBuffer(nil, "", 50, time.Second)
+	ub.QueueMetadataUpdate(uuid.New(), "status", "DESTROYED")
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	err := ub.Drain(ctx)
+	if !errors.Is(err, context.Canceled) || !strings.Contains(err.Error(), "1 updates unsent") {
+		t.Errorf("expected the pending update to be reported unsent, got %v", err)
+	}
+	if ub.GetPendingCount() != 1 {
+		t.Errorf("expected the unsent update to stay queued, got %d pending", ub.GetPendingCount())
+	}
+}
+
+func TestUpdateBufferBatchesPositions(t *testing.T) {
+	legion, memory := client.NewMemoryClient()
+	orgID := uuid.New()
+	ctx := client.WithOrgID(context.Background(), orgID.String())
+	name, category, entityType, status := "Interceptor 1", models.CategoryDEVICE, "Interceptor", "ACTIVE"
+	entity, err := legion.CreateEntity(ctx, &models.CreateEntityRequest{
+		OrganizationID: &orgID, Name: &name, Category: &category, Type: &entityType, Status: &status,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ub := NewUpdateBuffer(legion, orgID.String(), 50, time.Second)
+	pointType := "Point"
+	point := &models.GeomPoint{Type: &pointType, Coordinates: []float64{1, 2, 3}}
+	missing := uuid.New()
+	ub.QueuePositionUpdate(entity.ID, point)
+	ub.QueueStatusUpdate(entity.ID, "ENGAGING")
+	ub.QueuePositionUpdate(missing, point)
+	if err := ub.ForceFlush(context.Background()); err == nil {
+		t.Error("expected the unknown entity's position to fail")
+	}
+
+	sent, _ := ub.Sent(entity.ID)
+	if sent.Positions != 1 || sent.LastStatus != "ENGAGING" {
+		t.Errorf("expected the position and status sent, got %+v", sent)
+	}
+	if ub.GetPendingCount() != 1 || ub.GetStats().UpdatesFailed != 1 {
+		t.Errorf("expected only the failed update re-queued, got %d pending", ub.GetPendingCount())
+	}
+	if got := memory.Summary().Locations; got != 1 {
+		t.Errorf("expected 1 location stored, got %d", got)
+	}
+}
+
+func TestUpdateBufferPausesWhileBreakerOpen(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer server.Close()
+
+	legion, err := client.NewClient(client.Config{BaseURL: server.URL, Retry: &client.RetryPolicy{MaxAttempts: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	legion.SetCircuitBreaker(client.NewCircuitBreaker(1, time.Minute))
+	if _, err := legion.GetMe(context.Background()); err == nil {
+		t.Fatal("expected the server's error")
+	}
+
+	ub := NewUpdateBuffer(legion, uuid.NewString(), 50, time.Second)
+	ub.QueueStatusUpdate(uuid.New(), "ENGAGING")
+	if err := ub.Flush(context.Background()); err != nil {
+		t.Fatalf("expected a paused flush to succeed, got %v", err)
+	}
+	if calls != 1 || ub.GetPendingCount() != 1 || ub.GetStats().FlushesPaused != 1 {
+		t.Errorf("expected the update kept locally without calling Legion: %d calls, %d pending", calls, ub.GetPendingCount())
+	}
+}
@@ -0,0 +1,229 @@
+// Package coverage rasterizes combined sensor and weapon coverage around a defended site.
+//
+// Coverage is computed per altitude band on a local grid centered on the site. A cell is
+// covered by a system when the target altitude is within range and has line of sight to
+// the system over the terrain, using the 4/3 effective earth radius for refraction.
+package coverage
+
+import (
+	"math"
+)
+
+// Kilometers per degree used by the local equirectangular grid
+const (
+	kmPerDegLat = 110.574
+	kmPerDegLon = 111.320 // At the equator; scaled by cos(latitude)
+
+	effectiveEarthRadiusKm = 6371.0 * 4 / 3 // Standard refraction for radar line of sight
+	losSamples             = 32             // Terrain samples along each sight line
+)
+
+// Band is a target altitude above ground level to evaluate coverage at
+type Band struct {
+	Name      string
+	AltitudeM float64
+}
+
+// DefaultBands cover low-flying FPV, typical small UAS and higher loitering munitions
+var DefaultBands = []Band{
+	{Name: "low", AltitudeM: 30},
+	{Name: "medium", AltitudeM: 120},
+	{Name: "high", AltitudeM: 400},
+}
+
+// Terrain returns ground elevation in meters above the reference altitude
+type Terrain interface {
+	ElevationM(lat, lon float64) float64
+}
+
+// FlatTerrain is level ground at a fixed elevation. Only earth curvature masks coverage.
+type FlatTerrain float64
+
+// ElevationM implements Terrain
+func (f FlatTerrain) ElevationM(_, _ float64) float64 { return float64(f) }
+
+// GridTerrain is elevation sampled on a regular lat/lon grid, e.g. from a data pack.
+// Points between samples are interpolated; points off the grid read as Outside.
+type GridTerrain struct {
+	South, West float64     // Corner of the first sample
+	CellDeg     float64     // Sample spacing in degrees
+	Elevations  [][]float64 // Rows from south to north, columns from west to east
+	Outside     float64
+}
+
+// ElevationM implements Terrain
+func (g GridTerrain) ElevationM(lat, lon float64) float64 {
+	rows := len(g.Elevations)
+	if rows < 2 || len(g.Elevations[0]) < 2 || g.CellDeg <= 0 {
+		return g.Outside
+	}
+	cols := len(g.Elevations[0])
+	y := (lat - g.South) / g.CellDeg
+	x := (lon - g.West) / g.CellDeg
+	if y < 0 || x < 0 || y > float64(rows-1) || x > float64(cols-1) {
+		return g.Outside
+	}
+
+	r, c := math.Min(math.Floor(y), float64(rows-2)), math.Min(math.Floor(x), float64(cols-2))
+	fy, fx := y-r, x-c
+	row, col := int(r), int(c)
+	south := g.Elevations[row][col]*(1-fx) + g.Elevations[row][col+1]*fx
+	north := g.Elevations[row+1][col]*(1-fx) + g.Elevations[row+1][col+1]*fx
+	return south*(1-fy) + north*fy
+}
+
+// System is a sensor and weapon site
+type System struct {
+	Name          string
+	Lat           float64
+	Lon           float64
+	HeightM       float64 // Sensor height above ground
+	SensorRangeKm float64 // Longest detection range
+	WeaponRangeKm float64 // Effective engagement range
+}
+
+// Grid is a square raster centered on a point
+type Grid struct {
+	CenterLat   float64
+	CenterLon   float64
+	HalfWidthKm float64
+	Size        int // Cells per side
+}
+
+// CellKm returns the width of one cell
+func (g Grid) CellKm() float64 {
+	return 2 * g.HalfWidthKm / float64(g.Size)
+}
+
+// CellCenter returns the position of a cell center; row 0 is the northern edge
+func (g Grid) CellCenter(row, col int) (lat, lon float64) {
+	cell := g.CellKm()
+	x := -g.HalfWidthKm + (float64(col)+0.5)*cell
+	y := g.HalfWidthKm - (float64(row)+0.5)*cell
+	return g.offsetToLatLon(x, y)
+}
+
+// Cell returns the cell containing a position, or false if it is outside the grid
+func (g Grid) Cell(lat, lon float64) (row, col int, ok bool) {
+	x, y := g.latLonToOffset(lat, lon)
+	col = int(math.Floor((x + g.HalfWidthKm) / g.CellKm()))
+	row = int(math.Floor((g.HalfWidthKm - y) / g.CellKm()))
+	ok = row >= 0 && row < g.Size && col >= 0 && col < g.Size
+	return row, col, ok
+}
+
+func (g Grid) latLonToOffset(lat, lon float64) (x, y float64) {
+	x = (lon - g.CenterLon) * kmPerDegLon * math.Cos(g.CenterLat*math.Pi/180)
+	y = (lat - g.CenterLat) * kmPerDegLat
+	return x, y
+}
+
+func (g Grid) offsetToLatLon(x, y float64) (lat, lon float64) {
+	lat = g.CenterLat + y/kmPerDegLat
+	lon = g.CenterLon + x/(kmPerDegLon*math.Cos(g.CenterLat*math.Pi/180))
+	return lat, lon
+}
+
+// Raster holds per-cell counts of the systems that see and can engage a target in one band
+type Raster struct {
+	Grid    Grid
+	Band    Band
+	Sensors []uint8 // Row-major count of systems with detection coverage
+	Weapons []uint8 // Row-major count of systems with weapon coverage
+}
+
+// Analyze computes coverage of a grid at one altitude band
+func Analyze(grid Grid, systems []System, band Band, terrain Terrain) *Raster {
+	if terrain == nil {
+		terrain = FlatTerrain(0)
+	}
+
+	r := &Raster{
+		Grid:    grid,
+		Band:    band,
+		Sensors: make([]uint8, grid.Size*grid.Size),
+		Weapons: make([]uint8, grid.Size*grid.Size),
+	}
+
+	for _, sys := range systems {
+		sysAlt := terrain.ElevationM(sys.Lat, sys.Lon) + sys.HeightM
+		sx, sy := grid.latLonToOffset(sys.Lat, sys.Lon)
+		maxRange := math.Max(sys.SensorRangeKm, sys.WeaponRangeKm)
+
+		for row := 0; row < grid.Size; row++ {
+			for col := 0; col < grid.Size; col++ {
+				lat, lon := grid.CellCenter(row, col)
+				tx, ty := grid.latLonToOffset(lat, lon)
+				ground := math.Hypot(tx-sx, ty-sy)
+				if ground > maxRange {
+					continue
+				}
+
+				targetAlt := terrain.ElevationM(lat, lon) + band.AltitudeM
+				slant := math.Hypot(ground, (targetAlt-sysAlt)/1000)
+				if !lineOfSight(grid, terrain, sx, sy, sysAlt, tx, ty, targetAlt) {
+					continue
+				}
+
+				idx := row*grid.Size + col
+				if slant <= sys.SensorRangeKm && r.Sensors[idx] < math.MaxUint8 {
+					r.Sensors[idx]++
+				}
+				if slant <= sys.WeaponRangeKm && r.Weapons[idx] < math.MaxUint8 {
+					r.Weapons[idx]++
+				}
+			}
+		}
+	}
+	return r
+}
+
+// lineOfSight checks the straight line between two points clears the terrain, allowing
+// for the curvature drop of the effective earth along the path
+func lineOfSight(grid Grid, terrain Terrain, x1, y1, alt1, x2, y2, alt2 float64) bool {
+	distance := math.Hypot(x2-x1, y2-y1)
+	if distance == 0 {
+		return true
+	}
+
+	for i := 1; i < losSamples; i++ {
+		t := float64(i) / losSamples
+		d1, d2 := t*distance, (1-t)*distance
+
+		// Height of the sight line above the curved surface at this point, in meters
+		lineAlt := alt1 + (alt2-alt1)*t - d1*d2/(2*effectiveEarthRadiusKm)*1000
+
+		lat, lon := grid.offsetToLatLon(x1+(x2-x1)*t, y1+(y2-y1)*t)
+		if lineAlt < terrain.ElevationM(lat, lon) {
+			return false
+		}
+	}
+	return true
+}
+
+// Share returns the fraction of cells inside radiusKm of the center with at least one
+// sensor and one weapon covering them
+func (r *Raster) Share(radiusKm float64) (sensor, weapon float64) {
+	var total, sensed, armed int
+	for row := 0; row < r.Grid.Size; row++ {
+		for col := 0; col < r.Grid.Size; col++ {
+			lat, lon := r.Grid.CellCenter(row, col)
+			x, y := r.Grid.latLonToOffset(lat, lon)
+			if math.Hypot(x, y) > radiusKm {
+				continue
+			}
+			total++
+			idx := row*r.Grid.Size + col
+			if r.Sensors[idx] > 0 {
+				sensed++
+			}
+			if r.Weapons[idx] > 0 {
+				armed++
+			}
+		}
+	}
+	if total == 0 {
+		return 0, 0
+	}
+	return float64(sensed) / float64(total), float64(armed) / float64(total)
+}
@@ -0,0 +1,68 @@
+package coverage
+
+import (
+	"math"
+	"testing"
+)
+
+// ridge is flat ground with a 500 m wall running north-south 3 km east of the center
+type ridge struct{ grid Grid }
+
+func (r ridge) ElevationM(lat, lon float64) float64 {
+	x, _ := r.grid.latLonToOffset(lat, lon)
+	if math.Abs(x-3) < 0.2 {
+		return 500
+	}
+	return 0
+}
+
+func TestAnalyzeRangeAndMasking(t *testing.T) {
+	grid := Grid{CenterLat: 40, CenterLon: -76, HalfWidthKm: 10, Size: 100}
+	systems := []System{{Name: "S1", Lat: 40, Lon: -76, HeightM: 10, SensorRangeKm: 8, WeaponRangeKm: 2}}
+	band := Band{Name: "low", AltitudeM: 30}
+
+	flat := Analyze(grid, systems, band, FlatTerrain(0))
+	covered := func(r *Raster, xKm, yKm float64) (sensor, weapon bool) {
+		lat, lon := grid.offsetToLatLon(xKm, yKm)
+		row, col, ok := grid.Cell(lat, lon)
+		if !ok {
+			t.Fatalf("point %.1f,%.1f outside grid", xKm, yKm)
+		}
+		idx := row*grid.Size + col
+		return r.Sensors[idx] > 0, r.Weapons[idx] > 0
+	}
+
+	if s, w := covered(flat, 1, 0); !s || !w {
+		t.Error("expected sensor and weapon coverage 1 km out")
+	}
+	if s, w := covered(flat, 5, 0); !s || w {
+		t.Error("expected sensor-only coverage 5 km out")
+	}
+	if s, _ := covered(flat, 9, 0); s {
+		t.Error("expected no coverage beyond sensor range")
+	}
+
+	masked := Analyze(grid, systems, band, ridge{grid})
+	if s, _ := covered(masked, 5, 0); s {
+		t.Error("expected the ridge to mask coverage behind it")
+	}
+	if s, _ := covered(masked, -5, 0); !s {
+		t.Error("expected coverage on the open side")
+	}
+
+	sensor, weapon := flat.Share(2)
+	if sensor != 1 || weapon < 0.9 {
+		t.Errorf("expected full coverage inside 2 km, got sensor %.2f weapon %.2f", sensor, weapon)
+	}
+}
+
+func TestGridCellRoundTrip(t *testing.T) {
+	grid := Grid{CenterLat: 51.5, CenterLon: -0.1, HalfWidthKm: 20, Size: 256}
+	for _, rc := range [][2]int{{0, 0}, {17, 200}, {255, 255}} {
+		lat, lon := grid.CellCenter(rc[0], rc[1])
+		row, col, ok := grid.Cell(lat, lon)
+		if !ok || row != rc[0] || col != rc[1] {
+			t.Errorf("cell %v round-tripped to %d,%d (ok=%v)", rc, row, col, ok)
+		}
+	}
+}
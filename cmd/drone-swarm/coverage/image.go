@@ -0,0 +1,106 @@
+package coverage
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"image/png"
+	"os"
+	"strings"
+)
+
+// Point kinds overlaid on post-run maps
+const (
+	PointDetection  = "detection"
+	PointEngagement = "engagement"
+	PointLeaker     = "leaker"
+)
+
+// Point is an observed event location
+type Point struct {
+	Lat  float64
+	Lon  float64
+	Kind string
+}
+
+// Map colors
+var (
+	colorNone      = color.NRGBA{R: 255, G: 255, B: 255, A: 255}
+	colorSensor    = []color.NRGBA{{R: 198, G: 219, B: 239, A: 255}, {R: 107, G: 174, B: 214, A: 255}, {R: 33, G: 113, B: 181, A: 255}} // 1, 2, 3+ sensors
+	colorWeapon    = color.NRGBA{R: 253, G: 174, B: 107, A: 255}
+	colorWeaponMul = color.NRGBA{R: 230, G: 85, B: 13, A: 255} // Two or more weapons
+	colorPoints    = map[string]color.NRGBA{
+		PointDetection:  {R: 0, G: 109, B: 44, A: 255},
+		PointEngagement: {R: 165, G: 15, B: 21, A: 255},
+		PointLeaker:     {R: 0, G: 0, B: 0, A: 255},
+	}
+)
+
+// Image renders the raster: blue shades for sensor depth, orange for weapon coverage,
+// with any event points drawn on top
+func (r *Raster) Image(points []Point) *image.NRGBA {
+	size := r.Grid.Size
+	img := image.NewNRGBA(image.Rect(0, 0, size, size))
+
+	for row := 0; row < size; row++ {
+		for col := 0; col < size; col++ {
+			idx := row*size + col
+			c := colorNone
+			switch {
+			case r.Weapons[idx] >= 2:
+				c = colorWeaponMul
+			case r.Weapons[idx] == 1:
+				c = colorWeapon
+			case r.Sensors[idx] > 0:
+				c = colorSensor[min(int(r.Sensors[idx]), len(colorSensor))-1]
+			}
+			img.SetNRGBA(col, row, c)
+		}
+	}
+
+	for _, p := range points {
+		row, col, ok := r.Grid.Cell(p.Lat, p.Lon)
+		if !ok {
+			continue
+		}
+		c, known := colorPoints[p.Kind]
+		if !known {
+			c = colorPoints[PointDetection]
+		}
+		// 3x3 marker so single events stay visible
+		for dy := -1; dy <= 1; dy++ {
+			for dx := -1; dx <= 1; dx++ {
+				if image.Pt(col+dx, row+dy).In(img.Bounds()) {
+					img.SetNRGBA(col+dx, row+dy, c)
+				}
+			}
+		}
+	}
+	return img
+}
+
+// WritePNG writes the rendered raster to path and an ESRI world file (.pgw) beside it so
+// GIS tools can georeference it in WGS84 degrees
+func (r *Raster) WritePNG(path string, points []Point) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	if err := png.Encode(f, r.Image(points)); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to encode %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+
+	return os.WriteFile(strings.TrimSuffix(path, ".png")+".pgw", []byte(r.worldFile()), 0644)
+}
+
+// worldFile returns the six-line affine transform for the upper-left pixel center
+func (r *Raster) worldFile() string {
+	g := r.Grid
+	lat0, lon0 := g.CellCenter(0, 0)
+	lat1, lon1 := g.CellCenter(1, 1)
+	return fmt.Sprintf("%.10f\n0.0\n0.0\n%.10f\n%.10f\n%.10f\n", lon1-lon0, lat1-lat0, lon0, lat0)
+}
@@ -0,0 +1,134 @@
+package metadata
+
+import (
+	"math"
+	"time"
+)
+
+// CounterUASBuilder assembles CounterUAS metadata. Build stamps the schema version and
+// derives the UI circles, so callers only describe the system.
+type CounterUASBuilder struct {
+	m CounterUAS
+}
+
+// NewCounterUAS starts the metadata for a system
+func NewCounterUAS(callsign, iffCode, affiliation string) *CounterUASBuilder {
+	return &CounterUASBuilder{m: CounterUAS{Callsign: callsign, IFFCode: iffCode, Affiliation: affiliation}}
+}
+
+// Sensors sets the radar, EO/IR and RF detection ranges in km and the active sensor mode
+func (b *CounterUASBuilder) Sensors(radarKm, eoirKm, rfKm float64, mode string) *CounterUASBuilder {
+	b.m.RadarRangeKm, b.m.EOIRRangeKm, b.m.RFDetectionRangeKm, b.m.SensorMode = radarKm, eoirKm, rfKm, mode
+	return b
+}
+
+// Weapon sets the effector type, its range in km, kill probability and ticks of cooldown left
+func (b *CounterUASBuilder) Weapon(engagementType string, rangeKm, successRate float64, cooldownRemaining int) *CounterUASBuilder {
+	b.m.EngagementType, b.m.EffectiveRangeKm = engagementType, rangeKm
+	b.m.SuccessRate, b.m.CooldownRemaining = successRate, cooldownRemaining
+	return b
+}
+
+// Ammo sets a kinetic system's magazine; leave it unset for systems without one
+func (b *CounterUASBuilder) Ammo(capacity, remaining, reloadTimeSec int) *CounterUASBuilder {
+	b.m.AmmoCapacity, b.m.AmmoRemaining, b.m.ReloadTimeSec = &capacity, &remaining, &reloadTimeSec
+	return b
+}
+
+// Health sets system health (0-1), power level (percent), temperature (Celsius),
+// engagement stress (0-1) and datalink status
+func (b *CounterUASBuilder) Health(health, power, temperature, stress float64, datalink string) *CounterUASBuilder {
+	b.m.SystemHealth, b.m.PowerLevel, b.m.Temperature = health, power, temperature
+	b.m.EngagementStress, b.m.DatalinkStatus = stress, datalink
+	return b
+}
+
+// Combat sets engagement counts and how many tracks the system is following
+func (b *CounterUASBuilder) Combat(total, successful, tracking int) *CounterUASBuilder {
+	b.m.TotalEngagements, b.m.SuccessfulEngagements, b.m.TrackingTargets = total, successful, tracking
+	return b
+}
+
+// Engaging sets the entity ID of the track being engaged
+func (b *CounterUASBuilder) Engaging(targetID string) *CounterUASBuilder {
+	b.m.EngagedTarget = targetID
+	return b
+}
+
+// Build returns the metadata
+func (b *CounterUASBuilder) Build() CounterUAS {
+	m := b.m
+	m.SchemaVersion = SchemaVersion
+	m.DetectionRadiusKm = m.RadarRangeKm
+	m.EngagementRadiusKm = m.EffectiveRangeKm
+	m.MaxSensorRangeKm = math.Max(math.Max(m.RadarRangeKm, m.EOIRRangeKm), m.RFDetectionRangeKm)
+	return m
+}
+
+// UASThreatBuilder assembles UASThreat metadata
+type UASThreatBuilder struct {
+	m UASThreat
+}
+
+// NewUASThreat starts the metadata for a track
+func NewUASThreat(trackNumber, classification, affiliation string) *UASThreatBuilder {
+	return &UASThreatBuilder{m: UASThreat{TrackNumber: trackNumber, Classification: classification, Affiliation: affiliation}}
+}
+
+// Track sets track quality (0-1) and when the track was last seen
+func (b *UASThreatBuilder) Track(quality float64, lastSeen time.Time) *UASThreatBuilder {
+	b.m.TrackQuality, b.m.LastSeen = quality, lastSeen.Format(time.RFC3339)
+	return b
+}
+
+// Characteristics sets the size class, radar cross section (m²), observed behavior and
+// threat level (1-5)
+func (b *UASThreatBuilder) Characteristics(sizeClass string, rcs float64, behavior string, threatLevel int) *UASThreatBuilder {
+	b.m.SizeClass, b.m.RadarCrossSection, b.m.ObservedBehavior, b.m.ThreatLevel = sizeClass, rcs, behavior, threatLevel
+	return b
+}
+
+// Kinematics sets the estimated speed (kph), heading (degrees) and altitude (meters)
+func (b *UASThreatBuilder) Kinematics(speedKph, heading, altitude float64) *UASThreatBuilder {
+	b.m.EstimatedSpeedKph, b.m.EstimatedHeading, b.m.EstimatedAltitude = speedKph, heading, altitude
+	return b
+}
+
+// Signatures sets which sensors hold the track
+func (b *UASThreatBuilder) Signatures(rf, thermal, acoustic bool) *UASThreatBuilder {
+	b.m.RFEmitting, b.m.ThermalSignature, b.m.AcousticSignature = rf, thermal, acoustic
+	return b
+}
+
+// RFFrequency sets the measured emitter frequency in MHz
+func (b *UASThreatBuilder) RFFrequency(mhz float64) *UASThreatBuilder {
+	b.m.RFFrequencyMHz = &mhz
+	return b
+}
+
+// Engagements sets how often the track has been targeted and whether it resisted jamming
+func (b *UASThreatBuilder) Engagements(targeted, jamming, kinetic int, jamResistant bool) *UASThreatBuilder {
+	b.m.TimesTargeted, b.m.JammingAttempts, b.m.KineticAttempts, b.m.ShowsJamResistance = targeted, jamming, kinetic, jamResistant
+	return b
+}
+
+// Swarm sets the swarm the track flies with
+func (b *UASThreatBuilder) Swarm(id string) *UASThreatBuilder {
+	b.m.SwarmID = id
+	return b
+}
+
+// Intent sets the predicted target, time to impact and confidence (0-1), with the ranked
+// candidates
+func (b *UASThreatBuilder) Intent(target string, timeToImpact time.Duration, confidence float64, likely []LikelyTarget) *UASThreatBuilder {
+	seconds := timeToImpact.Seconds()
+	b.m.PredictedTarget, b.m.TimeToImpactS, b.m.IntentConfidence, b.m.LikelyTargets = target, &seconds, &confidence, likely
+	return b
+}
+
+// Build returns the metadata
+func (b *UASThreatBuilder) Build() UASThreat {
+	m := b.m
+	m.SchemaVersion = SchemaVersion
+	return m
+}
@@ -0,0 +1,106 @@
+// Package metadata defines the JSON metadata the drone swarm simulation attaches to its
+// Legion entities. The UI and downstream consumers key off these documents, so their
+// shape is declared once here instead of being assembled from string literals.
+//
+// Every document carries SchemaVersion. Adding a field is backwards compatible. Renaming
+// or removing one, or changing its type or units, is not: bump SchemaVersion so
+// consumers can tell the shapes apart, and update the golden keys in metadata_test.go.
+package metadata
+
+// SchemaVersion is the version of the documents in this package
+const SchemaVersion = 1
+
+// CounterUAS is the metadata of a BLUE FORCE Counter-UAS system
+type CounterUAS struct {
+	SchemaVersion int `json:"schema_version"`
+
+	// Identity
+	Callsign    string `json:"callsign"`
+	IFFCode     string `json:"iff_code"`
+	Affiliation string `json:"affiliation"`
+
+	// Sensors
+	RadarRangeKm       float64 `json:"radar_range_km"`
+	EOIRRangeKm        float64 `json:"eoir_range_km"`
+	RFDetectionRangeKm float64 `json:"rf_detection_range_km"`
+	SensorMode         string  `json:"sensor_mode"`
+
+	// Weapons
+	EngagementType    string  `json:"engagement_type"`
+	EffectiveRangeKm  float64 `json:"effective_range_km"`
+	SuccessRate       float64 `json:"success_rate"`
+	CooldownRemaining int     `json:"cooldown_remaining"`
+	AmmoCapacity      *int    `json:"ammo_capacity,omitempty"` // Kinetic systems only
+	AmmoRemaining     *int    `json:"ammo_remaining,omitempty"`
+	ReloadTimeSec     *int    `json:"reload_time_sec,omitempty"`
+
+	// System status
+	SystemHealth     float64 `json:"system_health"` // 0.0-1.0
+	PowerLevel       float64 `json:"power_level"`   // Percent
+	Temperature      float64 `json:"temperature"`   // Celsius
+	EngagementStress float64 `json:"engagement_stress"`
+	DatalinkStatus   string  `json:"datalink_status"`
+
+	// Combat stats
+	TotalEngagements      int    `json:"total_engagements"`
+	SuccessfulEngagements int    `json:"successful_engagements"`
+	TrackingTargets       int    `json:"tracking_targets"`
+	EngagedTarget         string `json:"engaged_target,omitempty"` // Entity ID of the track being engaged
+
+	// UI proximity circles
+	DetectionRadiusKm  float64 `json:"detection_radius_km"`
+	EngagementRadiusKm float64 `json:"engagement_radius_km"`
+	MaxSensorRangeKm   float64 `json:"max_sensor_range_km"`
+}
+
+// UASThreat is the observable metadata of a RED FORCE track. It holds only what the
+// defense could have sensed, never the simulation's ground truth.
+type UASThreat struct {
+	SchemaVersion int `json:"schema_version"`
+
+	// Track
+	TrackNumber    string  `json:"track_number"`
+	Classification string  `json:"classification"`
+	Affiliation    string  `json:"affiliation"`
+	TrackQuality   float64 `json:"track_quality"` // 0.0-1.0
+	LastSeen       string  `json:"last_seen"`     // RFC3339
+
+	// Observable characteristics
+	SizeClass         string  `json:"size_class"`
+	RadarCrossSection float64 `json:"radar_cross_section"` // Square meters
+	ObservedBehavior  string  `json:"observed_behavior"`
+	ThreatLevel       int     `json:"threat_level"` // 1-5
+
+	// Estimated kinematics
+	EstimatedSpeedKph float64 `json:"estimated_speed_kph"`
+	EstimatedHeading  float64 `json:"estimated_heading"`  // Degrees
+	EstimatedAltitude float64 `json:"estimated_altitude"` // Meters
+
+	// Sensor detections
+	RFEmitting        bool     `json:"rf_emitting"`
+	RFFrequencyMHz    *float64 `json:"rf_frequency_mhz,omitempty"`
+	ThermalSignature  bool     `json:"thermal_signature"`
+	AcousticSignature bool     `json:"acoustic_signature"`
+
+	// Engagement history
+	TimesTargeted      int  `json:"times_targeted"`
+	JammingAttempts    int  `json:"jamming_attempts"`
+	KineticAttempts    int  `json:"kinetic_attempts"`
+	ShowsJamResistance bool `json:"shows_jam_resistance"`
+
+	// Swarm membership
+	SwarmID string `json:"swarm_id,omitempty"`
+
+	// Intent estimate, present once the track's kinematics point at a defended asset
+	PredictedTarget  string         `json:"predicted_target,omitempty"`
+	TimeToImpactS    *float64       `json:"time_to_impact_s,omitempty"`
+	IntentConfidence *float64       `json:"intent_confidence,omitempty"` // 0.0-1.0
+	LikelyTargets    []LikelyTarget `json:"likely_targets,omitempty"`    // Most likely first
+}
+
+// LikelyTarget is a candidate target in a track's intent estimate
+type LikelyTarget struct {
+	Name          string  `json:"name"`
+	TimeToImpactS float64 `json:"time_to_impact_s"`
+	MissDistanceM float64 `json:"miss_distance_m"`
+}
@@ -0,0 +1,105 @@
+package metadata
+
+import (
+	"encoding/json"
+	"slices"
+	"sort"
+	"testing"
+	"time"
+)
+
+// Keys consumers rely on. Changing these lists means changing the schema: bump
+// SchemaVersion unless the change only adds keys.
+var (
+	counterUASKeys = []string{
+		"affiliation", "callsign", "cooldown_remaining", "datalink_status", "detection_radius_km",
+		"engagement_radius_km", "engagement_stress", "engagement_type", "effective_range_km",
+		"eoir_range_km", "iff_code", "max_sensor_range_km", "power_level", "radar_range_km",
+		"rf_detection_range_km", "schema_version", "sensor_mode", "success_rate",
+		"successful_engagements", "system_health", "temperature", "total_engagements",
+		"tracking_targets",
+	}
+	counterUASOptionalKeys = []string{"ammo_capacity", "ammo_remaining", "engaged_target", "reload_time_sec"}
+
+	uasThreatKeys = []string{
+		"acoustic_signature", "affiliation", "classification", "estimated_altitude",
+		"estimated_heading", "estimated_speed_kph", "jamming_attempts", "kinetic_attempts",
+		"last_seen", "observed_behavior", "radar_cross_section", "rf_emitting", "schema_version",
+		"shows_jam_resistance", "size_class", "thermal_signature", "threat_level", "times_targeted",
+		"track_number", "track_quality",
+	}
+	uasThreatOptionalKeys = []string{
+		"intent_confidence", "likely_targets", "predicted_target", "rf_frequency_mhz", "swarm_id", "time_to_impact_s",
+	}
+)
+
+// keys returns the sorted top-level keys v marshals to
+func keys(t *testing.T, v interface{}) []string {
+	t.Helper()
+	data, err := json.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc map[string]json.RawMessage
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	out := make([]string, 0, len(doc))
+	for key := range doc {
+		out = append(out, key)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// union returns the sorted keys of both lists
+func union(a, b []string) []string {
+	out := append(slices.Clone(a), b...)
+	sort.Strings(out)
+	return out
+}
+
+func TestCounterUASKeys(t *testing.T) {
+	ew := NewCounterUAS("VIPER-1", "4521", "FRIENDLY").
+		Sensors(8, 5, 12, "MULTI").
+		Weapon("electronic_warfare", 3, 0.7, 0).
+		Build()
+	if got, want := keys(t, ew), union(counterUASKeys, nil); !slices.Equal(got, want) {
+		t.Errorf("expected keys %v, got %v", want, got)
+	}
+	if ew.SchemaVersion != SchemaVersion || ew.MaxSensorRangeKm != 12 || ew.DetectionRadiusKm != 8 || ew.EngagementRadiusKm != 3 {
+		t.Errorf("expected Build to stamp the version and derive the UI circles, got %+v", ew)
+	}
+
+	kinetic := NewCounterUAS("HAMMER-2", "4522", "FRIENDLY").
+		Weapon("kinetic", 2, 0.8, 0).
+		Ammo(20, 0, 30).
+		Engaging("0b6f0a56-7d0e-4d6c-9f43-2a1b1f3b0c11").
+		Build()
+	if got, want := keys(t, kinetic), union(counterUASKeys, counterUASOptionalKeys); !slices.Equal(got, want) {
+		t.Errorf("expected keys %v, got %v", want, got)
+	}
+	if kinetic.AmmoRemaining == nil || *kinetic.AmmoRemaining != 0 {
+		t.Error("expected an empty magazine to still be reported")
+	}
+}
+
+func TestUASThreatKeys(t *testing.T) {
+	b := NewUASThreat("TK-4521", "HOSTILE", "HOSTILE").
+		Track(0.8, time.Unix(1_700_000_000, 0)).
+		Characteristics("GROUP_1", 0.1, "AGGRESSIVE", 5)
+	if got, want := keys(t, b.Build()), union(uasThreatKeys, nil); !slices.Equal(got, want) {
+		t.Errorf("expected keys %v, got %v", want, got)
+	}
+
+	full := b.RFFrequency(2400).
+		Swarm("SWARM-1").
+		Intent("Base", 45*time.Second, 0.9, []LikelyTarget{{Name: "Base", TimeToImpactS: 45, MissDistanceM: 20}}).
+		Build()
+	if got, want := keys(t, full), union(uasThreatKeys, uasThreatOptionalKeys); !slices.Equal(got, want) {
+		t.Errorf("expected keys %v, got %v", want, got)
+	}
+	if *full.TimeToImpactS != 45 || full.LastSeen != time.Unix(1_700_000_000, 0).Format(time.RFC3339) {
+		t.Errorf("unexpected intent or timestamp in %+v", full)
+	}
+}
@@ -10,13 +10,17 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/pkg/artifacts"
 	"github.com/picogrid/legion-simulations/pkg/logger"
 )
 
 // AARGenerator generates After Action Reports
 type AARGenerator struct {
-	logger *SimulationLogger
-	config AARConfig
+	logger         *SimulationLogger
+	config         AARConfig
+	attachments    []string
+	attachmentHash map[string]string // SHA-256 by file name
+	history        []*AAR            // Archived runs, most recent first
 }
 
 // AARConfig configures AAR generation
@@ -26,6 +30,11 @@ type AARConfig struct {
 	IncludeGraphs    bool
 	DetailLevel      string                 // "summary", "detailed", "full"
 	SimulationConfig map[string]interface{} // Configuration used for the simulation
+	DataPack         string                 // Model data pack the run used, pinned to its checksum
+	Seed             int64                  // Random seed the run used
+	HistoryDir       string                 // Archive of earlier JSON AARs to correlate recommendations with (empty disables)
+	HistoryRuns      int                    // Most recent archived runs compared
+	HistoryBefore    time.Time              // Only archived runs generated before this are compared (zero compares all)
 }
 
 // AAR represents an After Action Report
@@ -40,8 +49,13 @@ type AAR struct {
 	SystemAnalysis  SystemAnalysis          `json:"system_analysis"`
 	EventLog        []EventLogEntry         `json:"event_log"`
 	Statistics      SummaryStatistics       `json:"statistics"`
+	CollateralRisk  *CollateralRiskAnalysis `json:"collateral_risk,omitempty"`
+	KillChain       *KillChainAnalysis      `json:"kill_chain,omitempty"`
+	Acts            []ActAnalysis           `json:"acts,omitempty"`
 	Recommendations []Recommendation        `json:"recommendations"`
 	Lessons         []LessonLearned         `json:"lessons_learned"`
+	Attachments     []string                `json:"attachments,omitempty"`
+	AttachmentHash  map[string]string       `json:"attachment_sha256,omitempty"` // By file name, so the report vouches for what it lists
 }
 
 // AARMetadata contains report metadata
@@ -52,6 +66,9 @@ type AARMetadata struct {
 	SimulationEnd   time.Time `json:"simulation_end"`
 	Duration        string    `json:"duration"`
 	Version         string    `json:"version"`
+	DataPack        string    `json:"data_pack,omitempty"`     // name@version#sha256:<hex>
+	Seed            int64     `json:"seed,omitempty"`          // Repeats the run with --seed
+	RunsCompared    int       `json:"runs_compared,omitempty"` // This run and the archived runs recommendations were ranked against
 }
 
 // ExecutiveSummary provides high-level overview
@@ -71,6 +88,7 @@ type TimelineEntry struct {
 	Description string                 `json:"description"`
 	Impact      string                 `json:"impact"`
 	Details     map[string]interface{} `json:"details"`
+	Act         string                 `json:"act,omitempty"` // Scenario act in progress
 }
 
 // TeamAnalysis contains team-specific analysis
@@ -84,6 +102,7 @@ type TeamAnalysis struct {
 	EffectivenessRating float64               `json:"effectiveness_rating"`
 	DronePerformance    map[string]DroneStats `json:"drone_performance"`
 	TacticalAnalysis    TacticalAnalysis      `json:"tactical_analysis"`
+	Interference        int                   `json:"interference_encounters,omitempty"` // Encounters with other attacking factions
 }
 
 // DroneStats contains statistics for a drone type
@@ -130,6 +149,36 @@ type PerformanceAnalysis struct {
 	APIErrorRate        float64            `json:"api_error_rate"`
 	SimulationStability float64            `json:"simulation_stability"`
 	ResourceUtilization map[string]float64 `json:"resource_utilization"`
+	APIBudget           *APIBudgetUsage    `json:"api_budget,omitempty"`
+	UpdateTiers         *UpdateTierUsage   `json:"update_tiers,omitempty"`
+	Shutdown            *ShutdownBarrier   `json:"shutdown,omitempty"`
+}
+
+// ShutdownBarrier reports how the end of the run waited for in-flight updates before
+// the AAR was written
+type ShutdownBarrier struct {
+	WaitMs   float64 `json:"wait_ms"`
+	Unsent   int     `json:"unsent"` // Updates still pending when the barrier timed out
+	TimedOut bool    `json:"timed_out"`
+}
+
+// UpdateTierUsage reports how entities were spread across publish tiers
+type UpdateTierUsage struct {
+	High     float64 `json:"high"`   // Share of entity-ticks
+	Normal   float64 `json:"normal"` // Share of entity-ticks
+	Low      float64 `json:"low"`    // Share of entity-ticks
+	Deferred int     `json:"deferred"`
+}
+
+// APIBudgetUsage reports how much of the configured Legion API budget a run used
+type APIBudgetUsage struct {
+	PerMinute         int     `json:"per_minute"`         // 0 when unlimited
+	PerRun            int     `json:"per_run"`            // 0 when unlimited
+	PeakPerMinute     int     `json:"peak_per_minute"`    // Most calls in any 60s window
+	MinuteUtilization float64 `json:"minute_utilization"` // Peak against the per-minute budget
+	RunUtilization    float64 `json:"run_utilization"`    // Calls against the per-run budget
+	ShedPositions     int     `json:"shed_positions"`     // Location updates dropped
+	ShedMetadata      int     `json:"shed_metadata"`      // Metadata patches and feed messages dropped
 }
 
 // ThreatAnalysis contains threat assessment data
@@ -140,6 +189,64 @@ type ThreatAnalysis struct {
 	ThreatsByType          map[string]int `json:"threats_by_type"`
 	ThreatTimeline         []ThreatEvent  `json:"threat_timeline"`
 	PeakThreatLevel        string         `json:"peak_threat_level"`
+
+	// Penetrations weighted by what each leaker carried
+	Penetrations          int            `json:"penetrations"`
+	WeightedPenetrations  float64        `json:"weighted_penetrations"`
+	PenetrationsByPayload map[string]int `json:"penetrations_by_payload,omitempty"`
+	PenetrationsByAxis    map[string]int `json:"penetrations_by_axis,omitempty"`
+
+	// Payloads that landed on friendly ground units
+	GroundUnitsHit  map[string]int `json:"ground_units_hit,omitempty"`
+	PersonnelAtRisk int            `json:"personnel_at_risk,omitempty"`
+
+	// Counter-battery strikes on launch sites (nil when none landed)
+	CounterBattery *CounterBatteryAnalysis `json:"counter_battery,omitempty"`
+
+	// Later waves red replanned after watching earlier ones
+	RedAdaptations []RedAdaptation `json:"red_adaptations,omitempty"`
+}
+
+// RedAdaptation is one later wave red rerouted after an earlier wave took heavy losses
+type RedAdaptation struct {
+	Timestamp     time.Time `json:"timestamp"`
+	Faction       string    `json:"faction"`
+	ObservedWave  int       `json:"observed_wave"`
+	Attrition     float64   `json:"attrition"`
+	LethalAxis    string    `json:"lethal_axis"`
+	Wave          int       `json:"wave"`
+	IngressAxis   string    `json:"ingress_axis"`
+	IngressHeight float64   `json:"ingress_height_m"`
+	HoldSeconds   float64   `json:"hold_s"`
+}
+
+// CounterBatteryAnalysis summarizes strikes on estimated launch sites
+type CounterBatteryAnalysis struct {
+	Strikes             int      `json:"strikes"`
+	Hits                int      `json:"hits"`
+	SitesSuppressed     []string `json:"sites_suppressed,omitempty"`
+	LaunchesPrevented   int      `json:"launches_prevented"`
+	AverageMissDistance float64  `json:"avg_miss_distance_m"`
+}
+
+// CollateralRiskAnalysis summarizes hazards engagements left and who was inside them
+type CollateralRiskAnalysis struct {
+	Hazards             int                  `json:"hazards"`
+	HazardsByKind       map[string]int       `json:"hazards_by_kind"`
+	Exposures           []CollateralExposure `json:"exposures,omitempty"`
+	PopulatedAreas      []string             `json:"populated_areas_affected,omitempty"`
+	NeutralExposed      int                  `json:"neutral_traffic_exposed"`
+	ExposuresByEffector map[string]int       `json:"exposures_by_effector,omitempty"`
+}
+
+// CollateralExposure is one populated area or neutral track found inside a hazard
+type CollateralExposure struct {
+	Timestamp time.Time `json:"timestamp"`
+	Hazard    string    `json:"hazard"`
+	Subject   string    `json:"subject"`
+	Kind      string    `json:"kind"` // populated_area or neutral_traffic
+	Source    string    `json:"source"`
+	Distance  float64   `json:"distance_m"` // From the hazard's center to the subject (0 inside a populated area)
 }
 
 // ThreatEvent represents a threat detection event
@@ -158,6 +265,19 @@ type SystemAnalysis struct {
 	WeaponSystemEfficiency   float64         `json:"weapon_system_efficiency"`
 	AutonomyPerformance      float64         `json:"autonomy_performance"`
 	SystemFailures           []SystemFailure `json:"system_failures"`
+	// Sensor-to-C2 detection reporting (nil when reports are not delayed)
+	ReportLatency *ReportLatencyAnalysis `json:"report_latency,omitempty"`
+}
+
+// ReportLatencyAnalysis is how long detections took to reach C2 and what waiting for
+// them cost the defense
+type ReportLatencyAnalysis struct {
+	Delivered int     `json:"reports_delivered"`
+	Dropped   int     `json:"reports_dropped"` // Sent over a link that was down
+	Held      int     `json:"selections_held"` // Target selections passed over for want of a report
+	Mean      float64 `json:"mean_ms"`
+	P95       float64 `json:"p95_ms"`
+	Max       float64 `json:"max_ms"`
 }
 
 // SystemFailure represents a system failure event
@@ -198,6 +318,10 @@ type Recommendation struct {
 	Title           string `json:"title"`
 	Description     string `json:"description"`
 	ExpectedBenefit string `json:"expected_benefit"`
+
+	// Filled in when earlier runs were compared
+	RunsObserved int     `json:"runs_observed,omitempty"` // Runs, including this one, with the same deficiency
+	Persistence  float64 `json:"persistence,omitempty"`   // RunsObserved as a fraction of the runs compared
 }
 
 // LessonLearned represents an insight from the simulation
@@ -216,6 +340,21 @@ func NewAARGenerator(logger *SimulationLogger, config AARConfig) *AARGenerator {
 	}
 }
 
+// AddAttachment lists a file written alongside the report (timeline, coverage maps) in the
+// AAR with its SHA-256 digest
+func (g *AARGenerator) AddAttachment(path string) {
+	g.attachments = append(g.attachments, path)
+	digest, _, err := artifacts.HashFile(path)
+	if err != nil {
+		logger.Warnf("Failed to hash AAR attachment %s: %v", path, err)
+		return
+	}
+	if g.attachmentHash == nil {
+		g.attachmentHash = make(map[string]string)
+	}
+	g.attachmentHash[filepath.Base(path)] = digest
+}
+
 // GenerateAAR creates an After Action Report
 func (g *AARGenerator) GenerateAAR() (*AAR, error) {
 	summary := g.logger.GetSummary()
@@ -229,8 +368,12 @@ func (g *AARGenerator) GenerateAAR() (*AAR, error) {
 			SimulationEnd:   summary.StartTime.Add(summary.Duration),
 			Duration:        summary.Duration.String(),
 			Version:         "2.0",
+			DataPack:        g.config.DataPack,
+			Seed:            g.config.Seed,
 		},
-		TeamAnalysis: make(map[string]TeamAnalysis),
+		TeamAnalysis:   make(map[string]TeamAnalysis),
+		Attachments:    g.attachments,
+		AttachmentHash: g.attachmentHash,
 	}
 
 	// Generate executive summary
@@ -262,7 +405,20 @@ func (g *AARGenerator) GenerateAAR() (*AAR, error) {
 	// Generate summary statistics
 	aar.Statistics = g.generateStatistics(events, summary)
 
-	// Generate recommendations
+	// Assess collateral risk from engagement hazards
+	aar.CollateralRisk = g.analyzeCollateralRisk(events)
+
+	// Time the kill chain of every destroyed track
+	aar.KillChain = g.analyzeKillChain(events)
+
+	// Break the run down by scenario act
+	aar.Acts = g.analyzeActs(events, summary)
+
+	// Generate recommendations, ranked against earlier runs when there are any
+	g.loadHistory()
+	if len(g.history) > 0 {
+		aar.Metadata.RunsCompared = len(g.history) + 1
+	}
 	aar.Recommendations = g.generateRecommendations(aar)
 
 	// Generate lessons learned
@@ -271,18 +427,24 @@ func (g *AARGenerator) GenerateAAR() (*AAR, error) {
 	return aar, nil
 }
 
-// SaveAAR saves the AAR to file
-func (g *AARGenerator) SaveAAR(aar *AAR) error {
+// SaveAAR saves the AAR to file in the configured format and returns the path written
+func (g *AARGenerator) SaveAAR(aar *AAR) (string, error) {
+	return g.SaveAs(aar, g.config.Format)
+}
+
+// SaveAs saves the AAR to file in format ("json", "html" or "markdown") and returns the
+// path written
+func (g *AARGenerator) SaveAs(aar *AAR, format string) (string, error) {
 	// Create reports directory if it doesn't exist
 	if err := os.MkdirAll(g.config.OutputDir, 0755); err != nil {
-		return fmt.Errorf("failed to create output directory: %w", err)
+		return "", fmt.Errorf("failed to create output directory: %w", err)
 	}
 
 	timestamp := time.Now().Format("20060102_150405")
 	filename := fmt.Sprintf("AAR_%s_%s", aar.Metadata.SimulationID[:8], timestamp)
 
 	var err error
-	switch g.config.Format {
+	switch format {
 	case "json":
 		err = g.saveJSON(aar, filename)
 	case "html":
@@ -290,14 +452,19 @@ func (g *AARGenerator) SaveAAR(aar *AAR) error {
 	case "markdown":
 		err = g.saveMarkdown(aar, filename)
 	default:
-		return fmt.Errorf("unsupported format: %s", g.config.Format)
+		return "", fmt.Errorf("unsupported format: %s", format)
 	}
-
-	if err == nil {
-		logger.Successf("AAR saved to: %s", filepath.Join(g.config.OutputDir, filename+"."+g.config.Format))
+	if err != nil {
+		return "", err
 	}
 
-	return err
+	ext := format
+	if ext == "markdown" {
+		ext = "md"
+	}
+	path := filepath.Join(g.config.OutputDir, filename+"."+ext)
+	logger.Successf("AAR saved to: %s", path)
+	return path, nil
 }
 
 // saveJSON saves AAR as JSON
@@ -353,6 +520,12 @@ func (g *AARGenerator) saveHTML(aar *AAR, filename string) error {
 	sb.WriteString(fmt.Sprintf("<p><strong>Simulation ID:</strong> %s</p>\n", aar.Metadata.SimulationID))
 	sb.WriteString(fmt.Sprintf("<p><strong>Generated:</strong> %s</p>\n", aar.Metadata.GeneratedAt.Format("2006-01-02 15:04:05")))
 	sb.WriteString(fmt.Sprintf("<p><strong>Duration:</strong> %s</p>\n", aar.Metadata.Duration))
+	if aar.Metadata.DataPack != "" {
+		sb.WriteString(fmt.Sprintf("<p><strong>Data Pack:</strong> %s</p>\n", aar.Metadata.DataPack))
+	}
+	if aar.Metadata.Seed != 0 {
+		sb.WriteString(fmt.Sprintf("<p><strong>Seed:</strong> %d</p>\n", aar.Metadata.Seed))
+	}
 
 	// Executive Summary
 	sb.WriteString("<h2>Executive Summary</h2>\n")
@@ -363,6 +536,8 @@ func (g *AARGenerator) saveHTML(aar *AAR, filename string) error {
 		fmt.Sprintf("%d</span></div>\n", aar.Summary.TotalEngagements))
 	sb.WriteString("<div class='metric'><span class='metric-label'>Total Losses:</span> <span class='metric-value'>" +
 		fmt.Sprintf("%d</span></div>\n", aar.Summary.TotalLosses))
+	sb.WriteString("<div class='metric'><span class='metric-label'>Penetrations:</span> <span class='metric-value'>" +
+		fmt.Sprintf("%d (weighted %.1f)</span></div>\n", aar.ThreatAnalysis.Penetrations, aar.ThreatAnalysis.WeightedPenetrations))
 
 	// Team Analysis
 	sb.WriteString("<h2>Team Analysis</h2>\n")
@@ -380,6 +555,28 @@ func (g *AARGenerator) saveHTML(aar *AAR, filename string) error {
 	}
 	sb.WriteString("</table>\n")
 
+	// Kill Chain
+	if kc := aar.KillChain; kc != nil {
+		sb.WriteString("<h2>Kill Chain</h2>\n")
+		sb.WriteString("<table>\n<tr><th>Stage</th><th>Kills</th><th>Mean</th><th>P50</th><th>P90</th><th>P95</th><th>Max</th></tr>\n")
+		for _, stage := range kc.Stages {
+			sb.WriteString(fmt.Sprintf("<tr><td>%s</td><td>%d</td><td>%.1fs</td><td>%.1fs</td><td>%.1fs</td><td>%.1fs</td><td>%.1fs</td></tr>\n",
+				stage.Name, stage.Count, stage.Mean, stage.P50, stage.P90, stage.P95, stage.Max))
+		}
+		sb.WriteString("</table>\n")
+	}
+
+	// Collateral Risk
+	if risk := aar.CollateralRisk; risk != nil && len(risk.Exposures) > 0 {
+		sb.WriteString("<h2>Collateral Risk</h2>\n")
+		sb.WriteString("<table>\n<tr><th>Time</th><th>Hazard</th><th>Subject</th><th>Effector</th><th>Distance</th></tr>\n")
+		for _, e := range risk.Exposures {
+			sb.WriteString(fmt.Sprintf("<tr><td>%s</td><td>%s</td><td>%s</td><td>%s</td><td>%.0fm</td></tr>\n",
+				e.Timestamp.Format("15:04:05"), e.Hazard, e.Subject, e.Source, e.Distance))
+		}
+		sb.WriteString("</table>\n")
+	}
+
 	// Recommendations
 	if len(aar.Recommendations) > 0 {
 		sb.WriteString("<h2>Recommendations</h2>\n")
@@ -391,6 +588,16 @@ func (g *AARGenerator) saveHTML(aar *AAR, filename string) error {
 		}
 	}
 
+	// Attachments
+	if len(aar.Attachments) > 0 {
+		sb.WriteString("<h2>Attachments</h2>\n<ul>\n")
+		for _, path := range aar.Attachments {
+			name := filepath.Base(path)
+			sb.WriteString(fmt.Sprintf("<li><a href='%s'>%s</a>%s</li>\n", name, name, digestNote(aar.AttachmentHash[name], "<code>", "</code>")))
+		}
+		sb.WriteString("</ul>\n")
+	}
+
 	// Close HTML
 	sb.WriteString("</div>\n</body>\n</html>\n")
 
@@ -406,7 +613,14 @@ func (g *AARGenerator) saveMarkdown(aar *AAR, filename string) error {
 	sb.WriteString("# After Action Report\n\n")
 	sb.WriteString(fmt.Sprintf("**Simulation ID:** %s\n", aar.Metadata.SimulationID))
 	sb.WriteString(fmt.Sprintf("**Generated:** %s\n", aar.Metadata.GeneratedAt.Format("2006-01-02 15:04:05")))
-	sb.WriteString(fmt.Sprintf("**Duration:** %s\n\n", aar.Metadata.Duration))
+	sb.WriteString(fmt.Sprintf("**Duration:** %s\n", aar.Metadata.Duration))
+	if aar.Metadata.DataPack != "" {
+		sb.WriteString(fmt.Sprintf("**Data Pack:** %s\n", aar.Metadata.DataPack))
+	}
+	if aar.Metadata.Seed != 0 {
+		sb.WriteString(fmt.Sprintf("**Seed:** %d\n", aar.Metadata.Seed))
+	}
+	sb.WriteString("\n")
 
 	// Executive Summary
 	sb.WriteString("## Executive Summary\n\n")
@@ -433,6 +647,9 @@ func (g *AARGenerator) saveMarkdown(aar *AAR, filename string) error {
 			float64(analysis.FinalStrength)/float64(analysis.InitialStrength)*100))
 		sb.WriteString(fmt.Sprintf("- **Losses:** %d\n", analysis.Losses))
 		sb.WriteString(fmt.Sprintf("- **Kills:** %d\n", analysis.Kills))
+		if analysis.Interference > 0 {
+			sb.WriteString(fmt.Sprintf("- **Cross-Faction Encounters:** %d\n", analysis.Interference))
+		}
 		sb.WriteString(fmt.Sprintf("- **Effectiveness:** %.2f\n\n", analysis.EffectivenessRating))
 	}
 
@@ -448,14 +665,116 @@ func (g *AARGenerator) saveMarkdown(aar *AAR, filename string) error {
 		sb.WriteString("## Threat Analysis\n\n")
 		sb.WriteString(fmt.Sprintf("- **Threats Identified:** %d\n", aar.ThreatAnalysis.TotalThreatsIdentified))
 		sb.WriteString(fmt.Sprintf("- **Threats Neutralized:** %d\n", aar.ThreatAnalysis.ThreatsNeutralized))
-		sb.WriteString(fmt.Sprintf("- **Peak Threat Level:** %s\n\n", aar.ThreatAnalysis.PeakThreatLevel))
+		sb.WriteString(fmt.Sprintf("- **Peak Threat Level:** %s\n", aar.ThreatAnalysis.PeakThreatLevel))
+		sb.WriteString(fmt.Sprintf("- **Penetrations:** %d (weighted consequence %.1f)\n",
+			aar.ThreatAnalysis.Penetrations, aar.ThreatAnalysis.WeightedPenetrations))
+		for _, payload := range sortedKeys(aar.ThreatAnalysis.PenetrationsByPayload) {
+			sb.WriteString(fmt.Sprintf("  - %s: %d\n", payload, aar.ThreatAnalysis.PenetrationsByPayload[payload]))
+		}
+		if len(aar.ThreatAnalysis.GroundUnitsHit) > 0 {
+			sb.WriteString(fmt.Sprintf("- **Ground Units Hit:** %d personnel at risk\n", aar.ThreatAnalysis.PersonnelAtRisk))
+			for _, unit := range sortedKeys(aar.ThreatAnalysis.GroundUnitsHit) {
+				sb.WriteString(fmt.Sprintf("  - %s: %d\n", unit, aar.ThreatAnalysis.GroundUnitsHit[unit]))
+			}
+		}
+		if cb := aar.ThreatAnalysis.CounterBattery; cb != nil {
+			sb.WriteString(fmt.Sprintf("- **Counter-Battery Strikes:** %d (%d hits, average miss %.0fm)\n", cb.Strikes, cb.Hits, cb.AverageMissDistance))
+			if len(cb.SitesSuppressed) > 0 {
+				sb.WriteString(fmt.Sprintf("- **Launch Sites Suppressed:** %s (%d launches prevented)\n",
+					strings.Join(cb.SitesSuppressed, ", "), cb.LaunchesPrevented))
+			}
+		}
+		if adaptations := aar.ThreatAnalysis.RedAdaptations; len(adaptations) > 0 {
+			sb.WriteString(fmt.Sprintf("- **Red Adaptations:** %d later waves replanned\n", len(adaptations)))
+			sb.WriteString("\n| Time | Faction | After Wave | Losses | Lethal Axis | Wave | New Axis | Height | Extra Hold |\n")
+			sb.WriteString("|------|---------|------------|--------|-------------|------|----------|--------|------------|\n")
+			for _, a := range adaptations {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %d | %.0f%% | %s | %d | %s | %.0fm | %.0fs |\n",
+					a.Timestamp.Format("15:04:05"), a.Faction, a.ObservedWave, a.Attrition*100, a.LethalAxis,
+					a.Wave, a.IngressAxis, a.IngressHeight, a.HoldSeconds))
+			}
+		}
+		sb.WriteString("\n")
+	}
+
+	// Scenario Acts
+	if len(aar.Acts) > 0 {
+		writeActsMarkdown(&sb, aar.Acts, aar.Timeline, g.config.DetailLevel == "full")
+	}
+
+	// Kill Chain
+	if aar.KillChain != nil {
+		writeKillChainMarkdown(&sb, aar.KillChain, g.config.DetailLevel == "full")
+	}
+
+	// Collateral Risk
+	if risk := aar.CollateralRisk; risk != nil {
+		sb.WriteString("## Collateral Risk\n\n")
+		sb.WriteString(fmt.Sprintf("- **Hazards:** %d", risk.Hazards))
+		for _, kind := range sortedKeys(risk.HazardsByKind) {
+			sb.WriteString(fmt.Sprintf(", %d %s", risk.HazardsByKind[kind], kind))
+		}
+		sb.WriteString("\n")
+		if len(risk.PopulatedAreas) > 0 {
+			sb.WriteString(fmt.Sprintf("- **Populated Areas Affected:** %s\n", strings.Join(risk.PopulatedAreas, ", ")))
+		}
+		sb.WriteString(fmt.Sprintf("- **Neutral Traffic Exposed:** %d\n", risk.NeutralExposed))
+		if len(risk.Exposures) > 0 {
+			sb.WriteString("\n| Time | Hazard | Subject | Effector | Distance |\n|------|--------|---------|----------|----------|\n")
+			for _, e := range risk.Exposures {
+				sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %.0fm |\n",
+					e.Timestamp.Format("15:04:05"), e.Hazard, e.Subject, e.Source, e.Distance))
+			}
+		}
+		sb.WriteString("\n")
 	}
 
 	// System Performance
 	sb.WriteString("## System Performance\n\n")
 	sb.WriteString(fmt.Sprintf("- **Average Update Time:** %.2fms\n", aar.Performance.AverageUpdateTime))
 	sb.WriteString(fmt.Sprintf("- **Peak Entity Count:** %d\n", aar.Performance.PeakEntityCount))
-	sb.WriteString(fmt.Sprintf("- **Simulation Stability:** %.1f%%\n\n", aar.Performance.SimulationStability*100))
+	sb.WriteString(fmt.Sprintf("- **Simulation Stability:** %.1f%%\n", aar.Performance.SimulationStability*100))
+	if aar.Performance.TotalAPIRequests > 0 {
+		sb.WriteString(fmt.Sprintf("- **Legion API Calls:** %d\n", aar.Performance.TotalAPIRequests))
+	}
+	if budget := aar.Performance.APIBudget; budget != nil {
+		if budget.PerMinute > 0 {
+			sb.WriteString(fmt.Sprintf("- **API Budget (per minute):** peak %d of %d (%.0f%%)\n", budget.PeakPerMinute, budget.PerMinute, budget.MinuteUtilization*100))
+		}
+		if budget.PerRun > 0 {
+			sb.WriteString(fmt.Sprintf("- **API Budget (per run):** %d of %d (%.0f%%)\n", aar.Performance.TotalAPIRequests, budget.PerRun, budget.RunUtilization*100))
+		}
+		sb.WriteString(fmt.Sprintf("- **Shed by Budget:** %d positions, %d metadata/feed\n", budget.ShedPositions, budget.ShedMetadata))
+	}
+	if tiers := aar.Performance.UpdateTiers; tiers != nil {
+		sb.WriteString(fmt.Sprintf("- **Update Tiers:** %.0f%% high, %.0f%% normal, %.0f%% low; %d updates held for their tier\n",
+			tiers.High*100, tiers.Normal*100, tiers.Low*100, tiers.Deferred))
+	}
+	if barrier := aar.Performance.Shutdown; barrier != nil {
+		if barrier.TimedOut {
+			sb.WriteString(fmt.Sprintf("- **Shutdown Barrier:** timed out after %.0fms with %d updates unsent; Legion may not match this report\n", barrier.WaitMs, barrier.Unsent))
+		} else {
+			sb.WriteString(fmt.Sprintf("- **Shutdown Barrier:** final updates sent in %.0fms\n", barrier.WaitMs))
+		}
+	}
+	if usage := aar.Performance.ResourceUtilization; len(usage) > 0 {
+		if cpu, ok := usage["cpu"]; ok {
+			sb.WriteString(fmt.Sprintf("- **CPU:** %.0f%% average, %.0f%% peak\n", cpu*100, usage["cpu_peak"]*100))
+		}
+		sb.WriteString(fmt.Sprintf("- **Memory:** heap peak %.1f MB, %.1f MB from the OS", usage["heap_peak_mb"], usage["sys_peak_mb"]))
+		if memory, ok := usage["memory"]; ok {
+			sb.WriteString(fmt.Sprintf(" (%.0f%% of GOMEMLIMIT)", memory*100))
+		}
+		sb.WriteString("\n")
+		sb.WriteString(fmt.Sprintf("- **Goroutines:** %.0f peak\n", usage["goroutines_peak"]))
+		sb.WriteString(fmt.Sprintf("- **GC:** %.0f cycles, %.1fms paused (max %.2fms, p99 %.2fms)\n",
+			usage["gc_cycles"], usage["gc_pause_total_ms"], usage["gc_pause_max_ms"], usage["gc_pause_p99_ms"]))
+	}
+	if rl := aar.SystemAnalysis.ReportLatency; rl != nil {
+		sb.WriteString(fmt.Sprintf("- **Sensor-to-C2 Latency:** mean %.0fms, p95 %.0fms, max %.0fms over %d reports\n", rl.Mean, rl.P95, rl.Max, rl.Delivered))
+		sb.WriteString(fmt.Sprintf("- **Reporting Losses:** %d reports lost on down links, %d target selections held for C2\n", rl.Dropped, rl.Held))
+	}
+	sb.WriteString("\n")
 
 	// Recommendations
 	if len(aar.Recommendations) > 0 {
@@ -478,6 +797,16 @@ func (g *AARGenerator) saveMarkdown(aar *AAR, filename string) error {
 		}
 	}
 
+	// Attachments
+	if len(aar.Attachments) > 0 {
+		sb.WriteString("## Attachments\n\n")
+		for _, path := range aar.Attachments {
+			name := filepath.Base(path)
+			sb.WriteString(fmt.Sprintf("- [%s](%s)%s\n", name, name, digestNote(aar.AttachmentHash[name], "`", "`")))
+		}
+		sb.WriteString("\n")
+	}
+
 	path := filepath.Join(g.config.OutputDir, filename+".md")
 	return os.WriteFile(path, []byte(sb.String()), 0644)
 }
@@ -528,13 +857,17 @@ func (g *AARGenerator) generateExecutiveSummary(events []SimulationEvent, summar
 func (g *AARGenerator) buildTimeline(events []SimulationEvent, startTime time.Time) []TimelineEntry {
 	timeline := make([]TimelineEntry, 0)
 
-	// Sort events by timestamp
-	sort.Slice(events, func(i, j int) bool {
+	// Sort events by timestamp, keeping the logged order of simultaneous ones
+	sort.SliceStable(events, func(i, j int) bool {
 		return events[i].Timestamp.Before(events[j].Timestamp)
 	})
 
-	// Convert significant events to timeline entries
+	// Convert significant events to timeline entries, noting the act each falls in
+	act := ""
 	for _, event := range events {
+		if event.Type == EventTypeAct {
+			act, _ = event.Details["act"].(string)
+		}
 		if g.isSignificantEvent(event) {
 			elapsed := event.Timestamp.Sub(startTime)
 			entry := TimelineEntry{
@@ -544,6 +877,7 @@ func (g *AARGenerator) buildTimeline(events []SimulationEvent, startTime time.Ti
 				Description: event.Message,
 				Impact:      g.assessImpact(event),
 				Details:     event.Details,
+				Act:         act,
 			}
 			timeline = append(timeline, entry)
 		}
@@ -556,6 +890,14 @@ func (g *AARGenerator) buildTimeline(events []SimulationEvent, startTime time.Ti
 func (g *AARGenerator) analyzeTeams(events []SimulationEvent, summary SimulationSummary) map[string]TeamAnalysis {
 	teams := make(map[string]TeamAnalysis)
 
+	// Latest status logged for each team
+	status := make(map[string]map[string]interface{})
+	for _, event := range events {
+		if event.Type == EventTypeTeamStatus && event.Details != nil {
+			status[event.TeamName] = event.Details
+		}
+	}
+
 	// Extract team data from events
 	for teamName, teamEvents := range summary.TeamEvents {
 		analysis := TeamAnalysis{
@@ -563,6 +905,14 @@ func (g *AARGenerator) analyzeTeams(events []SimulationEvent, summary Simulation
 			Losses:           teamEvents[EventTypeDestruction],
 			DronePerformance: make(map[string]DroneStats),
 		}
+		if details := status[teamName]; details != nil {
+			analysis.InitialStrength = detailInt(details, "total_drones")
+			analysis.Kills = detailInt(details, "reached_objective")
+			analysis.Interference = detailInt(details, "interference")
+			if analysis.InitialStrength > 0 {
+				analysis.TacticalAnalysis.ObjectiveCompletion = float64(analysis.Kills) / float64(analysis.InitialStrength)
+			}
+		}
 
 		// Calculate effectiveness rating (simplified)
 		if analysis.InitialStrength > 0 {
@@ -588,6 +938,73 @@ func (g *AARGenerator) analyzeTeams(events []SimulationEvent, summary Simulation
 	return teams
 }
 
+// digestNote renders an attachment's SHA-256 digest after its link, wrapped in open and
+// close, or nothing when it couldn't be hashed
+func digestNote(digest, open, close string) string {
+	if digest == "" {
+		return ""
+	}
+	return " " + open + "sha256:" + digest + close
+}
+
+// detailInt reads an integer event detail, which may have been decoded from JSON as a float
+func detailInt(details map[string]interface{}, key string) int {
+	switch v := details[key].(type) {
+	case int:
+		return v
+	case float64:
+		return int(v)
+	}
+	return 0
+}
+
+// detailInts reads an integer list event detail, which may have been decoded from JSON as
+// a list of floats
+func detailInts(details map[string]interface{}, key string) []int {
+	switch v := details[key].(type) {
+	case []int:
+		return v
+	case []interface{}:
+		ints := make([]int, 0, len(v))
+		for _, item := range v {
+			if f, ok := item.(float64); ok {
+				ints = append(ints, int(f))
+			}
+		}
+		return ints
+	}
+	return nil
+}
+
+// detailStrings reads a string list event detail, decoded from JSON or not
+func detailStrings(details map[string]interface{}, key string) []string {
+	switch v := details[key].(type) {
+	case []string:
+		return v
+	case []interface{}:
+		strs := make([]string, 0, len(v))
+		for _, item := range v {
+			if str, ok := item.(string); ok {
+				strs = append(strs, str)
+			}
+		}
+		return strs
+	}
+	return nil
+}
+
+// detailTime reads a time event detail, which JSON decodes as an RFC 3339 string
+func detailTime(details map[string]interface{}, key string) time.Time {
+	switch v := details[key].(type) {
+	case time.Time:
+		return v
+	case string:
+		t, _ := time.Parse(time.RFC3339Nano, v)
+		return t
+	}
+	return time.Time{}
+}
+
 // analyzeEngagements performs engagement analysis
 func (g *AARGenerator) analyzeEngagements(events []SimulationEvent) EngagementAnalysis {
 	analysis := EngagementAnalysis{
@@ -635,7 +1052,7 @@ func (g *AARGenerator) analyzeEngagements(events []SimulationEvent) EngagementAn
 // analyzePerformance analyzes system performance
 func (g *AARGenerator) analyzePerformance(summary SimulationSummary) PerformanceAnalysis {
 	analysis := PerformanceAnalysis{
-		ResourceUtilization: make(map[string]float64),
+		ResourceUtilization: resourceUtilization(summary),
 	}
 
 	// Extract performance metrics from summary
@@ -651,6 +1068,24 @@ func (g *AARGenerator) analyzePerformance(summary SimulationSummary) Performance
 		analysis.PeakEntityCount = int(metric.Value)
 	}
 
+	if metric, ok := summary.Metrics[MetricAPICalls]; ok {
+		analysis.TotalAPIRequests = int(metric.Value)
+	}
+	analysis.APIBudget = apiBudgetUsage(summary, analysis.TotalAPIRequests)
+	if _, ok := summary.Metrics[MetricUpdatesDeferred]; ok {
+		analysis.UpdateTiers = &UpdateTierUsage{
+			High:     summary.Metrics[MetricUpdateTierHigh].Value,
+			Normal:   summary.Metrics[MetricUpdateTierNormal].Value,
+			Low:      summary.Metrics[MetricUpdateTierLow].Value,
+			Deferred: int(summary.Metrics[MetricUpdatesDeferred].Value),
+		}
+	}
+
+	if metric, ok := summary.Metrics[MetricShutdownWait]; ok {
+		unsent := int(summary.Metrics[MetricShutdownUnsent].Value)
+		analysis.Shutdown = &ShutdownBarrier{WaitMs: metric.Value, Unsent: unsent, TimedOut: unsent > 0}
+	}
+
 	// Calculate stability (simplified - based on error rate)
 	errorCount := summary.EventCounts[EventTypeSystem]
 	totalEvents := summary.TotalEvents
@@ -662,12 +1097,49 @@ func (g *AARGenerator) analyzePerformance(summary SimulationSummary) Performance
 	return analysis
 }
 
+// resourceUtilization reads the process resource metrics sampled during the run
+func resourceUtilization(summary SimulationSummary) map[string]float64 {
+	utilization := make(map[string]float64)
+	for name, key := range resourceMetrics {
+		if metric, ok := summary.Metrics[name]; ok {
+			utilization[key] = metric.Value
+		}
+	}
+	return utilization
+}
+
+// apiBudgetUsage reads budget metrics; nil when the run had no budget
+func apiBudgetUsage(summary SimulationSummary, calls int) *APIBudgetUsage {
+	value := func(name string) int { return int(summary.Metrics[name].Value) }
+	usage := &APIBudgetUsage{
+		PerMinute:     value(MetricAPIBudgetPerMinute),
+		PerRun:        value(MetricAPIBudgetPerRun),
+		PeakPerMinute: value(MetricAPIPeakPerMinute),
+		ShedPositions: value(MetricAPIShedPositions),
+		ShedMetadata:  value(MetricAPIShedMetadata),
+	}
+	if usage.PerMinute == 0 && usage.PerRun == 0 {
+		return nil
+	}
+	if usage.PerMinute > 0 {
+		usage.MinuteUtilization = float64(usage.PeakPerMinute) / float64(usage.PerMinute)
+	}
+	if usage.PerRun > 0 {
+		usage.RunUtilization = float64(calls) / float64(usage.PerRun)
+	}
+	return usage
+}
+
 // Helper functions
 
 func (g *AARGenerator) isSignificantEvent(event SimulationEvent) bool {
 	return event.Type == EventTypeEngagement ||
 		event.Type == EventTypeDestruction ||
 		event.Type == EventTypeObjective ||
+		event.Type == EventTypeInject ||
+		event.Type == EventTypeStrike ||
+		event.Type == EventTypeAdaptation ||
+		event.Type == EventTypeAct ||
 		(event.Type == EventTypeTeamStatus && event.Severity != SeverityInfo)
 }
 
@@ -677,6 +1149,17 @@ func (g *AARGenerator) assessImpact(event SimulationEvent) string {
 		return "High - Force reduction"
 	case EventTypeObjective:
 		return "High - Mission progress"
+	case EventTypeInject:
+		return "Medium - Facilitator inject"
+	case EventTypeStrike:
+		if hit, ok := event.Details["hit"].(bool); ok && hit {
+			return "High - Launch site suppressed"
+		}
+		return "Low - Strike missed"
+	case EventTypeAdaptation:
+		return "Medium - Red force replanned"
+	case EventTypeAct:
+		return "Medium - Scenario act began"
 	case EventTypeEngagement:
 		if hit, ok := event.Details["hit"].(bool); ok && hit {
 			return "Medium - Successful engagement"
@@ -690,8 +1173,11 @@ func (g *AARGenerator) assessImpact(event SimulationEvent) string {
 // analyzeThreatData analyzes threat-related events
 func (g *AARGenerator) analyzeThreatData(events []SimulationEvent) ThreatAnalysis {
 	analysis := ThreatAnalysis{
-		ThreatsByType:  make(map[string]int),
-		ThreatTimeline: make([]ThreatEvent, 0),
+		ThreatsByType:         make(map[string]int),
+		ThreatTimeline:        make([]ThreatEvent, 0),
+		PenetrationsByPayload: make(map[string]int),
+		PenetrationsByAxis:    make(map[string]int),
+		GroundUnitsHit:        make(map[string]int),
 	}
 
 	var threatDurations []time.Duration
@@ -714,9 +1200,7 @@ func (g *AARGenerator) analyzeThreatData(events []SimulationEvent) ThreatAnalysi
 						Team:        event.TeamName,
 					}
 
-					if count, ok := details["threat_count"].(int); ok {
-						threatEvent.ThreatCount = count
-					}
+					threatEvent.ThreatCount = detailInt(details, "threat_count")
 
 					analysis.ThreatTimeline = append(analysis.ThreatTimeline, threatEvent)
 				}
@@ -735,6 +1219,64 @@ func (g *AARGenerator) analyzeThreatData(events []SimulationEvent) ThreatAnalysi
 				}
 			}
 		}
+
+		if event.Type == EventTypeObjective && event.Message == "Objective reached_target: complete" {
+			analysis.Penetrations++
+			consequence := 1.0 // Unweighted when the payload is unknown
+			if details := event.Details; details != nil {
+				if payload, ok := details["payload"].(string); ok {
+					analysis.PenetrationsByPayload[payload]++
+				}
+				if axis, ok := details["axis"].(string); ok {
+					analysis.PenetrationsByAxis[axis]++
+				}
+				if c, ok := details["consequence"].(float64); ok {
+					consequence = c
+				}
+				for _, unit := range detailStrings(details, "units_hit") {
+					analysis.GroundUnitsHit[unit]++
+				}
+				analysis.PersonnelAtRisk += detailInt(details, "personnel")
+			}
+			analysis.WeightedPenetrations += consequence
+		}
+
+		if event.Type == EventTypeAdaptation && event.Details != nil {
+			adaptation := RedAdaptation{
+				Timestamp:    event.Timestamp,
+				Faction:      event.TeamName,
+				ObservedWave: detailInt(event.Details, "observed_wave"),
+				Wave:         detailInt(event.Details, "wave"),
+			}
+			adaptation.Attrition, _ = event.Details["attrition"].(float64)
+			adaptation.LethalAxis, _ = event.Details["lethal_axis"].(string)
+			adaptation.IngressAxis, _ = event.Details["ingress_axis"].(string)
+			adaptation.IngressHeight, _ = event.Details["ingress_height_m"].(float64)
+			adaptation.HoldSeconds, _ = event.Details["hold_s"].(float64)
+			analysis.RedAdaptations = append(analysis.RedAdaptations, adaptation)
+		}
+
+		if event.Type == EventTypeStrike && event.Details != nil {
+			if analysis.CounterBattery == nil {
+				analysis.CounterBattery = &CounterBatteryAnalysis{}
+			}
+			cb := analysis.CounterBattery
+			cb.Strikes++
+			if miss, ok := event.Details["miss_distance_m"].(float64); ok {
+				cb.AverageMissDistance += miss
+			}
+			if hit, ok := event.Details["hit"].(bool); ok && hit {
+				cb.Hits++
+				if site, ok := event.Details["site"].(string); ok {
+					cb.SitesSuppressed = append(cb.SitesSuppressed, site)
+				}
+				cb.LaunchesPrevented += detailInt(event.Details, "launches_prevented")
+			}
+		}
+	}
+
+	if cb := analysis.CounterBattery; cb != nil {
+		cb.AverageMissDistance /= float64(cb.Strikes)
 	}
 
 	analysis.PeakThreatLevel = maxThreatLevel
@@ -752,6 +1294,49 @@ func (g *AARGenerator) analyzeThreatData(events []SimulationEvent) ThreatAnalysi
 	return analysis
 }
 
+// analyzeCollateralRisk collects hazard and exposure events. It returns nil when no
+// engagement left a hazard.
+func (g *AARGenerator) analyzeCollateralRisk(events []SimulationEvent) *CollateralRiskAnalysis {
+	var analysis *CollateralRiskAnalysis
+	areas := make(map[string]bool)
+	for _, event := range events {
+		if (event.Type != EventTypeHazard && event.Type != EventTypeCollateral) || event.Details == nil {
+			continue
+		}
+		if analysis == nil {
+			analysis = &CollateralRiskAnalysis{HazardsByKind: make(map[string]int), ExposuresByEffector: make(map[string]int)}
+		}
+
+		hazard, _ := event.Details["hazard"].(string)
+		if event.Type == EventTypeHazard {
+			analysis.Hazards++
+			analysis.HazardsByKind[hazard]++
+			continue
+		}
+
+		exposure := CollateralExposure{Timestamp: event.Timestamp, Hazard: hazard}
+		exposure.Subject, _ = event.Details["subject"].(string)
+		exposure.Kind, _ = event.Details["kind"].(string)
+		exposure.Source, _ = event.Details["source"].(string)
+		exposure.Distance, _ = event.Details["distance_m"].(float64)
+		analysis.Exposures = append(analysis.Exposures, exposure)
+		analysis.ExposuresByEffector[exposure.Source]++
+
+		if exposure.Kind == "populated_area" {
+			if !areas[exposure.Subject] {
+				areas[exposure.Subject] = true
+				analysis.PopulatedAreas = append(analysis.PopulatedAreas, exposure.Subject)
+			}
+		} else {
+			analysis.NeutralExposed++
+		}
+	}
+	if analysis != nil {
+		sort.Strings(analysis.PopulatedAreas)
+	}
+	return analysis
+}
+
 // analyzeSystemPerformance analyzes system-level performance
 func (g *AARGenerator) analyzeSystemPerformance(events []SimulationEvent, summary SimulationSummary) SystemAnalysis {
 	analysis := SystemAnalysis{
@@ -839,6 +1424,18 @@ func (g *AARGenerator) analyzeSystemPerformance(events []SimulationEvent, summar
 		analysis.CommandLatency = metric.Value
 	}
 
+	// Sensor-to-C2 reporting delay
+	if delivered, ok := summary.Metrics[MetricReportsDelivered]; ok {
+		analysis.ReportLatency = &ReportLatencyAnalysis{
+			Delivered: int(delivered.Value),
+			Dropped:   int(summary.Metrics[MetricReportsDropped].Value),
+			Held:      int(summary.Metrics[MetricReportsHeld].Value),
+			Mean:      summary.Metrics[MetricReportLatencyMean].Value,
+			P95:       summary.Metrics[MetricReportLatencyP95].Value,
+			Max:       summary.Metrics[MetricReportLatencyMax].Value,
+		}
+	}
+
 	// Calculate autonomy performance (simplified)
 	analysis.AutonomyPerformance = (analysis.CommunicationReliability + analysis.SensorAccuracy +
 		analysis.WeaponSystemEfficiency) / 3.0
@@ -874,7 +1471,7 @@ func (g *AARGenerator) generateEventLog(events []SimulationEvent) []EventLogEntr
 func (g *AARGenerator) generateStatistics(events []SimulationEvent, summary SimulationSummary) SummaryStatistics {
 	stats := SummaryStatistics{
 		TotalEngagements:    summary.EventCounts[EventTypeEngagement],
-		ResourceUtilization: make(map[string]float64),
+		ResourceUtilization: resourceUtilization(summary),
 	}
 
 	// Count drones and losses
@@ -916,80 +1513,120 @@ func (g *AARGenerator) generateStatistics(events []SimulationEvent, summary Simu
 
 	stats.AverageMissionDuration = summary.Duration.String()
 
-	// Calculate resource utilization
-	if metric, ok := summary.Metrics["cpu_usage"]; ok {
-		stats.ResourceUtilization["cpu"] = metric.Value
+	return stats
+}
+
+// generateRecommendations turns this run's deficiencies into recommendations. With an
+// archive of earlier runs they are ranked by how persistently each deficiency recurs.
+func (g *AARGenerator) generateRecommendations(aar *AAR) []Recommendation {
+	found := detectDeficiencies(aar)
+	if len(g.history) > 0 {
+		return correlateDeficiencies(found, g.history)
 	}
-	if metric, ok := summary.Metrics["memory_usage"]; ok {
-		stats.ResourceUtilization["memory"] = metric.Value
+
+	recs := make([]Recommendation, 0, len(found))
+	for _, d := range found {
+		recs = append(recs, d.Recommendation)
 	}
+	return recs
+}
 
-	return stats
+// deficiency is a shortfall found in a single run. Its key is stable across runs so
+// the same problem can be recognized in the archive.
+type deficiency struct {
+	Key string
+	Recommendation
 }
 
-// generateRecommendations generates improvement recommendations
-func (g *AARGenerator) generateRecommendations(aar *AAR) []Recommendation {
-	recs := make([]Recommendation, 0)
+// detectDeficiencies checks a run against single-run thresholds
+func detectDeficiencies(aar *AAR) []deficiency {
+	found := make([]deficiency, 0)
 
 	// Check engagement effectiveness
 	if aar.Engagements.HitRate < 0.3 {
-		recs = append(recs, Recommendation{
+		found = append(found, deficiency{Key: "targeting", Recommendation: Recommendation{
 			Priority:        "High",
 			Category:        "Targeting",
 			Title:           "Improve Targeting Algorithms",
 			Description:     "Current hit rate is below 30%, indicating significant issues with targeting accuracy.",
 			ExpectedBenefit: "Increase hit rate to 50-60%, reducing ammunition expenditure and increasing mission effectiveness.",
-		})
+		}})
 	}
 
 	// Check communication reliability
 	if aar.SystemAnalysis.CommunicationReliability < 0.95 {
-		recs = append(recs, Recommendation{
+		found = append(found, deficiency{Key: "communications", Recommendation: Recommendation{
 			Priority:        "High",
 			Category:        "Communications",
 			Title:           "Enhance Communication Protocols",
 			Description:     "Communication reliability is below 95%, risking command and control effectiveness.",
 			ExpectedBenefit: "Improve mission coordination and reduce response times.",
-		})
+		}})
 	}
 
 	// Check threat response
 	if aar.ThreatAnalysis.TotalThreatsIdentified > 0 {
 		neutralizationRate := float64(aar.ThreatAnalysis.ThreatsNeutralized) / float64(aar.ThreatAnalysis.TotalThreatsIdentified)
 		if neutralizationRate < 0.8 {
-			recs = append(recs, Recommendation{
+			found = append(found, deficiency{Key: "threat_response", Recommendation: Recommendation{
 				Priority:        "Medium",
 				Category:        "Threat Response",
 				Title:           "Improve Threat Neutralization Capability",
 				Description:     fmt.Sprintf("Only %.1f%% of identified threats were neutralized.", neutralizationRate*100),
 				ExpectedBenefit: "Increase survivability and mission success rate.",
-			})
+			}})
 		}
 	}
 
+	// Check for an approach axis that accounts for most of the leakers
+	for _, axis := range sortedKeys(aar.ThreatAnalysis.PenetrationsByAxis) {
+		leaked := aar.ThreatAnalysis.PenetrationsByAxis[axis]
+		if leaked < 2 || float64(leaked) < axisLeakShare*float64(aar.ThreatAnalysis.Penetrations) {
+			continue
+		}
+		found = append(found, deficiency{Key: "leak_axis:" + axis, Recommendation: Recommendation{
+			Priority:        "High",
+			Category:        "Coverage",
+			Title:           fmt.Sprintf("Reinforce the %s Axis", axis),
+			Description:     fmt.Sprintf("%d of %d penetrations came in from the %s.", leaked, aar.ThreatAnalysis.Penetrations, axis),
+			ExpectedBenefit: "Close the approach raids get through on, cutting leakage where it concentrates.",
+		}})
+	}
+
 	// Check system stability
 	if aar.Performance.SimulationStability < 0.98 {
-		recs = append(recs, Recommendation{
+		found = append(found, deficiency{Key: "stability", Recommendation: Recommendation{
 			Priority:        "Medium",
 			Category:        "System Stability",
 			Title:           "Address System Stability Issues",
 			Description:     "System stability is below optimal levels, indicating potential reliability issues.",
 			ExpectedBenefit: "Reduce system failures and improve overall mission reliability.",
-		})
+		}})
+	}
+
+	// Check collateral risk
+	if risk := aar.CollateralRisk; risk != nil && len(risk.PopulatedAreas) > 0 {
+		found = append(found, deficiency{Key: "collateral", Recommendation: Recommendation{
+			Priority:        "High",
+			Category:        "Collateral Risk",
+			Title:           "Restrict Kinetic Engagements Over Populated Areas",
+			Description:     fmt.Sprintf("Debris or effector noise reached %s.", strings.Join(risk.PopulatedAreas, ", ")),
+			ExpectedBenefit: "Keep intercepts clear of people on the ground, favoring EW where kinetic debris would fall on them.",
+		}})
 	}
 
 	// Check resource utilization
 	if aar.Statistics.ResourceUtilization["cpu"] > 0.8 || aar.Statistics.ResourceUtilization["memory"] > 0.8 {
-		recs = append(recs, Recommendation{
+		found = append(found, deficiency{Key: "resources", Recommendation: Recommendation{
 			Priority:        "Low",
 			Category:        "Performance",
 			Title:           "Optimize Resource Usage",
 			Description:     "High resource utilization detected, which may limit scalability.",
 			ExpectedBenefit: "Enable handling of larger swarm sizes and more complex scenarios.",
-		})
+		}})
 	}
 
-	return recs
+	return found
 }
 
 // generateLessonsLearned extracts insights from the simulation
@@ -1115,3 +1752,13 @@ func formatDuration(d time.Duration) string {
 	seconds := int(d.Seconds()) % 60
 	return fmt.Sprintf("%02d:%02d", minutes, seconds)
 }
+
+// sortedKeys returns the keys of a count map in alphabetical order
+func sortedKeys(m map[string]int) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
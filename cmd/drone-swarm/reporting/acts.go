@@ -0,0 +1,120 @@
+package reporting
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ActAnalysis is what happened during one scenario act
+type ActAnalysis struct {
+	Number      int     `json:"number"`
+	Name        string  `json:"name"`
+	Behavior    string  `json:"behavior"`
+	ROE         string  `json:"roe"`
+	Waves       []int   `json:"waves,omitempty"` // Waves launched when the act began
+	StartS      float64 `json:"start_s"`         // Seconds after the simulation start
+	EndS        float64 `json:"end_s"`
+	Engagements int     `json:"engagements"`
+	Hits        int     `json:"hits"`
+	Destroyed   int     `json:"destroyed"`
+	Events      int     `json:"timeline_events"` // Timeline entries that fall in the act
+}
+
+// LogAct logs the start of a scenario act
+func (sl *SimulationLogger) LogAct(number int, name, behavior, roe string, waves []int, duration time.Duration) {
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypeAct,
+		Severity:  SeverityInfo,
+		Message:   fmt.Sprintf("Act %d begins: %s (red %s, ROE %s)", number, name, behavior, roe),
+		Details: map[string]interface{}{
+			"act":        name,
+			"number":     number,
+			"behavior":   behavior,
+			"roe":        roe,
+			"waves":      waves,
+			"duration_s": duration.Seconds(),
+		},
+	})
+}
+
+// analyzeActs splits the run into its scenario acts, or returns nil for a run without
+// acts. Events are expected in timestamp order.
+func (g *AARGenerator) analyzeActs(events []SimulationEvent, summary SimulationSummary) []ActAnalysis {
+	var acts []ActAnalysis
+	for _, event := range events {
+		if event.Type == EventTypeAct {
+			act := ActAnalysis{StartS: event.Timestamp.Sub(summary.StartTime).Seconds()}
+			act.Number = detailInt(event.Details, "number")
+			act.Name, _ = event.Details["act"].(string)
+			act.Behavior, _ = event.Details["behavior"].(string)
+			act.ROE, _ = event.Details["roe"].(string)
+			act.Waves = detailInts(event.Details, "waves")
+			if n := len(acts); n > 0 {
+				acts[n-1].EndS = act.StartS
+			}
+			acts = append(acts, act)
+			continue
+		}
+		if len(acts) == 0 {
+			continue
+		}
+
+		act := &acts[len(acts)-1]
+		switch event.Type {
+		case EventTypeEngagement:
+			act.Engagements++
+			if hit, ok := event.Details["hit"].(bool); ok && hit {
+				act.Hits++
+			}
+		case EventTypeDestruction:
+			act.Destroyed++
+		}
+		if g.isSignificantEvent(event) {
+			act.Events++
+		}
+	}
+	if n := len(acts); n > 0 {
+		acts[n-1].EndS = summary.Duration.Seconds()
+	}
+	return acts
+}
+
+// writeActsMarkdown writes the per-act breakdown and, at full detail, the timeline
+// grouped by act
+func writeActsMarkdown(sb *strings.Builder, acts []ActAnalysis, timeline []TimelineEntry, full bool) {
+	sb.WriteString("## Scenario Acts\n\n")
+	sb.WriteString("| Act | Red | ROE | Waves | Start | End | Engagements | Hits | Destroyed |\n")
+	sb.WriteString("|-----|-----|-----|-------|-------|-----|-------------|------|-----------|\n")
+	for _, act := range acts {
+		waves := "-"
+		if len(act.Waves) > 0 {
+			numbers := make([]string, len(act.Waves))
+			for i, wave := range act.Waves {
+				numbers[i] = strconv.Itoa(wave)
+			}
+			waves = strings.Join(numbers, ", ")
+		}
+		sb.WriteString(fmt.Sprintf("| %d. %s | %s | %s | %s | %s | %s | %d | %d | %d |\n",
+			act.Number, act.Name, act.Behavior, act.ROE, waves,
+			formatDuration(time.Duration(act.StartS*float64(time.Second))),
+			formatDuration(time.Duration(act.EndS*float64(time.Second))),
+			act.Engagements, act.Hits, act.Destroyed))
+	}
+	sb.WriteString("\n")
+
+	if !full {
+		return
+	}
+	for _, act := range acts {
+		sb.WriteString(fmt.Sprintf("### Act %d: %s\n\n", act.Number, act.Name))
+		for _, entry := range timeline {
+			if entry.Act == act.Name && entry.EventType != EventTypeAct {
+				sb.WriteString(fmt.Sprintf("- %s %s\n", entry.ElapsedTime, entry.Description))
+			}
+		}
+		sb.WriteString("\n")
+	}
+}
@@ -0,0 +1,37 @@
+package reporting
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestActAnalysis(t *testing.T) {
+	sl := NewSimulationLogger("test")
+	sl.LogAct(1, "Recon", "surveil", "hold", nil, time.Minute)
+	sl.LogEngagement(uuid.New(), uuid.New(), "miss", map[string]interface{}{"hit": false})
+	sl.LogAct(2, "Strike", "attack", "free", []int{2}, time.Minute)
+	target := uuid.New()
+	sl.LogEngagement(uuid.New(), target, "hit", map[string]interface{}{"hit": true})
+	sl.LogDestruction(target, "Red", "kinetic")
+
+	g := NewAARGenerator(sl, AARConfig{})
+	summary := sl.GetSummary()
+	events := sl.GetEvents()
+	timeline := g.buildTimeline(events, summary.StartTime)
+	acts := g.analyzeActs(events, summary)
+
+	if len(acts) != 2 {
+		t.Fatalf("got %d acts, want 2", len(acts))
+	}
+	if acts[0].Name != "Recon" || acts[0].Engagements != 1 || acts[0].Hits != 0 {
+		t.Errorf("unexpected first act %+v", acts[0])
+	}
+	if acts[1].Engagements != 1 || acts[1].Hits != 1 || acts[1].Destroyed != 1 || len(acts[1].Waves) != 1 {
+		t.Errorf("unexpected second act %+v", acts[1])
+	}
+	if last := timeline[len(timeline)-1]; last.Act != "Strike" {
+		t.Errorf("last timeline entry is in act %q, want Strike", last.Act)
+	}
+}
@@ -0,0 +1,97 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// EventDump is a run's simulation log saved to disk, so its AAR can be regenerated later
+// in another format or at another detail level
+type EventDump struct {
+	SimulationID string            `json:"simulation_id"`
+	RunID        string            `json:"run_id,omitempty"`
+	Start        time.Time         `json:"start"`
+	End          time.Time         `json:"end"`
+	DataPack     string            `json:"data_pack,omitempty"`
+	Attachments  []string          `json:"attachments,omitempty"` // Files the run's AAR listed
+	Events       []SimulationEvent `json:"events"`
+	Metrics      map[string]Metric `json:"metrics"`
+}
+
+// Dump captures the logger's events and metrics as they stand now
+func (sl *SimulationLogger) Dump() EventDump {
+	sl.mu.RLock()
+	end := sl.endTime
+	sl.mu.RUnlock()
+	if end.IsZero() {
+		end = time.Now()
+	}
+	return EventDump{
+		SimulationID: sl.simulationID,
+		Start:        sl.startTime,
+		End:          end,
+		Events:       sl.GetEvents(),
+		Metrics:      sl.GetMetrics(),
+	}
+}
+
+// Dump captures the generator's log with the data pack and attachments its AAR lists
+func (g *AARGenerator) Dump() EventDump {
+	dump := g.logger.Dump()
+	dump.DataPack = g.config.DataPack
+	dump.Attachments = append([]string(nil), g.attachments...)
+	return dump
+}
+
+// Logger returns a logger holding the dump's events and metrics, ended when the dump was
+// taken, for an AARGenerator to report on
+func (d *EventDump) Logger() *SimulationLogger {
+	sl := &SimulationLogger{
+		simulationID: d.SimulationID,
+		startTime:    d.Start,
+		endTime:      d.End,
+		events:       d.Events,
+		metrics:      d.Metrics,
+	}
+	if sl.events == nil {
+		sl.events = make([]SimulationEvent, 0)
+	}
+	if sl.metrics == nil {
+		sl.metrics = make(map[string]Metric)
+	}
+	return sl
+}
+
+// SaveEventDump writes a dump to path as JSON
+func SaveEventDump(path string, dump EventDump) error {
+	data, err := json.MarshalIndent(dump, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode event log: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write event log: %w", err)
+	}
+	return nil
+}
+
+// LoadEventDump reads a dump written by SaveEventDump
+func LoadEventDump(path string) (*EventDump, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read event log: %w", err)
+	}
+	var dump EventDump
+	if err := json.Unmarshal(data, &dump); err != nil {
+		return nil, fmt.Errorf("failed to parse event log %s: %w", path, err)
+	}
+	if len(dump.SimulationID) < 8 || dump.Start.IsZero() {
+		return nil, fmt.Errorf("%s is not a simulation event log", path)
+	}
+	return &dump, nil
+}
@@ -0,0 +1,50 @@
+package reporting
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestEventDumpRegeneratesAAR(t *testing.T) {
+	sl := NewSimulationLogger("counter-uas-simulation")
+	sl.LogAct(1, "Strike", "attack", "free", []int{1, 2}, time.Minute)
+	detected := time.Now().Add(-10 * time.Second)
+	sl.LogKillChain(uuid.New(), "TK-0001", "CUAS-01", map[string]time.Time{
+		MilestoneDetected: detected,
+		MilestoneKilled:   time.Now(),
+	})
+	sl.UpdateMetric(MetricPauses, 2, "count")
+
+	path := filepath.Join(t.TempDir(), "Events_test.json")
+	if err := SaveEventDump(path, sl.Dump()); err != nil {
+		t.Fatal(err)
+	}
+	dump, err := LoadEventDump(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	restored := dump.Logger()
+	if got, want := restored.GetSummary().Duration, dump.End.Sub(dump.Start); got != want {
+		t.Errorf("restored duration %s, want %s fixed at the dump", got, want)
+	}
+	aar, err := NewAARGenerator(restored, AARConfig{DetailLevel: "full"}).GenerateAAR()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(aar.Acts) != 1 || aar.Acts[0].Number != 1 || len(aar.Acts[0].Waves) != 2 {
+		t.Errorf("acts not restored from JSON details: %+v", aar.Acts)
+	}
+	if aar.KillChain == nil || !aar.KillChain.Timelines[0].DetectedAt.Equal(detected) {
+		t.Errorf("kill chain detection time not restored: %+v", aar.KillChain)
+	}
+	if len(aar.EventLog) != len(dump.Events) {
+		t.Errorf("full report logged %d events, want %d", len(aar.EventLog), len(dump.Events))
+	}
+	if restored.GetMetrics()[MetricPauses].Value != 2 {
+		t.Errorf("metrics not restored: %+v", restored.GetMetrics())
+	}
+}
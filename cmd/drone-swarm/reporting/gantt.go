@@ -0,0 +1,285 @@
+package reporting
+
+import (
+	"fmt"
+	"html"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// System states shown on the engagement timeline
+const (
+	GanttIdle     = "idle"
+	GanttTracking = "tracking"
+	GanttEngaging = "engaging"
+	GanttReload   = "reload"
+	GanttOffline  = "offline"
+	GanttActive   = "active" // Wave lanes
+)
+
+// ganttColors maps each state to its bar color
+var ganttColors = map[string]string{
+	GanttIdle:     "#d0d7de",
+	GanttTracking: "#58a6ff",
+	GanttEngaging: "#d73a49",
+	GanttReload:   "#f0ad4e",
+	GanttOffline:  "#57606a",
+	GanttActive:   "#8250df",
+}
+
+// ganttLegend is the order states appear in the legend
+var ganttLegend = []string{GanttIdle, GanttTracking, GanttEngaging, GanttReload, GanttOffline, GanttActive}
+
+// GanttSpan is a period a lane spent in one state, as offsets from the chart start
+type GanttSpan struct {
+	State string        `json:"state"`
+	Start time.Duration `json:"start"`
+	End   time.Duration `json:"end"`
+}
+
+// GanttLane is one row of the timeline
+type GanttLane struct {
+	Name  string      `json:"name"`
+	Spans []GanttSpan `json:"spans"`
+}
+
+// GanttChart is a complete engagement timeline
+type GanttChart struct {
+	Title    string        `json:"title"`
+	Start    time.Time     `json:"start"`
+	Duration time.Duration `json:"duration"`
+	Waves    []GanttLane   `json:"waves"`
+	Systems  []GanttLane   `json:"systems"`
+}
+
+// GanttRecorder samples lane states during a run and builds the timeline from them
+type GanttRecorder struct {
+	start   time.Time
+	systems map[string]*GanttLane
+	waves   map[string]*GanttLane
+	order   []string // System lanes in first-seen order
+	waveIDs []string // Wave lanes in first-seen order
+	mu      sync.Mutex
+}
+
+// NewGanttRecorder creates a recorder whose timeline starts at start
+func NewGanttRecorder(start time.Time) *GanttRecorder {
+	return &GanttRecorder{
+		start:   start,
+		systems: make(map[string]*GanttLane),
+		waves:   make(map[string]*GanttLane),
+	}
+}
+
+// RecordSystem samples a system's state. Consecutive samples in the same state extend one span.
+func (r *GanttRecorder) RecordSystem(name, state string, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lane, ok := r.systems[name]
+	if !ok {
+		lane = &GanttLane{Name: name}
+		r.systems[name] = lane
+		r.order = append(r.order, name)
+	}
+	r.extend(lane, state, at)
+}
+
+// RecordWave samples whether a wave has threats in the air
+func (r *GanttRecorder) RecordWave(name string, active bool, at time.Time) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	lane, ok := r.waves[name]
+	if !ok {
+		lane = &GanttLane{Name: name}
+		r.waves[name] = lane
+		r.waveIDs = append(r.waveIDs, name)
+	}
+
+	state := ""
+	if active {
+		state = GanttActive
+	}
+	r.extend(lane, state, at)
+}
+
+// extend closes the lane's current span at the sample time and opens a new one on a state change.
+// An empty state leaves a gap.
+func (r *GanttRecorder) extend(lane *GanttLane, state string, at time.Time) {
+	offset := at.Sub(r.start)
+	if n := len(lane.Spans); n > 0 {
+		last := &lane.Spans[n-1]
+		last.End = offset
+		if last.State == state {
+			return
+		}
+	}
+	lane.Spans = append(lane.Spans, GanttSpan{State: state, Start: offset, End: offset})
+}
+
+// Chart returns the recorded timeline ending at end
+func (r *GanttRecorder) Chart(title string, end time.Time) GanttChart {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	chart := GanttChart{Title: title, Start: r.start, Duration: end.Sub(r.start)}
+	for _, name := range r.waveIDs {
+		chart.Waves = append(chart.Waves, compactLane(r.waves[name]))
+	}
+	for _, name := range r.order {
+		chart.Systems = append(chart.Systems, compactLane(r.systems[name]))
+	}
+	return chart
+}
+
+// compactLane copies a lane without gaps or zero-length spans
+func compactLane(lane *GanttLane) GanttLane {
+	out := GanttLane{Name: lane.Name}
+	for _, span := range lane.Spans {
+		if span.State != "" && span.End > span.Start {
+			out.Spans = append(out.Spans, span)
+		}
+	}
+	return out
+}
+
+// StateShare returns the fraction of the chart duration a lane spent in a state
+func (c GanttChart) StateShare(lane GanttLane, state string) float64 {
+	if c.Duration <= 0 {
+		return 0
+	}
+	var total time.Duration
+	for _, span := range lane.Spans {
+		if span.State == state {
+			total += span.End - span.Start
+		}
+	}
+	return float64(total) / float64(c.Duration)
+}
+
+// Gantt layout in pixels
+const (
+	ganttLabelWidth = 170
+	ganttPlotWidth  = 900
+	ganttStatsWidth = 90
+	ganttLaneHeight = 22
+	ganttBarHeight  = 16
+	ganttTopMargin  = 30
+	ganttAxisHeight = 30
+)
+
+// RenderSVG draws the timeline: wave activity bands on top, then one lane per system,
+// with each system's idle share on the right
+func (c GanttChart) RenderSVG() string {
+	lanes := len(c.Waves) + len(c.Systems)
+	gap := 0
+	if len(c.Waves) > 0 && len(c.Systems) > 0 {
+		gap = ganttLaneHeight / 2
+	}
+	width := ganttLabelWidth + ganttPlotWidth + ganttStatsWidth
+	height := ganttTopMargin + lanes*ganttLaneHeight + gap + ganttAxisHeight
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" font-family="Arial, sans-serif" font-size="12">`+"\n", width, height))
+	sb.WriteString(fmt.Sprintf(`<text x="0" y="18" font-size="14" font-weight="bold">%s</text>`+"\n", html.EscapeString(c.Title)))
+	sb.WriteString(fmt.Sprintf(`<text x="%d" y="18" font-weight="bold">idle</text>`+"\n", ganttLabelWidth+ganttPlotWidth+10))
+
+	y := ganttTopMargin
+	for _, lane := range c.Waves {
+		c.renderLane(&sb, lane, y, "")
+		y += ganttLaneHeight
+	}
+	y += gap
+	for _, lane := range c.Systems {
+		c.renderLane(&sb, lane, y, fmt.Sprintf("%.0f%%", c.StateShare(lane, GanttIdle)*100))
+		y += ganttLaneHeight
+	}
+
+	c.renderAxis(&sb, ganttTopMargin, y)
+	sb.WriteString("</svg>\n")
+	return sb.String()
+}
+
+// renderLane draws one labelled row of bars
+func (c GanttChart) renderLane(sb *strings.Builder, lane GanttLane, y int, stat string) {
+	textY := y + ganttBarHeight - 3
+	sb.WriteString(fmt.Sprintf(`<text x="0" y="%d">%s</text>`+"\n", textY, html.EscapeString(lane.Name)))
+	for _, span := range lane.Spans {
+		x := ganttLabelWidth + c.scale(span.Start)
+		w := math.Max(c.scale(span.End)-c.scale(span.Start), 1)
+		sb.WriteString(fmt.Sprintf(`<rect x="%.1f" y="%d" width="%.1f" height="%d" fill="%s"><title>%s %s: %s-%s</title></rect>`+"\n",
+			x, y, w, ganttBarHeight, ganttColors[span.State],
+			html.EscapeString(lane.Name), span.State, formatDuration(span.Start), formatDuration(span.End)))
+	}
+	if stat != "" {
+		sb.WriteString(fmt.Sprintf(`<text x="%d" y="%d">%s</text>`+"\n", ganttLabelWidth+ganttPlotWidth+10, textY, stat))
+	}
+}
+
+// renderAxis draws vertical grid lines and mm:ss labels at a readable interval
+func (c GanttChart) renderAxis(sb *strings.Builder, top, bottom int) {
+	step := ganttTickStep(c.Duration)
+	for t := time.Duration(0); t <= c.Duration; t += step {
+		x := float64(ganttLabelWidth) + c.scale(t)
+		sb.WriteString(fmt.Sprintf(`<line x1="%.1f" y1="%d" x2="%.1f" y2="%d" stroke="#eaeef2"/>`+"\n", x, top, x, bottom))
+		sb.WriteString(fmt.Sprintf(`<text x="%.1f" y="%d" text-anchor="middle" fill="#57606a">%s</text>`+"\n", x, bottom+15, formatDuration(t)))
+	}
+}
+
+// scale converts a time offset to a horizontal position inside the plot area
+func (c GanttChart) scale(d time.Duration) float64 {
+	if c.Duration <= 0 {
+		return 0
+	}
+	return float64(d) / float64(c.Duration) * ganttPlotWidth
+}
+
+// ganttTickStep picks an axis interval giving roughly ten labels
+func ganttTickStep(total time.Duration) time.Duration {
+	for _, step := range []time.Duration{
+		10 * time.Second, 30 * time.Second, time.Minute, 2 * time.Minute,
+		5 * time.Minute, 10 * time.Minute, 30 * time.Minute,
+	} {
+		if total/step <= 10 {
+			return step
+		}
+	}
+	return time.Hour
+}
+
+// SaveGantt writes the timeline as a standalone SVG and an HTML page with a legend.
+// Returns the paths written.
+func SaveGantt(chart GanttChart, outputDir, filename string) ([]string, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	svg := chart.RenderSVG()
+	svgPath := filepath.Join(outputDir, filename+".svg")
+	if err := os.WriteFile(svgPath, []byte(svg), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write timeline SVG: %w", err)
+	}
+
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n")
+	sb.WriteString(fmt.Sprintf("\t<title>%s</title>\n", html.EscapeString(chart.Title)))
+	sb.WriteString("\t<style>body { font-family: Arial, sans-serif; margin: 40px; } .legend span { display: inline-block; margin-right: 16px; } .swatch { display: inline-block; width: 12px; height: 12px; margin-right: 4px; vertical-align: middle; }</style>\n")
+	sb.WriteString("</head>\n<body>\n<div class='legend'>\n")
+	for _, state := range ganttLegend {
+		sb.WriteString(fmt.Sprintf("<span><i class='swatch' style='background:%s'></i>%s</span>\n", ganttColors[state], state))
+	}
+	sb.WriteString("</div>\n")
+	sb.WriteString(svg)
+	sb.WriteString("</body>\n</html>\n")
+
+	htmlPath := filepath.Join(outputDir, filename+".html")
+	if err := os.WriteFile(htmlPath, []byte(sb.String()), 0644); err != nil {
+		return nil, fmt.Errorf("failed to write timeline HTML: %w", err)
+	}
+	return []string{svgPath, htmlPath}, nil
+}
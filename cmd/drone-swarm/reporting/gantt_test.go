@@ -0,0 +1,40 @@
+package reporting
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestGanttRecorderMergesSamples(t *testing.T) {
+	start := time.Unix(0, 0)
+	r := NewGanttRecorder(start)
+	for i, state := range []string{GanttIdle, GanttIdle, GanttEngaging, GanttReload, GanttIdle} {
+		r.RecordSystem("HAWK-01", state, start.Add(time.Duration(i+1)*time.Second))
+	}
+	r.RecordWave("Wave 1", true, start.Add(time.Second))
+	r.RecordWave("Wave 1", true, start.Add(3*time.Second))
+	r.RecordWave("Wave 1", false, start.Add(4*time.Second))
+
+	chart := r.Chart("test", start.Add(5*time.Second))
+	spans := chart.Systems[0].Spans
+	if len(spans) != 3 {
+		t.Fatalf("expected idle, engaging, reload spans, got %+v", spans)
+	}
+	if spans[0].State != GanttIdle || spans[0].Start != time.Second || spans[0].End != 3*time.Second {
+		t.Errorf("unexpected first span: %+v", spans[0])
+	}
+	if share := chart.StateShare(chart.Systems[0], GanttIdle); share != 0.4 {
+		t.Errorf("expected 40%% idle, got %.2f", share)
+	}
+
+	wave := chart.Waves[0].Spans
+	if len(wave) != 1 || wave[0].End != 4*time.Second {
+		t.Errorf("expected one wave band ending when the wave went inactive, got %+v", wave)
+	}
+
+	svg := chart.RenderSVG()
+	if !strings.Contains(svg, "<svg") || !strings.Contains(svg, "HAWK-01") || !strings.Contains(svg, ganttColors[GanttEngaging]) {
+		t.Error("SVG is missing the system lane")
+	}
+}
@@ -0,0 +1,141 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// History defaults
+const (
+	DefaultHistoryRuns = 20  // Archived runs compared when ranking recommendations
+	recurringRuns      = 2   // A deficiency absent from this run is still recommended once it recurs this often
+	axisLeakShare      = 0.4 // Fraction of a run's penetrations on one axis that marks it as a weak axis
+)
+
+// LoadArchive reads the JSON AARs saved in dir, most recent first, keeping at most limit
+// of them (0 keeps all). Files that aren't readable AARs are skipped.
+func LoadArchive(dir string, limit int) ([]*AAR, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "AAR_*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list archived AARs: %w", err)
+	}
+
+	var archive []*AAR
+	for _, path := range paths {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			logger.Debugf("Skipping archived AAR %s: %v", path, err)
+			continue
+		}
+		var aar AAR
+		if err := json.Unmarshal(data, &aar); err != nil || aar.Metadata.GeneratedAt.IsZero() {
+			logger.Debugf("Skipping %s: not an AAR", path)
+			continue
+		}
+		archive = append(archive, &aar)
+	}
+
+	sort.Slice(archive, func(i, j int) bool {
+		return archive[i].Metadata.GeneratedAt.After(archive[j].Metadata.GeneratedAt)
+	})
+	if limit > 0 && len(archive) > limit {
+		archive = archive[:limit]
+	}
+	return archive, nil
+}
+
+// loadHistory reads the configured archive once per generator
+func (g *AARGenerator) loadHistory() {
+	if g.config.HistoryDir == "" || g.history != nil {
+		return
+	}
+	archive, err := LoadArchive(g.config.HistoryDir, 0)
+	if err != nil {
+		logger.Warnf("Ranking recommendations on this run alone: %v", err)
+		return
+	}
+	// A regenerated report compares only the runs that came before it
+	if !g.config.HistoryBefore.IsZero() {
+		earlier := archive[:0]
+		for _, aar := range archive {
+			if aar.Metadata.GeneratedAt.Before(g.config.HistoryBefore) {
+				earlier = append(earlier, aar)
+			}
+		}
+		archive = earlier
+	}
+	if g.config.HistoryRuns > 0 && len(archive) > g.config.HistoryRuns {
+		archive = archive[:g.config.HistoryRuns]
+	}
+	g.history = archive
+	if len(archive) > 0 {
+		logger.Infof("Comparing recommendations against %d earlier runs", len(archive))
+	}
+}
+
+// correlateDeficiencies ranks recommendations by how many of the compared runs share each
+// deficiency. This run's deficiencies are always included; earlier ones only once they
+// recur. Priority follows persistence rather than the single-run threshold.
+func correlateDeficiencies(current []deficiency, history []*AAR) []Recommendation {
+	runs := len(history) + 1
+	seen := make(map[string]int)
+	latest := make(map[string]Recommendation)
+
+	for _, d := range current {
+		seen[d.Key]++
+		latest[d.Key] = d.Recommendation
+	}
+	// History is most recent first, so the first wording found for a key is the newest
+	for _, aar := range history {
+		for _, d := range detectDeficiencies(aar) {
+			seen[d.Key]++
+			if _, ok := latest[d.Key]; !ok {
+				latest[d.Key] = d.Recommendation
+			}
+		}
+	}
+
+	recs := make([]Recommendation, 0, len(latest))
+	for key, rec := range latest {
+		inCurrent := false
+		for _, d := range current {
+			inCurrent = inCurrent || d.Key == key
+		}
+		if !inCurrent && seen[key] < recurringRuns {
+			continue
+		}
+
+		rec.RunsObserved = seen[key]
+		rec.Persistence = float64(seen[key]) / float64(runs)
+		rec.Priority = persistencePriority(rec.Persistence)
+		rec.Description = fmt.Sprintf("%s Seen in %d of the last %d runs.", rec.Description, rec.RunsObserved, runs)
+		if !inCurrent {
+			rec.Description += " Not seen this run."
+		}
+		recs = append(recs, rec)
+	}
+
+	sort.Slice(recs, func(i, j int) bool {
+		if recs[i].Persistence != recs[j].Persistence {
+			return recs[i].Persistence > recs[j].Persistence
+		}
+		return recs[i].Title < recs[j].Title
+	})
+	return recs
+}
+
+// persistencePriority maps the share of runs a deficiency appears in to a priority
+func persistencePriority(persistence float64) string {
+	switch {
+	case persistence >= 0.5:
+		return "High"
+	case persistence >= 0.25:
+		return "Medium"
+	}
+	return "Low"
+}
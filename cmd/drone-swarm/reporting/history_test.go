@@ -0,0 +1,77 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// healthyAAR returns a run with no single-run deficiencies
+func healthyAAR(at time.Time) *AAR {
+	return &AAR{
+		Metadata:       AARMetadata{SimulationID: "run", GeneratedAt: at},
+		Engagements:    EngagementAnalysis{HitRate: 0.6},
+		SystemAnalysis: SystemAnalysis{CommunicationReliability: 1},
+		Performance:    PerformanceAnalysis{SimulationStability: 1},
+	}
+}
+
+func TestRecommendationsRankedByPersistence(t *testing.T) {
+	dir := t.TempDir()
+	start := time.Unix(1_700_000_000, 0)
+
+	// Four earlier runs: the east axis leaks in three of them, targeting was poor once
+	for i := 0; i < 4; i++ {
+		aar := healthyAAR(start.Add(time.Duration(i) * time.Hour))
+		if i != 1 {
+			aar.ThreatAnalysis.Penetrations = 3
+			aar.ThreatAnalysis.PenetrationsByAxis = map[string]int{"E": 3}
+		}
+		if i == 2 {
+			aar.Engagements.HitRate = 0.1
+		}
+		data, err := json.Marshal(aar)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, fmt.Sprintf("AAR_run_%d.json", i)), data, 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.WriteFile(filepath.Join(dir, "AAR_broken.json"), []byte("{"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	archive, err := LoadArchive(dir, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(archive) != 4 || !archive[0].Metadata.GeneratedAt.After(archive[3].Metadata.GeneratedAt) {
+		t.Fatalf("expected 4 archived runs, most recent first, got %d", len(archive))
+	}
+
+	// This run only has low stability, a one-off
+	g := &AARGenerator{history: archive}
+	current := healthyAAR(start.Add(5 * time.Hour))
+	current.Performance.SimulationStability = 0.9
+	recs := g.generateRecommendations(current)
+
+	if len(recs) != 2 {
+		t.Fatalf("expected the recurring east axis and this run's stability, got %+v", recs)
+	}
+	if recs[0].Title != "Reinforce the E Axis" || recs[0].RunsObserved != 3 || recs[0].Priority != "High" {
+		t.Errorf("expected the east axis first, seen in 3 of 5 runs, got %+v", recs[0])
+	}
+	if recs[1].Category != "System Stability" || recs[1].Priority != "Low" {
+		t.Errorf("expected a one-off to rank low, got %+v", recs[1])
+	}
+
+	// Without history the single-run thresholds decide
+	alone := (&AARGenerator{}).generateRecommendations(current)
+	if len(alone) != 1 || alone[0].Priority != "Medium" || alone[0].RunsObserved != 0 {
+		t.Errorf("expected this run's own recommendation, got %+v", alone)
+	}
+}
@@ -0,0 +1,211 @@
+package reporting
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Kill chain milestones, in the order a destroyed track passes through them
+const (
+	MilestoneDetected  = "detected"   // First sensor detection
+	MilestoneHostile   = "hostile"    // First classified HOSTILE
+	MilestoneAssigned  = "assigned"   // First selected as a system's target
+	MilestoneFirstShot = "first_shot" // First engagement
+	MilestoneKilled    = "killed"
+)
+
+// killChainMilestones are the milestones after detection, in order
+var killChainMilestones = []string{MilestoneHostile, MilestoneAssigned, MilestoneFirstShot, MilestoneKilled}
+
+// KillChainAnalysis is the sensor-to-shooter timeline of every destroyed track, the
+// primary measure of how fast a counter-UAS architecture closes its kill chain
+type KillChainAnalysis struct {
+	Kills     int                 `json:"kills"`
+	Stages    []KillChainStage    `json:"stages"`
+	Timelines []KillChainTimeline `json:"timelines"`
+}
+
+// KillChainStage is latency statistics for reaching one milestone from the one before,
+// or for the whole chain from detection to kill. A milestone reached ahead of the one
+// before it, such as a track engaged while still SUSPECTED, counts as no delay.
+type KillChainStage struct {
+	Name  string  `json:"name"`
+	Count int     `json:"count"` // Kills that reached both milestones
+	Mean  float64 `json:"mean_s"`
+	P50   float64 `json:"p50_s"`
+	P90   float64 `json:"p90_s"`
+	P95   float64 `json:"p95_s"`
+	Max   float64 `json:"max_s"`
+}
+
+// KillChainTimeline is one destroyed track's milestones in seconds after first detection.
+// Milestones the track skipped are nil.
+type KillChainTimeline struct {
+	TrackNumber string    `json:"track_number"`
+	Shooter     string    `json:"shooter"`
+	DetectedAt  time.Time `json:"detected_at"`
+	HostileS    *float64  `json:"hostile_s,omitempty"`
+	AssignedS   *float64  `json:"assigned_s,omitempty"`
+	FirstShotS  *float64  `json:"first_shot_s,omitempty"`
+	KilledS     float64   `json:"killed_s"`
+}
+
+// LogKillChain logs a destroyed track's kill chain. milestones holds when each was first
+// reached; detection is required and milestones the track skipped are left out.
+func (sl *SimulationLogger) LogKillChain(trackID uuid.UUID, trackNumber, shooter string, milestones map[string]time.Time) {
+	detected, ok := milestones[MilestoneDetected]
+	if !ok {
+		return
+	}
+	details := map[string]interface{}{
+		"track_number": trackNumber,
+		"shooter":      shooter,
+		"detected_at":  detected,
+	}
+	for _, milestone := range killChainMilestones {
+		if at, ok := milestones[milestone]; ok {
+			details[milestone+"_s"] = at.Sub(detected).Seconds()
+		}
+	}
+
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypeKillChain,
+		Severity:  SeverityInfo,
+		TeamName:  "Counter-UAS",
+		EntityID:  &trackID,
+		Message:   fmt.Sprintf("%s killed by %s %.1fs after first detection", trackNumber, shooter, milestones[MilestoneKilled].Sub(detected).Seconds()),
+		Details:   details,
+	})
+}
+
+// analyzeKillChain collects kill chain events. It returns nil when nothing was destroyed.
+func (g *AARGenerator) analyzeKillChain(events []SimulationEvent) *KillChainAnalysis {
+	var analysis *KillChainAnalysis
+	for _, event := range events {
+		if event.Type != EventTypeKillChain || event.Details == nil {
+			continue
+		}
+		if analysis == nil {
+			analysis = &KillChainAnalysis{}
+		}
+
+		timeline := KillChainTimeline{}
+		timeline.TrackNumber, _ = event.Details["track_number"].(string)
+		timeline.Shooter, _ = event.Details["shooter"].(string)
+		timeline.DetectedAt = detailTime(event.Details, "detected_at")
+		offset := func(milestone string) *float64 {
+			if v, ok := event.Details[milestone+"_s"].(float64); ok {
+				return &v
+			}
+			return nil
+		}
+		timeline.HostileS, timeline.AssignedS, timeline.FirstShotS = offset(MilestoneHostile), offset(MilestoneAssigned), offset(MilestoneFirstShot)
+		if killed := offset(MilestoneKilled); killed != nil {
+			timeline.KilledS = *killed
+		}
+		analysis.Timelines = append(analysis.Timelines, timeline)
+	}
+	if analysis == nil {
+		return nil
+	}
+
+	analysis.Kills = len(analysis.Timelines)
+	analysis.Stages = killChainStages(analysis.Timelines)
+	return analysis
+}
+
+// killChainStages computes the latency of each step of the chain and of the whole chain
+func killChainStages(timelines []KillChainTimeline) []KillChainStage {
+	names := []string{"detect → hostile", "hostile → assign", "assign → first shot", "first shot → kill"}
+	samples := make([][]float64, len(names))
+	var total []float64
+
+	for _, t := range timelines {
+		detected := 0.0
+		milestones := []*float64{&detected, t.HostileS, t.AssignedS, t.FirstShotS, &t.KilledS}
+		reached := 0.0 // Latest milestone so far
+		for i := 1; i < len(milestones); i++ {
+			if milestones[i] == nil {
+				continue
+			}
+			// A step is only timed when the milestone before it was reached too
+			if milestones[i-1] != nil {
+				samples[i-1] = append(samples[i-1], math.Max(0, *milestones[i]-reached))
+			}
+			reached = math.Max(reached, *milestones[i])
+		}
+		total = append(total, t.KilledS)
+	}
+
+	stages := make([]KillChainStage, 0, len(names)+1)
+	for i, name := range names {
+		stages = append(stages, latencyStage(name, samples[i]))
+	}
+	return append(stages, latencyStage("detect → kill", total))
+}
+
+// latencyStage summarizes latency samples in seconds
+func latencyStage(name string, samples []float64) KillChainStage {
+	stage := KillChainStage{Name: name, Count: len(samples)}
+	if len(samples) == 0 {
+		return stage
+	}
+	sorted := append([]float64(nil), samples...)
+	sort.Float64s(sorted)
+
+	var sum float64
+	for _, v := range sorted {
+		sum += v
+	}
+	stage.Mean = sum / float64(len(sorted))
+	stage.P50 = percentile(sorted, 50)
+	stage.P90 = percentile(sorted, 90)
+	stage.P95 = percentile(sorted, 95)
+	stage.Max = sorted[len(sorted)-1]
+	return stage
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	return sorted[max(rank-1, 0)]
+}
+
+// writeKillChainMarkdown renders the kill chain section, with every track's timeline at
+// full detail
+func writeKillChainMarkdown(sb *strings.Builder, kc *KillChainAnalysis, full bool) {
+	sb.WriteString("## Kill Chain\n\n")
+	sb.WriteString(fmt.Sprintf("- **Tracks Destroyed:** %d\n\n", kc.Kills))
+	sb.WriteString("| Stage | Kills | Mean | P50 | P90 | P95 | Max |\n|-------|-------|------|-----|-----|-----|-----|\n")
+	for _, stage := range kc.Stages {
+		if stage.Count == 0 {
+			sb.WriteString(fmt.Sprintf("| %s | 0 | - | - | - | - | - |\n", stage.Name))
+			continue
+		}
+		sb.WriteString(fmt.Sprintf("| %s | %d | %.1fs | %.1fs | %.1fs | %.1fs | %.1fs |\n",
+			stage.Name, stage.Count, stage.Mean, stage.P50, stage.P90, stage.P95, stage.Max))
+	}
+
+	if full {
+		seconds := func(v *float64) string {
+			if v == nil {
+				return "-"
+			}
+			return fmt.Sprintf("%.1fs", *v)
+		}
+		sb.WriteString("\n| Track | Shooter | Detected | Hostile | Assigned | First Shot | Killed |\n")
+		sb.WriteString("|-------|---------|----------|---------|----------|------------|--------|\n")
+		for _, t := range kc.Timelines {
+			sb.WriteString(fmt.Sprintf("| %s | %s | %s | %s | %s | %s | %.1fs |\n",
+				t.TrackNumber, t.Shooter, t.DetectedAt.Format("15:04:05"),
+				seconds(t.HostileS), seconds(t.AssignedS), seconds(t.FirstShotS), t.KilledS))
+		}
+	}
+	sb.WriteString("\n")
+}
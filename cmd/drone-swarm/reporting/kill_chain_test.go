@@ -0,0 +1,62 @@
+package reporting
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestKillChainAnalysis(t *testing.T) {
+	sl := NewSimulationLogger("test")
+	detected := time.Now()
+	at := func(s float64) time.Time { return detected.Add(time.Duration(s * float64(time.Second))) }
+
+	// Ten kills with a growing assignment delay; the last was shot while still SUSPECTED
+	for i := 0; i < 10; i++ {
+		sl.LogKillChain(uuid.New(), "TK-1", "VIPER-1", map[string]time.Time{
+			MilestoneDetected:  detected,
+			MilestoneHostile:   at(2),
+			MilestoneAssigned:  at(2 + float64(i)),
+			MilestoneFirstShot: at(3 + float64(i)),
+			MilestoneKilled:    at(4 + float64(i)),
+		})
+	}
+	sl.LogKillChain(uuid.New(), "TK-2", "HAMMER-2", map[string]time.Time{
+		MilestoneDetected:  detected,
+		MilestoneAssigned:  at(1),
+		MilestoneFirstShot: at(1.5),
+		MilestoneHostile:   at(5),
+		MilestoneKilled:    at(6),
+	})
+	// Never detected: not part of the chain
+	sl.LogKillChain(uuid.New(), "TK-3", "VIPER-1", map[string]time.Time{MilestoneKilled: at(1)})
+
+	g := NewAARGenerator(sl, AARConfig{})
+	kc := g.analyzeKillChain(sl.GetEvents())
+	if kc == nil || kc.Kills != 11 {
+		t.Fatalf("expected 11 kills, got %+v", kc)
+	}
+
+	stages := make(map[string]KillChainStage)
+	for _, stage := range kc.Stages {
+		stages[stage.Name] = stage
+	}
+	assign := stages["hostile → assign"]
+	if assign.Count != 11 || assign.P50 != 4 || assign.P90 != 8 || assign.Max != 9 {
+		t.Errorf("unexpected assignment latency %+v", assign)
+	}
+	if total := stages["detect → kill"]; total.Count != 11 || total.Max != 13 {
+		t.Errorf("unexpected end-to-end latency %+v", total)
+	}
+	if shot := stages["assign → first shot"]; shot.Count != 11 || shot.Mean != (10+0)/11.0 {
+		t.Errorf("expected an engagement ahead of HOSTILE to count as no delay, got %+v", shot)
+	}
+
+	var sb strings.Builder
+	writeKillChainMarkdown(&sb, kc, true)
+	if !strings.Contains(sb.String(), "| hostile → assign | 11 |") || !strings.Contains(sb.String(), "| TK-2 | HAMMER-2 |") {
+		t.Errorf("unexpected markdown:\n%s", sb.String())
+	}
+}
@@ -14,35 +14,37 @@ import (
 type SimulationLogger struct {
 	simulationID string
 	startTime    time.Time
+	endTime      time.Time // Set on a logger restored from an event dump; zero while the run is live
 	events       []SimulationEvent
 	metrics      map[string]Metric
+	onEvent      func(SimulationEvent)
 	mu           sync.RWMutex
 }
 
 // SimulationEvent represents a logged simulation event
 type SimulationEvent struct {
-	Timestamp time.Time
-	Type      string
-	Severity  string
-	TeamName  string
-	EntityID  *uuid.UUID
-	Message   string
-	Details   map[string]interface{}
+	Timestamp time.Time              `json:"timestamp"`
+	Type      string                 `json:"type"`
+	Severity  string                 `json:"severity"`
+	TeamName  string                 `json:"team_name,omitempty"`
+	EntityID  *uuid.UUID             `json:"entity_id,omitempty"`
+	Message   string                 `json:"message"`
+	Details   map[string]interface{} `json:"details,omitempty"`
 }
 
 // Metric represents a tracked metric
 type Metric struct {
-	Name        string
-	Value       float64
-	Unit        string
-	LastUpdated time.Time
-	History     []MetricPoint
+	Name        string        `json:"name"`
+	Value       float64       `json:"value"`
+	Unit        string        `json:"unit"`
+	LastUpdated time.Time     `json:"last_updated"`
+	History     []MetricPoint `json:"history,omitempty"`
 }
 
 // MetricPoint represents a metric value at a point in time
 type MetricPoint struct {
-	Timestamp time.Time
-	Value     float64
+	Timestamp time.Time `json:"timestamp"`
+	Value     float64   `json:"value"`
 }
 
 // EventType constants
@@ -60,6 +62,15 @@ const (
 	EventTypeInterception = "interception"
 	EventTypeThreat       = "threat"
 	EventTypeCommand      = "command"
+	EventTypeInject       = "inject"     // Facilitator change during the run
+	EventTypeStrike       = "strike"     // Counter-battery strike on an estimated launch site
+	EventTypeHazard       = "hazard"     // Debris or noise hazard left by an engagement
+	EventTypeCollateral   = "collateral" // People or neutral traffic inside a hazard
+	EventTypeAdaptation   = "adaptation" // Red force replanning a wave after watching an earlier one
+	EventTypeKillChain    = "kill_chain" // Sensor-to-shooter milestones of a destroyed track
+	EventTypeAct          = "act"        // Transition to the next scenario act
+	EventTypeCue          = "cue"        // Track spawned or moved by an external cue
+	EventTypePause        = "pause"      // Run paused or resumed by the operator
 )
 
 // Severity constants
@@ -174,6 +185,308 @@ func (sl *SimulationLogger) LogTeamStatus(teamName string, activeDrones, totalDr
 	})
 }
 
+// Legion API metrics recorded at the end of a run for the AAR
+const (
+	MetricAPICalls           = "api_calls"
+	MetricAPIPeakPerMinute   = "api_peak_per_minute"
+	MetricAPIBudgetPerMinute = "api_budget_per_minute"
+	MetricAPIBudgetPerRun    = "api_budget_per_run"
+	MetricAPIShedPositions   = "api_shed_positions"
+	MetricAPIShedMetadata    = "api_shed_metadata"
+)
+
+// Process resource metrics recorded at the end of a run for the AAR. CPU is a share of
+// GOMAXPROCS and memory a share of GOMEMLIMIT, recorded only when a limit is set.
+const (
+	MetricCPUUsage       = "cpu_usage"
+	MetricCPUPeak        = "cpu_peak"
+	MetricMemoryUsage    = "memory_usage"
+	MetricHeapPeak       = "heap_peak_mb"
+	MetricSysPeak        = "sys_peak_mb"
+	MetricGoroutinesPeak = "goroutines_peak"
+	MetricGCCycles       = "gc_cycles"
+	MetricGCPauseTotal   = "gc_pause_total_ms"
+	MetricGCPauseMax     = "gc_pause_max_ms"
+	MetricGCPauseP99     = "gc_pause_p99_ms"
+)
+
+// resourceMetrics maps resource metrics to their ResourceUtilization keys
+var resourceMetrics = map[string]string{
+	MetricCPUUsage:       "cpu",
+	MetricCPUPeak:        "cpu_peak",
+	MetricMemoryUsage:    "memory",
+	MetricHeapPeak:       "heap_peak_mb",
+	MetricSysPeak:        "sys_peak_mb",
+	MetricGoroutinesPeak: "goroutines_peak",
+	MetricGCCycles:       "gc_cycles",
+	MetricGCPauseTotal:   "gc_pause_total_ms",
+	MetricGCPauseMax:     "gc_pause_max_ms",
+	MetricGCPauseP99:     "gc_pause_p99_ms",
+}
+
+// Sensor-to-C2 detection reporting metrics recorded at the end of a run for the AAR
+const (
+	MetricReportsDelivered  = "reports_delivered"
+	MetricReportsDropped    = "reports_dropped"
+	MetricReportsHeld       = "reports_held"
+	MetricReportLatencyMean = "report_latency_mean_ms"
+	MetricReportLatencyP95  = "report_latency_p95_ms"
+	MetricReportLatencyMax  = "report_latency_max_ms"
+)
+
+// Update tier metrics recorded at the end of a run for the AAR. Tier shares are of
+// entity-ticks.
+const (
+	MetricUpdatesDeferred  = "updates_deferred"
+	MetricUpdateTierHigh   = "update_tier_high"
+	MetricUpdateTierNormal = "update_tier_normal"
+	MetricUpdateTierLow    = "update_tier_low"
+)
+
+// External cue metrics recorded at the end of a run for the AAR
+const (
+	MetricCuesReceived = "cues_received"
+	MetricCuesRejected = "cues_rejected"
+	MetricCuedTracks   = "cued_tracks"
+)
+
+// Pause metrics: how many times the run was paused and how long it spent paused
+const (
+	MetricPauses     = "pauses"
+	MetricPausedTime = "paused_ms"
+)
+
+// Shutdown barrier metrics: how long the end of the run waited for in-flight updates,
+// and how many were still unsent when it gave up
+const (
+	MetricShutdownWait   = "shutdown_wait_ms"
+	MetricShutdownUnsent = "shutdown_unsent"
+)
+
+// MetricTimeMarkers counts timing markers sent to the range clock feed
+const MetricTimeMarkers = "time_markers_sent"
+
+// MetricDatalinkMessages counts simulated datalink messages sent to the datalink feed
+const MetricDatalinkMessages = "datalink_messages_sent"
+
+// LogInject logs a facilitator adjustment made while the simulation was running
+func (sl *SimulationLogger) LogInject(parameter string, oldValue, newValue float64, source string) {
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypeInject,
+		Severity:  SeverityWarning,
+		Message:   fmt.Sprintf("Inject: %s %g -> %g", parameter, oldValue, newValue),
+		Details: map[string]interface{}{
+			"parameter": parameter,
+			"old_value": oldValue,
+			"new_value": newValue,
+			"source":    source,
+		},
+	})
+}
+
+// LogReload logs a parameter changed by a configuration reload while the simulation was running
+func (sl *SimulationLogger) LogReload(parameter, oldValue, newValue, source string) {
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypeInject,
+		Severity:  SeverityWarning,
+		Message:   fmt.Sprintf("Reload: %s %s -> %s", parameter, oldValue, newValue),
+		Details: map[string]interface{}{
+			"parameter": parameter,
+			"old_value": oldValue,
+			"new_value": newValue,
+			"source":    source,
+		},
+	})
+}
+
+// LogCue logs a track spawned, or moved when spawned is false, by an external cue
+func (sl *SimulationLogger) LogCue(entityID uuid.UUID, track, cueID, source string, spawned bool) {
+	message := fmt.Sprintf("Cue from %s moved %s", source, track)
+	if spawned {
+		message = fmt.Sprintf("Cue from %s spawned %s", source, track)
+	}
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypeCue,
+		Severity:  SeverityInfo,
+		TeamName:  "UAS",
+		EntityID:  &entityID,
+		Message:   message,
+		Details: map[string]interface{}{
+			"track":   track,
+			"cue_id":  cueID,
+			"source":  source,
+			"spawned": spawned,
+		},
+	})
+}
+
+// LogPause logs the run pausing, or resuming when paused is false after the time it
+// was held
+func (sl *SimulationLogger) LogPause(paused bool, held time.Duration, source string) {
+	message := fmt.Sprintf("Run paused by %s", source)
+	if !paused {
+		message = fmt.Sprintf("Run resumed by %s after %s", source, held.Round(time.Second))
+	}
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypePause,
+		Severity:  SeverityWarning,
+		Message:   message,
+		Details: map[string]interface{}{
+			"paused":    paused,
+			"held_secs": held.Seconds(),
+			"source":    source,
+		},
+	})
+}
+
+// LogSystemFailure logs a scripted Counter-UAS system failure, or its recovery when
+// failed is false, with how long the outage lasts (0 for the rest of the run)
+func (sl *SimulationLogger) LogSystemFailure(entityID uuid.UUID, system string, failed bool, outage time.Duration) {
+	message := fmt.Sprintf("Scripted failure: %s offline", system)
+	if !failed {
+		message = fmt.Sprintf("Scripted failure: %s back online after %s", system, outage)
+	}
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypeSystem,
+		Severity:  SeverityWarning,
+		EntityID:  &entityID,
+		Message:   message,
+		Details: map[string]interface{}{
+			"system":      system,
+			"failed":      failed,
+			"scripted":    true,
+			"outage_secs": outage.Seconds(),
+		},
+	})
+}
+
+// LogSystemOverride logs a Counter-UAS system taken offline, or put back in service when
+// offline is false, by a status change made in Legion rather than by the simulation
+func (sl *SimulationLogger) LogSystemOverride(entityID uuid.UUID, system string, offline bool, status string) {
+	message := fmt.Sprintf("Legion override: %s offline", system)
+	if !offline {
+		message = fmt.Sprintf("Legion override: %s back in service (%s)", system, status)
+	}
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypeSystem,
+		Severity:  SeverityWarning,
+		EntityID:  &entityID,
+		Message:   message,
+		Details: map[string]interface{}{
+			"system":        system,
+			"failed":        offline,
+			"legion_status": status,
+		},
+	})
+}
+
+// LogLegionDegraded logs the run losing or regaining Legion. pending is how many entity
+// updates were buffered locally; lasted is how long Legion was out, once it is back.
+func (sl *SimulationLogger) LogLegionDegraded(degraded bool, pending int, lasted time.Duration) {
+	message := fmt.Sprintf("Legion failing: buffering updates locally (%d pending)", pending)
+	severity := SeverityWarning
+	if !degraded {
+		message = fmt.Sprintf("Legion recovered after %s: sending %d buffered updates", lasted.Round(time.Second), pending)
+		severity = SeverityInfo
+	}
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypeSystem,
+		Severity:  severity,
+		Message:   message,
+		Details: map[string]interface{}{
+			"degraded":        degraded,
+			"pending_updates": pending,
+			"outage_seconds":  lasted.Seconds(),
+		},
+	})
+}
+
+// LogStrike logs a counter-battery strike. site is empty when the strike missed.
+func (sl *SimulationLogger) LogStrike(site string, hit bool, missDistance float64, launchesPrevented, lines int) {
+	message := fmt.Sprintf("Counter-battery strike missed by %.0fm", missDistance)
+	if hit {
+		message = fmt.Sprintf("Counter-battery strike suppressed launch site %s", site)
+	}
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypeStrike,
+		Severity:  SeverityWarning,
+		TeamName:  "Counter-UAS",
+		Message:   message,
+		Details: map[string]interface{}{
+			"site":               site,
+			"hit":                hit,
+			"miss_distance_m":    missDistance,
+			"launches_prevented": launchesPrevented,
+			"lines_of_bearing":   lines,
+		},
+	})
+}
+
+// LogHazard logs a hazard area opened by an engagement
+func (sl *SimulationLogger) LogHazard(kind, source, track string, radius float64) {
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypeHazard,
+		Severity:  SeverityInfo,
+		TeamName:  "Counter-UAS",
+		Message:   fmt.Sprintf("%s engagement of %s opened a %.0fm %s hazard", source, track, radius, kind),
+		Details: map[string]interface{}{
+			"hazard":   kind,
+			"source":   source,
+			"track":    track,
+			"radius_m": radius,
+		},
+	})
+}
+
+// LogCollateral logs a populated area or neutral track found inside a hazard
+func (sl *SimulationLogger) LogCollateral(hazard, subject, subjectKind, source string, distance float64) {
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypeCollateral,
+		Severity:  SeverityWarning,
+		TeamName:  "Counter-UAS",
+		Message:   fmt.Sprintf("%s inside the %s hazard from %s", subject, hazard, source),
+		Details: map[string]interface{}{
+			"hazard":     hazard,
+			"subject":    subject,
+			"kind":       subjectKind,
+			"source":     source,
+			"distance_m": distance,
+		},
+	})
+}
+
+// LogAdaptation logs red replanning a later wave after losing attrition (0-1) of an
+// earlier one, mostly on lethalAxis
+func (sl *SimulationLogger) LogAdaptation(faction string, observedWave, wave int, attrition float64, lethalAxis, ingressAxis string, ingressHeight float64, hold time.Duration) {
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypeAdaptation,
+		Severity:  SeverityWarning,
+		TeamName:  faction,
+		Message: fmt.Sprintf("Wave %d reroutes via the %s at %.0fm after wave %d lost %.0f%% to the %s",
+			wave, ingressAxis, ingressHeight, observedWave, attrition*100, lethalAxis),
+		Details: map[string]interface{}{
+			"observed_wave":    observedWave,
+			"wave":             wave,
+			"attrition":        attrition,
+			"lethal_axis":      lethalAxis,
+			"ingress_axis":     ingressAxis,
+			"ingress_height_m": ingressHeight,
+			"hold_s":           hold.Seconds(),
+		},
+	})
+}
+
 // LogError logs an error event
 func (sl *SimulationLogger) LogError(message string, err error, details map[string]interface{}) {
 	if details == nil {
@@ -249,6 +562,9 @@ func (sl *SimulationLogger) GetSummary() SimulationSummary {
 	defer sl.mu.RUnlock()
 
 	duration := time.Since(sl.startTime)
+	if !sl.endTime.IsZero() {
+		duration = sl.endTime.Sub(sl.startTime)
+	}
 
 	// Count events by type
 	eventCounts := make(map[string]int)
@@ -287,6 +603,26 @@ type SimulationSummary struct {
 	Metrics      map[string]Metric
 }
 
+// LogFactionOutcome logs an attacking faction's final strength. Team analysis in the AAR
+// reads the last status logged for each team.
+func (sl *SimulationLogger) LogFactionOutcome(teamName string, launched, destroyed, reachedObjective, interference int) {
+	sl.logEvent(SimulationEvent{
+		Timestamp: time.Now(),
+		Type:      EventTypeTeamStatus,
+		Severity:  SeverityInfo,
+		TeamName:  teamName,
+		Message: fmt.Sprintf("Team %s: %d launched, %d destroyed, %d reached objective",
+			teamName, launched, destroyed, reachedObjective),
+		Details: map[string]interface{}{
+			"active_drones":     launched - destroyed,
+			"total_drones":      launched,
+			"losses":            destroyed,
+			"reached_objective": reachedObjective,
+			"interference":      interference,
+		},
+	})
+}
+
 // LogWaveLaunch logs a wave launch event
 func (sl *SimulationLogger) LogWaveLaunch(teamName string, waveNumber int, droneCount int, details map[string]interface{}) {
 	sl.logEvent(SimulationEvent{
@@ -386,14 +722,25 @@ func (sl *SimulationLogger) LogThreatAssessment(teamName string, threatLevel str
 // logEvent adds an event to the log
 func (sl *SimulationLogger) logEvent(event SimulationEvent) {
 	sl.mu.Lock()
-	defer sl.mu.Unlock()
-
 	sl.events = append(sl.events, event)
 
 	// Keep only last 10000 events to prevent memory issues
 	if len(sl.events) > 10000 {
 		sl.events = sl.events[len(sl.events)-10000:]
 	}
+	onEvent := sl.onEvent
+	sl.mu.Unlock()
+
+	if onEvent != nil {
+		onEvent(event)
+	}
+}
+
+// OnEvent calls fn with every event as it is logged. fn must not block.
+func (sl *SimulationLogger) OnEvent(fn func(SimulationEvent)) {
+	sl.mu.Lock()
+	defer sl.mu.Unlock()
+	sl.onEvent = fn
 }
 
 // logColoredMessage logs a message with color based on severity
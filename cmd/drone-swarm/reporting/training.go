@@ -0,0 +1,120 @@
+package reporting
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+)
+
+// Why a decision point was selected for the training package
+const (
+	ReasonCloseCall    = "close_call"    // Runner-up scored within the margin of the chosen track
+	ReasonPassedLeaker = "passed_leaker" // A track passed over at this moment later reached the target
+)
+
+// DecisionCandidate is one track a system could have engaged, as the system saw it
+type DecisionCandidate struct {
+	TrackID        string  `json:"track_id"`
+	TrackNumber    string  `json:"track_number"`
+	Classification string  `json:"classification"`
+	ThreatLevel    int     `json:"threat_level"`
+	RangeKm        float64 `json:"range_km"`
+	TimeToImpactS  float64 `json:"time_to_impact_s,omitempty"` // 0 when intent was unknown
+	Score          float64 `json:"score"`
+	Chosen         bool    `json:"chosen"`
+	Leaked         bool    `json:"leaked"` // Filled in after the run
+}
+
+// DecisionPoint is a target selection with competing tracks and how it turned out
+type DecisionPoint struct {
+	Time       time.Time           `json:"time"`
+	Elapsed    string              `json:"elapsed"`
+	System     string              `json:"system"`
+	Engagement string              `json:"engagement_type"`
+	Candidates []DecisionCandidate `json:"candidates"` // Highest score first
+	Margin     float64             `json:"margin"`     // Chosen score minus runner-up
+	Outcome    string              `json:"outcome"`    // hit or miss
+	Reasons    []string            `json:"reasons"`
+}
+
+// TrainingPackage is the trainee debrief set written alongside the AAR
+type TrainingPackage struct {
+	RunID          string          `json:"run_id"`
+	Simulation     string          `json:"simulation"`
+	Generated      time.Time       `json:"generated"`
+	CloseCall      float64         `json:"close_call_margin"`
+	TotalDecisions int             `json:"total_decisions"`
+	DecisionPoints []DecisionPoint `json:"decision_points"`
+}
+
+// SelectDecisionPoints marks leaked candidates and keeps the decisions worth debriefing:
+// close calls and those that passed over an eventual leaker. Leaker decisions are kept
+// first when over limit, then the closest calls. The result is in time order.
+func SelectDecisionPoints(decisions []DecisionPoint, leaked map[string]bool, margin float64, limit int) []DecisionPoint {
+	var selected []DecisionPoint
+	for _, d := range decisions {
+		d.Candidates = append([]DecisionCandidate(nil), d.Candidates...)
+		d.Reasons = nil
+
+		passedLeaker := false
+		for i := range d.Candidates {
+			d.Candidates[i].Leaked = leaked[d.Candidates[i].TrackID]
+			if d.Candidates[i].Leaked && !d.Candidates[i].Chosen {
+				passedLeaker = true
+			}
+		}
+		if d.Margin <= margin {
+			d.Reasons = append(d.Reasons, ReasonCloseCall)
+		}
+		if passedLeaker {
+			d.Reasons = append(d.Reasons, ReasonPassedLeaker)
+		}
+		if len(d.Reasons) > 0 {
+			selected = append(selected, d)
+		}
+	}
+
+	if limit > 0 && len(selected) > limit {
+		sort.SliceStable(selected, func(i, j int) bool {
+			li, lj := hasReason(selected[i], ReasonPassedLeaker), hasReason(selected[j], ReasonPassedLeaker)
+			if li != lj {
+				return li
+			}
+			return selected[i].Margin < selected[j].Margin
+		})
+		selected = selected[:limit]
+	}
+
+	sort.SliceStable(selected, func(i, j int) bool { return selected[i].Time.Before(selected[j].Time) })
+	return selected
+}
+
+func hasReason(d DecisionPoint, reason string) bool {
+	for _, r := range d.Reasons {
+		if r == reason {
+			return true
+		}
+	}
+	return false
+}
+
+// SaveTrainingPackage writes the package as JSON for debrief tools and returns the path
+func SaveTrainingPackage(pkg TrainingPackage, outputDir, filename string) (string, error) {
+	if err := os.MkdirAll(outputDir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create output directory: %w", err)
+	}
+
+	data, err := json.MarshalIndent(pkg, "", "  ")
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal training package: %w", err)
+	}
+
+	path := filepath.Join(outputDir, filename+".json")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return "", fmt.Errorf("failed to write training package: %w", err)
+	}
+	return path, nil
+}
@@ -0,0 +1,43 @@
+package reporting
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSelectDecisionPoints(t *testing.T) {
+	start := time.Unix(0, 0)
+	decision := func(sec int, margin float64, passed string) DecisionPoint {
+		return DecisionPoint{
+			Time:   start.Add(time.Duration(sec) * time.Second),
+			Margin: margin,
+			Candidates: []DecisionCandidate{
+				{TrackID: "chosen", Chosen: true},
+				{TrackID: passed},
+			},
+		}
+	}
+	decisions := []DecisionPoint{
+		decision(1, 0.50, "a"), // Clear choice, runner-up destroyed: dropped
+		decision(2, 0.02, "b"), // Close call
+		decision(3, 0.40, "c"), // Runner-up leaked
+		decision(4, 0.01, "d"), // Closest call
+	}
+	leaked := map[string]bool{"c": true}
+
+	all := SelectDecisionPoints(decisions, leaked, 0.05, 0)
+	if len(all) != 3 {
+		t.Fatalf("expected 3 decision points, got %d", len(all))
+	}
+	if !all[1].Candidates[1].Leaked || all[1].Reasons[0] != ReasonPassedLeaker {
+		t.Errorf("expected passed leaker at 3s, got %+v", all[1])
+	}
+	if decisions[2].Candidates[1].Leaked {
+		t.Error("input decisions should not be modified")
+	}
+
+	limited := SelectDecisionPoints(decisions, leaked, 0.05, 2)
+	if len(limited) != 2 || limited[0].Margin != 0.40 || limited[1].Margin != 0.01 {
+		t.Errorf("expected leaker then closest call in time order, got %+v", limited)
+	}
+}
@@ -0,0 +1,381 @@
+// Package scenario reads declarative drone-swarm scenario files. A scenario lays down
+// the defense, the raid and the troops on the ground, scripts wave launches and system
+// failures, and sets the rules that end the run. It compiles to the simulation's
+// parameters, so new scenarios need no code changes.
+package scenario
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SimulationName is the simulation scenarios run on
+const SimulationName = "Drone Swarm Combat"
+
+// Scenario is a scenario file
+type Scenario struct {
+	Name        string                 `yaml:"name"`
+	Description string                 `yaml:"description"`
+	Location    Location               `yaml:"location"`
+	Laydown     Laydown                `yaml:"laydown"`
+	Events      []Event                `yaml:"events"`
+	Termination Termination            `yaml:"termination"`
+	Parameters  map[string]interface{} `yaml:"parameters"` // Any other simulation parameters
+}
+
+// Location is the defended base, by coordinates or MGRS grid
+type Location struct {
+	Latitude  *float64 `yaml:"latitude"`
+	Longitude *float64 `yaml:"longitude"`
+	Altitude  *float64 `yaml:"altitude"` // Meters MSL
+	MGRS      string   `yaml:"mgrs"`
+}
+
+// Laydown places the forces around the base
+type Laydown struct {
+	Systems     []System     `yaml:"systems"`
+	Threats     Threats      `yaml:"threats"`
+	GroundUnits []GroundUnit `yaml:"ground_units"`
+}
+
+// System is a Counter-UAS system at a fixed post
+type System struct {
+	Name       string  `yaml:"name"`
+	Kind       string  `yaml:"kind"` // kinetic or ew
+	DistanceM  float64 `yaml:"distance_m"`
+	BearingDeg float64 `yaml:"bearing_deg"`
+}
+
+// Threats is the raid: how many drones in how many waves, and where they launch from
+type Threats struct {
+	Count       int          `yaml:"count"`
+	Waves       int          `yaml:"waves"`
+	Formation   string       `yaml:"formation"` // distributed, concentrated or waves
+	LaunchSites []LaunchSite `yaml:"launch_sites"`
+}
+
+// LaunchSite is where raids launch and assemble
+type LaunchSite struct {
+	Name       string  `yaml:"name"`
+	DistanceKm float64 `yaml:"distance_km"`
+	BearingDeg float64 `yaml:"bearing_deg"`
+	RatePerMin float64 `yaml:"rate_per_min"`
+	OrbitM     float64 `yaml:"orbit_m"` // Assembly orbit radius (0 uses the default)
+}
+
+// GroundUnit is a friendly unit posted around the base
+type GroundUnit struct {
+	Name       string   `yaml:"name"`
+	Kind       string   `yaml:"kind"` // infantry or vehicle
+	DistanceM  float64  `yaml:"distance_m"`
+	BearingDeg float64  `yaml:"bearing_deg"`
+	Strength   int      `yaml:"strength"` // Personnel (0 uses the kind's default)
+	PatrolM    *float64 `yaml:"patrol_m"` // Patrol loop radius, given with strength (unset uses the kind's default)
+}
+
+// Event is a scripted event: a wave launch or a system failure at a scenario time
+type Event struct {
+	At            string `yaml:"at"`             // From the scenario start, e.g. 90s
+	Wave          int    `yaml:"wave"`           // Wave to launch
+	SystemFailure string `yaml:"system_failure"` // System to take offline
+	Duration      string `yaml:"duration"`       // Outage length (empty for the rest of the run)
+}
+
+// Termination is what ends the run besides its duration running out
+type Termination struct {
+	Duration             string   `yaml:"duration"`
+	AcceptableLeakage    *float64 `yaml:"acceptable_leakage"`     // Fraction of all threats allowed through
+	CriticalAssetLeakers *int     `yaml:"critical_asset_leakers"` // Leakers on the base that end the run
+	WaveLeakageThreshold *float64 `yaml:"wave_leakage_threshold"` // Fraction of one wave allowed through
+}
+
+// Load reads and checks a scenario file. Every problem found is reported.
+func Load(path string) (*Scenario, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read scenario: %w", err)
+	}
+
+	var sc Scenario
+	dec := yaml.NewDecoder(bytes.NewReader(data))
+	dec.KnownFields(true)
+	if err := dec.Decode(&sc); err != nil {
+		return nil, fmt.Errorf("failed to parse scenario %s: %w", path, err)
+	}
+	if err := sc.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid scenario %s:\n%w", path, err)
+	}
+	return &sc, nil
+}
+
+// Validate reports every problem with the scenario
+func (sc *Scenario) Validate() error {
+	var errs []error
+	fail := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if sc.Name == "" {
+		fail("name is required")
+	}
+	if (sc.Location.Latitude == nil) != (sc.Location.Longitude == nil) {
+		fail("location needs both latitude and longitude")
+	}
+
+	systems := make(map[string]bool)
+	if len(sc.Laydown.Systems) == 0 {
+		fail("laydown.systems: at least one system is required")
+	}
+	for i, system := range sc.Laydown.Systems {
+		field := fmt.Sprintf("laydown.systems[%d]", i)
+		switch {
+		case system.Name == "":
+			fail("%s: name is required", field)
+		case strings.ContainsAny(system.Name, ":;"):
+			fail("%s: name %q cannot contain ':' or ';'", field, system.Name)
+		case systems[system.Name]:
+			fail("%s: system %s is given twice", field, system.Name)
+		}
+		systems[system.Name] = true
+		if kind := strings.ToLower(system.Kind); kind != "kinetic" && kind != "ew" {
+			fail("%s: kind must be kinetic or ew", field)
+		}
+		if system.DistanceM < 0 {
+			fail("%s: distance_m cannot be negative", field)
+		}
+	}
+
+	threats := sc.Laydown.Threats
+	if threats.Count < 1 {
+		fail("laydown.threats.count must be at least 1")
+	}
+	if threats.Waves < 1 {
+		fail("laydown.threats.waves must be at least 1")
+	} else if threats.Count >= 1 && threats.Waves > threats.Count {
+		fail("laydown.threats: %d waves need at least %d threats", threats.Waves, threats.Waves)
+	}
+	for i, site := range threats.LaunchSites {
+		field := fmt.Sprintf("laydown.threats.launch_sites[%d]", i)
+		if site.Name == "" || strings.ContainsAny(site.Name, ":;") {
+			fail("%s: a name without ':' or ';' is required", field)
+		}
+		if site.DistanceKm <= 0 || site.RatePerMin <= 0 || site.OrbitM < 0 {
+			fail("%s: distance_km and rate_per_min must be positive", field)
+		}
+	}
+	for i, unit := range sc.Laydown.GroundUnits {
+		field := fmt.Sprintf("laydown.ground_units[%d]", i)
+		if unit.Name == "" || strings.ContainsAny(unit.Name, ":;") {
+			fail("%s: a name without ':' or ';' is required", field)
+		}
+		if kind := strings.ToLower(unit.Kind); kind != "infantry" && kind != "vehicle" {
+			fail("%s: kind must be infantry or vehicle", field)
+		}
+		if unit.Strength < 0 || (unit.PatrolM != nil && (unit.Strength == 0 || *unit.PatrolM < 0)) {
+			fail("%s: patrol_m needs a strength and neither can be negative", field)
+		}
+	}
+
+	waves := make(map[int]bool)
+	for i, event := range sc.Events {
+		field := fmt.Sprintf("events[%d]", i)
+		if _, err := parseTime(event.At); err != nil {
+			fail("%s: at: %v", field, err)
+		}
+		switch {
+		case (event.Wave != 0) == (event.SystemFailure != ""):
+			fail("%s: give exactly one of wave or system_failure", field)
+		case event.Wave != 0:
+			if event.Wave < 1 || event.Wave > threats.Waves {
+				fail("%s: wave %d is not one of the %d waves", field, event.Wave, threats.Waves)
+			} else if waves[event.Wave] {
+				fail("%s: wave %d is launched twice", field, event.Wave)
+			}
+			waves[event.Wave] = true
+			if event.Duration != "" {
+				fail("%s: duration only applies to system failures", field)
+			}
+		default:
+			if !systems[event.SystemFailure] {
+				fail("%s: system_failure names %s, which is not in laydown.systems", field, event.SystemFailure)
+			}
+			if event.Duration != "" {
+				if d, err := parseTime(event.Duration); err != nil || d == 0 {
+					fail("%s: duration must be a positive time, e.g. 90s", field)
+				}
+			}
+		}
+	}
+	if len(waves) > 0 {
+		for wave := 1; wave <= threats.Waves; wave++ {
+			if !waves[wave] {
+				fail("events: wave %d has no launch event; schedule every wave or none", wave)
+			}
+		}
+	}
+
+	term := sc.Termination
+	if term.Duration != "" {
+		if d, err := parseTime(term.Duration); err != nil || d == 0 {
+			fail("termination.duration must be a positive time, e.g. 10m")
+		}
+	}
+	for name, value := range map[string]*float64{"acceptable_leakage": term.AcceptableLeakage, "wave_leakage_threshold": term.WaveLeakageThreshold} {
+		if value != nil && (*value < 0 || *value > 1) {
+			fail("termination.%s must be between 0 and 1", name)
+		}
+	}
+	if term.CriticalAssetLeakers != nil && *term.CriticalAssetLeakers < 0 {
+		fail("termination.critical_asset_leakers cannot be negative")
+	}
+
+	set := sc.compiled()
+	for _, name := range sortedKeys(sc.Parameters) {
+		if _, ok := set[name]; ok {
+			fail("parameters.%s is set by the scenario itself", name)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Params compiles the scenario to simulation parameters, with values as a parameters
+// file would give them
+func (sc *Scenario) Params() map[string]interface{} {
+	params := sc.compiled()
+	for name, value := range sc.Parameters {
+		if _, ok := params[name]; !ok {
+			params[name] = value
+		}
+	}
+	return params
+}
+
+// compiled is the parameters the scenario's own sections set
+func (sc *Scenario) compiled() map[string]interface{} {
+	params := make(map[string]interface{})
+
+	loc := sc.Location
+	if loc.Latitude != nil && loc.Longitude != nil {
+		params["center_latitude"], params["center_longitude"] = *loc.Latitude, *loc.Longitude
+	}
+	if loc.Altitude != nil {
+		params["center_altitude"] = *loc.Altitude
+	}
+	if loc.MGRS != "" {
+		params["center_mgrs"] = loc.MGRS
+	}
+
+	sites := make([]string, len(sc.Laydown.Systems))
+	for i, system := range sc.Laydown.Systems {
+		sites[i] = fmt.Sprintf("%s:%s:%s:%s", system.Name, strings.ToLower(system.Kind), number(system.DistanceM), number(system.BearingDeg))
+	}
+	params["defense_sites"] = strings.Join(sites, ";")
+	params["num_counter_uas_systems"] = len(sc.Laydown.Systems)
+
+	threats := sc.Laydown.Threats
+	params["num_uas_threats"] = threats.Count
+	params["waves"] = threats.Waves
+	if threats.Formation != "" {
+		params["swarm_formation_type"] = threats.Formation
+	}
+	if len(threats.LaunchSites) > 0 {
+		launch := make([]string, len(threats.LaunchSites))
+		for i, site := range threats.LaunchSites {
+			launch[i] = fmt.Sprintf("%s:%s:%s:%s", site.Name, number(site.DistanceKm), number(site.BearingDeg), number(site.RatePerMin))
+			if site.OrbitM > 0 {
+				launch[i] += ":" + number(site.OrbitM)
+			}
+		}
+		params["launch_sites"] = strings.Join(launch, ";")
+	}
+
+	if len(sc.Laydown.GroundUnits) > 0 {
+		units := make([]string, len(sc.Laydown.GroundUnits))
+		for i, unit := range sc.Laydown.GroundUnits {
+			units[i] = fmt.Sprintf("%s:%s:%s:%s", unit.Name, strings.ToLower(unit.Kind), number(unit.DistanceM), number(unit.BearingDeg))
+			if unit.Strength > 0 {
+				units[i] += ":" + strconv.Itoa(unit.Strength)
+			}
+			if unit.PatrolM != nil {
+				units[i] += ":" + number(*unit.PatrolM)
+			}
+		}
+		params["ground_units"] = strings.Join(units, ";")
+	}
+
+	// Scripted events, each wave's launch in wave order
+	waveTimes := make([]string, threats.Waves)
+	scheduled := false
+	var failures []string
+	for _, event := range sc.Events {
+		at, _ := parseTime(event.At)
+		switch {
+		case event.Wave >= 1 && event.Wave <= threats.Waves:
+			waveTimes[event.Wave-1] = at.String()
+			scheduled = true
+		case event.SystemFailure != "":
+			failure := event.SystemFailure + ":" + at.String()
+			if d, err := parseTime(event.Duration); err == nil && d > 0 {
+				failure += ":" + d.String()
+			}
+			failures = append(failures, failure)
+		}
+	}
+	if scheduled {
+		params["wave_times"] = strings.Join(waveTimes, ",")
+	}
+	if len(failures) > 0 {
+		params["system_failures"] = strings.Join(failures, ";")
+	}
+
+	term := sc.Termination
+	if term.Duration != "" {
+		params["duration"] = term.Duration
+	}
+	if term.AcceptableLeakage != nil {
+		params["acceptable_leakage"] = *term.AcceptableLeakage
+	}
+	if term.CriticalAssetLeakers != nil {
+		params["critical_asset_leakers"] = *term.CriticalAssetLeakers
+	}
+	if term.WaveLeakageThreshold != nil {
+		params["wave_leakage_threshold"] = *term.WaveLeakageThreshold
+	}
+	return params
+}
+
+// parseTime parses a scenario time or duration such as 90s or 4m30s
+func parseTime(value string) (time.Duration, error) {
+	if value == "" {
+		return 0, fmt.Errorf("a time is required, e.g. 90s")
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d < 0 {
+		return 0, fmt.Errorf("invalid time %q, expected e.g. 90s or 4m30s", value)
+	}
+	return d, nil
+}
+
+// number formats a float without trailing zeros
+func number(v float64) string {
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// sortedKeys returns a map's keys in order, so problems are reported stably
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
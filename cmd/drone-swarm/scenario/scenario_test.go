@@ -0,0 +1,70 @@
+package scenario
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLoadExample(t *testing.T) {
+	sc, err := Load("../examples/scenario-northern-raid.yaml")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+
+	params := sc.Params()
+	want := map[string]interface{}{
+		"defense_sites":           "North Gun:kinetic:1200:0;North-East Gun:kinetic:1000:45;North-West Gun:kinetic:1000:315;Base Jammer:ew:200:0;South Jammer:ew:800:180",
+		"num_counter_uas_systems": 5,
+		"num_uas_threats":         24,
+		"waves":                   3,
+		"launch_sites":            "Ridge:8:350:6;Farm:6:30:4:300",
+		"ground_units":            "1st Squad:infantry:600:10;Motor Pool:vehicle:400:200",
+		"wave_times":              "0s,1m30s,4m0s",
+		"system_failures":         "North-East Gun:2m0s:1m0s",
+		"duration":                "8m",
+		"critical_asset_leakers":  3,
+		"update_interval":         "2s",
+	}
+	for name, value := range want {
+		if params[name] != value {
+			t.Errorf("%s = %v, want %v", name, params[name], value)
+		}
+	}
+}
+
+func TestValidateReportsEveryProblem(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bad.yaml")
+	if err := os.WriteFile(path, []byte(`
+name: Bad
+laydown:
+  systems:
+    - {name: "Gun", kind: laser, distance_m: 100, bearing_deg: 0}
+  threats: {count: 10, waves: 2}
+events:
+  - {at: 30s, wave: 1}
+  - {at: 1m, system_failure: "Jammer"}
+parameters:
+  waves: 4
+`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := Load(path)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	for _, problem := range []string{"kind must be kinetic or ew", "wave 2 has no launch event", "names Jammer", "parameters.waves"} {
+		if !strings.Contains(err.Error(), problem) {
+			t.Errorf("expected %q in %v", problem, err)
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("name: Typo\nlaydwon: {}\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Load(path); err == nil || !strings.Contains(err.Error(), "laydwon") {
+		t.Errorf("expected unknown field error, got %v", err)
+	}
+}
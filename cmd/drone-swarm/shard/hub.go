@@ -0,0 +1,114 @@
+package shard
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Hub is the coordinator's view of every shard's tracks
+type Hub struct {
+	tracks   map[uuid.UUID]TrackSnapshot
+	kills    map[int][]Kill // Pending kills keyed by owning shard
+	leakage  map[int]Leakage
+	lastSeen map[int]time.Time
+	token    string
+	mu       sync.Mutex
+}
+
+// NewHub creates an empty coordinator hub. A non-empty token must be presented as a
+// bearer token on every sync; empty lets anyone who can reach the hub sync.
+func NewHub(token string) *Hub {
+	return &Hub{
+		token:    token,
+		tracks:   make(map[uuid.UUID]TrackSnapshot),
+		kills:    make(map[int][]Kill),
+		leakage:  make(map[int]Leakage),
+		lastSeen: make(map[int]time.Time),
+	}
+}
+
+// Sync merges a shard's tracks, kills and leakers and returns the state it doesn't own
+func (h *Hub) Sync(req SyncRequest) SyncResponse {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.lastSeen[req.Shard] = time.Now()
+	h.leakage[req.Shard] = req.Leakage // Running totals, so the latest replaces the last
+
+	for _, track := range req.Tracks {
+		track.Shard = req.Shard
+		h.tracks[track.ID] = track
+	}
+
+	// Route kills to the shard that owns the target
+	for _, kill := range req.Kills {
+		kill.ByShard = req.Shard
+		if owner, ok := h.tracks[kill.TrackID]; ok && owner.Shard != req.Shard {
+			h.kills[owner.Shard] = append(h.kills[owner.Shard], kill)
+		}
+	}
+
+	resp := SyncResponse{
+		Tracks: make([]TrackSnapshot, 0, len(h.tracks)),
+		Kills:  h.kills[req.Shard],
+	}
+	delete(h.kills, req.Shard)
+
+	for _, track := range h.tracks {
+		if track.Shard != req.Shard {
+			resp.Tracks = append(resp.Tracks, track)
+		}
+	}
+	for shard, leakage := range h.leakage {
+		if shard != req.Shard {
+			resp.Leakage.add(leakage)
+		}
+	}
+	for shard := range h.lastSeen {
+		resp.Shards = append(resp.Shards, shard)
+	}
+	sort.Ints(resp.Shards)
+
+	return resp
+}
+
+// Handler serves the sync protocol over HTTP
+func (h *Hub) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/sync", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		if !h.authorized(r) {
+			http.Error(w, "missing or invalid shard token", http.StatusUnauthorized)
+			return
+		}
+
+		var req SyncRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid sync request: "+err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(h.Sync(req))
+	})
+	return mux
+}
+
+// authorized checks the bearer token when one is configured
+func (h *Hub) authorized(r *http.Request) bool {
+	if h.token == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(h.token)) == 1
+}
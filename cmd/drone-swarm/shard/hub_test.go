@@ -0,0 +1,59 @@
+package shard
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestHubSyncRoutesTracksAndKills(t *testing.T) {
+	hub := NewHub("")
+	redTrack := uuid.New()
+
+	// Worker publishes its track
+	resp := hub.Sync(SyncRequest{Shard: 1, Tracks: []TrackSnapshot{{ID: redTrack, TrackNumber: "TK-10001", Wave: 2}}})
+	if len(resp.Tracks) != 0 {
+		t.Fatalf("worker should not receive its own tracks, got %d", len(resp.Tracks))
+	}
+
+	// Coordinator sees it and scores a kill
+	resp = hub.Sync(SyncRequest{Shard: 0, Kills: []Kill{{TrackID: redTrack, By: "HAWK-01"}}})
+	if len(resp.Tracks) != 1 || resp.Tracks[0].Shard != 1 {
+		t.Fatalf("expected coordinator to receive shard 1's track, got %+v", resp.Tracks)
+	}
+
+	// Worker receives the kill exactly once
+	resp = hub.Sync(SyncRequest{Shard: 1})
+	if len(resp.Kills) != 1 || resp.Kills[0].ByShard != 0 {
+		t.Fatalf("expected one kill from shard 0, got %+v", resp.Kills)
+	}
+	if resp = hub.Sync(SyncRequest{Shard: 1}); len(resp.Kills) != 0 {
+		t.Fatalf("kills should be delivered once, got %+v", resp.Kills)
+	}
+}
+
+func TestOwnerOfWave(t *testing.T) {
+	for wave, expected := range map[int]int{1: 0, 2: 1, 3: 2, 4: 0, 5: 1} {
+		if got := OwnerOfWave(wave, 3); got != expected {
+			t.Errorf("wave %d: expected shard %d, got %d", wave, expected, got)
+		}
+	}
+}
+
+func TestHubRequiresToken(t *testing.T) {
+	server := httptest.NewServer(NewHub("s3cret").Handler())
+	defer server.Close()
+
+	ctx := context.Background()
+	for _, token := range []string{"", "wrong"} {
+		if _, err := NewClient(server.URL, token).Sync(ctx, SyncRequest{Shard: 1}); err == nil || !strings.Contains(err.Error(), "401") {
+			t.Errorf("token %q: expected the sync to be refused, got %v", token, err)
+		}
+	}
+	if _, err := NewClient(server.URL, "s3cret").Sync(ctx, SyncRequest{Shard: 1}); err != nil {
+		t.Errorf("expected the shared token to be accepted, got %v", err)
+	}
+}
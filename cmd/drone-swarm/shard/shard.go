@@ -0,0 +1,85 @@
+// Package shard implements a lightweight coordinator/worker protocol for running
+// one drone swarm scenario across several processes. Entity ownership is split by
+// wave; every tick each shard pushes snapshots of the tracks it owns and pulls the
+// tracks owned by everyone else, plus any kills other shards scored against it.
+package shard
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Roles a process can take in a sharded run
+const (
+	RoleStandalone  = "standalone"
+	RoleCoordinator = "coordinator"
+	RoleWorker      = "worker"
+)
+
+// DefaultListenAddr is where the coordinator serves the sync protocol
+const DefaultListenAddr = ":7400"
+
+// TrackSnapshot is the state of a track as published by its owning shard
+type TrackSnapshot struct {
+	ID                uuid.UUID  `json:"id"`
+	TrackNumber       string     `json:"track_number"`
+	Shard             int        `json:"shard"`
+	Wave              int        `json:"wave"`
+	Position          [3]float64 `json:"position"` // ECEF meters
+	Classification    string     `json:"classification"`
+	SizeClass         string     `json:"size_class"`
+	RFEmitting        bool       `json:"rf_emitting"`
+	ThermalSignature  bool       `json:"thermal_signature"`
+	EvasionCapability bool       `json:"evasion_capability"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// Kill reports that a shard destroyed a track owned by another shard
+type Kill struct {
+	TrackID uuid.UUID `json:"track_id"`
+	ByShard int       `json:"by_shard"`
+	By      string    `json:"by"` // Engaging system callsign
+}
+
+// Leakage counts threats that reached the defended area. Each shard scores the
+// leakers it owns, so the run's leakage is the sum over shards.
+type Leakage struct {
+	Penetrated int         `json:"penetrated"`
+	ByWave     map[int]int `json:"by_wave,omitempty"`
+}
+
+// add sums other into l
+func (l *Leakage) add(other Leakage) {
+	l.Penetrated += other.Penetrated
+	for wave, n := range other.ByWave {
+		if l.ByWave == nil {
+			l.ByWave = make(map[int]int)
+		}
+		l.ByWave[wave] += n
+	}
+}
+
+// SyncRequest is sent by a shard every tick
+type SyncRequest struct {
+	Shard   int             `json:"shard"`
+	Tracks  []TrackSnapshot `json:"tracks"`
+	Kills   []Kill          `json:"kills,omitempty"`
+	Leakage Leakage         `json:"leakage"` // The shard's leakers so far
+}
+
+// SyncResponse carries everything the requesting shard doesn't own
+type SyncResponse struct {
+	Tracks  []TrackSnapshot `json:"tracks"`
+	Kills   []Kill          `json:"kills,omitempty"` // Kills against the requester's tracks
+	Leakage Leakage         `json:"leakage"`         // Leakers on every other shard, summed
+	Shards  []int           `json:"shards"`          // Shards seen by the coordinator
+}
+
+// OwnerOfWave returns the shard that owns a wave (waves are 1-based)
+func OwnerOfWave(wave, shardCount int) int {
+	if shardCount <= 1 || wave < 1 {
+		return 0
+	}
+	return (wave - 1) % shardCount
+}
@@ -0,0 +1,298 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// Red behaviors a scenario act can order
+const (
+	ActBehaviorAttack   = "attack"   // Fly at the objective
+	ActBehaviorSurveil  = "surveil"  // Orbit the base at standoff range without closing
+	ActBehaviorWithdraw = "withdraw" // Turn away from the base and egress
+)
+
+// Rules of engagement a scenario act can set for the defense
+const (
+	ROEFree  = "free"  // Engage any track in the envelope
+	ROETight = "tight" // Engage only tracks classified HOSTILE
+	ROEHold  = "hold"  // Weapons hold: track but never engage
+)
+
+const (
+	actStandoffKm   = 6.0  // Orbit radius of surveilling threats, outside effector range
+	actOrbitStepDeg = 10.0 // How far ahead on the orbit a surveilling threat steers
+	actEgressKm     = 15.0 // How far from the base withdrawing threats head
+)
+
+// ScenarioAct is a time-boxed phase of the scenario with its own red behavior, rules of
+// engagement and the waves that launch when it begins
+type ScenarioAct struct {
+	Name     string
+	Duration time.Duration // The last act runs until the end of the run
+	Behavior string
+	ROE      string
+	Waves    []int // Waves launched when the act begins
+}
+
+// actState tracks the act in progress
+type actState struct {
+	index    int // -1 before the first act begins
+	launched map[int]bool
+}
+
+// parseActs parses "name:duration:behavior:roe[:waves]" entries separated by semicolons.
+// waves lists wave numbers and ranges, e.g. 1 or 2-4 or 1,3.
+func parseActs(spec string) ([]ScenarioAct, error) {
+	var acts []ScenarioAct
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ":")
+		if len(fields) < 4 || len(fields) > 5 {
+			return nil, fmt.Errorf("act %q: expected name:duration:behavior:roe[:waves]", entry)
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+
+		act := ScenarioAct{Name: fields[0], Behavior: strings.ToLower(fields[2]), ROE: strings.ToLower(fields[3])}
+		if act.Name == "" {
+			return nil, fmt.Errorf("act %q: name is required", entry)
+		}
+		duration, err := time.ParseDuration(fields[1])
+		if err != nil || duration <= 0 {
+			return nil, fmt.Errorf("act %s: invalid duration %q", act.Name, fields[1])
+		}
+		act.Duration = duration
+
+		switch act.Behavior {
+		case ActBehaviorAttack, ActBehaviorSurveil, ActBehaviorWithdraw:
+		default:
+			return nil, fmt.Errorf("act %s: behavior must be %s, %s or %s", act.Name, ActBehaviorAttack, ActBehaviorSurveil, ActBehaviorWithdraw)
+		}
+		switch act.ROE {
+		case ROEFree, ROETight, ROEHold:
+		default:
+			return nil, fmt.Errorf("act %s: roe must be %s, %s or %s", act.Name, ROEFree, ROETight, ROEHold)
+		}
+
+		if len(fields) == 5 && fields[4] != "" {
+			if act.Waves, err = parseWaveList(fields[4]); err != nil {
+				return nil, fmt.Errorf("act %s: %w", act.Name, err)
+			}
+		}
+		acts = append(acts, act)
+	}
+	return acts, nil
+}
+
+// parseWaveList parses comma-separated wave numbers and ranges
+func parseWaveList(spec string) ([]int, error) {
+	var waves []int
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		from, to, isRange := strings.Cut(part, "-")
+		first, err := strconv.Atoi(strings.TrimSpace(from))
+		if err != nil || first < 1 {
+			return nil, fmt.Errorf("invalid wave %q", part)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(strings.TrimSpace(to)); err != nil || last < first {
+				return nil, fmt.Errorf("invalid wave range %q", part)
+			}
+		}
+		for wave := first; wave <= last; wave++ {
+			waves = append(waves, wave)
+		}
+	}
+	return waves, nil
+}
+
+// validateActs checks act names are unique and act waves fit the scenario's wave count.
+// Each wave launches in at most one act; waves no act names launch with the first.
+func validateActs(acts []ScenarioAct, numWaves int) error {
+	seen := make(map[int]string)
+	names := make(map[string]bool)
+	for _, act := range acts {
+		if names[act.Name] {
+			return fmt.Errorf("act %s is given twice", act.Name)
+		}
+		names[act.Name] = true
+		for _, wave := range act.Waves {
+			if wave > numWaves {
+				return fmt.Errorf("act %s launches wave %d but the scenario has %d", act.Name, wave, numWaves)
+			}
+			if other, ok := seen[wave]; ok {
+				return fmt.Errorf("wave %d launches in both %s and %s", wave, other, act.Name)
+			}
+			seen[wave] = act.Name
+		}
+	}
+	return nil
+}
+
+// actStart is when the act begins, measured from the scenario start
+func actStart(acts []ScenarioAct, index int) time.Duration {
+	var start time.Duration
+	for _, act := range acts[:index] {
+		start += act.Duration
+	}
+	return start
+}
+
+// actAt returns the index of the act in progress at a scenario time
+func actAt(acts []ScenarioAct, elapsed time.Duration) int {
+	for i := len(acts) - 1; i > 0; i-- {
+		if elapsed >= actStart(acts, i) {
+			return i
+		}
+	}
+	return 0
+}
+
+// waveLaunchOffset is when a wave launches: its wave_times entry, the start of the act
+// that names it, or the scenario start when neither does
+func (s *DroneSwarmSimulation) waveLaunchOffset(wave int) time.Duration {
+	if wave >= 1 && wave <= len(s.config.WaveTimes) {
+		return s.config.WaveTimes[wave-1]
+	}
+	for i, act := range s.config.Acts {
+		for _, w := range act.Waves {
+			if w == wave {
+				return actStart(s.config.Acts, i)
+			}
+		}
+	}
+	return 0
+}
+
+// currentAct returns the act in progress, or nil without acts
+func (s *DroneSwarmSimulation) currentAct() *ScenarioAct {
+	if len(s.config.Acts) == 0 || s.acts.index < 0 {
+		return nil
+	}
+	return &s.config.Acts[s.acts.index]
+}
+
+// advanceActs moves to the act in progress at the current scenario time, logging each
+// transition and launching the waves held for it
+func (s *DroneSwarmSimulation) advanceActs() {
+	if len(s.config.Acts) == 0 {
+		return
+	}
+	index := actAt(s.config.Acts, s.scenarioElapsed())
+	for s.acts.index < index {
+		s.acts.index++
+		act := s.config.Acts[s.acts.index]
+
+		logger.LogSection(fmt.Sprintf("Act %d: %s", s.acts.index+1, act.Name))
+		logger.Infof("🎬 %s: red %s, ROE %s for %s", act.Name, act.Behavior, act.ROE, act.Duration)
+		s.simLogger.LogAct(s.acts.index+1, act.Name, act.Behavior, act.ROE, act.Waves, act.Duration)
+		s.launchActWaves()
+		if act.Behavior == ActBehaviorAttack {
+			s.resumeAttack()
+		}
+	}
+}
+
+// resumeAttack turns airborne threats back at their objectives after an act that held
+// them off
+func (s *DroneSwarmSimulation) resumeAttack() {
+	for _, threat := range s.uasThreats {
+		if threat.ActHeld || threat.Remote || threat.Replay != nil || threat.holdingAtLaunchSite() ||
+			threat.Classification == TrackStatusDestroyed || threat.Classification == TrackStatusLost {
+			continue
+		}
+		s.headForObjective(threat)
+	}
+}
+
+// launchActWaves releases held threats whose wave launches by now
+func (s *DroneSwarmSimulation) launchActWaves() {
+	if s.acts.launched == nil {
+		s.acts.launched = make(map[int]bool)
+	}
+	elapsed := actStart(s.config.Acts, s.acts.index)
+
+	released := make(map[int][]*UASThreat)
+	for _, threat := range s.uasThreats {
+		wave := threat.ActualCapabilities.WaveNumber
+		if threat.ActHeld && s.waveLaunchOffset(wave) <= elapsed {
+			threat.ActHeld = false
+			s.headForObjective(threat)
+			released[wave] = append(released[wave], threat)
+		}
+	}
+
+	waves := make([]int, 0, len(released))
+	for wave := range released {
+		waves = append(waves, wave)
+	}
+	sort.Ints(waves)
+	for _, wave := range waves {
+		if s.acts.launched[wave] {
+			continue
+		}
+		s.acts.launched[wave] = true
+		threats := released[wave]
+		s.simLogger.LogWaveLaunch(s.factionOf(threats[0]).Name, wave, len(threats), map[string]interface{}{
+			"act": s.config.Acts[s.acts.index].Name,
+		})
+	}
+}
+
+// roePermits reports whether the act's rules of engagement allow engaging a track
+func (s *DroneSwarmSimulation) roePermits(threat *UASThreat) bool {
+	act := s.currentAct()
+	if act == nil {
+		return true
+	}
+	switch act.ROE {
+	case ROEHold:
+		return false
+	case ROETight:
+		return threat.Classification == TrackStatusHostile
+	default:
+		return true
+	}
+}
+
+// steerForAct points an airborne threat's velocity to follow the act's red behavior
+func (s *DroneSwarmSimulation) steerForAct(threat *UASThreat) {
+	act := s.currentAct()
+	if act == nil || act.Behavior == ActBehaviorAttack {
+		return
+	}
+
+	base := s.config.BaseLocation
+	lat, lon, alt := ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+	bearing := bearingDegrees(base.Lat, base.Lon, lat, lon)
+
+	var x, y, z float64
+	switch act.Behavior {
+	case ActBehaviorSurveil:
+		// Close to the standoff ring, then circle it
+		if s.distanceToBaseKm(threat.Position) > actStandoffKm+0.5 {
+			tLat, tLon := destinationPoint(base.Lat, base.Lon, bearing, actStandoffKm*1000)
+			x, y, z = latLonAltToECEF(tLat, tLon, alt)
+		} else {
+			tLat, tLon := destinationPoint(base.Lat, base.Lon, math.Mod(bearing+actOrbitStepDeg, 360), actStandoffKm*1000)
+			x, y, z = latLonAltToECEF(tLat, tLon, alt)
+		}
+	case ActBehaviorWithdraw:
+		tLat, tLon := destinationPoint(base.Lat, base.Lon, bearing, actEgressKm*1000)
+		x, y, z = latLonAltToECEF(tLat, tLon, alt)
+	}
+	s.headFor(threat, x, y, z)
+}
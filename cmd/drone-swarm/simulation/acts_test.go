@@ -0,0 +1,78 @@
+package simulation
+
+import (
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseActs(t *testing.T) {
+	acts, err := parseActs("Recon:3m:surveil:hold; Strike:5m:Attack:free:2-3,5 ;Egress:2m:withdraw:tight")
+	if err != nil {
+		t.Fatalf("parseActs: %v", err)
+	}
+	if len(acts) != 3 {
+		t.Fatalf("got %d acts, want 3", len(acts))
+	}
+	strike := acts[1]
+	if strike.Name != "Strike" || strike.Duration != 5*time.Minute || strike.Behavior != ActBehaviorAttack ||
+		strike.ROE != ROEFree || !slices.Equal(strike.Waves, []int{2, 3, 5}) {
+		t.Errorf("unexpected act %+v", strike)
+	}
+
+	for _, spec := range []string{
+		"Recon:3m:surveil",          // too few fields
+		":3m:surveil:hold",          // no name
+		"Recon:soon:surveil:hold",   // bad duration
+		"Recon:3m:loiter:hold",      // unknown behavior
+		"Recon:3m:surveil:weapons",  // unknown ROE
+		"Strike:5m:attack:free:3-2", // backwards range
+		"Strike:5m:attack:free:0",   // no wave 0
+	} {
+		if _, err := parseActs(spec); err == nil {
+			t.Errorf("parseActs(%q) succeeded, want an error", spec)
+		}
+	}
+
+	if err := validateActs(acts, 5); err != nil {
+		t.Errorf("validateActs: %v", err)
+	}
+	if err := validateActs(acts, 4); err == nil {
+		t.Error("expected an error for a wave the scenario doesn't have")
+	}
+	twice, _ := parseActs("A:1m:attack:free:1;B:1m:attack:free:1")
+	if err := validateActs(twice, 2); err == nil {
+		t.Error("expected an error for a wave launched in two acts")
+	}
+}
+
+func TestActTiming(t *testing.T) {
+	acts, _ := parseActs("Recon:3m:surveil:hold;Strike:5m:attack:tight:2;Egress:2m:withdraw:free")
+	for elapsed, want := range map[time.Duration]int{0: 0, 3 * time.Minute: 1, 7 * time.Minute: 1, 8 * time.Minute: 2, time.Hour: 2} {
+		if got := actAt(acts, elapsed); got != want {
+			t.Errorf("actAt(%s) = %d, want %d", elapsed, got, want)
+		}
+	}
+
+	s := &DroneSwarmSimulation{config: SimulationConfig{Acts: acts}, acts: actState{index: -1}}
+	if got := s.waveLaunchOffset(2); got != 3*time.Minute {
+		t.Errorf("wave 2 launches at %s, want 3m", got)
+	}
+	if got := s.waveLaunchOffset(1); got != 0 {
+		t.Errorf("unnamed wave 1 launches at %s, want 0", got)
+	}
+
+	suspected := &UASThreat{Classification: TrackStatusSuspected}
+	hostile := &UASThreat{Classification: TrackStatusHostile}
+	if !s.roePermits(suspected) {
+		t.Error("before the first act every track may be engaged")
+	}
+	s.acts.index = 0
+	if s.roePermits(hostile) {
+		t.Error("weapons hold permitted an engagement")
+	}
+	s.acts.index = 1
+	if s.roePermits(suspected) || !s.roePermits(hostile) {
+		t.Error("weapons tight should engage only HOSTILE tracks")
+	}
+}
@@ -0,0 +1,266 @@
+package simulation
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// Red force adaptation
+const (
+	adaptationAttrition = 0.5   // Share of a departed wave lost before red changes its plan
+	adaptationMaxShift  = 90.0  // Degrees the ingress axis swings away from the losses at full adaptiveness
+	adaptationLossAlt   = 75.0  // Mean loss height above ground, meters, above which red drops low
+	adaptationLowAlt    = 30.0  // Ingress height above ground red drops to, meters
+	adaptationHighAlt   = 400.0 // Ingress height above ground red climbs to, meters
+	ingressArrival      = 300.0 // Meters from the ingress point a threat turns for its objective
+)
+
+// waveKey identifies one faction's wave
+type waveKey struct {
+	Faction string
+	Wave    int
+}
+
+// waveObservation is what red can plausibly know of a wave's fate: which drones dropped
+// off its datalink and where they were last heard, and which reported reaching the
+// objective. It never sees which defender fired or why.
+type waveObservation struct {
+	Departed     int
+	Destroyed    int
+	Leaked       int
+	LossBearings []float64 // From the base, where each destroyed drone was last heard
+	LossHeight   float64   // Mean height above ground of the losses, meters
+}
+
+// attrition is the share of the departed drones red lost
+func (o waveObservation) attrition() float64 {
+	if o.Departed == 0 {
+		return 0
+	}
+	return float64(o.Destroyed) / float64(o.Departed)
+}
+
+// lethalBearing is the circular mean bearing of the losses
+func (o waveObservation) lethalBearing() float64 {
+	var x, y float64
+	for _, b := range o.LossBearings {
+		x += math.Cos(b * math.Pi / 180)
+		y += math.Sin(b * math.Pi / 180)
+	}
+	return math.Mod(math.Atan2(y, x)*180/math.Pi+360, 360)
+}
+
+// ingressBearing swings a wave's approach away from the lethal bearing. Approaches already
+// more than 90° clear of it are left alone.
+func ingressBearing(siteBearing, lethal, adaptiveness float64) float64 {
+	diff := math.Mod(siteBearing-lethal+540, 360) - 180
+	if math.Abs(diff) >= 90 {
+		return siteBearing
+	}
+	shift := adaptiveness * adaptationMaxShift
+	if diff < 0 {
+		shift = -shift
+	}
+	return math.Mod(siteBearing+shift+360, 360)
+}
+
+// ingressHeight picks the height above ground to come in at: under the defense when the
+// losses came high, over it when they came low
+func ingressHeight(lossHeight, adaptiveness float64) float64 {
+	if lossHeight > adaptationLossAlt {
+		return assemblyAltitude - adaptiveness*(assemblyAltitude-adaptationLowAlt)
+	}
+	return assemblyAltitude + adaptiveness*(adaptationHighAlt-assemblyAltitude)
+}
+
+// waveAdaptation is one change red made to a later wave after watching an earlier one
+type waveAdaptation struct {
+	Faction       string
+	ObservedWave  int
+	Attrition     float64
+	LethalAxis    string
+	Wave          int
+	IngressAxis   string
+	IngressHeight float64       // Meters above ground
+	Hold          time.Duration // Added to the wave's assembly orbit so it masses with the next
+}
+
+// adaptationLog holds what red has concluded from its earlier waves
+type adaptationLog struct {
+	judged  map[waveKey]bool
+	changes []waveAdaptation
+}
+
+// adaptationActive reports whether red adapts later waves this run
+func (s *DroneSwarmSimulation) adaptationActive() bool {
+	return s.config.AttackerAdaptiveness > 0 && len(s.config.LaunchSites) > 0
+}
+
+// updateAdaptation judges each wave once red can tell how it fared, and replans that
+// faction's waves still on the ground or in the assembly orbit when it took heavy losses
+func (s *DroneSwarmSimulation) updateAdaptation() {
+	if !s.adaptationActive() {
+		return
+	}
+	if s.adaptation.judged == nil {
+		s.adaptation.judged = make(map[waveKey]bool)
+	}
+
+	waves := make(map[waveKey][]*UASThreat)
+	for _, threat := range s.uasThreats {
+		if threat.Remote || threat.LaunchSite == "" {
+			continue
+		}
+		key := waveKey{Faction: s.factionOf(threat).Name, Wave: threat.ActualCapabilities.WaveNumber}
+		waves[key] = append(waves[key], threat)
+	}
+
+	keys := make([]waveKey, 0, len(waves))
+	for key := range waves {
+		keys = append(keys, key)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Wave != keys[j].Wave {
+			return keys[i].Wave < keys[j].Wave
+		}
+		return keys[i].Faction < keys[j].Faction
+	})
+
+	for _, key := range keys {
+		if s.adaptation.judged[key] {
+			continue
+		}
+		obs, judged := s.observeWave(waves[key])
+		if !judged {
+			continue
+		}
+		s.adaptation.judged[key] = true
+		if obs.attrition() < adaptationAttrition {
+			behaviorLog.Debugf("🧠 %s wave %d lost %d of %d, keeping the plan", key.Faction, key.Wave, obs.Destroyed, obs.Departed)
+			continue
+		}
+		s.adapt(key, obs, waves)
+	}
+}
+
+// observeWave summarizes a wave once all of it has departed and red can judge it: every
+// drone has been destroyed or reached its objective, or losses alone already pass the
+// attrition threshold. judged is false until then, and for waves that never got airborne.
+func (s *DroneSwarmSimulation) observeWave(threats []*UASThreat) (obs waveObservation, judged bool) {
+	var height float64
+	flying := 0
+	for _, threat := range threats {
+		if threat.holdingAtLaunchSite() && threat.Classification != TrackStatusDestroyed {
+			return obs, false
+		}
+		if threat.LaunchPhase != LaunchPhaseTransit {
+			continue // Destroyed on the ground
+		}
+
+		obs.Departed++
+		switch threat.Classification {
+		case TrackStatusLost:
+			obs.Leaked++
+		case TrackStatusDestroyed:
+			obs.Destroyed++
+			lat, lon, alt := ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+			obs.LossBearings = append(obs.LossBearings, bearingDegrees(s.config.BaseLocation.Lat, s.config.BaseLocation.Lon, lat, lon))
+			height += alt - threat.GroundAltitude
+		default:
+			flying++
+		}
+	}
+	if obs.Destroyed > 0 {
+		obs.LossHeight = height / float64(obs.Destroyed)
+	}
+	return obs, obs.Departed > 0 && (flying == 0 || obs.attrition() >= adaptationAttrition)
+}
+
+// adapt reroutes the faction's later waves that have not departed around the lethal
+// bearing at a new height, and holds the next of them so it masses with the one after
+func (s *DroneSwarmSimulation) adapt(observed waveKey, obs waveObservation, waves map[waveKey][]*UASThreat) {
+	a := s.config.AttackerAdaptiveness
+	lethal := obs.lethalBearing()
+	height := ingressHeight(obs.LossHeight, a)
+	base := s.config.BaseLocation
+
+	var later []waveKey
+	for key, threats := range waves {
+		if key.Faction != observed.Faction || key.Wave <= observed.Wave {
+			continue
+		}
+		for _, threat := range threats {
+			if threat.holdingAtLaunchSite() && threat.Classification != TrackStatusDestroyed {
+				later = append(later, key)
+				break
+			}
+		}
+	}
+	sort.Slice(later, func(i, j int) bool { return later[i].Wave < later[j].Wave })
+
+	for i, key := range later {
+		change := waveAdaptation{
+			Faction:       key.Faction,
+			ObservedWave:  observed.Wave,
+			Attrition:     obs.attrition(),
+			LethalAxis:    compassAxis(lethal),
+			Wave:          key.Wave,
+			IngressHeight: height,
+		}
+		if i == 0 {
+			change.Hold = time.Duration(a * float64(s.config.WaveDelay))
+		}
+
+		for _, threat := range waves[key] {
+			if !threat.holdingAtLaunchSite() || threat.Classification == TrackStatusDestroyed {
+				continue
+			}
+			siteBearing := bearingDegrees(base.Lat, base.Lon, threat.AssemblyLat, threat.AssemblyLon)
+			siteDistance := math.Hypot(s.localMeters(threat.AssemblyLat, threat.AssemblyLon))
+			bearing := ingressBearing(siteBearing, lethal, a)
+			change.IngressAxis = compassAxis(bearing)
+
+			threat.Ingress = true
+			threat.IngressLat, threat.IngressLon = destinationPoint(base.Lat, base.Lon, bearing, siteDistance/2)
+			threat.IngressHeight = height
+			threat.DepartOffset += change.Hold
+		}
+
+		s.adaptation.changes = append(s.adaptation.changes, change)
+		s.simLogger.LogAdaptation(change.Faction, change.ObservedWave, change.Wave, change.Attrition,
+			change.LethalAxis, change.IngressAxis, change.IngressHeight, change.Hold)
+		logger.Warnf("🧠 %s lost %.0f%% of wave %d to the %s: wave %d reroutes via the %s at %.0fm%s",
+			change.Faction, change.Attrition*100, change.ObservedWave, change.LethalAxis,
+			change.Wave, change.IngressAxis, change.IngressHeight, holdNote(change.Hold))
+	}
+}
+
+// holdNote describes an extended assembly hold for log lines
+func holdNote(hold time.Duration) string {
+	if hold <= 0 {
+		return ""
+	}
+	return ", holding " + hold.Round(time.Second).String() + " longer to mass"
+}
+
+// advanceIngress steers a rerouted threat at its ingress point, then turns it for its
+// objective once it arrives. Threats flying straight in are left alone.
+func (s *DroneSwarmSimulation) advanceIngress(threat *UASThreat) {
+	if !threat.Ingress || threat.LaunchPhase != LaunchPhaseTransit {
+		return
+	}
+	x, y, z := latLonAltToECEF(threat.IngressLat, threat.IngressLon, threat.GroundAltitude+threat.IngressHeight)
+	dx := x - threat.Position.Coordinates[0]
+	dy := y - threat.Position.Coordinates[1]
+	dz := z - threat.Position.Coordinates[2]
+	if math.Sqrt(dx*dx+dy*dy+dz*dz) < ingressArrival {
+		threat.Ingress = false
+		s.headForObjective(threat)
+		behaviorLog.Debugf("➡️ %s reached its ingress point, turning inbound", threat.TrackNumber)
+		return
+	}
+	s.headFor(threat, x, y, z)
+}
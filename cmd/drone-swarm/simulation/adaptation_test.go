@@ -0,0 +1,97 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestIngressBearing(t *testing.T) {
+	cases := []struct {
+		site, lethal, adaptiveness, want float64
+	}{
+		{180, 180, 1, 270},   // Head-on: swing the full 90°
+		{170, 190, 0.5, 125}, // Losses just west of the approach push it east, half as far
+		{10, 350, 1, 100},    // Across north
+		{90, 180, 1, 90},     // Already clear
+	}
+	for _, c := range cases {
+		if got := ingressBearing(c.site, c.lethal, c.adaptiveness); math.Abs(got-c.want) > 1e-9 {
+			t.Errorf("ingressBearing(%v, %v, %v) = %v, want %v", c.site, c.lethal, c.adaptiveness, got, c.want)
+		}
+	}
+
+	if got := ingressHeight(200, 1); got != adaptationLowAlt {
+		t.Errorf("expected losses up high to send red low, got %.0fm", got)
+	}
+	if got := ingressHeight(40, 0.5); got != (assemblyAltitude+adaptationHighAlt)/2 {
+		t.Errorf("expected losses down low to send red halfway up, got %.0fm", got)
+	}
+}
+
+func TestUpdateAdaptationReplansLaterWaves(t *testing.T) {
+	base := Location{Lat: 40, Lon: -76}
+	s := &DroneSwarmSimulation{
+		config: SimulationConfig{
+			BaseLocation:         base,
+			LaunchSites:          []LaunchSite{{Name: "South", DistanceKm: 15, BearingDeg: 180}},
+			WaveDelay:            time.Minute,
+			AttackerAdaptiveness: 1,
+		},
+		uasThreats: make(map[uuid.UUID]*UASThreat),
+		simLogger:  reporting.NewSimulationLogger("test"),
+	}
+	siteLat, siteLon := destinationPoint(base.Lat, base.Lon, 180, 15000)
+	add := func(wave int, phase, classification string, lat, lon, alt float64) *UASThreat {
+		x, y, z := latLonAltToECEF(lat, lon, alt)
+		threat := &UASThreat{
+			ID:                 uuid.New(),
+			Classification:     classification,
+			LaunchSite:         "South",
+			LaunchPhase:        phase,
+			AssemblyLat:        siteLat,
+			AssemblyLon:        siteLon,
+			DepartOffset:       5 * time.Minute,
+			Position:           &models.GeomPoint{Coordinates: []float64{x, y, z}},
+			ActualCapabilities: SimulatedCapabilities{WaveNumber: wave},
+		}
+		s.uasThreats[threat.ID] = threat
+		return threat
+	}
+
+	// Wave 1 lost three of four drones 5km south at 200m while the fourth is still inbound
+	lossLat, lossLon := destinationPoint(base.Lat, base.Lon, 180, 5000)
+	for i := 0; i < 3; i++ {
+		add(1, LaunchPhaseTransit, TrackStatusDestroyed, lossLat, lossLon, 200)
+	}
+	add(1, LaunchPhaseTransit, TrackStatusHostile, lossLat, lossLon, 200)
+	next := add(2, LaunchPhaseForming, TrackStatusPending, siteLat, siteLon, 150)
+	after := add(3, LaunchPhaseGrounded, TrackStatusPending, siteLat, siteLon, 0)
+
+	s.updateAdaptation()
+
+	if len(s.adaptation.changes) != 2 {
+		t.Fatalf("expected waves 2 and 3 to be replanned, got %+v", s.adaptation.changes)
+	}
+	change := s.adaptation.changes[0]
+	if change.LethalAxis != "S" || change.IngressAxis != "W" || change.IngressHeight != adaptationLowAlt {
+		t.Errorf("expected wave 2 to come in low from the west, got %+v", change)
+	}
+	if !next.Ingress || next.DepartOffset != 6*time.Minute || after.DepartOffset != 5*time.Minute {
+		t.Errorf("expected only the next wave to hold a wave delay longer, got %s and %s", next.DepartOffset, after.DepartOffset)
+	}
+	ingressBearing := bearingDegrees(base.Lat, base.Lon, next.IngressLat, next.IngressLon)
+	if math.Abs(ingressBearing-270) > 0.5 {
+		t.Errorf("expected the ingress point due west of the base, got %.1f°", ingressBearing)
+	}
+
+	// Wave 1 is only judged once
+	s.updateAdaptation()
+	if len(s.adaptation.changes) != 2 {
+		t.Errorf("expected no further replanning, got %d changes", len(s.adaptation.changes))
+	}
+}
@@ -0,0 +1,43 @@
+package simulation
+
+import (
+	"context"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/artifacts"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// artifactUploadTimeout bounds the end-of-run upload of all outputs
+const artifactUploadTimeout = 2 * time.Minute
+
+// reportsDir is where the AAR and engagement timeline are written
+const reportsDir = "./reports"
+
+// publishArtifacts uploads the run's local outputs to the configured object storage
+func (s *DroneSwarmSimulation) publishArtifacts(ctx context.Context) {
+	if len(s.artifacts) == 0 {
+		return
+	}
+
+	publisher, err := artifacts.NewPublisher(s.config.ArtifactURL, s.config.OrganizationID, s.Name(), s.runID, s.runStarted)
+	if err != nil {
+		logger.Errorf("Artifact upload disabled: %v", err)
+		return
+	}
+	if publisher == nil {
+		return
+	}
+
+	// The run context may already be cancelled by an interrupt; uploads should still happen
+	uploadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), artifactUploadTimeout)
+	defer cancel()
+
+	locations, err := publisher.PublishAll(uploadCtx, s.artifacts)
+	for _, location := range locations {
+		logger.Successf("Artifact uploaded: %s", location)
+	}
+	if err != nil {
+		logger.Errorf("Some artifacts failed to upload: %v", err)
+	}
+}
@@ -0,0 +1,250 @@
+package simulation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/geo"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// briefSection is one paragraph of the scenario brief
+type briefSection struct {
+	Title string
+	Items []string
+}
+
+// scenarioBrief lays the resolved configuration out as an operations order: situation,
+// mission and execution, so a scenario can be checked without reading its parameters
+func (s *DroneSwarmSimulation) scenarioBrief() []briefSection {
+	cfg := s.config
+	base := fmt.Sprintf("%.5f, %.5f", cfg.BaseLocation.Lat, cfg.BaseLocation.Lon)
+	if grid, err := geo.FormatMGRS(cfg.BaseLocation.Lat, cfg.BaseLocation.Lon, 5); err == nil {
+		base = "MGRS " + grid
+	}
+
+	// Terrain and weather
+	terrain := []string{
+		fmt.Sprintf("Area of operations: %.0fkm around the defended base at %s, %.0fm MSL", cfg.SimulationRadius, base, cfg.BaseLocation.Alt),
+	}
+	if s.dataPack != nil && s.dataPack.terrain != nil {
+		terrain = append(terrain, fmt.Sprintf("Terrain: elevation grid from data pack %s masks coverage", s.dataPack.ref.Name))
+	} else {
+		terrain = append(terrain, "Terrain: level ground at the base's altitude")
+	}
+	terrain = append(terrain, "Weather: not modeled; clear, calm conditions assumed for every sensor and effector")
+	if cfg.SensorNoise > 0 {
+		terrain = append(terrain, fmt.Sprintf("Sensors: range and bearing error at %.1fx nominal", cfg.SensorNoise))
+	} else {
+		terrain = append(terrain, "Sensors: ground truth, no measurement error")
+	}
+	if len(cfg.PopulatedAreas) > 0 {
+		names := make([]string, len(cfg.PopulatedAreas))
+		for i, area := range cfg.PopulatedAreas {
+			names[i] = area.Name
+		}
+		terrain = append(terrain, "Civil considerations: "+strings.Join(names, ", "))
+	}
+
+	// Enemy forces
+	enemy := []string{fmt.Sprintf("%d UAS in %d waves", cfg.NumUASThreats, cfg.NumWaves)}
+	if len(cfg.LaunchSites) > 0 {
+		for _, site := range cfg.LaunchSites {
+			enemy = append(enemy, fmt.Sprintf("Launch site %s: %.1fkm at %03.0f°, %.0f launches/min", site.Name, site.DistanceKm, site.BearingDeg, site.RatePerMin))
+		}
+		if len(cfg.WaveTimes) == 0 {
+			enemy = append(enemy, fmt.Sprintf("Waves launch %s apart and assemble before transiting to the objective", cfg.WaveDelay))
+		}
+	} else {
+		enemy = append(enemy, "Waves start inbound from the edge of the area of operations")
+	}
+	if len(cfg.Factions) > 0 {
+		for _, faction := range cfg.Factions {
+			objective := "the base"
+			if faction.ObjectiveOffset > 0 {
+				objective = fmt.Sprintf("%.0fm from the base at %03.0f°", faction.ObjectiveOffset, faction.ObjectiveBearing)
+			}
+			enemy = append(enemy, fmt.Sprintf("Faction %s: %.0f%% of every wave, attacking %s", faction.Name, faction.Share/totalFactionShare(cfg.Factions)*100, objective))
+		}
+	}
+	if len(cfg.ReplayTracks) > 0 {
+		enemy = append(enemy, fmt.Sprintf("%d threats fly recorded tracks", len(cfg.ReplayTracks)))
+	}
+	var mix []string
+	for _, entry := range s.payloads() {
+		mix = append(mix, fmt.Sprintf("%s %.0f%%", entry.Type, entry.Profile.Weight/totalPayloadWeight(s.payloads())*100))
+	}
+	enemy = append(enemy, "Payloads: "+strings.Join(mix, ", "))
+	if cfg.AttackerAdaptiveness > 0 {
+		enemy = append(enemy, fmt.Sprintf("Red replans later waves after losses (adaptiveness %.2f)", cfg.AttackerAdaptiveness))
+	}
+
+	// Friendly forces
+	kinetic := 0
+	for _, system := range cfg.systemPlan() {
+		if system.EngagementType == EngagementTypeKinetic {
+			kinetic++
+		}
+	}
+	friendly := []string{
+		fmt.Sprintf("%d Counter-UAS systems: %d kinetic, %d EW", cfg.NumCounterUASSystems, kinetic, cfg.NumCounterUASSystems-kinetic),
+	}
+	for _, site := range cfg.DefenseSites {
+		kind := "kinetic"
+		if site.EngagementType == EngagementTypeEW {
+			kind = "EW"
+		}
+		friendly = append(friendly, fmt.Sprintf("%s (%s): %.0fm from the base at %03.0f°", site.Name, kind, site.DistanceM, site.BearingDeg))
+	}
+	for _, unit := range cfg.GroundUnits {
+		friendly = append(friendly, fmt.Sprintf("%s (%s, %d personnel): %.0fm from the base at %03.0f°", unit.Name, unit.Kind, unit.Strength, unit.DistanceM, unit.BearingDeg))
+	}
+	if online, ok := cfg.ReportLatency.forState(DataLinkOnline); ok {
+		degraded, _ := cfg.ReportLatency.forState(DataLinkDegraded)
+		friendly = append(friendly, fmt.Sprintf("Sensor-to-C2 reporting: %s ±%s online, %s ±%s degraded",
+			online.Latency, online.Jitter, degraded.Latency, degraded.Jitter))
+	}
+	if cfg.CounterBattery {
+		friendly = append(friendly, fmt.Sprintf("Counter-battery fires: strike after %d lines of bearing, %s to impact", cfg.CounterBatteryLines, cfg.CounterBatteryDelay))
+	}
+
+	// Mission
+	mission := []string{
+		fmt.Sprintf("Counter-UAS forces defend the base at %s against %d UAS for %s", base, cfg.NumUASThreats, cfg.SimDuration),
+	}
+
+	// Execution, by act when the scenario has them
+	var execution []string
+	if cfg.WarmupDuration > 0 {
+		execution = append(execution, fmt.Sprintf("Warm-up: %s of BIT and calibration before wave 1", cfg.WarmupDuration))
+	}
+	if !cfg.StartTime.IsZero() {
+		execution = append(execution, "Start: "+cfg.StartTime.UTC().Format(time.RFC3339))
+	}
+	roe := []string{"Weapons free: engage any track in the envelope, throughout"}
+	if len(cfg.Acts) > 0 {
+		roe = nil
+		for i, act := range cfg.Acts {
+			waves := "no new waves"
+			if len(act.Waves) > 0 {
+				numbers := make([]string, len(act.Waves))
+				for j, wave := range act.Waves {
+					numbers[j] = strconv.Itoa(wave)
+				}
+				waves = "launches waves " + strings.Join(numbers, ", ")
+			}
+			length := act.Duration.String()
+			if i == len(cfg.Acts)-1 {
+				length = "to end of run"
+			}
+			execution = append(execution, fmt.Sprintf("Act %d, %s (T+%s, %s): red %s, %s", i+1, act.Name, actStart(cfg.Acts, i), length, act.Behavior, waves))
+			roe = append(roe, fmt.Sprintf("%s: %s", act.Name, describeROE(act.ROE)))
+		}
+	} else if len(cfg.WaveTimes) == 0 {
+		execution = append(execution, "Single continuous assault until the run ends")
+	}
+	for i, at := range cfg.WaveTimes {
+		execution = append(execution, fmt.Sprintf("Wave %d launches at T+%s", i+1, at))
+	}
+	for _, failure := range cfg.SystemFailures {
+		outage := "for the rest of the run"
+		if failure.Duration > 0 {
+			outage = "for " + failure.Duration.String()
+		}
+		execution = append(execution, fmt.Sprintf("%s fails at T+%s and is offline %s", failure.System, failure.At, outage))
+	}
+
+	// Success criteria
+	success := []string{fmt.Sprintf("No more than %.0f%% of all threats reach the base", cfg.AcceptableLeakage*100)}
+	if cfg.CriticalAssetLeakers > 0 {
+		success = append(success, fmt.Sprintf("The run ends in defeat once %d leakers reach the base", cfg.CriticalAssetLeakers))
+	}
+	if cfg.WaveLeakageThreshold > 0 {
+		success = append(success, fmt.Sprintf("No single wave leaks more than %.0f%%", cfg.WaveLeakageThreshold*100))
+	}
+
+	return []briefSection{
+		{Title: "1a. Situation: Terrain and Weather", Items: terrain},
+		{Title: "1b. Situation: Enemy Forces", Items: enemy},
+		{Title: "1c. Situation: Friendly Forces", Items: friendly},
+		{Title: "2. Mission", Items: mission},
+		{Title: "3a. Execution", Items: execution},
+		{Title: "3b. Rules of Engagement", Items: roe},
+		{Title: "3c. Success Criteria", Items: success},
+	}
+}
+
+// describeROE spells out a rules of engagement setting for the brief
+func describeROE(roe string) string {
+	switch roe {
+	case ROEHold:
+		return "weapons hold, track without engaging"
+	case ROETight:
+		return "weapons tight, engage only tracks classified HOSTILE"
+	default:
+		return "weapons free, engage any track in the envelope"
+	}
+}
+
+// totalFactionShare sums faction shares, which are relative
+func totalFactionShare(factions []Faction) float64 {
+	total := 0.0
+	for _, faction := range factions {
+		total += faction.Share
+	}
+	return total
+}
+
+// totalPayloadWeight sums a raid mix's weights, which are relative
+func totalPayloadWeight(catalog []payloadEntry) float64 {
+	total := 0.0
+	for _, entry := range catalog {
+		total += entry.Profile.Weight
+	}
+	return total
+}
+
+// issueBrief prints the scenario brief before the run and, with brief_markdown set,
+// writes it to the reports directory alongside the AAR
+func (s *DroneSwarmSimulation) issueBrief() error {
+	sections := s.scenarioBrief()
+
+	logger.LogSection("Scenario Brief")
+	for _, section := range sections {
+		logger.LogList(section.Title, section.Items)
+	}
+
+	if !s.config.BriefMarkdown {
+		return nil
+	}
+
+	var sb strings.Builder
+	sb.WriteString("# Scenario Brief\n\n")
+	sb.WriteString(fmt.Sprintf("**Run:** %s  \n**Issued:** %s\n\n", s.runID, time.Now().UTC().Format(time.RFC3339)))
+	for _, section := range sections {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", section.Title))
+		for _, item := range section.Items {
+			sb.WriteString(fmt.Sprintf("- %s\n", item))
+		}
+		sb.WriteString("\n")
+	}
+
+	if err := os.MkdirAll(reportsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create reports directory: %w", err)
+	}
+	path := filepath.Join(reportsDir, fmt.Sprintf("Brief_%s_%s.md", s.runID[:8], time.Now().Format("20060102_150405")))
+	if err := os.WriteFile(path, []byte(sb.String()), 0644); err != nil {
+		return fmt.Errorf("failed to write scenario brief: %w", err)
+	}
+
+	s.artifacts = append(s.artifacts, path)
+	if s.aarGenerator != nil {
+		s.aarGenerator.AddAttachment(path)
+	}
+	logger.Successf("Scenario brief saved to: %s", path)
+	return nil
+}
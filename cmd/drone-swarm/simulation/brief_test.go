@@ -0,0 +1,44 @@
+package simulation
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestScenarioBrief(t *testing.T) {
+	acts, _ := parseActs("Recon:3m:surveil:hold;Strike:5m:attack:tight:2")
+	s := &DroneSwarmSimulation{config: SimulationConfig{
+		NumCounterUASSystems: 3,
+		NumUASThreats:        20,
+		NumWaves:             2,
+		SimDuration:          10 * time.Minute,
+		BaseLocation:         Location{Lat: 34.05, Lon: -118.25},
+		SimulationRadius:     10,
+		AcceptableLeakage:    0.3,
+		WaveLeakageThreshold: 0.5,
+		Acts:                 acts,
+		Factions:             []Faction{{Name: "Red", Share: 3}, {Name: "Orange", Share: 1, ObjectiveBearing: 90, ObjectiveOffset: 1500}},
+	}}
+
+	var brief strings.Builder
+	for _, section := range s.scenarioBrief() {
+		brief.WriteString(section.Title + "\n" + strings.Join(section.Items, "\n") + "\n")
+	}
+	for _, want := range []string{
+		"3 Counter-UAS systems: 2 kinetic, 1 EW",
+		"20 UAS in 2 waves",
+		"Faction Red: 75% of every wave, attacking the base",
+		"Faction Orange: 25% of every wave, attacking 1500m from the base at 090°",
+		"Act 2, Strike (T+3m0s, to end of run): red attack, launches waves 2",
+		"Recon: weapons hold",
+		"Strike: weapons tight",
+		"No more than 30% of all threats reach the base",
+		"No single wave leaks more than 50%",
+		"Weather: not modeled",
+	} {
+		if !strings.Contains(brief.String(), want) {
+			t.Errorf("brief is missing %q:\n%s", want, brief.String())
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package simulation
+
+import (
+	"time"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// startBudget installs the API budget, rate limit and circuit breaker on the Legion
+// client. An unlimited budget is still installed so the AAR can report how many calls
+// the run made.
+func (s *DroneSwarmSimulation) startBudget() {
+	if s.legionClient == nil {
+		return
+	}
+	s.legionClient.SetBudget(client.NewBudget(s.config.APIBudgetPerMinute, s.config.APIBudgetPerRun))
+	if s.config.APIBudgetPerMinute > 0 || s.config.APIBudgetPerRun > 0 {
+		logger.Infof("API budget: %d calls/min, %d calls/run (0 is unlimited); positions are shed first",
+			s.config.APIBudgetPerMinute, s.config.APIBudgetPerRun)
+	}
+
+	// Entity creation, update flushes and telemetry share the one client, so one limiter paces them all
+	if s.config.APIRateLimit > 0 {
+		rate := s.config.APIRateLimit
+		s.legionClient.SetRateLimiter(client.NewRateLimiter(float64(rate), rate))
+		logger.Infof("API rate limit: %d calls/s", rate)
+	} else {
+		s.legionClient.SetRateLimiter(nil)
+	}
+
+	if s.config.BreakerThreshold > 0 {
+		s.legionClient.SetCircuitBreaker(client.NewCircuitBreaker(s.config.BreakerThreshold, s.config.BreakerCooldown))
+	} else {
+		s.legionClient.SetCircuitBreaker(nil)
+	}
+	s.degraded = degradedState{}
+}
+
+// recordBudgetMetrics hands budget use to the AAR
+func (s *DroneSwarmSimulation) recordBudgetMetrics() {
+	if s.legionClient == nil || s.legionClient.Budget() == nil {
+		return
+	}
+	stats := s.legionClient.Budget().Stats()
+	s.simLogger.UpdateMetric(reporting.MetricAPICalls, float64(stats.Used), "count")
+	s.simLogger.UpdateMetric(reporting.MetricAPIPeakPerMinute, float64(stats.PeakPerMinute), "count")
+	s.simLogger.UpdateMetric(reporting.MetricAPIBudgetPerMinute, float64(stats.PerMinute), "count")
+	s.simLogger.UpdateMetric(reporting.MetricAPIBudgetPerRun, float64(stats.PerRun), "count")
+	s.simLogger.UpdateMetric(reporting.MetricAPIShedPositions, float64(stats.ShedPositions), "count")
+	s.simLogger.UpdateMetric(reporting.MetricAPIShedMetadata, float64(stats.ShedNormal), "count")
+
+	if shed := stats.ShedPositions + stats.ShedNormal; shed > 0 {
+		logger.Warnf("API budget shed %d position and %d metadata/feed writes", stats.ShedPositions, stats.ShedNormal)
+	}
+
+	if limiter := s.legionClient.RateLimiter(); limiter != nil {
+		if limited := limiter.Stats(); limited.Waited > 0 {
+			logger.Infof("API rate limit held back %d calls for %s in total", limited.Waited, limited.TotalWait.Round(time.Millisecond))
+		}
+	}
+
+	if s.degraded.outages > 0 {
+		logger.Warnf("Legion was failing %d times for %s in total; %d calls were not sent while it was",
+			s.degraded.outages, s.degraded.total.Round(time.Second), s.legionClient.CircuitBreaker().Stats().Rejected)
+	}
+}
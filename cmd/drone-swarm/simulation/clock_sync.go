@@ -0,0 +1,54 @@
+package simulation
+
+import (
+	"context"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/clock"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// startClock disciplines the simulation clock to the configured reference, so ticks land
+// on the same wall-clock instants as other tools synced to it
+func (s *DroneSwarmSimulation) startClock(ctx context.Context) error {
+	source, err := clock.Open(s.config.ClockSource)
+	if err != nil || source == nil {
+		return err
+	}
+
+	governor := clock.NewGovernor(source, s.config.ClockSyncInterval)
+	if err := governor.Sync(ctx); err != nil {
+		return err
+	}
+	s.governor = governor
+	go governor.Run(ctx)
+
+	logger.Infof("%s Clock governed by %s (offset %s)", logger.IconTime, source, governor.Offset().Round(time.Microsecond))
+	return nil
+}
+
+// now is the simulation's wall clock: the governed reference when one is configured
+func (s *DroneSwarmSimulation) now() time.Time {
+	if s.governor != nil {
+		return s.governor.Now()
+	}
+	return time.Now()
+}
+
+// newTickSource returns the tick channel for the simulation loop. Governed ticks fall on
+// multiples of the update interval from the epoch on the reference clock.
+func (s *DroneSwarmSimulation) newTickSource(epoch time.Time) (<-chan time.Time, func()) {
+	if s.governor != nil {
+		ticker := s.governor.NewTicker(epoch, s.config.UpdateInterval)
+		return ticker.C, ticker.Stop
+	}
+	ticker := time.NewTicker(s.config.UpdateInterval)
+	return ticker.C, ticker.Stop
+}
+
+// recordClockOffset reports the applied offset as a run metric
+func (s *DroneSwarmSimulation) recordClockOffset() {
+	if s.governor != nil {
+		s.simLogger.UpdateMetric("clock_offset_ms", float64(s.governor.Offset())/float64(time.Millisecond), "ms")
+	}
+}
@@ -0,0 +1,223 @@
+package simulation
+
+import (
+	"math"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Counter-battery defaults
+const (
+	DefaultCounterBatteryLines = 4                // Lines of bearing needed before a site is estimated
+	DefaultCounterBatteryDelay = 90 * time.Second // From tasking a strike to impact
+	counterBatteryRadius       = 1500.0           // Meters; a strike this close to a site suppresses it
+	counterBatteryBaseline     = 1000.0           // Meters a track must fly before its back-bearing is taken
+	counterBatteryCluster      = 20.0             // Degrees; detections this close in bearing are assumed to share a site
+	bearingNoise               = 3.0              // Degrees of error on each line of bearing (1 sigma)
+)
+
+// bearingLine is a track's observed path run backwards, in meters east/north of the base
+type bearingLine struct {
+	X, Y       float64 // Where the track was first seen
+	DirX, DirY float64 // Unit vector pointing back the way it came
+}
+
+// siteEstimate collects lines of bearing that appear to come from the same launch site
+type siteEstimate struct {
+	Bearing  float64 // Bearing from the base of the first line's origin
+	Lines    []bearingLine
+	X, Y     float64   // Estimated site once tasked
+	StrikeAt time.Time // Zero until a strike is tasked
+	Struck   bool      // Site destroyed; later tracks from this direction are ignored
+}
+
+// counterBatteryLog holds blue's launch-site picture. Blue only sees tracks, so sites are
+// estimated from where detected drones came from, never from the scenario's site list.
+type counterBatteryLog struct {
+	origins    map[uuid.UUID][2]float64 // First non-forming sighting of each track, local meters
+	taken      map[uuid.UUID]bool       // Tracks whose line of bearing has been recorded
+	estimates  []*siteEstimate
+	suppressed map[string]bool // Launch sites knocked out by a strike
+}
+
+// CounterBatteryStats tracks strikes on launch sites
+type CounterBatteryStats struct {
+	Tasked            int
+	SitesSuppressed   int
+	LaunchesPrevented int
+}
+
+// counterBatteryActive reports whether blue is hunting launch sites this run
+func (s *DroneSwarmSimulation) counterBatteryActive() bool {
+	return s.config.CounterBattery && len(s.config.LaunchSites) > 0 && s.ownsBlueForce()
+}
+
+// localMeters projects lat/lon onto a flat plane centred on the base (x east, y north)
+func (s *DroneSwarmSimulation) localMeters(lat, lon float64) (float64, float64) {
+	const earthRadius = 6371000.0
+	lat0 := s.config.BaseLocation.Lat * math.Pi / 180
+	x := (lon - s.config.BaseLocation.Lon) * math.Pi / 180 * math.Cos(lat0) * earthRadius
+	y := (lat - s.config.BaseLocation.Lat) * math.Pi / 180 * earthRadius
+	return x, y
+}
+
+// observeBearing records a detected track's back-bearing once it has flown far enough
+// from where it was first seen to give a usable line
+func (s *DroneSwarmSimulation) observeBearing(threat *UASThreat) {
+	if !s.counterBatteryActive() || threat.ObservedBehavior == BehaviorForming {
+		return
+	}
+	if s.counterBattery.origins == nil {
+		s.counterBattery.origins = make(map[uuid.UUID][2]float64)
+		s.counterBattery.taken = make(map[uuid.UUID]bool)
+		s.counterBattery.suppressed = make(map[string]bool)
+	}
+	if s.counterBattery.taken[threat.ID] {
+		return
+	}
+
+	lat, lon, _ := ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+	x, y := s.localMeters(lat, lon)
+	origin, seen := s.counterBattery.origins[threat.ID]
+	if !seen {
+		s.counterBattery.origins[threat.ID] = [2]float64{x, y}
+		return
+	}
+
+	dx, dy := origin[0]-x, origin[1]-y
+	length := math.Hypot(dx, dy)
+	if length < counterBatteryBaseline {
+		return
+	}
+	s.counterBattery.taken[threat.ID] = true
+
+	// Sensor error swings the line about its origin
+	angle := math.Atan2(dy, dx) + threat.random().NormFloat64()*bearingNoise*math.Pi/180
+	line := bearingLine{X: origin[0], Y: origin[1], DirX: math.Cos(angle), DirY: math.Sin(angle)}
+	bearing := math.Mod(math.Atan2(origin[0], origin[1])*180/math.Pi+360, 360)
+
+	for _, estimate := range s.counterBattery.estimates {
+		if angleBetween(estimate.Bearing, bearing) <= counterBatteryCluster {
+			if !estimate.Struck {
+				estimate.Lines = append(estimate.Lines, line)
+			}
+			return
+		}
+	}
+	s.counterBattery.estimates = append(s.counterBattery.estimates, &siteEstimate{Bearing: bearing, Lines: []bearingLine{line}})
+}
+
+// angleBetween returns the smallest difference between two bearings in degrees
+func angleBetween(a, b float64) float64 {
+	diff := math.Mod(math.Abs(a-b), 360)
+	return math.Min(diff, 360-diff)
+}
+
+// intersectLines returns the least-squares crossing point of lines of bearing.
+// ok is false when the lines are too close to parallel to fix a point.
+func intersectLines(lines []bearingLine) (x, y float64, ok bool) {
+	var a11, a12, a22, b1, b2 float64
+	for _, l := range lines {
+		// Projection onto the line's normal: I - d dᵀ
+		m11, m12, m22 := 1-l.DirX*l.DirX, -l.DirX*l.DirY, 1-l.DirY*l.DirY
+		a11 += m11
+		a12 += m12
+		a22 += m22
+		b1 += m11*l.X + m12*l.Y
+		b2 += m12*l.X + m22*l.Y
+	}
+
+	det := a11*a22 - a12*a12
+	if math.Abs(det) < 1e-6*float64(len(lines)*len(lines)) {
+		return 0, 0, false
+	}
+	return (a22*b1 - a12*b2) / det, (a11*b2 - a12*b1) / det, true
+}
+
+// updateCounterBattery tasks strikes on sites with enough lines of bearing and resolves
+// strikes whose time of flight has elapsed
+func (s *DroneSwarmSimulation) updateCounterBattery() {
+	if !s.counterBatteryActive() {
+		return
+	}
+
+	now := s.scenarioNow()
+	for _, estimate := range s.counterBattery.estimates {
+		switch {
+		case estimate.Struck:
+			continue
+		case estimate.StrikeAt.IsZero() && len(estimate.Lines) >= s.config.CounterBatteryLines:
+			x, y, ok := intersectLines(estimate.Lines)
+			if !ok {
+				continue
+			}
+			estimate.X, estimate.Y = x, y
+			estimate.StrikeAt = now.Add(s.config.CounterBatteryDelay)
+
+			s.stats.mu.Lock()
+			s.stats.CounterBattery.Tasked++
+			s.stats.mu.Unlock()
+
+			bearing := math.Mod(math.Atan2(x, y)*180/math.Pi+360, 360)
+			engagementLog.Warnf("🎯 Counter-battery: launch site estimated %.1fkm at %03.0f° from %d tracks, strike in %s",
+				math.Hypot(x, y)/1000, bearing, len(estimate.Lines), s.config.CounterBatteryDelay)
+
+		case !estimate.StrikeAt.IsZero() && !now.Before(estimate.StrikeAt):
+			s.resolveStrike(estimate)
+		}
+	}
+}
+
+// resolveStrike lands a strike on an estimated site. A hit within range of a real site
+// stops its remaining launches; a miss discards the lines so a fresh estimate is built.
+func (s *DroneSwarmSimulation) resolveStrike(estimate *siteEstimate) {
+	hitSite, miss := "", math.Inf(1)
+	for _, site := range s.config.LaunchSites {
+		if s.counterBattery.suppressed[site.Name] {
+			continue
+		}
+		lat, lon := destinationPoint(s.config.BaseLocation.Lat, s.config.BaseLocation.Lon, site.BearingDeg, site.DistanceKm*1000)
+		x, y := s.localMeters(lat, lon)
+		if d := math.Hypot(x-estimate.X, y-estimate.Y); d < miss {
+			hitSite, miss = site.Name, d
+		}
+	}
+
+	if hitSite == "" {
+		estimate.Struck = true // Every site is already down
+		return
+	}
+
+	lines := len(estimate.Lines)
+	if miss > counterBatteryRadius {
+		engagementLog.Warnf("💨 Counter-battery strike missed (%.0fm from the nearest active site)", miss)
+		s.simLogger.LogStrike("", false, miss, 0, lines)
+		estimate.Lines = nil
+		estimate.StrikeAt = time.Time{}
+		return
+	}
+
+	s.counterBattery.suppressed[hitSite] = true
+	prevented := 0
+	for _, threat := range s.uasThreats {
+		if threat.LaunchSite != hitSite || threat.LaunchPhase != LaunchPhaseGrounded || threat.Remote {
+			continue
+		}
+		threat.UpdateClassification(TrackStatusDestroyed)
+		s.queueClassificationUpdate(threat)
+		s.simLogger.LogDestruction(threat.ID, s.factionOf(threat).Name, "counter-battery strike on launch site "+hitSite)
+		prevented++
+	}
+
+	s.stats.mu.Lock()
+	s.stats.CounterBattery.SitesSuppressed++
+	s.stats.CounterBattery.LaunchesPrevented += prevented
+	s.stats.mu.Unlock()
+
+	estimate.Struck = true
+	estimate.Lines = nil
+
+	engagementLog.Warnf("💥 Counter-battery strike hit launch site %s (%.0fm off): %d launches stopped", hitSite, miss, prevented)
+	s.simLogger.LogStrike(hitSite, true, miss, prevented, lines)
+}
@@ -0,0 +1,66 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/core"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+)
+
+func TestIntersectLines(t *testing.T) {
+	// Three lines radiating from a site 10km north, 2km east of the base
+	siteX, siteY := 2000.0, 10000.0
+	var lines []bearingLine
+	for _, p := range [][2]float64{{0, 4000}, {3000, 5000}, {-1000, 6000}} {
+		dx, dy := siteX-p[0], siteY-p[1]
+		n := math.Hypot(dx, dy)
+		lines = append(lines, bearingLine{X: p[0], Y: p[1], DirX: dx / n, DirY: dy / n})
+	}
+
+	x, y, ok := intersectLines(lines)
+	if !ok || math.Abs(x-siteX) > 1 || math.Abs(y-siteY) > 1 {
+		t.Errorf("expected (%.0f, %.0f), got (%.0f, %.0f) ok=%v", siteX, siteY, x, y, ok)
+	}
+
+	parallel := []bearingLine{{X: 0, Y: 0, DirX: 0, DirY: 1}, {X: 100, Y: 0, DirX: 0, DirY: 1}}
+	if _, _, ok := intersectLines(parallel); ok {
+		t.Error("expected parallel lines to give no fix")
+	}
+}
+
+func TestResolveStrikeStopsGroundedLaunches(t *testing.T) {
+	s := &DroneSwarmSimulation{
+		config: SimulationConfig{
+			BaseLocation:   Location{Lat: 40, Lon: -76},
+			LaunchSites:    []LaunchSite{{Name: "North", DistanceKm: 15, BearingDeg: 0}},
+			CounterBattery: true,
+		},
+		uasThreats:     make(map[uuid.UUID]*UASThreat),
+		simLogger:      reporting.NewSimulationLogger("test"),
+		counterBattery: counterBatteryLog{suppressed: make(map[string]bool)},
+	}
+	s.updateBuffer = core.NewUpdateBuffer(nil, "", 50, time.Hour)
+
+	grounded := &UASThreat{ID: uuid.New(), LaunchSite: "North", LaunchPhase: LaunchPhaseGrounded, Classification: TrackStatusPending}
+	airborne := &UASThreat{ID: uuid.New(), LaunchSite: "North", LaunchPhase: LaunchPhaseTransit, Classification: TrackStatusHostile}
+	s.uasThreats[grounded.ID], s.uasThreats[airborne.ID] = grounded, airborne
+
+	// 1km off the true site is inside the strike radius
+	lat, lon := destinationPoint(40, -76, 0, 16000)
+	x, y := s.localMeters(lat, lon)
+	estimate := &siteEstimate{X: x, Y: y, StrikeAt: time.Now()}
+	s.resolveStrike(estimate)
+
+	if !s.counterBattery.suppressed["North"] || !estimate.Struck {
+		t.Fatal("expected the site to be suppressed")
+	}
+	if grounded.Classification != TrackStatusDestroyed || airborne.Classification != TrackStatusHostile {
+		t.Errorf("expected only the grounded drone to be destroyed, got %s and %s", grounded.Classification, airborne.Classification)
+	}
+	if s.stats.CounterBattery.LaunchesPrevented != 1 {
+		t.Errorf("expected 1 launch prevented, got %d", s.stats.CounterBattery.LaunchesPrevented)
+	}
+}
@@ -0,0 +1,132 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/coverage"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// Coverage map layout
+const (
+	coverageGridSize = 256 // Raster cells per side
+	defenseRadiusKm  = 5.0 // Counter-UAS ring around the base
+)
+
+// coverageLog collects where threats were first detected, engaged and leaked
+type coverageLog struct {
+	rasters []*coverage.Raster // Pre-run coverage, one per altitude band
+	points  []coverage.Point
+	mu      sync.Mutex
+}
+
+// recordCoveragePoint notes an event at a threat's current position for the post-run maps
+func (s *DroneSwarmSimulation) recordCoveragePoint(threat *UASThreat, kind string) {
+	if !s.config.CoverageMaps || threat.Position == nil {
+		return
+	}
+	lat, lon, _ := ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+
+	s.coverage.mu.Lock()
+	s.coverage.points = append(s.coverage.points, coverage.Point{Lat: lat, Lon: lon, Kind: kind})
+	s.coverage.mu.Unlock()
+}
+
+// coverageSystems describes the deployed defenders for coverage analysis. RF detection is
+// left out because it only sees emitting threats.
+func (s *DroneSwarmSimulation) coverageSystems() []coverage.System {
+	systems := make([]coverage.System, 0, len(s.counterUASSystems))
+	for _, system := range s.counterUASSystems {
+		system.mu.RLock()
+		lat, lon, alt := ecefToLatLonAlt(system.Position.Coordinates[0], system.Position.Coordinates[1], system.Position.Coordinates[2])
+		systems = append(systems, coverage.System{
+			Name:          system.Callsign,
+			Lat:           lat,
+			Lon:           lon,
+			HeightM:       math.Max(alt-s.config.BaseLocation.Alt, 0),
+			SensorRangeKm: math.Max(system.RadarRange, system.EOIRRange),
+			WeaponRangeKm: system.EffectiveRange,
+		})
+		system.mu.RUnlock()
+	}
+	return systems
+}
+
+// analyzeCoverage rasterizes pre-run coverage for each altitude band and writes the maps
+func (s *DroneSwarmSimulation) analyzeCoverage() error {
+	if !s.config.CoverageMaps {
+		return nil
+	}
+
+	systems := s.coverageSystems()
+	if len(systems) == 0 {
+		return nil // Workers own no defenders
+	}
+
+	// Extend the grid past the outermost system's sensor range
+	halfWidth := 0.0
+	for _, system := range s.counterUASSystems {
+		offset := s.distanceToBaseKm(system.Position)
+		halfWidth = math.Max(halfWidth, offset+math.Max(system.RadarRange, system.EOIRRange))
+	}
+	grid := coverage.Grid{
+		CenterLat:   s.config.BaseLocation.Lat,
+		CenterLon:   s.config.BaseLocation.Lon,
+		HalfWidthKm: math.Ceil(halfWidth * 1.1),
+		Size:        coverageGridSize,
+	}
+
+	terrain := s.coverageTerrain()
+	s.coverage.rasters = nil
+	for _, band := range coverage.DefaultBands {
+		raster := coverage.Analyze(grid, systems, band, terrain)
+		s.coverage.rasters = append(s.coverage.rasters, raster)
+
+		sensor, weapon := raster.Share(defenseRadiusKm)
+		logger.Infof("📡 Coverage at %s altitude (%.0fm AGL) inside the %.0fkm perimeter: sensors %.0f%%, weapons %.0f%%",
+			band.Name, band.AltitudeM, defenseRadiusKm, sensor*100, weapon*100)
+	}
+
+	return s.writeCoverageMaps("pre", nil)
+}
+
+// writeCoverageMaps renders every band to PNG (with a world file) in the reports directory
+func (s *DroneSwarmSimulation) writeCoverageMaps(stage string, points []coverage.Point) error {
+	if err := os.MkdirAll(reportsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	for _, raster := range s.coverage.rasters {
+		name := fmt.Sprintf("Coverage_%s_%s_%s.png", s.runID[:8], stage, raster.Band.Name)
+		path := filepath.Join(reportsDir, name)
+		if err := raster.WritePNG(path, points); err != nil {
+			return fmt.Errorf("failed to write coverage map: %w", err)
+		}
+		s.artifacts = append(s.artifacts, path, strings.TrimSuffix(path, ".png")+".pgw")
+		if s.aarGenerator != nil {
+			s.aarGenerator.AddAttachment(path)
+		}
+	}
+	return nil
+}
+
+// saveCoverageOverlay writes the post-run maps with detection, engagement and leaker points
+func (s *DroneSwarmSimulation) saveCoverageOverlay() error {
+	if len(s.coverage.rasters) == 0 {
+		return nil
+	}
+
+	s.coverage.mu.Lock()
+	points := append([]coverage.Point(nil), s.coverage.points...)
+	s.coverage.mu.Unlock()
+
+	if err := s.writeCoverageMaps("post", points); err != nil {
+		return err
+	}
+	logger.Successf("Coverage maps saved to: %s (%d events overlaid)", reportsDir, len(points))
+	return nil
+}
@@ -0,0 +1,282 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/cues"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// cuedAltitude is the height above the base a cue without an altitude flies at, meters
+const cuedAltitude = 150.0
+
+// cuedRadarCrossSection is the radar cross section given to a cued track of each size
+// class, m²
+var cuedRadarCrossSection = map[string]float64{
+	UASSizeGroup1: 0.03,
+	UASSizeGroup2: 0.12,
+	UASSizeGroup3: 0.35,
+	UASSizeGroup4: 0.75,
+	UASSizeGroup5: 1.0,
+}
+
+// cueState tracks the external cue sources and the tracks they put in the air
+type cueState struct {
+	inbox   *cues.Inbox
+	servers []*http.Server
+	cancel  context.CancelFunc
+	byID    map[string]uuid.UUID // Cue ID to the track it spawned
+	spawned int
+	moved   int
+	dropped int // Accepted by a source but not placed, e.g. an unknown faction
+}
+
+// checkCueSources rejects feed sources whose target is not a feed definition ID
+func checkCueSources(sources []cues.Source) error {
+	for _, source := range sources {
+		if source.Kind != cues.SourceFeed {
+			continue
+		}
+		if _, err := uuid.Parse(source.Target); err != nil {
+			return fmt.Errorf("cue source %s: feed must be a feed definition ID", source)
+		}
+	}
+	return nil
+}
+
+// startCues opens the configured cue sources. Cues they receive are applied once per
+// tick by applyCues.
+func (s *DroneSwarmSimulation) startCues(ctx context.Context) error {
+	if len(s.config.CueSources) == 0 {
+		return nil
+	}
+
+	cueCtx, cancel := context.WithCancel(ctx)
+	s.cues = cueState{inbox: cues.NewInbox(), cancel: cancel, byID: make(map[string]uuid.UUID)}
+	for _, source := range s.config.CueSources {
+		switch source.Kind {
+		case cues.SourceHTTP:
+			s.cues.servers = append(s.cues.servers, cues.Serve(s.cues.inbox, source.Target, s.config.CueToken))
+		case cues.SourceFile:
+			if err := cues.Tail(cueCtx, s.cues.inbox, source.Target); err != nil {
+				s.stopCues()
+				return err
+			}
+			logger.Infof("%s Following threat cues in %s", logger.IconNetwork, source.Target)
+		case cues.SourceFeed:
+			feedID, _ := uuid.Parse(source.Target) // Checked when configured
+			cues.PollFeed(cueCtx, s.cues.inbox, s.legionClient, s.config.OrganizationID, feedID)
+			logger.Infof("%s Polling feed %s for threat cues", logger.IconNetwork, feedID)
+		}
+	}
+	return nil
+}
+
+// stopCues closes the cue sources
+func (s *DroneSwarmSimulation) stopCues() {
+	if s.cues.cancel == nil {
+		return
+	}
+	s.cues.cancel()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	for _, server := range s.cues.servers {
+		_ = server.Shutdown(ctx)
+	}
+}
+
+// applyCues spawns a track for each cue received since the last tick, or moves the
+// track an earlier cue with the same ID spawned
+func (s *DroneSwarmSimulation) applyCues(ctx context.Context) {
+	if s.cues.inbox == nil {
+		return
+	}
+	for _, cue := range s.cues.inbox.Drain() {
+		if id, ok := s.cues.byID[cue.ID]; ok && cue.ID != "" {
+			s.moveCuedThreat(id, cue)
+			continue
+		}
+		if err := s.spawnCuedThreat(ctx, cue); err != nil {
+			if errors.Is(err, context.Canceled) {
+				return
+			}
+			logger.Warnf("Dropping cue from %s: %v", cue.Source, err)
+			s.cues.dropped++
+		}
+	}
+}
+
+// spawnCuedThreat creates a track where a cue places it, flying the cue's heading
+func (s *DroneSwarmSimulation) spawnCuedThreat(ctx context.Context, cue cues.Cue) error {
+	faction := s.config.Factions[0].Name
+	if cue.Faction != "" {
+		if !s.hasFaction(cue.Faction) {
+			return fmt.Errorf("unknown faction %q", cue.Faction)
+		}
+		faction = cue.Faction
+	}
+	rcs, ok := cuedRadarCrossSection[cue.Type]
+	if cue.Type != "" && !ok {
+		return fmt.Errorf("unknown size class %q", cue.Type)
+	}
+
+	trackNumber := s.nextTrackNumber()
+	pointType := "Point"
+	position := &models.GeomPoint{Type: &pointType, Coordinates: make([]float64, 3)}
+
+	// Cued tracks belong to no planned wave
+	threat := NewUASThreat(trackNumber, position, 0, s.random())
+	threat.ActualCapabilities.Faction = faction
+	threat.ActualCapabilities.PayloadType = randomPayloadFrom(s.payloads(), s.random())
+	threat.History = NewTrackHistory(s.config.TrackHistoryDepth)
+	if cue.Type != "" {
+		threat.SizeClass = cue.Type
+		threat.RadarCrossSection = rcs
+		threat.AcousticSignature = cue.Type != UASSizeGroup1
+	}
+	s.placeCuedThreat(threat, cue)
+
+	metadata, err := json.Marshal(threat.GetMetadata())
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+	metadataRaw := json.RawMessage(metadata)
+	orgID, err := uuid.Parse(s.config.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("invalid organization ID: %w", err)
+	}
+	category := models.CategoryTRACK
+	entityType := EntityTypeUAS
+	entityReq := &models.CreateEntityRequest{
+		OrganizationID: &orgID,
+		Name:           &trackNumber,
+		Category:       &category,
+		Type:           &entityType,
+		Status:         &threat.Classification,
+		Affiliation:    threat.Affiliation,
+		Metadata:       &metadataRaw,
+	}
+	s.applyEntityTemplate(TemplateThreat, entityReq)
+
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	createdEntity, err := s.legionClient.CreateEntity(s.idempotent(orgCtx, "entity", trackNumber), entityReq)
+	if err != nil {
+		return fmt.Errorf("failed to create UAS entity %s: %w", trackNumber, err)
+	}
+	threat.ID = createdEntity.ID
+	threat.PublishedAffiliation = entityReq.Affiliation
+	s.recordEntity(threat.ID, trackNumber, entityType)
+
+	s.mu.Lock()
+	s.uasThreats[threat.ID] = threat
+	s.mu.Unlock()
+	if cue.ID != "" {
+		s.cues.byID[cue.ID] = threat.ID
+	}
+	s.cues.spawned++
+
+	recordedAt := s.recordedAt(threat.ID)
+	locationReq := &models.CreateEntityLocationRequest{
+		Position:   s.observedPosition(threat),
+		Source:     "Drone-Swarm-Simulation",
+		RecordedAt: &recordedAt,
+	}
+	if _, err := s.legionClient.CreateEntityLocation(orgCtx, threat.ID.String(), locationReq); err != nil {
+		logger.Warnf("Failed to place cued track %s: %v", trackNumber, err)
+	}
+	s.recordTrackHistory(threat)
+
+	logger.Infof("🔴 New air track cued by %s: %s", cue.Source, trackNumber)
+	s.simLogger.LogCue(threat.ID, trackNumber, cue.ID, cue.Source, true)
+	return nil
+}
+
+// moveCuedThreat puts a cued track where a later cue with its ID reports it
+func (s *DroneSwarmSimulation) moveCuedThreat(id uuid.UUID, cue cues.Cue) {
+	s.mu.RLock()
+	threat, ok := s.uasThreats[id]
+	s.mu.RUnlock()
+	if !ok {
+		return
+	}
+
+	threat.mu.Lock()
+	terminal := threat.Classification == TrackStatusDestroyed || threat.Classification == TrackStatusLost
+	if !terminal {
+		s.placeCuedThreat(threat, cue)
+	}
+	threat.mu.Unlock()
+	if terminal {
+		return
+	}
+
+	s.cues.moved++
+	s.simLogger.LogCue(threat.ID, threat.TrackNumber, cue.ID, cue.Source, false)
+}
+
+// placeCuedThreat sets a track's true position and velocity from a cue, keeping its
+// own speed when the cue gives none
+func (s *DroneSwarmSimulation) placeCuedThreat(threat *UASThreat, cue cues.Cue) {
+	alt := cue.Alt
+	if alt <= 0 {
+		alt = s.config.BaseLocation.Alt + cuedAltitude
+	}
+	if cue.SpeedMps > 0 {
+		threat.ActualCapabilities.SpeedKph = cue.SpeedMps * 3.6
+	}
+	speed := threat.ActualCapabilities.SpeedKph / 3.6
+
+	x, y, z := latLonAltToECEF(cue.Lat, cue.Lon, alt)
+	aheadLat, aheadLon := destinationPoint(cue.Lat, cue.Lon, cue.HeadingDeg, speed)
+	ax, ay, az := latLonAltToECEF(aheadLat, aheadLon, alt)
+
+	threat.Position.Coordinates[0] = x
+	threat.Position.Coordinates[1] = y
+	threat.Position.Coordinates[2] = z
+	threat.EstimatedAltitude = z
+	threat.ActualVelocity.Coordinates[0] = ax - x
+	threat.ActualVelocity.Coordinates[1] = ay - y
+	threat.ActualVelocity.Coordinates[2] = az - z
+	threat.LastSeenTime = time.Now()
+}
+
+// hasFaction reports whether a faction by that name is configured
+func (s *DroneSwarmSimulation) hasFaction(name string) bool {
+	for _, f := range s.config.Factions {
+		if f.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// plannedThreats returns the number of threats the run was scored against: the
+// configured force plus any tracks spawned by cues
+func (s *DroneSwarmSimulation) plannedThreats() int {
+	return s.config.NumUASThreats + s.cues.spawned
+}
+
+// recordCueMetrics records how many cues arrived and the tracks they spawned
+func (s *DroneSwarmSimulation) recordCueMetrics() {
+	if s.cues.inbox == nil {
+		return
+	}
+	received, rejected := s.cues.inbox.Counts()
+	s.simLogger.UpdateMetric(reporting.MetricCuesReceived, float64(received), "count")
+	s.simLogger.UpdateMetric(reporting.MetricCuesRejected, float64(rejected+s.cues.dropped), "count")
+	s.simLogger.UpdateMetric(reporting.MetricCuedTracks, float64(s.cues.spawned), "count")
+
+	logger.Infof("Cues: %d received, %d rejected; %d tracks spawned, %d moves",
+		received, rejected+s.cues.dropped, s.cues.spawned, s.cues.moved)
+}
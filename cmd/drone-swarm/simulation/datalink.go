@@ -0,0 +1,444 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// Datalink feed
+const (
+	EntityTypeDatalinkGateway = "DatalinkGateway" // Blue Force - gateway forwarding the simulated datalink
+	datalinkName              = "Datalink-Gateway"
+	datalinkFeedBase          = "cuas_datalink_"
+	datalinkFeedLimit         = 2 * time.Second
+	DatalinkSchema            = "legion-sim/datalink/v1"
+	datalinkGatewayJU         = 0o1 // Source track number of the gateway itself
+	datalinkFirstShooterJU    = 0o20
+)
+
+// Datalink message labels, modeled on the Link 16 J-series messages that carry the
+// same information. The JSON structure is the simulation's own, not a J-series encoding.
+const (
+	DatalinkTrackManagement     = "J7.0"  // Track dropped
+	DatalinkAirTrack            = "J3.2"  // Periodic air track report
+	DatalinkEngagementStatus    = "J10.2" // Shot fired and its outcome
+	DatalinkWeaponsCoordination = "J10.6" // Weapon paired with a track
+)
+
+// Engagement status values
+const (
+	DatalinkStatusDestroyed = "DESTROYED"
+	DatalinkStatusMissed    = "MISSED"
+)
+
+// DatalinkBatch is one feed message: every datalink message since the last batch
+type DatalinkBatch struct {
+	Schema   string            `json:"schema"`
+	RunID    string            `json:"run_id"`
+	Batch    int               `json:"batch"`
+	Sent     string            `json:"sent"` // UTC, nanosecond precision
+	Messages []DatalinkMessage `json:"messages"`
+}
+
+// DatalinkMessage is a single tactical datalink message. Exactly one of the bodies is set,
+// matching its label.
+type DatalinkMessage struct {
+	Label      string              `json:"label"`
+	Sequence   int                 `json:"seq"`       // Run-wide, increasing in send order
+	SourceJU   string              `json:"source_ju"` // Five-digit octal source track number of the sending unit
+	Time       string              `json:"time"`      // UTC, nanosecond precision
+	SimTimeS   float64             `json:"sim_time_s"`
+	Track      *DatalinkTrack      `json:"track,omitempty"`
+	Drop       *DatalinkDrop       `json:"drop,omitempty"`
+	Pairing    *DatalinkPairing    `json:"pairing,omitempty"`
+	Engagement *DatalinkEngagement `json:"engagement,omitempty"`
+}
+
+// DatalinkTrack is an air track report as the reporting unit's sensors measure it
+type DatalinkTrack struct {
+	TrackNumber string  `json:"track_number"` // Five-digit octal
+	TrackName   string  `json:"track_name"`   // The simulation's track number, e.g. TK-4521
+	Identity    string  `json:"identity"`     // PENDING, UNKNOWN, SUSPECT, HOSTILE or NEUTRAL
+	Lat         float64 `json:"lat"`
+	Lon         float64 `json:"lon"`
+	AltM        float64 `json:"alt_m"`
+	CourseDeg   float64 `json:"course_deg"`
+	SpeedMps    float64 `json:"speed_mps"`
+}
+
+// DatalinkDrop drops a track that was destroyed or lost from the picture
+type DatalinkDrop struct {
+	TrackNumber string `json:"track_number"`
+	Reason      string `json:"reason"` // DESTROYED or LOST
+}
+
+// DatalinkPairing pairs a weapon with the track it is assigned
+type DatalinkPairing struct {
+	TrackNumber string `json:"track_number"`
+	WeaponJU    string `json:"weapon_ju"`
+	Weapon      string `json:"weapon"` // KINETIC or EW
+}
+
+// DatalinkEngagement reports one engagement and its outcome
+type DatalinkEngagement struct {
+	TrackNumber string  `json:"track_number"`
+	WeaponJU    string  `json:"weapon_ju"`
+	Weapon      string  `json:"weapon"`
+	Status      string  `json:"status"` // DESTROYED or MISSED
+	RangeKm     float64 `json:"range_km"`
+}
+
+// datalinkLog holds the datalink feed and the messages waiting for the next batch.
+// Engagement goroutines queue messages concurrently.
+type datalinkLog struct {
+	mu         sync.Mutex
+	entityID   uuid.UUID
+	feedID     uuid.UUID
+	lastTracks time.Time
+	pending    []DatalinkMessage
+	sequence   int
+	batches    int
+	sent       int
+	tracks     map[uuid.UUID]int       // Octal track number by threat
+	units      map[uuid.UUID]int       // Source track number by system
+	paired     map[uuid.UUID]uuid.UUID // Track each system was last paired with
+	dropped    map[uuid.UUID]bool
+}
+
+// octalTN formats a track number as Link 16 does, five octal digits
+func octalTN(n int) string {
+	return fmt.Sprintf("%05o", n)
+}
+
+// datalinkIdentity maps a track classification to its datalink identity
+func datalinkIdentity(classification string) string {
+	switch classification {
+	case TrackStatusHostile:
+		return "HOSTILE"
+	case TrackStatusSuspected:
+		return "SUSPECT"
+	case TrackStatusNeutral:
+		return "NEUTRAL"
+	case TrackStatusUnknown:
+		return "UNKNOWN"
+	}
+	return "PENDING"
+}
+
+// datalinkWeapon names an engagement type on the datalink
+func datalinkWeapon(engagementType string) string {
+	if engagementType == EngagementTypeKinetic {
+		return "KINETIC"
+	}
+	return "EW"
+}
+
+// datalinkActive reports whether the datalink feed is being published
+func (s *DroneSwarmSimulation) datalinkActive() bool {
+	return s.config.DatalinkInterval > 0 && s.datalink.feedID != uuid.Nil
+}
+
+// trackNumber returns the threat's octal track number, assigning the next free one
+// the first time it goes out. Callers hold s.datalink.mu.
+func (s *DroneSwarmSimulation) trackNumber(threatID uuid.UUID) string {
+	if s.datalink.tracks == nil {
+		s.datalink.tracks = make(map[uuid.UUID]int)
+	}
+	n, ok := s.datalink.tracks[threatID]
+	if !ok {
+		// Track numbers sit above the block reserved for the units themselves
+		n = 0o1000 + len(s.datalink.tracks)
+		s.datalink.tracks[threatID] = n
+	}
+	return octalTN(n)
+}
+
+// unitJU returns a system's source track number. Callers hold s.datalink.mu.
+func (s *DroneSwarmSimulation) unitJU(systemID uuid.UUID) string {
+	if s.datalink.units == nil {
+		s.datalink.units = make(map[uuid.UUID]int)
+	}
+	n, ok := s.datalink.units[systemID]
+	if !ok {
+		n = datalinkFirstShooterJU + len(s.datalink.units)
+		s.datalink.units[systemID] = n
+	}
+	return octalTN(n)
+}
+
+// queueDatalink stamps a message and holds it for the next batch. Callers hold s.datalink.mu.
+func (s *DroneSwarmSimulation) queueDatalink(msg DatalinkMessage, now time.Time) {
+	s.datalink.sequence++
+	msg.Sequence = s.datalink.sequence
+	msg.Time = now.UTC().Format(time.RFC3339Nano)
+	msg.SimTimeS = s.tickSimTime().Seconds()
+	s.datalink.pending = append(s.datalink.pending, msg)
+}
+
+// datalinkPairing reports a system being paired with a new target
+func (s *DroneSwarmSimulation) datalinkPairing(system *CounterUASSystem, threat *UASThreat) {
+	if !s.datalinkActive() {
+		return
+	}
+	s.datalink.mu.Lock()
+	defer s.datalink.mu.Unlock()
+
+	if s.datalink.paired == nil {
+		s.datalink.paired = make(map[uuid.UUID]uuid.UUID)
+	}
+	if s.datalink.paired[system.ID] == threat.ID {
+		return
+	}
+	s.datalink.paired[system.ID] = threat.ID
+
+	ju := s.unitJU(system.ID)
+	s.queueDatalink(DatalinkMessage{
+		Label:    DatalinkWeaponsCoordination,
+		SourceJU: ju,
+		Pairing: &DatalinkPairing{
+			TrackNumber: s.trackNumber(threat.ID),
+			WeaponJU:    ju,
+			Weapon:      datalinkWeapon(system.EngagementType),
+		},
+	}, time.Now())
+}
+
+// datalinkEngagement reports an engagement's outcome
+func (s *DroneSwarmSimulation) datalinkEngagement(system *CounterUASSystem, threat *UASThreat, result *EngagementResult) {
+	if !s.datalinkActive() {
+		return
+	}
+	s.datalink.mu.Lock()
+	defer s.datalink.mu.Unlock()
+
+	status := DatalinkStatusMissed
+	if result.Success {
+		status = DatalinkStatusDestroyed
+	}
+	ju := s.unitJU(system.ID)
+	s.queueDatalink(DatalinkMessage{
+		Label:    DatalinkEngagementStatus,
+		SourceJU: ju,
+		Engagement: &DatalinkEngagement{
+			TrackNumber: s.trackNumber(threat.ID),
+			WeaponJU:    ju,
+			Weapon:      datalinkWeapon(result.EngageType),
+			Status:      status,
+			RangeKm:     math.Round(result.Distance*1000) / 1000,
+		},
+	}, time.Now())
+}
+
+// queueTrackReports queues an air track report for every track on the picture, from the
+// unit whose sensor measures it best, and drops tracks that were destroyed or lost
+func (s *DroneSwarmSimulation) queueTrackReports(now time.Time) {
+	threats := make([]*UASThreat, 0, len(s.uasThreats))
+	for _, threat := range s.uasThreats {
+		threats = append(threats, threat)
+	}
+	sort.Slice(threats, func(i, j int) bool { return threats[i].TrackNumber < threats[j].TrackNumber })
+
+	s.datalink.mu.Lock()
+	defer s.datalink.mu.Unlock()
+	if s.datalink.dropped == nil {
+		s.datalink.dropped = make(map[uuid.UUID]bool)
+	}
+
+	for _, threat := range threats {
+		threat.mu.RLock()
+		classification := threat.Classification
+		threat.mu.RUnlock()
+
+		_, reported := s.datalink.tracks[threat.ID]
+		switch classification {
+		case TrackStatusPending:
+			continue
+		case TrackStatusDestroyed, TrackStatusLost:
+			if reported && !s.datalink.dropped[threat.ID] {
+				s.datalink.dropped[threat.ID] = true
+				s.queueDatalink(DatalinkMessage{
+					Label:    DatalinkTrackManagement,
+					SourceJU: octalTN(datalinkGatewayJU),
+					Drop:     &DatalinkDrop{TrackNumber: s.trackNumber(threat.ID), Reason: classification},
+				}, now)
+			}
+			continue
+		}
+
+		track := s.datalinkTrack(threat, classification)
+		if track == nil {
+			continue
+		}
+		source := octalTN(datalinkGatewayJU)
+		if sensor, _ := s.measuringSensor(threat); sensor != nil {
+			source = s.unitJU(sensor.ID)
+		}
+		track.TrackNumber = s.trackNumber(threat.ID)
+		s.queueDatalink(DatalinkMessage{Label: DatalinkAirTrack, SourceJU: source, Track: track}, now)
+	}
+}
+
+// datalinkTrack builds a track report from the threat's observed positions, so the
+// picture on the datalink carries the same sensor error as the published tracks
+func (s *DroneSwarmSimulation) datalinkTrack(threat *UASThreat, classification string) *DatalinkTrack {
+	if threat.ObservedHistory == nil {
+		return nil
+	}
+	recent := threat.ObservedHistory.Recent(2)
+	if len(recent) == 0 {
+		return nil
+	}
+	latest := recent[len(recent)-1]
+	track := &DatalinkTrack{
+		TrackName: threat.TrackNumber,
+		Identity:  datalinkIdentity(classification),
+		Lat:       math.Round(latest.Lat*1e7) / 1e7,
+		Lon:       math.Round(latest.Lon*1e7) / 1e7,
+		AltM:      math.Round(latest.Alt*10) / 10,
+	}
+	if len(recent) == 2 {
+		previous := recent[0]
+		if dt := latest.Timestamp.Sub(previous.Timestamp).Seconds(); dt > 0 {
+			dx, dy, dz := latest.X-previous.X, latest.Y-previous.Y, latest.Z-previous.Z
+			track.SpeedMps = math.Round(math.Sqrt(dx*dx+dy*dy+dz*dz)/dt*10) / 10
+			track.CourseDeg = math.Round(bearingDegrees(previous.Lat, previous.Lon, latest.Lat, latest.Lon)*10) / 10
+		}
+	}
+	return track
+}
+
+// createDatalinkFeed creates the gateway entity and the feed carrying the datalink traffic
+func (s *DroneSwarmSimulation) createDatalinkFeed(ctx context.Context) error {
+	orgID, err := uuid.Parse(s.config.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("invalid organization ID: %w", err)
+	}
+
+	name := datalinkName
+	if s.config.UseUniqueNames {
+		name = fmt.Sprintf("%s-%d", datalinkName, time.Now().Unix())
+	}
+	name = s.named(name)
+	category := models.CategoryDEVICE
+	entityType := EntityTypeDatalinkGateway
+	status := "ACTIVE"
+	entityReq := &models.CreateEntityRequest{
+		OrganizationID: &orgID,
+		Name:           &name,
+		Category:       &category,
+		Type:           &entityType,
+		Status:         &status,
+		Affiliation:    models.AffiliationFRIEND,
+	}
+
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	entity, err := s.createOrAdoptEntity(orgCtx, entityReq)
+	if err != nil {
+		return fmt.Errorf("failed to create datalink gateway entity: %w", err)
+	}
+	s.datalink.entityID = entity.ID
+
+	if feedID, ok := s.findEntityFeed(ctx, entity.ID, datalinkFeedBase); ok {
+		s.datalink.feedID = feedID
+		logger.Infof("📡 Reusing datalink feed (Feed ID: %s)", feedID.String())
+		return nil
+	}
+
+	feedName := s.named(fmt.Sprintf("%s%s", datalinkFeedBase, entity.ID.String()[:8]))
+	feedCategory := models.MessageCategoryMESSAGE
+	dataType := "application/json"
+	isActive := true
+	feedReq := &models.CreateFeedDefinitionRequest{
+		Category:    &feedCategory,
+		FeedName:    &feedName,
+		EntityID:    entity.ID,
+		DataType:    &dataType,
+		Description: fmt.Sprintf("Simulated tactical datalink traffic (%s): track reports, weapon pairing and engagement status", DatalinkSchema),
+		IsActive:    &isActive,
+	}
+
+	feed, err := s.legionClient.CreateFeedDefinition(s.idempotent(orgCtx, "feed", feedName), feedReq)
+	if err != nil {
+		return fmt.Errorf("failed to create datalink feed: %w", err)
+	}
+	s.datalink.feedID = feed.ID
+
+	logger.Infof("📡 Created datalink feed, track reports every %s (Feed ID: %s)", s.config.DatalinkInterval, feed.ID.String())
+	return nil
+}
+
+// publishDatalink sends everything queued since the last batch, adding a round of track
+// reports once per configured interval. final flushes what is left when the run ends.
+func (s *DroneSwarmSimulation) publishDatalink(ctx context.Context, final bool) {
+	if !s.datalinkActive() {
+		return
+	}
+	// Messages stay pending while Legion is failing and go out in the first batch after
+	if !final && s.legionDegraded() {
+		return
+	}
+
+	now := time.Now()
+	if final || now.Sub(s.datalink.lastTracks) >= s.config.DatalinkInterval {
+		s.datalink.lastTracks = now
+		s.queueTrackReports(now)
+	}
+
+	s.datalink.mu.Lock()
+	messages := s.datalink.pending
+	s.datalink.pending = nil
+	if len(messages) > 0 {
+		s.datalink.batches++
+	}
+	batch := DatalinkBatch{
+		Schema:   DatalinkSchema,
+		RunID:    s.runID,
+		Batch:    s.datalink.batches,
+		Sent:     now.UTC().Format(time.RFC3339Nano),
+		Messages: messages,
+	}
+	s.datalink.mu.Unlock()
+	if len(messages) == 0 {
+		return
+	}
+
+	payload, err := json.Marshal(batch)
+	if err != nil {
+		logger.Debugf("Failed to marshal datalink batch: %v", err)
+		return
+	}
+
+	payloadRaw := json.RawMessage(payload)
+	ingestReq := &models.IngestFeedDataRequest{
+		EntityID:         &s.datalink.entityID,
+		FeedDefinitionID: &s.datalink.feedID,
+		RecordedAt:       &now,
+		Payload:          &payloadRaw,
+	}
+
+	// The run context may already be cancelled when the last batch goes out
+	ingestCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), datalinkFeedLimit)
+	defer cancel()
+	ingestCtx = s.idempotent(client.WithOrgID(ingestCtx, s.config.OrganizationID), "ingest", s.datalink.feedID.String(), fmt.Sprint(batch.Batch))
+	if err := s.legionClient.IngestFeedData(ingestCtx, ingestReq); err != nil {
+		logger.Warnf("Failed to publish datalink batch: %v", err)
+		return
+	}
+
+	s.datalink.sent += len(messages)
+	if s.simLogger != nil {
+		s.simLogger.UpdateMetric(reporting.MetricDatalinkMessages, float64(s.datalink.sent), "count")
+	}
+	logger.Debugf("📡 Datalink batch %d: %d messages", batch.Batch, len(messages))
+}
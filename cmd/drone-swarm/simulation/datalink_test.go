@@ -0,0 +1,70 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestDatalinkMessages(t *testing.T) {
+	s := &DroneSwarmSimulation{
+		config:            SimulationConfig{DatalinkInterval: 5 * time.Second, UpdateInterval: time.Second, TrackHistoryDepth: 10},
+		uasThreats:        make(map[uuid.UUID]*UASThreat),
+		counterUASSystems: make(map[uuid.UUID]*CounterUASSystem),
+	}
+	s.datalink.feedID = uuid.New()
+
+	add := func(name, classification string) *UASThreat {
+		threat := &UASThreat{ID: uuid.New(), TrackNumber: name, Classification: classification}
+		s.uasThreats[threat.ID] = threat
+		return threat
+	}
+	// Flying north at 20 m/s
+	hostile := add("TK-0001", TrackStatusHostile)
+	hostile.ObservedHistory = NewTrackHistory(10)
+	start := time.Now()
+	for i, lat := range []float64{40, 40 + 200/111195.0} {
+		x, y, z := latLonAltToECEF(lat, -76, 120)
+		hostile.ObservedHistory.Record(&models.GeomPoint{Coordinates: []float64{x, y, z}}, start.Add(time.Duration(i)*10*time.Second))
+	}
+	add("TK-0002", TrackStatusPending)
+	system := &CounterUASSystem{ID: uuid.New(), EngagementType: EngagementTypeKinetic}
+
+	s.queueTrackReports(time.Now())
+	s.datalinkPairing(system, hostile)
+	s.datalinkPairing(system, hostile) // Still paired, nothing new
+	s.datalinkEngagement(system, hostile, &EngagementResult{Success: true, Distance: 1.5, EngageType: EngagementTypeKinetic})
+	hostile.Classification = TrackStatusDestroyed
+	s.queueTrackReports(time.Now())
+	s.queueTrackReports(time.Now()) // Dropped once only
+
+	labels := make([]string, 0, len(s.datalink.pending))
+	for _, msg := range s.datalink.pending {
+		labels = append(labels, msg.Label)
+	}
+	want := []string{DatalinkAirTrack, DatalinkWeaponsCoordination, DatalinkEngagementStatus, DatalinkTrackManagement}
+	if len(labels) != len(want) {
+		t.Fatalf("expected %v, got %v", want, labels)
+	}
+	for i := range want {
+		if labels[i] != want[i] || s.datalink.pending[i].Sequence != i+1 {
+			t.Fatalf("expected %v in sequence, got %v", want, labels)
+		}
+	}
+
+	track := s.datalink.pending[0].Track
+	if track.TrackNumber != "01000" || track.Identity != "HOSTILE" || track.SpeedMps != 20 || track.CourseDeg != 0 {
+		t.Errorf("unexpected track report %+v", track)
+	}
+	if pairing := s.datalink.pending[1].Pairing; pairing.TrackNumber != "01000" || pairing.WeaponJU != "00020" || pairing.Weapon != "KINETIC" {
+		t.Errorf("unexpected pairing %+v", pairing)
+	}
+	if engagement := s.datalink.pending[2].Engagement; engagement.Status != DatalinkStatusDestroyed {
+		t.Errorf("unexpected engagement %+v", engagement)
+	}
+	if drop := s.datalink.pending[3].Drop; drop.TrackNumber != "01000" || drop.Reason != TrackStatusDestroyed {
+		t.Errorf("unexpected drop %+v", drop)
+	}
+}
@@ -0,0 +1,140 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/coverage"
+	"github.com/picogrid/legion-simulations/pkg/datapack"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// dataPackTimeout bounds fetching a data pack during configuration
+const dataPackTimeout = 2 * time.Minute
+
+// dataPackFile is the drone swarm's schema for a data pack. Every section is optional;
+// anything left out keeps the built-in model.
+type dataPackFile struct {
+	Payloads map[string]struct {
+		Weight      float64 `yaml:"weight"`
+		Lethality   float64 `yaml:"lethality"`
+		JamTicks    int     `yaml:"jam_ticks"`
+		BlastRadius float64 `yaml:"blast_radius_m"`
+	} `yaml:"payloads"` // Threat capability catalog
+	Pk      map[string][2]float64 `yaml:"pk"` // Engagement type -> min, max kill probability
+	Terrain *struct {
+		South      float64     `yaml:"south"`
+		West       float64     `yaml:"west"`
+		CellDeg    float64     `yaml:"cell_deg"`
+		Elevations [][]float64 `yaml:"elevations"` // Meters, rows from south to north
+	} `yaml:"terrain"`
+}
+
+// dataPackModel is a resolved data pack ready for the run to use
+type dataPackModel struct {
+	ref      datapack.Ref // Carries the pack's actual checksum
+	payloads []payloadEntry
+	pk       map[string][2]float64
+	terrain  *coverage.GridTerrain
+}
+
+// loadDataPack fetches or reads the cached pack and checks its contents
+func loadDataPack(spec string) (*dataPackModel, error) {
+	ref, err := datapack.ParseRef(spec)
+	if err != nil {
+		return nil, err
+	}
+	store, err := datapack.DefaultStore()
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), dataPackTimeout)
+	defer cancel()
+	pack, err := store.Resolve(ctx, ref)
+	if err != nil {
+		return nil, err
+	}
+
+	var file dataPackFile
+	if err := pack.Decode(&file); err != nil {
+		return nil, err
+	}
+
+	model := &dataPackModel{ref: pack.Ref, pk: make(map[string][2]float64)}
+
+	// Sorted so weighted selection is the same on every run of the same pack
+	types := make([]string, 0, len(file.Payloads))
+	for payload := range file.Payloads {
+		types = append(types, payload)
+	}
+	sort.Strings(types)
+	for _, payload := range types {
+		p := file.Payloads[payload]
+		if p.Weight < 0 || p.Lethality < 0 || p.JamTicks < 0 || p.BlastRadius < 0 {
+			return nil, fmt.Errorf("data pack %s: payload %s cannot have negative values", ref.Name, payload)
+		}
+		model.payloads = append(model.payloads, payloadEntry{payload, PayloadProfile{Weight: p.Weight, Lethality: p.Lethality, JamTicks: p.JamTicks, BlastRadius: p.BlastRadius}})
+	}
+	if len(model.payloads) > 0 {
+		total := 0.0
+		for _, entry := range model.payloads {
+			total += entry.Profile.Weight
+		}
+		if total <= 0 {
+			return nil, fmt.Errorf("data pack %s: payload weights must not all be zero", ref.Name)
+		}
+	}
+
+	for engagementType, pk := range file.Pk {
+		if engagementType != EngagementTypeKinetic && engagementType != EngagementTypeEW {
+			return nil, fmt.Errorf("data pack %s: unknown engagement type %q in pk (valid: %s, %s)",
+				ref.Name, engagementType, EngagementTypeKinetic, EngagementTypeEW)
+		}
+		if pk[0] < 0 || pk[1] > 1 || pk[0] > pk[1] {
+			return nil, fmt.Errorf("data pack %s: pk for %s must be [min, max] between 0 and 1", ref.Name, engagementType)
+		}
+		model.pk[engagementType] = pk
+	}
+
+	if t := file.Terrain; t != nil {
+		if len(t.Elevations) < 2 || t.CellDeg <= 0 {
+			return nil, fmt.Errorf("data pack %s: terrain needs cell_deg and at least 2 rows of elevations", ref.Name)
+		}
+		for _, row := range t.Elevations {
+			if len(row) != len(t.Elevations[0]) || len(row) < 2 {
+				return nil, fmt.Errorf("data pack %s: terrain rows must all have the same length of at least 2", ref.Name)
+			}
+		}
+		model.terrain = &coverage.GridTerrain{South: t.South, West: t.West, CellDeg: t.CellDeg, Elevations: t.Elevations}
+	}
+
+	if ref.SHA256 == "" {
+		logger.Warnf("Data pack %s is not pinned; pin it as %s to make runs reproducible", spec, model.ref)
+	}
+	logger.Infof("📦 Data pack %s@%s (sha256:%s): %d payload types, %d Pk tables, terrain %t",
+		model.ref.Name, model.ref.Version, model.ref.SHA256, len(model.payloads), len(model.pk), model.terrain != nil)
+	return model, nil
+}
+
+// applyPkTable draws a system's kill probability from the data pack's table for its type
+func (s *DroneSwarmSimulation) applyPkTable(system *CounterUASSystem) {
+	if s.dataPack == nil {
+		return
+	}
+	if pk, ok := s.dataPack.pk[system.EngagementType]; ok {
+		system.SuccessRate = pk[0] + system.random().Float64()*(pk[1]-pk[0])
+	}
+}
+
+// coverageTerrain returns the data pack's terrain, or level ground at the base's altitude
+func (s *DroneSwarmSimulation) coverageTerrain() coverage.Terrain {
+	if s.dataPack != nil && s.dataPack.terrain != nil {
+		terrain := *s.dataPack.terrain
+		terrain.Outside = s.config.BaseLocation.Alt
+		return terrain
+	}
+	return coverage.FlatTerrain(s.config.BaseLocation.Alt)
+}
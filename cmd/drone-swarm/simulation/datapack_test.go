@@ -0,0 +1,56 @@
+package simulation
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadDataPack(t *testing.T) {
+	source := t.TempDir()
+	dir := filepath.Join(source, "cuas", "1.0.0")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	pack := `payloads:
+  fpv_warhead: {weight: 1, lethality: 1}
+  loitering_munition: {weight: 3, lethality: 0.8}
+pk:
+  kinetic: [0.4, 0.4]
+terrain:
+  south: 40
+  west: -77
+  cell_deg: 0.5
+  elevations: [[100, 200], [300, 400]]
+`
+	if err := os.WriteFile(filepath.Join(dir, "pack.yaml"), []byte(pack), 0644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("LEGION_DATA_PACK_SOURCE", source)
+	t.Setenv("LEGION_DATA_PACK_CACHE", t.TempDir())
+
+	model, err := loadDataPack("cuas@1.0.0")
+	if err != nil {
+		t.Fatalf("loadDataPack: %v", err)
+	}
+	if len(model.ref.SHA256) != 64 {
+		t.Fatalf("expected the resolved checksum, got %q", model.ref.SHA256)
+	}
+
+	s := &DroneSwarmSimulation{dataPack: model}
+	if got := s.payloadProfile("loitering_munition").Lethality; got != 0.8 {
+		t.Errorf("loitering_munition lethality = %g, want 0.8", got)
+	}
+	system := &CounterUASSystem{EngagementType: EngagementTypeKinetic, SuccessRate: 0.8}
+	s.applyPkTable(system)
+	if system.SuccessRate != 0.4 {
+		t.Errorf("kinetic Pk = %g, want 0.4 from the pack", system.SuccessRate)
+	}
+	if got := s.coverageTerrain().ElevationM(40.25, -76.75); got != 250 {
+		t.Errorf("terrain at the grid center = %g, want 250", got)
+	}
+
+	if _, err := loadDataPack("cuas@1.0.0#sha256:" + "0000000000000000000000000000000000000000000000000000000000000000"); err == nil {
+		t.Error("expected a checksum mismatch")
+	}
+}
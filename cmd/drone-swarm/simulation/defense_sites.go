@@ -0,0 +1,99 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// counterUASEntityPrefix starts every Counter-UAS system's entity name
+const counterUASEntityPrefix = "Counter-UAS-"
+
+// DefenseSite posts one Counter-UAS system at a fixed point instead of on the ring
+type DefenseSite struct {
+	Name           string
+	EngagementType string  // kinetic or electronic_warfare
+	DistanceM      float64 // From the defended base
+	BearingDeg     float64 // True bearing from the base
+}
+
+// parseDefenseSites parses "name:kind:distance_m:bearing_deg" entries separated by
+// semicolons, where kind is kinetic or ew, e.g. "North Gun:kinetic:1200:0;Jammer:ew:400:180"
+func parseDefenseSites(spec string) ([]DefenseSite, error) {
+	var sites []DefenseSite
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ":")
+		if len(fields) != 4 {
+			return nil, fmt.Errorf("defense site %q: expected name:kind:distance_m:bearing_deg", entry)
+		}
+
+		site := DefenseSite{Name: strings.TrimSpace(fields[0])}
+		if site.Name == "" {
+			return nil, fmt.Errorf("defense site %q: name is required", entry)
+		}
+		if seen[site.Name] {
+			return nil, fmt.Errorf("defense site %s is given twice", site.Name)
+		}
+		seen[site.Name] = true
+
+		switch strings.ToLower(strings.TrimSpace(fields[1])) {
+		case "kinetic":
+			site.EngagementType = EngagementTypeKinetic
+		case "ew", EngagementTypeEW:
+			site.EngagementType = EngagementTypeEW
+		default:
+			return nil, fmt.Errorf("defense site %s: kind must be kinetic or ew", site.Name)
+		}
+
+		distance, err := strconv.ParseFloat(strings.TrimSpace(fields[2]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("defense site %s: invalid number %q", site.Name, fields[2])
+		}
+		bearing, err := strconv.ParseFloat(strings.TrimSpace(fields[3]), 64)
+		if err != nil {
+			return nil, fmt.Errorf("defense site %s: invalid number %q", site.Name, fields[3])
+		}
+		if distance < 0 {
+			return nil, fmt.Errorf("defense site %s: distance cannot be negative", site.Name)
+		}
+		site.DistanceM, site.BearingDeg = distance, math.Mod(bearing+360, 360)
+		sites = append(sites, site)
+	}
+	return sites, nil
+}
+
+// plannedSystem is a Counter-UAS system the run will deploy
+type plannedSystem struct {
+	Name           string       // Entity name
+	EngagementType string       // kinetic or electronic_warfare
+	Site           *DefenseSite // Fixed post; nil places the system on the ring
+}
+
+// systemPlan lists the Counter-UAS systems the run deploys: one per defense site, or
+// alternating kinetic and EW on the ring without them
+func (c *SimulationConfig) systemPlan() []plannedSystem {
+	if len(c.DefenseSites) > 0 {
+		plan := make([]plannedSystem, len(c.DefenseSites))
+		for i := range c.DefenseSites {
+			site := &c.DefenseSites[i]
+			plan[i] = plannedSystem{Name: counterUASEntityPrefix + site.Name, EngagementType: site.EngagementType, Site: site}
+		}
+		return plan
+	}
+
+	plan := make([]plannedSystem, c.NumCounterUASSystems)
+	for i := range plan {
+		plan[i] = plannedSystem{Name: fmt.Sprintf("%s%02d", counterUASEntityPrefix, i+1), EngagementType: EngagementTypeKinetic}
+		if i%2 == 1 {
+			plan[i].EngagementType = EngagementTypeEW
+		}
+	}
+	return plan
+}
@@ -0,0 +1,52 @@
+package simulation
+
+import (
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// degradedState follows the Legion client's circuit breaker. While it is open the run
+// keeps simulating but stops calling Legion: entity updates wait in the update buffer,
+// coalesced to the latest per entity, and go out once Legion answers again.
+type degradedState struct {
+	active  bool
+	since   time.Time
+	outages int
+	total   time.Duration // Time spent degraded over the run
+}
+
+// legionDegraded reports whether Legion calls are being held back. It stays true while
+// the breaker is half-open, until the update buffer's next flush shows Legion is back.
+func (s *DroneSwarmSimulation) legionDegraded() bool {
+	if s.legionClient == nil || s.legionClient.CircuitBreaker() == nil {
+		return false
+	}
+	return s.legionClient.CircuitBreaker().State() != client.CircuitClosed
+}
+
+// checkDegraded logs the run losing or regaining Legion, once per change
+func (s *DroneSwarmSimulation) checkDegraded() {
+	degraded := s.legionDegraded()
+	if degraded == s.degraded.active {
+		return
+	}
+	s.degraded.active = degraded
+	pending := s.updateBuffer.GetPendingCount()
+
+	if degraded {
+		s.degraded.since = time.Now()
+		s.degraded.outages++
+		logger.Warnf("%s Legion is failing; buffering updates locally and retrying every %s",
+			logger.IconNetwork, s.config.BreakerCooldown)
+		s.simLogger.LogLegionDegraded(true, pending, 0)
+		return
+	}
+
+	lasted := time.Since(s.degraded.since)
+	s.degraded.total += lasted
+	logger.Infof("%s Legion recovered after %s; sending %d buffered updates",
+		logger.IconNetwork, lasted.Round(time.Second), pending)
+	s.simLogger.LogLegionDegraded(false, pending, lasted)
+}
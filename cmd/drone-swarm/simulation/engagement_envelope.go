@@ -0,0 +1,134 @@
+package simulation
+
+import (
+	"math"
+)
+
+// Engagement envelope tables are binned by target speed and height above the effector.
+// Lookups round both up so a table never credits more reach than the model gives.
+const (
+	envelopeSpeedStepKph = 10.0
+	envelopeMaxSpeedKph  = 400.0 // Faster tracks use the last bin
+	envelopeHeightStepM  = 50.0
+)
+
+// effectorTiming describes how long an engagement takes to complete and how steeply
+// the effector can point
+type effectorTiming struct {
+	ReactionS       float64 // Slew, track and fire (kinetic) or dwell to break the links (EW)
+	FlyoutMps       float64 // Interceptor average speed; 0 for effects that arrive instantly
+	MaxElevationDeg float64 // Above this the target is in the cone of silence overhead
+}
+
+// effectorTimings by engagement type
+var effectorTimings = map[string]effectorTiming{
+	EngagementTypeKinetic: {ReactionS: 3, FlyoutMps: 900, MaxElevationDeg: 80},
+	EngagementTypeEW:      {ReactionS: 2, MaxElevationDeg: 70},
+}
+
+// envelopeKey identifies a capability set; systems with the same one share a table
+type envelopeKey struct {
+	engagementType string
+	rangeM         int
+}
+
+// envelopeCell is the band of slant ranges, in km, at which an engagement can complete.
+// An empty band (MaxKm 0) means the target can't be engaged at that speed and height.
+type envelopeCell struct {
+	MinKm float64
+	MaxKm float64
+}
+
+// engagementEnvelope is a system's intercept envelope indexed by [speed bin][height bin]
+type engagementEnvelope struct {
+	cells [][]envelopeCell
+}
+
+// interceptReach computes the engageable slant-range band against a target at speedKph
+// and heightM above the effector. The target may open the range by the distance it
+// covers while the engagement completes, so the outer edge pulls in with speed.
+func interceptReach(engagementType string, effectiveRangeKm, speedKph, heightM float64) envelopeCell {
+	timing, ok := effectorTimings[engagementType]
+	if !ok {
+		return envelopeCell{MaxKm: effectiveRangeKm}
+	}
+
+	engageS := timing.ReactionS
+	if timing.FlyoutMps > 0 {
+		engageS += effectiveRangeKm * 1000 / timing.FlyoutMps
+	}
+	maxKm := effectiveRangeKm - speedKph/3.6*engageS/1000
+	heightKm := math.Abs(heightM) / 1000
+	if maxKm <= heightKm {
+		return envelopeCell{}
+	}
+
+	minKm := heightKm / math.Sin(timing.MaxElevationDeg*math.Pi/180)
+	if minKm >= maxKm {
+		return envelopeCell{}
+	}
+	return envelopeCell{MinKm: minKm, MaxKm: maxKm}
+}
+
+// newEngagementEnvelope precomputes the envelope for a capability set, evaluating each
+// bin at its upper speed and height edges
+func newEngagementEnvelope(engagementType string, effectiveRangeKm float64) *engagementEnvelope {
+	speedBins := int(math.Ceil(envelopeMaxSpeedKph/envelopeSpeedStepKph)) + 1
+	heightBins := int(math.Ceil(effectiveRangeKm*1000/envelopeHeightStepM)) + 1
+
+	env := &engagementEnvelope{cells: make([][]envelopeCell, speedBins)}
+	for i := range env.cells {
+		env.cells[i] = make([]envelopeCell, heightBins)
+		for j := range env.cells[i] {
+			env.cells[i][j] = interceptReach(engagementType, effectiveRangeKm,
+				float64(i)*envelopeSpeedStepKph, float64(j)*envelopeHeightStepM)
+		}
+	}
+	return env
+}
+
+// lookup returns the band for a target; heights beyond the table are out of reach
+func (e *engagementEnvelope) lookup(speedKph, heightM float64) envelopeCell {
+	speedBin := int(math.Ceil(math.Max(speedKph, 0) / envelopeSpeedStepKph))
+	if speedBin >= len(e.cells) {
+		speedBin = len(e.cells) - 1
+	}
+	heightBin := int(math.Ceil(math.Abs(heightM) / envelopeHeightStepM))
+	if heightBin >= len(e.cells[speedBin]) {
+		return envelopeCell{}
+	}
+	return e.cells[speedBin][heightBin]
+}
+
+// engagementEnvelope returns the table for the system's capability set, building it
+// the first time the set is seen. Called while creating the forces, before any ticks.
+func (s *DroneSwarmSimulation) engagementEnvelope(system *CounterUASSystem) *engagementEnvelope {
+	key := envelopeKey{engagementType: system.EngagementType, rangeM: int(math.Round(system.EffectiveRange * 1000))}
+	if s.envelopes == nil {
+		s.envelopes = make(map[envelopeKey]*engagementEnvelope)
+	}
+	if table, ok := s.envelopes[key]; ok {
+		return table
+	}
+	table := newEngagementEnvelope(system.EngagementType, float64(key.rangeM)/1000)
+	s.envelopes[key] = table
+	return table
+}
+
+// withinEnvelope reports the slant range to a target and whether the system can
+// complete an engagement against it at its observed speed and height. Height follows
+// the scenario's convention of altitude offsets along Z.
+func withinEnvelope(system *CounterUASSystem, target *UASThreat) (float64, bool) {
+	env := system.envelope
+	if env == nil {
+		env = newEngagementEnvelope(system.EngagementType, system.EffectiveRange)
+	}
+
+	dx := target.Position.Coordinates[0] - system.Position.Coordinates[0]
+	dy := target.Position.Coordinates[1] - system.Position.Coordinates[1]
+	dz := target.Position.Coordinates[2] - system.Position.Coordinates[2]
+	distanceKm := math.Sqrt(dx*dx+dy*dy+dz*dz) / 1000
+
+	cell := env.lookup(target.EstimatedSpeed, dz)
+	return distanceKm, cell.MaxKm > 0 && distanceKm >= cell.MinKm && distanceKm <= cell.MaxKm
+}
@@ -0,0 +1,74 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestEngagementEnvelope(t *testing.T) {
+	env := newEngagementEnvelope(EngagementTypeKinetic, 4.0)
+
+	slow := env.lookup(0, 100)
+	fast := env.lookup(180, 100)
+	if slow.MaxKm <= fast.MaxKm {
+		t.Errorf("expected reach to shrink with speed, got %.3fkm at rest and %.3fkm at 180 kph", slow.MaxKm, fast.MaxKm)
+	}
+
+	// Binned lookups never credit more reach than the model at the exact speed and height
+	for _, tc := range []struct{ speed, height float64 }{{37, 120}, {95, 480}, {151, 1010}, {399, 20}} {
+		exact := interceptReach(EngagementTypeKinetic, 4.0, tc.speed, tc.height)
+		binned := env.lookup(tc.speed, tc.height)
+		if binned.MaxKm > exact.MaxKm || binned.MinKm < exact.MinKm {
+			t.Errorf("%.0f kph at %.0fm: binned %+v is wider than exact %+v", tc.speed, tc.height, binned, exact)
+		}
+	}
+
+	if cell := env.lookup(50, 4500); cell.MaxKm != 0 {
+		t.Errorf("expected a target above the effective range to be out of reach, got %+v", cell)
+	}
+
+	// A target nearly straight overhead sits in the cone of silence
+	system := &CounterUASSystem{
+		EngagementType: EngagementTypeKinetic,
+		EffectiveRange: 4.0,
+		Position:       &models.GeomPoint{Coordinates: []float64{0, 0, 0}},
+		envelope:       env,
+	}
+	overhead := &UASThreat{Position: &models.GeomPoint{Coordinates: []float64{50, 0, 1000}}}
+	if _, ok := withinEnvelope(system, overhead); ok {
+		t.Error("expected a target overhead to be outside the envelope")
+	}
+	offset := &UASThreat{Position: &models.GeomPoint{Coordinates: []float64{2000, 0, 1000}}}
+	if distance, ok := withinEnvelope(system, offset); !ok {
+		t.Errorf("expected a target at %.2fkm to be engageable", distance)
+	}
+}
+
+func TestEngagementEnvelopeSharedByCapabilitySet(t *testing.T) {
+	s := &DroneSwarmSimulation{}
+	a := s.engagementEnvelope(&CounterUASSystem{EngagementType: EngagementTypeEW, EffectiveRange: 2.5})
+	b := s.engagementEnvelope(&CounterUASSystem{EngagementType: EngagementTypeEW, EffectiveRange: 2.5})
+	c := s.engagementEnvelope(&CounterUASSystem{EngagementType: EngagementTypeKinetic, EffectiveRange: 2.5})
+	if a != b {
+		t.Error("expected systems with the same capabilities to share an envelope")
+	}
+	if a == c {
+		t.Error("expected different engagement types to have separate envelopes")
+	}
+}
+
+func BenchmarkEngagementFeasibility(b *testing.B) {
+	env := newEngagementEnvelope(EngagementTypeKinetic, 4.0)
+
+	b.Run("lookup", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			env.lookup(float64(i%300), float64(i%2000))
+		}
+	})
+	b.Run("direct", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			interceptReach(EngagementTypeKinetic, 4.0, float64(i%300), float64(i%2000))
+		}
+	})
+}
@@ -8,12 +8,14 @@ import (
 	"time"
 
 	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/core"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/metadata"
 	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
 )
 
-func init() {
-	// Note: As of Go 1.20, rand is auto-seeded
-}
+// unseeded is the random source of entities built outside a run, as in tests
+var unseeded, _ = simulation.NewRand(0)
 
 // Entity types - Blue Force (friendly) vs Red Force (enemy)
 const (
@@ -23,6 +25,7 @@ const (
 
 // Blue Force Status - Complete visibility of our systems
 const (
+	CounterUASStatusBIT       = "BIT"       // Built-in test and calibration
 	CounterUASStatusIdle      = "IDLE"      // System ready, no targets
 	CounterUASStatusSearching = "SEARCHING" // Active sensor sweep
 	CounterUASStatusTracking  = "TRACKING"  // Tracking detected target
@@ -65,6 +68,7 @@ const (
 	BehaviorAggressive   = "AGGRESSIVE"   // Direct approach, high speed
 	BehaviorEvasive      = "EVASIVE"      // Erratic movement when targeted
 	BehaviorFormation    = "FORMATION"    // Moving in coordinated group
+	BehaviorForming      = "FORMING"      // Orbiting an assembly point while a raid forms
 	BehaviorUnknown      = "UNKNOWN"      // No clear pattern
 )
 
@@ -77,7 +81,8 @@ type CounterUASSystem struct {
 	Status      string
 	Affiliation models.Affiliation // Always FRIENDLY for our systems
 	Position    *models.GeomPoint
-	Heading     float64 // Degrees
+	Heading     float64      // Degrees
+	Site        *DefenseSite // Fixed post; nil places the system on the ring
 
 	// Sensor Capabilities
 	RadarRange        float64 // Detection range for radar
@@ -107,9 +112,17 @@ type CounterUASSystem struct {
 
 	// C2 Integration
 	DataLinkStatus string // ONLINE, DEGRADED, OFFLINE
+	JammedTicks    int    // Remaining ticks of datalink jamming from a hostile EW payload
 	LastC2Update   time.Time
 	IFFCode        string // Identification Friend or Foe
 
+	// Precomputed intercept envelope, shared by systems with the same capabilities
+	envelope *engagementEnvelope
+
+	// The system's own random stream, so its draws don't depend on the order systems
+	// are visited in
+	rng *rand.Rand
+
 	LastUpdateTime time.Time
 	mu             sync.RWMutex
 }
@@ -142,6 +155,9 @@ type UASThreat struct {
 	IsPartOfSwarm    bool    // Multiple tracks moving together
 	SwarmID          *string // If part of detected swarm
 
+	// Intent Estimation (from observed kinematics)
+	Intent *IntentEstimate // Predicted target and time-to-impact, nil if unknown
+
 	// Sensor Detections
 	RFEmitting        bool     // Detected RF emissions
 	RFFrequency       *float64 // If detected, MHz
@@ -158,6 +174,59 @@ type UASThreat struct {
 	ActualVelocity     *models.GeomPoint     // True velocity for physics
 	ActualCapabilities SimulatedCapabilities // Hidden true capabilities
 
+	// Rolling position history for trails and post-run analysis
+	History         *TrackHistory
+	ObservedHistory *TrackHistory // Published (sensor-measured) positions, used for trails
+	SpawnBearing    *float64      // Bearing from the base of the first recorded position, nil until recorded
+
+	// Affiliation currently shown in Legion, so changes are only published once
+	PublishedAffiliation models.Affiliation
+
+	// When C2 first received a detection report (zero until then)
+	ReportedAt time.Time
+
+	// Track lifecycle
+	TerminalSince time.Time // When the track became LOST or DESTROYED
+	Archived      bool      // Removed from Legion; retained locally for the AAR
+
+	// Kill chain milestones, zero until first reached
+	DetectedAt  time.Time // First sensor detection
+	HostileAt   time.Time // First classified HOSTILE
+	AssignedAt  time.Time // First selected as a system's target
+	FirstShotAt time.Time // First engagement
+
+	// Sharded runs
+	Remote     bool // Simulated by another shard; this process only holds a proxy
+	OwnerShard int  // Shard that owns the track
+
+	// Launch site and assembly (empty LaunchPhase when spawned already inbound)
+	LaunchPhase    string        // GROUNDED, FORMING, TRANSIT
+	LaunchSite     string        // Name of the site the threat flies from
+	LaunchOffset   time.Duration // Scenario time the threat leaves the ground
+	DepartOffset   time.Duration // Scenario time the raid leaves the assembly orbit
+	AssemblyLat    float64
+	AssemblyLon    float64
+	AssemblyRadius float64 // Meters
+	OrbitAngle     float64 // Bearing from the assembly point, degrees
+	GroundAltitude float64 // Site elevation, meters
+
+	// Reroute red planned after watching an earlier wave (Ingress false flies straight in)
+	Ingress       bool
+	IngressLat    float64
+	IngressLon    float64
+	IngressHeight float64 // Meters above the site
+
+	// Held at its start point until the scenario act that launches its wave begins
+	ActHeld bool
+
+	// Recorded flight followed instead of synthetic behavior (nil when simulated)
+	Replay *trackReplay
+
+	// Shared-world asset the threat is attacking instead of the base (empty for the base)
+	WorldTarget string
+
+	rng *rand.Rand // The threat's own random stream
+
 	LastUpdateTime time.Time
 	mu             sync.RWMutex
 }
@@ -167,15 +236,18 @@ type SimulatedCapabilities struct {
 	SpeedKph          float64
 	AutonomyLevel     float64 // 0.0-1.0 for simulation mechanics
 	EvasionCapability bool
-	PayloadType       string // For simulation narrative
-	WaveNumber        int    // Which attack wave
+	PayloadType       string        // Drives the consequence of a penetration
+	WaveNumber        int           // Which attack wave
+	Faction           string        // Red force the threat flies for
+	Nav               core.NavStack // Navigation and control links; drives EW susceptibility
 }
 
-// NewCounterUASSystem creates a new BLUE FORCE Counter-UAS system
-func NewCounterUASSystem(name string, position *models.GeomPoint, engagementType string) *CounterUASSystem {
+// NewCounterUASSystem creates a new BLUE FORCE Counter-UAS system, drawing its
+// capabilities from rng
+func NewCounterUASSystem(name string, position *models.GeomPoint, engagementType string, rng *rand.Rand) *CounterUASSystem {
 	// Generate military callsign
 	callsigns := []string{"HAWK", "EAGLE", "SENTRY", "GUARDIAN", "DEFENDER"}
-	callsign := fmt.Sprintf("%s-%02d", callsigns[rand.Intn(len(callsigns))], rand.Intn(99)+1)
+	callsign := fmt.Sprintf("%s-%02d", callsigns[rng.Intn(len(callsigns))], rng.Intn(99)+1)
 
 	// Assign capabilities based on engagement type
 	var successRate float64
@@ -184,15 +256,15 @@ func NewCounterUASSystem(name string, position *models.GeomPoint, engagementType
 	var effectiveRange float64
 
 	if engagementType == EngagementTypeKinetic {
-		successRate = 0.7 + rand.Float64()*0.2    // 0.7-0.9
-		ammoCapacity = 20 + rand.Intn(20)         // 20-40 rounds
-		reloadTime = 30 + rand.Intn(30)           // 30-60 seconds
-		effectiveRange = 3.0 + rand.Float64()*2.0 // 3-5 km
+		successRate = 0.7 + rng.Float64()*0.2    // 0.7-0.9
+		ammoCapacity = 20 + rng.Intn(20)         // 20-40 rounds
+		reloadTime = 30 + rng.Intn(30)           // 30-60 seconds
+		effectiveRange = 3.0 + rng.Float64()*2.0 // 3-5 km
 	} else {
-		successRate = 0.5 + rand.Float64()*0.2 // 0.5-0.7
-		ammoCapacity = -1                      // Unlimited for EW
-		reloadTime = 5                         // Quick reset
-		effectiveRange = 2.0 + rand.Float64()  // 2-3 km
+		successRate = 0.5 + rng.Float64()*0.2 // 0.5-0.7
+		ammoCapacity = -1                     // Unlimited for EW
+		reloadTime = 5                        // Quick reset
+		effectiveRange = 2.0 + rng.Float64()  // 2-3 km
 	}
 
 	return &CounterUASSystem{
@@ -202,7 +274,7 @@ func NewCounterUASSystem(name string, position *models.GeomPoint, engagementType
 		Status:      CounterUASStatusIdle,
 		Affiliation: models.AffiliationFRIEND, // Our systems are always FRIEND
 		Position:    position,
-		Heading:     rand.Float64() * 360,
+		Heading:     rng.Float64() * 360,
 
 		// Sensor suite
 		RadarRange:        12.0, // 12km radar detection
@@ -233,41 +305,43 @@ func NewCounterUASSystem(name string, position *models.GeomPoint, engagementType
 		// C2 Integration
 		DataLinkStatus: "ONLINE",
 		LastC2Update:   time.Now(),
-		IFFCode:        fmt.Sprintf("BLUE-%04d", rand.Intn(9999)),
+		IFFCode:        fmt.Sprintf("BLUE-%04d", rng.Intn(9999)),
 
+		rng:            simulation.DeriveRand(rng),
 		LastUpdateTime: time.Now(),
 	}
 }
 
-// NewUASThreat creates a new RED FORCE threat (with limited observable data)
-func NewUASThreat(trackNumber string, position *models.GeomPoint, waveNumber int) *UASThreat {
+// NewUASThreat creates a new RED FORCE threat (with limited observable data), drawing
+// its hidden characteristics from rng
+func NewUASThreat(trackNumber string, position *models.GeomPoint, waveNumber int, rng *rand.Rand) *UASThreat {
 	// Hidden true characteristics (for simulation)
-	trueSpeed := 100.0 + rand.Float64()*200.0 // 100-300 kph - faster drones for better visibility
-	autonomyLevel := rand.Float64()           // 0.0-1.0
-	evasionCapability := rand.Float64() > 0.3 // 70% have evasion
+	trueSpeed := 100.0 + rng.Float64()*200.0 // 100-300 kph - faster drones for better visibility
+	autonomyLevel := rng.Float64()           // 0.0-1.0
+	evasionCapability := rng.Float64() > 0.3 // 70% have evasion
 
 	// Determine size class based on random distribution
-	sizeRoll := rand.Float64()
+	sizeRoll := rng.Float64()
 	var sizeClass string
 	var radarCrossSection float64
 
 	switch {
 	case sizeRoll < 0.4:
 		sizeClass = UASSizeGroup1
-		radarCrossSection = 0.01 + rand.Float64()*0.04 // 0.01-0.05 m²
+		radarCrossSection = 0.01 + rng.Float64()*0.04 // 0.01-0.05 m²
 	case sizeRoll < 0.7:
 		sizeClass = UASSizeGroup2
-		radarCrossSection = 0.05 + rand.Float64()*0.15 // 0.05-0.2 m²
+		radarCrossSection = 0.05 + rng.Float64()*0.15 // 0.05-0.2 m²
 	case sizeRoll < 0.9:
 		sizeClass = UASSizeGroup3
-		radarCrossSection = 0.2 + rand.Float64()*0.3 // 0.2-0.5 m²
+		radarCrossSection = 0.2 + rng.Float64()*0.3 // 0.2-0.5 m²
 	default:
 		sizeClass = UASSizeGroup4
-		radarCrossSection = 0.5 + rand.Float64()*0.5 // 0.5-1.0 m²
+		radarCrossSection = 0.5 + rng.Float64()*0.5 // 0.5-1.0 m²
 	}
 
 	// Initial velocity (hidden from C2)
-	heading := rand.Float64() * 360.0
+	heading := rng.Float64() * 360.0
 	velocityMagnitude := trueSpeed / 3.6 // Convert to m/s
 	headingRad := heading * math.Pi / 180.0
 
@@ -283,9 +357,9 @@ func NewUASThreat(trackNumber string, position *models.GeomPoint, waveNumber int
 
 	// RF emissions (60% of drones emit RF)
 	var rfFreq *float64
-	rfEmitting := rand.Float64() < 0.6
+	rfEmitting := rng.Float64() < 0.6
 	if rfEmitting {
-		freq := 2400.0 + rand.Float64()*100.0 // 2.4-2.5 GHz typical
+		freq := 2400.0 + rng.Float64()*100.0 // 2.4-2.5 GHz typical
 		rfFreq = &freq
 	}
 
@@ -332,113 +406,91 @@ func NewUASThreat(trackNumber string, position *models.GeomPoint, waveNumber int
 			SpeedKph:          trueSpeed,
 			AutonomyLevel:     autonomyLevel,
 			EvasionCapability: evasionCapability,
-			PayloadType:       "surveillance", // Could expand this
+			PayloadType:       randomPayloadType(rng),
 			WaveNumber:        waveNumber,
+			Nav:               core.NavStackForAutonomy(autonomyLevel),
 		},
 
+		rng:            simulation.DeriveRand(rng),
 		LastUpdateTime: time.Now(),
 	}
 }
 
-// GetMetadata returns the metadata map for a BLUE FORCE Counter-UAS system
-func (c *CounterUASSystem) GetMetadata() map[string]interface{} {
-	c.mu.RLock()
-	defer c.mu.RUnlock()
-
-	metadata := map[string]interface{}{
-		// Identity
-		"callsign":    c.Callsign,
-		"iff_code":    c.IFFCode,
-		"affiliation": string(c.Affiliation),
+// random returns the run's random source
+func (s *DroneSwarmSimulation) random() *rand.Rand {
+	if s.rng == nil {
+		return unseeded
+	}
+	return s.rng
+}
 
-		// Sensors
-		"radar_range_km":        c.RadarRange,
-		"eoir_range_km":         c.EOIRRange,
-		"rf_detection_range_km": c.RFDetectionRange,
-		"sensor_mode":           c.CurrentSensorMode,
+// random returns the system's random stream
+func (c *CounterUASSystem) random() *rand.Rand {
+	if c.rng == nil {
+		return unseeded
+	}
+	return c.rng
+}
 
-		// Weapons
-		"engagement_type":    c.EngagementType,
-		"effective_range_km": c.EffectiveRange,
-		"success_rate":       c.SuccessRate,
-		"cooldown_remaining": c.CooldownRemaining,
-
-		// System Status
-		"system_health":     c.SystemHealth,
-		"power_level":       c.PowerLevel,
-		"temperature":       c.Temperature,
-		"engagement_stress": c.EngagementStress,
-		"datalink_status":   c.DataLinkStatus,
-
-		// Combat Stats
-		"total_engagements":      c.TotalEngagements,
-		"successful_engagements": c.SuccessfulEngagements,
-		"tracking_targets":       len(c.CurrentTargets),
-
-		// UI Proximity Circles (for rendering detection/engagement ranges)
-		"detection_radius_km":  c.RadarRange,                                                      // Primary detection range for UI
-		"engagement_radius_km": c.EffectiveRange,                                                  // Engagement range for UI
-		"max_sensor_range_km":  math.Max(math.Max(c.RadarRange, c.EOIRRange), c.RFDetectionRange), // Maximum sensor range
+// random returns the threat's random stream
+func (u *UASThreat) random() *rand.Rand {
+	if u.rng == nil {
+		return unseeded
 	}
+	return u.rng
+}
+
+// GetMetadata returns the metadata for a BLUE FORCE Counter-UAS system
+func (c *CounterUASSystem) GetMetadata() metadata.CounterUAS {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	b := metadata.NewCounterUAS(c.Callsign, c.IFFCode, string(c.Affiliation)).
+		Sensors(c.RadarRange, c.EOIRRange, c.RFDetectionRange, c.CurrentSensorMode).
+		Weapon(c.EngagementType, c.EffectiveRange, c.SuccessRate, c.CooldownRemaining).
+		Health(c.SystemHealth, c.PowerLevel, c.Temperature, c.EngagementStress, c.DataLinkStatus).
+		Combat(c.TotalEngagements, c.SuccessfulEngagements, len(c.CurrentTargets))
 
 	if c.EngagementType == EngagementTypeKinetic {
-		metadata["ammo_capacity"] = c.AmmoCapacity
-		metadata["ammo_remaining"] = c.AmmoRemaining
-		metadata["reload_time_sec"] = c.ReloadTimeSeconds
+		b.Ammo(c.AmmoCapacity, c.AmmoRemaining, c.ReloadTimeSeconds)
 	}
 
 	if c.EngagedTarget != nil {
-		metadata["engaged_target"] = c.EngagedTarget.String()
+		b.Engaging(c.EngagedTarget.String())
 	}
 
-	return metadata
+	return b.Build()
 }
 
 // GetMetadata returns observable metadata for a RED FORCE threat
-func (u *UASThreat) GetMetadata() map[string]interface{} {
+func (u *UASThreat) GetMetadata() metadata.UASThreat {
 	u.mu.RLock()
 	defer u.mu.RUnlock()
 
-	metadata := map[string]interface{}{
-		// Track Info
-		"track_number":   u.TrackNumber,
-		"classification": u.Classification,
-		"affiliation":    string(u.Affiliation),
-		"track_quality":  u.TrackQuality,
-		"last_seen":      u.LastSeenTime.Format(time.RFC3339),
-
-		// Observable Characteristics
-		"size_class":          u.SizeClass,
-		"radar_cross_section": u.RadarCrossSection,
-		"observed_behavior":   u.ObservedBehavior,
-		"threat_level":        u.ThreatLevel,
-
-		// Estimated Kinematics
-		"estimated_speed_kph": u.EstimatedSpeed,
-		"estimated_heading":   u.EstimatedHeading,
-		"estimated_altitude":  u.EstimatedAltitude,
-
-		// Sensor Detections
-		"rf_emitting":        u.RFEmitting,
-		"thermal_signature":  u.ThermalSignature,
-		"acoustic_signature": u.AcousticSignature,
-
-		// Engagement History
-		"times_targeted":       u.TimesTargeted,
-		"jamming_attempts":     u.JammingAttempts,
-		"kinetic_attempts":     u.KineticAttempts,
-		"shows_jam_resistance": u.ShowsJamResistance,
-	}
+	b := metadata.NewUASThreat(u.TrackNumber, u.Classification, string(u.Affiliation)).
+		Track(u.TrackQuality, u.LastSeenTime).
+		Characteristics(u.SizeClass, u.RadarCrossSection, u.ObservedBehavior, u.ThreatLevel).
+		Kinematics(u.EstimatedSpeed, u.EstimatedHeading, u.EstimatedAltitude).
+		Signatures(u.RFEmitting, u.ThermalSignature, u.AcousticSignature).
+		Engagements(u.TimesTargeted, u.JammingAttempts, u.KineticAttempts, u.ShowsJamResistance)
 
 	if u.RFFrequency != nil {
-		metadata["rf_frequency_mhz"] = *u.RFFrequency
+		b.RFFrequency(*u.RFFrequency)
 	}
 
 	if u.IsPartOfSwarm && u.SwarmID != nil {
-		metadata["swarm_id"] = *u.SwarmID
+		b.Swarm(*u.SwarmID)
+	}
+
+	if u.Intent != nil {
+		likely := make([]metadata.LikelyTarget, 0, len(u.Intent.LikelyTargets))
+		for _, outcome := range u.Intent.LikelyTargets {
+			likely = append(likely, metadata.LikelyTarget(outcome))
+		}
+		b.Intent(u.Intent.PredictedTargetName, u.Intent.TimeToImpact, u.Intent.Confidence, likely)
 	}
 
-	return metadata
+	return b.Build()
 }
 
 // UpdateStatus safely updates the status of a BLUE FORCE system
@@ -457,6 +509,16 @@ func (u *UASThreat) UpdateClassification(newClass string) {
 	u.Classification = newClass
 	u.LastUpdateTime = time.Now()
 
+	if (newClass == TrackStatusDestroyed || newClass == TrackStatusLost) && u.TerminalSince.IsZero() {
+		u.TerminalSince = u.LastUpdateTime
+	}
+	if (newClass == TrackStatusUnknown || newClass == TrackStatusSuspected || newClass == TrackStatusHostile) && u.DetectedAt.IsZero() {
+		u.DetectedAt = u.LastUpdateTime
+	}
+	if newClass == TrackStatusHostile && u.HostileAt.IsZero() {
+		u.HostileAt = u.LastUpdateTime
+	}
+
 	// Update affiliation based on classification
 	switch newClass {
 	case TrackStatusPending:
@@ -512,7 +574,9 @@ func (u *UASThreat) UpdateObservedKinematics(newPos *models.GeomPoint) {
 		}
 
 		// Update behavior based on movement pattern
-		if u.EstimatedSpeed > 150 {
+		if u.LaunchPhase == LaunchPhaseForming {
+			u.ObservedBehavior = BehaviorForming
+		} else if u.EstimatedSpeed > 150 {
 			u.ObservedBehavior = BehaviorAggressive
 		} else if u.EstimatedSpeed < 20 {
 			u.ObservedBehavior = BehaviorSurveillance
@@ -525,6 +589,11 @@ func (u *UASThreat) UpdateObservedKinematics(newPos *models.GeomPoint) {
 	u.LastUpdateTime = time.Now()
 }
 
+// holdingAtLaunchSite reports whether the threat is still grounded or assembling
+func (u *UASThreat) holdingAtLaunchSite() bool {
+	return u.LaunchPhase == LaunchPhaseGrounded || u.LaunchPhase == LaunchPhaseForming
+}
+
 // Location represents a geographic location
 type Location struct {
 	Lat float64
@@ -556,6 +625,28 @@ func latLonAltToECEF(lat, lon, alt float64) (x, y, z float64) {
 	return x, y, z
 }
 
+// ecefToLatLonAlt converts ECEF coordinates to latitude, longitude, and altitude
+func ecefToLatLonAlt(x, y, z float64) (lat, lon, alt float64) {
+	// WGS84 ellipsoid constants
+	a := 6378137.0           // Semi-major axis
+	f := 1.0 / 298.257223563 // Flattening
+	e2 := 2*f - f*f          // First eccentricity squared
+
+	lonRad := math.Atan2(y, x)
+	p := math.Sqrt(x*x + y*y)
+
+	// Iterate on latitude; converges to sub-millimeter accuracy in a few passes
+	latRad := math.Atan2(z, p*(1-e2))
+	var N float64
+	for i := 0; i < 5; i++ {
+		N = a / math.Sqrt(1-e2*math.Sin(latRad)*math.Sin(latRad))
+		alt = p/math.Cos(latRad) - N
+		latRad = math.Atan2(z, p*(1-e2*N/(N+alt)))
+	}
+
+	return latRad * 180.0 / math.Pi, lonRad * 180.0 / math.Pi, alt
+}
+
 // calculateDistance3D calculates the 3D Euclidean distance between two ECEF points
 func calculateDistance3D(p1, p2 *models.GeomPoint) float64 {
 	dx := p2.Coordinates[0] - p1.Coordinates[0]
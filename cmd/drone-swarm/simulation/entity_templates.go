@@ -0,0 +1,148 @@
+package simulation
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// Unit templates whose Legion taxonomy a scenario can override
+const (
+	TemplateKinetic    = "kinetic"     // Kinetic Counter-UAS systems
+	TemplateEW         = "ew"          // Electronic warfare Counter-UAS systems
+	TemplateThreat     = "threat"      // UAS threat tracks
+	TemplateGroundUnit = "ground_unit" // Friendly ground units
+)
+
+// entityTemplateNames lists the templates entity_templates accepts
+var entityTemplateNames = []string{TemplateKinetic, TemplateEW, TemplateThreat, TemplateGroundUnit}
+
+// legionCategories and legionAffiliations are the values Legion accepts
+var (
+	legionCategories = []models.Category{
+		models.CategoryDEVICE, models.CategoryDETECTION, models.CategoryALERT, models.CategoryWEATHER,
+		models.CategoryGEOMETRIC, models.CategoryZONE, models.CategorySENSOR, models.CategoryVEHICLE,
+		models.CategoryUXV, models.CategoryTRACK,
+	}
+	legionAffiliations = []models.Affiliation{
+		models.AffiliationPENDING, models.AffiliationUNKNOWN, models.AffiliationASSUMEDFRIEND,
+		models.AffiliationFRIEND, models.AffiliationNEUTRAL, models.AffiliationSUSPECT, models.AffiliationHOSTILE,
+		models.AffiliationEXERCISEPENDING, models.AffiliationEXERCISEUNKNOWN, models.AffiliationEXERCISEFRIEND,
+		models.AffiliationEXERCISENEUTRAL, models.AffiliationEXERCISEASSUMEDFRIEND, models.AffiliationJOKER,
+		models.AffiliationFAKER, models.AffiliationNONESPECIFIED,
+	}
+)
+
+// EntityTemplate overrides the Legion category, type and affiliation a unit template is
+// created with, for organizations whose Legion taxonomy differs from the simulation's.
+// Empty fields keep the simulation's value.
+type EntityTemplate struct {
+	Category    models.Category
+	Type        string
+	Affiliation models.Affiliation // For threats, only until the track is first classified
+}
+
+// EntityTemplates are a scenario's overrides, keyed by unit template
+type EntityTemplates map[string]EntityTemplate
+
+// parseEntityTemplates parses "template:category[:type[:affiliation]]" entries separated
+// by semicolons, e.g. "ew:SENSOR;threat:UXV:Quadcopter"
+func parseEntityTemplates(spec string) (EntityTemplates, error) {
+	templates := make(EntityTemplates)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ":")
+		if len(fields) < 2 || len(fields) > 4 {
+			return nil, fmt.Errorf("entity template %q: expected template:category[:type[:affiliation]]", entry)
+		}
+		for i := range fields {
+			fields[i] = strings.TrimSpace(fields[i])
+		}
+		fields = append(fields, "", "")
+
+		name := strings.ToLower(fields[0])
+		if !slices.Contains(entityTemplateNames, name) {
+			return nil, fmt.Errorf("entity template %q: unknown template (use %s)", fields[0], strings.Join(entityTemplateNames, ", "))
+		}
+		if _, dup := templates[name]; dup {
+			return nil, fmt.Errorf("entity template %s is given twice", name)
+		}
+
+		template := EntityTemplate{
+			Category:    models.Category(strings.ToUpper(fields[1])),
+			Type:        fields[2],
+			Affiliation: models.Affiliation(strings.ToUpper(fields[3])),
+		}
+		if template.Category != "" && !slices.Contains(legionCategories, template.Category) {
+			return nil, fmt.Errorf("entity template %s: unknown category %q", name, fields[1])
+		}
+		if template.Affiliation != "" && !slices.Contains(legionAffiliations, template.Affiliation) {
+			return nil, fmt.Errorf("entity template %s: unknown affiliation %q", name, fields[3])
+		}
+		if template == (EntityTemplate{}) {
+			return nil, fmt.Errorf("entity template %s overrides nothing", name)
+		}
+		templates[name] = template
+	}
+	return templates, nil
+}
+
+// systemTemplate is the template of a Counter-UAS system's engagement type
+func systemTemplate(engagementType string) string {
+	if engagementType == EngagementTypeEW {
+		return TemplateEW
+	}
+	return TemplateKinetic
+}
+
+// applyEntityTemplate overrides a create request with the scenario's template, if any
+func (s *DroneSwarmSimulation) applyEntityTemplate(name string, req *models.CreateEntityRequest) {
+	template, ok := s.config.EntityTemplates[name]
+	if !ok {
+		return
+	}
+	if template.Category != "" {
+		category := template.Category
+		req.Category = &category
+	}
+	if template.Type != "" {
+		entityType := template.Type
+		req.Type = &entityType
+	}
+	if template.Affiliation != "" {
+		req.Affiliation = template.Affiliation
+	}
+}
+
+// describeEntityTemplates summarizes the overrides in effect for the startup log
+func describeEntityTemplates(templates EntityTemplates) string {
+	names := make([]string, 0, len(templates))
+	for name := range templates {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		t := templates[name]
+		var overrides []string
+		if t.Category != "" {
+			overrides = append(overrides, "category "+string(t.Category))
+		}
+		if t.Type != "" {
+			overrides = append(overrides, "type "+t.Type)
+		}
+		if t.Affiliation != "" {
+			overrides = append(overrides, "affiliation "+string(t.Affiliation))
+		}
+		parts = append(parts, fmt.Sprintf("%s (%s)", name, strings.Join(overrides, ", ")))
+	}
+	return strings.Join(parts, "; ")
+}
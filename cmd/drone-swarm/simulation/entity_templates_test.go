@@ -0,0 +1,62 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestParseEntityTemplates(t *testing.T) {
+	templates, err := parseEntityTemplates("ew:sensor; threat:UXV:Group1-Quadcopter ;ground_unit:::exercise_friend")
+	if err != nil {
+		t.Fatalf("parseEntityTemplates: %v", err)
+	}
+	want := EntityTemplates{
+		TemplateEW:         {Category: models.CategorySENSOR},
+		TemplateThreat:     {Category: models.CategoryUXV, Type: "Group1-Quadcopter"},
+		TemplateGroundUnit: {Affiliation: models.AffiliationEXERCISEFRIEND},
+	}
+	if len(templates) != len(want) {
+		t.Fatalf("got %d templates, want %d", len(templates), len(want))
+	}
+	for name, template := range want {
+		if templates[name] != template {
+			t.Errorf("%s = %+v, want %+v", name, templates[name], template)
+		}
+	}
+
+	for _, spec := range []string{
+		"radar:SENSOR",              // unknown template
+		"ew:ANTENNA",                // unknown category
+		"kinetic:::ALLY",            // unknown affiliation
+		"ew",                        // no overrides given
+		"ew::",                      // empty overrides
+		"ew:SENSOR;ew:DEVICE",       // duplicate
+		"ew:SENSOR:Jammer:FRIEND:x", // too many fields
+	} {
+		if _, err := parseEntityTemplates(spec); err == nil {
+			t.Errorf("parseEntityTemplates(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestApplyEntityTemplateKeepsUnsetFields(t *testing.T) {
+	s := &DroneSwarmSimulation{config: SimulationConfig{EntityTemplates: EntityTemplates{
+		TemplateThreat: {Type: "Group1-Quadcopter"},
+	}}}
+	category := models.CategoryTRACK
+	entityType := EntityTypeUAS
+	req := &models.CreateEntityRequest{Category: &category, Type: &entityType, Affiliation: models.AffiliationUNKNOWN}
+
+	s.applyEntityTemplate(TemplateKinetic, req)
+	if *req.Type != EntityTypeUAS {
+		t.Fatalf("template for another unit applied: type %s", *req.Type)
+	}
+	s.applyEntityTemplate(TemplateThreat, req)
+	if *req.Type != "Group1-Quadcopter" || *req.Category != models.CategoryTRACK || req.Affiliation != models.AffiliationUNKNOWN {
+		t.Errorf("got category %s, type %s, affiliation %s", *req.Category, *req.Type, req.Affiliation)
+	}
+	if category != models.CategoryTRACK {
+		t.Error("template modified the caller's category")
+	}
+}
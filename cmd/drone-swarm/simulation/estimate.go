@@ -0,0 +1,108 @@
+package simulation
+
+import (
+	"math"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// healthTelemetryInterval matches the periodic health feed cadence
+const healthTelemetryInterval = 5 * time.Second
+
+// Estimate predicts the Legion load of the configured run. Run-time rates are an upper
+// bound: every local threat is assumed airborne and tracked for the whole run.
+func (s *DroneSwarmSimulation) Estimate() simulation.Estimate {
+	c := s.config
+
+	systems := 0
+	if s.ownsBlueForce() {
+		systems = c.NumCounterUASSystems
+	}
+	ownedWaves := 0
+	for wave := 1; wave <= c.NumWaves; wave++ {
+		if s.ownsWave(wave) {
+			ownedWaves++
+		}
+	}
+	threats := c.NumUASThreats
+	if c.NumWaves > 0 {
+		threats = int(math.Ceil(float64(c.NumUASThreats*ownedWaves) / float64(c.NumWaves)))
+	}
+	board := 0
+	if c.ThreatBoardSize > 0 && systems > 0 {
+		board = 1
+	}
+	clock := 0
+	if c.TimeMarkerInterval > 0 && s.ownsBlueForce() {
+		clock = 1
+	}
+
+	// Calls follow wall time, which time_scale shortens or stretches
+	wallDuration := c.SimDuration
+	if c.TimeScale > 0 {
+		wallDuration = time.Duration(float64(c.SimDuration) / c.TimeScale)
+	}
+	est := simulation.Estimate{
+		Duration: wallDuration,
+		Entities: systems + threats + board + clock,
+	}
+
+	// Setup: create and place every entity, then a feed per system and the threat board.
+	// The range clock isn't placed; its second call creates its marker feed.
+	est.SetupCalls = 2*est.Entities + 2*systems + board
+	if c.WarmupDuration > 0 {
+		est.SetupCalls += 2 * systems // BIT and ready status patches
+	}
+
+	ticksPerMinute := float64(time.Minute) / float64(c.UpdateInterval)
+	decimation := float64(max(c.UpdateDecimation, 1))
+	minutes := wallDuration.Minutes()
+
+	add := func(source string, perMinute float64, feed bool) {
+		calls := int(math.Ceil(perMinute * minutes))
+		est.Breakdown = append(est.Breakdown, simulation.EstimateLine{Source: source, Calls: calls, CallsPerMinute: perMinute})
+		est.RunCalls += calls
+		est.PeakCallsPerMinute += perMinute
+		if feed {
+			est.FeedMessages += calls
+			est.FeedMessagesPerMinute += perMinute
+		}
+	}
+	positionsPerMinute, updatesPerMinute := ticksPerMinute/decimation, ticksPerMinute
+	if high := c.UpdateTiers[UpdateTierHigh].Interval; high > 0 {
+		// Update tiers send no entity more often than the high tier
+		tierPerMinute := float64(time.Minute) / float64(high)
+		positionsPerMinute = min(positionsPerMinute, tierPerMinute)
+		updatesPerMinute = min(updatesPerMinute, tierPerMinute)
+	}
+	add("Threat positions", float64(threats)*positionsPerMinute, false)
+	add("Threat status and metadata", float64(threats)*updatesPerMinute, false)
+	add("System status and metadata", float64(systems)*updatesPerMinute, false)
+	add("Health telemetry feed", float64(systems)*float64(time.Minute)/float64(healthTelemetryInterval), true)
+	if board > 0 {
+		add("Threat board feed", float64(time.Minute)/float64(c.ThreatBoardInterval), true)
+	}
+	if clock > 0 {
+		add("Time marker feed", float64(time.Minute)/float64(max(c.TimeMarkerInterval, c.UpdateInterval)), true)
+	}
+
+	// One-off calls that do not add to the per-minute rate
+	if c.TrackGCGrace > 0 {
+		est.Breakdown = append(est.Breakdown, simulation.EstimateLine{Source: "Track cleanup deletes", Calls: threats})
+		est.RunCalls += threats
+	}
+	if c.VerifyLegion {
+		est.Breakdown = append(est.Breakdown, simulation.EstimateLine{Source: "Post-run verification", Calls: 2 * (systems + threats)})
+		est.RunCalls += 2 * (systems + threats)
+	}
+
+	est.Notes = append(est.Notes, "Rates assume every threat is airborne and tracked for the whole run")
+	if len(c.UpdateTiers) > 0 {
+		est.Notes = append(est.Notes, "Update rates assume every entity is in the high tier; idle and distant ones publish less often")
+	}
+	if c.CleanupExisting {
+		est.Notes = append(est.Notes, "Cleanup adds a search plus a delete per entity left by earlier runs")
+	}
+	return est
+}
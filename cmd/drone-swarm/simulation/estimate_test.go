@@ -0,0 +1,42 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/shard"
+)
+
+func TestEstimate(t *testing.T) {
+	s := &DroneSwarmSimulation{config: SimulationConfig{
+		NumCounterUASSystems: 10,
+		NumUASThreats:        50,
+		NumWaves:             5,
+		SimDuration:          2 * time.Minute,
+		UpdateInterval:       500 * time.Millisecond,
+		UpdateDecimation:     1,
+		ShardRole:            shard.RoleStandalone,
+		ShardCount:           1,
+	}}
+
+	est := s.Estimate()
+	if est.Entities != 60 {
+		t.Errorf("expected 60 entities, got %d", est.Entities)
+	}
+	// 50 threats x 120 ticks/min for positions and again for status, 10 systems x 120, 10 x 12 health
+	if est.PeakCallsPerMinute != 6000+6000+1200+120 {
+		t.Errorf("unexpected peak rate %.0f", est.PeakCallsPerMinute)
+	}
+	if est.FeedMessages != 240 {
+		t.Errorf("expected 240 health messages over 2 minutes, got %d", est.FeedMessages)
+	}
+
+	s.config.UpdateDecimation = 4
+	if decimated := s.Estimate(); decimated.PeakCallsPerMinute != est.PeakCallsPerMinute-4500 {
+		t.Errorf("decimation should cut position calls to a quarter, got %.0f", decimated.PeakCallsPerMinute)
+	}
+
+	if warnings := est.QuotaWarnings(10000, 0, 100); len(warnings) != 1 {
+		t.Errorf("expected a calls/min warning only, got %v", warnings)
+	}
+}
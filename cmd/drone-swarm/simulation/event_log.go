@@ -0,0 +1,31 @@
+package simulation
+
+import (
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// saveEventLog writes the run's events and metrics when save_events is set, so
+// legion-sim report can regenerate the AAR in another format or detail level later.
+// The log is listed as an AAR attachment; it doesn't list itself.
+func (s *DroneSwarmSimulation) saveEventLog() error {
+	if !s.config.SaveEvents || s.aarGenerator == nil {
+		return nil
+	}
+	dump := s.aarGenerator.Dump()
+	dump.RunID = s.runID
+
+	path := filepath.Join(reportsDir, fmt.Sprintf("Events_%s_%s.json", s.runID[:8], time.Now().Format("20060102_150405")))
+	if err := reporting.SaveEventDump(path, dump); err != nil {
+		return err
+	}
+
+	s.artifacts = append(s.artifacts, path)
+	s.aarGenerator.AddAttachment(path)
+	logger.Successf("Event log saved to: %s (%d events; legion-sim report --from %s)", path, len(dump.Events), path)
+	return nil
+}
@@ -0,0 +1,299 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/core"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// DefaultFaction is the single red force used when no factions are configured
+const DefaultFaction = "Red"
+
+// Faction interference
+const (
+	factionConflictRange = 75.0 // Meters; closer raids from different factions get in each other's way
+	factionConflictForce = 8.0  // m/s of sideways push per tick while in conflict
+)
+
+// Faction is an independent attacking force. Factions fly their own swarms against
+// their own objective and don't deconflict with each other.
+type Faction struct {
+	Name             string
+	Share            float64 // Relative share of the threats in every wave
+	ObjectiveBearing float64 // True bearing of the objective from the base
+	ObjectiveOffset  float64 // Meters from the base (0 attacks the base itself)
+}
+
+// factionLog holds per-faction runtime state
+type factionLog struct {
+	engines      map[string]*core.SwarmBehaviorEngine // One engine per faction so wave state isn't shared
+	conflicts    map[[2]uuid.UUID]bool                // Cross-faction pairs already counted
+	interference map[string]int                       // Cross-faction encounters by faction
+}
+
+// parseFactions parses "name:share[:bearing_deg:offset_m]" entries separated by
+// semicolons, e.g. "Red:2;Orange:1:120:1500"
+func parseFactions(spec string) ([]Faction, error) {
+	var factions []Faction
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ":")
+		if len(fields) != 2 && len(fields) != 4 {
+			return nil, fmt.Errorf("faction %q: expected name:share[:bearing_deg:offset_m]", entry)
+		}
+
+		faction := Faction{Name: strings.TrimSpace(fields[0])}
+		if faction.Name == "" {
+			return nil, fmt.Errorf("faction %q: name is required", entry)
+		}
+		if seen[faction.Name] {
+			return nil, fmt.Errorf("faction %s: listed twice", faction.Name)
+		}
+		seen[faction.Name] = true
+
+		values := make([]float64, len(fields)-1)
+		for i, field := range fields[1:] {
+			v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return nil, fmt.Errorf("faction %s: invalid number %q", faction.Name, field)
+			}
+			values[i] = v
+		}
+		faction.Share = values[0]
+		if len(values) == 3 {
+			faction.ObjectiveBearing, faction.ObjectiveOffset = math.Mod(values[1]+360, 360), values[2]
+		}
+
+		switch {
+		case faction.Share <= 0:
+			return nil, fmt.Errorf("faction %s: share must be positive", faction.Name)
+		case faction.ObjectiveOffset < 0:
+			return nil, fmt.Errorf("faction %s: objective offset cannot be negative", faction.Name)
+		}
+		factions = append(factions, faction)
+	}
+	return factions, nil
+}
+
+// splitByShare divides total threats between factions in proportion to their shares,
+// handing the remainder to the largest fractional parts
+func splitByShare(total int, factions []Faction) []int {
+	counts := make([]int, len(factions))
+	if len(factions) == 0 || total <= 0 {
+		return counts
+	}
+
+	var sum float64
+	for _, f := range factions {
+		sum += f.Share
+	}
+
+	type remainder struct {
+		index int
+		frac  float64
+	}
+	remainders := make([]remainder, len(factions))
+	assigned := 0
+	for i, f := range factions {
+		exact := float64(total) * f.Share / sum
+		counts[i] = int(exact)
+		assigned += counts[i]
+		remainders[i] = remainder{i, exact - float64(counts[i])}
+	}
+	sort.SliceStable(remainders, func(a, b int) bool { return remainders[a].frac > remainders[b].frac })
+	for i := 0; assigned < total; i++ {
+		counts[remainders[i%len(remainders)].index]++
+		assigned++
+	}
+	return counts
+}
+
+// objectiveAsset names the asset a faction attacks
+func (f Faction) objectiveAsset() string {
+	if f.ObjectiveOffset == 0 {
+		return BaseAssetName
+	}
+	return f.Name + " objective"
+}
+
+// multiFaction reports whether more than one red force is in play
+func (s *DroneSwarmSimulation) multiFaction() bool {
+	return len(s.config.Factions) > 1
+}
+
+// factionOf returns the faction a threat belongs to
+func (s *DroneSwarmSimulation) factionOf(threat *UASThreat) Faction {
+	for _, f := range s.config.Factions {
+		if f.Name == threat.ActualCapabilities.Faction {
+			return f
+		}
+	}
+	return Faction{Name: DefaultFaction, Share: 1}
+}
+
+// objectiveECEF returns the point a threat is attacking: a shared-world asset it was
+// sent after, or its faction's objective
+func (s *DroneSwarmSimulation) objectiveECEF(threat *UASThreat) (float64, float64, float64) {
+	if asset, ok := s.worldTarget(threat); ok {
+		return latLonAltToECEF(asset.Lat, asset.Lon, asset.Alt)
+	}
+	base := s.config.BaseLocation
+	faction := s.factionOf(threat)
+	if faction.ObjectiveOffset == 0 {
+		return latLonAltToECEF(base.Lat, base.Lon, base.Alt)
+	}
+	lat, lon := destinationPoint(base.Lat, base.Lon, faction.ObjectiveBearing, faction.ObjectiveOffset)
+	return latLonAltToECEF(lat, lon, base.Alt)
+}
+
+// startFactions creates a swarm engine for every faction
+func (s *DroneSwarmSimulation) startFactions() {
+	s.factions = factionLog{
+		engines:      make(map[string]*core.SwarmBehaviorEngine, len(s.config.Factions)),
+		conflicts:    make(map[[2]uuid.UUID]bool),
+		interference: make(map[string]int),
+	}
+	for _, f := range s.config.Factions {
+		s.factions.engines[f.Name] = core.NewSwarmBehaviorEngine(simulation.DeriveRand(s.random()))
+	}
+
+	if !s.multiFaction() {
+		return
+	}
+	for _, f := range s.config.Factions {
+		if f.ObjectiveOffset == 0 {
+			logger.Infof("🔴 Faction %s (share %.1f) attacking %s", f.Name, f.Share, BaseAssetName)
+		} else {
+			logger.Infof("🔴 Faction %s (share %.1f) attacking an objective %.0fm from the base on bearing %.0f°",
+				f.Name, f.Share, f.ObjectiveOffset, f.ObjectiveBearing)
+		}
+	}
+}
+
+// swarmID names the swarm C2 sees for a wave. Factions in the same wave fly separate swarms.
+func (s *DroneSwarmSimulation) swarmID(faction string, wave int) string {
+	if !s.multiFaction() {
+		return fmt.Sprintf("SWARM-%02d", wave)
+	}
+	return fmt.Sprintf("SWARM-%s-%02d", strings.ToUpper(faction), wave)
+}
+
+// swarmCenter returns a swarm's center of mass from its faction's engine
+func (s *DroneSwarmSimulation) swarmCenter(faction string, wave int, threats []*UASThreat) core.Vector3D {
+	swarm := &core.Swarm{
+		ID:          s.swarmID(faction, wave),
+		TeamName:    faction,
+		CurrentWave: wave,
+		Drones:      make([]*core.Drone, 0, len(threats)),
+	}
+	for _, threat := range threats {
+		swarm.Drones = append(swarm.Drones, &core.Drone{
+			ID:         threat.ID,
+			Position:   core.Vector3D{X: threat.Position.Coordinates[0], Y: threat.Position.Coordinates[1], Z: threat.Position.Coordinates[2]},
+			Status:     "INBOUND",
+			WaveNumber: wave,
+		})
+	}
+
+	engine := s.factions.engines[faction]
+	if engine == nil {
+		engine = core.NewSwarmBehaviorEngine(simulation.DeriveRand(s.random()))
+		s.factions.engines[faction] = engine
+	}
+	engine.UpdateSwarmMetrics(swarm)
+	return swarm.CenterMass
+}
+
+// applyFactionInterference pushes apart airborne threats from different factions that
+// stray within conflict range of each other. Nobody deconflicts, so raids crossing paths
+// lose formation; each new pairing is counted against both factions.
+func (s *DroneSwarmSimulation) applyFactionInterference(threats []*UASThreat) {
+	if !s.multiFaction() {
+		return
+	}
+
+	for i, a := range threats {
+		for _, b := range threats[i+1:] {
+			if a.ActualCapabilities.Faction == b.ActualCapabilities.Faction {
+				continue
+			}
+
+			dx := a.Position.Coordinates[0] - b.Position.Coordinates[0]
+			dy := a.Position.Coordinates[1] - b.Position.Coordinates[1]
+			distance := math.Sqrt(dx*dx + dy*dy)
+			if distance >= factionConflictRange || distance == 0 {
+				continue
+			}
+
+			pushX := dx / distance * factionConflictForce
+			pushY := dy / distance * factionConflictForce
+			a.ActualVelocity.Coordinates[0] += pushX
+			a.ActualVelocity.Coordinates[1] += pushY
+			b.ActualVelocity.Coordinates[0] -= pushX
+			b.ActualVelocity.Coordinates[1] -= pushY
+
+			key := [2]uuid.UUID{a.ID, b.ID}
+			if b.ID.String() < a.ID.String() {
+				key = [2]uuid.UUID{b.ID, a.ID}
+			}
+			if s.factions.conflicts[key] {
+				continue
+			}
+			s.factions.conflicts[key] = true
+			s.factions.interference[a.ActualCapabilities.Faction]++
+			s.factions.interference[b.ActualCapabilities.Faction]++
+			if s.config.EnableDebugLogging {
+				behaviorLog.Debugf("%s (%s) and %s (%s) crossed paths at %.0fm",
+					a.TrackNumber, a.ActualCapabilities.Faction, b.TrackNumber, b.ActualCapabilities.Faction, distance)
+			}
+		}
+	}
+}
+
+// recordFactionOutcomes logs each faction's final strength for the AAR team analysis
+func (s *DroneSwarmSimulation) recordFactionOutcomes() {
+	s.mu.RLock()
+	total := make(map[string]int)
+	destroyed := make(map[string]int)
+	for _, threat := range s.uasThreats {
+		if threat.Remote {
+			continue // Reported by the owning shard
+		}
+		faction := s.factionOf(threat).Name
+		total[faction]++
+		if threat.Classification == TrackStatusDestroyed {
+			destroyed[faction]++
+		}
+	}
+	s.mu.RUnlock()
+
+	s.stats.mu.RLock()
+	leaked := make(map[string]int, len(s.stats.Leakage.ByFaction))
+	for faction, n := range s.stats.Leakage.ByFaction {
+		leaked[faction] = n
+	}
+	s.stats.mu.RUnlock()
+
+	for _, f := range s.config.Factions {
+		if total[f.Name] == 0 {
+			continue
+		}
+		s.simLogger.LogFactionOutcome(f.Name, total[f.Name], destroyed[f.Name], leaked[f.Name], s.factions.interference[f.Name])
+		if s.multiFaction() {
+			logger.Infof("🔴 Faction %s: %d launched, %d destroyed, %d reached %s, %d cross-faction encounters",
+				f.Name, total[f.Name], destroyed[f.Name], leaked[f.Name], f.objectiveAsset(), s.factions.interference[f.Name])
+		}
+	}
+}
@@ -0,0 +1,69 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestParseFactions(t *testing.T) {
+	factions, err := parseFactions("Red:2; Orange:1:-90:1500;")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(factions) != 2 {
+		t.Fatalf("expected 2 factions, got %d", len(factions))
+	}
+	if factions[0].Name != "Red" || factions[0].Share != 2 || factions[0].objectiveAsset() != BaseAssetName {
+		t.Errorf("unexpected first faction: %+v", factions[0])
+	}
+	if factions[1].ObjectiveBearing != 270 || factions[1].ObjectiveOffset != 1500 || factions[1].objectiveAsset() != "Orange objective" {
+		t.Errorf("expected bearing normalized to 270 and offset 1500, got %+v", factions[1])
+	}
+
+	for _, spec := range []string{"Red", "Red:1:90", ":1", "Red:x", "Red:0", "Red:1:90:-5", "Red:1;Red:2"} {
+		if _, err := parseFactions(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestSplitByShare(t *testing.T) {
+	factions := []Faction{{Name: "A", Share: 2}, {Name: "B", Share: 1}, {Name: "C", Share: 1}}
+	counts := splitByShare(10, factions)
+	if counts[0] != 5 || counts[1]+counts[2] != 5 {
+		t.Errorf("expected 5 for A and 5 between B and C, got %v", counts)
+	}
+	if counts := splitByShare(1, factions); counts[0] != 1 {
+		t.Errorf("expected the single threat to go to the largest share, got %v", counts)
+	}
+}
+
+func TestFactionInterference(t *testing.T) {
+	s := &DroneSwarmSimulation{config: SimulationConfig{Factions: []Faction{{Name: "A", Share: 1}, {Name: "B", Share: 1}}}}
+	s.startFactions()
+
+	newThreat := func(faction string, x float64) *UASThreat {
+		threat := &UASThreat{
+			ID:             uuid.New(),
+			Position:       &models.GeomPoint{Coordinates: []float64{x, 0, 0}},
+			ActualVelocity: &models.GeomPoint{Coordinates: []float64{0, 0, 0}},
+		}
+		threat.ActualCapabilities.Faction = faction
+		return threat
+	}
+	a1, a2, b := newThreat("A", 0), newThreat("A", 10), newThreat("B", 40)
+	threats := []*UASThreat{a1, a2, b}
+
+	s.applyFactionInterference(threats)
+	s.applyFactionInterference(threats)
+
+	if a1.ActualVelocity.Coordinates[0] >= 0 || b.ActualVelocity.Coordinates[0] <= 0 {
+		t.Errorf("expected the factions to be pushed apart, got %v and %v", a1.ActualVelocity.Coordinates, b.ActualVelocity.Coordinates)
+	}
+	// Each cross-faction pairing is counted once however long it lasts
+	if s.factions.interference["A"] != 2 || s.factions.interference["B"] != 2 {
+		t.Errorf("expected 2 encounters per faction, got %v", s.factions.interference)
+	}
+}
@@ -0,0 +1,242 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// Ground unit kinds
+const (
+	GroundUnitInfantry = "infantry" // Dismounted squad on foot
+	GroundUnitVehicle  = "vehicle"  // Vehicle and its crew
+)
+
+// Ground unit defaults
+const (
+	EntityTypeGroundUnit   = "GroundUnit"
+	groundUnitEntityPrefix = "Ground-"
+	payloadAimRadius       = 500.0 // Meters; a leaker dives on the nearest unit this close to where it arrives
+	payloadMissSigma       = 8.0   // Meters of aiming error on each axis (1 sigma)
+)
+
+// groundUnitProfile is what a kind of unit defaults to
+type groundUnitProfile struct {
+	Strength     int     // Personnel
+	PatrolRadius float64 // Meters around the post
+	SpeedMps     float64 // Patrol speed
+}
+
+var groundUnitProfiles = map[string]groundUnitProfile{
+	GroundUnitInfantry: {Strength: 9, PatrolRadius: 40, SpeedMps: 1.4},
+	GroundUnitVehicle:  {Strength: 3, PatrolRadius: 150, SpeedMps: 5},
+}
+
+// GroundUnit is a friendly unit posted around the defended base. It patrols a loop
+// around its post and gives leakers real positions to strike.
+type GroundUnit struct {
+	Name         string
+	Kind         string
+	DistanceM    float64 // Post distance from the base
+	BearingDeg   float64 // True bearing of the post from the base
+	Strength     int     // Personnel
+	PatrolRadius float64 // Meters around the post (0 holds the post)
+}
+
+// groundUnit is a ground unit's state during the run
+type groundUnit struct {
+	GroundUnit
+	ID               uuid.UUID
+	PostLat, PostLon float64
+	Lat, Lon         float64
+	PatrolAngle      float64 // Bearing from the post, degrees
+	Hits             int     // Payloads that landed on the unit
+}
+
+// parseGroundUnits parses "name:kind:distance_m:bearing_deg[:strength[:patrol_m]]" entries
+// separated by semicolons, e.g. "1st Squad:infantry:250:40;Motor Pool:vehicle:400:200:6:0"
+func parseGroundUnits(spec string) ([]GroundUnit, error) {
+	var units []GroundUnit
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ":")
+		if len(fields) < 4 || len(fields) > 6 {
+			return nil, fmt.Errorf("ground unit %q: expected name:kind:distance_m:bearing_deg[:strength[:patrol_m]]", entry)
+		}
+
+		unit := GroundUnit{Name: strings.TrimSpace(fields[0]), Kind: strings.ToLower(strings.TrimSpace(fields[1]))}
+		if unit.Name == "" {
+			return nil, fmt.Errorf("ground unit %q: name is required", entry)
+		}
+		profile, ok := groundUnitProfiles[unit.Kind]
+		if !ok {
+			return nil, fmt.Errorf("ground unit %s: kind must be %s or %s", unit.Name, GroundUnitInfantry, GroundUnitVehicle)
+		}
+		unit.Strength, unit.PatrolRadius = profile.Strength, profile.PatrolRadius
+
+		values := make([]float64, len(fields)-2)
+		for i, field := range fields[2:] {
+			v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return nil, fmt.Errorf("ground unit %s: invalid number %q", unit.Name, field)
+			}
+			values[i] = v
+		}
+		unit.DistanceM, unit.BearingDeg = values[0], math.Mod(values[1]+360, 360)
+		if len(values) > 2 {
+			unit.Strength = int(values[2])
+		}
+		if len(values) > 3 {
+			unit.PatrolRadius = values[3]
+		}
+
+		switch {
+		case unit.DistanceM < 0:
+			return nil, fmt.Errorf("ground unit %s: distance cannot be negative", unit.Name)
+		case unit.Strength < 1:
+			return nil, fmt.Errorf("ground unit %s: strength must be at least 1", unit.Name)
+		case unit.PatrolRadius < 0:
+			return nil, fmt.Errorf("ground unit %s: patrol radius cannot be negative", unit.Name)
+		}
+		units = append(units, unit)
+	}
+	return units, nil
+}
+
+// createGroundUnits publishes the configured ground units as FRIEND tracks at their posts
+func (s *DroneSwarmSimulation) createGroundUnits(ctx context.Context) error {
+	orgID, err := uuid.Parse(s.config.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("invalid organization ID: %w", err)
+	}
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	base := s.config.BaseLocation
+
+	for _, cfg := range s.config.GroundUnits {
+		unit := &groundUnit{GroundUnit: cfg, PatrolAngle: s.random().Float64() * 360}
+		unit.PostLat, unit.PostLon = destinationPoint(base.Lat, base.Lon, cfg.BearingDeg, cfg.DistanceM)
+		unit.Lat, unit.Lon = destinationPoint(unit.PostLat, unit.PostLon, unit.PatrolAngle, cfg.PatrolRadius)
+
+		metadata, err := json.Marshal(map[string]interface{}{
+			"unit":            cfg.Name,
+			"kind":            cfg.Kind,
+			"strength":        cfg.Strength,
+			"patrol_radius_m": cfg.PatrolRadius,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to marshal ground unit metadata: %w", err)
+		}
+		metadataRaw := json.RawMessage(metadata)
+
+		name := groundUnitEntityPrefix + cfg.Name
+		if s.config.UseUniqueNames {
+			name = fmt.Sprintf("%s-%d", name, time.Now().Unix())
+		}
+		name = s.named(name)
+		category := models.CategoryTRACK
+		entityType := EntityTypeGroundUnit
+		status := "ACTIVE"
+		entityReq := &models.CreateEntityRequest{
+			OrganizationID: &orgID,
+			Name:           &name,
+			Category:       &category,
+			Type:           &entityType,
+			Status:         &status,
+			Affiliation:    models.AffiliationFRIEND,
+			Metadata:       &metadataRaw,
+		}
+		s.applyEntityTemplate(TemplateGroundUnit, entityReq)
+		entity, err := s.createOrAdoptEntity(orgCtx, entityReq)
+		if err != nil {
+			return fmt.Errorf("failed to create ground unit %s: %w", cfg.Name, err)
+		}
+		unit.ID = entity.ID
+
+		recordedAt := s.recordedAt(unit.ID)
+		if _, err := s.legionClient.CreateEntityLocation(orgCtx, unit.ID.String(), &models.CreateEntityLocationRequest{
+			Position:   unit.position(base.Alt),
+			Source:     "Drone-Swarm-Simulation",
+			RecordedAt: &recordedAt,
+		}); err != nil {
+			return fmt.Errorf("failed to place ground unit %s: %w", cfg.Name, err)
+		}
+
+		s.groundUnits = append(s.groundUnits, unit)
+		logger.Infof("🪖 Posted %s (%s, %d personnel) %.0fm at %03.0f° from the base", cfg.Name, cfg.Kind, cfg.Strength, cfg.DistanceM, cfg.BearingDeg)
+	}
+	return nil
+}
+
+// position returns the unit's current position as an ECEF point at ground level
+func (u *groundUnit) position(groundAlt float64) *models.GeomPoint {
+	x, y, z := latLonAltToECEF(u.Lat, u.Lon, groundAlt)
+	pointType := "Point"
+	return &models.GeomPoint{Type: &pointType, Coordinates: []float64{x, y, z}}
+}
+
+// advanceGroundUnits walks each unit around its patrol loop
+func (s *DroneSwarmSimulation) advanceGroundUnits(deltaTime float64) {
+	for _, unit := range s.groundUnits {
+		if unit.PatrolRadius <= 0 {
+			continue
+		}
+		speed := groundUnitProfiles[unit.Kind].SpeedMps
+		unit.PatrolAngle = math.Mod(unit.PatrolAngle+(speed/unit.PatrolRadius)*deltaTime*180/math.Pi, 360)
+		unit.Lat, unit.Lon = destinationPoint(unit.PostLat, unit.PostLon, unit.PatrolAngle, unit.PatrolRadius)
+		if s.sendPositionsThisTick() {
+			s.updateBuffer.QueuePositionUpdate(unit.ID, unit.position(s.config.BaseLocation.Alt))
+		}
+	}
+}
+
+// strikeGroundUnits resolves a leaker's payload against the ground units. It dives on
+// the nearest unit near where it arrived, misses by its aiming error, and hits every
+// unit inside the payload's blast radius. Returns the units hit and their personnel.
+func (s *DroneSwarmSimulation) strikeGroundUnits(threat *UASThreat) (hit []string, personnel int) {
+	radius := s.payloadProfile(threat.ActualCapabilities.PayloadType).BlastRadius
+	if radius <= 0 || len(s.groundUnits) == 0 {
+		return nil, 0
+	}
+
+	lat, lon, _ := ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+	tx, ty := s.localMeters(lat, lon)
+	var aim *groundUnit
+	nearest := payloadAimRadius
+	for _, unit := range s.groundUnits {
+		x, y := s.localMeters(unit.Lat, unit.Lon)
+		if d := math.Hypot(x-tx, y-ty); d <= nearest {
+			aim, nearest = unit, d
+		}
+	}
+	if aim == nil {
+		return nil, 0
+	}
+
+	ax, ay := s.localMeters(aim.Lat, aim.Lon)
+	rng := threat.random()
+	ix, iy := ax+rng.NormFloat64()*payloadMissSigma, ay+rng.NormFloat64()*payloadMissSigma
+	for _, unit := range s.groundUnits {
+		x, y := s.localMeters(unit.Lat, unit.Lon)
+		if math.Hypot(x-ix, y-iy) <= radius {
+			unit.Hits++
+			hit = append(hit, unit.Name)
+			personnel += unit.Strength
+		}
+	}
+	sort.Strings(hit)
+	return hit, personnel
+}
@@ -0,0 +1,71 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestParseGroundUnits(t *testing.T) {
+	units, err := parseGroundUnits("1st Squad:infantry:250:-40; Motor Pool:Vehicle:400:200:6:0;")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(units) != 2 {
+		t.Fatalf("expected 2 units, got %d", len(units))
+	}
+	if units[0].BearingDeg != 320 || units[0].Strength != 9 || units[0].PatrolRadius != 40 {
+		t.Errorf("expected infantry defaults and bearing 320, got %+v", units[0])
+	}
+	if units[1].Kind != GroundUnitVehicle || units[1].Strength != 6 || units[1].PatrolRadius != 0 {
+		t.Errorf("unexpected vehicle: %+v", units[1])
+	}
+
+	for _, spec := range []string{"A:infantry:250", ":infantry:250:40", "A:tank:250:40", "A:infantry:x:40", "A:infantry:250:40:0", "A:vehicle:250:40:3:-1"} {
+		if _, err := parseGroundUnits(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestStrikeGroundUnits(t *testing.T) {
+	base := Location{Lat: 40, Lon: -76}
+	s := &DroneSwarmSimulation{
+		config: SimulationConfig{BaseLocation: base},
+		// A blast wide enough that the aiming error cannot miss
+		dataPack: &dataPackModel{payloads: []payloadEntry{
+			{Type: PayloadMortarDropper, Profile: PayloadProfile{Weight: 1, BlastRadius: 100}},
+			{Type: PayloadISR, Profile: PayloadProfile{Weight: 1}},
+		}},
+	}
+	post := func(name string, bearing, distance float64, strength int) *groundUnit {
+		unit := &groundUnit{GroundUnit: GroundUnit{Name: name, Strength: strength}}
+		unit.Lat, unit.Lon = destinationPoint(base.Lat, base.Lon, bearing, distance)
+		return unit
+	}
+	// Two squads dug in together to the north, a vehicle well clear to the south
+	s.groundUnits = []*groundUnit{post("2nd", 0, 310, 8), post("1st", 0, 300, 9), post("Truck", 180, 300, 3)}
+	at := func(bearing, distance float64, payload string) *UASThreat {
+		lat, lon := destinationPoint(base.Lat, base.Lon, bearing, distance)
+		x, y, z := latLonAltToECEF(lat, lon, 20)
+		return &UASThreat{
+			Position:           &models.GeomPoint{Coordinates: []float64{x, y, z}},
+			ActualCapabilities: SimulatedCapabilities{PayloadType: payload},
+		}
+	}
+
+	hit, personnel := s.strikeGroundUnits(at(10, 450, PayloadMortarDropper))
+	if len(hit) != 2 || hit[0] != "1st" || hit[1] != "2nd" || personnel != 17 {
+		t.Fatalf("expected both northern squads hit with 17 personnel, got %v and %d", hit, personnel)
+	}
+	if s.groundUnits[2].Hits != 0 {
+		t.Errorf("expected the truck to be untouched, got %d hits", s.groundUnits[2].Hits)
+	}
+
+	if hit, _ := s.strikeGroundUnits(at(10, 450, PayloadISR)); hit != nil {
+		t.Errorf("expected ISR to hit nothing, got %v", hit)
+	}
+	if hit, _ := s.strikeGroundUnits(at(90, 2000, PayloadMortarDropper)); hit != nil {
+		t.Errorf("expected a leaker far from every unit to hit nothing, got %v", hit)
+	}
+}
@@ -0,0 +1,324 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+	"github.com/picogrid/legion-simulations/pkg/world"
+)
+
+// Hazard kinds left behind by an engagement
+const (
+	HazardDebris   = "debris"   // Fragments falling under a kinetic intercept
+	HazardAcoustic = "acoustic" // Blast and launch noise around a loud effector
+)
+
+// Hazard model defaults
+const (
+	DefaultHazardDuration = 60 * time.Second
+	EntityTypeHazardArea  = "HazardArea" // Zone published for each active hazard
+	hazardEntityPrefix    = "Hazard-"
+	debrisBaseRadius      = 150.0 // Meters of scatter from an intercept at ground level
+	debrisDriftRatio      = 0.4   // Meters of drift per meter of fall
+	acousticSourceLevel   = 140.0 // dB SPL at 1m from a kinetic effector firing
+	acousticThreshold     = 85.0  // dB SPL; louder than this is a hazard to people nearby
+	hazardRingPoints      = 24    // Vertices in the published zone polygon
+)
+
+// Subjects a hazard can put at risk
+const (
+	ExposurePopulatedArea  = "populated_area"
+	ExposureNeutralTraffic = "neutral_traffic"
+)
+
+// PopulatedArea is a polygon of people on the ground the defense should avoid endangering
+type PopulatedArea struct {
+	Name     string
+	Vertices [][2]float64 // lat, lon
+}
+
+// hazardArea is a circle around an engagement where debris or noise endangers people
+type hazardArea struct {
+	ID       uuid.UUID
+	Kind     string
+	Lat, Lon float64
+	RadiusM  float64
+	CeilingM float64 // Debris falls through everything below the intercept (0 is unbounded)
+	Source   string  // Effector callsign
+	Track    string  // Track number engaged
+	Expires  time.Time
+	EntityID uuid.UUID
+	exposed  map[string]bool // Subjects already reported for this hazard
+}
+
+// parsePopulatedAreas parses "name:lat,lon lat,lon lat,lon" entries separated by ";"
+func parsePopulatedAreas(spec string) ([]PopulatedArea, error) {
+	var areas []PopulatedArea
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		name, vertices, ok := strings.Cut(entry, ":")
+		name = strings.TrimSpace(name)
+		if !ok || name == "" {
+			return nil, fmt.Errorf("populated area %q: expected name:lat,lon lat,lon lat,lon", entry)
+		}
+
+		area := PopulatedArea{Name: name}
+		for _, vertex := range strings.Fields(vertices) {
+			latText, lonText, ok := strings.Cut(vertex, ",")
+			if !ok {
+				return nil, fmt.Errorf("populated area %s: vertex %q is not lat,lon", name, vertex)
+			}
+			lat, err := strconv.ParseFloat(latText, 64)
+			if err != nil || lat < -90 || lat > 90 {
+				return nil, fmt.Errorf("populated area %s: invalid latitude %q", name, latText)
+			}
+			lon, err := strconv.ParseFloat(lonText, 64)
+			if err != nil || lon < -180 || lon > 180 {
+				return nil, fmt.Errorf("populated area %s: invalid longitude %q", name, lonText)
+			}
+			area.Vertices = append(area.Vertices, [2]float64{lat, lon})
+		}
+		if len(area.Vertices) < 3 {
+			return nil, fmt.Errorf("populated area %s: needs at least 3 vertices", name)
+		}
+		areas = append(areas, area)
+	}
+	return areas, nil
+}
+
+// debrisRadius is how far from the point under an intercept fragments come down
+func debrisRadius(heightM float64) float64 {
+	return debrisBaseRadius + debrisDriftRatio*math.Max(heightM, 0)
+}
+
+// acousticRadius is where a kinetic effector's report falls to the hazard threshold,
+// assuming spherical spreading
+func acousticRadius() float64 {
+	return math.Pow(10, (acousticSourceLevel-acousticThreshold)/20)
+}
+
+// recordHazards opens the hazard areas a kinetic engagement leaves: debris under the
+// intercept, hit or miss, and noise around the effector. Jamming leaves none.
+func (s *DroneSwarmSimulation) recordHazards(system *CounterUASSystem, threat *UASThreat) {
+	if s.config.HazardDuration <= 0 || system.EngagementType != EngagementTypeKinetic {
+		return
+	}
+
+	expires := s.scenarioNow().Add(s.config.HazardDuration)
+	lat, lon, alt := ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+	height := alt - s.config.BaseLocation.Alt
+	sysLat, sysLon, _ := ecefToLatLonAlt(system.Position.Coordinates[0], system.Position.Coordinates[1], system.Position.Coordinates[2])
+
+	for _, h := range []*hazardArea{
+		{Kind: HazardDebris, Lat: lat, Lon: lon, RadiusM: debrisRadius(height), CeilingM: math.Max(height, 0)},
+		{Kind: HazardAcoustic, Lat: sysLat, Lon: sysLon, RadiusM: acousticRadius()},
+	} {
+		h.ID = uuid.New()
+		h.Source, h.Track, h.Expires = system.Callsign, threat.TrackNumber, expires
+		h.exposed = make(map[string]bool)
+		s.hazards = append(s.hazards, h)
+
+		s.stats.mu.Lock()
+		s.stats.Hazards++
+		s.stats.mu.Unlock()
+		s.simLogger.LogHazard(h.Kind, h.Source, h.Track, h.RadiusM)
+	}
+}
+
+// updateHazards publishes new hazard areas, reports what falls inside them and removes
+// them from Legion once they clear
+func (s *DroneSwarmSimulation) updateHazards(ctx context.Context) {
+	if len(s.hazards) == 0 {
+		return
+	}
+
+	now := s.scenarioNow()
+	active := s.hazards[:0]
+	for _, h := range s.hazards {
+		if now.After(h.Expires) {
+			s.clearHazard(ctx, h)
+			continue
+		}
+		if h.EntityID == uuid.Nil {
+			s.publishHazard(ctx, h)
+		}
+		s.checkExposure(h)
+		active = append(active, h)
+	}
+	s.hazards = active
+}
+
+// checkExposure reports populated areas and neutral traffic newly inside a hazard
+func (s *DroneSwarmSimulation) checkExposure(h *hazardArea) {
+	cx, cy := s.localMeters(h.Lat, h.Lon)
+
+	for _, area := range s.config.PopulatedAreas {
+		polygon := make([][2]float64, len(area.Vertices))
+		for i, v := range area.Vertices {
+			polygon[i][0], polygon[i][1] = s.localMeters(v[0], v[1])
+		}
+		if d := polygonDistance(cx, cy, polygon); d <= h.RadiusM {
+			s.reportExposure(h, area.Name, ExposurePopulatedArea, d)
+		}
+	}
+
+	for _, traffic := range s.neutralTraffic() {
+		if h.CeilingM > 0 && traffic.Alt-s.config.BaseLocation.Alt > h.CeilingM {
+			continue // Above the intercept, clear of falling debris
+		}
+		x, y := s.localMeters(traffic.Lat, traffic.Lon)
+		if d := math.Hypot(x-cx, y-cy); d <= h.RadiusM {
+			s.reportExposure(h, traffic.Name, ExposureNeutralTraffic, d)
+		}
+	}
+}
+
+// reportExposure records a subject inside a hazard once per hazard
+func (s *DroneSwarmSimulation) reportExposure(h *hazardArea, subject, kind string, distance float64) {
+	if h.exposed[subject] {
+		return
+	}
+	h.exposed[subject] = true
+
+	s.stats.mu.Lock()
+	s.stats.CollateralExposures++
+	s.stats.mu.Unlock()
+
+	engagementLog.Warnf("⚠️ %s inside the %s hazard from %s's engagement of %s", subject, h.Kind, h.Source, h.Track)
+	s.simLogger.LogCollateral(h.Kind, subject, kind, h.Source, distance)
+}
+
+// neutralTraffic lists tracks identified as non-threats and traffic other simulations
+// published to the shared world
+func (s *DroneSwarmSimulation) neutralTraffic() []world.Asset {
+	var traffic []world.Asset
+	for _, threat := range s.getActiveThreats() {
+		threat.mu.RLock()
+		neutral := threat.Classification == TrackStatusNeutral
+		threat.mu.RUnlock()
+		if !neutral {
+			continue
+		}
+		lat, lon, alt := ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+		traffic = append(traffic, world.Asset{Name: threat.TrackNumber, Lat: lat, Lon: lon, Alt: alt})
+	}
+	return append(traffic, s.worldTraffic...)
+}
+
+// polygonDistance is how far a point is from a polygon's edge, or 0 inside it
+func polygonDistance(x, y float64, polygon [][2]float64) float64 {
+	inside := false
+	nearest := math.Inf(1)
+	for i, j := 0, len(polygon)-1; i < len(polygon); j, i = i, i+1 {
+		a, b := polygon[j], polygon[i]
+		if (a[1] > y) != (b[1] > y) && x < (b[0]-a[0])*(y-a[1])/(b[1]-a[1])+a[0] {
+			inside = !inside
+		}
+		nearest = math.Min(nearest, segmentDistance(x, y, a, b))
+	}
+	if inside {
+		return 0
+	}
+	return nearest
+}
+
+// segmentDistance is the distance from a point to the segment a-b
+func segmentDistance(x, y float64, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	t := 0.0
+	if length := dx*dx + dy*dy; length > 0 {
+		t = math.Max(0, math.Min(1, ((x-a[0])*dx+(y-a[1])*dy)/length))
+	}
+	return math.Hypot(x-(a[0]+t*dx), y-(a[1]+t*dy))
+}
+
+// hazardRing approximates a hazard's circle as a closed lon/lat ring for the zone geometry
+func hazardRing(h *hazardArea) [][]float64 {
+	ring := make([][]float64, 0, hazardRingPoints+1)
+	for _, c := range circleRing(h.Lat, h.Lon, h.RadiusM, hazardRingPoints) {
+		ring = append(ring, []float64{c[0], c[1]})
+	}
+	return ring
+}
+
+// publishHazard creates the zone entity for a hazard in Legion
+func (s *DroneSwarmSimulation) publishHazard(ctx context.Context, h *hazardArea) {
+	orgID, err := uuid.Parse(s.config.OrganizationID)
+	if err != nil {
+		return
+	}
+
+	metadata, err := json.Marshal(map[string]interface{}{
+		"hazard":     h.Kind,
+		"radius_m":   h.RadiusM,
+		"source":     h.Source,
+		"track":      h.Track,
+		"expires_at": h.Expires.Format(time.RFC3339),
+		"geometry":   map[string]interface{}{"type": "Polygon", "coordinates": [][][]float64{hazardRing(h)}},
+	})
+	if err != nil {
+		logger.Debugf("Failed to marshal hazard metadata: %v", err)
+		return
+	}
+	metadataRaw := json.RawMessage(metadata)
+
+	_, track := simulation.SplitNamespace(h.Track)
+	name := s.named(fmt.Sprintf("%s%s-%s-%s", hazardEntityPrefix, h.Kind, track, h.ID.String()[:8]))
+	category := models.CategoryZONE
+	entityType := EntityTypeHazardArea
+	status := "ACTIVE"
+	entityReq := &models.CreateEntityRequest{
+		OrganizationID: &orgID,
+		Name:           &name,
+		Category:       &category,
+		Type:           &entityType,
+		Status:         &status,
+		Affiliation:    models.AffiliationNEUTRAL,
+		Metadata:       &metadataRaw,
+	}
+
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	entity, err := s.createOrAdoptEntity(orgCtx, entityReq)
+	if err != nil {
+		logger.Warnf("Failed to publish %s hazard zone: %v", h.Kind, err)
+		return
+	}
+	h.EntityID = entity.ID
+
+	x, y, z := latLonAltToECEF(h.Lat, h.Lon, s.config.BaseLocation.Alt)
+	pointType := "Point"
+	recordedAt := time.Now()
+	locationReq := &models.CreateEntityLocationRequest{
+		Position:   &models.GeomPoint{Type: &pointType, Coordinates: []float64{x, y, z}},
+		Source:     "Drone-Swarm-Simulation",
+		RecordedAt: &recordedAt,
+	}
+	if _, err := s.legionClient.CreateEntityLocation(orgCtx, entity.ID.String(), locationReq); err != nil {
+		logger.Warnf("Failed to place %s hazard zone: %v", h.Kind, err)
+	}
+}
+
+// clearHazard removes an expired hazard's zone from Legion
+func (s *DroneSwarmSimulation) clearHazard(ctx context.Context, h *hazardArea) {
+	if h.EntityID == uuid.Nil {
+		return
+	}
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	if err := s.legionClient.DeleteEntity(orgCtx, h.EntityID.String()); err != nil {
+		logger.Debugf("Failed to clear %s hazard zone %s: %v", h.Kind, h.EntityID, err)
+	}
+}
@@ -0,0 +1,68 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/world"
+)
+
+func TestParsePopulatedAreas(t *testing.T) {
+	areas, err := parsePopulatedAreas("Village:37.01,-122.01 37.01,-121.99 36.99,-122.00; Farm:37.1,-122.1 37.1,-122.0 37.0,-122.0 37.0,-122.1")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(areas) != 2 || areas[0].Name != "Village" || len(areas[1].Vertices) != 4 {
+		t.Fatalf("unexpected areas %+v", areas)
+	}
+
+	for _, spec := range []string{"Village", "Village:37,-122 37.1,-122", "Village:37;-122 1,1 2,2", ":1,1 2,2 3,3"} {
+		if _, err := parsePopulatedAreas(spec); err == nil {
+			t.Errorf("expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestPolygonDistance(t *testing.T) {
+	square := [][2]float64{{0, 0}, {100, 0}, {100, 100}, {0, 100}}
+	if d := polygonDistance(50, 50, square); d != 0 {
+		t.Errorf("expected a point inside to be 0m away, got %.1fm", d)
+	}
+	if d := polygonDistance(150, 50, square); d != 50 {
+		t.Errorf("expected 50m to the nearest edge, got %.1fm", d)
+	}
+	if d := polygonDistance(130, 140, square); d != 50 {
+		t.Errorf("expected 50m to the nearest corner, got %.1fm", d)
+	}
+}
+
+func TestHazardExposure(t *testing.T) {
+	s := &DroneSwarmSimulation{simLogger: reporting.NewSimulationLogger("test")}
+	s.config.BaseLocation = Location{Lat: 37.0, Lon: -122.0}
+	s.config.PopulatedAreas = []PopulatedArea{
+		{Name: "Near", Vertices: [][2]float64{{37.001, -122.001}, {37.001, -121.999}, {37.003, -122.0}}},
+		{Name: "Far", Vertices: [][2]float64{{37.1, -122.1}, {37.1, -122.09}, {37.11, -122.1}}},
+	}
+	s.worldTraffic = []world.Asset{
+		{Name: "N123AB", Lat: 37.0, Lon: -122.0005, Alt: 300},
+		{Name: "N456CD", Lat: 37.0, Lon: -122.0005, Alt: 900},
+	}
+
+	h := &hazardArea{Kind: HazardDebris, Lat: 37.0, Lon: -122.0, RadiusM: debrisRadius(500), CeilingM: 500, exposed: map[string]bool{}}
+	s.checkExposure(h)
+	s.checkExposure(h) // Each subject is only reported once per hazard
+
+	if !h.exposed["Near"] || h.exposed["Far"] {
+		t.Errorf("expected only the nearby area to be exposed, got %v", h.exposed)
+	}
+	if !h.exposed["N123AB"] || h.exposed["N456CD"] {
+		t.Errorf("expected only traffic below the intercept to be exposed, got %v", h.exposed)
+	}
+	if s.stats.CollateralExposures != 2 {
+		t.Errorf("expected 2 exposures, got %d", s.stats.CollateralExposures)
+	}
+
+	if r := acousticRadius(); r < 500 || r > 600 {
+		t.Errorf("expected the acoustic hazard to reach ~560m, got %.0fm", r)
+	}
+}
@@ -0,0 +1,192 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/shard"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// Phase names a step of the simulation tick that hooks can attach to
+type Phase string
+
+// Phases in the order they run each tick
+const (
+	PhaseCoordination Phase = "coordination"
+	PhaseMovement     Phase = "movement"
+	PhaseDetection    Phase = "detection"
+	PhaseEngagement   Phase = "engagement"
+	PhaseResolution   Phase = "resolution"
+)
+
+var phases = []Phase{PhaseCoordination, PhaseMovement, PhaseDetection, PhaseEngagement, PhaseResolution}
+
+// HookPoint is whether a hook runs before or after its phase
+type HookPoint string
+
+// Hook points
+const (
+	BeforePhase HookPoint = "before"
+	AfterPhase  HookPoint = "after"
+)
+
+// Hook is extension code run around a phase, such as a custom attrition model or a data
+// exporter. It runs on the simulation loop, so it sees a consistent picture and must
+// return quickly. An error is logged and the tick carries on.
+type Hook func(ctx context.Context, state *PhaseState) error
+
+type namedHook struct {
+	name string
+	fn   Hook
+}
+
+type hookKey struct {
+	phase Phase
+	point HookPoint
+}
+
+var (
+	hooksMu sync.RWMutex
+	hooks   = make(map[hookKey][]namedHook)
+)
+
+// RegisterHook adds a hook to every drone swarm run started afterwards, typically from an
+// extension package's init. Hooks at the same point run in registration order; names
+// must be unique so a hook can't be registered twice by accident.
+func RegisterHook(phase Phase, point HookPoint, name string, fn Hook) error {
+	known := false
+	for _, p := range phases {
+		known = known || p == phase
+	}
+	if !known {
+		return fmt.Errorf("unknown phase %q", phase)
+	}
+	if point != BeforePhase && point != AfterPhase {
+		return fmt.Errorf("unknown hook point %q", point)
+	}
+	if name == "" || fn == nil {
+		return fmt.Errorf("a hook needs a name and a function")
+	}
+
+	hooksMu.Lock()
+	defer hooksMu.Unlock()
+	for _, registered := range hooks {
+		for _, h := range registered {
+			if h.name == name {
+				return fmt.Errorf("hook %s already registered", name)
+			}
+		}
+	}
+	key := hookKey{phase, point}
+	hooks[key] = append(hooks[key], namedHook{name, fn})
+	return nil
+}
+
+// registeredHooks copies the registry so a run isn't affected by later registrations
+func registeredHooks() map[hookKey][]namedHook {
+	hooksMu.RLock()
+	defer hooksMu.RUnlock()
+
+	snapshot := make(map[hookKey][]namedHook, len(hooks))
+	for key, registered := range hooks {
+		snapshot[key] = append([]namedHook(nil), registered...)
+	}
+	return snapshot
+}
+
+// PhaseState is what a hook sees of the run
+type PhaseState struct {
+	Phase   Phase
+	Point   HookPoint
+	Tick    int
+	Elapsed time.Duration // Since the scenario started
+
+	sim *DroneSwarmSimulation
+}
+
+// Threats returns the threats still in play. Remote threats are flown by another shard.
+func (p *PhaseState) Threats() []*UASThreat {
+	return p.sim.getActiveThreats()
+}
+
+// Systems returns the counter-UAS systems
+func (p *PhaseState) Systems() []*CounterUASSystem {
+	p.sim.mu.RLock()
+	defer p.sim.mu.RUnlock()
+
+	systems := make([]*CounterUASSystem, 0, len(p.sim.counterUASSystems))
+	for _, system := range p.sim.counterUASSystems {
+		systems = append(systems, system)
+	}
+	return systems
+}
+
+// Destroy removes a threat from play as an extension's own attrition, crediting cause
+// in the logs and the AAR
+func (p *PhaseState) Destroy(threat *UASThreat, cause string) {
+	s := p.sim
+	threat.mu.RLock()
+	classification := threat.Classification
+	threat.mu.RUnlock()
+	if classification == TrackStatusDestroyed || classification == TrackStatusLost {
+		return
+	}
+
+	threat.UpdateClassification(TrackStatusDestroyed)
+	s.queueClassificationUpdate(threat)
+	if threat.Remote {
+		s.shardMu.Lock()
+		s.pendingKills = append(s.pendingKills, shard.Kill{TrackID: threat.ID, By: cause})
+		s.shardMu.Unlock()
+	}
+
+	s.stats.mu.Lock()
+	s.stats.UASEliminated++
+	s.stats.mu.Unlock()
+
+	logger.Infof("💥 Track %s destroyed by %s", threat.TrackNumber, cause)
+	s.simLogger.LogDestruction(threat.ID, s.factionOf(threat).Name, fmt.Sprintf("destroyed by %s", cause))
+}
+
+// runPhase runs a phase between its before and after hooks. After hooks are skipped when
+// the phase fails, but not when it ends the scenario.
+func (s *DroneSwarmSimulation) runPhase(ctx context.Context, phase Phase, fn func(context.Context) error) error {
+	s.setPhase(string(phase))
+	s.runHooks(ctx, phase, BeforePhase)
+	err := fn(ctx)
+	if err != nil && !errors.Is(err, errSimulationTerminated) {
+		return err
+	}
+	s.runHooks(ctx, phase, AfterPhase)
+	return err
+}
+
+// runHooks calls the hooks at one point, keeping a failing or panicking hook from
+// taking the run down with it
+func (s *DroneSwarmSimulation) runHooks(ctx context.Context, phase Phase, point HookPoint) {
+	registered := s.hooks[hookKey{phase, point}]
+	if len(registered) == 0 {
+		return
+	}
+
+	state := &PhaseState{Phase: phase, Point: point, Tick: s.tick, sim: s}
+	if !s.scenarioStart.IsZero() {
+		state.Elapsed = s.scenarioElapsed()
+	}
+	for _, h := range registered {
+		func() {
+			defer func() {
+				if r := recover(); r != nil {
+					logger.Errorf("Hook %s panicked %s %s: %v", h.name, point, phase, r)
+				}
+			}()
+			if err := h.fn(ctx, state); err != nil {
+				logger.Warnf("Hook %s failed %s %s: %v", h.name, point, phase, err)
+			}
+		}()
+	}
+}
@@ -0,0 +1,82 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestPhaseHooks(t *testing.T) {
+	// Hooks apply to every later run, so leave none behind for other tests
+	hooksMu.Lock()
+	saved := hooks
+	hooks = make(map[hookKey][]namedHook)
+	hooksMu.Unlock()
+	t.Cleanup(func() {
+		hooksMu.Lock()
+		hooks = saved
+		hooksMu.Unlock()
+	})
+
+	var calls []string
+	record := func(label string) Hook {
+		return func(_ context.Context, state *PhaseState) error {
+			calls = append(calls, label)
+			if state.Phase != PhaseDetection || state.Tick != 7 {
+				t.Errorf("%s: unexpected state %+v", label, state)
+			}
+			return nil
+		}
+	}
+
+	for _, h := range []struct {
+		point HookPoint
+		name  string
+		fn    Hook
+	}{
+		{BeforePhase, "test-before", record("before")},
+		{AfterPhase, "test-after", record("after")},
+		{AfterPhase, "test-failing", func(context.Context, *PhaseState) error { return errors.New("boom") }},
+		{AfterPhase, "test-panicking", func(context.Context, *PhaseState) error { panic("boom") }},
+		{AfterPhase, "test-last", record("last")},
+	} {
+		if err := RegisterHook(PhaseDetection, h.point, h.name, h.fn); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := RegisterHook(PhaseDetection, BeforePhase, "test-before", record("again")); err == nil {
+		t.Error("expected a duplicate hook name to be rejected")
+	}
+	if err := RegisterHook("warmup", BeforePhase, "test-unknown", record("unknown")); err == nil {
+		t.Error("expected an unknown phase to be rejected")
+	}
+
+	s := &DroneSwarmSimulation{tick: 7, hooks: registeredHooks()}
+	phase := func(context.Context) error {
+		calls = append(calls, "phase")
+		return nil
+	}
+	if err := s.runPhase(context.Background(), PhaseDetection, phase); err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"before", "phase", "after", "last"}; !slices.Equal(calls, want) {
+		t.Errorf("expected %v, got %v", want, calls)
+	}
+
+	// A failing phase skips its after hooks and returns the error
+	calls = nil
+	failed := errors.New("phase failed")
+	if err := s.runPhase(context.Background(), PhaseDetection, func(context.Context) error { return failed }); err != failed {
+		t.Errorf("expected the phase error, got %v", err)
+	}
+	if len(calls) != 1 || calls[0] != "before" {
+		t.Errorf("expected only the before hook, got %v", calls)
+	}
+
+	// Other phases are unaffected
+	calls = nil
+	if err := s.runPhase(context.Background(), PhaseMovement, func(context.Context) error { return nil }); err != nil || len(calls) != 0 {
+		t.Errorf("expected no hooks around movement, got %v (%v)", calls, err)
+	}
+}
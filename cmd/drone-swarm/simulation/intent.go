@@ -0,0 +1,171 @@
+package simulation
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Intent estimation tuning
+const (
+	intentMissDistanceM = 1500.0            // Closest approach within this distance counts as targeting an asset
+	intentHorizon       = 120 * time.Second // Time-to-impact beyond this gets no priority boost
+	intentMaxCandidates = 3                 // Likely targets published per track
+)
+
+// BaseAssetName identifies the defended base in intent estimates
+const BaseAssetName = "BASE"
+
+// IntentEstimate is the blue-picture prediction of what a track is flying at.
+// It is derived only from observed positions, never from hidden threat data.
+type IntentEstimate struct {
+	PredictedTargetID   *uuid.UUID      // nil when the predicted target is the base
+	PredictedTargetName string          // Asset name, or BaseAssetName
+	TimeToImpact        time.Duration   // Time until closest approach to the predicted target
+	MissDistanceM       float64         // Predicted closest approach distance
+	ClosingSpeedMps     float64         // Observed ground speed
+	Confidence          float64         // 0.0-1.0
+	LikelyTargets       []IntentOutcome // Ranked candidates, most likely first
+	EstimatedAt         time.Time
+}
+
+// IntentOutcome is a single candidate target for a track
+type IntentOutcome struct {
+	Name          string  `json:"name"`
+	TimeToImpactS float64 `json:"time_to_impact_s"`
+	MissDistanceM float64 `json:"miss_distance_m"`
+}
+
+// intentAsset is a defended asset considered by the estimator
+type intentAsset struct {
+	id       *uuid.UUID
+	name     string
+	x, y, z  float64
+	priority int // Lower is more important; used to break ties
+}
+
+// estimateIntent predicts the likely target of a track from its observed history.
+// Returns nil when there is not enough history or the track is not closing on any asset.
+func (s *DroneSwarmSimulation) estimateIntent(threat *UASThreat) *IntentEstimate {
+	if threat.History == nil {
+		return nil
+	}
+	points := threat.History.Recent(2)
+	if len(points) < 2 {
+		return nil
+	}
+
+	prev, last := points[0], points[1]
+	dt := last.Timestamp.Sub(prev.Timestamp).Seconds()
+	if dt <= 0 {
+		return nil
+	}
+
+	vx := (last.X - prev.X) / dt
+	vy := (last.Y - prev.Y) / dt
+	vz := (last.Z - prev.Z) / dt
+	speedSq := vx*vx + vy*vy + vz*vz
+	if speedSq < 1 {
+		return nil // Effectively stationary
+	}
+
+	var outcomes []IntentOutcome
+	var best *intentAsset
+	bestTime, bestMiss := 0.0, 0.0
+
+	for _, asset := range s.intentAssets() {
+		rx := asset.x - last.X
+		ry := asset.y - last.Y
+		rz := asset.z - last.Z
+
+		// Time of closest approach along the current velocity vector
+		t := (rx*vx + ry*vy + rz*vz) / speedSq
+		if t <= 0 {
+			continue // Moving away
+		}
+
+		mx := rx - vx*t
+		my := ry - vy*t
+		mz := rz - vz*t
+		miss := math.Sqrt(mx*mx + my*my + mz*mz)
+		if miss > intentMissDistanceM {
+			continue
+		}
+
+		outcomes = append(outcomes, IntentOutcome{Name: asset.name, TimeToImpactS: t, MissDistanceM: miss})
+
+		a := asset
+		if best == nil || miss < bestMiss || (miss == bestMiss && a.priority < best.priority) {
+			best = &a
+			bestTime, bestMiss = t, miss
+		}
+	}
+
+	if best == nil {
+		return nil
+	}
+
+	sort.Slice(outcomes, func(i, j int) bool {
+		return outcomes[i].MissDistanceM < outcomes[j].MissDistanceM
+	})
+	if len(outcomes) > intentMaxCandidates {
+		outcomes = outcomes[:intentMaxCandidates]
+	}
+
+	return &IntentEstimate{
+		PredictedTargetID:   best.id,
+		PredictedTargetName: best.name,
+		TimeToImpact:        time.Duration(bestTime * float64(time.Second)),
+		MissDistanceM:       bestMiss,
+		ClosingSpeedMps:     math.Sqrt(speedSq),
+		Confidence:          1.0 - bestMiss/intentMissDistanceM,
+		LikelyTargets:       outcomes,
+		EstimatedAt:         last.Timestamp,
+	}
+}
+
+// intentAssets returns the defended assets a track may be targeting
+func (s *DroneSwarmSimulation) intentAssets() []intentAsset {
+	baseX, baseY, baseZ := latLonAltToECEF(
+		s.config.BaseLocation.Lat,
+		s.config.BaseLocation.Lon,
+		s.config.BaseLocation.Alt,
+	)
+	assets := []intentAsset{{name: BaseAssetName, x: baseX, y: baseY, z: baseZ, priority: 0}}
+
+	for _, system := range s.counterUASSystems {
+		if system.Status == CounterUASStatusOffline || system.Position == nil {
+			continue
+		}
+		id := system.ID
+		assets = append(assets, intentAsset{
+			id:       &id,
+			name:     system.Name,
+			x:        system.Position.Coordinates[0],
+			y:        system.Position.Coordinates[1],
+			z:        system.Position.Coordinates[2],
+			priority: 1,
+		})
+	}
+
+	return assets
+}
+
+// updateIntent refreshes the intent estimate stored on a tracked threat
+func (s *DroneSwarmSimulation) updateIntent(threat *UASThreat) {
+	estimate := s.estimateIntent(threat)
+
+	threat.mu.Lock()
+	threat.Intent = estimate
+	threat.mu.Unlock()
+}
+
+// intentUrgency maps predicted time-to-impact onto 0.0-1.0 (imminent = 1.0)
+func intentUrgency(intent *IntentEstimate) float64 {
+	if intent == nil || intent.TimeToImpact >= intentHorizon {
+		return 0
+	}
+	return 1.0 - intent.TimeToImpact.Seconds()/intentHorizon.Seconds()
+}
@@ -0,0 +1,44 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestEstimateIntentTowardsBase(t *testing.T) {
+	s := &DroneSwarmSimulation{
+		config: SimulationConfig{
+			BaseLocation: Location{Lat: 40.044437, Lon: -76.306229, Alt: 100},
+		},
+		counterUASSystems: make(map[uuid.UUID]*CounterUASSystem),
+	}
+
+	baseX, baseY, baseZ := latLonAltToECEF(40.044437, -76.306229, 100)
+	threat := &UASThreat{History: NewTrackHistory(10)}
+
+	// Track closing on the base along +X at 50 m/s, 2km out after the second fix
+	start := time.Now()
+	threat.History.Record(&models.GeomPoint{Coordinates: []float64{baseX - 2050, baseY, baseZ}}, start)
+	threat.History.Record(&models.GeomPoint{Coordinates: []float64{baseX - 2000, baseY, baseZ}}, start.Add(time.Second))
+
+	intent := s.estimateIntent(threat)
+	if intent == nil {
+		t.Fatal("expected an intent estimate")
+	}
+	if intent.PredictedTargetName != BaseAssetName {
+		t.Errorf("expected predicted target %s, got %s", BaseAssetName, intent.PredictedTargetName)
+	}
+	if math.Abs(intent.TimeToImpact.Seconds()-40) > 0.01 {
+		t.Errorf("expected time-to-impact 40s, got %v", intent.TimeToImpact)
+	}
+
+	// Reverse course: moving away produces no estimate
+	threat.History.Record(&models.GeomPoint{Coordinates: []float64{baseX - 2100, baseY, baseZ}}, start.Add(2*time.Second))
+	if intent := s.estimateIntent(threat); intent != nil {
+		t.Errorf("expected no estimate for an opening track, got %+v", intent)
+	}
+}
@@ -0,0 +1,28 @@
+package simulation
+
+import (
+	"time"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+)
+
+// logKillChain records the milestones a destroyed track passed on its way from first
+// detection to the kill, for the AAR's sensor-to-shooter timeline
+func (s *DroneSwarmSimulation) logKillChain(threat *UASThreat, shooter *CounterUASSystem) {
+	threat.mu.RLock()
+	milestones := map[string]time.Time{
+		reporting.MilestoneDetected:  threat.DetectedAt,
+		reporting.MilestoneHostile:   threat.HostileAt,
+		reporting.MilestoneAssigned:  threat.AssignedAt,
+		reporting.MilestoneFirstShot: threat.FirstShotAt,
+		reporting.MilestoneKilled:    threat.TerminalSince,
+	}
+	threat.mu.RUnlock()
+
+	for milestone, at := range milestones {
+		if at.IsZero() {
+			delete(milestones, milestone)
+		}
+	}
+	s.simLogger.LogKillChain(threat.ID, threat.TrackNumber, shooter.Callsign, milestones)
+}
@@ -0,0 +1,248 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// Launch phases for threats flying from configured launch sites
+const (
+	LaunchPhaseGrounded = "GROUNDED" // On the ground waiting for its launch slot
+	LaunchPhaseForming  = "FORMING"  // Orbiting the assembly point while the raid forms
+	LaunchPhaseTransit  = "TRANSIT"  // Inbound to the defended base
+)
+
+// Launch site defaults
+const (
+	DefaultWaveDelay    = 45 * time.Second // Between the first launches of consecutive waves
+	DefaultOrbitRadius  = 400.0            // Assembly orbit radius in meters
+	DefaultAssemblyHold = 20 * time.Second // Orbit time after the last drone of a wave is up
+	assemblyAltitude    = 150.0            // Assembly orbit height above the site in meters
+	climbRate           = 8.0              // m/s while climbing to the assembly orbit
+)
+
+// LaunchSite is a ground location raids launch from before transiting to the base
+type LaunchSite struct {
+	Name        string
+	DistanceKm  float64 // From the defended base
+	BearingDeg  float64 // True bearing from the base
+	RatePerMin  float64 // Drones launched per minute
+	OrbitRadius float64 // Assembly orbit radius in meters
+}
+
+// launchSlot schedules one threat relative to scenario start
+type launchSlot struct {
+	Site   int
+	Launch time.Duration // Leaves the ground
+	Depart time.Duration // Leaves the assembly orbit for the base
+}
+
+// parseLaunchSites parses "name:distance_km:bearing_deg:rate_per_min[:orbit_m]" entries
+// separated by semicolons, e.g. "North:18:10:12;East:22:95:8:600"
+func parseLaunchSites(spec string) ([]LaunchSite, error) {
+	var sites []LaunchSite
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ":")
+		if len(fields) != 4 && len(fields) != 5 {
+			return nil, fmt.Errorf("launch site %q: expected name:distance_km:bearing_deg:rate_per_min[:orbit_m]", entry)
+		}
+
+		site := LaunchSite{Name: strings.TrimSpace(fields[0]), OrbitRadius: DefaultOrbitRadius}
+		if site.Name == "" {
+			return nil, fmt.Errorf("launch site %q: name is required", entry)
+		}
+
+		values := make([]float64, len(fields)-1)
+		for i, field := range fields[1:] {
+			v, err := strconv.ParseFloat(strings.TrimSpace(field), 64)
+			if err != nil {
+				return nil, fmt.Errorf("launch site %s: invalid number %q", site.Name, field)
+			}
+			values[i] = v
+		}
+		site.DistanceKm, site.BearingDeg, site.RatePerMin = values[0], math.Mod(values[1]+360, 360), values[2]
+		if len(values) == 4 {
+			site.OrbitRadius = values[3]
+		}
+
+		switch {
+		case site.DistanceKm <= 0:
+			return nil, fmt.Errorf("launch site %s: distance must be positive", site.Name)
+		case site.RatePerMin <= 0:
+			return nil, fmt.Errorf("launch site %s: launch rate must be positive", site.Name)
+		case site.OrbitRadius <= 0:
+			return nil, fmt.Errorf("launch site %s: orbit radius must be positive", site.Name)
+		}
+		sites = append(sites, site)
+	}
+	return sites, nil
+}
+
+// planLaunches assigns waves to sites round-robin. Each wave's first drone launches
+// waveDelay after the previous wave's, the rest follow at the site's launch rate, and
+// the raid departs once its last drone has held in the assembly orbit.
+func planLaunches(sites []LaunchSite, waveSizes []int, waveDelay time.Duration) [][]launchSlot {
+	plan := make([][]launchSlot, len(waveSizes))
+	for wave, size := range waveSizes {
+		siteIdx := wave % len(sites)
+		interval := time.Duration(float64(time.Minute) / sites[siteIdx].RatePerMin)
+		first := time.Duration(wave) * waveDelay
+		depart := first + time.Duration(max(size-1, 0))*interval + DefaultAssemblyHold
+
+		plan[wave] = make([]launchSlot, size)
+		for i := range plan[wave] {
+			plan[wave][i] = launchSlot{
+				Site:   siteIdx,
+				Launch: first + time.Duration(i)*interval,
+				Depart: depart,
+			}
+		}
+	}
+	return plan
+}
+
+// destinationPoint returns the point distanceM along a great circle from lat/lon at the given bearing
+func destinationPoint(lat, lon, bearingDeg, distanceM float64) (float64, float64) {
+	const earthRadius = 6371000.0
+	phi1 := lat * math.Pi / 180
+	lambda1 := lon * math.Pi / 180
+	theta := bearingDeg * math.Pi / 180
+	delta := distanceM / earthRadius
+
+	phi2 := math.Asin(math.Sin(phi1)*math.Cos(delta) + math.Cos(phi1)*math.Sin(delta)*math.Cos(theta))
+	lambda2 := lambda1 + math.Atan2(math.Sin(theta)*math.Sin(delta)*math.Cos(phi1),
+		math.Cos(delta)-math.Sin(phi1)*math.Sin(phi2))
+
+	return phi2 * 180 / math.Pi, math.Mod(lambda2*180/math.Pi+540, 360) - 180
+}
+
+// deployAtLaunchSites places threats on the ground at their launch sites and schedules
+// their launches; they stay put until the simulation clock reaches their slot
+func (s *DroneSwarmSimulation) deployAtLaunchSites() {
+	// Group local threats by wave in track-number order so slots are deterministic
+	byWave := make(map[int][]*UASThreat)
+	for _, threat := range s.uasThreats {
+		if threat.Remote {
+			continue
+		}
+		byWave[threat.ActualCapabilities.WaveNumber] = append(byWave[threat.ActualCapabilities.WaveNumber], threat)
+	}
+
+	sizes := make([]int, s.config.NumWaves)
+	for wave, threats := range byWave {
+		sort.Slice(threats, func(i, j int) bool { return threats[i].TrackNumber < threats[j].TrackNumber })
+		if wave >= 1 && wave <= len(sizes) {
+			sizes[wave-1] = len(threats)
+		}
+	}
+	// Scheduled waves launch at their own times rather than wave_delay apart
+	delay := s.config.WaveDelay
+	if len(s.config.WaveTimes) > 0 {
+		delay = 0
+	}
+	plan := planLaunches(s.config.LaunchSites, sizes, delay)
+
+	for wave, threats := range byWave {
+		if wave < 1 || wave > len(plan) {
+			continue
+		}
+		for i, threat := range threats {
+			slot := plan[wave-1][i]
+			site := s.config.LaunchSites[slot.Site]
+			siteLat, siteLon := destinationPoint(s.config.BaseLocation.Lat, s.config.BaseLocation.Lon, site.BearingDeg, site.DistanceKm*1000)
+
+			threat.LaunchPhase = LaunchPhaseGrounded
+			threat.LaunchSite = site.Name
+			// Waves of a later act stay on the ground until it begins
+			threat.LaunchOffset = slot.Launch + s.waveLaunchOffset(wave)
+			threat.DepartOffset = slot.Depart + s.waveLaunchOffset(wave)
+			threat.AssemblyLat, threat.AssemblyLon = siteLat, siteLon
+			threat.AssemblyRadius = site.OrbitRadius
+			threat.OrbitAngle = threat.random().Float64() * 360
+			threat.GroundAltitude = s.config.BaseLocation.Alt
+
+			x, y, z := latLonAltToECEF(siteLat, siteLon, threat.GroundAltitude)
+			threat.Position.Coordinates = []float64{x, y, z}
+			threat.ActualVelocity.Coordinates = []float64{0, 0, 0}
+		}
+	}
+
+	for _, site := range s.config.LaunchSites {
+		logger.Infof("🛫 Launch site %s: %.1fkm at %03.0f°, %.0f launches/min", site.Name, site.DistanceKm, site.BearingDeg, site.RatePerMin)
+	}
+}
+
+// advanceLaunch moves a threat through its launch and assembly phases. Returns true
+// while the threat is still held at its launch site and normal movement should be skipped.
+func (s *DroneSwarmSimulation) advanceLaunch(threat *UASThreat, deltaTime float64) bool {
+	if !threat.holdingAtLaunchSite() {
+		return false
+	}
+	elapsed := s.scenarioElapsed()
+
+	if threat.LaunchPhase == LaunchPhaseGrounded {
+		if elapsed < threat.LaunchOffset {
+			return true
+		}
+		threat.LaunchPhase = LaunchPhaseForming
+		threat.ObservedBehavior = BehaviorForming
+		behaviorLog.Debugf("🛫 %s launched from %s", threat.TrackNumber, threat.LaunchSite)
+	}
+
+	if elapsed >= threat.DepartOffset {
+		threat.LaunchPhase = LaunchPhaseTransit
+		threat.ObservedBehavior = BehaviorUnknown
+		if threat.Ingress {
+			s.advanceIngress(threat)
+		} else {
+			s.headForObjective(threat)
+		}
+		behaviorLog.Debugf("➡️ %s departing %s assembly area inbound", threat.TrackNumber, threat.LaunchSite)
+		return false
+	}
+
+	// Climb while circling the assembly point
+	speed := threat.ActualCapabilities.SpeedKph / 3.6
+	threat.OrbitAngle = math.Mod(threat.OrbitAngle+(speed/threat.AssemblyRadius)*deltaTime*180/math.Pi, 360)
+	lat, lon := destinationPoint(threat.AssemblyLat, threat.AssemblyLon, threat.OrbitAngle, threat.AssemblyRadius)
+
+	_, _, alt := ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+	alt = math.Min(alt+climbRate*deltaTime, threat.GroundAltitude+assemblyAltitude)
+
+	x, y, z := latLonAltToECEF(lat, lon, alt)
+	threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2] = x, y, z
+	return true
+}
+
+// headForObjective points a threat's velocity at its faction's objective at its true speed
+func (s *DroneSwarmSimulation) headForObjective(threat *UASThreat) {
+	x, y, z := s.objectiveECEF(threat)
+	s.headFor(threat, x, y, z)
+}
+
+// headFor points a threat's velocity at an ECEF point at its true speed
+func (s *DroneSwarmSimulation) headFor(threat *UASThreat, x, y, z float64) {
+	dx := x - threat.Position.Coordinates[0]
+	dy := y - threat.Position.Coordinates[1]
+	dz := z - threat.Position.Coordinates[2]
+
+	distance := math.Sqrt(dx*dx + dy*dy + dz*dz)
+	if distance == 0 {
+		return
+	}
+	velocityMagnitude := threat.ActualCapabilities.SpeedKph / 3.6
+	threat.ActualVelocity.Coordinates[0] = (dx / distance) * velocityMagnitude
+	threat.ActualVelocity.Coordinates[1] = (dy / distance) * velocityMagnitude
+	threat.ActualVelocity.Coordinates[2] = (dz / distance) * velocityMagnitude
+}
@@ -0,0 +1,63 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestParseLaunchSites(t *testing.T) {
+	sites, err := parseLaunchSites("North:18:10:12; East:22:-90:6:600;")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sites) != 2 {
+		t.Fatalf("expected 2 sites, got %d", len(sites))
+	}
+	if sites[0].Name != "North" || sites[0].DistanceKm != 18 || sites[0].RatePerMin != 12 || sites[0].OrbitRadius != DefaultOrbitRadius {
+		t.Errorf("unexpected first site: %+v", sites[0])
+	}
+	if sites[1].BearingDeg != 270 || sites[1].OrbitRadius != 600 {
+		t.Errorf("expected bearing normalized to 270 and orbit 600, got %+v", sites[1])
+	}
+
+	for _, spec := range []string{"North:18:10", ":18:10:12", "North:x:10:12", "North:0:10:12", "North:18:10:0", "North:18:10:12:-5"} {
+		if _, err := parseLaunchSites(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestPlanLaunches(t *testing.T) {
+	sites := []LaunchSite{{Name: "A", RatePerMin: 6}, {Name: "B", RatePerMin: 12}}
+	plan := planLaunches(sites, []int{3, 2, 1}, time.Minute)
+
+	// Wave 1 at site A: one launch every 10s, departs after the last is up plus the hold
+	if plan[0][2].Launch != 20*time.Second || plan[0][0].Depart != 20*time.Second+DefaultAssemblyHold {
+		t.Errorf("unexpected wave 1 schedule: %+v", plan[0])
+	}
+	// Wave 2 rotates to site B and starts one wave delay later
+	if plan[1][0].Site != 1 || plan[1][0].Launch != time.Minute || plan[1][1].Launch != time.Minute+5*time.Second {
+		t.Errorf("unexpected wave 2 schedule: %+v", plan[1])
+	}
+	// Wave 3 wraps back to site A
+	if plan[2][0].Site != 0 || plan[2][0].Depart != 2*time.Minute+DefaultAssemblyHold {
+		t.Errorf("unexpected wave 3 schedule: %+v", plan[2])
+	}
+}
+
+func TestDestinationPoint(t *testing.T) {
+	lat, lon := destinationPoint(38.8895, -77.0353, 45, 20000)
+
+	bearing := bearingDegrees(38.8895, -77.0353, lat, lon)
+	if math.Abs(bearing-45) > 0.1 {
+		t.Errorf("expected bearing 45°, got %.2f", bearing)
+	}
+
+	x1, y1, z1 := latLonAltToECEF(38.8895, -77.0353, 0)
+	x2, y2, z2 := latLonAltToECEF(lat, lon, 0)
+	distance := math.Sqrt((x2-x1)*(x2-x1) + (y2-y1)*(y2-y1) + (z2-z1)*(z2-z1))
+	if math.Abs(distance-20000) > 50 {
+		t.Errorf("expected ~20km, got %.0fm", distance)
+	}
+}
@@ -0,0 +1,136 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// Threats without launch sites spawn this far from the base, within visual range but
+// outside immediate engagement
+const (
+	minSpawnRadius = 5000.0 // Meters
+	maxSpawnRadius = 8000.0 // Meters
+)
+
+// spawnRingPoints is how many points outline a spawn ring in the laydown
+const spawnRingPoints = 72
+
+// Laydown feature kinds
+const (
+	laydownDefendedAsset = "defended_asset"
+	laydownObjective     = "objective"
+	laydownCounterUAS    = "counter_uas"
+	laydownLaunchSite    = "launch_site"
+	laydownThreatSpawn   = "threat_spawn"
+)
+
+// systemPosition places the i-th Counter-UAS system, in name order, in ECEF: at its
+// defense site, or on the defensive ring around the base
+func (s *DroneSwarmSimulation) systemPosition(i int, site *DefenseSite) (x, y, z float64) {
+	base := s.config.BaseLocation
+	if site != nil {
+		lat, lon := destinationPoint(base.Lat, base.Lon, site.BearingDeg, site.DistanceM)
+		return latLonAltToECEF(lat, lon, base.Alt+50) // 50m elevation
+	}
+
+	baseX, baseY, baseZ := latLonAltToECEF(base.Lat, base.Lon, base.Alt)
+	angleStep := 360.0 / float64(s.config.NumCounterUASSystems)
+	angle := float64(i) * angleStep * math.Pi / 180.0
+	defenseRadius := defenseRadiusKm * 1000
+	return baseX + defenseRadius*math.Cos(angle), baseY + defenseRadius*math.Sin(angle), baseZ + 50 // 50m elevation
+}
+
+// spawnPosition is where a threat without a launch site starts, in ECEF: radius meters
+// from the base at angle radians, higher for later waves
+func (s *DroneSwarmSimulation) spawnPosition(radius, angle float64, wave int) (x, y, z float64) {
+	base := s.config.BaseLocation
+	baseX, baseY, baseZ := latLonAltToECEF(base.Lat, base.Lon, base.Alt)
+	return baseX + radius*math.Cos(angle), baseY + radius*math.Sin(angle), baseZ + 100 + float64(wave)*50
+}
+
+// Laydown returns where the configured run deploys: the base and faction objectives,
+// the Counter-UAS systems, and the launch sites or spawn ring threats start from.
+// Replayed threats start wherever their recording does and are not included.
+func (s *DroneSwarmSimulation) Laydown() simulation.Laydown {
+	c := s.config
+	laydown := simulation.Laydown{Name: s.Name()}
+
+	laydown.Features = append(laydown.Features, simulation.LaydownFeature{
+		Name:        BaseAssetName,
+		Kind:        laydownDefendedAsset,
+		Geometry:    simulation.GeometryPoint,
+		Coordinates: [][2]float64{{c.BaseLocation.Lon, c.BaseLocation.Lat}},
+		AltitudeM:   c.BaseLocation.Alt,
+	})
+	for _, f := range c.Factions {
+		if f.ObjectiveOffset == 0 {
+			continue
+		}
+		lat, lon := destinationPoint(c.BaseLocation.Lat, c.BaseLocation.Lon, f.ObjectiveBearing, f.ObjectiveOffset)
+		laydown.Features = append(laydown.Features, simulation.LaydownFeature{
+			Name:        f.objectiveAsset(),
+			Kind:        laydownObjective,
+			Geometry:    simulation.GeometryPoint,
+			Coordinates: [][2]float64{{lon, lat}},
+			AltitudeM:   c.BaseLocation.Alt,
+			Properties:  map[string]interface{}{"faction": f.Name},
+		})
+	}
+
+	if s.ownsBlueForce() {
+		// Deployed in name order, as deployEntities does
+		plan := c.systemPlan()
+		sort.Slice(plan, func(i, j int) bool { return plan[i].Name < plan[j].Name })
+		for i, system := range plan {
+			lat, lon, alt := ecefToLatLonAlt(s.systemPosition(i, system.Site))
+			props := map[string]interface{}{"engagement_type": system.EngagementType}
+			if system.Site != nil {
+				props["site"] = system.Site.Name
+			}
+			laydown.Features = append(laydown.Features, simulation.LaydownFeature{
+				Name:        s.named(system.Name),
+				Kind:        laydownCounterUAS,
+				Geometry:    simulation.GeometryPoint,
+				Coordinates: [][2]float64{{lon, lat}},
+				AltitudeM:   alt,
+				Properties:  props,
+			})
+		}
+	}
+
+	if len(c.LaunchSites) > 0 {
+		for _, site := range c.LaunchSites {
+			lat, lon := destinationPoint(c.BaseLocation.Lat, c.BaseLocation.Lon, site.BearingDeg, site.DistanceKm*1000)
+			laydown.Features = append(laydown.Features, simulation.LaydownFeature{
+				Name:        site.Name,
+				Kind:        laydownLaunchSite,
+				Geometry:    simulation.GeometryPoint,
+				Coordinates: [][2]float64{{lon, lat}},
+				AltitudeM:   c.BaseLocation.Alt,
+				Properties:  map[string]interface{}{"rate_per_min": site.RatePerMin},
+			})
+		}
+		return laydown
+	}
+
+	// Each run draws one spawn radius between the rings; threats start on it at random bearings
+	for _, radius := range []float64{minSpawnRadius, maxSpawnRadius} {
+		ring := make([][2]float64, 0, spawnRingPoints+1)
+		for i := 0; i <= spawnRingPoints; i++ {
+			angle := 2 * math.Pi * float64(i%spawnRingPoints) / spawnRingPoints
+			lat, lon, _ := ecefToLatLonAlt(s.spawnPosition(radius, angle, 1))
+			ring = append(ring, [2]float64{lon, lat})
+		}
+		laydown.Features = append(laydown.Features, simulation.LaydownFeature{
+			Name:        fmt.Sprintf("Threat spawn %.0f km", radius/1000),
+			Kind:        laydownThreatSpawn,
+			Geometry:    simulation.GeometryPolygon,
+			Coordinates: ring,
+			Properties:  map[string]interface{}{"radius_m": radius},
+		})
+	}
+	return laydown
+}
@@ -0,0 +1,53 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+)
+
+func TestLaydown(t *testing.T) {
+	s := &DroneSwarmSimulation{config: SimulationConfig{
+		NumCounterUASSystems: 2,
+		BaseLocation:         Location{Lat: 40.044437, Lon: -76.306229, Alt: 100},
+		DefenseSites:         []DefenseSite{{Name: "North", EngagementType: EngagementTypeKinetic, DistanceM: 1200}, {Name: "Jammer", EngagementType: EngagementTypeEW, DistanceM: 400, BearingDeg: 180}},
+		Factions:             []Faction{{Name: "Red", Share: 1}, {Name: "Orange", Share: 1, ObjectiveBearing: 90, ObjectiveOffset: 1500}},
+		RunID:                "alice",
+	}}
+
+	counts := make(map[string]int)
+	for _, f := range s.Laydown().Features {
+		counts[f.Kind]++
+		if f.Kind != laydownCounterUAS {
+			continue
+		}
+		// Exported where deployEntities puts it
+		site := &s.config.DefenseSites[0]
+		if f.Name == "alice/Counter-UAS-Jammer" {
+			site = &s.config.DefenseSites[1]
+		} else if f.Name != "alice/Counter-UAS-North" {
+			t.Fatalf("unexpected system %s", f.Name)
+		}
+		lat, lon, alt := ecefToLatLonAlt(s.systemPosition(0, site))
+		if math.Abs(f.Coordinates[0][0]-lon) > 1e-9 || math.Abs(f.Coordinates[0][1]-lat) > 1e-9 || math.Abs(f.AltitudeM-alt) > 1e-6 {
+			t.Errorf("%s exported at %v %.1fm, deployed at [%f %f] %.1fm", f.Name, f.Coordinates[0], f.AltitudeM, lon, lat, alt)
+		}
+		if f.Properties["site"] != site.Name {
+			t.Errorf("%s has site %v, want %s", f.Name, f.Properties["site"], site.Name)
+		}
+	}
+	want := map[string]int{laydownDefendedAsset: 1, laydownObjective: 1, laydownCounterUAS: 2, laydownThreatSpawn: 2}
+	for kind, n := range want {
+		if counts[kind] != n {
+			t.Errorf("expected %d %s features, got %d", n, kind, counts[kind])
+		}
+	}
+
+	s.config.LaunchSites = []LaunchSite{{Name: "South", DistanceKm: 15, BearingDeg: 180, RatePerMin: 6}}
+	counts = make(map[string]int)
+	for _, f := range s.Laydown().Features {
+		counts[f.Kind]++
+	}
+	if counts[laydownLaunchSite] != 1 || counts[laydownThreatSpawn] != 0 {
+		t.Errorf("expected the launch site instead of spawn rings, got %v", counts)
+	}
+}
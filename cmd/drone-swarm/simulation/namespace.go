@@ -0,0 +1,18 @@
+package simulation
+
+import "github.com/picogrid/legion-simulations/pkg/simulation"
+
+// named puts a name the run gives Legion in the run's namespace, so runs with different
+// run_id values share an organization without colliding or cleaning up each other's
+// entities and feeds
+func (s *DroneSwarmSimulation) named(name string) string {
+	return simulation.Namespace(s.config.RunID, name)
+}
+
+// nextTrackNumber issues the next track number in the run's namespace
+func (s *DroneSwarmSimulation) nextTrackNumber() string {
+	if s.config.UseUniqueNames {
+		return s.named(generateUniqueTrackNumber())
+	}
+	return s.named(generateTrackNumber())
+}
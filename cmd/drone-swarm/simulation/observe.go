@@ -0,0 +1,123 @@
+package simulation
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// Observe implements simulation.Observable. Every event the AAR sees is passed on.
+func (s *DroneSwarmSimulation) Observe(fn func(simulation.Event)) {
+	s.observers = append(s.observers, fn)
+}
+
+// notifyObservers forwards a logged event to embedding programs
+func (s *DroneSwarmSimulation) notifyObservers(event reporting.SimulationEvent) {
+	if len(s.observers) == 0 && s.replayLog == nil {
+		return
+	}
+	out := simulation.Event{
+		Time:     event.Timestamp,
+		Type:     event.Type,
+		Severity: event.Severity,
+		Message:  event.Message,
+		Details:  event.Details,
+	}
+	for _, fn := range s.observers {
+		fn(out)
+	}
+	if s.replayLog != nil {
+		s.replayLog.Event(out)
+	}
+}
+
+// recordEntity adds an entity to the run's manifest
+func (s *DroneSwarmSimulation) recordEntity(id uuid.UUID, name, entityType string) {
+	s.manifest = append(s.manifest, simulation.EntityRecord{ID: id.String(), Name: name, Type: entityType})
+}
+
+// Termination conditions that end a run before its duration
+const (
+	terminationThreatsEliminated = "threats_eliminated"
+	terminationDefensesDestroyed = "defenses_destroyed"
+	terminationLeakageExceeded   = "leakage_exceeded" // Past an asset, wave or overall threshold
+)
+
+// result summarizes the run for Run's caller: the outcome, headline counts and AAR
+// metrics, the outputs written locally and the entities created in Legion
+func (s *DroneSwarmSimulation) result() *simulation.Result {
+	s.stats.mu.RLock()
+	summary := s.stats.SimulationOutcome
+	result := simulation.NewResult(simulation.OutcomePartial, summary)
+	result.Termination = s.stats.Termination
+	result.Seed = s.seed
+	result.Stats = map[string]float64{
+		"total_engagements":      float64(s.stats.TotalEngagements),
+		"successful_engagements": float64(s.stats.SuccessfulEngagements),
+		"uas_eliminated":         float64(s.stats.UASEliminated),
+		"uas_penetrated":         float64(s.stats.UASPenetrated),
+		"counter_uas_losses":     float64(s.stats.CounterUASLosses),
+		"leakage_consequence":    s.stats.Leakage.Consequence,
+		"hazards":                float64(s.stats.Hazards),
+		"collateral_exposures":   float64(s.stats.CollateralExposures),
+		"ground_units_hit":       float64(s.stats.GroundUnitsHit),
+		"personnel_at_risk":      float64(s.stats.PersonnelAtRisk),
+	}
+	// Fraction of threats that reached the defended area, for gating CI on defensive performance
+	if threats := s.plannedThreats(); threats > 0 {
+		penetrated, _ := s.runLeakers()
+		result.Stats["penetration"] = float64(penetrated) / float64(threats)
+	}
+	s.stats.mu.RUnlock()
+
+	switch {
+	case strings.HasPrefix(summary, "SUCCESS"):
+		result.Outcome = simulation.OutcomeSuccess
+	case strings.HasPrefix(summary, "FAILURE"):
+		result.Outcome = simulation.OutcomeFailure
+	case s.stopped:
+		result.Outcome = simulation.OutcomeStopped
+		result.Termination = simulation.TerminationStopped
+		result.Summary = "STOPPED - Run ended before the scenario finished"
+	default:
+		result.Termination = simulation.TerminationDuration
+		result.Summary = fmt.Sprintf("PARTIAL - Time expired with %d threats remaining", len(s.getActiveThreats()))
+	}
+
+	if s.simLogger != nil {
+		for name, metric := range s.simLogger.GetMetrics() {
+			if _, ok := result.Stats[name]; !ok {
+				result.Stats[name] = metric.Value
+			}
+		}
+	}
+	result.Artifacts = append(result.Artifacts, s.artifacts...)
+	result.Entities = append(result.Entities, s.liveManifest()...)
+	return result
+}
+
+// liveManifest is the manifest less the tracks collectStaleTracks already deleted, so
+// the entities reported as left in Legion are the ones still there
+func (s *DroneSwarmSimulation) liveManifest() []simulation.EntityRecord {
+	archived := make(map[string]bool)
+	s.mu.RLock()
+	for id, threat := range s.uasThreats {
+		threat.mu.RLock()
+		if threat.Archived {
+			archived[id.String()] = true
+		}
+		threat.mu.RUnlock()
+	}
+	s.mu.RUnlock()
+
+	live := make([]simulation.EntityRecord, 0, len(s.manifest))
+	for _, record := range s.manifest {
+		if !archived[record.ID] {
+			live = append(live, record)
+		}
+	}
+	return live
+}
@@ -0,0 +1,95 @@
+package simulation
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+func TestResultOutcomeAndManifest(t *testing.T) {
+	tests := []struct {
+		name            string
+		summary         string
+		termination     string
+		stopped         bool
+		wantOutcome     string
+		wantTermination string
+		wantSummary     string
+	}{
+		{
+			name:            "success",
+			summary:         "SUCCESS - All threats neutralized",
+			termination:     terminationThreatsEliminated,
+			wantOutcome:     simulation.OutcomeSuccess,
+			wantTermination: terminationThreatsEliminated,
+			wantSummary:     "SUCCESS - All threats neutralized",
+		},
+		{
+			name:            "failure",
+			summary:         "FAILURE - 40% of threats leaked",
+			termination:     terminationLeakageExceeded,
+			wantOutcome:     simulation.OutcomeFailure,
+			wantTermination: terminationLeakageExceeded,
+			wantSummary:     "FAILURE - 40% of threats leaked",
+		},
+		{
+			name:            "stopped",
+			stopped:         true,
+			wantOutcome:     simulation.OutcomeStopped,
+			wantTermination: simulation.TerminationStopped,
+			wantSummary:     "STOPPED - Run ended before the scenario finished",
+		},
+		{
+			name:            "duration elapsed",
+			wantOutcome:     simulation.OutcomePartial,
+			wantTermination: simulation.TerminationDuration,
+			wantSummary:     "PARTIAL - Time expired with 1 threats remaining",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &DroneSwarmSimulation{
+				config:     SimulationConfig{NumUASThreats: 3},
+				uasThreats: make(map[uuid.UUID]*UASThreat),
+				stopped:    tt.stopped,
+			}
+			s.stats.Leakage = newLeakageScore()
+			s.stats.SimulationOutcome = tt.summary
+			s.stats.Termination = tt.termination
+
+			active := &UASThreat{ID: uuid.New(), TrackNumber: "TK-0001", Classification: TrackStatusHostile}
+			destroyed := &UASThreat{ID: uuid.New(), TrackNumber: "TK-0002", Classification: TrackStatusDestroyed}
+			archived := &UASThreat{ID: uuid.New(), TrackNumber: "TK-0003", Classification: TrackStatusDestroyed, Archived: true}
+			for _, threat := range []*UASThreat{active, destroyed, archived} {
+				s.uasThreats[threat.ID] = threat
+				s.recordEntity(threat.ID, threat.TrackNumber, EntityTypeUAS)
+			}
+			base := uuid.New()
+			s.recordEntity(base, "Base", "Base")
+
+			result := s.result()
+			if result.Outcome != tt.wantOutcome {
+				t.Errorf("expected outcome %s, got %s", tt.wantOutcome, result.Outcome)
+			}
+			if result.Termination != tt.wantTermination {
+				t.Errorf("expected termination %s, got %s", tt.wantTermination, result.Termination)
+			}
+			if result.Summary != tt.wantSummary {
+				t.Errorf("expected summary %q, got %q", tt.wantSummary, result.Summary)
+			}
+
+			want := []string{active.ID.String(), destroyed.ID.String(), base.String()}
+			if len(result.Entities) != len(want) {
+				t.Fatalf("expected %d entities left in Legion, got %+v", len(want), result.Entities)
+			}
+			for i, record := range result.Entities {
+				if record.ID != want[i] {
+					t.Errorf("entity %d: expected %s, got %s (%s)", i, want[i], record.ID, record.Name)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,121 @@
+package simulation
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// overrideState follows Counter-UAS systems in Legion for status changes made outside
+// the simulation, by an operator or another system
+type overrideState struct {
+	sub  *client.EntitySubscription
+	held map[uuid.UUID]bool // Systems an override has taken offline
+}
+
+// startOverrides subscribes to this run's Counter-UAS systems in Legion. Changes are
+// applied once per tick by applyOverrides.
+func (s *DroneSwarmSimulation) startOverrides(ctx context.Context) error {
+	if s.config.OverridePollInterval <= 0 || len(s.counterUASSystems) == 0 {
+		return nil
+	}
+
+	orgID, err := uuid.Parse(s.config.OrganizationID)
+	if err != nil {
+		return err
+	}
+	ids := make([]uuid.UUID, 0, len(s.counterUASSystems))
+	for _, system := range s.systemsByName() {
+		ids = append(ids, system.ID)
+	}
+
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	s.overrides = overrideState{
+		sub: s.legionClient.SubscribeEntities(orgCtx, &models.SearchEntitiesRequest{
+			OrganizationID: &orgID,
+			Filters:        &models.SearchFilters{EntityIDs: ids},
+		}, s.config.OverridePollInterval),
+		held: make(map[uuid.UUID]bool),
+	}
+	logger.Infof("%s Watching %d Counter-UAS systems in Legion for status overrides every %s",
+		logger.IconNetwork, len(ids), s.config.OverridePollInterval)
+	return nil
+}
+
+// stopOverrides ends the subscription
+func (s *DroneSwarmSimulation) stopOverrides() {
+	if s.overrides.sub != nil {
+		s.overrides.sub.Close()
+	}
+}
+
+// applyOverrides acts on system changes seen in Legion since the last tick. A system set
+// OFFLINE is taken out of the fight as if it had failed; setting any other status brings
+// it back. The run's own status writes come back too and are ignored.
+func (s *DroneSwarmSimulation) applyOverrides() {
+	if s.overrides.sub == nil {
+		return
+	}
+	for {
+		select {
+		case entity, ok := <-s.overrides.sub.Changes():
+			if !ok {
+				return
+			}
+			s.applyOverride(entity)
+		default:
+			return
+		}
+	}
+}
+
+// applyOverride takes a system offline or brings it back to match its status in Legion
+func (s *DroneSwarmSimulation) applyOverride(entity models.EntityResponse) {
+	system, ok := s.counterUASSystems[entity.ID]
+	if !ok {
+		return
+	}
+	held := s.overrides.held[entity.ID]
+
+	system.mu.Lock()
+	current := system.Status
+	system.mu.Unlock()
+
+	if entity.Status == CounterUASStatusOffline {
+		// Offline already, or the echo of the run's own write
+		sent, _ := s.updateBuffer.Sent(entity.ID)
+		if held || current == CounterUASStatusOffline || sent.LastStatus == CounterUASStatusOffline {
+			return
+		}
+		s.overrides.held[entity.ID] = true
+		system.mu.Lock()
+		system.Status = CounterUASStatusOffline
+		system.DataLinkStatus = "OFFLINE"
+		system.EngagedTarget = nil
+		system.CurrentTargets = nil
+		system.mu.Unlock()
+		engagementLog.Warnf("🛑 %s (%s) taken offline in Legion", system.Callsign, system.Name)
+		s.simLogger.LogSystemOverride(system.ID, system.Name, true, entity.Status)
+		s.updateBuffer.QueueStatusUpdate(system.ID, system.Status)
+		return
+	}
+
+	if !held {
+		return
+	}
+	delete(s.overrides.held, entity.ID)
+	system.mu.Lock()
+	system.Status = CounterUASStatusIdle
+	if system.EngagementType == EngagementTypeKinetic && system.AmmoRemaining == 0 {
+		system.Status = CounterUASStatusOffline
+	}
+	system.DataLinkStatus = "ONLINE"
+	system.mu.Unlock()
+	logger.Infof("🔧 %s (%s) put back in service in Legion (%s)", system.Callsign, system.Name, entity.Status)
+	s.simLogger.LogSystemOverride(system.ID, system.Name, false, entity.Status)
+	s.updateBuffer.QueueStatusUpdate(system.ID, system.Status)
+}
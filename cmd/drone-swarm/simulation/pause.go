@@ -0,0 +1,157 @@
+package simulation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/spectate"
+)
+
+// DefaultPauseHeartbeat is how often a paused run resends entity positions and statuses
+const DefaultPauseHeartbeat = 10 * time.Second
+
+// pauseState is the operator's pause request and, on the loop's side, the pause in effect
+type pauseState struct {
+	requested bool
+	source    string // Who last paused or resumed the run
+	mu        sync.Mutex
+
+	// Owned by the simulation loop
+	since     time.Time // When the pause in effect began (zero when running)
+	total     time.Duration
+	count     int
+	lastBeat  time.Time
+	heartbeat int // Updates resent while paused
+}
+
+// Pause implements simulation.Pausable. The loop stops advancing on its next tick.
+func (s *DroneSwarmSimulation) Pause(source string) bool {
+	return s.requestPause(true, source)
+}
+
+// Resume implements simulation.Pausable
+func (s *DroneSwarmSimulation) Resume(source string) bool {
+	return s.requestPause(false, source)
+}
+
+// Paused implements simulation.Pausable
+func (s *DroneSwarmSimulation) Paused() bool {
+	s.pause.mu.Lock()
+	defer s.pause.mu.Unlock()
+	return s.pause.requested
+}
+
+// requestPause records a pause or resume for the loop to act on
+func (s *DroneSwarmSimulation) requestPause(paused bool, source string) bool {
+	s.pause.mu.Lock()
+	defer s.pause.mu.Unlock()
+	if s.pause.requested == paused {
+		return false
+	}
+	s.pause.requested = paused
+	s.pause.source = source
+	return true
+}
+
+// holdPaused is called by the loop on every tick. It starts and ends pauses as
+// requested and, while paused, heartbeats entities to Legion; it reports whether the
+// tick should be skipped. Ending a pause moves the scenario start forward by its length,
+// so acts, launches and the run's duration carry on where they stopped.
+func (s *DroneSwarmSimulation) holdPaused() bool {
+	s.pause.mu.Lock()
+	paused, source := s.pause.requested, s.pause.source
+	s.pause.mu.Unlock()
+	now := time.Now()
+
+	switch {
+	case paused && s.pause.since.IsZero():
+		s.pause.since = now
+		s.pause.count++
+		s.setPhase(runPhasePaused)
+		logger.Warnf("⏸  Run paused by %s; entities are kept live in Legion every %s", source, s.config.PauseHeartbeat)
+		s.simLogger.LogPause(true, 0, source)
+		s.publishSpectatorSnapshot(spectate.StatusPaused)
+		s.heartbeatEntities(now)
+
+	case paused && now.Sub(s.pause.lastBeat) >= s.config.PauseHeartbeat:
+		s.heartbeatEntities(now)
+
+	case !paused && !s.pause.since.IsZero():
+		held := now.Sub(s.pause.since)
+		s.pause.total += held
+		s.pause.since = time.Time{}
+		s.scenarioStart = s.scenarioStart.Add(held)
+		logger.Infof("▶  Run resumed by %s after %s", source, held.Round(time.Second))
+		s.simLogger.LogPause(false, held, source)
+	}
+	return paused
+}
+
+// heartbeatEntities queues every live entity's last sent position and status again, so
+// Legion sees fresh reports of an unchanged picture
+func (s *DroneSwarmSimulation) heartbeatEntities(now time.Time) {
+	s.pause.lastBeat = now
+	if s.updateBuffer == nil {
+		return
+	}
+
+	s.mu.RLock()
+	ids := make([]uuid.UUID, 0, len(s.counterUASSystems)+len(s.uasThreats))
+	for id := range s.counterUASSystems {
+		ids = append(ids, id)
+	}
+	for id, threat := range s.uasThreats {
+		threat.mu.RLock()
+		archived := threat.Archived
+		threat.mu.RUnlock()
+		if !archived {
+			ids = append(ids, id)
+		}
+	}
+	s.mu.RUnlock()
+
+	for _, id := range ids {
+		sent, ok := s.updateBuffer.Sent(id)
+		if !ok {
+			continue
+		}
+		if sent.LastPosition != nil {
+			position := &models.GeomPoint{
+				Type:        sent.LastPosition.Type,
+				Coordinates: append([]float64(nil), sent.LastPosition.Coordinates...),
+			}
+			s.updateBuffer.QueuePositionUpdate(id, position)
+			s.pause.heartbeat++
+		}
+		if sent.LastStatus != "" {
+			s.updateBuffer.QueueStatusUpdate(id, sent.LastStatus)
+		}
+	}
+}
+
+// pausedFor returns the total time the run has spent paused, including a pause still
+// in effect
+func (s *DroneSwarmSimulation) pausedFor() time.Duration {
+	total := s.pause.total
+	if !s.pause.since.IsZero() {
+		total += time.Since(s.pause.since)
+	}
+	return total
+}
+
+// recordPauseMetrics records how often and for how long the run was paused
+func (s *DroneSwarmSimulation) recordPauseMetrics() {
+	if s.pause.count == 0 {
+		return
+	}
+	paused := s.pausedFor()
+	s.simLogger.UpdateMetric(reporting.MetricPauses, float64(s.pause.count), "count")
+	s.simLogger.UpdateMetric(reporting.MetricPausedTime, float64(paused.Milliseconds()), "ms")
+	logger.Infof("Paused %d times for %s; %d positions resent while paused",
+		s.pause.count, paused.Round(time.Second), s.pause.heartbeat)
+}
@@ -0,0 +1,41 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+)
+
+func TestPauseHoldsScenarioClock(t *testing.T) {
+	start := time.Now().Add(-time.Minute)
+	s := &DroneSwarmSimulation{
+		config:        SimulationConfig{PauseHeartbeat: DefaultPauseHeartbeat},
+		simLogger:     reporting.NewSimulationLogger("test"),
+		scenarioStart: start,
+	}
+
+	if s.holdPaused() {
+		t.Fatal("expected a running tick not to be held")
+	}
+	if !s.Pause("test") || s.Pause("test") {
+		t.Error("expected only the first Pause to change the state")
+	}
+	if !s.holdPaused() || s.Status().Phase != runPhasePaused {
+		t.Fatalf("expected a paused tick to be held, phase %q", s.Status().Phase)
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	if !s.Resume("test") {
+		t.Error("expected Resume to change the state")
+	}
+	if s.holdPaused() {
+		t.Fatal("expected the tick after resuming to run")
+	}
+	if held := s.scenarioStart.Sub(start); held < 20*time.Millisecond {
+		t.Errorf("expected the scenario start to move past the pause, moved %s", held)
+	}
+	if s.pause.count != 1 || s.pausedFor() != s.scenarioStart.Sub(start) {
+		t.Errorf("expected one pause of %s, got %d of %s", s.scenarioStart.Sub(start), s.pause.count, s.pausedFor())
+	}
+}
@@ -0,0 +1,113 @@
+package simulation
+
+import (
+	"math/rand"
+)
+
+// Threat payload types
+const (
+	PayloadFPVWarhead    = "fpv_warhead"    // One-way attack, detonates on the target
+	PayloadMortarDropper = "mortar_dropper" // Drops munitions over the target
+	PayloadISR           = "isr"            // Reconnaissance only
+	PayloadEW            = "ew"             // Airborne jammer
+)
+
+// PayloadProfile describes how common a payload is and what it does once it gets through
+type PayloadProfile struct {
+	Weight      float64 // Relative share of a raid
+	Lethality   float64 // Consequence of one penetration (1.0 = warhead on the asset)
+	JamTicks    int     // Ticks defenders near the base are jammed after it arrives
+	BlastRadius float64 // Meters around the impact ground units are hit (0 harms no one)
+}
+
+// payloadEntry is one payload type in a raid mix
+type payloadEntry struct {
+	Type    string
+	Profile PayloadProfile
+}
+
+// payloadCatalog is the default raid mix. Order matters for weighted selection.
+var payloadCatalog = []payloadEntry{
+	{PayloadFPVWarhead, PayloadProfile{Weight: 0.40, Lethality: 1.0, BlastRadius: 15}},
+	{PayloadMortarDropper, PayloadProfile{Weight: 0.20, Lethality: 0.6, BlastRadius: 25}},
+	{PayloadISR, PayloadProfile{Weight: 0.30, Lethality: 0.1}},
+	{PayloadEW, PayloadProfile{Weight: 0.10, Lethality: 0.3, JamTicks: 5}},
+}
+
+// ewJamRadiusKm is how far from the base an EW payload degrades defender datalinks
+const ewJamRadiusKm = 6.0
+
+// randomPayloadType picks a payload according to the default catalog weights
+func randomPayloadType(rng *rand.Rand) string {
+	return randomPayloadFrom(payloadCatalog, rng)
+}
+
+// randomPayloadFrom picks a payload according to a catalog's weights
+func randomPayloadFrom(catalog []payloadEntry, rng *rand.Rand) string {
+	total := 0.0
+	for _, entry := range catalog {
+		total += entry.Profile.Weight
+	}
+
+	r := rng.Float64() * total
+	for _, entry := range catalog {
+		if r < entry.Profile.Weight {
+			return entry.Type
+		}
+		r -= entry.Profile.Weight
+	}
+	return catalog[len(catalog)-1].Type
+}
+
+// payloads returns this run's raid mix: the data pack's if it has one, else the default
+func (s *DroneSwarmSimulation) payloads() []payloadEntry {
+	if s.dataPack != nil && len(s.dataPack.payloads) > 0 {
+		return s.dataPack.payloads
+	}
+	return payloadCatalog
+}
+
+// payloadProfile returns the profile for a payload type. Unknown types are scored as ISR.
+func (s *DroneSwarmSimulation) payloadProfile(payload string) PayloadProfile {
+	for _, entry := range s.payloads() {
+		if entry.Type == payload {
+			return entry.Profile
+		}
+	}
+	return PayloadProfile{Lethality: 0.1}
+}
+
+// applyPayloadEffects resolves what a threat that reached the base does there.
+// Returns the consequence of the penetration and the callsigns of jammed defenders.
+func (s *DroneSwarmSimulation) applyPayloadEffects(threat *UASThreat) (float64, []string) {
+	profile := s.payloadProfile(threat.ActualCapabilities.PayloadType)
+	if profile.JamTicks == 0 {
+		return profile.Lethality, nil
+	}
+
+	baseX, baseY, baseZ := latLonAltToECEF(s.config.BaseLocation.Lat, s.config.BaseLocation.Lon, s.config.BaseLocation.Alt)
+	var jammed []string
+	for _, system := range s.counterUASSystems {
+		dx := system.Position.Coordinates[0] - baseX
+		dy := system.Position.Coordinates[1] - baseY
+		dz := system.Position.Coordinates[2] - baseZ
+		if (dx*dx+dy*dy+dz*dz)/1e6 > ewJamRadiusKm*ewJamRadiusKm {
+			continue
+		}
+
+		system.mu.Lock()
+		if system.Status != CounterUASStatusOffline {
+			if system.JammedTicks == 0 && system.DataLinkStatus == "ONLINE" {
+				system.DataLinkStatus = "DEGRADED"
+			}
+			system.JammedTicks += profile.JamTicks
+			system.CooldownRemaining += profile.JamTicks
+			if system.Status == CounterUASStatusIdle {
+				system.Status = CounterUASStatusCooldown
+			}
+			jammed = append(jammed, system.Callsign)
+		}
+		system.mu.Unlock()
+	}
+	return profile.Lethality, jammed
+}
@@ -0,0 +1,36 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+)
+
+func TestRandomPayloadTypeFollowsWeights(t *testing.T) {
+	counts := make(map[string]int)
+	const draws = 20000
+	for i := 0; i < draws; i++ {
+		counts[randomPayloadType(unseeded)]++
+	}
+
+	for _, entry := range payloadCatalog {
+		share := float64(counts[entry.Type]) / draws
+		if math.Abs(share-entry.Profile.Weight) > 0.03 {
+			t.Errorf("%s: expected share ~%.2f, got %.3f", entry.Type, entry.Profile.Weight, share)
+		}
+	}
+}
+
+func TestLeakageConsequenceWeightsPayloads(t *testing.T) {
+	s := &DroneSwarmSimulation{}
+	for _, payload := range []string{PayloadFPVWarhead, PayloadISR, PayloadISR} {
+		threat := &UASThreat{ActualCapabilities: SimulatedCapabilities{PayloadType: payload, WaveNumber: 1}}
+		s.recordLeaker(threat, BaseAssetName)
+	}
+
+	if got := s.stats.Leakage.Consequence; math.Abs(got-1.2) > 1e-9 {
+		t.Errorf("expected consequence 1.2, got %.2f", got)
+	}
+	if s.stats.Leakage.ByPayload[PayloadISR] != 2 {
+		t.Errorf("expected 2 ISR leakers, got %d", s.stats.Leakage.ByPayload[PayloadISR])
+	}
+}
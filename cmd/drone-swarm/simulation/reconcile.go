@@ -0,0 +1,322 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// Reconcile strategies for entities left in Legion by earlier runs
+const (
+	ReconcileAdopt  = "adopt"  // Reuse entities matching the planned force, repair them, delete the rest
+	ReconcileDelete = "delete" // Delete everything matching the naming patterns and recreate
+)
+
+// DefaultReconcileStrategy is used when reconcile_strategy is not set
+const DefaultReconcileStrategy = ReconcileAdopt
+
+// runEntityPrefixes are the name prefixes of entities this simulation creates
+var runEntityPrefixes = []string{
+	"Counter-UAS-",
+	"UAS-W",
+	"TK-",
+	threatBoardName,
+	timeMarkerName,
+	datalinkName,
+	hazardEntityPrefix,
+	groundUnitEntityPrefix,
+}
+
+// runFeedPatterns match the names of feed definitions this simulation creates
+var runFeedPatterns = []string{
+	"cuas_health_telemetry_Counter-UAS-",
+	"cuas_health_telemetry_DEFENDER-",
+	"cuas_health_telemetry_GUARDIAN-",
+	"cuas_health_telemetry_HAWK-",
+	"cuas_health_telemetry_SENTRY-",
+	threatBoardFeedBase,
+	timeMarkerFeedBase,
+	datalinkFeedBase,
+}
+
+// CleanupPatterns implements simulation.Cleaner
+func (s *DroneSwarmSimulation) CleanupPatterns() simulation.CleanupPatterns {
+	return simulation.CleanupPatterns{
+		EntityPrefixes: runEntityPrefixes,
+		FeedPatterns:   runFeedPatterns,
+	}
+}
+
+// entityInventory caches entities found in Legion at startup, keyed by name
+type entityInventory struct {
+	byName  map[string]models.EntityResponse
+	surplus []models.EntityResponse // Duplicates that can never be adopted
+}
+
+// newEntityInventory indexes existing entities by name. When several share a name
+// the most recently updated one is kept and the others are marked surplus.
+func newEntityInventory(entities []models.EntityResponse) *entityInventory {
+	inv := &entityInventory{byName: make(map[string]models.EntityResponse)}
+	for _, entity := range entities {
+		if entity.DeletedAt != nil {
+			continue
+		}
+		current, ok := inv.byName[entity.Name]
+		switch {
+		case !ok:
+			inv.byName[entity.Name] = entity
+		case entity.UpdatedAt.After(current.UpdatedAt):
+			inv.surplus = append(inv.surplus, current)
+			inv.byName[entity.Name] = entity
+		default:
+			inv.surplus = append(inv.surplus, entity)
+		}
+	}
+	return inv
+}
+
+// take removes and returns the cached entity with the given name
+func (inv *entityInventory) take(name string) (models.EntityResponse, bool) {
+	if inv == nil {
+		return models.EntityResponse{}, false
+	}
+	entity, ok := inv.byName[name]
+	if ok {
+		delete(inv.byName, name)
+	}
+	return entity, ok
+}
+
+// remaining returns every entity that was not adopted
+func (inv *entityInventory) remaining() []models.EntityResponse {
+	if inv == nil {
+		return nil
+	}
+	entities := append([]models.EntityResponse{}, inv.surplus...)
+	for _, entity := range inv.byName {
+		entities = append(entities, entity)
+	}
+	return entities
+}
+
+// reconcileStats counts what startup reconciliation did
+type reconcileStats struct {
+	Adopted  int
+	Repaired int
+	Created  int
+	Removed  int
+}
+
+// loadExistingEntities caches entities left by earlier runs so createEntities can adopt them
+func (s *DroneSwarmSimulation) loadExistingEntities(ctx context.Context) error {
+	logger.Info("Reconciling existing entities...")
+
+	// Track numbers restart so a rerun maps onto the previous run's tracks
+	atomic.StoreUint32(&trackNumberCounter, 0)
+
+	orgID, err := uuid.Parse(s.config.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("invalid organization ID: %w", err)
+	}
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+
+	var found []models.EntityResponse
+	for _, prefix := range runEntityPrefixes {
+		prefix = s.named(prefix)
+		err := s.legionClient.ForEachEntity(orgCtx, &models.SearchEntitiesRequest{
+			OrganizationID: &orgID,
+			Filters:        &models.SearchFilters{Name: prefix},
+		}, func(entity models.EntityResponse) bool {
+			if runID, _ := simulation.SplitNamespace(entity.Name); runID == s.config.RunID && strings.HasPrefix(entity.Name, prefix) {
+				found = append(found, entity)
+			}
+			return true
+		})
+		if err != nil {
+			return fmt.Errorf("failed to search for entities with prefix %s: %w", prefix, err)
+		}
+	}
+
+	s.existingEntities = newEntityInventory(found)
+	s.reconcileStats = reconcileStats{}
+	logger.Infof("Found %d existing entities from earlier runs", len(found))
+	return nil
+}
+
+// idempotent keys requests made with ctx by the run ID and parts, so a create or ingest
+// retried after a timeout is applied once
+func (s *DroneSwarmSimulation) idempotent(ctx context.Context, parts ...string) context.Context {
+	return client.WithIdempotencyKey(ctx, client.IdempotencyKey(append([]string{s.runID}, parts...)...))
+}
+
+// createOrAdoptEntity reuses a cached entity with the requested name, repairing any
+// fields that drifted, and only creates a new entity when none exists
+func (s *DroneSwarmSimulation) createOrAdoptEntity(ctx context.Context, req *models.CreateEntityRequest) (*models.EntityResponse, error) {
+	if entity, adopted, err := s.adoptEntity(ctx, req); adopted || err != nil {
+		return entity, err
+	}
+
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	entity, err := s.legionClient.CreateEntity(s.idempotent(orgCtx, "entity", *req.Name), req)
+	if err != nil {
+		return nil, err
+	}
+	s.recordCreated(entity, req)
+	return entity, nil
+}
+
+// createOrAdoptEntities does createOrAdoptEntity for many entities, adopting cached ones
+// first and then creating the rest concurrently. Results are in request order.
+func (s *DroneSwarmSimulation) createOrAdoptEntities(ctx context.Context, reqs []*models.CreateEntityRequest) ([]*models.EntityResponse, error) {
+	entities := make([]*models.EntityResponse, len(reqs))
+	var pending []*models.CreateEntityRequest
+	var pendingIdx []int
+	for i, req := range reqs {
+		entity, adopted, err := s.adoptEntity(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		if adopted {
+			entities[i] = entity
+			continue
+		}
+		pending = append(pending, req)
+		pendingIdx = append(pendingIdx, i)
+	}
+	if len(pending) == 0 {
+		return entities, nil
+	}
+
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	created, err := s.legionClient.CreateEntities(s.idempotent(orgCtx, "entity"), pending, 0)
+	// Record whatever was created, even on failure, so it is cleaned up with the run
+	for j, entity := range created {
+		if entity != nil {
+			s.recordCreated(entity, pending[j])
+			entities[pendingIdx[j]] = entity
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+	return entities, nil
+}
+
+// adoptEntity takes a cached entity with the requested name, repairing any fields that
+// drifted. It reports false when there is none to adopt.
+func (s *DroneSwarmSimulation) adoptEntity(ctx context.Context, req *models.CreateEntityRequest) (*models.EntityResponse, bool, error) {
+	existing, ok := s.existingEntities.take(*req.Name)
+	if !ok {
+		return nil, false, nil
+	}
+
+	patch := repairPatch(existing, req)
+	if patch.IsEmpty() {
+		s.reconcileStats.Adopted++
+		logger.Debugf("Adopted %s (%s)", existing.Name, existing.ID)
+		s.recordEntity(existing.ID, existing.Name, *req.Type)
+		s.recordAdopted(&existing, req)
+		return &existing, true, nil
+	}
+
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	entity, err := s.legionClient.PatchEntity(orgCtx, existing.ID.String(), patch)
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to repair existing entity %s: %w", existing.Name, err)
+	}
+	s.reconcileStats.Adopted++
+	s.reconcileStats.Repaired++
+	logger.Debugf("Adopted and repaired %s (%s)", existing.Name, existing.ID)
+	s.recordEntity(entity.ID, existing.Name, *req.Type)
+	s.recordAdopted(entity, req)
+	return entity, true, nil
+}
+
+// recordCreated counts and records an entity created rather than adopted
+func (s *DroneSwarmSimulation) recordCreated(entity *models.EntityResponse, req *models.CreateEntityRequest) {
+	if s.existingEntities != nil {
+		s.reconcileStats.Created++
+	}
+	s.recordEntity(entity.ID, *req.Name, *req.Type)
+}
+
+// repairPatch returns the changes needed to bring an existing entity in line with a
+// create request. Metadata is always rewritten since it describes this run.
+func repairPatch(existing models.EntityResponse, req *models.CreateEntityRequest) *models.EntityPatch {
+	patch := &models.EntityPatch{Metadata: req.Metadata}
+	if req.Category != nil && *req.Category != existing.Category {
+		patch.Category = req.Category
+	}
+	if req.Type != nil && *req.Type != existing.Type {
+		patch.Type = req.Type
+	}
+	if req.Status != nil && *req.Status != existing.Status {
+		patch.Status = req.Status
+	}
+	if req.Affiliation != "" && req.Affiliation != existing.Affiliation {
+		affiliation := req.Affiliation
+		patch.Affiliation = &affiliation
+	}
+	return patch
+}
+
+// removeSurplusEntities deletes cached entities the planned force did not adopt,
+// together with any feed definitions attached to them
+func (s *DroneSwarmSimulation) removeSurplusEntities(ctx context.Context) {
+	if s.existingEntities == nil {
+		return
+	}
+
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	for _, entity := range s.existingEntities.remaining() {
+		feeds, err := s.legionClient.SearchFeedDefinitions(orgCtx, &models.FeedDefinitionSearchRequest{
+			EntityID: entity.ID,
+			Category: models.MessageCategoryMESSAGE,
+		})
+		if err == nil && feeds != nil {
+			for _, feed := range feeds.Results {
+				if err := s.legionClient.DeleteFeedDefinition(orgCtx, feed.ID.String()); err != nil {
+					logger.Debugf("Failed to delete feed %s: %v", feed.FeedName, err)
+				}
+			}
+		}
+
+		if err := s.legionClient.DeleteEntity(orgCtx, entity.ID.String()); err != nil {
+			logger.Debugf("Failed to delete surplus entity %s (%s): %v", entity.Name, entity.ID, err)
+			continue
+		}
+		s.reconcileStats.Removed++
+		logger.Debugf("Deleted surplus entity: %s", entity.Name)
+	}
+	s.existingEntities = nil
+
+	stats := s.reconcileStats
+	logger.Infof("Reconciled entities: %d adopted (%d repaired), %d created, %d removed",
+		stats.Adopted, stats.Repaired, stats.Created, stats.Removed)
+}
+
+// findEntityFeed returns the first feed on an entity whose name contains the marker
+func (s *DroneSwarmSimulation) findEntityFeed(ctx context.Context, entityID uuid.UUID, marker string) (uuid.UUID, bool) {
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	result, err := s.legionClient.SearchFeedDefinitions(orgCtx, &models.FeedDefinitionSearchRequest{
+		EntityID: entityID,
+		Category: models.MessageCategoryMESSAGE,
+	})
+	if err != nil || result == nil {
+		return uuid.Nil, false
+	}
+	for _, feed := range result.Results {
+		if strings.Contains(feed.FeedName, marker) {
+			return feed.ID, true
+		}
+	}
+	return uuid.Nil, false
+}
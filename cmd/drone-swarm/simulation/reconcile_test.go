@@ -0,0 +1,73 @@
+package simulation
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestEntityInventoryAdoptsLatestAndReportsSurplus(t *testing.T) {
+	now := time.Now()
+	stale := models.EntityResponse{ID: uuid.New(), Name: "TK-0001", UpdatedAt: now.Add(-time.Hour)}
+	latest := models.EntityResponse{ID: uuid.New(), Name: "TK-0001", UpdatedAt: now}
+	extra := models.EntityResponse{ID: uuid.New(), Name: "TK-0002", UpdatedAt: now}
+
+	inv := newEntityInventory([]models.EntityResponse{stale, latest, extra})
+
+	adopted, ok := inv.take("TK-0001")
+	if !ok || adopted.ID != latest.ID {
+		t.Fatalf("expected the most recently updated TK-0001 to be adopted, got %+v", adopted)
+	}
+	if _, ok := inv.take("TK-0001"); ok {
+		t.Fatal("an entity must only be adopted once")
+	}
+
+	remaining := inv.remaining()
+	if len(remaining) != 2 {
+		t.Fatalf("expected 2 surplus entities, got %d", len(remaining))
+	}
+	for _, entity := range remaining {
+		if entity.ID == latest.ID {
+			t.Fatal("adopted entity reported as surplus")
+		}
+	}
+}
+
+func TestRepairPatchOnlyChangesDriftedFields(t *testing.T) {
+	status := "PENDING"
+	entityType := EntityTypeUAS
+	category := models.CategoryTRACK
+	metadata := json.RawMessage(`{"wave":1}`)
+	req := &models.CreateEntityRequest{
+		Category:    &category,
+		Type:        &entityType,
+		Status:      &status,
+		Affiliation: models.AffiliationUNKNOWN,
+		Metadata:    &metadata,
+	}
+
+	existing := models.EntityResponse{
+		Category:    models.CategoryTRACK,
+		Type:        EntityTypeUAS,
+		Status:      "DESTROYED",
+		Affiliation: models.AffiliationHOSTILE,
+	}
+
+	patch := repairPatch(existing, req)
+	if patch.Category != nil || patch.Type != nil {
+		t.Errorf("unchanged fields should not be patched: %+v", patch)
+	}
+	if patch.Status == nil || *patch.Status != status {
+		t.Errorf("expected status repaired to %s", status)
+	}
+	if patch.Affiliation == nil || *patch.Affiliation != models.AffiliationUNKNOWN {
+		t.Errorf("expected affiliation repaired to UNKNOWN")
+	}
+	if patch.Metadata == nil {
+		t.Errorf("expected metadata rewritten for the new run")
+	}
+}
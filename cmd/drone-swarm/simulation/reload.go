@@ -0,0 +1,320 @@
+package simulation
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// reloadTimeout bounds how long a reload waits for the simulation loop to pick it up
+const reloadTimeout = 5 * time.Second
+
+// reloadRequest hands a reload to the simulation loop so the config is never
+// changed mid-tick
+type reloadRequest struct {
+	params map[string]interface{}
+	source string
+	reply  chan simulation.ReloadResult
+}
+
+// reloadable is a parameter that can change without restarting the run.
+// get returns the current value for display; set validates and applies a new one.
+type reloadable struct {
+	get func(s *DroneSwarmSimulation) interface{}
+	set func(s *DroneSwarmSimulation, value interface{}, source string) error
+}
+
+// reloadables covers logging, behavior weights and alert thresholds. Everything else
+// shapes the forces or the Legion entities already created and needs a restart.
+var reloadables = map[string]reloadable{
+	"log_level": {
+		get: func(s *DroneSwarmSimulation) interface{} { return s.params["log_level"] },
+		set: func(s *DroneSwarmSimulation, value interface{}, _ string) error {
+			level, ok := value.(string)
+			if !ok {
+				return fmt.Errorf("log_level must be a string")
+			}
+			switch strings.ToLower(level) {
+			case "debug", "info", "warn", "warning", "error":
+			default:
+				return fmt.Errorf("log_level must be debug, info, warn or error")
+			}
+			logger.SetLevel(logger.ParseLevel(level))
+			return nil
+		},
+	},
+	"debug_logging": {
+		get: func(s *DroneSwarmSimulation) interface{} { return s.config.EnableDebugLogging },
+		set: func(s *DroneSwarmSimulation, value interface{}, _ string) error {
+			enabled, ok := value.(bool)
+			if !ok {
+				return fmt.Errorf("debug_logging must be true or false")
+			}
+			s.config.EnableDebugLogging = enabled
+			return nil
+		},
+	},
+	TuneCohesionWeight:      tunableReloadable(TuneCohesionWeight),
+	TuneFormationSpacing:    tunableReloadable(TuneFormationSpacing),
+	TuneSuccessRateModifier: tunableReloadable(TuneSuccessRateModifier),
+	TuneUpdateDecimation:    tunableReloadable(TuneUpdateDecimation),
+	"acceptable_leakage": {
+		get: func(s *DroneSwarmSimulation) interface{} { return s.config.AcceptableLeakage },
+		set: func(s *DroneSwarmSimulation, value interface{}, _ string) error {
+			v, err := reloadFloat("acceptable_leakage", value)
+			if err != nil {
+				return err
+			}
+			if v < 0 || v > 1 {
+				return fmt.Errorf("acceptable_leakage must be between 0 and 1")
+			}
+			s.config.AcceptableLeakage = v
+			return nil
+		},
+	},
+	"wave_leakage_threshold": {
+		get: func(s *DroneSwarmSimulation) interface{} { return s.config.WaveLeakageThreshold },
+		set: func(s *DroneSwarmSimulation, value interface{}, _ string) error {
+			v, err := reloadFloat("wave_leakage_threshold", value)
+			if err != nil {
+				return err
+			}
+			if v < 0 || v > 1 {
+				return fmt.Errorf("wave_leakage_threshold must be between 0 and 1")
+			}
+			s.config.WaveLeakageThreshold = v
+			return nil
+		},
+	},
+	"critical_asset_leakers": {
+		get: func(s *DroneSwarmSimulation) interface{} { return s.config.CriticalAssetLeakers },
+		set: func(s *DroneSwarmSimulation, value interface{}, _ string) error {
+			v, err := reloadFloat("critical_asset_leakers", value)
+			if err != nil {
+				return err
+			}
+			if v < 0 || v != float64(int(v)) {
+				return fmt.Errorf("critical_asset_leakers must be a whole number of at least 0")
+			}
+			s.config.CriticalAssetLeakers = int(v)
+			return nil
+		},
+	},
+	"summary_interval": {
+		get: func(s *DroneSwarmSimulation) interface{} { return s.config.SummaryInterval },
+		set: func(s *DroneSwarmSimulation, value interface{}, _ string) error {
+			d, err := reloadDuration("summary_interval", value)
+			if err != nil {
+				return err
+			}
+			if d < 0 {
+				return fmt.Errorf("summary_interval cannot be negative")
+			}
+			s.config.SummaryInterval = d
+			if s.summary.progress != nil {
+				s.summary.progress.SetInterval(d)
+			}
+			return nil
+		},
+	},
+	"threat_board_interval": {
+		get: func(s *DroneSwarmSimulation) interface{} { return s.config.ThreatBoardInterval },
+		set: func(s *DroneSwarmSimulation, value interface{}, _ string) error {
+			d, err := reloadDuration("threat_board_interval", value)
+			if err != nil {
+				return err
+			}
+			if d <= 0 {
+				return fmt.Errorf("threat_board_interval must be positive")
+			}
+			s.config.ThreatBoardInterval = d
+			return nil
+		},
+	},
+}
+
+// tunableReloadable routes a reload of a live-tunable parameter through the same
+// bounds the control panel enforces
+func tunableReloadable(name string) reloadable {
+	return reloadable{
+		get: func(s *DroneSwarmSimulation) interface{} {
+			for _, p := range s.tunables() {
+				if p.Name == name {
+					return p.Value
+				}
+			}
+			return nil
+		},
+		set: func(s *DroneSwarmSimulation, value interface{}, source string) error {
+			v, err := reloadFloat(name, value)
+			if err != nil {
+				return err
+			}
+			if s.control != nil {
+				// Queued like any other panel change; applyTuning records the inject
+				_, err := s.control.Set(name, v, source)
+				return err
+			}
+			for _, p := range s.tunables() {
+				if p.Name != name {
+					continue
+				}
+				if err := p.Check(v); err != nil {
+					return err
+				}
+				s.setTuned(name, v)
+				if s.simLogger != nil {
+					s.simLogger.LogInject(name, p.Value, v, source)
+				}
+			}
+			return nil
+		},
+	}
+}
+
+// Reload implements simulation.Reloader. The request is applied at the start of the
+// next tick; it fails if the simulation loop isn't running.
+func (s *DroneSwarmSimulation) Reload(params map[string]interface{}, source string) (simulation.ReloadResult, error) {
+	req := reloadRequest{params: params, source: source, reply: make(chan simulation.ReloadResult, 1)}
+
+	timeout := time.NewTimer(reloadTimeout)
+	defer timeout.Stop()
+
+	select {
+	case s.reloads <- req:
+	case <-timeout.C:
+		return simulation.ReloadResult{}, fmt.Errorf("simulation is not running; reload not applied")
+	}
+
+	select {
+	case result := <-req.reply:
+		return result, nil
+	case <-timeout.C:
+		return simulation.ReloadResult{}, fmt.Errorf("simulation did not apply the reload within %s", reloadTimeout)
+	}
+}
+
+// applyReloads handles reloads queued since the last tick
+func (s *DroneSwarmSimulation) applyReloads() {
+	for {
+		select {
+		case req := <-s.reloads:
+			req.reply <- s.reload(req.params, req.source)
+		default:
+			return
+		}
+	}
+}
+
+// reload compares params against the running configuration, applies the changed
+// parameters that are safe to change live and rejects the rest
+func (s *DroneSwarmSimulation) reload(params map[string]interface{}, source string) simulation.ReloadResult {
+	result := simulation.ReloadResult{Applied: []simulation.ParamChange{}}
+
+	names := make([]string, 0, len(params))
+	for name := range params {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		value := params[name]
+		if name == "organization_id" {
+			continue // Set by the CLI, not the operator
+		}
+
+		if r, ok := reloadables[name]; ok {
+			old := r.get(s)
+			if paramString(old) == paramString(value) {
+				continue
+			}
+			change := simulation.ParamChange{Name: name, Old: paramString(old), New: paramString(value)}
+			if err := r.set(s, value, source); err != nil {
+				change.Reason = err.Error()
+				result.Rejected = append(result.Rejected, change)
+				continue
+			}
+			result.Applied = append(result.Applied, change)
+			if s.params != nil {
+				s.params[name] = value
+			}
+			continue
+		}
+
+		old, known := s.params[name]
+		if known && paramString(old) == paramString(value) {
+			continue
+		}
+		change := simulation.ParamChange{Name: name, New: paramString(value), Reason: "unknown parameter"}
+		if known {
+			change.Old = paramString(old)
+			change.Reason = "structural; restart the run to change it"
+		}
+		result.Rejected = append(result.Rejected, change)
+	}
+
+	for _, change := range result.Applied {
+		logger.Infof("🔄 Reload from %s: %s %s -> %s", source, change.Name, change.Old, change.New)
+		if s.simLogger != nil && !isTunable(change.Name) {
+			s.simLogger.LogReload(change.Name, change.Old, change.New, source)
+		}
+	}
+	for _, change := range result.Rejected {
+		logger.Warnf("Reload from %s: %s not changed (%s)", source, change.Name, change.Reason)
+	}
+	return result
+}
+
+// isTunable reports whether name is one of the live-tunable parameters
+func isTunable(name string) bool {
+	switch name {
+	case TuneCohesionWeight, TuneFormationSpacing, TuneSuccessRateModifier, TuneUpdateDecimation:
+		return true
+	}
+	return false
+}
+
+// paramString renders a parameter value so equal settings compare equal whatever
+// their source: YAML ints, JSON floats and duration strings all normalize
+func paramString(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case time.Duration:
+		return v.String()
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	case int:
+		return strconv.Itoa(v)
+	case string:
+		if d, err := time.ParseDuration(v); err == nil {
+			return d.String()
+		}
+		return v
+	default:
+		return fmt.Sprint(v)
+	}
+}
+
+// reloadFloat reads a number from a reloaded parameter
+func reloadFloat(name string, value interface{}) (float64, error) {
+	v, err := simulation.Parameter{Name: name, Type: "float"}.Convert(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a number", name)
+	}
+	return v.(float64), nil
+}
+
+// reloadDuration reads a duration from a reloaded parameter; bare numbers are seconds
+func reloadDuration(name string, value interface{}) (time.Duration, error) {
+	v, err := simulation.Parameter{Name: name, Type: "duration"}.Convert(value)
+	if err != nil {
+		return 0, fmt.Errorf("%s must be a duration such as 30s", name)
+	}
+	return v.(time.Duration), nil
+}
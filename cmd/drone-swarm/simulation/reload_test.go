@@ -0,0 +1,43 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestReloadAppliesOnlyNonStructuralChanges(t *testing.T) {
+	sim := NewDroneSwarmSimulation().(*DroneSwarmSimulation)
+	if err := sim.Configure(map[string]interface{}{
+		"num_uas_threats":    50,
+		"acceptable_leakage": 0.05,
+		"summary_interval":   10 * time.Second,
+	}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+
+	result := sim.reload(map[string]interface{}{
+		"num_uas_threats":    80,
+		"acceptable_leakage": 0.1,
+		"summary_interval":   "10s", // Unchanged, just written differently
+		TuneCohesionWeight:   0.9,   // Above the panel's bound
+		"weather":            "fog",
+	}, "test")
+
+	if len(result.Applied) != 1 || result.Applied[0].Name != "acceptable_leakage" {
+		t.Fatalf("expected only acceptable_leakage applied, got %+v", result.Applied)
+	}
+	if sim.config.AcceptableLeakage != 0.1 {
+		t.Fatalf("acceptable_leakage = %g, want 0.1", sim.config.AcceptableLeakage)
+	}
+	if sim.config.NumUASThreats != 50 || sim.config.CohesionWeight != DefaultCohesionWeight {
+		t.Fatal("rejected changes must not touch the config")
+	}
+
+	rejected := make(map[string]string)
+	for _, change := range result.Rejected {
+		rejected[change.Name] = change.Reason
+	}
+	if len(rejected) != 3 || rejected["num_uas_threats"] == "" || rejected[TuneCohesionWeight] == "" || rejected["weather"] != "unknown parameter" {
+		t.Fatalf("unexpected rejections: %+v", result.Rejected)
+	}
+}
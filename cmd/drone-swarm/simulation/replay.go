@@ -0,0 +1,137 @@
+package simulation
+
+import (
+	"math"
+	"sort"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/flightlog"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// trackReplay drives a threat along a recorded flight instead of simulated behavior
+type trackReplay struct {
+	Track  flightlog.Track
+	Offset time.Duration // From scenario start to the track's first sample
+	DLat   float64       // Shift applied to every sample when relocating to the base
+	DLon   float64
+	DAlt   float64
+}
+
+// position returns the replayed position at elapsed scenario time, and false once the
+// recording has ended
+func (r *trackReplay) position(elapsed time.Duration) (lat, lon, alt float64, ok bool) {
+	offset := elapsed - r.Offset
+	if offset > r.Track.Duration() {
+		return 0, 0, 0, false
+	}
+	sample := r.Track.At(offset)
+	return sample.Lat + r.DLat, sample.Lon + r.DLon, sample.Alt + r.DAlt, true
+}
+
+// deployReplayTracks assigns the recorded tracks to threats in track-number order. The
+// recording's relative timing is kept: the earliest track starts with the scenario.
+// Relocating moves the whole recording so its centroid sits on the base and its lowest
+// sample is at base altitude, keeping the geometry between tracks.
+func (s *DroneSwarmSimulation) deployReplayTracks() {
+	tracks := s.config.ReplayTracks
+
+	threats := make([]*UASThreat, 0, len(s.uasThreats))
+	for _, threat := range s.uasThreats {
+		if !threat.Remote {
+			threats = append(threats, threat)
+		}
+	}
+	sort.Slice(threats, func(i, j int) bool { return threats[i].TrackNumber < threats[j].TrackNumber })
+
+	epoch := tracks[0].Start()
+	var sumLat, sumLon float64
+	var samples int
+	minAlt := math.Inf(1)
+	for _, track := range tracks {
+		if track.Start().Before(epoch) {
+			epoch = track.Start()
+		}
+		for _, sample := range track.Samples {
+			sumLat += sample.Lat
+			sumLon += sample.Lon
+			minAlt = math.Min(minAlt, sample.Alt)
+			samples++
+		}
+	}
+
+	var dLat, dLon, dAlt float64
+	if s.config.ReplayRelocate {
+		dLat = s.config.BaseLocation.Lat - sumLat/float64(samples)
+		dLon = s.config.BaseLocation.Lon - sumLon/float64(samples)
+		dAlt = s.config.BaseLocation.Alt - minAlt
+	}
+
+	replayed := min(len(threats), len(tracks))
+	for i, threat := range threats[:replayed] {
+		track := tracks[i]
+		threat.Replay = &trackReplay{
+			Track:  track,
+			Offset: track.Start().Sub(epoch),
+			DLat:   dLat,
+			DLon:   dLon,
+			DAlt:   dAlt,
+		}
+		threat.LaunchPhase = ""
+		threat.ActualCapabilities.SpeedKph = averageSpeedKph(track)
+
+		first := track.Samples[0]
+		x, y, z := latLonAltToECEF(first.Lat+dLat, first.Lon+dLon, first.Alt+dAlt)
+		threat.Position.Coordinates = []float64{x, y, z}
+		threat.ActualVelocity.Coordinates = []float64{0, 0, 0}
+		behaviorLog.Debugf("📼 %s replays recorded track %s (%s, starts at +%s)",
+			threat.TrackNumber, track.ID, track.Duration().Round(time.Second), threat.Replay.Offset.Round(time.Second))
+	}
+
+	logger.Infof("📼 Replaying %d recorded tracks; %d threats fly synthetic behaviors", replayed, len(threats)-replayed)
+	if len(tracks) > len(threats) {
+		logger.Warnf("%d recorded tracks not replayed; raise num_uas_threats to replay them all", len(tracks)-len(threats))
+	}
+}
+
+// advanceReplay moves a replayed threat to its recorded position. Returns true for
+// replayed threats so normal movement is skipped; a threat whose recording has ended
+// is marked LOST.
+func (s *DroneSwarmSimulation) advanceReplay(threat *UASThreat, deltaTime float64) bool {
+	if threat.Replay == nil {
+		return false
+	}
+
+	lat, lon, alt, ok := threat.Replay.position(s.scenarioElapsed())
+	if !ok {
+		threat.UpdateClassification(TrackStatusLost)
+		s.queueClassificationUpdate(threat)
+		behaviorLog.Infof("📼 %s recording ended; track lost", threat.TrackNumber)
+		return true
+	}
+
+	x, y, z := latLonAltToECEF(lat, lon, alt)
+	if deltaTime > 0 {
+		threat.ActualVelocity.Coordinates[0] = (x - threat.Position.Coordinates[0]) / deltaTime
+		threat.ActualVelocity.Coordinates[1] = (y - threat.Position.Coordinates[1]) / deltaTime
+		threat.ActualVelocity.Coordinates[2] = (z - threat.Position.Coordinates[2]) / deltaTime
+	}
+	threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2] = x, y, z
+	return true
+}
+
+// averageSpeedKph is a recording's path length over its duration
+func averageSpeedKph(track flightlog.Track) float64 {
+	duration := track.Duration().Seconds()
+	if duration <= 0 {
+		return 0
+	}
+	var meters float64
+	px, py, pz := latLonAltToECEF(track.Samples[0].Lat, track.Samples[0].Lon, track.Samples[0].Alt)
+	for _, sample := range track.Samples[1:] {
+		x, y, z := latLonAltToECEF(sample.Lat, sample.Lon, sample.Alt)
+		meters += math.Sqrt((x-px)*(x-px) + (y-py)*(y-py) + (z-pz)*(z-pz))
+		px, py, pz = x, y, z
+	}
+	return meters / duration * 3.6
+}
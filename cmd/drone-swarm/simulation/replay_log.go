@@ -0,0 +1,68 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/runlog"
+)
+
+// startReplayLog records every entity write the run makes to Legion, with the run's
+// events, for legion-sim replay when record_replay is set
+func (s *DroneSwarmSimulation) startReplayLog() {
+	if !s.config.RecordReplay {
+		return
+	}
+	if err := os.MkdirAll(reportsDir, 0755); err != nil {
+		logger.Warnf("Replay log disabled: failed to create reports directory: %v", err)
+		return
+	}
+	path := filepath.Join(reportsDir, fmt.Sprintf("Replay_%s_%s.jsonl.gz", s.runID[:8], time.Now().Format("20060102_150405")))
+	w, err := runlog.Create(path, runlog.Header{Simulation: s.Name(), RunID: s.runID})
+	if err != nil {
+		logger.Warnf("Replay log disabled: %v", err)
+		return
+	}
+	s.replayLog, s.replayLogPath = w, path
+	s.legionClient.SetRecorder(w)
+}
+
+// recordAdopted logs an entity adopted from an earlier run as if the run created it,
+// so a replay recreates it
+func (s *DroneSwarmSimulation) recordAdopted(entity *models.EntityResponse, req *models.CreateEntityRequest) {
+	if s.replayLog != nil {
+		s.replayLog.EntityCreated(entity, req)
+	}
+}
+
+// saveReplayLog sends what is still buffered, stops recording and closes the log.
+// Safe to call more than once.
+func (s *DroneSwarmSimulation) saveReplayLog() error {
+	if s.replayLog == nil {
+		return nil
+	}
+	if s.updateBuffer != nil {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = s.updateBuffer.ForceFlush(ctx)
+	}
+
+	w, path := s.replayLog, s.replayLogPath
+	s.replayLog = nil
+	s.legionClient.SetRecorder(nil)
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write replay log: %w", err)
+	}
+
+	s.artifacts = append(s.artifacts, path)
+	if s.aarGenerator != nil {
+		s.aarGenerator.AddAttachment(path)
+	}
+	logger.Successf("Replay log saved to: %s (%d records; legion-sim replay %s)", path, w.Records(), path)
+	return nil
+}
@@ -0,0 +1,75 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/pkg/flightlog"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestReplayTracks(t *testing.T) {
+	recorded := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	tracks := []flightlog.Track{
+		{ID: "a", Samples: []flightlog.Sample{
+			{Time: recorded, Lat: 51.00, Lon: 0.00, Alt: 200},
+			{Time: recorded.Add(20 * time.Second), Lat: 51.01, Lon: 0.00, Alt: 300},
+		}},
+		{ID: "b", Samples: []flightlog.Sample{
+			{Time: recorded.Add(10 * time.Second), Lat: 51.00, Lon: 0.02, Alt: 250},
+			{Time: recorded.Add(30 * time.Second), Lat: 51.01, Lon: 0.02, Alt: 250},
+		}},
+	}
+
+	s := &DroneSwarmSimulation{uasThreats: map[uuid.UUID]*UASThreat{}}
+	s.config.BaseLocation = Location{Lat: 38.8895, Lon: -77.0353, Alt: 50}
+	s.config.ReplayTracks = tracks
+	s.config.ReplayRelocate = true
+	for _, number := range []string{"TK-0002", "TK-0001", "TK-0003"} {
+		threat := &UASThreat{
+			ID:             uuid.New(),
+			TrackNumber:    number,
+			Position:       &models.GeomPoint{Coordinates: []float64{0, 0, 0}},
+			ActualVelocity: &models.GeomPoint{Coordinates: []float64{0, 0, 0}},
+		}
+		s.uasThreats[threat.ID] = threat
+	}
+	byNumber := map[string]*UASThreat{}
+	for _, threat := range s.uasThreats {
+		byNumber[threat.TrackNumber] = threat
+	}
+
+	s.deployReplayTracks()
+
+	first, second := byNumber["TK-0001"], byNumber["TK-0002"]
+	if first.Replay == nil || first.Replay.Track.ID != "a" || second.Replay == nil || second.Replay.Track.ID != "b" {
+		t.Fatal("expected tracks assigned in track-number order")
+	}
+	if byNumber["TK-0003"].Replay != nil {
+		t.Error("expected the threat without a recording to stay simulated")
+	}
+	if second.Replay.Offset != 10*time.Second {
+		t.Errorf("expected b to start 10s into the scenario, got %s", second.Replay.Offset)
+	}
+
+	// Relocated onto the base: lowest sample at base altitude, geometry between tracks kept
+	lat, lon, alt := ecefToLatLonAlt(first.Position.Coordinates[0], first.Position.Coordinates[1], first.Position.Coordinates[2])
+	if math.Abs(alt-50) > 0.01 || math.Abs(lat-(38.8895-0.005)) > 1e-6 || math.Abs(lon-(-77.0353-0.01)) > 1e-6 {
+		t.Errorf("unexpected relocated start %.6f, %.6f, %.1fm", lat, lon, alt)
+	}
+
+	// Halfway through b's recording
+	s.scenarioStart = time.Now().Add(-20 * time.Second)
+	if !s.advanceReplay(second, 1) {
+		t.Fatal("expected a replayed threat to skip simulated movement")
+	}
+	lat, _, _ = ecefToLatLonAlt(second.Position.Coordinates[0], second.Position.Coordinates[1], second.Position.Coordinates[2])
+	if math.Abs(lat-38.8895) > 1e-4 {
+		t.Errorf("expected b at its midpoint latitude, got %.6f", lat)
+	}
+	if _, _, _, ok := second.Replay.position(31 * time.Second); ok {
+		t.Error("expected the recording to have ended")
+	}
+}
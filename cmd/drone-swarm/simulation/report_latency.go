@@ -0,0 +1,239 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// Datalink states a reporting delay can be set for
+const (
+	DataLinkOnline   = "ONLINE"
+	DataLinkDegraded = "DEGRADED"
+)
+
+// degradedLatencyFactor scales the online delay and jitter on a DEGRADED link when
+// report_latency sets no degraded entry
+const degradedLatencyFactor = 3.0
+
+// ReportLatency is the delay from a sensor detecting a track to C2 receiving the report
+type ReportLatency struct {
+	Latency time.Duration
+	Jitter  time.Duration // 1-sigma
+}
+
+// ReportLatencies are sensor-to-C2 reporting delays by datalink state
+type ReportLatencies map[string]ReportLatency
+
+// detectionReport is a detection on its way from a sensor to C2
+type detectionReport struct {
+	due            time.Time
+	detected       time.Time
+	threat         *UASThreat
+	classification string
+	affiliation    models.Affiliation
+	metadata       json.RawMessage
+}
+
+// reportLog holds detections in flight to C2 and what their delivery took
+type reportLog struct {
+	mu        sync.Mutex
+	pending   []detectionReport
+	latencies []float64 // Seconds from detection to delivery
+	dropped   int       // Reports sent over a link that was down
+	held      int       // Target selections passed over because C2 had no report yet
+}
+
+// parseReportLatency parses "state:latency[:jitter]" entries separated by semicolons,
+// where state is online or degraded, e.g. "online:500ms:200ms;degraded:3s:1s"
+func parseReportLatency(spec string) (ReportLatencies, error) {
+	latencies := make(ReportLatencies)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ":")
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("report latency %q: expected state:latency[:jitter]", entry)
+		}
+		state := strings.ToUpper(strings.TrimSpace(fields[0]))
+		if state != DataLinkOnline && state != DataLinkDegraded {
+			return nil, fmt.Errorf("report latency %q: state must be online or degraded", entry)
+		}
+		if _, dup := latencies[state]; dup {
+			return nil, fmt.Errorf("report latency for %s is given twice", strings.ToLower(state))
+		}
+
+		var latency ReportLatency
+		var err error
+		if latency.Latency, err = time.ParseDuration(strings.TrimSpace(fields[1])); err != nil || latency.Latency < 0 {
+			return nil, fmt.Errorf("report latency %q: invalid latency %q", entry, fields[1])
+		}
+		if len(fields) == 3 {
+			if latency.Jitter, err = time.ParseDuration(strings.TrimSpace(fields[2])); err != nil || latency.Jitter < 0 {
+				return nil, fmt.Errorf("report latency %q: invalid jitter %q", entry, fields[2])
+			}
+		}
+		latencies[state] = latency
+	}
+
+	if _, ok := latencies[DataLinkDegraded]; ok {
+		if _, ok := latencies[DataLinkOnline]; !ok {
+			return nil, fmt.Errorf("report latency for degraded links needs one for online links too")
+		}
+	}
+	return latencies, nil
+}
+
+// forState returns the delay over a link in the given state. ok is false when the link
+// is down and reports over it are lost.
+func (l ReportLatencies) forState(state string) (latency ReportLatency, ok bool) {
+	if latency, ok = l[state]; ok || state != DataLinkDegraded {
+		return latency, ok
+	}
+	online := l[DataLinkOnline]
+	return ReportLatency{
+		Latency: time.Duration(float64(online.Latency) * degradedLatencyFactor),
+		Jitter:  time.Duration(float64(online.Jitter) * degradedLatencyFactor),
+	}, true
+}
+
+// reportDelay draws a report's delay over a link in the given state from rng. ok is
+// false when the link is down and the report is lost.
+func (l ReportLatencies) reportDelay(state string, rng *rand.Rand) (delay time.Duration, ok bool) {
+	latency, ok := l.forState(state)
+	if !ok {
+		return 0, false
+	}
+	delay = latency.Latency + time.Duration(rng.NormFloat64()*float64(latency.Jitter))
+	return max(delay, 0), true
+}
+
+// reportDetection sends a detection to C2: at once without report latency, or over the
+// detecting system's datalink to arrive after its delay
+func (s *DroneSwarmSimulation) reportDetection(system *CounterUASSystem, threat *UASThreat) {
+	threat.mu.RLock()
+	report := detectionReport{
+		detected:       s.scenarioNow(),
+		threat:         threat,
+		classification: threat.Classification,
+		affiliation:    threat.Affiliation,
+	}
+	threat.mu.RUnlock()
+	report.metadata, _ = json.Marshal(threat.GetMetadata())
+	if len(s.config.ReportLatency) == 0 {
+		s.deliverReport(report)
+		return
+	}
+
+	delay, ok := s.config.ReportLatency.reportDelay(system.DataLinkStatus, system.random())
+	s.reports.mu.Lock()
+	defer s.reports.mu.Unlock()
+	if !ok {
+		s.reports.dropped++
+		return
+	}
+	report.due = report.detected.Add(delay)
+	s.reports.pending = append(s.reports.pending, report)
+}
+
+// deliverReports publishes the detections that have reached C2 by now, in the order
+// they arrive. A report outrun by the track's destruction or loss is discarded.
+func (s *DroneSwarmSimulation) deliverReports() {
+	now := s.scenarioNow()
+	s.reports.mu.Lock()
+	var due []detectionReport
+	remaining := s.reports.pending[:0]
+	for _, report := range s.reports.pending {
+		if report.due.After(now) {
+			remaining = append(remaining, report)
+			continue
+		}
+		due = append(due, report)
+		s.reports.latencies = append(s.reports.latencies, report.due.Sub(report.detected).Seconds())
+	}
+	s.reports.pending = remaining
+	s.reports.mu.Unlock()
+
+	sort.SliceStable(due, func(i, j int) bool { return due[i].due.Before(due[j].due) })
+	for _, report := range due {
+		if status := report.threat.Classification; status == TrackStatusDestroyed || status == TrackStatusLost {
+			continue
+		}
+		s.deliverReport(report)
+	}
+}
+
+// deliverReport publishes a detection as it was when sent and marks the track reported
+func (s *DroneSwarmSimulation) deliverReport(report detectionReport) {
+	threat := report.threat
+	threat.mu.Lock()
+	if threat.ReportedAt.IsZero() {
+		threat.ReportedAt = s.now()
+	}
+	threat.mu.Unlock()
+
+	s.publishClassification(threat, report.classification, report.affiliation)
+	s.updateBuffer.QueueMetadataUpdate(threat.ID, "metadata", report.metadata)
+	s.queueTrailUpdate(threat)
+}
+
+// reportedToC2 reports whether C2 holds a track and may task systems against it. A
+// system passing over a track for want of a report counts as held.
+func (s *DroneSwarmSimulation) reportedToC2(threat *UASThreat) bool {
+	if len(s.config.ReportLatency) == 0 {
+		return true
+	}
+	threat.mu.RLock()
+	reported := !threat.ReportedAt.IsZero()
+	threat.mu.RUnlock()
+	if !reported {
+		s.reports.mu.Lock()
+		s.reports.held++
+		s.reports.mu.Unlock()
+	}
+	return reported
+}
+
+// recordReportMetrics records sensor-to-C2 reporting delays for the AAR
+func (s *DroneSwarmSimulation) recordReportMetrics() {
+	if len(s.config.ReportLatency) == 0 {
+		return
+	}
+	s.reports.mu.Lock()
+	latencies := append([]float64(nil), s.reports.latencies...)
+	dropped, held := s.reports.dropped, s.reports.held
+	s.reports.mu.Unlock()
+
+	s.simLogger.UpdateMetric(reporting.MetricReportsDelivered, float64(len(latencies)), "count")
+	s.simLogger.UpdateMetric(reporting.MetricReportsDropped, float64(dropped), "count")
+	s.simLogger.UpdateMetric(reporting.MetricReportsHeld, float64(held), "count")
+	if len(latencies) == 0 {
+		return
+	}
+
+	sort.Float64s(latencies)
+	total := 0.0
+	for _, latency := range latencies {
+		total += latency
+	}
+	mean := total / float64(len(latencies))
+	p95 := latencies[int(math.Ceil(0.95*float64(len(latencies))))-1]
+	s.simLogger.UpdateMetric(reporting.MetricReportLatencyMean, mean*1000, "ms")
+	s.simLogger.UpdateMetric(reporting.MetricReportLatencyP95, p95*1000, "ms")
+	s.simLogger.UpdateMetric(reporting.MetricReportLatencyMax, latencies[len(latencies)-1]*1000, "ms")
+
+	logger.Infof("Sensor-to-C2 reports: %d delivered (mean %.0fms, p95 %.0fms), %d lost on down links, %d target selections held for C2",
+		len(latencies), mean*1000, p95*1000, dropped, held)
+}
@@ -0,0 +1,51 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseReportLatency(t *testing.T) {
+	latencies, err := parseReportLatency("online:500ms:200ms; Degraded:3s")
+	if err != nil {
+		t.Fatalf("parseReportLatency: %v", err)
+	}
+	if latencies[DataLinkOnline] != (ReportLatency{Latency: 500 * time.Millisecond, Jitter: 200 * time.Millisecond}) ||
+		latencies[DataLinkDegraded] != (ReportLatency{Latency: 3 * time.Second}) {
+		t.Errorf("unexpected latencies %+v", latencies)
+	}
+
+	for _, spec := range []string{
+		"online",                    // no latency
+		"offline:1s",                // unknown state
+		"online:soon",               // bad latency
+		"online:1s:-1s",             // negative jitter
+		"online:1s;online:2s",       // duplicate
+		"degraded:2s",               // degraded without online
+		"online:1s:100ms:extra:bad", // too many fields
+	} {
+		if _, err := parseReportLatency(spec); err == nil {
+			t.Errorf("parseReportLatency(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestReportDelay(t *testing.T) {
+	latencies, _ := parseReportLatency("online:1s")
+	if delay, ok := latencies.reportDelay(DataLinkOnline, unseeded); !ok || delay != time.Second {
+		t.Errorf("online delay = %s, %t; want 1s", delay, ok)
+	}
+	if delay, ok := latencies.reportDelay(DataLinkDegraded, unseeded); !ok || delay != 3*time.Second {
+		t.Errorf("degraded delay = %s, %t; want three times online", delay, ok)
+	}
+	if _, ok := latencies.reportDelay("OFFLINE", unseeded); ok {
+		t.Error("a report over a down link should be lost")
+	}
+
+	jittery, _ := parseReportLatency("online:10ms:1s")
+	for i := 0; i < 100; i++ {
+		if delay, _ := jittery.reportDelay(DataLinkOnline, unseeded); delay < 0 {
+			t.Fatalf("negative delay %s", delay)
+		}
+	}
+}
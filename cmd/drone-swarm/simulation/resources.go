@@ -0,0 +1,199 @@
+package simulation
+
+import (
+	"math"
+	"runtime"
+	"runtime/debug"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// resourceSampleInterval is how often process resource usage is sampled during a run
+const resourceSampleInterval = time.Second
+
+// resourceSampler samples the process's CPU, memory, goroutines and GC pauses in the
+// background, keeping running peaks so the AAR reports the whole run rather than the
+// last sample
+type resourceSampler struct {
+	mu sync.Mutex
+
+	started  time.Time
+	cpuOK    bool          // Whether this platform reports process CPU time
+	cpuStart time.Duration // Process CPU time when sampling began
+	lastAt   time.Time
+	lastCPU  time.Duration
+	cpuPeak  float64
+
+	heapPeak       uint64
+	sysPeak        uint64
+	goroutinesPeak int
+
+	gcStart   uint32 // GC cycle count when sampling began
+	lastNumGC uint32
+	pauses    []time.Duration
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// resourceUsage is a run's process resource usage
+type resourceUsage struct {
+	CPU            float64 // Share of GOMAXPROCS used over the run
+	CPUPeak        float64 // Highest share in one sample interval
+	Memory         float64 // Peak memory as a share of GOMEMLIMIT; 0 without a limit
+	HeapPeakMB     float64
+	SysPeakMB      float64
+	GoroutinesPeak int
+	GCCycles       int
+	GCPauseTotal   time.Duration
+	GCPauseMax     time.Duration
+	GCPauseP99     time.Duration
+}
+
+// startResourceSampler takes a first sample and keeps sampling until stopped
+func startResourceSampler() *resourceSampler {
+	r := &resourceSampler{
+		started: time.Now(),
+		stop:    make(chan struct{}),
+		done:    make(chan struct{}),
+	}
+	r.lastAt = r.started
+	r.cpuStart, r.cpuOK = processCPUTime()
+	r.lastCPU = r.cpuStart
+
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+	r.gcStart, r.lastNumGC = ms.NumGC, ms.NumGC
+	r.observe(&ms)
+
+	go func() {
+		defer close(r.done)
+		ticker := time.NewTicker(resourceSampleInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-r.stop:
+				return
+			case now := <-ticker.C:
+				r.sample(now)
+			}
+		}
+	}()
+	return r
+}
+
+// sample reads the runtime's memory statistics and the process CPU time
+func (r *resourceSampler) sample(now time.Time) {
+	var ms runtime.MemStats
+	runtime.ReadMemStats(&ms)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.observe(&ms)
+	if cpu, ok := processCPUTime(); ok {
+		if share := cpuShare(cpu-r.lastCPU, now.Sub(r.lastAt)); share > r.cpuPeak {
+			r.cpuPeak = share
+		}
+		r.lastCPU = cpu
+	}
+	r.lastAt = now
+}
+
+// observe folds one memory sample into the peaks and collects pauses of GC cycles
+// completed since the last sample
+func (r *resourceSampler) observe(ms *runtime.MemStats) {
+	r.heapPeak = max(r.heapPeak, ms.HeapAlloc)
+	r.sysPeak = max(r.sysPeak, ms.Sys)
+	r.goroutinesPeak = max(r.goroutinesPeak, runtime.NumGoroutine())
+
+	// PauseNs is a ring of the last 256 pauses; older ones are lost between samples
+	first := r.lastNumGC + 1
+	if ms.NumGC-r.lastNumGC > uint32(len(ms.PauseNs)) {
+		first = ms.NumGC - uint32(len(ms.PauseNs)) + 1
+	}
+	for cycle := first; cycle <= ms.NumGC; cycle++ {
+		r.pauses = append(r.pauses, time.Duration(ms.PauseNs[(cycle+255)%256]))
+	}
+	r.lastNumGC = ms.NumGC
+}
+
+// Stop takes a final sample and returns the run's usage
+func (r *resourceSampler) Stop() resourceUsage {
+	close(r.stop)
+	<-r.done
+	r.sample(time.Now())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	usage := resourceUsage{
+		CPUPeak:        r.cpuPeak,
+		HeapPeakMB:     float64(r.heapPeak) / (1 << 20),
+		SysPeakMB:      float64(r.sysPeak) / (1 << 20),
+		GoroutinesPeak: r.goroutinesPeak,
+		GCCycles:       int(r.lastNumGC - r.gcStart),
+	}
+	if r.cpuOK {
+		usage.CPU = cpuShare(r.lastCPU-r.cpuStart, r.lastAt.Sub(r.started))
+	}
+	// Without a limit the runtime reports math.MaxInt64
+	if limit := debug.SetMemoryLimit(-1); limit > 0 && limit < math.MaxInt64 {
+		usage.Memory = float64(r.sysPeak) / float64(limit)
+	}
+
+	pauses := append([]time.Duration(nil), r.pauses...)
+	sort.Slice(pauses, func(i, j int) bool { return pauses[i] < pauses[j] })
+	for _, pause := range pauses {
+		usage.GCPauseTotal += pause
+	}
+	if n := len(pauses); n > 0 {
+		usage.GCPauseMax = pauses[n-1]
+		usage.GCPauseP99 = pauses[int(math.Ceil(0.99*float64(n)))-1]
+	}
+	return usage
+}
+
+// cpuShare is CPU time as a share of what GOMAXPROCS allows over the wall time
+func cpuShare(cpu, wall time.Duration) float64 {
+	if wall <= 0 {
+		return 0
+	}
+	return cpu.Seconds() / (wall.Seconds() * float64(runtime.GOMAXPROCS(0)))
+}
+
+// stopResources stops the sampler when the run ends before its usage is recorded
+func (s *DroneSwarmSimulation) stopResources() {
+	if s.resources != nil {
+		s.resources.Stop()
+		s.resources = nil
+	}
+}
+
+// recordResourceMetrics stops the sampler and hands the run's resource usage to the AAR
+func (s *DroneSwarmSimulation) recordResourceMetrics() {
+	if s.resources == nil {
+		return
+	}
+	usage := s.resources.Stop()
+	s.resources = nil
+	logger.Infof("Resources: %.0f%% CPU (peak %.0f%%), heap peak %.0f MB, %d goroutines peak, %d GC cycles (p99 pause %s)",
+		usage.CPU*100, usage.CPUPeak*100, usage.HeapPeakMB, usage.GoroutinesPeak, usage.GCCycles, usage.GCPauseP99)
+
+	if usage.CPU > 0 {
+		s.simLogger.UpdateMetric(reporting.MetricCPUUsage, usage.CPU, "ratio")
+		s.simLogger.UpdateMetric(reporting.MetricCPUPeak, usage.CPUPeak, "ratio")
+	}
+	if usage.Memory > 0 {
+		s.simLogger.UpdateMetric(reporting.MetricMemoryUsage, usage.Memory, "ratio")
+	}
+	s.simLogger.UpdateMetric(reporting.MetricHeapPeak, usage.HeapPeakMB, "MB")
+	s.simLogger.UpdateMetric(reporting.MetricSysPeak, usage.SysPeakMB, "MB")
+	s.simLogger.UpdateMetric(reporting.MetricGoroutinesPeak, float64(usage.GoroutinesPeak), "count")
+	s.simLogger.UpdateMetric(reporting.MetricGCCycles, float64(usage.GCCycles), "count")
+	s.simLogger.UpdateMetric(reporting.MetricGCPauseTotal, float64(usage.GCPauseTotal)/float64(time.Millisecond), "ms")
+	s.simLogger.UpdateMetric(reporting.MetricGCPauseMax, float64(usage.GCPauseMax)/float64(time.Millisecond), "ms")
+	s.simLogger.UpdateMetric(reporting.MetricGCPauseP99, float64(usage.GCPauseP99)/float64(time.Millisecond), "ms")
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package simulation
+
+import "time"
+
+// processCPUTime is unsupported here; the AAR omits CPU usage
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
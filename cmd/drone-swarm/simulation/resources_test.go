@@ -0,0 +1,30 @@
+package simulation
+
+import (
+	"runtime"
+	"testing"
+)
+
+func TestResourceSamplerCountsGCCycles(t *testing.T) {
+	sampler := startResourceSampler()
+	for i := 0; i < 5; i++ {
+		runtime.GC()
+	}
+	usage := sampler.Stop()
+
+	if usage.GCCycles < 5 {
+		t.Errorf("GC cycles = %d, want at least 5", usage.GCCycles)
+	}
+	if usage.GCPauseTotal <= 0 || usage.GCPauseMax < usage.GCPauseP99 || usage.GCPauseTotal < usage.GCPauseMax {
+		t.Errorf("inconsistent GC pauses: total %s, max %s, p99 %s", usage.GCPauseTotal, usage.GCPauseMax, usage.GCPauseP99)
+	}
+	if usage.HeapPeakMB <= 0 || usage.SysPeakMB < usage.HeapPeakMB {
+		t.Errorf("heap peak %.2f MB, sys peak %.2f MB", usage.HeapPeakMB, usage.SysPeakMB)
+	}
+	if usage.GoroutinesPeak < 2 {
+		t.Errorf("goroutines peak = %d, want the test and the sampler", usage.GoroutinesPeak)
+	}
+	if usage.CPU < 0 || usage.CPUPeak < 0 {
+		t.Errorf("CPU %.2f, peak %.2f", usage.CPU, usage.CPUPeak)
+	}
+}
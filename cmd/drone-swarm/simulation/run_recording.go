@@ -0,0 +1,244 @@
+package simulation
+
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/tiles"
+)
+
+// Run recording settings
+const (
+	recordingMinStep    = 20.0 // Meters a track moves before another vertex is recorded
+	recordingRingPoints = 72   // Vertices approximating each coverage circle
+)
+
+// runRecording collects the run's geometry for post-run analysis with legion-sim tiles serve
+type runRecording struct {
+	tracks map[uuid.UUID]*recordedTrack
+	points []tiles.Feature // Engagements, detections and leakers
+	mu     sync.Mutex
+}
+
+// recordedTrack is a threat's flight path, thinned to vertices recordingMinStep apart
+type recordedTrack struct {
+	coords     [][2]float64
+	last       [3]float64 // ECEF position of the last vertex
+	start, end time.Time
+}
+
+// recordTrackVertex extends a threat's recorded flight path
+func (s *DroneSwarmSimulation) recordTrackVertex(threat *UASThreat, at time.Time) {
+	if !s.config.RecordRun || threat.Position == nil || len(threat.Position.Coordinates) < 3 {
+		return
+	}
+	var pos [3]float64
+	copy(pos[:], threat.Position.Coordinates)
+
+	s.recording.mu.Lock()
+	defer s.recording.mu.Unlock()
+	if s.recording.tracks == nil {
+		s.recording.tracks = make(map[uuid.UUID]*recordedTrack)
+	}
+	track := s.recording.tracks[threat.ID]
+	if track == nil {
+		track = &recordedTrack{start: at}
+		s.recording.tracks[threat.ID] = track
+	} else if dx, dy, dz := pos[0]-track.last[0], pos[1]-track.last[1], pos[2]-track.last[2]; math.Sqrt(dx*dx+dy*dy+dz*dz) < recordingMinStep {
+		track.end = at
+		return
+	}
+
+	lat, lon, _ := ecefToLatLonAlt(pos[0], pos[1], pos[2])
+	track.coords = append(track.coords, [2]float64{lon, lat})
+	track.last, track.end = pos, at
+}
+
+// recordRunEvent notes a detection or leak at the threat's position
+func (s *DroneSwarmSimulation) recordRunEvent(threat *UASThreat, kind string) {
+	if !s.config.RecordRun || threat.Position == nil {
+		return
+	}
+	lat, lon, _ := ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+
+	s.recording.mu.Lock()
+	s.recording.points = append(s.recording.points, tiles.Feature{
+		Layer:       tiles.LayerEvents,
+		Type:        tiles.Point,
+		Coordinates: [][2]float64{{lon, lat}},
+		Properties: map[string]interface{}{
+			"kind":    kind,
+			"track":   threat.TrackNumber,
+			"elapsed": s.now().Sub(s.scenarioStart).Seconds(),
+		},
+	})
+	s.recording.mu.Unlock()
+}
+
+// recordEngagementPoint notes where a shot was taken and how it went
+func (s *DroneSwarmSimulation) recordEngagementPoint(system *CounterUASSystem, threat *UASThreat, result *EngagementResult) {
+	if !s.config.RecordRun || threat.Position == nil {
+		return
+	}
+	lat, lon, _ := ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+
+	system.mu.RLock()
+	callsign, name := system.Callsign, system.Name
+	system.mu.RUnlock()
+
+	s.recording.mu.Lock()
+	s.recording.points = append(s.recording.points, tiles.Feature{
+		Layer:       tiles.LayerEngagements,
+		Type:        tiles.Point,
+		Coordinates: [][2]float64{{lon, lat}},
+		Properties: map[string]interface{}{
+			"system":   callsign,
+			"name":     name,
+			"effect":   result.EngageType,
+			"track":    threat.TrackNumber,
+			"hit":      result.Success,
+			"range_km": math.Round(result.Distance*100) / 100,
+			"elapsed":  s.now().Sub(s.scenarioStart).Seconds(),
+		},
+	})
+	s.recording.mu.Unlock()
+}
+
+// saveRecording writes the run's tracks, events and coverage envelopes for the tile server
+func (s *DroneSwarmSimulation) saveRecording() error {
+	if !s.config.RecordRun {
+		return nil
+	}
+
+	features := s.coverageFeatures()
+	features = append(features, s.trackFeatures()...)
+	s.recording.mu.Lock()
+	features = append(features, s.recording.points...)
+	s.recording.mu.Unlock()
+	if len(features) == 0 {
+		return nil
+	}
+
+	if err := os.MkdirAll(reportsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create reports directory: %w", err)
+	}
+	path := filepath.Join(reportsDir, fmt.Sprintf("Recording_%s_%s.jsonl.gz", s.runID[:8], time.Now().Format("20060102_150405")))
+	w, err := tiles.Create(path)
+	if err != nil {
+		return err
+	}
+	for _, f := range features {
+		if err := w.Write(f); err != nil {
+			w.Close()
+			return fmt.Errorf("failed to write run recording: %w", err)
+		}
+	}
+	if err := w.Close(); err != nil {
+		return fmt.Errorf("failed to write run recording: %w", err)
+	}
+
+	s.artifacts = append(s.artifacts, path)
+	if s.aarGenerator != nil {
+		s.aarGenerator.AddAttachment(path)
+	}
+	logger.Successf("Run recording saved to: %s (legion-sim tiles serve %s)", path, path)
+	return nil
+}
+
+// trackFeatures returns each threat's flight path with its final state
+func (s *DroneSwarmSimulation) trackFeatures() []tiles.Feature {
+	s.mu.RLock()
+	threats := make(map[uuid.UUID]*UASThreat, len(s.uasThreats))
+	for id, threat := range s.uasThreats {
+		threats[id] = threat
+	}
+	s.mu.RUnlock()
+
+	s.recording.mu.Lock()
+	defer s.recording.mu.Unlock()
+
+	features := make([]tiles.Feature, 0, len(s.recording.tracks))
+	for id, track := range s.recording.tracks {
+		threat := threats[id]
+		if threat == nil || len(track.coords) < 2 {
+			continue // Never left the ground
+		}
+
+		threat.mu.RLock()
+		props := map[string]interface{}{
+			"track":      threat.TrackNumber,
+			"faction":    threat.ActualCapabilities.Faction,
+			"wave":       threat.ActualCapabilities.WaveNumber,
+			"size_class": threat.SizeClass,
+			"outcome":    threat.Classification,
+			"engaged":    threat.TimesTargeted,
+			"start":      track.start.Sub(s.scenarioStart).Seconds(),
+			"end":        track.end.Sub(s.scenarioStart).Seconds(),
+		}
+		if threat.LaunchSite != "" {
+			props["launch_site"] = threat.LaunchSite
+		}
+		threat.mu.RUnlock()
+
+		features = append(features, tiles.Feature{
+			Layer:       tiles.LayerTracks,
+			Type:        tiles.LineString,
+			Coordinates: track.coords,
+			Properties:  props,
+		})
+	}
+	sort.Slice(features, func(i, j int) bool {
+		return features[i].Properties["track"].(string) < features[j].Properties["track"].(string)
+	})
+	return features
+}
+
+// coverageFeatures returns each defender's sensor and weapon envelopes
+func (s *DroneSwarmSimulation) coverageFeatures() []tiles.Feature {
+	systems := s.coverageSystems()
+	sort.Slice(systems, func(i, j int) bool { return systems[i].Name < systems[j].Name })
+
+	var features []tiles.Feature
+	for _, system := range systems {
+		for _, envelope := range []struct {
+			kind    string
+			rangeKm float64
+		}{{"sensor", system.SensorRangeKm}, {"weapon", system.WeaponRangeKm}} {
+			if envelope.rangeKm <= 0 {
+				continue
+			}
+			features = append(features, tiles.Feature{
+				Layer:       tiles.LayerCoverage,
+				Type:        tiles.Polygon,
+				Coordinates: circleRing(system.Lat, system.Lon, envelope.rangeKm*1000, recordingRingPoints),
+				Properties: map[string]interface{}{
+					"system":   system.Name,
+					"envelope": envelope.kind,
+					"range_km": envelope.rangeKm,
+				},
+			})
+		}
+	}
+	return features
+}
+
+// circleRing approximates a circle as a closed lon/lat ring
+func circleRing(lat, lon, radiusM float64, points int) [][2]float64 {
+	const earthRadius = 6371000.0
+	ring := make([][2]float64, 0, points+1)
+	for i := 0; i <= points; i++ {
+		angle := 2 * math.Pi * float64(i%points) / float64(points)
+		dLat := radiusM * math.Cos(angle) / earthRadius * 180 / math.Pi
+		dLon := radiusM * math.Sin(angle) / (earthRadius * math.Cos(lat*math.Pi/180)) * 180 / math.Pi
+		ring = append(ring, [2]float64{lon + dLon, lat + dLat})
+	}
+	return ring
+}
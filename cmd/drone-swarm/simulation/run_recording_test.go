@@ -0,0 +1,44 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestRecordTrackVertexThinsPath(t *testing.T) {
+	s := &DroneSwarmSimulation{uasThreats: make(map[uuid.UUID]*UASThreat)}
+	s.config.RecordRun = true
+	s.scenarioStart = time.Unix(1_700_000_000, 0)
+
+	threat := &UASThreat{ID: uuid.New(), TrackNumber: "TK-1001", Classification: TrackStatusDestroyed}
+	s.uasThreats[threat.ID] = threat
+
+	// Northbound at 12m per second: only every other sample moves far enough for a vertex
+	for i := 0; i <= 10; i++ {
+		x, y, z := latLonAltToECEF(37.0+float64(i)*12/111320, -122.0, 300)
+		threat.Position = &models.GeomPoint{Coordinates: []float64{x, y, z}}
+		s.recordTrackVertex(threat, s.scenarioStart.Add(time.Duration(i)*time.Second))
+	}
+
+	features := s.trackFeatures()
+	if len(features) != 1 {
+		t.Fatalf("expected one track, got %d", len(features))
+	}
+	track := features[0]
+	if n := len(track.Coordinates); n != 6 {
+		t.Errorf("expected 6 vertices 24m apart, got %d", n)
+	}
+	if track.Properties["outcome"] != TrackStatusDestroyed || track.Properties["end"] != 10.0 {
+		t.Errorf("unexpected track properties %v", track.Properties)
+	}
+
+	s.config.RecordRun = false
+	s.recordTrackVertex(&UASThreat{ID: uuid.New(), Position: threat.Position}, s.scenarioStart)
+	if len(s.recording.tracks) != 1 {
+		t.Error("expected nothing recorded with recording disabled")
+	}
+}
@@ -0,0 +1,64 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// maxStartLag is how far past the scheduled start a process may join before it refuses to run
+const maxStartLag = 30 * time.Second
+
+// parseStartTime parses an absolute scenario start time.
+// Accepts RFC3339 timestamps or Unix seconds; an empty string means "start immediately".
+func parseStartTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+
+	if unix, err := strconv.ParseInt(value, 10, 64); err == nil {
+		return time.Unix(unix, 0), nil
+	}
+
+	return time.Time{}, fmt.Errorf("invalid start_time %q: expected RFC3339 (e.g. 2025-01-02T15:04:05Z) or Unix seconds", value)
+}
+
+// waitForStartTime blocks until the scheduled scenario start so that independently
+// launched processes begin their simulation clocks together. Returns the clock epoch.
+func (s *DroneSwarmSimulation) waitForStartTime(ctx context.Context) (time.Time, error) {
+	if s.config.StartTime.IsZero() {
+		return s.now(), nil
+	}
+
+	wait := s.config.StartTime.Sub(s.now())
+	if wait <= 0 {
+		lag := -wait
+		if lag > maxStartLag {
+			return time.Time{}, fmt.Errorf("scheduled start %s passed %s ago", s.config.StartTime.Format(time.RFC3339), lag.Round(time.Second))
+		}
+		logger.Warnf("Joining scheduled start %s late by %s", s.config.StartTime.Format(time.RFC3339), lag.Round(time.Millisecond))
+		return s.config.StartTime, nil
+	}
+
+	logger.Infof("%s Waiting %s for synchronized start at %s", logger.IconTime, wait.Round(time.Second), s.config.StartTime.Format(time.RFC3339))
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return time.Time{}, ctx.Err()
+	case <-s.stopChan:
+		return s.config.StartTime, nil
+	case <-timer.C:
+	}
+
+	return s.config.StartTime, nil
+}
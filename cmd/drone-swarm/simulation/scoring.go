@@ -0,0 +1,199 @@
+package simulation
+
+import (
+	"fmt"
+	"maps"
+	"math"
+	"sort"
+	"strings"
+)
+
+// Default leakage thresholds
+const (
+	DefaultAcceptableLeakage    = 0.3 // Fraction of all threats allowed to reach the base
+	DefaultCriticalAssetLeakers = 0   // Leakers tolerated on the critical asset (0 disables)
+	DefaultWaveLeakageThreshold = 0.0 // Fraction of a single wave allowed to leak (0 disables)
+)
+
+// leakageAxes are the compass sectors leakers are attributed to
+var leakageAxes = []string{"N", "NE", "E", "SE", "S", "SW", "W", "NW"}
+
+// LeakageScore breaks penetrations down by defended asset, wave, approach axis, payload and faction
+type LeakageScore struct {
+	ByAsset     map[string]int
+	ByWave      map[int]int
+	ByAxis      map[string]int
+	ByPayload   map[string]int
+	ByFaction   map[string]int
+	Consequence float64 // Penetrations weighted by payload lethality
+}
+
+// newLeakageScore creates an empty leakage score
+func newLeakageScore() LeakageScore {
+	return LeakageScore{
+		ByAsset:   make(map[string]int),
+		ByWave:    make(map[int]int),
+		ByAxis:    make(map[string]int),
+		ByPayload: make(map[string]int),
+		ByFaction: make(map[string]int),
+	}
+}
+
+// recordLeaker attributes a threat that reached a defended asset.
+// Caller must hold s.stats.mu.
+func (s *DroneSwarmSimulation) recordLeaker(threat *UASThreat, asset string) (axis string) {
+	if s.stats.Leakage.ByAsset == nil {
+		s.stats.Leakage = newLeakageScore()
+	}
+
+	axis = s.approachAxis(threat)
+	s.stats.Leakage.ByAsset[asset]++
+	s.stats.Leakage.ByWave[threat.ActualCapabilities.WaveNumber]++
+	s.stats.Leakage.ByAxis[axis]++
+	s.stats.Leakage.ByPayload[threat.ActualCapabilities.PayloadType]++
+	s.stats.Leakage.ByFaction[s.factionOf(threat).Name]++
+	s.stats.Leakage.Consequence += s.payloadProfile(threat.ActualCapabilities.PayloadType).Lethality
+	return axis
+}
+
+// approachAxis returns the compass sector, relative to the base, a threat came in on.
+// The bearing recorded at spawn is used so late evasive jinks don't skew the result;
+// a threat with none recorded is placed by its last known position.
+func (s *DroneSwarmSimulation) approachAxis(threat *UASThreat) string {
+	if threat.SpawnBearing != nil {
+		return compassAxis(*threat.SpawnBearing)
+	}
+
+	lat, lon := s.config.BaseLocation.Lat, s.config.BaseLocation.Lon
+	if threat.Position != nil {
+		lat, lon, _ = ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+	}
+	return compassAxis(bearingDegrees(s.config.BaseLocation.Lat, s.config.BaseLocation.Lon, lat, lon))
+}
+
+// compassAxis returns the compass sector a bearing falls in
+func compassAxis(bearing float64) string {
+	return leakageAxes[int(math.Mod(bearing+22.5, 360)/45)]
+}
+
+// bearingDegrees returns the initial great-circle bearing from one point to another
+func bearingDegrees(lat1, lon1, lat2, lon2 float64) float64 {
+	phi1 := lat1 * math.Pi / 180.0
+	phi2 := lat2 * math.Pi / 180.0
+	dLambda := (lon2 - lon1) * math.Pi / 180.0
+
+	y := math.Sin(dLambda) * math.Cos(phi2)
+	x := math.Cos(phi1)*math.Sin(phi2) - math.Sin(phi1)*math.Cos(phi2)*math.Cos(dLambda)
+
+	bearing := math.Atan2(y, x) * 180.0 / math.Pi
+	if bearing < 0 {
+		bearing += 360
+	}
+	return bearing
+}
+
+// evaluateLeakage checks leakage against the configured asset, wave and global thresholds.
+// Returns a non-empty outcome when the scenario should end in failure.
+// Caller must hold s.stats.mu.
+func (s *DroneSwarmSimulation) evaluateLeakage() string {
+	// Critical asset: fail once it takes the configured number of leakers
+	if s.config.CriticalAssetLeakers > 0 {
+		if hits := s.stats.Leakage.ByAsset[BaseAssetName]; hits >= s.config.CriticalAssetLeakers {
+			return fmt.Sprintf("FAILURE - Critical asset %s took %d leakers", BaseAssetName, hits)
+		}
+	}
+
+	penetrated, byWave := s.runLeakers()
+
+	// Per-wave: fail if any single wave leaks more than allowed
+	if s.config.WaveLeakageThreshold > 0 {
+		waveSizes := s.waveSizes()
+		for wave, leakers := range byWave {
+			size := waveSizes[wave]
+			if size == 0 {
+				continue
+			}
+			if rate := float64(leakers) / float64(size); rate > s.config.WaveLeakageThreshold {
+				return fmt.Sprintf("FAILURE - %.0f%% of wave %d penetrated defenses", rate*100, wave)
+			}
+		}
+	}
+
+	// Global: fail once overall leakage exceeds the acceptable rate
+	if threats := s.plannedThreats(); s.config.AcceptableLeakage < 1.0 && threats > 0 {
+		rate := float64(penetrated) / float64(threats)
+		if rate > s.config.AcceptableLeakage {
+			return fmt.Sprintf("FAILURE - %.0f%% of threats penetrated defenses", rate*100)
+		}
+	}
+
+	return ""
+}
+
+// runLeakers returns the leakers across the whole run: this shard's own and those the
+// coordinator last reported for the other shards. Caller must hold s.stats.mu.
+func (s *DroneSwarmSimulation) runLeakers() (penetrated int, byWave map[int]int) {
+	byWave = maps.Clone(s.stats.Leakage.ByWave)
+	if byWave == nil {
+		byWave = make(map[int]int)
+	}
+	for wave, n := range s.shardLeakage.ByWave {
+		byWave[wave] += n
+	}
+	return s.stats.UASPenetrated + s.shardLeakage.Penetrated, byWave
+}
+
+// waveSizes returns the number of threats launched in each wave, counting the proxies
+// of other shards' tracks for the waves they own
+func (s *DroneSwarmSimulation) waveSizes() map[int]int {
+	sizes := make(map[int]int)
+	for _, threat := range s.uasThreats {
+		sizes[threat.ActualCapabilities.WaveNumber]++
+	}
+	return sizes
+}
+
+// leakageSummary formats the leakage breakdown for logging
+func (l LeakageScore) leakageSummary() string {
+	if len(l.ByWave) == 0 {
+		return "no leakers"
+	}
+
+	waves := make([]int, 0, len(l.ByWave))
+	for wave := range l.ByWave {
+		waves = append(waves, wave)
+	}
+	sort.Ints(waves)
+
+	var parts []string
+	for _, wave := range waves {
+		parts = append(parts, fmt.Sprintf("W%d=%d", wave, l.ByWave[wave]))
+	}
+	for _, axis := range leakageAxes {
+		if n := l.ByAxis[axis]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", axis, n))
+		}
+	}
+	payloads := make([]string, 0, len(l.ByPayload))
+	for payload := range l.ByPayload {
+		payloads = append(payloads, payload)
+	}
+	sort.Strings(payloads) // Data packs may add payload types beyond the default catalog
+	for _, payload := range payloads {
+		if n := l.ByPayload[payload]; n > 0 {
+			parts = append(parts, fmt.Sprintf("%s=%d", payload, n))
+		}
+	}
+	if len(l.ByFaction) > 1 {
+		factions := make([]string, 0, len(l.ByFaction))
+		for faction := range l.ByFaction {
+			factions = append(factions, faction)
+		}
+		sort.Strings(factions)
+		for _, faction := range factions {
+			parts = append(parts, fmt.Sprintf("%s=%d", faction, l.ByFaction[faction]))
+		}
+	}
+	parts = append(parts, fmt.Sprintf("consequence=%.1f", l.Consequence))
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,116 @@
+package simulation
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestEvaluateLeakage(t *testing.T) {
+	newSim := func(cfg SimulationConfig) *DroneSwarmSimulation {
+		s := &DroneSwarmSimulation{config: cfg, uasThreats: make(map[uuid.UUID]*UASThreat)}
+		for i := 0; i < 10; i++ {
+			threat := &UASThreat{ID: uuid.New(), ActualCapabilities: SimulatedCapabilities{WaveNumber: i%2 + 1}}
+			s.uasThreats[threat.ID] = threat
+		}
+		s.stats.Leakage = newLeakageScore()
+		return s
+	}
+
+	tests := []struct {
+		name     string
+		cfg      SimulationConfig
+		leakers  map[int]int
+		expected string
+	}{
+		{
+			name:    "within acceptable leakage",
+			cfg:     SimulationConfig{NumUASThreats: 10, AcceptableLeakage: 0.3},
+			leakers: map[int]int{1: 2, 2: 1},
+		},
+		{
+			name:     "global leakage exceeded",
+			cfg:      SimulationConfig{NumUASThreats: 10, AcceptableLeakage: 0.3},
+			leakers:  map[int]int{1: 2, 2: 2},
+			expected: "40% of threats",
+		},
+		{
+			name:    "global check disabled",
+			cfg:     SimulationConfig{NumUASThreats: 10, AcceptableLeakage: 1.0},
+			leakers: map[int]int{1: 5, 2: 5},
+		},
+		{
+			name:     "single wave exceeded",
+			cfg:      SimulationConfig{NumUASThreats: 10, AcceptableLeakage: 1.0, WaveLeakageThreshold: 0.5},
+			leakers:  map[int]int{2: 3},
+			expected: "wave 2",
+		},
+		{
+			name:     "critical asset hit",
+			cfg:      SimulationConfig{NumUASThreats: 10, AcceptableLeakage: 1.0, CriticalAssetLeakers: 2},
+			leakers:  map[int]int{1: 1, 2: 1},
+			expected: "Critical asset",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newSim(tt.cfg)
+			for wave, n := range tt.leakers {
+				s.stats.Leakage.ByWave[wave] = n
+				s.stats.Leakage.ByAsset[BaseAssetName] += n
+				s.stats.UASPenetrated += n
+			}
+
+			outcome := s.evaluateLeakage()
+			if tt.expected == "" && outcome != "" {
+				t.Fatalf("expected no failure, got %q", outcome)
+			}
+			if !strings.Contains(outcome, tt.expected) {
+				t.Fatalf("expected outcome containing %q, got %q", tt.expected, outcome)
+			}
+		})
+	}
+}
+
+func TestBearingDegrees(t *testing.T) {
+	if b := bearingDegrees(40, -76, 41, -76); b > 0.01 && b < 359.99 {
+		t.Errorf("expected due north, got %.2f", b)
+	}
+	if b := bearingDegrees(40, -76, 40, -75); b < 89 || b > 91 {
+		t.Errorf("expected roughly due east, got %.2f", b)
+	}
+}
+
+func TestApproachAxisUsesSpawnBearing(t *testing.T) {
+	s := &DroneSwarmSimulation{config: SimulationConfig{
+		BaseLocation:      Location{Lat: 40, Lon: -76, Alt: 100},
+		TrackHistoryDepth: 2,
+	}}
+	at := func(lat, lon float64) *models.GeomPoint {
+		x, y, z := latLonAltToECEF(lat, lon, 200)
+		return &models.GeomPoint{Coordinates: []float64{x, y, z}}
+	}
+
+	// Spawned north of the base, then swings around to the south until the rolling
+	// history no longer holds the spawn point
+	threat := &UASThreat{ID: uuid.New(), Position: at(40.1, -76)}
+	s.recordTrackHistory(threat)
+	for _, lat := range []float64{40.05, 39.99, 39.98, 39.97} {
+		threat.Position = at(lat, -76.001)
+		s.recordTrackHistory(threat)
+	}
+
+	if axis := s.approachAxis(threat); axis != "N" {
+		t.Errorf("expected the northern spawn axis, got %s", axis)
+	}
+
+	// No recorded spawn falls back to the last known position
+	threat.SpawnBearing = nil
+	if axis := s.approachAxis(threat); axis != "S" {
+		t.Errorf("expected the last position's axis, got %s", axis)
+	}
+}
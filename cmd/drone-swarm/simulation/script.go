@@ -0,0 +1,209 @@
+package simulation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// SystemFailure takes a Counter-UAS system offline at a scenario time, for good or for
+// a while
+type SystemFailure struct {
+	System   string        // Defense site or entity name, e.g. "North Gun" or "Counter-UAS-03"
+	At       time.Duration // From the scenario start
+	Duration time.Duration // Until the system comes back (0 never)
+
+	entity string // Entity name System resolves to
+}
+
+// scriptState tracks which scripted events have happened
+type scriptState struct {
+	launched map[int]bool // Scheduled waves released
+	failed   []bool       // By index in SystemFailures
+	restored []bool
+}
+
+// parseWaveTimes parses comma-separated launch times for waves 1, 2, ... measured
+// from the scenario start, e.g. "0s,90s,4m"
+func parseWaveTimes(spec string) ([]time.Duration, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	var times []time.Duration
+	for i, part := range strings.Split(spec, ",") {
+		at, err := time.ParseDuration(strings.TrimSpace(part))
+		if err != nil {
+			return nil, fmt.Errorf("wave %d: invalid time %q", i+1, part)
+		}
+		if at < 0 {
+			return nil, fmt.Errorf("wave %d: time cannot be negative", i+1)
+		}
+		times = append(times, at)
+	}
+	return times, nil
+}
+
+// parseSystemFailures parses "system:at[:duration]" entries separated by semicolons,
+// e.g. "North Gun:3m;Counter-UAS-04:5m:90s"
+func parseSystemFailures(spec string) ([]SystemFailure, error) {
+	var failures []SystemFailure
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ":")
+		if len(fields) != 2 && len(fields) != 3 {
+			return nil, fmt.Errorf("system failure %q: expected system:at[:duration]", entry)
+		}
+
+		failure := SystemFailure{System: strings.TrimSpace(fields[0])}
+		if failure.System == "" {
+			return nil, fmt.Errorf("system failure %q: system is required", entry)
+		}
+		var err error
+		if failure.At, err = time.ParseDuration(strings.TrimSpace(fields[1])); err != nil || failure.At < 0 {
+			return nil, fmt.Errorf("system failure %s: invalid time %q", failure.System, fields[1])
+		}
+		if len(fields) == 3 {
+			if failure.Duration, err = time.ParseDuration(strings.TrimSpace(fields[2])); err != nil || failure.Duration <= 0 {
+				return nil, fmt.Errorf("system failure %s: invalid duration %q", failure.System, fields[2])
+			}
+		}
+		failures = append(failures, failure)
+	}
+	return failures, nil
+}
+
+// validateScript checks scheduled waves cover every wave without clashing with acts,
+// and resolves each system failure to a system the run deploys
+func (c *SimulationConfig) validateScript() error {
+	if len(c.WaveTimes) > 0 {
+		if len(c.WaveTimes) != c.NumWaves {
+			return fmt.Errorf("wave_times gives %d times for %d waves", len(c.WaveTimes), c.NumWaves)
+		}
+		for _, act := range c.Acts {
+			if len(act.Waves) > 0 {
+				return fmt.Errorf("wave_times and act %s both schedule waves; use one", act.Name)
+			}
+		}
+	}
+
+	names := make(map[string]string)
+	for _, system := range c.systemPlan() {
+		names[system.Name] = system.Name
+		if system.Site != nil {
+			names[system.Site.Name] = system.Name
+		}
+	}
+	for i := range c.SystemFailures {
+		failure := &c.SystemFailures[i]
+		entity, ok := names[failure.System]
+		if !ok {
+			entity, ok = names[counterUASEntityPrefix+failure.System]
+		}
+		if !ok {
+			return fmt.Errorf("system failure names %s, which the run does not deploy", failure.System)
+		}
+		failure.entity = entity
+	}
+	return nil
+}
+
+// advanceScript fires the scripted events due by the current scenario time: scheduled
+// wave launches and system failures and recoveries
+func (s *DroneSwarmSimulation) advanceScript() {
+	elapsed := s.scenarioElapsed()
+	if len(s.config.WaveTimes) > 0 {
+		s.launchScheduledWaves(elapsed)
+	}
+
+	if s.script.failed == nil {
+		s.script.failed = make([]bool, len(s.config.SystemFailures))
+		s.script.restored = make([]bool, len(s.config.SystemFailures))
+	}
+	for i, failure := range s.config.SystemFailures {
+		system := s.systemNamed(s.named(failure.entity))
+		if system == nil {
+			continue // Deployed by another shard
+		}
+		if !s.script.failed[i] && elapsed >= failure.At {
+			s.script.failed[i] = true
+			system.mu.Lock()
+			system.Status = CounterUASStatusOffline
+			system.DataLinkStatus = "OFFLINE"
+			system.EngagedTarget = nil
+			system.CurrentTargets = nil
+			system.mu.Unlock()
+			engagementLog.Errorf("💥 %s (%s) failed at T+%s - system offline", system.Callsign, system.Name, failure.At)
+			s.simLogger.LogSystemFailure(system.ID, system.Name, true, failure.Duration)
+			s.updateBuffer.QueueStatusUpdate(system.ID, system.Status)
+		}
+		if failure.Duration > 0 && s.script.failed[i] && !s.script.restored[i] && elapsed >= failure.At+failure.Duration {
+			s.script.restored[i] = true
+			system.mu.Lock()
+			system.Status = CounterUASStatusIdle
+			if system.EngagementType == EngagementTypeKinetic && system.AmmoRemaining == 0 {
+				system.Status = CounterUASStatusOffline
+			}
+			system.DataLinkStatus = "ONLINE"
+			system.mu.Unlock()
+			logger.Infof("🔧 %s (%s) back online after %s", system.Callsign, system.Name, failure.Duration)
+			s.simLogger.LogSystemFailure(system.ID, system.Name, false, failure.Duration)
+			s.updateBuffer.QueueStatusUpdate(system.ID, system.Status)
+		}
+	}
+}
+
+// launchScheduledWaves releases held threats whose wave_times entry has come
+func (s *DroneSwarmSimulation) launchScheduledWaves(elapsed time.Duration) {
+	if s.script.launched == nil {
+		s.script.launched = make(map[int]bool)
+	}
+
+	released := make(map[int][]*UASThreat)
+	for _, threat := range s.uasThreats {
+		wave := threat.ActualCapabilities.WaveNumber
+		if threat.ActHeld && s.waveLaunchOffset(wave) <= elapsed {
+			threat.ActHeld = false
+			s.headForObjective(threat)
+			released[wave] = append(released[wave], threat)
+		}
+	}
+
+	waves := make([]int, 0, len(released))
+	for wave := range released {
+		waves = append(waves, wave)
+	}
+	sort.Ints(waves)
+	for _, wave := range waves {
+		if s.script.launched[wave] {
+			continue
+		}
+		s.script.launched[wave] = true
+		threats := released[wave]
+		logger.Infof("🚀 Wave %d launched at T+%s: %d drones", wave, s.waveLaunchOffset(wave), len(threats))
+		s.simLogger.LogWaveLaunch(s.factionOf(threats[0]).Name, wave, len(threats), map[string]interface{}{
+			"scheduled_secs": s.waveLaunchOffset(wave).Seconds(),
+		})
+	}
+}
+
+// systemNamed returns the Counter-UAS system created under a planned entity name, which
+// may carry a timestamp suffix when unique names are in use
+func (s *DroneSwarmSimulation) systemNamed(name string) *CounterUASSystem {
+	for _, system := range s.counterUASSystems {
+		if system.Name == name {
+			return system
+		}
+		if suffix, ok := strings.CutPrefix(system.Name, name+"-"); ok && suffix != "" && strings.IndexFunc(suffix, func(r rune) bool { return !unicode.IsDigit(r) }) < 0 {
+			return system
+		}
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestParseDefenseSites(t *testing.T) {
+	sites, err := parseDefenseSites("North Gun:kinetic:1200:-10; Jammer:EW:400:180;")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(sites) != 2 || sites[0].BearingDeg != 350 || sites[1].EngagementType != EngagementTypeEW {
+		t.Fatalf("unexpected sites: %+v", sites)
+	}
+
+	for _, spec := range []string{"A:kinetic:100", ":kinetic:100:0", "A:laser:100:0", "A:ew:x:0", "A:ew:-1:0", "A:ew:1:0;A:kinetic:2:0"} {
+		if _, err := parseDefenseSites(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+
+	cfg := SimulationConfig{DefenseSites: sites, NumCounterUASSystems: 2}
+	plan := cfg.systemPlan()
+	if plan[0].Name != "Counter-UAS-North Gun" || plan[0].Site != &cfg.DefenseSites[0] {
+		t.Errorf("expected the first system posted at North Gun, got %+v", plan[0])
+	}
+	cfg.DefenseSites = nil
+	if plan = cfg.systemPlan(); plan[1].Name != "Counter-UAS-02" || plan[1].EngagementType != EngagementTypeEW || plan[1].Site != nil {
+		t.Errorf("expected ring systems alternating kinetic and EW, got %+v", plan)
+	}
+}
+
+func TestParseScript(t *testing.T) {
+	times, err := parseWaveTimes("0s, 90s,4m")
+	if err != nil || len(times) != 3 || times[2] != 4*time.Minute {
+		t.Fatalf("unexpected wave times %v: %v", times, err)
+	}
+	for _, spec := range []string{"0s,soon", "-1m"} {
+		if _, err := parseWaveTimes(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+
+	failures, err := parseSystemFailures("North Gun:3m; Counter-UAS-02:5m:90s")
+	if err != nil || len(failures) != 2 || failures[1].Duration != 90*time.Second || failures[0].Duration != 0 {
+		t.Fatalf("unexpected failures %+v: %v", failures, err)
+	}
+	for _, spec := range []string{"North Gun", ":3m", "A:later", "A:3m:0s"} {
+		if _, err := parseSystemFailures(spec); err == nil {
+			t.Errorf("expected error for %q", spec)
+		}
+	}
+}
+
+func TestValidateScript(t *testing.T) {
+	sites, _ := parseDefenseSites("North Gun:kinetic:1200:0;Jammer:ew:400:180")
+	cfg := SimulationConfig{
+		DefenseSites:   sites,
+		NumWaves:       2,
+		WaveTimes:      []time.Duration{0, time.Minute},
+		SystemFailures: []SystemFailure{{System: "Jammer"}, {System: "Counter-UAS-North Gun"}},
+	}
+	if err := cfg.validateScript(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if cfg.SystemFailures[0].entity != "Counter-UAS-Jammer" || cfg.SystemFailures[1].entity != "Counter-UAS-North Gun" {
+		t.Errorf("expected failures resolved to entity names, got %+v", cfg.SystemFailures)
+	}
+
+	bad := []SimulationConfig{
+		{DefenseSites: sites, NumWaves: 3, WaveTimes: cfg.WaveTimes},
+		{DefenseSites: sites, NumWaves: 2, WaveTimes: cfg.WaveTimes, Acts: []ScenarioAct{{Name: "Strike", Waves: []int{2}}}},
+		{DefenseSites: sites, SystemFailures: []SystemFailure{{System: "Counter-UAS-01"}}},
+		{NumCounterUASSystems: 2, SystemFailures: []SystemFailure{{System: "03"}}},
+	}
+	for i, c := range bad {
+		if err := c.validateScript(); err == nil {
+			t.Errorf("case %d: expected error", i)
+		}
+	}
+}
+
+func TestSystemNamed(t *testing.T) {
+	s := &DroneSwarmSimulation{counterUASSystems: make(map[uuid.UUID]*CounterUASSystem)}
+	for _, name := range []string{"Counter-UAS-North-1712345678", "Counter-UAS-North-Gun", "Counter-UAS-03"} {
+		s.counterUASSystems[uuid.New()] = &CounterUASSystem{Name: name}
+	}
+
+	tests := map[string]string{
+		"Counter-UAS-North":     "Counter-UAS-North-1712345678",
+		"Counter-UAS-North-Gun": "Counter-UAS-North-Gun",
+		"Counter-UAS-03":        "Counter-UAS-03",
+		"Counter-UAS-04":        "",
+	}
+	for name, want := range tests {
+		got := ""
+		if system := s.systemNamed(name); system != nil {
+			got = system.Name
+		}
+		if got != want {
+			t.Errorf("systemNamed(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
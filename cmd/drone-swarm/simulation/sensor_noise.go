@@ -0,0 +1,123 @@
+package simulation
+
+import (
+	"math"
+	"math/rand"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// DefaultSensorNoise scales the sensor error models; 0 publishes ground truth
+const DefaultSensorNoise = 1.0
+
+// Sensor types a track position can be measured by
+const (
+	SensorRadar = "RADAR"
+	SensorEOIR  = "EO/IR"
+	SensorRF    = "RF"
+)
+
+// sensorError is a sensor's 1-sigma measurement error. Range error grows with distance;
+// bearing error is angular, so its cross-range effect grows with distance too.
+type sensorError struct {
+	RangeM        float64 // Fixed range error, meters
+	RangeFraction float64 // Additional range error per meter of range
+	BearingDeg    float64 // Azimuth and elevation error
+}
+
+// sensorErrors model a pulse-Doppler radar, a passively-ranging EO/IR turret and an RF
+// direction finder: radar ranges well, EO/IR points well but ranges poorly, and RF does
+// both poorly
+var sensorErrors = map[string]sensorError{
+	SensorRadar: {RangeM: 5, RangeFraction: 0.002, BearingDeg: 0.3},
+	SensorEOIR:  {RangeM: 15, RangeFraction: 0.01, BearingDeg: 0.05},
+	SensorRF:    {RangeM: 50, RangeFraction: 0.05, BearingDeg: 2.0},
+}
+
+// expectedError is the sensor's typical position error in meters at rangeM
+func (e sensorError) expectedError(rangeM float64) float64 {
+	rangeErr := e.RangeM + e.RangeFraction*rangeM
+	crossErr := rangeM * e.BearingDeg * math.Pi / 180
+	return math.Hypot(rangeErr, crossErr)
+}
+
+// measuringSensor picks the most accurate sensor observing the threat, with the same
+// range rules as detection. When nothing holds it, the nearest system's radar stands in
+// as the long-range cue that created the track.
+func (s *DroneSwarmSimulation) measuringSensor(threat *UASThreat) (*CounterUASSystem, sensorError) {
+	var best, nearest *CounterUASSystem
+	var bestErr sensorError
+	bestExpected, nearestKm := math.Inf(1), math.Inf(1)
+
+	for _, system := range s.counterUASSystems {
+		if system.Status == CounterUASStatusOffline || system.Position == nil {
+			continue
+		}
+		distanceKm := calculateDistanceKm(system.Position, threat.Position)
+		if distanceKm < nearestKm {
+			nearest, nearestKm = system, distanceKm
+		}
+
+		candidates := make([]string, 0, 3)
+		if distanceKm <= system.RadarRange {
+			candidates = append(candidates, SensorRadar)
+		}
+		if distanceKm <= system.EOIRRange && threat.ThermalSignature {
+			candidates = append(candidates, SensorEOIR)
+		}
+		if distanceKm <= system.RFDetectionRange && threat.RFEmitting {
+			candidates = append(candidates, SensorRF)
+		}
+		for _, sensor := range candidates {
+			model := sensorErrors[sensor]
+			if expected := model.expectedError(distanceKm * 1000); expected < bestExpected {
+				best, bestErr, bestExpected = system, model, expected
+			}
+		}
+	}
+
+	if best == nil {
+		return nearest, sensorErrors[SensorRadar]
+	}
+	return best, bestErr
+}
+
+// observedPosition returns the threat's position as a sensor would report it: the true
+// position perturbed by range and bearing error from the measuring sensor. The truth
+// stays on the threat for the simulation; only the observed position is published.
+func (s *DroneSwarmSimulation) observedPosition(threat *UASThreat) *models.GeomPoint {
+	observed := &models.GeomPoint{Type: threat.Position.Type, Coordinates: append([]float64(nil), threat.Position.Coordinates...)}
+	if s.config.SensorNoise > 0 {
+		if sensor, model := s.measuringSensor(threat); sensor != nil {
+			applySensorError(observed.Coordinates, sensor.Position.Coordinates, model, s.config.SensorNoise, threat.random())
+		}
+	}
+
+	if threat.ObservedHistory == nil {
+		threat.ObservedHistory = NewTrackHistory(s.config.TrackHistoryDepth)
+	}
+	threat.ObservedHistory.Record(observed, time.Now())
+	return observed
+}
+
+// applySensorError perturbs target in place as measured from sensor, drawing the error
+// from rng. Following the simulation's local frame, X/Y are horizontal and Z is height.
+func applySensorError(target, sensor []float64, model sensorError, scale float64, rng *rand.Rand) {
+	dx, dy, dz := target[0]-sensor[0], target[1]-sensor[1], target[2]-sensor[2]
+	horizontal := math.Hypot(dx, dy)
+	rangeM := math.Sqrt(horizontal*horizontal + dz*dz)
+	if rangeM == 0 {
+		return
+	}
+
+	bearingSigma := model.BearingDeg * math.Pi / 180 * scale
+	measuredRange := rangeM + rng.NormFloat64()*(model.RangeM+model.RangeFraction*rangeM)*scale
+	azimuth := math.Atan2(dy, dx) + rng.NormFloat64()*bearingSigma
+	elevation := math.Atan2(dz, horizontal) + rng.NormFloat64()*bearingSigma
+
+	measuredHorizontal := measuredRange * math.Cos(elevation)
+	target[0] = sensor[0] + measuredHorizontal*math.Cos(azimuth)
+	target[1] = sensor[1] + measuredHorizontal*math.Sin(azimuth)
+	target[2] = sensor[2] + measuredRange*math.Sin(elevation)
+}
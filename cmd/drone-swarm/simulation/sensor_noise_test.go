@@ -0,0 +1,34 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+)
+
+func TestSensorErrorGrowsWithRange(t *testing.T) {
+	rmsError := func(model sensorError, rangeM, scale float64) float64 {
+		var sum float64
+		const n = 2000
+		for i := 0; i < n; i++ {
+			target := []float64{rangeM, 0, 100}
+			applySensorError(target, []float64{0, 0, 100}, model, scale, unseeded)
+			sum += (target[0]-rangeM)*(target[0]-rangeM) + target[1]*target[1] + (target[2]-100)*(target[2]-100)
+		}
+		return math.Sqrt(sum / n)
+	}
+
+	radar := sensorErrors[SensorRadar]
+	near, far := rmsError(radar, 1000, 1), rmsError(radar, 10000, 1)
+	if far <= near {
+		t.Errorf("expected radar error to grow with range, got %.1fm at 1km and %.1fm at 10km", near, far)
+	}
+	if expected := radar.expectedError(10000); far < expected*0.8 || far > expected*1.6 {
+		t.Errorf("expected ~%.0fm error at 10km, got %.1fm", expected, far)
+	}
+	if rf := rmsError(sensorErrors[SensorRF], 10000, 1); rf <= far {
+		t.Errorf("expected RF to be less accurate than radar, got %.1fm vs %.1fm", rf, far)
+	}
+	if truth := rmsError(radar, 10000, 0); truth > 1e-6 {
+		t.Errorf("expected no error at zero scale, got %.6fm", truth)
+	}
+}
@@ -0,0 +1,251 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"maps"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/shard"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// trackNumbersPerShard spaces track numbers so shards never collide on entity names
+const trackNumbersPerShard = 10000
+
+// sharded reports whether this process is part of a multi-process run
+func (s *DroneSwarmSimulation) sharded() bool {
+	return s.config.ShardRole != shard.RoleStandalone && s.config.ShardCount > 1
+}
+
+// ownsWave reports whether this shard simulates the given wave
+func (s *DroneSwarmSimulation) ownsWave(wave int) bool {
+	if !s.sharded() {
+		return true
+	}
+	return shard.OwnerOfWave(wave, s.config.ShardCount) == s.config.ShardIndex
+}
+
+// ownsBlueForce reports whether this shard simulates the Counter-UAS systems.
+// Blue force always lives on the coordinator so engagements resolve in one place.
+func (s *DroneSwarmSimulation) ownsBlueForce() bool {
+	return !s.sharded() || s.config.ShardRole == shard.RoleCoordinator
+}
+
+// validateSharding checks the shard settings for consistency
+func (c *SimulationConfig) validateSharding() error {
+	switch c.ShardRole {
+	case shard.RoleStandalone:
+		return nil
+	case shard.RoleCoordinator:
+		if c.ShardIndex != 0 {
+			return fmt.Errorf("coordinator must be shard_index 0")
+		}
+	case shard.RoleWorker:
+		if c.ShardIndex < 1 || c.ShardIndex >= c.ShardCount {
+			return fmt.Errorf("worker shard_index must be between 1 and shard_count-1 (%d)", c.ShardCount-1)
+		}
+		if c.ShardCoordinatorURL == "" {
+			return fmt.Errorf("shard_coordinator_url is required for workers")
+		}
+	default:
+		return fmt.Errorf("invalid shard_role %q (must be standalone, coordinator, or worker)", c.ShardRole)
+	}
+
+	if c.ShardCount < 2 {
+		return fmt.Errorf("shard_count must be at least 2 for a sharded run")
+	}
+	if c.NumWaves < c.ShardCount {
+		return fmt.Errorf("waves (%d) must be at least shard_count (%d) so every shard owns a wave", c.NumWaves, c.ShardCount)
+	}
+	return nil
+}
+
+// startSharding brings up the coordinator hub or the worker's coordinator client
+func (s *DroneSwarmSimulation) startSharding() error {
+	if !s.sharded() {
+		return nil
+	}
+
+	switch s.config.ShardRole {
+	case shard.RoleCoordinator:
+		s.shardHub = shard.NewHub(s.config.ShardToken)
+		s.shardServer = &http.Server{
+			Addr:              s.config.ShardListenAddr,
+			Handler:           s.shardHub.Handler(),
+			ReadHeaderTimeout: 5 * time.Second,
+		}
+		go func() {
+			if err := s.shardServer.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				logger.Errorf("Shard coordinator stopped: %v", err)
+			}
+		}()
+		if s.config.ShardToken == "" {
+			logger.Warnf("Shard coordinator on %s has no shard_token; anyone who can reach it can inject tracks and kills", s.config.ShardListenAddr)
+		}
+		logger.Infof("%s Shard coordinator listening on %s (%d shards)", logger.IconNetwork, s.config.ShardListenAddr, s.config.ShardCount)
+	case shard.RoleWorker:
+		s.shardClient = shard.NewClient(s.config.ShardCoordinatorURL, s.config.ShardToken)
+		logger.Infof("%s Shard worker %d/%d syncing with %s", logger.IconNetwork, s.config.ShardIndex, s.config.ShardCount, s.config.ShardCoordinatorURL)
+	}
+
+	// Keep track numbers unique across shards
+	offset := uint32(s.config.ShardIndex * trackNumbersPerShard)
+	if atomic.LoadUint32(&trackNumberCounter) < offset {
+		atomic.StoreUint32(&trackNumberCounter, offset)
+	}
+
+	return nil
+}
+
+// stopSharding shuts down the coordinator hub if running
+func (s *DroneSwarmSimulation) stopSharding() {
+	if s.shardServer == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	if err := s.shardServer.Shutdown(ctx); err != nil {
+		logger.Debugf("Failed to shut down shard coordinator: %v", err)
+	}
+}
+
+// queueShardKill records a kill against a track owned by another shard
+func (s *DroneSwarmSimulation) queueShardKill(threat *UASThreat, system *CounterUASSystem) {
+	s.shardMu.Lock()
+	defer s.shardMu.Unlock()
+	s.pendingKills = append(s.pendingKills, shard.Kill{TrackID: threat.ID, By: system.Callsign})
+}
+
+// syncShards exchanges track state with the other shards (Phase 0)
+func (s *DroneSwarmSimulation) syncShards(ctx context.Context) error {
+	if !s.sharded() {
+		return nil
+	}
+
+	req := shard.SyncRequest{Shard: s.config.ShardIndex}
+	for _, threat := range s.uasThreats {
+		if threat.Remote {
+			continue
+		}
+		threat.mu.RLock()
+		req.Tracks = append(req.Tracks, shard.TrackSnapshot{
+			ID:                threat.ID,
+			TrackNumber:       threat.TrackNumber,
+			Wave:              threat.ActualCapabilities.WaveNumber,
+			Position:          [3]float64{threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2]},
+			Classification:    threat.Classification,
+			SizeClass:         threat.SizeClass,
+			RFEmitting:        threat.RFEmitting,
+			ThermalSignature:  threat.ThermalSignature,
+			EvasionCapability: threat.ActualCapabilities.EvasionCapability,
+			UpdatedAt:         threat.LastUpdateTime,
+		})
+		threat.mu.RUnlock()
+	}
+
+	s.stats.mu.RLock()
+	req.Leakage = shard.Leakage{Penetrated: s.stats.UASPenetrated, ByWave: maps.Clone(s.stats.Leakage.ByWave)}
+	s.stats.mu.RUnlock()
+
+	s.shardMu.Lock()
+	req.Kills = s.pendingKills
+	s.pendingKills = nil
+	s.shardMu.Unlock()
+
+	var resp *shard.SyncResponse
+	if s.shardHub != nil {
+		r := s.shardHub.Sync(req)
+		resp = &r
+	} else {
+		syncCtx, cancel := context.WithTimeout(ctx, 2*time.Second)
+		defer cancel()
+		var err error
+		resp, err = s.shardClient.Sync(syncCtx, req)
+		if err != nil {
+			// Put kills back so they are delivered on the next sync
+			s.shardMu.Lock()
+			s.pendingKills = append(req.Kills, s.pendingKills...)
+			s.shardMu.Unlock()
+			return err
+		}
+	}
+
+	s.applyRemoteTracks(resp.Tracks)
+	s.applyRemoteKills(resp.Kills)
+	s.stats.mu.Lock()
+	s.shardLeakage = resp.Leakage
+	s.stats.mu.Unlock()
+	return nil
+}
+
+// applyRemoteTracks creates or refreshes local proxies for tracks owned by other shards
+func (s *DroneSwarmSimulation) applyRemoteTracks(tracks []shard.TrackSnapshot) {
+	pointType := "Point"
+	for _, snap := range tracks {
+		s.mu.Lock()
+		threat, exists := s.uasThreats[snap.ID]
+		if !exists {
+			threat = &UASThreat{
+				ID:                snap.ID,
+				TrackNumber:       snap.TrackNumber,
+				Classification:    TrackStatusPending,
+				Affiliation:       models.AffiliationPENDING,
+				Position:          &models.GeomPoint{Type: &pointType, Coordinates: make([]float64, 3)},
+				ObservedBehavior:  BehaviorUnknown,
+				ThreatLevel:       3,
+				SizeClass:         snap.SizeClass,
+				RFEmitting:        snap.RFEmitting,
+				ThermalSignature:  snap.ThermalSignature,
+				ActualVelocity:    &models.GeomPoint{Type: &pointType, Coordinates: make([]float64, 3)},
+				History:           NewTrackHistory(s.config.TrackHistoryDepth),
+				Remote:            true,
+				OwnerShard:        snap.Shard,
+				LastSeenTime:      time.Now(),
+				EstimatedAltitude: snap.Position[2],
+			}
+			threat.ActualCapabilities.WaveNumber = snap.Wave
+			threat.ActualCapabilities.EvasionCapability = snap.EvasionCapability
+			s.uasThreats[snap.ID] = threat
+		}
+		s.mu.Unlock()
+
+		if !threat.Remote {
+			continue // Never let another shard overwrite a track we own
+		}
+
+		threat.mu.Lock()
+		copy(threat.Position.Coordinates, snap.Position[:])
+		threat.LastUpdateTime = snap.UpdatedAt
+		terminal := (snap.Classification == TrackStatusDestroyed || snap.Classification == TrackStatusLost) &&
+			threat.Classification != TrackStatusDestroyed && threat.Classification != TrackStatusLost
+		threat.mu.Unlock()
+
+		// The owner decides when a track leaves the battlespace; classification otherwise stays ours
+		if terminal {
+			threat.UpdateClassification(snap.Classification)
+		}
+		threat.History.Record(threat.Position, snap.UpdatedAt)
+		s.recordTrackVertex(threat, snap.UpdatedAt)
+	}
+}
+
+// applyRemoteKills marks our own tracks destroyed by other shards' engagements
+func (s *DroneSwarmSimulation) applyRemoteKills(kills []shard.Kill) {
+	for _, kill := range kills {
+		s.mu.RLock()
+		threat, exists := s.uasThreats[kill.TrackID]
+		s.mu.RUnlock()
+		if !exists || threat.Remote || threat.Classification == TrackStatusDestroyed {
+			continue
+		}
+
+		threat.UpdateClassification(TrackStatusDestroyed)
+		s.queueClassificationUpdate(threat)
+		logger.Infof("💥 Track %s destroyed by %s on shard %d", threat.TrackNumber, kill.By, kill.ByShard)
+	}
+}
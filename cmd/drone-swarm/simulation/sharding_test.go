@@ -0,0 +1,78 @@
+package simulation
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/shard"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestLeakageSumsAcrossShards(t *testing.T) {
+	hub := shard.NewHub("")
+	server := httptest.NewServer(hub.Handler())
+	defer server.Close()
+
+	newShard := func(role string, index, wave int) *DroneSwarmSimulation {
+		s := &DroneSwarmSimulation{
+			config: SimulationConfig{
+				NumUASThreats:        10,
+				NumWaves:             2,
+				AcceptableLeakage:    0.3,
+				WaveLeakageThreshold: 0.5,
+				ShardRole:            role,
+				ShardIndex:           index,
+				ShardCount:           2,
+				TrackHistoryDepth:    4,
+			},
+			uasThreats: make(map[uuid.UUID]*UASThreat),
+		}
+		s.stats.Leakage = newLeakageScore()
+		pointType := "Point"
+		for i := 0; i < 5; i++ {
+			threat := &UASThreat{
+				ID:       uuid.New(),
+				Position: &models.GeomPoint{Type: &pointType, Coordinates: []float64{1, 2, 3}},
+			}
+			threat.ActualCapabilities.WaveNumber = wave
+			s.uasThreats[threat.ID] = threat
+		}
+		return s
+	}
+	coordinator := newShard(shard.RoleCoordinator, 0, 1)
+	coordinator.shardHub = hub
+	worker := newShard(shard.RoleWorker, 1, 2)
+	worker.shardClient = shard.NewClient(server.URL, "")
+
+	// Four of the worker's five threats reach the base; none of the coordinator's do
+	worker.stats.UASPenetrated = 4
+	worker.stats.Leakage.ByWave[2] = 4
+
+	if outcome := coordinator.evaluateLeakage(); outcome != "" {
+		t.Fatalf("expected no failure before the worker syncs, got %q", outcome)
+	}
+
+	ctx := context.Background()
+	if err := worker.syncShards(ctx); err != nil {
+		t.Fatal(err)
+	}
+	if err := coordinator.syncShards(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	// Wave 2 is judged first: 4 of its 5 threats leaked
+	if outcome := coordinator.evaluateLeakage(); !strings.Contains(outcome, "80% of wave 2") {
+		t.Errorf("expected the coordinator to fail wave 2 on the worker's leakers, got %q", outcome)
+	}
+	coordinator.config.WaveLeakageThreshold = 0
+	if outcome := coordinator.evaluateLeakage(); !strings.Contains(outcome, "40% of threats") {
+		t.Errorf("expected the coordinator to count the worker's leakers against the run, got %q", outcome)
+	}
+	if stats := coordinator.result().Stats; stats["penetration"] != 0.4 || stats["uas_penetrated"] != 0 {
+		t.Errorf("expected run-wide penetration 0.4 with no local leakers, got %g and %g", stats["penetration"], stats["uas_penetrated"])
+	}
+}
@@ -0,0 +1,151 @@
+package simulation
+
+import (
+	"context"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/world"
+)
+
+// worldSyncInterval is how often the shared world is read and written
+const worldSyncInterval = 2 * time.Second
+
+// startWorld joins the shared world when configured
+func (s *DroneSwarmSimulation) startWorld() error {
+	if s.config.WorldURL == "" {
+		return nil
+	}
+	shared, err := world.Open(s.config.WorldURL)
+	if err != nil {
+		return err
+	}
+	s.world = shared
+	s.worldAssets = make(map[string]world.Asset)
+	logger.Infof("🌍 Sharing the world at %s", s.config.WorldURL)
+	return nil
+}
+
+// stopWorld withdraws this run's assets so other simulations stop reacting to them
+func (s *DroneSwarmSimulation) stopWorld() {
+	if s.world == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.world.Withdraw(ctx, s.Name()); err != nil {
+		logger.Warnf("Failed to withdraw from the shared world: %v", err)
+	}
+}
+
+// syncWorld publishes the base and airborne threats, then picks up defended assets and
+// neutral traffic other simulations published. Each inbound threat attacks the nearest of
+// the base and those assets, re-aiming as moving assets move.
+func (s *DroneSwarmSimulation) syncWorld(ctx context.Context) {
+	if s.world == nil || time.Since(s.worldSynced) < worldSyncInterval {
+		return
+	}
+	s.worldSynced = time.Now()
+
+	base := s.config.BaseLocation
+	assets := []world.Asset{{
+		ID: s.runID + "/base", Name: BaseAssetName, Owner: s.Name(), Role: world.RoleDefended,
+		Lat: base.Lat, Lon: base.Lon, Alt: base.Alt,
+	}}
+	for _, threat := range s.getActiveThreats() {
+		if threat.Remote || threat.LaunchPhase == LaunchPhaseGrounded {
+			continue
+		}
+		lat, lon, alt := ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+		assets = append(assets, world.Asset{
+			ID: threat.ID.String(), Name: threat.TrackNumber, Owner: s.Name(), Role: world.RoleThreat,
+			Lat: lat, Lon: lon, Alt: alt,
+		})
+	}
+	if err := s.world.Publish(ctx, assets...); err != nil {
+		logger.Warnf("Failed to publish to the shared world: %v", err)
+	}
+
+	shared, err := s.world.Assets(ctx)
+	if err != nil {
+		logger.Warnf("Failed to read the shared world: %v", err)
+		return
+	}
+	defended := make(map[string]world.Asset)
+	var traffic []world.Asset
+	for _, asset := range shared {
+		if asset.Owner == s.Name() {
+			continue
+		}
+		if asset.Role == world.RoleTraffic {
+			traffic = append(traffic, asset)
+			continue
+		}
+		if asset.Role != world.RoleDefended {
+			continue
+		}
+		if _, known := s.worldAssets[asset.ID]; !known {
+			logger.Infof("🌍 %s from %s is now a target", asset.Name, asset.Owner)
+		}
+		defended[asset.ID] = asset
+	}
+	s.worldAssets = defended
+	s.worldTraffic = traffic
+
+	s.assignWorldTargets()
+}
+
+// assignWorldTargets points each inbound threat at the nearest of the base and the shared
+// defended assets. Factions with their own objective and replayed threats keep theirs.
+func (s *DroneSwarmSimulation) assignWorldTargets() {
+	baseX, baseY, baseZ := latLonAltToECEF(s.config.BaseLocation.Lat, s.config.BaseLocation.Lon, s.config.BaseLocation.Alt)
+	basePos := &models.GeomPoint{Coordinates: []float64{baseX, baseY, baseZ}}
+
+	for _, threat := range s.getActiveThreats() {
+		if threat.Remote || threat.Replay != nil || threat.holdingAtLaunchSite() || s.factionOf(threat).ObjectiveOffset > 0 {
+			continue
+		}
+
+		target, nearest := "", calculateDistanceKm(threat.Position, basePos)
+		for id, asset := range s.worldAssets {
+			x, y, z := latLonAltToECEF(asset.Lat, asset.Lon, asset.Alt)
+			if d := calculateDistanceKm(threat.Position, &models.GeomPoint{Coordinates: []float64{x, y, z}}); d < nearest {
+				target, nearest = id, d
+			}
+		}
+
+		if target != threat.WorldTarget {
+			behaviorLog.Debugf("🌍 %s retargeted to %s", threat.TrackNumber, s.objectiveName(threat, target))
+		}
+		// Shared assets move, so keep re-aiming at them
+		if target != "" || target != threat.WorldTarget {
+			threat.WorldTarget = target
+			s.headForObjective(threat)
+		}
+	}
+}
+
+// worldTarget returns the shared asset a threat is attacking, if any
+func (s *DroneSwarmSimulation) worldTarget(threat *UASThreat) (world.Asset, bool) {
+	if threat.WorldTarget == "" {
+		return world.Asset{}, false
+	}
+	asset, ok := s.worldAssets[threat.WorldTarget]
+	return asset, ok
+}
+
+// objectiveName names what a threat targeting id attacks, for logs and the AAR
+func (s *DroneSwarmSimulation) objectiveName(threat *UASThreat, id string) string {
+	if asset, ok := s.worldAssets[id]; ok {
+		return asset.Name + " (" + asset.Owner + ")"
+	}
+	return s.factionOf(threat).objectiveAsset()
+}
+
+// strikeWorldAsset tells the owning simulation a threat reached its asset
+func (s *DroneSwarmSimulation) strikeWorldAsset(ctx context.Context, asset world.Asset) {
+	if err := s.world.Hit(ctx, asset.ID, s.Name()); err != nil {
+		logger.Warnf("Failed to report the strike on %s to the shared world: %v", asset.Name, err)
+	}
+}
@@ -0,0 +1,56 @@
+package simulation
+
+import (
+	"context"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/world"
+)
+
+func TestThreatsTargetNearestSharedAsset(t *testing.T) {
+	shared := world.NewLocal(time.Minute)
+	ctx := context.Background()
+	convoyLat, convoyLon := destinationPoint(38.8895, -77.0353, 90, 6000)
+	_ = shared.Publish(ctx, world.Asset{ID: "convoy-1", Name: "Convoy Lead", Owner: "Convoy Escort", Role: world.RoleDefended, Lat: convoyLat, Lon: convoyLon})
+
+	s := &DroneSwarmSimulation{uasThreats: map[uuid.UUID]*UASThreat{}, world: shared, runID: "run-1"}
+	s.config.BaseLocation = Location{Lat: 38.8895, Lon: -77.0353}
+
+	newThreat := func(bearing float64) *UASThreat {
+		lat, lon := destinationPoint(38.8895, -77.0353, bearing, 8000)
+		x, y, z := latLonAltToECEF(lat, lon, 150)
+		threat := &UASThreat{
+			ID:                 uuid.New(),
+			Position:           &models.GeomPoint{Coordinates: []float64{x, y, z}},
+			ActualVelocity:     &models.GeomPoint{Coordinates: []float64{0, 0, 0}},
+			ActualCapabilities: SimulatedCapabilities{SpeedKph: 100},
+		}
+		s.uasThreats[threat.ID] = threat
+		return threat
+	}
+	east, west := newThreat(90), newThreat(270)
+
+	s.syncWorld(ctx)
+
+	if east.WorldTarget != "convoy-1" || west.WorldTarget != "" {
+		t.Fatalf("expected only the eastern threat to go after the convoy, got %q and %q", east.WorldTarget, west.WorldTarget)
+	}
+	x, y, z := s.objectiveECEF(east)
+	if lat, lon, _ := ecefToLatLonAlt(x, y, z); math.Abs(lat-convoyLat) > 1e-6 || math.Abs(lon-convoyLon) > 1e-6 {
+		t.Errorf("expected the convoy as the objective, got %.6f, %.6f", lat, lon)
+	}
+
+	published, _ := shared.Assets(ctx)
+	if len(published) != 4 {
+		t.Errorf("expected the convoy, the base and both threats in the world, got %d assets", len(published))
+	}
+
+	s.stopWorld()
+	if remaining, _ := shared.Assets(ctx); len(remaining) != 1 {
+		t.Errorf("expected only the convoy after withdrawing, got %+v", remaining)
+	}
+}
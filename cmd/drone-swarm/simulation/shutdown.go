@@ -0,0 +1,38 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// DefaultShutdownTimeout is how long the end of a run waits for in-flight updates
+const DefaultShutdownTimeout = 10 * time.Second
+
+// errSimulationTerminated is returned by a phase that ends the scenario early. The rest
+// of the tick still runs so its results are published and recorded.
+var errSimulationTerminated = errors.New("simulation terminated")
+
+// awaitShutdown is the barrier between the last tick and the run's outputs. Every
+// phase of the final tick has finished by the time it is called, engagement results
+// included; it stops the update buffer's periodic flush, waiting for a flush in
+// progress, then sends what is still queued, such as the final DESTROYED patches and
+// telemetry fallbacks, so the AAR and Legion agree on how the run ended. It gives up
+// after shutdown_timeout, noting in the AAR how many updates were left unsent.
+func (s *DroneSwarmSimulation) awaitShutdown() {
+	started := time.Now()
+	ctx, cancel := context.WithTimeout(context.Background(), s.config.ShutdownTimeout)
+	defer cancel()
+
+	unsent := 0
+	if err := s.updateBuffer.Drain(ctx); err != nil {
+		unsent = s.updateBuffer.GetPendingCount()
+		logger.Warnf("Shutdown barrier gave up after %s: %v; Legion may not show the run's final state", time.Since(started).Round(time.Millisecond), err)
+	}
+
+	s.simLogger.UpdateMetric(reporting.MetricShutdownWait, float64(time.Since(started).Milliseconds()), "ms")
+	s.simLogger.UpdateMetric(reporting.MetricShutdownUnsent, float64(unsent), "count")
+}
@@ -0,0 +1,44 @@
+package simulation
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/artifacts"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// signArtifacts writes a manifest of every run output's SHA-256 digest and a detached
+// Ed25519 signature over it, so results used in formal evaluations can be shown to be
+// unmodified. Both join the artifacts and are uploaded with them.
+func (s *DroneSwarmSimulation) signArtifacts() error {
+	if s.signingKey == nil || len(s.artifacts) == 0 {
+		return nil
+	}
+
+	manifest, err := artifacts.BuildManifest(s.runID, s.Name(), s.config.OrganizationID, s.artifacts)
+	if err != nil {
+		return err
+	}
+	data, signature, err := artifacts.SignManifest(manifest, s.signingKey)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(reportsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create reports directory: %w", err)
+	}
+	path := filepath.Join(reportsDir, fmt.Sprintf("Manifest_%s_%s.json", s.runID[:8], time.Now().Format("20060102_150405")))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest: %w", err)
+	}
+	if err := os.WriteFile(path+".sig", signature, 0644); err != nil {
+		return fmt.Errorf("failed to write manifest signature: %w", err)
+	}
+
+	s.artifacts = append(s.artifacts, path, path+".sig")
+	logger.Successf("Signed manifest of %d artifacts saved to: %s (key %s)", len(manifest.Files), path, manifest.KeyID)
+	return nil
+}
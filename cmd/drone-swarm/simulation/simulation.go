@@ -2,10 +2,14 @@ package simulation
 
 import (
 	"context"
+	"crypto/ed25519"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math"
 	"math/rand"
+	"net/http"
+	"sort"
 	"strings"
 	"sync"
 	"sync/atomic"
@@ -14,16 +18,34 @@ import (
 	"github.com/google/uuid"
 	"github.com/picogrid/legion-simulations/cmd/drone-swarm/controllers"
 	"github.com/picogrid/legion-simulations/cmd/drone-swarm/core"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/coverage"
 	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/shard"
+	"github.com/picogrid/legion-simulations/pkg/artifacts"
+	"github.com/picogrid/legion-simulations/pkg/cleanup"
 	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/clock"
+	"github.com/picogrid/legion-simulations/pkg/control"
+	"github.com/picogrid/legion-simulations/pkg/cues"
+	"github.com/picogrid/legion-simulations/pkg/flightlog"
+	"github.com/picogrid/legion-simulations/pkg/geo"
 	"github.com/picogrid/legion-simulations/pkg/logger"
 	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/runlog"
 	"github.com/picogrid/legion-simulations/pkg/simulation"
+	"github.com/picogrid/legion-simulations/pkg/spectate"
+	"github.com/picogrid/legion-simulations/pkg/world"
 )
 
 // Track number counter for generating military-style track numbers
 var trackNumberCounter uint32 = 0
 
+// Module loggers so behavior and engagement output can be tuned independently
+var (
+	behaviorLog   = logger.Module(logger.ModuleBehavior)
+	engagementLog = logger.Module(logger.ModuleEngagement)
+)
+
 // generateTrackNumber creates a military-style track number
 func generateTrackNumber() string {
 	num := atomic.AddUint32(&trackNumberCounter, 1)
@@ -49,12 +71,42 @@ type DroneSwarmSimulation struct {
 
 	// Core systems
 	engagementCalculator *core.EngagementCalculator
-	swarmBehavior        *core.SwarmBehaviorEngine
 	updateBuffer         *core.UpdateBuffer
 
 	// Reporting
 	simLogger    *reporting.SimulationLogger
 	aarGenerator *reporting.AARGenerator
+	runID        string
+	runStarted   time.Time
+	artifacts    []string // Local output files published at the end of the run
+	summary      summaryState
+
+	// Scenario clock, set when the main loop starts
+	scenarioStart  time.Time // Moved forward past each pause
+	scenarioEpoch  time.Time // Where the scenario clock starts
+	timeline       *reporting.GanttRecorder
+	coverage       coverageLog
+	recording      runRecording
+	training       trainingLog
+	factions       factionLog
+	counterBattery counterBatteryLog
+	adaptation     adaptationLog
+	acts           actState
+	script         scriptState
+	reports        reportLog
+	cues           cueState
+	overrides      overrideState
+	degraded       degradedState
+	tierPolicy     *tierPolicy             // Paces Legion updates by entity significance (nil when update_tiers is unset)
+	replayLog      *runlog.Writer          // Entity writes and events for legion-sim replay (nil unless record_replay is set)
+	replayLogPath  string                  // Where replayLog is written
+	hazards        []*hazardArea           // Active debris and noise hazards from engagements
+	groundUnits    []*groundUnit           // Friendly units patrolling around the base
+	hooks          map[hookKey][]namedHook // Extension hooks, fixed when the run starts
+	governor       *clock.Governor         // Disciplines the clock to an external reference (nil uses the local clock)
+	timing         *core.TimingModel       // Clock errors of entities with degraded timing (nil reports true time)
+	rng            *rand.Rand              // Run-wide random source; entities draw from their own streams
+	seed           int64                   // Seed rng was made from
 
 	// Entity tracking
 	counterUASSystems map[uuid.UUID]*CounterUASSystem
@@ -63,6 +115,10 @@ type DroneSwarmSimulation struct {
 	// Feed tracking for health telemetry
 	systemHealthFeeds map[uuid.UUID]uuid.UUID // Maps system ID to feed definition ID
 
+	// Startup reconciliation
+	existingEntities *entityInventory // Entities from earlier runs awaiting adoption
+	reconcileStats   reconcileStats
+
 	// Legion client
 	legionClient *client.Legion
 
@@ -75,6 +131,64 @@ type DroneSwarmSimulation struct {
 
 	// Health tracking
 	lastReportedHealth map[uuid.UUID]float64
+
+	// Threat board
+	threatBoardEntityID    uuid.UUID
+	threatBoardFeedID      uuid.UUID
+	lastThreatBoardPublish time.Time
+	threatBoardMu          sync.Mutex
+
+	// Sharding
+	shardHub     *shard.Hub
+	shardServer  *http.Server
+	shardClient  *shard.Client
+	pendingKills []shard.Kill
+	shardMu      sync.Mutex
+	shardLeakage shard.Leakage // Other shards' leakers as of the last sync; guarded by stats.mu
+
+	// Spectators
+	spectators      *spectate.Broadcaster
+	spectatorServer *http.Server
+
+	// Live tuning
+	control       *control.Panel
+	controlServer *http.Server
+	tick          int
+	status        runStatus // Phase reported to "legion-sim status"
+	pause         pauseState
+
+	// Hot reload
+	params  map[string]interface{} // As configured, for telling what a reload changes
+	reloads chan reloadRequest
+
+	// Embedding
+	observers []func(simulation.Event)
+
+	// Model data
+	dataPack *dataPackModel
+
+	// Tamper evidence
+	signingKey ed25519.PrivateKey
+
+	// Process resource usage, sampled for the AAR
+	resources *resourceSampler
+
+	// Range integration
+	timeMarkers timeMarkerLog
+	datalink    datalinkLog
+
+	// Run result
+	manifest []simulation.EntityRecord // Entities created or adopted in Legion
+	stopped  bool                      // Ended by Stop or cancellation
+
+	// Engagement envelopes by capability set
+	envelopes map[envelopeKey]*engagementEnvelope
+
+	// Shared world with other simulations
+	world        world.World
+	worldAssets  map[string]world.Asset // Defended assets other simulations published, by ID
+	worldTraffic []world.Asset          // Neutral traffic other simulations published
+	worldSynced  time.Time
 }
 
 // SimulationConfig holds configuration parameters
@@ -89,7 +203,87 @@ type SimulationConfig struct {
 	SimulationRadius     float64 // km
 	EnableDebugLogging   bool
 	CleanupExisting      bool
-	UseUniqueNames       bool // Add timestamp to entity names for uniqueness
+	ReconcileStrategy    string // adopt or delete entities left by earlier runs
+	UseUniqueNames       bool   // Add timestamp to entity names for uniqueness
+	TrackHistoryDepth    int    // Positions retained per track
+	TrailPoints          int    // Recent positions published as trail metadata (0 disables)
+	ThreatBoardSize      int    // Ranked threats published on the threat board (0 disables)
+	ThreatBoardInterval  time.Duration
+	AcceptableLeakage    float64           // Fraction of all threats allowed to penetrate (1.0 disables)
+	CriticalAssetLeakers int               // Leakers on the base that end the run (0 disables)
+	WaveLeakageThreshold float64           // Fraction of a single wave allowed to penetrate (0 disables)
+	TrackGCGrace         time.Duration     // Delay before LOST/DESTROYED tracks are removed from Legion (0 disables)
+	WarmupDuration       time.Duration     // BIT and calibration time before wave 1 (0 disables)
+	ShutdownTimeout      time.Duration     // Longest the end of the run waits for in-flight updates before writing the AAR
+	PauseHeartbeat       time.Duration     // How often a paused run resends entity positions and statuses to Legion
+	TimeScale            float64           // Scenario seconds simulated per wall second (1 is real time)
+	StartTime            time.Time         // Absolute scenario start for synchronized multi-host runs (zero starts immediately)
+	ExerciseStart        time.Time         // Exercise clock reading at scenario start (zero reports wall time only)
+	TimeMarkerInterval   time.Duration     // Timing marker feed cadence for range integration (0 disables)
+	DatalinkInterval     time.Duration     // Datalink track report cadence; pairing and engagement messages go out each tick (0 disables)
+	ClockSource          string            // External time reference ticks are aligned to (empty uses the local clock)
+	ClockSyncInterval    time.Duration     // How often the reference is sampled
+	LaunchSites          []LaunchSite      // Raids launch and assemble here (empty spawns threats already inbound)
+	WaveDelay            time.Duration     // Between first launches of consecutive waves at launch sites
+	ReportLatency        ReportLatencies   // Sensor-to-C2 detection reporting delay by datalink state (empty reports at once)
+	Acts                 []ScenarioAct     // Time-boxed phases with their own red behavior, ROE and launches (empty is one continuous assault)
+	AttackerAdaptiveness float64           // How far red replans later waves after watching earlier ones, 0.0-1.0 (0 keeps the plan)
+	ReplayTracks         []flightlog.Track // Recorded flights replayed as threats (empty simulates every threat)
+	ReplayRelocate       bool              // Move the recording onto the base rather than keeping its real coordinates
+	SensorNoise          float64           // Scale on sensor error in published track positions (0 publishes ground truth)
+	Factions             []Faction         // Independent red forces; every wave is split between them by share
+	CounterBattery       bool              // Estimate launch sites from track back-bearings and strike them
+	CounterBatteryLines  int               // Lines of bearing needed before a strike is tasked
+	CounterBatteryDelay  time.Duration     // From tasking a strike to impact
+	HazardDuration       time.Duration     // How long debris and noise hazards stay active after a kinetic engagement (0 disables)
+	PopulatedAreas       []PopulatedArea   // Polygons of people checked against hazards for collateral risk
+	GroundUnits          []GroundUnit      // Friendly troops and vehicles posted around the base
+	DefenseSites         []DefenseSite     // Fixed posts for Counter-UAS systems (empty spaces them on a ring)
+	WaveTimes            []time.Duration   // Launch time of each wave from the scenario start (empty launches with acts or at once)
+	SystemFailures       []SystemFailure   // Scripted Counter-UAS outages
+	EntityTemplates      EntityTemplates   // Legion category/type/affiliation overrides per unit template
+	TimingOffset         time.Duration     // Largest fixed clock offset of an entity with degraded timing
+	TimingDriftPPM       float64           // Largest clock drift of an entity with degraded timing, parts per million
+	TimingJitter         time.Duration     // 1-sigma jitter on each timestamp from degraded timing
+	TimingDegradedShare  float64           // Fraction of entities with degraded timing
+	ShardRole            string            // standalone, coordinator, or worker
+	ShardIndex           int               // This process's shard (coordinator is 0)
+	ShardCount           int               // Total shards; waves are split across them
+	ShardListenAddr      string            // Coordinator sync listen address
+	ShardCoordinatorURL  string            // Coordinator base URL for workers
+	ShardToken           string            // Bearer token the coordinator requires on every sync
+	ArtifactURL          string            // Object storage destination for run outputs (empty uses LEGION_ARTIFACT_URL)
+	DataPack             string            // Model data pack reference (empty uses built-in data)
+	SigningKey           string            // Ed25519 PEM key that signs the run's artifact manifest (empty leaves it unsigned)
+	SpectatorAddr        string            // Read-only spectator stream listen address (empty disables)
+	ControlAddr          string            // Live parameter tuning listen address (empty disables)
+	WorldURL             string            // Shared world with other simulations: "local" or a world service URL (empty disables)
+	ControlToken         string            // Bearer token required to change parameters
+	CueSources           []cues.Source     // External threat cue inputs: webhook, file or feed (empty disables)
+	OverridePollInterval time.Duration     // How often to check Legion for status overrides on Counter-UAS systems (0 disables)
+	CueToken             string            // Bearer token required to post cues
+	CohesionWeight       float64           // Pull of stragglers back toward their swarm center
+	FormationSpacing     float64           // Swarm spread in meters before cohesion kicks in
+	SuccessRateModifier  float64           // Scales every engagement's kill probability
+	UpdateDecimation     int               // Send threat positions to Legion every Nth tick
+	UpdateTiers          UpdateTiers       // Publish intervals by entity significance (empty publishes every entity every flush)
+	SummaryInterval      time.Duration     // Progress log cadence off a terminal (0 disables)
+	SummaryFields        []string          // Sections in the tick summary
+	CoverageMaps         bool              // Write pre- and post-run coverage maps with the AAR
+	RecordRun            bool              // Write tracks, engagements and coverage for legion-sim tiles serve
+	RecordReplay         bool              // Write every entity write and event for legion-sim replay
+	TrainingPackage      bool              // Write trainee decision points with the AAR
+	BriefMarkdown        bool              // Write the pre-run scenario brief to Markdown as well as the console
+	AARHistory           int               // Earlier runs' AARs recommendations are ranked against (0 ranks this run alone)
+	SaveEvents           bool              // Write the run's event log so legion-sim report can regenerate the AAR
+	VerifyLegion         bool              // Read back Legion's record after the run and compare it with what was sent
+	APIBudgetPerMinute   int               // Legion API calls allowed per minute (0 is unlimited)
+	APIBudgetPerRun      int               // Legion API calls allowed over the run (0 is unlimited)
+	APIRateLimit         int               // Legion API calls per second the client paces itself to (0 is unlimited)
+	BreakerThreshold     int               // Failed Legion calls in a row before updates are buffered locally (0 disables)
+	BreakerCooldown      time.Duration     // Wait before trying a failing Legion again
+	Seed                 int64             // Seeds every random draw so a run can be repeated (0 picks one)
+	RunID                string            // Namespaces every name the run gives Legion, so runs can share an organization
 }
 
 // SimulationStats tracks simulation statistics
@@ -99,7 +293,15 @@ type SimulationStats struct {
 	UASEliminated         int
 	UASPenetrated         int
 	CounterUASLosses      int
+	Leakage               LeakageScore
+	CounterBattery        CounterBatteryStats
+	Hazards               int // Hazard areas opened by kinetic engagements
+	CollateralExposures   int // Populated areas and neutral traffic found inside a hazard
+	GroundUnitsHit        int // Ground units inside a leaker's blast
+	PersonnelAtRisk       int // Strength of the ground units hit
+	TracksArchived        int
 	SimulationOutcome     string
+	Termination           string // Which termination condition ended the run, if one did
 	mu                    sync.RWMutex
 }
 
@@ -116,6 +318,7 @@ func NewDroneSwarmSimulation() simulation.Simulation {
 		stopChan:           make(chan struct{}),
 		lastReportedHealth: make(map[uuid.UUID]float64),
 		systemHealthFeeds:  make(map[uuid.UUID]uuid.UUID),
+		reloads:            make(chan reloadRequest),
 	}
 }
 
@@ -144,60 +347,363 @@ func (s *DroneSwarmSimulation) Configure(params map[string]interface{}) error {
 		SimulationRadius:     15.0, // km
 		EnableDebugLogging:   true,
 		CleanupExisting:      true,
+		ReconcileStrategy:    DefaultReconcileStrategy,
+		TrackHistoryDepth:    DefaultTrackHistoryDepth,
+		TrailPoints:          DefaultTrailPoints,
+		ThreatBoardSize:      DefaultThreatBoardSize,
+		ThreatBoardInterval:  DefaultThreatBoardInterval,
+		AcceptableLeakage:    DefaultAcceptableLeakage,
+		CriticalAssetLeakers: DefaultCriticalAssetLeakers,
+		WaveLeakageThreshold: DefaultWaveLeakageThreshold,
+		TrackGCGrace:         DefaultTrackGCGrace,
+		WarmupDuration:       DefaultWarmupDuration,
+		ShutdownTimeout:      DefaultShutdownTimeout,
+		PauseHeartbeat:       DefaultPauseHeartbeat,
+		TimeScale:            DefaultTimeScale,
+		WaveDelay:            DefaultWaveDelay,
+		SensorNoise:          DefaultSensorNoise,
+		Factions:             []Faction{{Name: DefaultFaction, Share: 1}},
+		CounterBatteryLines:  DefaultCounterBatteryLines,
+		CounterBatteryDelay:  DefaultCounterBatteryDelay,
+		HazardDuration:       DefaultHazardDuration,
+		TimingDegradedShare:  1,
+		ClockSyncInterval:    clock.DefaultSyncInterval,
+		SummaryInterval:      DefaultSummaryInterval,
+		CoverageMaps:         true,
+		RecordRun:            true,
+		TrainingPackage:      true,
+		AARHistory:           reporting.DefaultHistoryRuns,
+		SaveEvents:           true,
+		CohesionWeight:       DefaultCohesionWeight,
+		FormationSpacing:     DefaultFormationSpacing,
+		SuccessRateModifier:  DefaultSuccessRateModifier,
+		UpdateDecimation:     DefaultUpdateDecimation,
+		ShardRole:            shard.RoleStandalone,
+		ShardCount:           1,
+		ShardListenAddr:      shard.DefaultListenAddr,
+	}
+
+	// Keep what was asked for so a reload can tell what changed
+	s.params = make(map[string]interface{}, len(params))
+	for name, value := range params {
+		s.params[name] = value
 	}
 
 	// Parse configuration parameters
-	if val, ok := params["organization_id"].(string); ok {
-		s.config.OrganizationID = val
+	p := simulation.ReadParams(params)
+	p.String("organization_id", &s.config.OrganizationID)
+	p.Int("num_counter_uas_systems", &s.config.NumCounterUASSystems)
+	p.Int("num_uas_threats", &s.config.NumUASThreats)
+	p.Int("waves", &s.config.NumWaves)
+	p.Duration("duration", &s.config.SimDuration)
+	p.Duration("update_interval", &s.config.UpdateInterval)
+	p.Float("center_latitude", &s.config.BaseLocation.Lat)
+	p.Float("center_longitude", &s.config.BaseLocation.Lon)
+	p.Float("center_altitude", &s.config.BaseLocation.Alt)
+
+	// A grid reference takes precedence over latitude/longitude
+	var centerMgrs string
+	if p.String("center_mgrs", &centerMgrs) && centerMgrs != "" {
+		pos, err := geo.ParseMGRS(centerMgrs)
+		if err != nil {
+			return fmt.Errorf("invalid center_mgrs: %w", err)
+		}
+		s.config.BaseLocation.Lat, s.config.BaseLocation.Lon = pos.Lat, pos.Lon
+	}
+
+	p.Int("track_history_depth", &s.config.TrackHistoryDepth)
+	p.Int("trail_points", &s.config.TrailPoints)
+	p.Float("sensor_noise", &s.config.SensorNoise)
+	p.Int("threat_board_size", &s.config.ThreatBoardSize)
+	p.Duration("threat_board_interval", &s.config.ThreatBoardInterval)
+	p.Duration("summary_interval", &s.config.SummaryInterval)
+
+	summaryFields := DefaultSummaryFields
+	p.String("summary_fields", &summaryFields)
+	fields, err := parseSummaryFields(summaryFields)
+	if err != nil {
+		return err
+	}
+	s.config.SummaryFields = fields
+
+	p.Float("acceptable_leakage", &s.config.AcceptableLeakage)
+	p.Int64(simulation.SeedParameter, &s.config.Seed)
+
+	if p.String(simulation.RunIDParameter, &s.config.RunID) && s.config.RunID != "" {
+		if err := simulation.ValidateRunID(s.config.RunID); err != nil {
+			return fmt.Errorf("invalid run_id: %w", err)
+		}
+	}
+
+	p.Int("critical_asset_leakers", &s.config.CriticalAssetLeakers)
+	p.Float("wave_leakage_threshold", &s.config.WaveLeakageThreshold)
+	p.Duration("track_gc_grace", &s.config.TrackGCGrace)
+	p.Duration("warmup_duration", &s.config.WarmupDuration)
+
+	if p.Duration("shutdown_timeout", &s.config.ShutdownTimeout) && s.config.ShutdownTimeout <= 0 {
+		return fmt.Errorf("invalid shutdown_timeout: must be positive")
+	}
+
+	if p.Duration("pause_heartbeat", &s.config.PauseHeartbeat) && s.config.PauseHeartbeat <= 0 {
+		return fmt.Errorf("invalid pause_heartbeat: must be positive")
+	}
+
+	if p.Float("time_scale", &s.config.TimeScale) && (s.config.TimeScale <= 0 || s.config.TimeScale > maxTimeScale) {
+		return fmt.Errorf("invalid time_scale: must be greater than 0 and at most %g", maxTimeScale)
+	}
+
+	var launchSites string
+	if p.String("launch_sites", &launchSites) {
+		sites, err := parseLaunchSites(launchSites)
+		if err != nil {
+			return fmt.Errorf("invalid launch_sites: %w", err)
+		}
+		s.config.LaunchSites = sites
+	}
+
+	p.Duration("wave_delay", &s.config.WaveDelay)
+	p.Float("attacker_adaptiveness", &s.config.AttackerAdaptiveness)
+
+	var replayTracks string
+	if p.String("replay_tracks", &replayTracks) && strings.TrimSpace(replayTracks) != "" {
+		tracks, err := flightlog.Load(strings.TrimSpace(replayTracks))
+		if err != nil {
+			return fmt.Errorf("invalid replay_tracks: %w", err)
+		}
+		s.config.ReplayTracks = tracks
+	}
+
+	s.config.ReplayRelocate = true
+	p.Bool("replay_relocate", &s.config.ReplayRelocate)
+
+	var factionsSpec string
+	if p.String("factions", &factionsSpec) {
+		factions, err := parseFactions(factionsSpec)
+		if err != nil {
+			return fmt.Errorf("invalid factions: %w", err)
+		}
+		if len(factions) > 0 {
+			s.config.Factions = factions
+		}
+	}
+
+	p.Bool("counter_battery", &s.config.CounterBattery)
+	p.Int("counter_battery_lines", &s.config.CounterBatteryLines)
+	p.Duration("counter_battery_delay", &s.config.CounterBatteryDelay)
+	p.Duration("hazard_duration", &s.config.HazardDuration)
+	p.Duration("timing_offset", &s.config.TimingOffset)
+	p.Float("timing_drift_ppm", &s.config.TimingDriftPPM)
+	p.Duration("timing_jitter", &s.config.TimingJitter)
+	p.Float("timing_degraded_share", &s.config.TimingDegradedShare)
+
+	var populatedAreas string
+	if p.String("populated_areas", &populatedAreas) {
+		areas, err := parsePopulatedAreas(populatedAreas)
+		if err != nil {
+			return fmt.Errorf("invalid populated_areas: %w", err)
+		}
+		s.config.PopulatedAreas = areas
+	}
+
+	var defenseSites string
+	if p.String("defense_sites", &defenseSites) {
+		sites, err := parseDefenseSites(defenseSites)
+		if err != nil {
+			return fmt.Errorf("invalid defense_sites: %w", err)
+		}
+		s.config.DefenseSites = sites
+		if len(sites) > 0 {
+			s.config.NumCounterUASSystems = len(sites)
+		}
+	}
+
+	var waveTimes string
+	if p.String("wave_times", &waveTimes) {
+		times, err := parseWaveTimes(waveTimes)
+		if err != nil {
+			return fmt.Errorf("invalid wave_times: %w", err)
+		}
+		s.config.WaveTimes = times
+	}
+
+	var systemFailures string
+	if p.String("system_failures", &systemFailures) {
+		failures, err := parseSystemFailures(systemFailures)
+		if err != nil {
+			return fmt.Errorf("invalid system_failures: %w", err)
+		}
+		s.config.SystemFailures = failures
+	}
+
+	var groundUnits string
+	if p.String("ground_units", &groundUnits) {
+		units, err := parseGroundUnits(groundUnits)
+		if err != nil {
+			return fmt.Errorf("invalid ground_units: %w", err)
+		}
+		s.config.GroundUnits = units
+	}
+
+	var reportLatency string
+	if p.String("report_latency", &reportLatency) {
+		latencies, err := parseReportLatency(reportLatency)
+		if err != nil {
+			return fmt.Errorf("invalid report_latency: %w", err)
+		}
+		s.config.ReportLatency = latencies
+	}
+
+	var updateTiers string
+	if p.String("update_tiers", &updateTiers) {
+		tiers, err := parseUpdateTiers(updateTiers)
+		if err != nil {
+			return fmt.Errorf("invalid update_tiers: %w", err)
+		}
+		s.config.UpdateTiers = tiers
+	}
+
+	var actsSpec string
+	if p.String("acts", &actsSpec) {
+		acts, err := parseActs(actsSpec)
+		if err != nil {
+			return fmt.Errorf("invalid acts: %w", err)
+		}
+		s.config.Acts = acts
+	}
+
+	var entityTemplates string
+	if p.String("entity_templates", &entityTemplates) {
+		templates, err := parseEntityTemplates(entityTemplates)
+		if err != nil {
+			return fmt.Errorf("invalid entity_templates: %w", err)
+		}
+		s.config.EntityTemplates = templates
+		if len(templates) > 0 {
+			logger.Infof("Entity templates: %s", describeEntityTemplates(templates))
+		}
+	}
+
+	var startTimeSpec string
+	if p.String("start_time", &startTimeSpec) {
+		startTime, err := parseStartTime(startTimeSpec)
+		if err != nil {
+			return err
+		}
+		s.config.StartTime = startTime
 	}
 
-	// Handle both int and float64 for num_counter_uas_systems
-	switch val := params["num_counter_uas_systems"].(type) {
-	case int:
-		s.config.NumCounterUASSystems = val
-	case float64:
-		s.config.NumCounterUASSystems = int(val)
+	var exerciseTime string
+	if p.String("exercise_time", &exerciseTime) {
+		exerciseStart, err := parseExerciseTime(exerciseTime)
+		if err != nil {
+			return err
+		}
+		s.config.ExerciseStart = exerciseStart
+	}
+
+	p.Duration("time_marker_interval", &s.config.TimeMarkerInterval)
+	p.Duration("datalink_interval", &s.config.DatalinkInterval)
+
+	var clockSource string
+	if p.String("clock_source", &clockSource) {
+		if _, err := clock.Open(clockSource); err != nil {
+			return fmt.Errorf("invalid clock_source: %w", err)
+		}
+		s.config.ClockSource = strings.TrimSpace(clockSource)
+	}
+
+	p.Duration("clock_sync_interval", &s.config.ClockSyncInterval)
+
+	var shardRole string
+	if p.String("shard_role", &shardRole) && shardRole != "" {
+		s.config.ShardRole = shardRole
+	}
+
+	p.Int("shard_index", &s.config.ShardIndex)
+	p.Int("shard_count", &s.config.ShardCount)
+
+	var shardListenAddr string
+	if p.String("shard_listen_addr", &shardListenAddr) && shardListenAddr != "" {
+		s.config.ShardListenAddr = shardListenAddr
 	}
 
-	// Handle both int and float64 for num_uas_threats
-	switch val := params["num_uas_threats"].(type) {
-	case int:
-		s.config.NumUASThreats = val
-	case float64:
-		s.config.NumUASThreats = int(val)
+	p.String("shard_coordinator_url", &s.config.ShardCoordinatorURL)
+	p.String("shard_token", &s.config.ShardToken)
+	p.String("artifact_url", &s.config.ArtifactURL)
+
+	var dataPack string
+	if p.String("data_pack", &dataPack) {
+		s.config.DataPack = strings.TrimSpace(dataPack)
 	}
 
-	// Handle both int and float64 for waves
-	switch val := params["waves"].(type) {
-	case int:
-		s.config.NumWaves = val
-	case float64:
-		s.config.NumWaves = int(val)
+	var signingKey string
+	if p.String("signing_key", &signingKey) {
+		s.config.SigningKey = strings.TrimSpace(signingKey)
 	}
 
-	if val, ok := params["duration"].(time.Duration); ok {
-		s.config.SimDuration = val
+	p.String("spectator_addr", &s.config.SpectatorAddr)
+	p.String("control_addr", &s.config.ControlAddr)
+
+	var worldURL string
+	if p.String("world_url", &worldURL) {
+		if _, err := world.Open(worldURL); err != nil {
+			return fmt.Errorf("invalid world_url: %w", err)
+		}
+		s.config.WorldURL = strings.TrimSpace(worldURL)
 	}
 
-	if val, ok := params["update_interval"].(time.Duration); ok {
-		s.config.UpdateInterval = val
+	p.String("control_token", &s.config.ControlToken)
+
+	var cueSources string
+	if p.String("cue_sources", &cueSources) {
+		sources, err := cues.ParseSources(cueSources)
+		if err == nil {
+			err = checkCueSources(sources)
+		}
+		if err != nil {
+			return fmt.Errorf("invalid cue_sources: %w", err)
+		}
+		s.config.CueSources = sources
 	}
 
-	if val, ok := params["debug_logging"].(bool); ok {
-		s.config.EnableDebugLogging = val
+	p.String("cue_token", &s.config.CueToken)
+
+	if p.Duration("override_poll_interval", &s.config.OverridePollInterval) && s.config.OverridePollInterval < 0 {
+		return fmt.Errorf("invalid override_poll_interval: cannot be negative")
 	}
+	p.Bool("debug_logging", &s.config.EnableDebugLogging)
+	p.Bool("cleanup_existing", &s.config.CleanupExisting)
+	p.Int("aar_history", &s.config.AARHistory)
+	p.Bool("coverage_maps", &s.config.CoverageMaps)
+	p.Bool("record_run", &s.config.RecordRun)
+	p.Bool("record_replay", &s.config.RecordReplay)
+	p.Bool("training_package", &s.config.TrainingPackage)
+	p.Bool("save_events", &s.config.SaveEvents)
+	p.Bool("brief_markdown", &s.config.BriefMarkdown)
+	p.Bool("verify_legion", &s.config.VerifyLegion)
+	p.Int("api_budget_per_minute", &s.config.APIBudgetPerMinute)
+	p.Int("api_budget_per_run", &s.config.APIBudgetPerRun)
+	p.Int("api_rate_limit", &s.config.APIRateLimit)
+	p.Int("circuit_breaker_threshold", &s.config.BreakerThreshold)
+	p.Duration("circuit_breaker_cooldown", &s.config.BreakerCooldown)
 
-	if val, ok := params["cleanup_existing"].(bool); ok {
-		s.config.CleanupExisting = val
+	var reconcileStrategy string
+	if p.String("reconcile_strategy", &reconcileStrategy) && reconcileStrategy != "" {
+		s.config.ReconcileStrategy = reconcileStrategy
 	}
 
 	// Handle log level parameter and apply to global logger
-	if val, ok := params["log_level"].(string); ok {
-		logger.Infof("Setting log level to: %s", val)
-		logger.SetLevel(logger.ParseLevel(val))
+	var logLevel string
+	if p.String("log_level", &logLevel) {
+		logger.Infof("Setting log level to: %s", logLevel)
+		logger.SetLevel(logger.ParseLevel(logLevel))
 	}
 
 	// Validate configuration
+	if err := p.Err(); err != nil {
+		return err
+	}
+
 	if s.config.NumCounterUASSystems < 1 {
 		return fmt.Errorf("must have at least 1 Counter-UAS system")
 	}
@@ -206,24 +712,205 @@ func (s *DroneSwarmSimulation) Configure(params map[string]interface{}) error {
 		return fmt.Errorf("must have at least 1 UAS threat")
 	}
 
+	if s.config.TrackHistoryDepth < 1 {
+		return fmt.Errorf("track_history_depth must be at least 1")
+	}
+
+	if s.config.ReconcileStrategy != ReconcileAdopt && s.config.ReconcileStrategy != ReconcileDelete {
+		return fmt.Errorf("reconcile_strategy must be %s or %s", ReconcileAdopt, ReconcileDelete)
+	}
+
+	if err := s.config.validateSharding(); err != nil {
+		return err
+	}
+
+	s.dataPack = nil
+	if s.config.DataPack != "" {
+		pack, err := loadDataPack(s.config.DataPack)
+		if err != nil {
+			return err
+		}
+		s.dataPack = pack
+	}
+
+	// Load the key up front so a bad path fails the run before it starts, not after it
+	s.signingKey = nil
+	if s.config.SigningKey != "" {
+		key, err := artifacts.LoadSigningKey(s.config.SigningKey)
+		if err != nil {
+			return err
+		}
+		s.signingKey = key
+	}
+
+	// Workers must never delete the coordinator's entities
+	if s.config.ShardRole == shard.RoleWorker {
+		s.config.CleanupExisting = false
+	}
+
+	if s.config.AcceptableLeakage < 0 || s.config.AcceptableLeakage > 1 {
+		return fmt.Errorf("acceptable_leakage must be between 0 and 1")
+	}
+
+	if s.config.WaveLeakageThreshold < 0 || s.config.WaveLeakageThreshold > 1 {
+		return fmt.Errorf("wave_leakage_threshold must be between 0 and 1")
+	}
+
+	if s.config.CriticalAssetLeakers < 0 {
+		return fmt.Errorf("critical_asset_leakers cannot be negative")
+	}
+
+	if s.config.ThreatBoardSize < 0 {
+		return fmt.Errorf("threat_board_size cannot be negative")
+	}
+
+	if s.config.TimeMarkerInterval < 0 {
+		return fmt.Errorf("time_marker_interval cannot be negative")
+	}
+	if s.config.DatalinkInterval < 0 {
+		return fmt.Errorf("datalink_interval cannot be negative")
+	}
+
+	if s.config.ClockSyncInterval <= 0 {
+		return fmt.Errorf("clock_sync_interval must be positive")
+	}
+
+	if s.config.SensorNoise < 0 {
+		return fmt.Errorf("sensor_noise must not be negative")
+	}
+
+	if s.config.TrailPoints < 0 || s.config.TrailPoints > s.config.TrackHistoryDepth {
+		return fmt.Errorf("trail_points must be between 0 and track_history_depth (%d)", s.config.TrackHistoryDepth)
+	}
+
+	if s.config.WaveDelay < 0 {
+		return fmt.Errorf("wave_delay cannot be negative")
+	}
+
+	if err := validateActs(s.config.Acts, s.config.NumWaves); err != nil {
+		return fmt.Errorf("invalid acts: %w", err)
+	}
+	if err := s.config.validateScript(); err != nil {
+		return err
+	}
+	if len(s.config.Acts) > 0 && actStart(s.config.Acts, len(s.config.Acts)-1) >= s.config.SimDuration {
+		logger.Warnf("Acts after %s start once the %s run has ended", actStart(s.config.Acts, len(s.config.Acts)-1), s.config.SimDuration)
+	}
+
+	if s.config.AttackerAdaptiveness < 0 || s.config.AttackerAdaptiveness > 1 {
+		return fmt.Errorf("attacker_adaptiveness must be between 0 and 1")
+	}
+
+	if s.config.AttackerAdaptiveness > 0 && len(s.config.LaunchSites) == 0 {
+		return fmt.Errorf("attacker_adaptiveness requires launch_sites so later waves are still on the ground to replan")
+	}
+
+	if s.config.APIBudgetPerMinute < 0 || s.config.APIBudgetPerRun < 0 {
+		return fmt.Errorf("api budgets cannot be negative")
+	}
+
+	if s.config.APIRateLimit < 0 {
+		return fmt.Errorf("api_rate_limit cannot be negative")
+	}
+
+	if s.config.BreakerThreshold < 0 || s.config.BreakerCooldown < 0 {
+		return fmt.Errorf("circuit_breaker_threshold and circuit_breaker_cooldown cannot be negative")
+	}
+
+	if s.config.CounterBatteryLines < 2 {
+		return fmt.Errorf("counter_battery_lines must be at least 2")
+	}
+
+	if s.config.CounterBatteryDelay < 0 {
+		return fmt.Errorf("counter_battery_delay cannot be negative")
+	}
+
+	if s.config.AARHistory < 0 {
+		return fmt.Errorf("aar_history cannot be negative")
+	}
+
+	if s.config.HazardDuration < 0 {
+		return fmt.Errorf("hazard_duration cannot be negative")
+	}
+
+	if s.config.TimingOffset < 0 || s.config.TimingDriftPPM < 0 || s.config.TimingJitter < 0 {
+		return fmt.Errorf("timing_offset, timing_drift_ppm and timing_jitter cannot be negative")
+	}
+
+	if s.config.TimingDegradedShare < 0 || s.config.TimingDegradedShare > 1 {
+		return fmt.Errorf("timing_degraded_share must be between 0 and 1")
+	}
+
 	logger.Infof("Configuration: %d Counter-UAS systems vs %d UAS threats in %d waves",
 		s.config.NumCounterUASSystems, s.config.NumUASThreats, s.config.NumWaves)
+	if grid, err := geo.FormatMGRS(s.config.BaseLocation.Lat, s.config.BaseLocation.Lon, 5); err == nil {
+		logger.Infof("Defended base at %.6f, %.6f (MGRS %s)", s.config.BaseLocation.Lat, s.config.BaseLocation.Lon, grid)
+	}
 
 	return nil
 }
 
 // Run executes the simulation
-func (s *DroneSwarmSimulation) Run(ctx context.Context, legionClient *client.Legion) error {
+func (s *DroneSwarmSimulation) Run(ctx context.Context, legionClient *client.Legion) (*simulation.Result, error) {
+	err := s.run(ctx, legionClient)
+	return s.result(), err
+}
+
+// run sets up the forces and drives the simulation loop to the end of the scenario
+func (s *DroneSwarmSimulation) run(ctx context.Context, legionClient *client.Legion) error {
 	logger.Infof("Starting %s simulation", s.Name())
+	s.setPhase(runPhaseSetup)
 	s.legionClient = legionClient
+	s.startBudget()
 
 	// Initialize controllers and systems
 	if err := s.initialize(ctx); err != nil {
 		return fmt.Errorf("failed to initialize simulation: %w", err)
 	}
 
-	// Clean up existing entities if requested
-	if s.config.CleanupExisting {
+	// Brief the scenario before anything is created
+	if err := s.issueBrief(); err != nil {
+		logger.Warnf("Scenario brief failed: %v", err)
+	}
+
+	// Sample CPU, memory and GC for the AAR; stopped early once the run is recorded
+	s.resources = startResourceSampler()
+	defer s.stopResources()
+
+	// Bring up the shard coordinator or worker link
+	if err := s.startSharding(); err != nil {
+		return fmt.Errorf("failed to start sharding: %w", err)
+	}
+	defer s.stopSharding()
+
+	// Let read-only spectators watch the run
+	s.startSpectator()
+	defer s.stopSpectator()
+
+	// Let facilitators adjust whitelisted parameters mid-run
+	s.startControl()
+	defer s.stopControl()
+
+	// Share the area with other simulations running alongside
+	if err := s.startWorld(); err != nil {
+		return fmt.Errorf("failed to join the shared world: %w", err)
+	}
+	defer s.stopWorld()
+
+	// Discipline the clock before anything is scheduled against it
+	if err := s.startClock(ctx); err != nil {
+		return fmt.Errorf("failed to sync the clock: %w", err)
+	}
+
+	// Adopt or clean up entities left by earlier runs if requested
+	if s.config.CleanupExisting && s.config.ReconcileStrategy == ReconcileAdopt {
+		// Feeds stay in place; adopted entities keep their IDs and reuse them
+		if err := s.loadExistingEntities(ctx); err != nil {
+			logger.Warnf("Failed to load existing entities: %v", err)
+			// Enable unique names as fallback
+			s.config.UseUniqueNames = true
+		}
+	} else if s.config.CleanupExisting {
 		// Clean up orphaned feeds first to avoid conflicts
 		if err := s.cleanupOrphanedFeeds(ctx); err != nil {
 			logger.Warnf("Failed to cleanup orphaned feeds: %v", err)
@@ -236,10 +923,18 @@ func (s *DroneSwarmSimulation) Run(ctx context.Context, legionClient *client.Leg
 		}
 	}
 
+	// Record what the run sends Legion from here on for legion-sim replay
+	s.startReplayLog()
+	defer func() {
+		if err := s.saveReplayLog(); err != nil {
+			logger.Errorf("Failed to save replay log: %v", err)
+		}
+	}()
+
 	// Create entities
 	if err := s.createEntities(ctx); err != nil {
 		// If we get a conflict error, retry with unique names
-		if strings.Contains(err.Error(), "409") || strings.Contains(err.Error(), "already exists") {
+		if errors.Is(err, client.ErrConflict) {
 			logger.Warn("Entity name conflict detected, retrying with unique names...")
 			s.config.UseUniqueNames = true
 			// Clear any partially created entities
@@ -255,49 +950,98 @@ func (s *DroneSwarmSimulation) Run(ctx context.Context, legionClient *client.Leg
 		}
 	}
 
+	// Remove leftovers the planned force did not adopt
+	s.removeSurplusEntities(ctx)
+
 	// Deploy entities to initial positions
 	if err := s.deployEntities(ctx); err != nil {
 		return fmt.Errorf("failed to deploy entities: %w", err)
 	}
 
+	// Pre-run coverage maps of the deployed defense
+	if err := s.analyzeCoverage(); err != nil {
+		logger.Warnf("Coverage analysis failed: %v", err)
+	}
+
 	// Start the update buffer with context
 	s.updateBuffer.Start(ctx)
 	defer s.updateBuffer.Stop()
 
+	// Warm-up: BIT, comm checks and sensor baselines before wave 1
+	if err := s.runWarmup(ctx); err != nil {
+		return fmt.Errorf("warm-up failed: %w", err)
+	}
+
+	// Hold for a synchronized start if one is scheduled
+	epoch, err := s.waitForStartTime(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to synchronize start: %w", err)
+	}
+
+	// Take external threat cues from here on
+	if err := s.startCues(ctx); err != nil {
+		return fmt.Errorf("failed to start cue sources: %w", err)
+	}
+	defer s.stopCues()
+
+	// React to systems taken offline or back in service in Legion
+	if err := s.startOverrides(ctx); err != nil {
+		return fmt.Errorf("failed to watch for overrides: %w", err)
+	}
+	defer s.stopOverrides()
+
 	// Start simulation loop
-	return s.runSimulationLoop(ctx)
+	return s.runSimulationLoop(ctx, epoch)
 }
 
 // initialize sets up controllers and systems
 func (s *DroneSwarmSimulation) initialize(ctx context.Context) error {
 	logger.Info("Initializing simulation controllers and systems...")
 
+	// Every random draw comes from the seed, logged so the run can be repeated
+	s.rng, s.seed = simulation.NewRand(s.config.Seed)
+	logger.Infof("Random seed %d (repeat this run with --seed %d)", s.seed, s.seed)
+
 	// Initialize simulation logger
 	s.simLogger = reporting.NewSimulationLogger("counter-uas-simulation")
+	s.simLogger.OnEvent(s.notifyObservers)
+	s.runID = uuid.New().String()
+	s.runStarted = time.Now()
+	s.hooks = registeredHooks()
 
 	// Initialize AAR generator
 	aarConfig := reporting.AARConfig{
-		OutputDir:     "./reports",
+		OutputDir:     reportsDir,
 		Format:        "json",
 		IncludeGraphs: true,
 		DetailLevel:   "detailed",
 	}
+	if s.dataPack != nil {
+		aarConfig.DataPack = s.dataPack.ref.String()
+	}
+	aarConfig.Seed = s.seed
+	if s.config.AARHistory > 0 {
+		aarConfig.HistoryDir, aarConfig.HistoryRuns = reportsDir, s.config.AARHistory
+	}
 	s.aarGenerator = reporting.NewAARGenerator(s.simLogger, aarConfig)
 
 	// Initialize core systems
-	s.engagementCalculator = core.NewEngagementCalculator()
-	s.swarmBehavior = core.NewSwarmBehaviorEngine()
+	s.engagementCalculator = core.NewEngagementCalculator(simulation.DeriveRand(s.random()))
+	s.startFactions()
 	s.updateBuffer = core.NewUpdateBuffer(s.legionClient, s.config.OrganizationID, 50, 250*time.Millisecond)
+	s.startTiming()
+	s.startUpdateTiers()
 
 	// Initialize controllers
 	simConfig := &controllers.SimulationConfig{
 		Duration:       s.config.SimDuration,
 		UpdateInterval: s.config.UpdateInterval,
 		TickRate:       100 * time.Millisecond,
+		Rand:           simulation.DeriveRand(s.random()),
 	}
 	s.simController = controllers.NewSimulationController(s.legionClient, s.config.OrganizationID, simConfig)
 	s.systemController = controllers.NewSystemController()
-	s.swarmController = controllers.NewSwarmController()
+	s.swarmController = controllers.NewSwarmController(simulation.DeriveRand(s.random()))
 
 	// Initialize controllers
 	if err := s.simController.Initialize(ctx); err != nil {
@@ -320,25 +1064,32 @@ func (s *DroneSwarmSimulation) initialize(ctx context.Context) error {
 func (s *DroneSwarmSimulation) createEntities(ctx context.Context) error {
 	logger.Info("Creating entities in Legion...")
 
-	// Create Counter-UAS systems (BLUE FORCE)
-	for i := 0; i < s.config.NumCounterUASSystems; i++ {
-		// Alternate between kinetic and EW systems
-		engagementType := EngagementTypeKinetic
-		if i%2 == 1 {
-			engagementType = EngagementTypeEW
-		}
-
-		name := fmt.Sprintf("Counter-UAS-%02d", i+1)
+	// Create Counter-UAS systems (BLUE FORCE), owned by the coordinator in sharded runs
+	var systems []plannedSystem
+	if s.ownsBlueForce() {
+		systems = s.config.systemPlan()
+	}
+	for _, planned := range systems {
+		engagementType := planned.EngagementType
+		name := planned.Name
 		if s.config.UseUniqueNames {
-			name = fmt.Sprintf("Counter-UAS-%02d-%d", i+1, time.Now().Unix())
+			name = fmt.Sprintf("%s-%d", planned.Name, time.Now().Unix())
 		}
+		name = s.named(name)
 		pointType := "Point"
 		position := &models.GeomPoint{
 			Type:        &pointType,
 			Coordinates: []float64{0, 0, 0}, // Will be set during deployment
 		}
 
-		system := NewCounterUASSystem(name, position, engagementType)
+		system := NewCounterUASSystem(name, position, engagementType, s.random())
+		system.Callsign = s.named(system.Callsign)
+		system.Site = planned.Site
+		if template := s.config.EntityTemplates[systemTemplate(engagementType)]; template.Affiliation != "" {
+			system.Affiliation = template.Affiliation
+		}
+		s.applyPkTable(system)
+		system.envelope = s.engagementEnvelope(system)
 		s.counterUASSystems[system.ID] = system
 
 		// Prepare metadata with full BLUE FORCE visibility
@@ -355,20 +1106,20 @@ func (s *DroneSwarmSimulation) createEntities(ctx context.Context) error {
 		}
 		category := models.CategoryDEVICE
 		entityType := EntityTypeCounterUAS
-		affiliation := models.AffiliationFRIEND
 		entityReq := &models.CreateEntityRequest{
 			OrganizationID: &orgID,
 			Name:           &name,
 			Category:       &category,
 			Type:           &entityType,
 			Status:         &system.Status,
-			Affiliation:    affiliation,
+			Affiliation:    system.Affiliation,
 			Metadata:       &metadataRaw,
 		}
+		s.applyEntityTemplate(systemTemplate(engagementType), entityReq)
 
 		// Create context with organization ID
 		orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
-		createdEntity, err := s.legionClient.CreateEntity(orgCtx, entityReq)
+		createdEntity, err := s.createOrAdoptEntity(orgCtx, entityReq)
 		if err != nil {
 			return fmt.Errorf("failed to create Counter-UAS entity %s: %w", name, err)
 		}
@@ -402,7 +1153,12 @@ func (s *DroneSwarmSimulation) createEntities(ctx context.Context) error {
 	logger.Infof("Creating %d UAS threats in %d waves (%d per wave, %d remainder)",
 		s.config.NumUASThreats, s.config.NumWaves, threatsPerWave, remainingThreats)
 
-	threatCount := 0
+	orgID, err := uuid.Parse(s.config.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("invalid organization ID: %w", err)
+	}
+	var threats []*UASThreat
+	var threatReqs []*models.CreateEntityRequest
 	for wave := 0; wave < s.config.NumWaves; wave++ {
 		// Add remainder threats to the last wave
 		threatsInThisWave := threatsPerWave
@@ -410,22 +1166,31 @@ func (s *DroneSwarmSimulation) createEntities(ctx context.Context) error {
 			threatsInThisWave += remainingThreats
 		}
 
+		// Waves owned by other shards are created and flown there
+		if !s.ownsWave(wave + 1) {
+			continue
+		}
+
+		// Every faction puts its share of the wave in the air
+		factionSizes := splitByShare(threatsInThisWave, s.config.Factions)
+		factionIdx := 0
+
 		for i := 0; i < threatsInThisWave; i++ {
-			threatCount++
-			var trackNumber string
-			if s.config.UseUniqueNames {
-				trackNumber = generateUniqueTrackNumber()
-			} else {
-				trackNumber = generateTrackNumber()
+			for factionSizes[factionIdx] == 0 {
+				factionIdx++
 			}
+			factionSizes[factionIdx]--
+			trackNumber := s.nextTrackNumber()
 			pointType := "Point"
 			position := &models.GeomPoint{
 				Type:        &pointType,
 				Coordinates: []float64{0, 0, 0}, // Will be set during deployment
 			}
 
-			threat := NewUASThreat(trackNumber, position, wave+1)
-			s.uasThreats[threat.ID] = threat
+			threat := NewUASThreat(trackNumber, position, wave+1, s.random())
+			threat.ActualCapabilities.Faction = s.config.Factions[factionIdx].Name
+			threat.ActualCapabilities.PayloadType = randomPayloadFrom(s.payloads(), s.random())
+			threat.History = NewTrackHistory(s.config.TrackHistoryDepth)
 
 			// Prepare metadata with only observable RED FORCE data
 			metadata, err := json.Marshal(threat.GetMetadata())
@@ -435,10 +1200,6 @@ func (s *DroneSwarmSimulation) createEntities(ctx context.Context) error {
 			metadataRaw := json.RawMessage(metadata)
 
 			// Create entity in Legion - using track classification
-			orgID, err := uuid.Parse(s.config.OrganizationID)
-			if err != nil {
-				return fmt.Errorf("invalid organization ID: %w", err)
-			}
 			category := models.CategoryTRACK
 			entityType := EntityTypeUAS
 			entityReq := &models.CreateEntityRequest{
@@ -450,24 +1211,55 @@ func (s *DroneSwarmSimulation) createEntities(ctx context.Context) error {
 				Affiliation:    threat.Affiliation,     // Initially UNKNOWN, changes with classification
 				Metadata:       &metadataRaw,
 			}
+			s.applyEntityTemplate(TemplateThreat, entityReq)
 
-			// Create context with organization ID
-			orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
-			createdEntity, err := s.legionClient.CreateEntity(orgCtx, entityReq)
-			if err != nil {
-				return fmt.Errorf("failed to create UAS entity %s: %w", trackNumber, err)
-			}
+			threats = append(threats, threat)
+			threatReqs = append(threatReqs, entityReq)
+		}
+	}
+
+	// Create every threat together rather than one call per drone
+	createdThreats, err := s.createOrAdoptEntities(ctx, threatReqs)
+	if err != nil {
+		return fmt.Errorf("failed to create UAS entities: %w", err)
+	}
+	for i, threat := range threats {
+		threat.ID = createdThreats[i].ID
+		threat.PublishedAffiliation = threatReqs[i].Affiliation
+		s.uasThreats[threat.ID] = threat
+
+		logger.Infof("🔴 New air track detected: %s", threat.TrackNumber)
+	}
+
+	logger.Infof("Total threats created: %d (expected: %d)", len(threats), s.config.NumUASThreats)
+
+	// Create the C2 threat board
+	if s.config.ThreatBoardSize > 0 && s.ownsBlueForce() {
+		if err := s.createThreatBoard(ctx); err != nil {
+			logger.Warnf("Failed to create threat board: %v", err)
+		}
+	}
 
-			// Update the map with the new Legion ID
-			delete(s.uasThreats, threat.ID) // Remove old entry
-			threat.ID = createdEntity.ID
-			s.uasThreats[threat.ID] = threat // Add with new ID
+	// Post friendly ground units around the base
+	if len(s.config.GroundUnits) > 0 && s.ownsBlueForce() {
+		if err := s.createGroundUnits(ctx); err != nil {
+			logger.Warnf("Failed to create ground units: %v", err)
+		}
+	}
 
-			logger.Infof("🔴 New air track detected: %s", trackNumber)
+	// Create the range clock for timing markers
+	if s.config.TimeMarkerInterval > 0 && s.ownsBlueForce() {
+		if err := s.createTimeMarkerFeed(ctx); err != nil {
+			logger.Warnf("Failed to create time marker feed: %v", err)
 		}
 	}
 
-	logger.Infof("Total threats created: %d (expected: %d)", threatCount, s.config.NumUASThreats)
+	// Create the gateway for simulated datalink traffic
+	if s.config.DatalinkInterval > 0 && s.ownsBlueForce() {
+		if err := s.createDatalinkFeed(ctx); err != nil {
+			logger.Warnf("Failed to create datalink feed: %v", err)
+		}
+	}
 
 	logger.Infof("Successfully created %d Counter-UAS systems and %d UAS threats",
 		len(s.counterUASSystems), len(s.uasThreats))
@@ -479,31 +1271,13 @@ func (s *DroneSwarmSimulation) createEntities(ctx context.Context) error {
 func (s *DroneSwarmSimulation) deployEntities(ctx context.Context) error {
 	logger.Info("Deploying entities to initial positions...")
 
-	// Convert base location to ECEF
-	baseX, baseY, baseZ := latLonAltToECEF(
-		s.config.BaseLocation.Lat,
-		s.config.BaseLocation.Lon,
-		s.config.BaseLocation.Alt,
-	)
-
-	// Deploy Counter-UAS systems in defensive ring
-	angleStep := 360.0 / float64(s.config.NumCounterUASSystems)
-	defenseRadius := 5000.0 // 5km defensive perimeter
-
+	// Deploy Counter-UAS systems in defensive ring, or at their defense sites
 	i := 0
-	for _, system := range s.counterUASSystems {
-		angle := float64(i) * angleStep * math.Pi / 180.0
-
-		// Calculate position on defensive ring
-		offsetX := defenseRadius * math.Cos(angle)
-		offsetY := defenseRadius * math.Sin(angle)
-
-		system.Position.Coordinates[0] = baseX + offsetX
-		system.Position.Coordinates[1] = baseY + offsetY
-		system.Position.Coordinates[2] = baseZ + 50 // 50m elevation
+	for _, system := range s.systemsByName() {
+		system.Position.Coordinates[0], system.Position.Coordinates[1], system.Position.Coordinates[2] = s.systemPosition(i, system.Site)
 
 		// Update location in Legion
-		recordedAt := time.Now()
+		recordedAt := s.recordedAt(system.ID)
 		locationReq := &models.CreateEntityLocationRequest{
 			Position:   system.Position,
 			Source:     "Drone-Swarm-Simulation",
@@ -521,40 +1295,48 @@ func (s *DroneSwarmSimulation) deployEntities(ctx context.Context) error {
 
 	// Deploy UAS threats at 5-8km radius - within visual range but outside immediate engagement
 	// This allows for progressive classification: PENDING -> UNKNOWN -> SUSPECTED -> HOSTILE
-	threatRadius := 5000.0 + rand.Float64()*3000.0 // 5-8km initial distance - variable per threat
+	threatRadius := minSpawnRadius + s.random().Float64()*(maxSpawnRadius-minSpawnRadius) // Variable per run
 
-	for _, threat := range s.uasThreats {
-		// Random attack vector
-		angle := rand.Float64() * 360.0 * math.Pi / 180.0
-
-		// Calculate initial position
-		offsetX := threatRadius * math.Cos(angle)
-		offsetY := threatRadius * math.Sin(angle)
-
-		// Vary altitude by wave
-		altitude := baseZ + 100 + float64(threat.ActualCapabilities.WaveNumber)*50
+	// With launch sites, raids start on the ground at range instead
+	if len(s.config.LaunchSites) > 0 {
+		s.deployAtLaunchSites()
+	}
 
-		threat.Position.Coordinates[0] = baseX + offsetX
-		threat.Position.Coordinates[1] = baseY + offsetY
-		threat.Position.Coordinates[2] = altitude
+	// Recorded flights take over from launch sites and synthetic attack vectors
+	if len(s.config.ReplayTracks) > 0 {
+		s.deployReplayTracks()
+	}
 
-		// Calculate velocity towards base (hidden simulation data)
-		dx := baseX - threat.Position.Coordinates[0]
-		dy := baseY - threat.Position.Coordinates[1]
-		dz := baseZ - threat.Position.Coordinates[2]
+	for _, threat := range s.uasThreats {
+		// Threats at launch sites are already on the ground; replayed threats at their recording's start
+		if threat.LaunchPhase == "" && threat.Replay == nil {
+			// Random attack vector, with altitude varying by wave
+			angle := threat.random().Float64() * 360.0 * math.Pi / 180.0
+			threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2] =
+				s.spawnPosition(threatRadius, angle, threat.ActualCapabilities.WaveNumber)
+
+			// Calculate velocity towards the faction's objective (hidden simulation data)
+			objX, objY, objZ := s.objectiveECEF(threat)
+			dx := objX - threat.Position.Coordinates[0]
+			dy := objY - threat.Position.Coordinates[1]
+			dz := objZ - threat.Position.Coordinates[2]
+
+			// Normalize direction vector
+			distance := math.Sqrt(dx*dx + dy*dy + dz*dz)
+			velocityMagnitude := threat.ActualCapabilities.SpeedKph / 3.6 // Convert to m/s
 
-		// Normalize direction vector
-		distance := math.Sqrt(dx*dx + dy*dy + dz*dz)
-		velocityMagnitude := threat.ActualCapabilities.SpeedKph / 3.6 // Convert to m/s
+			threat.ActualVelocity.Coordinates[0] = (dx / distance) * velocityMagnitude
+			threat.ActualVelocity.Coordinates[1] = (dy / distance) * velocityMagnitude
+			threat.ActualVelocity.Coordinates[2] = (dz / distance) * velocityMagnitude
 
-		threat.ActualVelocity.Coordinates[0] = (dx / distance) * velocityMagnitude
-		threat.ActualVelocity.Coordinates[1] = (dy / distance) * velocityMagnitude
-		threat.ActualVelocity.Coordinates[2] = (dz / distance) * velocityMagnitude
+			// Waves of a later act wait at their start point until it begins
+			threat.ActHeld = s.waveLaunchOffset(threat.ActualCapabilities.WaveNumber) > 0
+		}
 
 		// Update location in Legion
-		recordedAt := time.Now()
+		recordedAt := s.recordedAt(threat.ID)
 		locationReq := &models.CreateEntityLocationRequest{
-			Position:   threat.Position,
+			Position:   s.observedPosition(threat),
 			Source:     "Drone-Swarm-Simulation",
 			RecordedAt: &recordedAt,
 		}
@@ -564,6 +1346,7 @@ func (s *DroneSwarmSimulation) deployEntities(ctx context.Context) error {
 		if err != nil {
 			return fmt.Errorf("failed to update UAS threat location: %w", err)
 		}
+		s.recordTrackHistory(threat)
 
 		// Threats start as PENDING until detected and classified
 		// No need to update status here as they're created with PENDING classification
@@ -574,12 +1357,17 @@ func (s *DroneSwarmSimulation) deployEntities(ctx context.Context) error {
 }
 
 // runSimulationLoop executes the main simulation loop
-func (s *DroneSwarmSimulation) runSimulationLoop(ctx context.Context) error {
+func (s *DroneSwarmSimulation) runSimulationLoop(ctx context.Context, startTime time.Time) error {
 	logger.Info("Starting main simulation loop...")
+	s.scenarioStart = startTime
+	s.scenarioEpoch = startTime
+	s.timeline = reporting.NewGanttRecorder(startTime)
+	s.acts = actState{index: -1}
+	s.advanceActs()
+	s.advanceScript()
 
-	startTime := time.Now()
-	ticker := time.NewTicker(s.config.UpdateInterval)
-	defer ticker.Stop()
+	ticks, stopTicks := s.newTickSource(startTime)
+	defer stopTicks()
 
 	simulationComplete := false
 
@@ -587,19 +1375,28 @@ func (s *DroneSwarmSimulation) runSimulationLoop(ctx context.Context) error {
 		select {
 		case <-ctx.Done():
 			logger.Info("Simulation cancelled by context")
+			s.logTickSummary(true)
 			// Flush any pending updates with timeout
 			flushCtx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
-			s.updateBuffer.Flush(flushCtx)
+			s.updateBuffer.ForceFlush(flushCtx)
 			cancel()
+			s.stopped = true
 			return ctx.Err()
 
 		case <-s.stopChan:
 			logger.Info("Simulation stopped by user")
+			s.logTickSummary(true)
+			s.stopped = true
 			return nil
 
-		case <-ticker.C:
-			// Check if simulation duration exceeded
-			if time.Since(startTime) > s.config.SimDuration {
+		case <-ticks:
+			// A paused run keeps Legion's picture fresh but does not advance
+			if s.holdPaused() {
+				continue
+			}
+
+			// Check if simulation duration exceeded, in scenario time
+			if s.scenarioElapsed() > s.config.SimDuration {
 				logger.Info("Simulation duration reached")
 				simulationComplete = true
 				break
@@ -608,7 +1405,7 @@ func (s *DroneSwarmSimulation) runSimulationLoop(ctx context.Context) error {
 			// Execute simulation phases
 			if err := s.executeSimulationPhases(ctx); err != nil {
 				// Check if this is an early termination (not an actual error)
-				if strings.Contains(err.Error(), "simulation terminated:") {
+				if errors.Is(err, errSimulationTerminated) {
 					simulationComplete = true
 					break
 				}
@@ -620,52 +1417,141 @@ func (s *DroneSwarmSimulation) runSimulationLoop(ctx context.Context) error {
 				simulationComplete = true
 			}
 
-			// Log progress
-			elapsed := time.Since(startTime)
-			logger.Infof("Simulation progress: %s / %s", elapsed.Round(time.Second), s.config.SimDuration)
+			// Show progress
+			s.recordClockOffset()
+			s.logTickSummary(false)
 		}
 	}
+	s.logTickSummary(true)
+
+	// Hold the outputs until the final tick's updates have reached Legion
+	s.setPhase(runPhaseShutdown)
+	s.awaitShutdown()
+	s.setPhase(runPhaseReporting)
+
+	s.publishSpectatorSnapshot(spectate.StatusComplete)
+	s.publishTimeMarker(ctx, true)
+	s.publishDatalink(ctx, true)
+
+	// Timeline and coverage overlays first so the AAR can list them
+	if err := s.saveTimeline(); err != nil {
+		logger.Errorf("Failed to save engagement timeline: %v", err)
+	}
+	if err := s.saveCoverageOverlay(); err != nil {
+		logger.Errorf("Failed to save coverage maps: %v", err)
+	}
+	if err := s.saveRecording(); err != nil {
+		logger.Errorf("Failed to save run recording: %v", err)
+	}
+	if err := s.saveReplayLog(); err != nil {
+		logger.Errorf("Failed to save replay log: %v", err)
+	}
+	if err := s.saveTimingTruth(); err != nil {
+		logger.Errorf("Failed to save timing errors: %v", err)
+	}
+	if err := s.saveTrainingPackage(); err != nil {
+		logger.Errorf("Failed to save training package: %v", err)
+	}
+	if err := s.verifyLegionRecord(ctx); err != nil {
+		logger.Errorf("Failed to verify Legion record: %v", err)
+	}
 
 	// Generate After Action Report
+	s.recordResourceMetrics()
+	s.recordBudgetMetrics()
+	s.recordReportMetrics()
+	s.recordTierMetrics()
+	s.recordCueMetrics()
+	s.recordPauseMetrics()
+	s.recordFactionOutcomes()
+	if err := s.saveEventLog(); err != nil {
+		logger.Errorf("Failed to save event log: %v", err)
+	}
 	if err := s.generateAAR(); err != nil {
 		logger.Errorf("Failed to generate AAR: %v", err)
 	}
 
+	// Sign before uploading so the manifest and signature travel with the bundle
+	if err := s.signArtifacts(); err != nil {
+		logger.Errorf("Failed to sign artifacts: %v", err)
+	}
+
+	// Upload outputs so ephemeral runs keep them
+	s.publishArtifacts(ctx)
+
+	s.setPhase(runPhaseComplete)
 	logger.Infof("Simulation completed. Outcome: %s", s.stats.SimulationOutcome)
 	return nil
 }
 
 // executeSimulationPhases runs the 5 phases of the simulation
 func (s *DroneSwarmSimulation) executeSimulationPhases(ctx context.Context) error {
+	// Pick up facilitator changes before anything reads the config this tick
+	s.tick++
+	s.applyReloads()
+	s.applyTuning()
+	s.advanceActs()
+	s.advanceScript()
+	s.applyCues(ctx)
+	s.applyOverrides()
+	s.checkDegraded()
+
+	// Phase 0: Shard Sync
+	if err := s.syncShards(ctx); err != nil {
+		logger.Warnf("Shard sync failed: %v", err)
+	}
+
+	// Read and write the world shared with other simulations
+	s.syncWorld(ctx)
+
 	// Phase 1: Swarm Coordination
-	if err := s.executeSwarmCoordination(ctx); err != nil {
+	if err := s.runPhase(ctx, PhaseCoordination, s.executeSwarmCoordination); err != nil {
 		return fmt.Errorf("swarm coordination phase failed: %w", err)
 	}
 
 	// Phase 2: Movement
-	if err := s.executeMovement(ctx); err != nil {
+	if err := s.runPhase(ctx, PhaseMovement, s.executeMovement); err != nil {
 		return fmt.Errorf("movement phase failed: %w", err)
 	}
 
 	// Phase 3: Detection
-	if err := s.executeDetection(ctx); err != nil {
+	if err := s.runPhase(ctx, PhaseDetection, s.executeDetection); err != nil {
 		return fmt.Errorf("detection phase failed: %w", err)
 	}
 
-	// Phase 4: Engagement
-	if err := s.executeEngagement(ctx); err != nil {
-		return fmt.Errorf("engagement phase failed: %w", err)
+	// Phase 4: Engagement. Ending the scenario here still finishes the tick so its
+	// kills are resolved, published and recorded before the AAR.
+	terminated := s.runPhase(ctx, PhaseEngagement, s.executeEngagement)
+	if terminated != nil && !errors.Is(terminated, errSimulationTerminated) {
+		return fmt.Errorf("engagement phase failed: %w", terminated)
 	}
+	s.updateCounterBattery()
+	s.updateAdaptation()
+	s.updateHazards(ctx)
 
 	// Phase 5: Resolution
-	if err := s.executeResolution(ctx); err != nil {
+	if err := s.runPhase(ctx, PhaseResolution, s.executeResolution); err != nil {
 		return fmt.Errorf("resolution phase failed: %w", err)
 	}
 
 	// Phase 6: Health Telemetry
 	s.updateSystemHealthTelemetry()
 
-	return nil
+	// Phase 7: Threat Board
+	s.updateThreatBoard(ctx)
+	s.publishTimeMarker(ctx, false)
+	s.publishDatalink(ctx, false)
+
+	// Phase 8: Stale track cleanup
+	s.collectStaleTracks(ctx)
+
+	// Phase 9: Spectator feed
+	s.publishSpectatorSnapshot(spectate.StatusRunning)
+
+	// Phase 10: Engagement timeline
+	s.recordTimeline(time.Now())
+
+	return terminated
 }
 
 // Phase 1: Swarm Coordination
@@ -673,29 +1559,35 @@ func (s *DroneSwarmSimulation) executeSwarmCoordination(_ context.Context) error
 	// Update swarm formations and behaviors
 	activeThreats := s.getActiveThreats()
 
-	// Group threats by wave (using hidden simulation data)
-	waveGroups := make(map[int][]*UASThreat)
+	// Group threats by faction and wave (using hidden simulation data); factions never
+	// coordinate with each other
+	type swarmKey struct {
+		faction string
+		wave    int
+	}
+	waveGroups := make(map[swarmKey][]*UASThreat)
+	var airborne []*UASThreat
 	for _, threat := range activeThreats {
-		waveGroups[threat.ActualCapabilities.WaveNumber] = append(waveGroups[threat.ActualCapabilities.WaveNumber], threat)
+		if threat.Remote {
+			continue // Flown by the owning shard
+		}
+		if threat.holdingAtLaunchSite() {
+			continue // Raids only fly as a swarm once they leave the assembly area
+		}
+		key := swarmKey{threat.ActualCapabilities.Faction, threat.ActualCapabilities.WaveNumber}
+		waveGroups[key] = append(waveGroups[key], threat)
+		airborne = append(airborne, threat)
 	}
 
-	// Coordinate each wave
-	for wave, threats := range waveGroups {
+	// Coordinate each faction's wave
+	for key, threats := range waveGroups {
 		if len(threats) < 2 {
 			continue
 		}
 
-		// Calculate center of mass for the wave
-		var sumX, sumY, sumZ float64
-		for _, threat := range threats {
-			sumX += threat.Position.Coordinates[0]
-			sumY += threat.Position.Coordinates[1]
-			sumZ += threat.Position.Coordinates[2]
-		}
-
-		centerX := sumX / float64(len(threats))
-		centerY := sumY / float64(len(threats))
-		_ = sumZ / float64(len(threats)) // centerZ - not currently used
+		// Center of mass for the swarm
+		center := s.swarmCenter(key.faction, key.wave, threats)
+		centerX, centerY := center.X, center.Y
 
 		// Apply swarm behavior if they're close enough to be identified as a swarm
 		for _, threat := range threats {
@@ -703,13 +1595,13 @@ func (s *DroneSwarmSimulation) executeSwarmCoordination(_ context.Context) error
 			dx := threat.Position.Coordinates[0] - centerX
 			dy := threat.Position.Coordinates[1] - centerY
 
-			desiredDistance := 100.0 // meters
+			desiredDistance := s.config.FormationSpacing // meters
 			currentDistance := math.Sqrt(dx*dx + dy*dy)
 
 			if currentDistance > desiredDistance*2 {
 				// Apply correction force while maintaining general direction
 				// Don't just reduce velocity - add a force towards the swarm center
-				correctionFactor := 0.05 // Reduced from 0.1 to be less aggressive
+				correctionFactor := s.config.CohesionWeight
 
 				// Add force towards swarm center
 				forceX := -(dx / currentDistance) * correctionFactor * 10.0
@@ -722,7 +1614,7 @@ func (s *DroneSwarmSimulation) executeSwarmCoordination(_ context.Context) error
 
 		// Mark threats as part of swarm if close enough (observable)
 		if len(threats) >= 3 {
-			swarmID := fmt.Sprintf("SWARM-%02d", wave)
+			swarmID := s.swarmID(key.faction, key.wave)
 			for _, threat := range threats {
 				threat.mu.Lock()
 				threat.IsPartOfSwarm = true
@@ -732,10 +1624,13 @@ func (s *DroneSwarmSimulation) executeSwarmCoordination(_ context.Context) error
 		}
 
 		if s.config.EnableDebugLogging {
-			logger.Debugf("Wave %d coordination: %d active threats", wave, len(threats))
+			behaviorLog.Debugf("%s wave %d coordination: %d active threats", key.faction, key.wave, len(threats))
 		}
 	}
 
+	// Raids from different factions that cross paths get in each other's way
+	s.applyFactionInterference(airborne)
+
 	return nil
 }
 
@@ -743,12 +1638,44 @@ func (s *DroneSwarmSimulation) executeSwarmCoordination(_ context.Context) error
 func (s *DroneSwarmSimulation) executeMovement(_ context.Context) error {
 	// Update UAS threat positions using hidden actual velocity
 	for _, threat := range s.uasThreats {
-		if threat.Classification == TrackStatusDestroyed || threat.Classification == TrackStatusLost {
+		if threat.Classification == TrackStatusDestroyed || threat.Classification == TrackStatusLost || threat.Remote || threat.ActHeld {
 			continue
 		}
 
 		// Update position based on actual velocity (simulation physics)
-		deltaTime := s.config.UpdateInterval.Seconds()
+		deltaTime := s.tickDelta()
+
+		// Replayed threats follow their recording instead of simulated physics
+		if s.advanceReplay(threat, deltaTime) {
+			if threat.Classification != TrackStatusLost {
+				if threat.Classification != TrackStatusPending {
+					threat.UpdateObservedKinematics(threat.Position)
+				}
+				if s.sendPositionsThisTick() {
+					s.updateBuffer.QueuePositionUpdate(threat.ID, s.observedPosition(threat))
+				}
+				s.recordTrackHistory(threat)
+			}
+			threat.LastUpdateTime = time.Now()
+			continue
+		}
+
+		// Grounded and assembling threats are positioned by their launch schedule
+		if s.advanceLaunch(threat, deltaTime) {
+			if threat.LaunchPhase == LaunchPhaseForming {
+				if threat.Classification != TrackStatusPending {
+					threat.UpdateObservedKinematics(threat.Position)
+				}
+				if s.sendPositionsThisTick() {
+					s.updateBuffer.QueuePositionUpdate(threat.ID, s.observedPosition(threat))
+				}
+				s.recordTrackHistory(threat)
+			}
+			threat.LastUpdateTime = time.Now()
+			continue
+		}
+		s.advanceIngress(threat)
+		s.steerForAct(threat)
 
 		// Log velocity for debugging if it's too low
 		speed := math.Sqrt(
@@ -757,14 +1684,10 @@ func (s *DroneSwarmSimulation) executeMovement(_ context.Context) error {
 				threat.ActualVelocity.Coordinates[2]*threat.ActualVelocity.Coordinates[2])
 
 		if speed < 10.0 { // Less than 10 m/s (36 kph) is too slow for our faster drones
-			logger.Warnf("Threat %s has very low speed: %.2f m/s, recalculating velocity", threat.TrackNumber, speed)
+			behaviorLog.Warnf("Threat %s has very low speed: %.2f m/s, recalculating velocity", threat.TrackNumber, speed)
 
-			// Recalculate velocity towards base
-			baseX, baseY, baseZ := latLonAltToECEF(
-				s.config.BaseLocation.Lat,
-				s.config.BaseLocation.Lon,
-				s.config.BaseLocation.Alt,
-			)
+			// Recalculate velocity towards the objective
+			baseX, baseY, baseZ := s.objectiveECEF(threat)
 
 			dx := baseX - threat.Position.Coordinates[0]
 			dy := baseY - threat.Position.Coordinates[1]
@@ -795,12 +1718,17 @@ func (s *DroneSwarmSimulation) executeMovement(_ context.Context) error {
 
 		// Only queue location update if threat is still active
 		if threat.Classification != TrackStatusDestroyed && threat.Classification != TrackStatusLost {
-			s.updateBuffer.QueuePositionUpdate(threat.ID, threat.Position)
+			if s.sendPositionsThisTick() {
+				s.updateBuffer.QueuePositionUpdate(threat.ID, s.observedPosition(threat))
+			}
+			s.recordTrackHistory(threat)
 		}
 
 		threat.LastUpdateTime = time.Now()
 	}
 
+	s.advanceGroundUnits(s.tickDelta())
+
 	// Counter-UAS systems may update their sensor modes
 	for _, system := range s.counterUASSystems {
 		// Update heading to track primary target
@@ -817,10 +1745,11 @@ func (s *DroneSwarmSimulation) executeMovement(_ context.Context) error {
 	}
 
 	// Flush position updates immediately for better map visibility
+	s.assignUpdateTiers()
 	flushCtx, cancel := context.WithTimeout(context.Background(), 500*time.Millisecond)
 	if err := s.updateBuffer.Flush(flushCtx); err != nil {
 		if err != context.DeadlineExceeded && err != context.Canceled {
-			logger.Debugf("Failed to flush movement updates: %v", err)
+			behaviorLog.Debugf("Failed to flush movement updates: %v", err)
 		}
 	}
 	cancel()
@@ -831,7 +1760,7 @@ func (s *DroneSwarmSimulation) executeMovement(_ context.Context) error {
 // Phase 3: Detection
 func (s *DroneSwarmSimulation) executeDetection(_ context.Context) error {
 	// For each Counter-UAS system, check for threats in detection range
-	for _, system := range s.counterUASSystems {
+	for _, system := range s.systemsByName() {
 		if system.Status == CounterUASStatusOffline {
 			continue
 		}
@@ -857,33 +1786,37 @@ func (s *DroneSwarmSimulation) executeDetection(_ context.Context) error {
 			// Log detection events and update threat classifications
 			for _, threat := range detectedThreats {
 				// More aggressive classification based on proximity and behavior
-				distance := calculateDistanceKm(system.Position, threat.Position)
+				distance, engageable := withinEnvelope(system, threat)
 
 				switch threat.Classification {
 				case TrackStatusPending:
 					threat.UpdateClassification(TrackStatusUnknown)
-					logger.Infof("🔵 Track %s classification: UNKNOWN - New contact detected at %.1fkm", threat.TrackNumber, distance)
+					s.recordCoveragePoint(threat, coverage.PointDetection)
+					s.recordRunEvent(threat, coverage.PointDetection)
+					engagementLog.Infof("🔵 Track %s classification: UNKNOWN - New contact detected at %.1fkm", threat.TrackNumber, distance)
 				case TrackStatusUnknown:
-					// Within engagement range = definitely hostile
-					if distance <= system.EffectiveRange {
+					// Within engagement envelope = definitely hostile
+					if engageable {
 						threat.UpdateClassification(TrackStatusHostile)
-						logger.Errorf("🔴 Track %s classification: HOSTILE - Within weapons range (%.1fkm)", threat.TrackNumber, distance)
+						engagementLog.Errorf("🔴 Track %s classification: HOSTILE - Within weapons range (%.1fkm)", threat.TrackNumber, distance)
 					} else if threat.EstimatedSpeed > 50 || threat.ObservedBehavior == BehaviorAggressive {
 						threat.UpdateClassification(TrackStatusSuspected)
-						logger.Warnf("🟡 Track %s classification: SUSPECTED - Approaching at %.0f kph", threat.TrackNumber, threat.EstimatedSpeed)
+						engagementLog.Warnf("🟡 Track %s classification: SUSPECTED - Approaching at %.0f kph", threat.TrackNumber, threat.EstimatedSpeed)
 					}
 				case TrackStatusSuspected:
 					// Upgrade to hostile if getting closer or if engaged
 					if distance <= system.EffectiveRange*1.5 || threat.TimesTargeted > 0 {
 						threat.UpdateClassification(TrackStatusHostile)
-						logger.Errorf("🔴 Track %s classification: HOSTILE - Confirmed enemy asset", threat.TrackNumber)
+						engagementLog.Errorf("🔴 Track %s classification: HOSTILE - Confirmed enemy asset", threat.TrackNumber)
 					}
 				}
 
-				// Update observable metadata
-				threatMetadata, _ := json.Marshal(threat.GetMetadata())
-				s.updateBuffer.QueueStatusUpdate(threat.ID, threat.Classification)
-				s.updateBuffer.QueueMetadataUpdate(threat.ID, "metadata", json.RawMessage(threatMetadata))
+				// Refresh intent estimate before publishing
+				s.updateIntent(threat)
+				s.observeBearing(threat)
+
+				// Report the detection to C2, which publishes it
+				s.reportDetection(system, threat)
 
 				// Log detection
 				s.simLogger.LogDetection(system.ID, threat.ID,
@@ -901,6 +1834,9 @@ func (s *DroneSwarmSimulation) executeDetection(_ context.Context) error {
 		}
 	}
 
+	// Publish the reports that have reached C2
+	s.deliverReports()
+
 	return nil
 }
 
@@ -928,55 +1864,32 @@ func (s *DroneSwarmSimulation) executeEngagement(ctx context.Context) error {
 				return
 			}
 
-			// Check engagement range
-			distance := calculateDistanceKm(sys.Position, target.Position)
-			if distance > sys.EffectiveRange {
+			// Check the engagement envelope for the target's speed and height
+			distance, engageable := withinEnvelope(sys, target)
+			if !engageable {
 				if s.config.EnableDebugLogging {
-					logger.Debugf("%s: Track %s beyond effective range: %.1fkm (max: %.1fkm)",
-						sys.Callsign, target.TrackNumber, distance, sys.EffectiveRange)
+					engagementLog.Debugf("%s: Track %s outside engagement envelope: %.1fkm at %.0f kph (max range: %.1fkm)",
+						sys.Callsign, target.TrackNumber, distance, target.EstimatedSpeed, sys.EffectiveRange)
 				}
 				return
 			}
 
 			// Log engagement attempt
-			logger.Infof("🎯 %s (%s) engaging track %s at %.1fkm", sys.Callsign, sys.Name, target.TrackNumber, distance)
+			engagementLog.Infof("🎯 %s (%s) engaging track %s at %.1fkm", sys.Callsign, sys.Name, target.TrackNumber, distance)
+			s.datalinkPairing(sys, target)
 
 			// Engage target
 			result := s.engageTarget(sys, target)
 			if result == nil {
-				logger.Error("engageTarget returned nil result")
+				engagementLog.Error("engageTarget returned nil result")
 				return
 			}
-			logger.Debugf("Engagement result created: %v", result)
+			engagementLog.Debugf("Engagement result created: %v", result)
 			engagementChan <- result
 		}(system)
 	}
 
-	logger.Debugf("Started %d engagement goroutines", engagementCount)
-
-	// Process results in a separate goroutine with context awareness
-	resultsChan := make(chan bool, 1)
-	go func() {
-		for {
-			select {
-			case result, ok := <-engagementChan:
-				if !ok {
-					resultsChan <- true
-					return
-				}
-				if result == nil {
-					logger.Error("Received nil engagement result")
-					continue
-				}
-				logger.Infof("📋 Processing engagement result: SystemID=%s, TargetID=%s, success=%v",
-					result.SystemID, result.TargetID, result.Success)
-				s.processEngagementResult(ctx, result)
-			case <-ctx.Done():
-				resultsChan <- false
-				return
-			}
-		}
-	}()
+	engagementLog.Debugf("Started %d engagement goroutines", engagementCount)
 
 	// Wait for all engagements to complete with context awareness
 	done := make(chan struct{})
@@ -990,22 +1903,39 @@ func (s *DroneSwarmSimulation) executeEngagement(ctx context.Context) error {
 		close(engagementChan)
 	case <-ctx.Done():
 		// Context cancelled, stop waiting
-		close(engagementChan)
 		return ctx.Err()
 	}
 
-	// Wait for all results to be processed or context cancellation
-	select {
-	case <-resultsChan:
-	case <-ctx.Done():
-		return ctx.Err()
+	// Resolve results in a fixed order rather than as the goroutines finish, so two
+	// systems engaging the same track settle it the same way in every seeded run
+	results := make([]*EngagementResult, 0, len(engagementChan))
+	for result := range engagementChan {
+		results = append(results, result)
+	}
+	s.mu.RLock()
+	sort.Slice(results, func(i, j int) bool {
+		a, b := s.counterUASSystems[results[i].SystemID], s.counterUASSystems[results[j].SystemID]
+		if a == nil || b == nil {
+			return a != nil
+		}
+		return a.Name < b.Name
+	})
+	s.mu.RUnlock()
+
+	for _, result := range results {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		engagementLog.Infof("📋 Processing engagement result: SystemID=%s, TargetID=%s, success=%v",
+			result.SystemID, result.TargetID, result.Success)
+		s.processEngagementResult(ctx, result)
 	}
 
 	// Check termination conditions immediately after engagements
 	if s.checkTerminationConditions() {
-		logger.Info("Simulation ending after engagement phase")
+		engagementLog.Info("Simulation ending after engagement phase")
 		// Return a special error to signal early termination
-		return fmt.Errorf("simulation terminated: %s", s.stats.SimulationOutcome)
+		return fmt.Errorf("%w: %s", errSimulationTerminated, s.stats.SimulationOutcome)
 	}
 
 	return nil
@@ -1014,7 +1944,7 @@ func (s *DroneSwarmSimulation) executeEngagement(ctx context.Context) error {
 // Phase 5: Resolution
 func (s *DroneSwarmSimulation) executeResolution(ctx context.Context) error {
 	// Update cooldowns
-	for _, system := range s.counterUASSystems {
+	for _, system := range s.systemsByName() {
 		if system.CooldownRemaining > 0 {
 			system.mu.Lock()
 			system.CooldownRemaining--
@@ -1024,10 +1954,20 @@ func (s *DroneSwarmSimulation) executeResolution(ctx context.Context) error {
 			system.mu.Unlock()
 		}
 
+		// Jamming from EW payloads wears off
+		if system.JammedTicks > 0 {
+			system.mu.Lock()
+			system.JammedTicks--
+			if system.JammedTicks == 0 && system.DataLinkStatus == "DEGRADED" {
+				system.DataLinkStatus = "ONLINE"
+			}
+			system.mu.Unlock()
+		}
+
 		// Check ammo depletion
 		if system.EngagementType == EngagementTypeKinetic && system.AmmoRemaining == 0 {
 			system.UpdateStatus(CounterUASStatusOffline)
-			logger.Warnf("⚠️ %s (%s) ammunition depleted - system offline", system.Callsign, system.Name)
+			engagementLog.Warnf("⚠️ %s (%s) ammunition depleted - system offline", system.Callsign, system.Name)
 		}
 
 		// Check if system is overwhelmed (too many threats in close proximity)
@@ -1050,15 +1990,15 @@ func (s *DroneSwarmSimulation) executeResolution(ctx context.Context) error {
 			// Temperature spikes when overwhelmed
 			system.Temperature = math.Min(85.0, system.Temperature+10.0)
 
-			if rand.Float64() < 0.1 { // 10% chance of going offline when overwhelmed
+			if system.random().Float64() < 0.1 { // 10% chance of going offline when overwhelmed
 				system.Status = CounterUASStatusOffline
-				logger.Errorf("💥 %s (%s) OVERWHELMED - system offline!", system.Callsign, system.Name)
+				engagementLog.Errorf("💥 %s (%s) OVERWHELMED - system offline!", system.Callsign, system.Name)
 				s.stats.mu.Lock()
 				s.stats.CounterUASLosses++
 				s.stats.mu.Unlock()
 			} else if system.Status != CounterUASStatusDegraded {
 				system.Status = CounterUASStatusDegraded
-				logger.Warnf("⚠️ %s (%s) under heavy attack - system degraded", system.Callsign, system.Name)
+				engagementLog.Warnf("⚠️ %s (%s) under heavy attack - system degraded", system.Callsign, system.Name)
 			}
 
 			system.mu.Unlock()
@@ -1066,7 +2006,9 @@ func (s *DroneSwarmSimulation) executeResolution(ctx context.Context) error {
 			// Send immediate health telemetry when overwhelmed
 			ctx := context.Background()
 			if err := s.sendHealthTelemetryViaFeed(ctx, system); err != nil {
-				logger.Errorf("Failed to send critical health telemetry for %s: %v", system.Callsign, err)
+				if !errors.Is(err, client.ErrCircuitOpen) {
+					engagementLog.Errorf("Failed to send critical health telemetry for %s: %v", system.Callsign, err)
+				}
 				// Fallback to metadata updates
 				s.updateBuffer.QueueMetadataUpdate(system.ID, "system_health", system.SystemHealth)
 				s.updateBuffer.QueueMetadataUpdate(system.ID, "status", CounterUASStatusDegraded)
@@ -1093,24 +2035,58 @@ func (s *DroneSwarmSimulation) executeResolution(ctx context.Context) error {
 	}
 
 	for _, threat := range s.uasThreats {
-		if threat.Classification == TrackStatusDestroyed || threat.Classification == TrackStatusLost {
+		if threat.Classification == TrackStatusDestroyed || threat.Classification == TrackStatusLost || threat.Remote {
 			continue
 		}
 
-		// Check if threat reached target
-		distance := calculateDistanceKm(threat.Position, basePos)
+		// Check if threat reached its faction's objective, or the shared asset it was sent after
+		target := basePos
+		faction := s.factionOf(threat)
+		sharedAsset, sharedTarget := s.worldTarget(threat)
+		if faction.ObjectiveOffset > 0 || sharedTarget {
+			objX, objY, objZ := s.objectiveECEF(threat)
+			target = &models.GeomPoint{Type: &pointType, Coordinates: []float64{objX, objY, objZ}}
+		}
+		distance := calculateDistanceKm(threat.Position, target)
 		if distance < 0.5 { // Within 500m of target
 			threat.UpdateClassification(TrackStatusLost) // Lost track once it reaches target
+			consequence, jammed := s.applyPayloadEffects(threat)
+			unitsHit, personnel := s.strikeGroundUnits(threat)
+			s.recordCoveragePoint(threat, coverage.PointLeaker)
+			s.recordRunEvent(threat, coverage.PointLeaker)
+			s.recordTrainingLeaker(threat)
+			objective := s.objectiveName(threat, threat.WorldTarget)
+			if sharedTarget {
+				s.strikeWorldAsset(ctx, sharedAsset)
+			}
 
 			s.stats.mu.Lock()
 			s.stats.UASPenetrated++
+			s.stats.GroundUnitsHit += len(unitsHit)
+			s.stats.PersonnelAtRisk += personnel
+			axis := s.recordLeaker(threat, objective)
 			s.stats.mu.Unlock()
 
 			// Log mission complete
-			logger.Errorf("💥 Track %s reached protected area - MISSION FAILURE", threat.TrackNumber)
-			s.simLogger.LogObjective("UAS", "reached_target", "complete", map[string]interface{}{
+			payload := threat.ActualCapabilities.PayloadType
+			engagementLog.Errorf("💥 Track %s (%s) reached %s from the %s (wave %d)",
+				threat.TrackNumber, payload, objective, axis, threat.ActualCapabilities.WaveNumber)
+			if len(jammed) > 0 {
+				engagementLog.Warnf("📡 %s jamming %d defenders: %s", threat.TrackNumber, len(jammed), strings.Join(jammed, ", "))
+			}
+			if len(unitsHit) > 0 {
+				engagementLog.Errorf("🪖 %s hit %s (%d personnel at risk)", threat.TrackNumber, strings.Join(unitsHit, ", "), personnel)
+			}
+			s.simLogger.LogObjective(faction.Name, "reached_target", "complete", map[string]interface{}{
 				"track_id":     threat.ID.String(),
 				"track_number": threat.TrackNumber,
+				"asset":        objective,
+				"wave":         threat.ActualCapabilities.WaveNumber,
+				"axis":         axis,
+				"payload":      payload,
+				"consequence":  consequence,
+				"units_hit":    unitsHit,
+				"personnel":    personnel,
 			})
 		}
 	}
@@ -1121,7 +2097,7 @@ func (s *DroneSwarmSimulation) executeResolution(ctx context.Context) error {
 	if err := s.updateBuffer.Flush(flushCtx); err != nil {
 		// Don't block on flush errors during resolution
 		if err != context.DeadlineExceeded && err != context.Canceled {
-			logger.Errorf("Failed to flush updates: %v", err)
+			engagementLog.Errorf("Failed to flush updates: %v", err)
 		}
 	}
 
@@ -1133,6 +2109,12 @@ func (s *DroneSwarmSimulation) executeResolution(ctx context.Context) error {
 
 // Helper methods
 
+// distanceToBaseKm returns a position's distance from the defended base
+func (s *DroneSwarmSimulation) distanceToBaseKm(position *models.GeomPoint) float64 {
+	baseX, baseY, baseZ := latLonAltToECEF(s.config.BaseLocation.Lat, s.config.BaseLocation.Lon, s.config.BaseLocation.Alt)
+	return calculateDistanceKm(&models.GeomPoint{Coordinates: []float64{baseX, baseY, baseZ}}, position)
+}
+
 // getActiveThreats returns all non-eliminated threats
 func (s *DroneSwarmSimulation) getActiveThreats() []*UASThreat {
 	s.mu.RLock()
@@ -1144,9 +2126,51 @@ func (s *DroneSwarmSimulation) getActiveThreats() []*UASThreat {
 			active = append(active, threat)
 		}
 	}
+	sort.Slice(active, func(i, j int) bool { return active[i].TrackNumber < active[j].TrackNumber })
 	return active
 }
 
+// systemsByName returns the Counter-UAS systems in name order. Phases where one system's
+// turn changes what the next sees visit them in this order, so seeded runs repeat.
+func (s *DroneSwarmSimulation) systemsByName() []*CounterUASSystem {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	systems := make([]*CounterUASSystem, 0, len(s.counterUASSystems))
+	for _, system := range s.counterUASSystems {
+		systems = append(systems, system)
+	}
+	sort.Slice(systems, func(i, j int) bool { return systems[i].Name < systems[j].Name })
+	return systems
+}
+
+// queueClassificationUpdate publishes a track's classification as its status and, when the
+// classification implies a new affiliation, updates the entity's affiliation so the
+// operational picture's symbology changes with it
+func (s *DroneSwarmSimulation) queueClassificationUpdate(threat *UASThreat) {
+	threat.mu.RLock()
+	classification, affiliation := threat.Classification, threat.Affiliation
+	threat.mu.RUnlock()
+	s.publishClassification(threat, classification, affiliation)
+}
+
+// publishClassification publishes a classification as the track's status, and its
+// affiliation when that differs from the one Legion shows
+func (s *DroneSwarmSimulation) publishClassification(threat *UASThreat, classification string, affiliation models.Affiliation) {
+	threat.mu.Lock()
+	changed := affiliation != "" && affiliation != threat.PublishedAffiliation
+	if changed {
+		threat.PublishedAffiliation = affiliation
+	}
+	threat.mu.Unlock()
+
+	s.updateBuffer.QueueStatusUpdate(threat.ID, classification)
+	if changed {
+		s.updateBuffer.QueueAffiliationUpdate(threat.ID, affiliation)
+		logger.Debugf("Track %s affiliation → %s", threat.TrackNumber, affiliation)
+	}
+}
+
 // detectThreats returns threats within detection range
 func (s *DroneSwarmSimulation) detectThreats(system *CounterUASSystem) []*UASThreat {
 	detected := make([]*UASThreat, 0)
@@ -1155,6 +2179,9 @@ func (s *DroneSwarmSimulation) detectThreats(system *CounterUASSystem) []*UASThr
 		if threat.Classification == TrackStatusDestroyed || threat.Classification == TrackStatusLost {
 			continue
 		}
+		if threat.LaunchPhase == LaunchPhaseGrounded || threat.ActHeld {
+			continue // Not yet airborne
+		}
 
 		distance := calculateDistanceKm(system.Position, threat.Position)
 
@@ -1181,6 +2208,7 @@ func (s *DroneSwarmSimulation) detectThreats(system *CounterUASSystem) []*UASThr
 			detected = append(detected, threat)
 		}
 	}
+	sort.Slice(detected, func(i, j int) bool { return detected[i].TrackNumber < detected[j].TrackNumber })
 
 	return detected
 }
@@ -1193,14 +2221,27 @@ func (s *DroneSwarmSimulation) selectTarget(system *CounterUASSystem) *UASThreat
 	}
 
 	// Prioritize by:
-	// 1. Already targeted threats (continue engagement)
-	// 2. Closest threat
-	// 3. Highest autonomy level (more dangerous)
+	// 1. The threat board's priority (time-to-impact, classification, proximity to base)
+	// 2. Already targeted threats (continue engagement)
+	// 3. Closest threat
+	// 4. Highest threat level
+
+	baseX, baseY, baseZ := latLonAltToECEF(s.config.BaseLocation.Lat, s.config.BaseLocation.Lon, s.config.BaseLocation.Alt)
+	basePoint := &models.GeomPoint{Coordinates: []float64{baseX, baseY, baseZ}}
 
 	var bestTarget *UASThreat
 	bestScore := -1.0
+	candidates := make([]reporting.DecisionCandidate, 0, len(threats))
 
 	for _, threat := range threats {
+		// The act's rules of engagement may hold fire on some or all tracks
+		if !s.roePermits(threat) {
+			continue
+		}
+		// C2 tasks systems only against tracks it has been sent
+		if !s.reportedToC2(threat) {
+			continue
+		}
 		score := 0.0
 
 		// Distance factor (closer = higher priority)
@@ -1211,37 +2252,63 @@ func (s *DroneSwarmSimulation) selectTarget(system *CounterUASSystem) *UASThreat
 		// Threat level factor
 		score += float64(threat.ThreatLevel) / 5.0 * 0.3
 
-		// Classification factor (prioritize confirmed hostiles)
-		switch threat.Classification {
-		case TrackStatusHostile:
-			score += 0.3
-		case TrackStatusSuspected:
-			score += 0.2
-		case TrackStatusUnknown:
-			score += 0.1
-		}
-
 		// Already engaged bonus
 		if system.EngagedTarget != nil && *system.EngagedTarget == threat.ID {
 			score += 0.2
 		}
 
+		// Board priority (imminent, confirmed hostiles float to the top)
+		threat.mu.RLock()
+		score += s.threatPriority(threat, calculateDistanceKm(basePoint, threat.Position)) * 1.5
+		timeToImpact := 0.0
+		if threat.Intent != nil {
+			timeToImpact = threat.Intent.TimeToImpact.Seconds()
+		}
+		threat.mu.RUnlock()
+
+		candidates = append(candidates, reporting.DecisionCandidate{
+			TrackID:        threat.ID.String(),
+			TrackNumber:    threat.TrackNumber,
+			Classification: threat.Classification,
+			ThreatLevel:    threat.ThreatLevel,
+			RangeKm:        distance,
+			TimeToImpactS:  timeToImpact,
+			Score:          score,
+		})
+
 		if score > bestScore {
 			bestScore = score
 			bestTarget = threat
 		}
 	}
 
+	if bestTarget == nil {
+		return nil
+	}
+
+	// Keep what the system saw for the trainee debrief
+	for i := range candidates {
+		candidates[i].Chosen = candidates[i].TrackID == bestTarget.ID.String()
+	}
+	s.noteDecision(system, candidates)
+
+	bestTarget.mu.Lock()
+	if bestTarget.AssignedAt.IsZero() {
+		bestTarget.AssignedAt = time.Now()
+	}
+	bestTarget.mu.Unlock()
+
 	return bestTarget
 }
 
 // EngagementResult represents the outcome of an engagement
 type EngagementResult struct {
-	SystemID   uuid.UUID
-	TargetID   uuid.UUID
-	Success    bool
-	Distance   float64
-	EngageType string
+	SystemID    uuid.UUID
+	TargetID    uuid.UUID
+	Success     bool
+	Distance    float64
+	EngageType  string
+	ActiveLinks []string // Target nav/control links in use (EW only)
 }
 
 // engageTarget attempts to engage a threat
@@ -1263,6 +2330,9 @@ func (s *DroneSwarmSimulation) engageTarget(system *CounterUASSystem, target *UA
 	// Update threat engagement history
 	target.mu.Lock()
 	target.TimesTargeted++
+	if target.FirstShotAt.IsZero() {
+		target.FirstShotAt = time.Now()
+	}
 	if system.EngagementType == EngagementTypeKinetic {
 		target.KineticAttempts++
 	} else {
@@ -1293,16 +2363,18 @@ func (s *DroneSwarmSimulation) engageTarget(system *CounterUASSystem, target *UA
 		sizeModifier = 0.9
 	}
 
-	// Jamming resistance (for EW attacks)
+	// Jamming only bites on the RF links the target is relying on right now
 	jamResistanceModifier := 1.0
-	if system.EngagementType == EngagementTypeEW && target.ShowsJamResistance {
-		jamResistanceModifier = 0.5
+	if system.EngagementType == EngagementTypeEW {
+		terminal := s.distanceToBaseKm(target.Position) < core.TerminalRangeKm
+		jamResistanceModifier = target.ActualCapabilities.Nav.JamSusceptibility(terminal)
+		result.ActiveLinks = target.ActualCapabilities.Nav.ActiveLinks(terminal)
 	}
 
-	finalProbability := baseProbability * rangeFactor * evasionModifier * sizeModifier * jamResistanceModifier
+	finalProbability := baseProbability * rangeFactor * evasionModifier * sizeModifier * jamResistanceModifier * s.config.SuccessRateModifier
 
 	// Roll for success
-	if rand.Float64() < finalProbability {
+	if system.random().Float64() < finalProbability {
 		result.Success = true
 		system.SuccessfulEngagements++
 	}
@@ -1338,10 +2410,16 @@ func (s *DroneSwarmSimulation) processEngagementResult(_ context.Context, result
 	s.mu.RUnlock()
 
 	if !threatExists || !systemExists {
-		logger.Errorf("Failed to find entities for engagement result: threat=%v, system=%v", threatExists, systemExists)
+		engagementLog.Errorf("Failed to find entities for engagement result: threat=%v, system=%v", threatExists, systemExists)
 		return
 	}
 
+	s.recordCoveragePoint(threat, coverage.PointEngagement)
+	s.recordEngagementPoint(system, threat, result)
+	s.resolveDecision(system.ID, result.Success)
+	s.datalinkEngagement(system, threat, result)
+	s.recordHazards(system, threat)
+
 	s.stats.mu.Lock()
 	s.stats.TotalEngagements++
 	if result.Success {
@@ -1352,26 +2430,32 @@ func (s *DroneSwarmSimulation) processEngagementResult(_ context.Context, result
 
 	if result.Success {
 		threat.UpdateClassification(TrackStatusDestroyed)
-		logger.Infof("💥 %s (%s) destroyed track %s - SPLASH ONE!", system.Callsign, system.Name, threat.TrackNumber)
+		engagementLog.Infof("💥 %s (%s) destroyed track %s - SPLASH ONE!", system.Callsign, system.Name, threat.TrackNumber)
 
 		// Update status in Legion to show destroyed
-		s.updateBuffer.QueueStatusUpdate(threat.ID, TrackStatusDestroyed)
+		s.queueClassificationUpdate(threat)
+
+		// Tell the owning shard to stop flying it
+		if threat.Remote {
+			s.queueShardKill(threat, system)
+		}
 
 		// Log elimination
 		s.simLogger.LogDestruction(
 			result.TargetID,
-			"UAS-Threats",
+			s.factionOf(threat).Name,
 			fmt.Sprintf("destroyed by %s at %.1fkm (%s)",
 				system.Callsign,
 				result.Distance,
 				result.EngageType),
 		)
+		s.logKillChain(threat, system)
 	} else {
-		logger.Infof("❌ %s (%s) missed track %s", system.Callsign, system.Name, threat.TrackNumber)
+		engagementLog.Infof("❌ %s (%s) missed track %s", system.Callsign, system.Name, threat.TrackNumber)
 
 		// Update behavior based on engagement
 		threat.mu.Lock()
-		if threat.ActualCapabilities.EvasionCapability && rand.Float64() > 0.3 {
+		if threat.ActualCapabilities.EvasionCapability && threat.random().Float64() > 0.3 {
 			threat.ObservedBehavior = BehaviorEvasive
 		}
 
@@ -1396,9 +2480,10 @@ func (s *DroneSwarmSimulation) processEngagementResult(_ context.Context, result
 		result.TargetID,
 		fmt.Sprintf("%s engagement", result.EngageType),
 		map[string]interface{}{
-			"distance_km": result.Distance,
-			"hit":         result.Success,
-			"type":        result.EngageType,
+			"distance_km":  result.Distance,
+			"hit":          result.Success,
+			"type":         result.EngageType,
+			"active_links": result.ActiveLinks,
 		},
 	)
 
@@ -1412,7 +2497,7 @@ func (s *DroneSwarmSimulation) processEngagementResult(_ context.Context, result
 	}
 
 	// Update threat status
-	s.updateBuffer.QueueStatusUpdate(threat.ID, threat.Classification)
+	s.queueClassificationUpdate(threat)
 	threatMetadata, _ := json.Marshal(threat.GetMetadata())
 	s.updateBuffer.QueueMetadataUpdate(threat.ID, "metadata", json.RawMessage(threatMetadata))
 }
@@ -1420,7 +2505,7 @@ func (s *DroneSwarmSimulation) processEngagementResult(_ context.Context, result
 // applyEvasiveManeuvers modifies threat velocity for evasion
 func (s *DroneSwarmSimulation) applyEvasiveManeuvers(threat *UASThreat) {
 	// Random direction change
-	angleChange := (rand.Float64() - 0.5) * 60 * math.Pi / 180 // ±30 degrees
+	angleChange := (threat.random().Float64() - 0.5) * 60 * math.Pi / 180 // ±30 degrees
 
 	// Current velocity magnitude
 	vMag := math.Sqrt(threat.ActualVelocity.Coordinates[0]*threat.ActualVelocity.Coordinates[0] +
@@ -1437,7 +2522,7 @@ func (s *DroneSwarmSimulation) applyEvasiveManeuvers(threat *UASThreat) {
 	threat.ActualVelocity.Coordinates[1] = vMag * math.Sin(newAngle)
 
 	// Random altitude change
-	threat.ActualVelocity.Coordinates[2] = (rand.Float64() - 0.5) * 10 // ±5 m/s vertical
+	threat.ActualVelocity.Coordinates[2] = (threat.random().Float64() - 0.5) * 10 // ±5 m/s vertical
 }
 
 // updateStatistics updates simulation statistics
@@ -1461,6 +2546,10 @@ func (s *DroneSwarmSimulation) updateStatistics() {
 		activeSystems, s.config.NumCounterUASSystems,
 		activeThreats, s.config.NumUASThreats,
 		s.stats.TotalEngagements, s.stats.SuccessfulEngagements)
+
+	if s.stats.UASPenetrated > 0 {
+		logger.Warnf("Leakers: %d (%s)", s.stats.UASPenetrated, s.stats.Leakage.leakageSummary())
+	}
 }
 
 // checkTerminationConditions checks if simulation should end
@@ -1480,22 +2569,24 @@ func (s *DroneSwarmSimulation) checkTerminationConditions() bool {
 	// Success: All threats eliminated
 	if activeThreats == 0 {
 		s.stats.SimulationOutcome = "SUCCESS - All threats eliminated"
+		s.stats.Termination = terminationThreatsEliminated
 		logger.Info("🎉 Termination condition met: All threats eliminated - DEFENDERS WIN!")
 		return true
 	}
 
-	// Failure: All defensive systems destroyed
-	if activeSystems == 0 {
+	// Failure: All defensive systems destroyed (workers don't own any)
+	if activeSystems == 0 && s.ownsBlueForce() {
 		s.stats.SimulationOutcome = "FAILURE - All defensive systems destroyed"
+		s.stats.Termination = terminationDefensesDestroyed
 		logger.Error("💀 Termination condition met: All defensive systems destroyed - ATTACKERS WIN!")
 		return true
 	}
 
-	// Failure: Too many threats penetrated defenses (lowered threshold to 30%)
-	penetrationRate := float64(s.stats.UASPenetrated) / float64(s.config.NumUASThreats)
-	if penetrationRate > 0.3 {
-		s.stats.SimulationOutcome = fmt.Sprintf("FAILURE - %.0f%% of threats penetrated defenses", penetrationRate*100)
-		logger.Errorf("💥 Termination condition met: %.0f%% penetration rate - ATTACKERS WIN!", penetrationRate*100)
+	// Failure: Leakage exceeded the configured asset, wave or global thresholds
+	if outcome := s.evaluateLeakage(); outcome != "" {
+		s.stats.SimulationOutcome = outcome
+		s.stats.Termination = terminationLeakageExceeded
+		logger.Errorf("💥 Termination condition met: %s (%s) - ATTACKERS WIN!", outcome, s.stats.Leakage.leakageSummary())
 		return true
 	}
 
@@ -1514,9 +2605,11 @@ func (s *DroneSwarmSimulation) generateAAR() error {
 	}
 
 	// Save report
-	if err := s.aarGenerator.SaveAAR(aar); err != nil {
+	path, err := s.aarGenerator.SaveAAR(aar)
+	if err != nil {
 		return fmt.Errorf("failed to save AAR: %w", err)
 	}
+	s.artifacts = append(s.artifacts, path)
 
 	logger.Info("After Action Report generated successfully")
 	return nil
@@ -1540,7 +2633,7 @@ func (s *DroneSwarmSimulation) Stop() error {
 		// Then flush any remaining updates with timeout
 		ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
 		defer cancel()
-		_ = s.updateBuffer.Flush(ctx)
+		_ = s.updateBuffer.ForceFlush(ctx)
 	}
 
 	if s.simController != nil {
@@ -1559,53 +2652,20 @@ func (s *DroneSwarmSimulation) cleanupExistingEntities(ctx context.Context) erro
 	// Reset track number counter to ensure clean start
 	atomic.StoreUint32(&trackNumberCounter, 0)
 
-	// Create organization context
-	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
-
-	// List of entity name patterns to clean up
-	patterns := []string{
-		"Counter-UAS-",
-		"UAS-W",
-		"TK-",
+	orgID, err := uuid.Parse(s.config.OrganizationID)
+	if err != nil {
+		logger.Warnf("Invalid organization ID during cleanup: %v", err)
+		return nil
 	}
 
-	deletedCount := 0
-
-	// Search for each pattern separately to avoid overwhelming the API
-	for _, pattern := range patterns {
-		orgID, err := uuid.Parse(s.config.OrganizationID)
-		if err != nil {
-			logger.Warnf("Invalid organization ID during cleanup: %v", err)
-			continue
-		}
-		searchReq := &models.SearchEntitiesRequest{
-			OrganizationID: &orgID,
-			Filters: &models.SearchFilters{
-				Name: pattern, // This will do a prefix match
-			},
-		}
-
-		result, err := s.legionClient.SearchEntities(orgCtx, searchReq)
-		if err != nil {
-			logger.Warnf("Failed to search for entities with pattern %s: %v", pattern, err)
-			continue
-		}
-
-		// Delete matching entities
-		for _, entity := range result.Results {
-			if strings.HasPrefix(entity.Name, pattern) {
-				if err := s.legionClient.DeleteEntity(orgCtx, entity.ID.String()); err != nil {
-					logger.Debugf("Failed to delete entity %s (%s): %v", entity.Name, entity.ID, err)
-				} else {
-					logger.Debugf("Deleted entity: %s", entity.Name)
-					deletedCount++
-				}
-			}
-		}
+	plan, err := cleanup.Find(ctx, s.legionClient, orgID, cleanup.Selector{EntityPrefixes: runEntityPrefixes, RunID: s.config.RunID})
+	if err != nil {
+		logger.Warnf("Failed to search for some entities to clean up: %v", err)
 	}
+	result := cleanup.Delete(ctx, s.legionClient, orgID, plan)
 
-	if deletedCount > 0 {
-		logger.Infof("Cleaned up %d existing entities", deletedCount)
+	if result.EntitiesDeleted > 0 {
+		logger.Infof("Cleaned up %d existing entities", result.EntitiesDeleted)
 	} else {
 		logger.Info("No existing entities found to clean up")
 	}
@@ -1617,61 +2677,25 @@ func (s *DroneSwarmSimulation) cleanupExistingEntities(ctx context.Context) erro
 func (s *DroneSwarmSimulation) cleanupOrphanedFeeds(ctx context.Context) error {
 	logger.Info("Cleaning up orphaned feed definitions...")
 
-	// Create organization context
-	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
-
-	// Feed name patterns to clean up (matching createHealthTelemetryFeed patterns)
-	feedPatterns := []string{
-		"cuas_health_telemetry_Counter-UAS-",
-		"cuas_health_telemetry_DEFENDER-",
-		"cuas_health_telemetry_GUARDIAN-",
-		"cuas_health_telemetry_HAWK-",
-		"cuas_health_telemetry_SENTRY-",
-	}
-
-	deletedFeedCount := 0
+	// Clear our internal feed tracking
+	s.systemHealthFeeds = make(map[uuid.UUID]uuid.UUID)
 
-	// Search for feeds with our naming patterns
-	category := models.MessageCategoryMESSAGE
-	searchReq := &models.FeedDefinitionSearchRequest{
-		Category: category,
+	orgID, err := uuid.Parse(s.config.OrganizationID)
+	if err != nil {
+		logger.Warnf("Invalid organization ID during cleanup: %v", err)
+		return nil
 	}
 
 	logger.Debug("Searching for feed definitions to clean up...")
-	searchResult, err := s.legionClient.SearchFeedDefinitions(orgCtx, searchReq)
+	plan, err := cleanup.Find(ctx, s.legionClient, orgID, cleanup.Selector{FeedPatterns: runFeedPatterns, RunID: s.config.RunID})
 	if err != nil {
 		logger.Warnf("Failed to search for feed definitions during cleanup: %v", err)
 		return nil // Continue with simulation even if cleanup fails
 	}
+	result := cleanup.Delete(ctx, s.legionClient, orgID, plan)
 
-	if searchResult != nil && len(searchResult.Results) > 0 {
-		for _, feed := range searchResult.Results {
-			// Check if this feed matches our naming patterns
-			shouldDelete := false
-			for _, pattern := range feedPatterns {
-				if strings.Contains(feed.FeedName, pattern) {
-					shouldDelete = true
-					break
-				}
-			}
-
-			if shouldDelete {
-				logger.Debugf("Deleting orphaned feed: %s (ID: %s)", feed.FeedName, feed.ID)
-				if err := s.legionClient.DeleteFeedDefinition(orgCtx, feed.ID.String()); err != nil {
-					logger.Warnf("Failed to delete feed %s (ID: %s): %v", feed.FeedName, feed.ID, err)
-				} else {
-					deletedFeedCount++
-					logger.Debugf("Successfully deleted feed: %s", feed.FeedName)
-				}
-			}
-		}
-	}
-
-	// Clear our internal feed tracking
-	s.systemHealthFeeds = make(map[uuid.UUID]uuid.UUID)
-
-	if deletedFeedCount > 0 {
-		logger.Infof("Cleaned up %d orphaned feed definitions", deletedFeedCount)
+	if result.FeedsDeleted > 0 {
+		logger.Infof("Cleaned up %d orphaned feed definitions", result.FeedsDeleted)
 	} else {
 		logger.Info("No orphaned feed definitions found to clean up")
 	}
@@ -1683,7 +2707,8 @@ func (s *DroneSwarmSimulation) cleanupOrphanedFeeds(ctx context.Context) error {
 func (s *DroneSwarmSimulation) createHealthTelemetryFeed(ctx context.Context, systemID uuid.UUID, systemName string) (uuid.UUID, error) {
 	// Create feed definition request with unique name per entity
 	// Include entity ID in feed name to ensure global uniqueness
-	feedName := fmt.Sprintf("cuas_health_telemetry_%s_%s", systemName, systemID.String()[:8])
+	_, baseName := simulation.SplitNamespace(systemName)
+	feedName := s.named(fmt.Sprintf("cuas_health_telemetry_%s_%s", baseName, systemID.String()[:8]))
 	description := fmt.Sprintf("Health telemetry data for Counter-UAS system %s", systemName)
 	category := models.MessageCategoryMESSAGE
 	dataType := "application/json"
@@ -1721,11 +2746,11 @@ func (s *DroneSwarmSimulation) createHealthTelemetryFeed(ctx context.Context, sy
 	// Create new feed
 	logger.Debugf("Creating feed definition for system %s (ID: %s) with name: %s", systemName, systemID.String(), feedName)
 	logger.Debugf("Feed request - OrgID from context: %s, EntityID: %s, Category: %s", s.config.OrganizationID, systemID.String(), feedReq.Category)
-	createdFeed, err := s.legionClient.CreateFeedDefinition(orgCtx, feedReq)
+	createdFeed, err := s.legionClient.CreateFeedDefinition(s.idempotent(orgCtx, "feed", feedName), feedReq)
 	if err != nil {
-		// If we get a 409, it might be from a previous run with the same name
+		// A conflict might be from a previous run with the same name
 		// Try to find it by searching without entity filter
-		if strings.Contains(err.Error(), "409") {
+		if errors.Is(err, client.ErrConflict) {
 			logger.Warnf("Feed name conflict for %s, searching for existing feed", feedName)
 
 			// Search by feed name pattern
@@ -1751,6 +2776,10 @@ func (s *DroneSwarmSimulation) createHealthTelemetryFeed(ctx context.Context, sy
 
 // sendHealthTelemetryViaFeed sends health telemetry data through the feed
 func (s *DroneSwarmSimulation) sendHealthTelemetryViaFeed(ctx context.Context, system *CounterUASSystem) error {
+	if s.legionDegraded() {
+		// Callers fall back to metadata updates, which wait in the update buffer
+		return client.ErrCircuitOpen
+	}
 	feedID, exists := s.systemHealthFeeds[system.ID]
 	if !exists {
 		// No feed configured, skip
@@ -1763,8 +2792,12 @@ func (s *DroneSwarmSimulation) sendHealthTelemetryViaFeed(ctx context.Context, s
 	defer validateCancel()
 	orgCtx := client.WithOrgID(validateCtx, s.config.OrganizationID)
 	feedDef, validateErr := s.legionClient.GetFeedDefinition(orgCtx, feedID.String())
+	if validateErr != nil && !errors.Is(validateErr, client.ErrNotFound) {
+		// Transient failure; keep the feed and try again next time
+		return fmt.Errorf("failed to validate health telemetry feed: %w", validateErr)
+	}
 	if validateErr != nil {
-		// Feed doesn't exist or there's an error - try to recreate it
+		// Feed doesn't exist - try to recreate it
 		logger.Warnf("Feed validation failed for system %s (Feed ID: %s): %v. Attempting to recreate.",
 			system.Name, feedID.String(), validateErr)
 
@@ -1826,7 +2859,7 @@ func (s *DroneSwarmSimulation) sendHealthTelemetryViaFeed(ctx context.Context, s
 
 	// Create feed data ingest request
 	payloadRaw := json.RawMessage(payload)
-	recordedAt := time.Now()
+	recordedAt := s.recordedAt(system.ID)
 	ingestReq := &models.IngestFeedDataRequest{
 		EntityID:         &system.ID,
 		FeedDefinitionID: &feedID,
@@ -1840,10 +2873,10 @@ func (s *DroneSwarmSimulation) sendHealthTelemetryViaFeed(ctx context.Context, s
 	ingestCtx, ingestCancel := context.WithTimeout(ctx, 2*time.Second)
 	defer ingestCancel()
 	orgCtx = client.WithOrgID(ingestCtx, s.config.OrganizationID)
-	err = s.legionClient.IngestFeedData(orgCtx, ingestReq)
+	err = s.legionClient.IngestFeedData(s.idempotent(orgCtx, "ingest", feedID.String(), recordedAt.Format(time.RFC3339Nano)), ingestReq)
 	if err != nil {
-		// If we get a 404, the feed might have been deleted or doesn't exist
-		if strings.Contains(err.Error(), "404") {
+		// The feed might have been deleted or doesn't exist
+		if errors.Is(err, client.ErrNotFound) {
 			logger.Warnf("Feed definition not found (ID: %s) for system %s. Attempting to recreate feed.",
 				feedID.String(), system.Name)
 
@@ -1861,7 +2894,7 @@ func (s *DroneSwarmSimulation) sendHealthTelemetryViaFeed(ctx context.Context, s
 
 			// Update the request with the new feed ID and retry
 			ingestReq.FeedDefinitionID = &newFeedID
-			retryErr := s.legionClient.IngestFeedData(orgCtx, ingestReq)
+			retryErr := s.legionClient.IngestFeedData(s.idempotent(orgCtx, "ingest", newFeedID.String(), recordedAt.Format(time.RFC3339Nano)), ingestReq)
 			if retryErr != nil {
 				logger.Errorf("Failed to send telemetry even after feed recreation for system %s: %v", system.Name, retryErr)
 				return fmt.Errorf("failed to send health telemetry (retry after recreation failed): %w", retryErr)
@@ -1885,7 +2918,7 @@ func (s *DroneSwarmSimulation) updateSystemHealthTelemetry() {
 		switch system.Status {
 		case CounterUASStatusEngaging:
 			// Temperature increases during engagement
-			system.Temperature += 0.5 + rand.Float64()*0.5
+			system.Temperature += 0.5 + system.random().Float64()*0.5
 			if system.Temperature > 85.0 {
 				system.Temperature = 85.0 // Max operating temp
 			}
@@ -1956,7 +2989,9 @@ func (s *DroneSwarmSimulation) updateSystemHealthTelemetry() {
 			// Send health telemetry via feed
 			ctx := context.Background() // Use background context for async telemetry
 			if err := s.sendHealthTelemetryViaFeed(ctx, system); err != nil {
-				logger.Errorf("Failed to send health telemetry for %s: %v", system.Callsign, err)
+				if !errors.Is(err, client.ErrCircuitOpen) {
+					logger.Errorf("Failed to send health telemetry for %s: %v", system.Callsign, err)
+				}
 				// Fallback to metadata updates
 				s.updateBuffer.QueueMetadataUpdate(system.ID, "system_health", system.SystemHealth)
 				s.updateBuffer.QueueMetadataUpdate(system.ID, "power_level", system.PowerLevel)
@@ -0,0 +1,75 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+func TestConfigureConvertsParameters(t *testing.T) {
+	sim := NewDroneSwarmSimulation().(*DroneSwarmSimulation)
+	// As a parameters file or an older caller might pass them
+	if err := sim.Configure(map[string]interface{}{
+		"waves":              float64(3),
+		"duration":           "2m",
+		"acceptable_leakage": 0,
+		"seed":               int64(7),
+		"debug_logging":      "false",
+	}); err != nil {
+		t.Fatalf("Configure: %v", err)
+	}
+	c := sim.config
+	if c.NumWaves != 3 || c.SimDuration != 2*time.Minute || c.AcceptableLeakage != 0 || c.Seed != 7 || c.EnableDebugLogging {
+		t.Errorf("unexpected config: waves %d, duration %s, leakage %g, seed %d, debug %v",
+			c.NumWaves, c.SimDuration, c.AcceptableLeakage, c.Seed, c.EnableDebugLogging)
+	}
+
+	if err := sim.Configure(map[string]interface{}{"num_uas_threats": "many"}); err == nil {
+		t.Error("expected an error for a non-numeric num_uas_threats")
+	}
+}
+
+func TestSameSeedRepeatsRun(t *testing.T) {
+	t.Chdir(t.TempDir()) // Reports are written to ./reports
+
+	run := func() *simulation.Result {
+		sim := NewDroneSwarmSimulation().(*DroneSwarmSimulation)
+		if err := sim.Configure(map[string]interface{}{
+			"organization_id":         uuid.NewString(),
+			"num_counter_uas_systems": 4,
+			"num_uas_threats":         12,
+			"waves":                   2,
+			"update_interval":         "50ms",
+			"duration":                "1m",
+			"time_scale":              20,
+			"warmup_duration":         "0s",
+			"seed":                    42,
+		}); err != nil {
+			t.Fatalf("Configure: %v", err)
+		}
+		legion, _ := client.NewMemoryClient()
+		result, err := sim.Run(context.Background(), legion)
+		if err != nil {
+			t.Fatalf("Run: %v", err)
+		}
+		return result
+	}
+
+	first, second := run(), run()
+	if first.Seed != 42 || second.Seed != 42 {
+		t.Fatalf("expected both runs seeded 42, got %d and %d", first.Seed, second.Seed)
+	}
+	if first.Outcome != second.Outcome || first.Summary != second.Summary || first.Termination != second.Termination {
+		t.Errorf("expected the same outcome, got %s %q and %s %q", first.Outcome, first.Summary, second.Outcome, second.Summary)
+	}
+	for _, name := range []string{"total_engagements", "successful_engagements", "uas_eliminated", "uas_penetrated", "counter_uas_losses", "hazards"} {
+		if first.Stats[name] != second.Stats[name] {
+			t.Errorf("expected %s to repeat, got %g and %g", name, first.Stats[name], second.Stats[name])
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package simulation
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/spectate"
+)
+
+// spectatorEvents is how many recent log events each snapshot carries
+const spectatorEvents = 20
+
+// startSpectator brings up the read-only spectator endpoint when configured
+func (s *DroneSwarmSimulation) startSpectator() {
+	if s.config.SpectatorAddr == "" {
+		return
+	}
+
+	s.spectators = spectate.NewBroadcaster()
+	s.spectatorServer = s.spectators.Serve(s.config.SpectatorAddr)
+	s.publishSpectatorSnapshot(spectate.StatusStarting)
+}
+
+// stopSpectator shuts the spectator endpoint down
+func (s *DroneSwarmSimulation) stopSpectator() {
+	if s.spectatorServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = s.spectatorServer.Shutdown(ctx)
+}
+
+// publishSpectatorSnapshot pushes the current picture to connected spectators
+func (s *DroneSwarmSimulation) publishSpectatorSnapshot(status string) {
+	if s.spectators == nil {
+		return
+	}
+	s.spectators.Publish(s.spectatorSnapshot(status))
+}
+
+// Snapshot implements simulation.Watchable for the run dashboard
+func (s *DroneSwarmSimulation) Snapshot() spectate.Snapshot {
+	snapshot := s.spectatorSnapshot(spectate.StatusRunning)
+	switch {
+	case snapshot.Outcome != "":
+		snapshot.Status = spectate.StatusComplete
+	case snapshot.Started.IsZero():
+		snapshot.Status = spectate.StatusStarting
+	case s.Paused():
+		snapshot.Status = spectate.StatusPaused
+	}
+	return snapshot
+}
+
+// spectatorSnapshot captures scoreboard, unit positions and recent events
+func (s *DroneSwarmSimulation) spectatorSnapshot(status string) spectate.Snapshot {
+	snapshot := spectate.Snapshot{
+		Simulation: s.Name(),
+		RunID:      s.runID,
+		Status:     status,
+		Started:    s.runStarted,
+		Origin: spectate.Point{
+			Lat: s.config.BaseLocation.Lat,
+			Lon: s.config.BaseLocation.Lon,
+			Alt: s.config.BaseLocation.Alt,
+		},
+		RadiusKm:        s.config.SimulationRadius,
+		DurationSeconds: s.config.SimDuration.Seconds(),
+	}
+	if !s.runStarted.IsZero() {
+		snapshot.ElapsedSeconds = time.Since(s.runStarted).Seconds() * s.timeScale()
+	}
+
+	s.stats.mu.RLock()
+	snapshot.Outcome = s.stats.SimulationOutcome
+	snapshot.Counters = []spectate.Counter{
+		{Name: "Engagements", Value: float64(s.stats.TotalEngagements)},
+		{Name: "Kills", Value: float64(s.stats.UASEliminated)},
+		{Name: "Leakers", Value: float64(s.stats.UASPenetrated)},
+		{Name: "Blue losses", Value: float64(s.stats.CounterUASLosses)},
+	}
+	s.stats.mu.RUnlock()
+	if s.updateBuffer != nil {
+		stats := s.updateBuffer.GetStats()
+		snapshot.Counters = append(snapshot.Counters,
+			spectate.Counter{Name: "API sent", Value: float64(stats.UpdatesSent)},
+			spectate.Counter{Name: "API failed", Value: float64(stats.UpdatesFailed)})
+		if attempted := stats.UpdatesSent + stats.UpdatesFailed; attempted > 0 {
+			errorRate := math.Round(float64(stats.UpdatesFailed)/float64(attempted)*1000) / 10
+			snapshot.Counters = append(snapshot.Counters, spectate.Counter{Name: "API error %", Value: errorRate})
+		}
+	}
+
+	s.mu.RLock()
+	for _, system := range s.counterUASSystems {
+		system.mu.RLock()
+		if system.Position != nil {
+			lat, lon, alt := ecefToLatLonAlt(system.Position.Coordinates[0], system.Position.Coordinates[1], system.Position.Coordinates[2])
+			snapshot.Units = append(snapshot.Units, spectate.Unit{
+				ID:     system.ID.String(),
+				Name:   system.Callsign,
+				Side:   spectate.SideBlue,
+				Kind:   system.EngagementType,
+				Status: system.Status,
+				Point:  spectate.Point{Lat: lat, Lon: lon, Alt: alt},
+				Detail: fmt.Sprintf("health %.0f%% ammo %d/%d", system.SystemHealth*100, system.AmmoRemaining, system.AmmoCapacity),
+			})
+		}
+		system.mu.RUnlock()
+	}
+
+	for _, threat := range s.uasThreats {
+		threat.mu.RLock()
+		if threat.Position != nil && !threat.Archived {
+			lat, lon, alt := ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+			detail := fmt.Sprintf("wave %d", threat.ActualCapabilities.WaveNumber)
+			if threat.Intent != nil && threat.Intent.PredictedTargetName != "" {
+				detail += fmt.Sprintf(" → %s in %.0fs", threat.Intent.PredictedTargetName, threat.Intent.TimeToImpact.Seconds())
+			}
+			snapshot.Units = append(snapshot.Units, spectate.Unit{
+				ID:     threat.ID.String(),
+				Name:   threat.TrackNumber,
+				Side:   spectate.SideRed,
+				Kind:   threat.SizeClass,
+				Status: threat.Classification,
+				Point:  spectate.Point{Lat: lat, Lon: lon, Alt: alt},
+				Detail: detail,
+			})
+		}
+		threat.mu.RUnlock()
+	}
+	s.mu.RUnlock()
+
+	if s.simLogger != nil {
+		events := s.simLogger.GetEvents()
+		if len(events) > spectatorEvents {
+			events = events[len(events)-spectatorEvents:]
+		}
+		for _, event := range events {
+			snapshot.Events = append(snapshot.Events, spectate.Event{
+				Time:     event.Timestamp,
+				Type:     event.Type,
+				Severity: event.Severity,
+				Message:  event.Message,
+			})
+		}
+	}
+
+	return snapshot
+}
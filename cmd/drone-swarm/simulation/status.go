@@ -0,0 +1,92 @@
+package simulation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// Run phases outside the tick, reported alongside the tick phases by Status
+const (
+	runPhaseSetup     = "setup"
+	runPhasePaused    = "paused"
+	runPhaseShutdown  = "shutdown"
+	runPhaseReporting = "reporting"
+	runPhaseComplete  = "complete"
+)
+
+// bufferDegradedRate is the share of failed updates above which the buffer is degraded
+const bufferDegradedRate = 0.05
+
+// bufferStalledTicks is how many update intervals pending updates may wait for a flush
+// before the buffer is stalled
+const bufferStalledTicks = 5
+
+// runStatus is the phase the run is in, read by Status from another goroutine
+type runStatus struct {
+	phase string
+	tick  int
+	mu    sync.RWMutex
+}
+
+// setPhase records the phase the run has entered
+func (s *DroneSwarmSimulation) setPhase(phase string) {
+	s.status.mu.Lock()
+	s.status.phase = phase
+	s.status.tick = s.tick
+	s.status.mu.Unlock()
+}
+
+// Status implements simulation.StatusReporter for "legion-sim status"
+func (s *DroneSwarmSimulation) Status() simulation.Status {
+	s.status.mu.RLock()
+	status := simulation.Status{Phase: s.status.phase, Tick: s.status.tick}
+	s.status.mu.RUnlock()
+	if status.Phase == "" {
+		status.Phase = runPhaseSetup
+	}
+
+	s.stats.mu.RLock()
+	status.Stats = map[string]float64{
+		"total_engagements":      float64(s.stats.TotalEngagements),
+		"successful_engagements": float64(s.stats.SuccessfulEngagements),
+		"uas_eliminated":         float64(s.stats.UASEliminated),
+		"uas_penetrated":         float64(s.stats.UASPenetrated),
+		"counter_uas_losses":     float64(s.stats.CounterUASLosses),
+		"hazards":                float64(s.stats.Hazards),
+		"tracks_archived":        float64(s.stats.TracksArchived),
+	}
+	s.stats.mu.RUnlock()
+
+	if s.updateBuffer != nil {
+		status.Buffer = s.bufferHealth()
+	}
+	return status
+}
+
+// bufferHealth summarizes the update buffer: stalled when updates have waited several
+// intervals without a flush, degraded when too many sends fail
+func (s *DroneSwarmSimulation) bufferHealth() *simulation.BufferHealth {
+	stats := s.updateBuffer.GetStats()
+	health := &simulation.BufferHealth{
+		State:     simulation.BufferOK,
+		Pending:   stats.TotalUpdates,
+		Sent:      stats.UpdatesSent,
+		Failed:    stats.UpdatesFailed,
+		Shed:      stats.UpdatesShed,
+		LastFlush: stats.LastBatchTime,
+	}
+	if stats.LastError != nil {
+		health.LastError = stats.LastError.Error()
+	}
+
+	attempted := stats.UpdatesSent + stats.UpdatesFailed
+	switch {
+	case stats.TotalUpdates > 0 && time.Since(stats.LastBatchTime) > bufferStalledTicks*s.config.UpdateInterval:
+		health.State = simulation.BufferStalled
+	case attempted > 0 && float64(stats.UpdatesFailed)/float64(attempted) > bufferDegradedRate:
+		health.State = simulation.BufferDegraded
+	}
+	return health
+}
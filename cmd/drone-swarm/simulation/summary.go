@@ -0,0 +1,165 @@
+package simulation
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// DefaultSummaryInterval is how often a progress line is logged when the console is not
+// a terminal
+const DefaultSummaryInterval = 10 * time.Second
+
+// Tick summary sections
+const (
+	SummaryTracks      = "tracks"      // Threat counts by classification
+	SummaryEngagements = "engagements" // Engagements and kills this interval
+	SummaryForces      = "forces"      // Counter-UAS systems by status
+	SummaryAPI         = "api"         // Update buffer throughput and failures
+)
+
+// DefaultSummaryFields are the sections printed when summary_fields is not set
+const DefaultSummaryFields = SummaryTracks + "," + SummaryEngagements + "," + SummaryAPI
+
+// trackStatusOrder keeps classification counts in a stable, kill-chain order
+var trackStatusOrder = []string{
+	TrackStatusPending, TrackStatusUnknown, TrackStatusSuspected, TrackStatusHostile,
+	TrackStatusNeutral, TrackStatusLost, TrackStatusDestroyed,
+}
+
+// counterUASStatusOrder keeps system status counts in a stable order
+var counterUASStatusOrder = []string{
+	CounterUASStatusBIT, CounterUASStatusIdle, CounterUASStatusSearching, CounterUASStatusTracking,
+	CounterUASStatusEngaging, CounterUASStatusReloading, CounterUASStatusCooldown,
+	CounterUASStatusDegraded, CounterUASStatusOffline,
+}
+
+// summaryState remembers the previous summary so intervals can report deltas
+type summaryState struct {
+	progress    *logger.RunProgress
+	engagements int
+	kills       int
+	apiSent     int64
+	apiFailed   int64
+}
+
+// parseSummaryFields validates a comma-separated list of summary sections
+func parseSummaryFields(value string) ([]string, error) {
+	var fields []string
+	for _, field := range strings.Split(value, ",") {
+		field = strings.ToLower(strings.TrimSpace(field))
+		switch field {
+		case "":
+			continue
+		case SummaryTracks, SummaryEngagements, SummaryForces, SummaryAPI:
+			fields = append(fields, field)
+		default:
+			return nil, fmt.Errorf("unknown summary field %q (valid: %s, %s, %s, %s)",
+				field, SummaryTracks, SummaryEngagements, SummaryForces, SummaryAPI)
+		}
+	}
+	return fields, nil
+}
+
+// formatCounts renders non-zero counts in the given order, appending any unlisted keys
+func formatCounts(counts map[string]int, order []string) string {
+	var parts []string
+	seen := make(map[string]bool, len(order))
+	for _, key := range order {
+		seen[key] = true
+		if counts[key] > 0 {
+			parts = append(parts, fmt.Sprintf("%s %d", key, counts[key]))
+		}
+	}
+	var extra []string
+	for key, count := range counts {
+		if !seen[key] && count > 0 {
+			extra = append(extra, key)
+		}
+	}
+	sort.Strings(extra)
+	for _, key := range extra {
+		parts = append(parts, fmt.Sprintf("%s %d", key, counts[key]))
+	}
+	if len(parts) == 0 {
+		return "none"
+	}
+	return strings.Join(parts, ", ")
+}
+
+// logTickSummary shows the run's progress with the configured summary sections: a live
+// line on a terminal, or a log line per interval (or per quarter of the run in quiet
+// mode). When final is set it logs where the run ended.
+func (s *DroneSwarmSimulation) logTickSummary(final bool) {
+	if s.summary.progress == nil {
+		s.summary.progress = logger.NewRunProgress(s.config.SimDuration, s.timeScale(), s.config.SummaryInterval)
+	}
+	elapsed := s.scenarioElapsed()
+	if final {
+		s.summary.progress.Done(min(elapsed, s.config.SimDuration), s.summaryCounts())
+		return
+	}
+	if s.config.SummaryInterval <= 0 || !s.summary.progress.Due(elapsed) {
+		return
+	}
+	s.summary.progress.Update(elapsed, s.summaryCounts())
+}
+
+// summaryCounts renders the configured summary sections, with engagement and API
+// counts since the previous summary
+func (s *DroneSwarmSimulation) summaryCounts() string {
+	var parts []string
+	for _, field := range s.config.SummaryFields {
+		switch field {
+		case SummaryTracks:
+			parts = append(parts, "tracks: "+formatCounts(s.countTracksByClassification(), trackStatusOrder))
+		case SummaryForces:
+			parts = append(parts, "forces: "+formatCounts(s.countSystemsByStatus(), counterUASStatusOrder))
+		case SummaryEngagements:
+			s.stats.mu.RLock()
+			engagements, kills, leakers := s.stats.TotalEngagements, s.stats.UASEliminated, s.stats.UASPenetrated
+			s.stats.mu.RUnlock()
+			parts = append(parts, fmt.Sprintf("engagements: +%d (%d total), kills: +%d (%d total), leakers: %d",
+				engagements-s.summary.engagements, engagements, kills-s.summary.kills, kills, leakers))
+			s.summary.engagements, s.summary.kills = engagements, kills
+		case SummaryAPI:
+			if s.updateBuffer == nil {
+				continue
+			}
+			stats := s.updateBuffer.GetStats()
+			parts = append(parts, fmt.Sprintf("api: %d sent, %d failed, %d pending",
+				stats.UpdatesSent-s.summary.apiSent, stats.UpdatesFailed-s.summary.apiFailed, stats.TotalUpdates))
+			s.summary.apiSent, s.summary.apiFailed = stats.UpdatesSent, stats.UpdatesFailed
+		}
+	}
+	return strings.Join(parts, " | ")
+}
+
+// countTracksByClassification counts threats by their current classification
+func (s *DroneSwarmSimulation) countTracksByClassification() map[string]int {
+	counts := make(map[string]int)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, threat := range s.uasThreats {
+		threat.mu.RLock()
+		counts[threat.Classification]++
+		threat.mu.RUnlock()
+	}
+	return counts
+}
+
+// countSystemsByStatus counts Counter-UAS systems by their current status
+func (s *DroneSwarmSimulation) countSystemsByStatus() map[string]int {
+	counts := make(map[string]int)
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, system := range s.counterUASSystems {
+		system.mu.RLock()
+		counts[system.Status]++
+		system.mu.RUnlock()
+	}
+	return counts
+}
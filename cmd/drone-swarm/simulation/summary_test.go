@@ -0,0 +1,36 @@
+package simulation
+
+import "testing"
+
+func TestParseSummaryFields(t *testing.T) {
+	fields, err := parseSummaryFields(" Tracks, api ,")
+	if err != nil {
+		t.Fatalf("parseSummaryFields: %v", err)
+	}
+	if len(fields) != 2 || fields[0] != SummaryTracks || fields[1] != SummaryAPI {
+		t.Fatalf("unexpected fields: %v", fields)
+	}
+
+	if _, err := parseSummaryFields("tracks,weather"); err == nil {
+		t.Fatal("expected an error for an unknown field")
+	}
+}
+
+func TestFormatCountsUsesStableOrder(t *testing.T) {
+	counts := map[string]int{
+		TrackStatusDestroyed: 4,
+		TrackStatusPending:   3,
+		TrackStatusHostile:   0,
+		"FORMING":            2,
+	}
+
+	got := formatCounts(counts, trackStatusOrder)
+	want := "PENDING 3, DESTROYED 4, FORMING 2"
+	if got != want {
+		t.Fatalf("formatCounts() = %q, want %q", got, want)
+	}
+
+	if got := formatCounts(nil, trackStatusOrder); got != "none" {
+		t.Fatalf("expected none for empty counts, got %q", got)
+	}
+}
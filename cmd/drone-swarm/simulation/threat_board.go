@@ -0,0 +1,242 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// Default threat board settings
+const (
+	DefaultThreatBoardSize     = 10
+	DefaultThreatBoardInterval = 2 * time.Second
+
+	EntityTypeC2Node     = "C2Node" // Blue Force - command and control node hosting the threat board
+	threatBoardName      = "C2-Threat-Board"
+	threatBoardFeedBase  = "cuas_threat_board_"
+	threatBoardFeedLimit = 2 * time.Second
+)
+
+// ThreatBoardEntry is a single ranked row of the commander's threat board
+type ThreatBoardEntry struct {
+	Rank             int      `json:"rank"`
+	TrackID          string   `json:"track_id"`
+	TrackNumber      string   `json:"track_number"`
+	Classification   string   `json:"classification"`
+	ThreatLevel      int      `json:"threat_level"`
+	DistanceToBaseKm float64  `json:"distance_to_base_km"`
+	PredictedTarget  string   `json:"predicted_target,omitempty"`
+	TimeToImpactS    *float64 `json:"time_to_impact_s,omitempty"`
+	Score            float64  `json:"score"`
+}
+
+// threatPriority scores a track by predicted impact, then classification, then proximity
+// to the base, from 0 to 1. The board ranks by it and target selection weighs it, so
+// systems work down the same list the commander sees. Caller holds threat.mu.
+func (s *DroneSwarmSimulation) threatPriority(threat *UASThreat, distanceToBaseKm float64) float64 {
+	score := intentUrgency(threat.Intent) * 0.5
+
+	switch threat.Classification {
+	case TrackStatusHostile:
+		score += 0.3
+	case TrackStatusSuspected:
+		score += 0.2
+	case TrackStatusUnknown:
+		score += 0.1
+	}
+
+	if s.config.SimulationRadius > 0 {
+		score += math.Max(0, 1.0-distanceToBaseKm/s.config.SimulationRadius) * 0.2
+	}
+	return score
+}
+
+// rankThreats orders every tracked threat by threatPriority, highest first
+func (s *DroneSwarmSimulation) rankThreats(limit int) []ThreatBoardEntry {
+	baseX, baseY, baseZ := latLonAltToECEF(
+		s.config.BaseLocation.Lat,
+		s.config.BaseLocation.Lon,
+		s.config.BaseLocation.Alt,
+	)
+	basePoint := &models.GeomPoint{Coordinates: []float64{baseX, baseY, baseZ}}
+
+	var entries []ThreatBoardEntry
+	for _, threat := range s.uasThreats {
+		threat.mu.RLock()
+		classification := threat.Classification
+		if classification == TrackStatusPending || classification == TrackStatusDestroyed ||
+			classification == TrackStatusLost || classification == TrackStatusNeutral {
+			threat.mu.RUnlock()
+			continue
+		}
+
+		distance := calculateDistanceKm(basePoint, threat.Position)
+		entry := ThreatBoardEntry{
+			TrackID:          threat.ID.String(),
+			TrackNumber:      threat.TrackNumber,
+			Classification:   classification,
+			ThreatLevel:      threat.ThreatLevel,
+			DistanceToBaseKm: distance,
+		}
+
+		entry.Score = s.threatPriority(threat, distance)
+		if threat.Intent != nil {
+			tti := threat.Intent.TimeToImpact.Seconds()
+			entry.TimeToImpactS = &tti
+			entry.PredictedTarget = threat.Intent.PredictedTargetName
+		}
+		threat.mu.RUnlock()
+
+		entries = append(entries, entry)
+	}
+
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Score != entries[j].Score {
+			return entries[i].Score > entries[j].Score
+		}
+		return entries[i].TrackNumber < entries[j].TrackNumber
+	})
+
+	if limit > 0 && len(entries) > limit {
+		entries = entries[:limit]
+	}
+	for i := range entries {
+		entries[i].Rank = i + 1
+	}
+
+	return entries
+}
+
+// createThreatBoard creates the C2 node entity and feed that carry the threat board
+func (s *DroneSwarmSimulation) createThreatBoard(ctx context.Context) error {
+	orgID, err := uuid.Parse(s.config.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("invalid organization ID: %w", err)
+	}
+
+	name := threatBoardName
+	if s.config.UseUniqueNames {
+		name = fmt.Sprintf("%s-%d", threatBoardName, time.Now().Unix())
+	}
+	name = s.named(name)
+	category := models.CategoryDEVICE
+	entityType := EntityTypeC2Node
+	status := "ACTIVE"
+	entityReq := &models.CreateEntityRequest{
+		OrganizationID: &orgID,
+		Name:           &name,
+		Category:       &category,
+		Type:           &entityType,
+		Status:         &status,
+		Affiliation:    models.AffiliationFRIEND,
+	}
+
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	entity, err := s.createOrAdoptEntity(orgCtx, entityReq)
+	if err != nil {
+		return fmt.Errorf("failed to create threat board entity: %w", err)
+	}
+	s.threatBoardEntityID = entity.ID
+
+	// Place the C2 node at the defended base
+	x, y, z := latLonAltToECEF(s.config.BaseLocation.Lat, s.config.BaseLocation.Lon, s.config.BaseLocation.Alt)
+	pointType := "Point"
+	recordedAt := time.Now()
+	locationReq := &models.CreateEntityLocationRequest{
+		Position:   &models.GeomPoint{Type: &pointType, Coordinates: []float64{x, y, z}},
+		Source:     "Drone-Swarm-Simulation",
+		RecordedAt: &recordedAt,
+	}
+	if _, err := s.legionClient.CreateEntityLocation(orgCtx, entity.ID.String(), locationReq); err != nil {
+		logger.Warnf("Failed to place threat board entity: %v", err)
+	}
+
+	// An adopted board keeps its feed from the earlier run
+	if feedID, ok := s.findEntityFeed(ctx, entity.ID, threatBoardFeedBase); ok {
+		s.threatBoardFeedID = feedID
+		logger.Infof("📊 Reusing threat board feed (Feed ID: %s)", feedID.String())
+		return nil
+	}
+
+	feedName := s.named(fmt.Sprintf("%s%s", threatBoardFeedBase, entity.ID.String()[:8]))
+	feedCategory := models.MessageCategoryMESSAGE
+	dataType := "application/json"
+	isActive := true
+	feedReq := &models.CreateFeedDefinitionRequest{
+		Category:    &feedCategory,
+		FeedName:    &feedName,
+		EntityID:    entity.ID,
+		DataType:    &dataType,
+		Description: fmt.Sprintf("Top %d ranked threats for the commander's display", s.config.ThreatBoardSize),
+		IsActive:    &isActive,
+	}
+
+	feed, err := s.legionClient.CreateFeedDefinition(s.idempotent(orgCtx, "feed", feedName), feedReq)
+	if err != nil {
+		return fmt.Errorf("failed to create threat board feed: %w", err)
+	}
+	s.threatBoardFeedID = feed.ID
+
+	logger.Infof("📊 Created threat board feed (Feed ID: %s)", feed.ID.String())
+	return nil
+}
+
+// updateThreatBoard re-ranks threats and publishes the board once per configured interval
+func (s *DroneSwarmSimulation) updateThreatBoard(ctx context.Context) {
+	if s.config.ThreatBoardSize <= 0 {
+		return
+	}
+
+	board := s.rankThreats(s.config.ThreatBoardSize)
+
+	s.threatBoardMu.Lock()
+	// A board is a snapshot, so while Legion is failing there is nothing worth keeping
+	due := time.Since(s.lastThreatBoardPublish) >= s.config.ThreatBoardInterval && !s.legionDegraded()
+	if due {
+		s.lastThreatBoardPublish = time.Now()
+	}
+	s.threatBoardMu.Unlock()
+
+	if !due || s.threatBoardFeedID == uuid.Nil {
+		return
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"timestamp": time.Now().Format(time.RFC3339),
+		"count":     len(board),
+		"threats":   board,
+	})
+	if err != nil {
+		logger.Debugf("Failed to marshal threat board: %v", err)
+		return
+	}
+
+	payloadRaw := json.RawMessage(payload)
+	recordedAt := time.Now()
+	ingestReq := &models.IngestFeedDataRequest{
+		EntityID:         &s.threatBoardEntityID,
+		FeedDefinitionID: &s.threatBoardFeedID,
+		RecordedAt:       &recordedAt,
+		Payload:          &payloadRaw,
+	}
+
+	ingestCtx, cancel := context.WithTimeout(ctx, threatBoardFeedLimit)
+	defer cancel()
+	ingestCtx = s.idempotent(client.WithOrgID(ingestCtx, s.config.OrganizationID), "ingest", s.threatBoardFeedID.String(), recordedAt.Format(time.RFC3339Nano))
+	if err := s.legionClient.IngestFeedData(ingestCtx, ingestReq); err != nil {
+		logger.Warnf("Failed to publish threat board: %v", err)
+		return
+	}
+
+	if len(board) > 0 {
+		logger.Debugf("📊 Threat board published: %d tracks, top priority %s", len(board), board[0].TrackNumber)
+	}
+}
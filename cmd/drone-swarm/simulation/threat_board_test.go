@@ -0,0 +1,58 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestRankThreatsOrdersByImpactClassificationProximity(t *testing.T) {
+	s := &DroneSwarmSimulation{
+		config: SimulationConfig{
+			BaseLocation:     Location{Lat: 40.044437, Lon: -76.306229, Alt: 100},
+			SimulationRadius: 10,
+		},
+		uasThreats: make(map[uuid.UUID]*UASThreat),
+	}
+	baseX, baseY, baseZ := latLonAltToECEF(40.044437, -76.306229, 100)
+	add := func(trackNumber, classification string, distanceKm float64, intent *IntentEstimate) {
+		threat := &UASThreat{
+			ID:             uuid.New(),
+			TrackNumber:    trackNumber,
+			Classification: classification,
+			Position:       &models.GeomPoint{Coordinates: []float64{baseX + distanceKm*1000, baseY, baseZ}},
+			Intent:         intent,
+		}
+		s.uasThreats[threat.ID] = threat
+	}
+
+	// An imminent impact outranks classification, which outranks proximity
+	add("TK-0001", TrackStatusSuspected, 9, nil)
+	add("TK-0002", TrackStatusHostile, 9, nil)
+	add("TK-0003", TrackStatusHostile, 1, nil)
+	add("TK-0004", TrackStatusUnknown, 9, &IntentEstimate{PredictedTargetName: BaseAssetName, TimeToImpact: 10 * time.Second})
+	add("TK-0005", TrackStatusPending, 1, nil)
+	add("TK-0006", TrackStatusDestroyed, 1, nil)
+	add("TK-0007", TrackStatusLost, 1, nil)
+
+	board := s.rankThreats(0)
+	want := []string{"TK-0004", "TK-0003", "TK-0002", "TK-0001"}
+	if len(board) != len(want) {
+		t.Fatalf("expected %d ranked threats, got %+v", len(want), board)
+	}
+	for i, entry := range board {
+		if entry.TrackNumber != want[i] || entry.Rank != i+1 {
+			t.Errorf("rank %d: expected %s, got %s ranked %d", i+1, want[i], entry.TrackNumber, entry.Rank)
+		}
+	}
+	if board[0].TimeToImpactS == nil || *board[0].TimeToImpactS != 10 || board[0].PredictedTarget != BaseAssetName {
+		t.Errorf("expected the top entry to carry its predicted impact, got %+v", board[0])
+	}
+
+	top := s.rankThreats(2)
+	if len(top) != 2 || top[0].TrackNumber != "TK-0004" || top[1].TrackNumber != "TK-0003" {
+		t.Errorf("expected the board limited to the top 2, got %+v", top)
+	}
+}
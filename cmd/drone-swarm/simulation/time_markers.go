@@ -0,0 +1,183 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// Time marker feed
+const (
+	EntityTypeTimeSource = "TimeSource" // Range clock that carries the timing marker feed
+	timeMarkerName       = "Range-Clock"
+	timeMarkerFeedBase   = "cuas_time_markers_"
+	timeMarkerFeedLimit  = 2 * time.Second
+)
+
+// TimeMarker relates the simulation, wall and exercise clocks at one instant so range
+// video and live sensor recordings can be lined up with the event stream afterwards
+type TimeMarker struct {
+	Sequence      int     `json:"marker"`
+	RunID         string  `json:"run_id"`
+	Tick          int     `json:"tick"`
+	SimTimeS      float64 `json:"sim_time_s"`     // Ticks elapsed times the update interval
+	WallTime      string  `json:"wall_time"`      // UTC, nanosecond precision
+	WallElapsedS  float64 `json:"wall_elapsed_s"` // Since the scenario clock started
+	ExerciseTime  string  `json:"exercise_time,omitempty"`
+	DriftMs       float64 `json:"drift_ms"`                  // Wall elapsed minus sim time; grows when ticks run late
+	ClockOffsetMs float64 `json:"clock_offset_ms,omitempty"` // Correction applied to the local clock to match clock_source
+}
+
+// timeMarkerLog tracks the marker feed
+type timeMarkerLog struct {
+	entityID uuid.UUID
+	feedID   uuid.UUID
+	last     time.Time
+	sent     int
+}
+
+// parseExerciseTime parses the exercise clock reading at scenario start
+func parseExerciseTime(value string) (time.Time, error) {
+	if value == "" {
+		return time.Time{}, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid exercise_time %q: expected RFC3339 (e.g. 2030-06-01T04:30:00Z)", value)
+	}
+	return t, nil
+}
+
+// newTimeMarker captures the clocks now
+func (s *DroneSwarmSimulation) newTimeMarker(now time.Time) TimeMarker {
+	simTime := s.tickSimTime()
+	elapsed := now.Sub(s.scenarioStart)
+	scaled := time.Duration(float64(elapsed) * s.timeScale())
+	marker := TimeMarker{
+		Sequence:     s.timeMarkers.sent + 1,
+		RunID:        s.runID,
+		Tick:         s.tick,
+		SimTimeS:     simTime.Seconds(),
+		WallTime:     now.UTC().Format(time.RFC3339Nano),
+		WallElapsedS: elapsed.Seconds(),
+		DriftMs:      float64(scaled-simTime) / float64(time.Millisecond),
+	}
+	if s.governor != nil {
+		marker.ClockOffsetMs = float64(s.governor.Offset()) / float64(time.Millisecond)
+	}
+	if !s.config.ExerciseStart.IsZero() {
+		marker.ExerciseTime = s.config.ExerciseStart.Add(elapsed).UTC().Format(time.RFC3339Nano)
+	}
+	return marker
+}
+
+// createTimeMarkerFeed creates the range clock entity and its marker feed
+func (s *DroneSwarmSimulation) createTimeMarkerFeed(ctx context.Context) error {
+	orgID, err := uuid.Parse(s.config.OrganizationID)
+	if err != nil {
+		return fmt.Errorf("invalid organization ID: %w", err)
+	}
+
+	name := timeMarkerName
+	if s.config.UseUniqueNames {
+		name = fmt.Sprintf("%s-%d", timeMarkerName, time.Now().Unix())
+	}
+	name = s.named(name)
+	category := models.CategoryDEVICE
+	entityType := EntityTypeTimeSource
+	status := "ACTIVE"
+	entityReq := &models.CreateEntityRequest{
+		OrganizationID: &orgID,
+		Name:           &name,
+		Category:       &category,
+		Type:           &entityType,
+		Status:         &status,
+		Affiliation:    models.AffiliationFRIEND,
+	}
+
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	entity, err := s.createOrAdoptEntity(orgCtx, entityReq)
+	if err != nil {
+		return fmt.Errorf("failed to create range clock entity: %w", err)
+	}
+	s.timeMarkers.entityID = entity.ID
+
+	if feedID, ok := s.findEntityFeed(ctx, entity.ID, timeMarkerFeedBase); ok {
+		s.timeMarkers.feedID = feedID
+		logger.Infof("%s Reusing time marker feed (Feed ID: %s)", logger.IconTime, feedID.String())
+		return nil
+	}
+
+	feedName := s.named(fmt.Sprintf("%s%s", timeMarkerFeedBase, entity.ID.String()[:8]))
+	feedCategory := models.MessageCategoryMESSAGE
+	dataType := "application/json"
+	isActive := true
+	feedReq := &models.CreateFeedDefinitionRequest{
+		Category:    &feedCategory,
+		FeedName:    &feedName,
+		EntityID:    entity.ID,
+		DataType:    &dataType,
+		Description: "Simulation, wall and exercise clock markers for synchronizing range recordings",
+		IsActive:    &isActive,
+	}
+
+	feed, err := s.legionClient.CreateFeedDefinition(s.idempotent(orgCtx, "feed", feedName), feedReq)
+	if err != nil {
+		return fmt.Errorf("failed to create time marker feed: %w", err)
+	}
+	s.timeMarkers.feedID = feed.ID
+
+	logger.Infof("%s Created time marker feed every %s (Feed ID: %s)", logger.IconTime, s.config.TimeMarkerInterval, feed.ID.String())
+	return nil
+}
+
+// publishTimeMarker sends a marker once per configured interval. final forces one out
+// at the end of the run so recordings can be bracketed.
+func (s *DroneSwarmSimulation) publishTimeMarker(ctx context.Context, final bool) {
+	if s.config.TimeMarkerInterval <= 0 || s.timeMarkers.feedID == uuid.Nil {
+		return
+	}
+
+	now := s.now()
+	if !final && (now.Sub(s.timeMarkers.last) < s.config.TimeMarkerInterval || s.legionDegraded()) {
+		return
+	}
+	s.timeMarkers.last = now
+
+	marker := s.newTimeMarker(now)
+	payload, err := json.Marshal(marker)
+	if err != nil {
+		logger.Debugf("Failed to marshal time marker: %v", err)
+		return
+	}
+
+	payloadRaw := json.RawMessage(payload)
+	ingestReq := &models.IngestFeedDataRequest{
+		EntityID:         &s.timeMarkers.entityID,
+		FeedDefinitionID: &s.timeMarkers.feedID,
+		RecordedAt:       &now,
+		Payload:          &payloadRaw,
+	}
+
+	// The run context may already be cancelled when the final marker goes out
+	ingestCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), timeMarkerFeedLimit)
+	defer cancel()
+	ingestCtx = s.idempotent(client.WithOrgID(ingestCtx, s.config.OrganizationID), "ingest", s.timeMarkers.feedID.String(), now.Format(time.RFC3339Nano))
+	if err := s.legionClient.IngestFeedData(ingestCtx, ingestReq); err != nil {
+		logger.Warnf("Failed to publish time marker: %v", err)
+		return
+	}
+
+	s.timeMarkers.sent++
+	if s.simLogger != nil {
+		s.simLogger.UpdateMetric(reporting.MetricTimeMarkers, float64(s.timeMarkers.sent), "count")
+	}
+	logger.Debugf("%s Time marker %d: tick %d, drift %.0fms", logger.IconTime, marker.Sequence, marker.Tick, marker.DriftMs)
+}
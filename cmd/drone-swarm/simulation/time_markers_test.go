@@ -0,0 +1,34 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestNewTimeMarkerRelatesClocks(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	exercise, err := parseExerciseTime("2030-06-01T04:30:00Z")
+	if err != nil {
+		t.Fatalf("parseExerciseTime: %v", err)
+	}
+
+	s := &DroneSwarmSimulation{scenarioStart: start, tick: 20, runID: "run-1"}
+	s.config.UpdateInterval = 500 * time.Millisecond
+	s.config.ExerciseStart = exercise
+
+	// 20 ticks of 500ms is 10s of sim time; the wall clock has run 10.25s
+	marker := s.newTimeMarker(start.Add(10250 * time.Millisecond))
+	if marker.SimTimeS != 10 || marker.WallElapsedS != 10.25 || marker.DriftMs != 250 {
+		t.Fatalf("unexpected clocks: %+v", marker)
+	}
+	if marker.ExerciseTime != "2030-06-01T04:30:10.25Z" {
+		t.Fatalf("exercise time = %s", marker.ExerciseTime)
+	}
+	if marker.Sequence != 1 {
+		t.Fatalf("first marker sequence = %d", marker.Sequence)
+	}
+
+	if _, err := parseExerciseTime("tomorrow"); err == nil {
+		t.Fatal("expected an error for a non-RFC3339 exercise time")
+	}
+}
@@ -0,0 +1,51 @@
+package simulation
+
+import "time"
+
+// DefaultTimeScale runs the scenario in real time
+const DefaultTimeScale = 1.0
+
+// maxTimeScale bounds time_scale. Beyond it, a fast threat moves kilometers per tick at
+// the default update interval and slips between detection and engagement checks.
+const maxTimeScale = 100.0
+
+// scenarioElapsed is the scenario time since the start: the wall time the run has not
+// spent paused, times time_scale. A run given a seed follows its ticks instead, so a
+// slow or dropped tick doesn't shift launches, acts or the end of the run.
+func (s *DroneSwarmSimulation) scenarioElapsed() time.Duration {
+	if s.config.Seed != 0 {
+		return s.tickSimTime()
+	}
+	if s.scenarioStart.IsZero() {
+		return 0
+	}
+	return time.Duration(float64(s.now().Sub(s.scenarioStart)) * s.timeScale())
+}
+
+// scenarioNow reads the scenario clock, which starts with the wall clock and then runs
+// at time_scale and stops while paused. Delays and lifetimes in scenario time, such as
+// hazards and report latency, are measured on it.
+func (s *DroneSwarmSimulation) scenarioNow() time.Time {
+	if s.scenarioEpoch.IsZero() {
+		return s.now()
+	}
+	return s.scenarioEpoch.Add(s.scenarioElapsed())
+}
+
+// tickDelta is the scenario time one tick advances, in seconds
+func (s *DroneSwarmSimulation) tickDelta() float64 {
+	return s.config.UpdateInterval.Seconds() * s.timeScale()
+}
+
+// tickSimTime is the scenario time reached after the ticks run so far
+func (s *DroneSwarmSimulation) tickSimTime() time.Duration {
+	return time.Duration(float64(time.Duration(s.tick)*s.config.UpdateInterval) * s.timeScale())
+}
+
+// timeScale returns time_scale, treating an unset scale as real time
+func (s *DroneSwarmSimulation) timeScale() float64 {
+	if s.config.TimeScale <= 0 {
+		return DefaultTimeScale
+	}
+	return s.config.TimeScale
+}
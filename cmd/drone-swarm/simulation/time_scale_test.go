@@ -0,0 +1,52 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+)
+
+func TestTimeScaleRunsScenarioClockFaster(t *testing.T) {
+	start := time.Now().Add(-30 * time.Second)
+	s := &DroneSwarmSimulation{
+		config:        SimulationConfig{UpdateInterval: time.Second, TimeScale: 10},
+		scenarioStart: start,
+		scenarioEpoch: start,
+		tick:          30,
+	}
+
+	if elapsed := s.scenarioElapsed(); elapsed < 300*time.Second || elapsed > 301*time.Second {
+		t.Errorf("expected about 5m of scenario time after 30s, got %s", elapsed)
+	}
+	if now := s.scenarioNow(); now.Sub(start) < 300*time.Second {
+		t.Errorf("expected the scenario clock 5m past the start, got %s", now.Sub(start))
+	}
+	if delta := s.tickDelta(); delta != 10 {
+		t.Errorf("expected each tick to advance 10s, got %g", delta)
+	}
+	if simTime := s.tickSimTime(); simTime != 300*time.Second {
+		t.Errorf("expected 30 ticks to reach 5m, got %s", simTime)
+	}
+
+	s.config.TimeScale = 0
+	if delta := s.tickDelta(); delta != 1 {
+		t.Errorf("expected an unset scale to run in real time, got %g", delta)
+	}
+}
+
+func TestSeededScenarioClockFollowsTicks(t *testing.T) {
+	start := time.Now().Add(-time.Minute)
+	s := &DroneSwarmSimulation{
+		config:        SimulationConfig{UpdateInterval: time.Second, TimeScale: 10, Seed: 42},
+		scenarioStart: start,
+		scenarioEpoch: start,
+		tick:          3,
+	}
+
+	// A minute of wall time but three ticks: the seeded clock ignores the wall clock
+	if elapsed := s.scenarioElapsed(); elapsed != 30*time.Second {
+		t.Errorf("expected 3 ticks to reach 30s, got %s", elapsed)
+	}
+	if now := s.scenarioNow(); now.Sub(start) != 30*time.Second {
+		t.Errorf("expected the scenario clock 30s past the start, got %s", now.Sub(start))
+	}
+}
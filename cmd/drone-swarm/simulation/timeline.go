@@ -0,0 +1,79 @@
+package simulation
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// ganttState maps a Counter-UAS status to its engagement timeline state
+func ganttState(status string) string {
+	switch status {
+	case CounterUASStatusTracking:
+		return reporting.GanttTracking
+	case CounterUASStatusEngaging:
+		return reporting.GanttEngaging
+	case CounterUASStatusReloading, CounterUASStatusCooldown:
+		return reporting.GanttReload
+	case CounterUASStatusOffline, CounterUASStatusDegraded:
+		return reporting.GanttOffline // Degraded systems are not tasked
+	default:
+		return reporting.GanttIdle
+	}
+}
+
+// recordTimeline samples every system's state and which waves are airborne
+func (s *DroneSwarmSimulation) recordTimeline(now time.Time) {
+	if s.timeline == nil {
+		return
+	}
+
+	systems := make([]*CounterUASSystem, 0, len(s.counterUASSystems))
+	for _, system := range s.counterUASSystems {
+		systems = append(systems, system)
+	}
+	sort.Slice(systems, func(i, j int) bool { return systems[i].Name < systems[j].Name })
+	for _, system := range systems {
+		system.mu.RLock()
+		label := fmt.Sprintf("%s (%s)", system.Callsign, system.Name)
+		state := ganttState(system.Status)
+		system.mu.RUnlock()
+		s.timeline.RecordSystem(label, state, now)
+	}
+
+	active := make(map[int]bool)
+	for _, threat := range s.uasThreats {
+		if threat.Remote || threat.holdingAtLaunchSite() ||
+			threat.Classification == TrackStatusDestroyed || threat.Classification == TrackStatusLost {
+			continue
+		}
+		active[threat.ActualCapabilities.WaveNumber] = true
+	}
+	for wave := 1; wave <= s.config.NumWaves; wave++ {
+		s.timeline.RecordWave(fmt.Sprintf("Wave %d", wave), active[wave], now)
+	}
+}
+
+// saveTimeline writes the engagement timeline next to the AAR
+func (s *DroneSwarmSimulation) saveTimeline() error {
+	if s.timeline == nil {
+		return nil
+	}
+
+	chart := s.timeline.Chart(fmt.Sprintf("Engagement timeline - %s", s.runID[:8]), time.Now())
+	filename := fmt.Sprintf("Timeline_%s_%s", s.runID[:8], time.Now().Format("20060102_150405"))
+	paths, err := reporting.SaveGantt(chart, reportsDir, filename)
+	if err != nil {
+		return fmt.Errorf("failed to save engagement timeline: %w", err)
+	}
+
+	s.artifacts = append(s.artifacts, paths...)
+	if s.aarGenerator != nil {
+		s.aarGenerator.AddAttachment(paths[len(paths)-1])
+	}
+	logger.Successf("Engagement timeline saved to: %s", paths[len(paths)-1])
+	return nil
+}
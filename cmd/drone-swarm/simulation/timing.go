@@ -0,0 +1,115 @@
+package simulation
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/core"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// startTiming sets up degraded timing when any timing error is configured. Every
+// location and telemetry timestamp an entity reports then carries its clock error.
+func (s *DroneSwarmSimulation) startTiming() {
+	bounds := core.TimingError{
+		Offset:   s.config.TimingOffset,
+		DriftPPM: s.config.TimingDriftPPM,
+		Jitter:   s.config.TimingJitter,
+		Share:    s.config.TimingDegradedShare,
+	}
+	if !bounds.Enabled() {
+		return
+	}
+
+	s.timing = core.NewTimingModel(bounds, time.Now(), simulation.DeriveRand(s.random()))
+	s.updateBuffer.SetTiming(s.timing)
+	logger.Infof("Degraded timing on %.0f%% of entities: offset up to ±%s, drift up to ±%.1f ppm, jitter %s",
+		bounds.Share*100, bounds.Offset, bounds.DriftPPM, bounds.Jitter)
+}
+
+// recordedAt is the timestamp an entity reports for now, including its clock error
+func (s *DroneSwarmSimulation) recordedAt(entityID uuid.UUID) time.Time {
+	return s.timing.Stamp(entityID, time.Now())
+}
+
+// timingTruth is one entity's clock error, written so time-alignment analytics can be
+// checked against the errors that were applied
+type timingTruth struct {
+	EntityID   string  `json:"entity_id"`
+	Name       string  `json:"name"`
+	EntityType string  `json:"entity_type"`
+	OffsetMs   float64 `json:"offset_ms"`
+	DriftPPM   float64 `json:"drift_ppm"`
+	FinalMs    float64 `json:"final_error_ms"` // Offset plus drift at the end of the run, before jitter
+}
+
+// saveTimingTruth writes every degraded entity's clock error next to the AAR
+func (s *DroneSwarmSimulation) saveTimingTruth() error {
+	if s.timing == nil {
+		return nil
+	}
+
+	elapsed := time.Since(s.timing.Start())
+	clocks := s.timing.Clocks()
+	var entities []timingTruth
+	add := func(id uuid.UUID, name, entityType string) {
+		clock := clocks[id]
+		if !clock.Degraded {
+			return
+		}
+		entities = append(entities, timingTruth{
+			EntityID:   id.String(),
+			Name:       name,
+			EntityType: entityType,
+			OffsetMs:   float64(clock.Offset) / float64(time.Millisecond),
+			DriftPPM:   clock.DriftPPM,
+			FinalMs:    float64(clock.Error(elapsed)) / float64(time.Millisecond),
+		})
+	}
+
+	s.mu.RLock()
+	for id, system := range s.counterUASSystems {
+		add(id, system.Name, EntityTypeCounterUAS)
+	}
+	for id, threat := range s.uasThreats {
+		if !threat.Remote {
+			add(id, threat.TrackNumber, EntityTypeUAS)
+		}
+	}
+	s.mu.RUnlock()
+	sort.Slice(entities, func(i, j int) bool { return entities[i].Name < entities[j].Name })
+
+	data, err := json.MarshalIndent(map[string]interface{}{
+		"run_id":        s.runID,
+		"start":         s.timing.Start().UTC().Format(time.RFC3339Nano),
+		"max_offset_ms": float64(s.config.TimingOffset) / float64(time.Millisecond),
+		"max_drift_ppm": s.config.TimingDriftPPM,
+		"jitter_ms":     float64(s.config.TimingJitter) / float64(time.Millisecond),
+		"entities":      entities,
+	}, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to encode timing errors: %w", err)
+	}
+
+	if err := os.MkdirAll(reportsDir, 0755); err != nil {
+		return fmt.Errorf("failed to create reports directory: %w", err)
+	}
+	path := filepath.Join(reportsDir, fmt.Sprintf("Timing_%s_%s.json", s.runID[:8], time.Now().Format("20060102_150405")))
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write timing errors: %w", err)
+	}
+
+	s.artifacts = append(s.artifacts, path)
+	if s.aarGenerator != nil {
+		s.aarGenerator.AddAttachment(path)
+	}
+	logger.Successf("Applied timing errors saved to: %s (%d degraded entities)", path, len(entities))
+	return nil
+}
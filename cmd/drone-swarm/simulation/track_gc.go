@@ -0,0 +1,95 @@
+package simulation
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// DefaultTrackGCGrace is how long LOST/DESTROYED tracks stay in Legion before removal
+const DefaultTrackGCGrace = 60 * time.Second
+
+const (
+	// trackGCBatch caps the tracks archived per tick; the rest wait for later ticks
+	trackGCBatch = 32
+	// trackGCConcurrency is how many deletes a tick keeps in flight
+	trackGCConcurrency = 8
+)
+
+// collectStaleTracks deletes LOST and DESTROYED track entities from Legion once their
+// grace period has elapsed. Tracks stay in the local threat map so the AAR still sees them.
+// Deletes run concurrently and at most trackGCBatch per tick, so a wave's worth of
+// kills going stale together can't stall the tick behind one delete after another.
+func (s *DroneSwarmSimulation) collectStaleTracks(ctx context.Context) {
+	if s.config.TrackGCGrace <= 0 {
+		return
+	}
+
+	var stale []*UASThreat
+	for _, threat := range s.uasThreats {
+		threat.mu.RLock()
+		expired := !threat.Archived && !threat.Remote && !threat.TerminalSince.IsZero() &&
+			time.Since(threat.TerminalSince) >= s.config.TrackGCGrace
+		threat.mu.RUnlock()
+		if expired {
+			stale = append(stale, threat)
+			if len(stale) == trackGCBatch {
+				break
+			}
+		}
+	}
+	if len(stale) == 0 {
+		return
+	}
+
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	var (
+		mu       sync.Mutex
+		archived int
+		wg       sync.WaitGroup
+	)
+	slots := make(chan struct{}, trackGCConcurrency)
+	for _, threat := range stale {
+		// Drop queued updates so the buffer doesn't retry against a deleted entity
+		s.updateBuffer.Discard(threat.ID)
+
+		slots <- struct{}{}
+		wg.Add(1)
+		go func(threat *UASThreat) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			deleteCtx, cancel := context.WithTimeout(orgCtx, 2*time.Second)
+			err := s.legionClient.DeleteEntity(deleteCtx, threat.ID.String())
+			cancel()
+			if err != nil && !errors.Is(err, client.ErrNotFound) { // Already gone counts as archived
+				logger.Debugf("Failed to archive track %s: %v", threat.TrackNumber, err)
+				return
+			}
+
+			threat.mu.Lock()
+			threat.Archived = true
+			threat.mu.Unlock()
+			mu.Lock()
+			archived++
+			mu.Unlock()
+
+			logger.Debugf("🗑️ Archived %s track %s from the operational picture", threat.Classification, threat.TrackNumber)
+		}(threat)
+	}
+	wg.Wait()
+
+	if archived > 0 {
+		s.stats.mu.Lock()
+		s.stats.TracksArchived += archived
+		total := s.stats.TracksArchived
+		s.stats.mu.Unlock()
+
+		s.simLogger.UpdateMetric("tracks_archived", float64(total), "count")
+		logger.Infof("Archived %d stale tracks (%d total)", archived, total)
+	}
+}
@@ -0,0 +1,107 @@
+package simulation
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/core"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestCollectStaleTracks(t *testing.T) {
+	legion, memory := client.NewMemoryClient()
+	orgID := uuid.New()
+	ctx := client.WithOrgID(context.Background(), orgID.String())
+
+	s := &DroneSwarmSimulation{
+		config:       SimulationConfig{OrganizationID: orgID.String(), TrackGCGrace: time.Minute},
+		legionClient: legion,
+		updateBuffer: core.NewUpdateBuffer(legion, orgID.String(), 50, time.Second),
+		simLogger:    reporting.NewSimulationLogger("test"),
+		uasThreats:   make(map[uuid.UUID]*UASThreat),
+	}
+	track := func(name string, terminal time.Duration, inLegion bool) *UASThreat {
+		threat := &UASThreat{ID: uuid.New(), TrackNumber: name}
+		if inLegion {
+			category, entityType, status := models.CategoryTRACK, EntityTypeUAS, TrackStatusDestroyed
+			entity, err := legion.CreateEntity(ctx, &models.CreateEntityRequest{
+				OrganizationID: &orgID, Name: &name, Category: &category, Type: &entityType, Status: &status,
+			})
+			if err != nil {
+				t.Fatal(err)
+			}
+			threat.ID = entity.ID
+		}
+		if terminal > 0 {
+			threat.TerminalSince = time.Now().Add(-terminal)
+		}
+		s.uasThreats[threat.ID] = threat
+		return threat
+	}
+
+	stale := track("TK-0001", 2*time.Minute, true)
+	fresh := track("TK-0002", 30*time.Second, true)
+	active := track("TK-0003", 0, true)
+	remote := track("TK-0004", 2*time.Minute, false)
+	remote.Remote = true
+	gone := track("TK-0005", 2*time.Minute, false)
+
+	s.updateBuffer.QueueStatusUpdate(stale.ID, TrackStatusDestroyed)
+	s.updateBuffer.QueueStatusUpdate(fresh.ID, TrackStatusDestroyed)
+
+	s.collectStaleTracks(context.Background())
+
+	if !stale.Archived || !gone.Archived {
+		t.Errorf("expected tracks past the grace period archived, ErrNotFound included: stale=%v gone=%v", stale.Archived, gone.Archived)
+	}
+	if fresh.Archived || active.Archived || remote.Archived {
+		t.Errorf("expected fresh, active and remote tracks kept: fresh=%v active=%v remote=%v", fresh.Archived, active.Archived, remote.Archived)
+	}
+	if s.stats.TracksArchived != 2 {
+		t.Errorf("expected 2 tracks archived, got %d", s.stats.TracksArchived)
+	}
+	if got := len(memory.CallsTo("DELETE", "/v3/entities/{id}")); got != 2 {
+		t.Errorf("expected 2 deletes, got %d", got)
+	}
+	if got := memory.Summary().Entities; got != 2 {
+		t.Errorf("expected the fresh and active tracks left in Legion, got %d entities", got)
+	}
+	if got := s.updateBuffer.GetPendingCount(); got != 1 {
+		t.Errorf("expected the archived track's queued update discarded, got %d pending", got)
+	}
+
+	// A second pass has nothing left to delete
+	s.collectStaleTracks(context.Background())
+	if got := len(memory.CallsTo("DELETE", "/v3/entities/{id}")); got != 2 {
+		t.Errorf("expected archived tracks not deleted again, got %d deletes", got)
+	}
+}
+
+func TestCollectStaleTracksCapsEachTick(t *testing.T) {
+	legion, memory := client.NewMemoryClient()
+	s := &DroneSwarmSimulation{
+		config:       SimulationConfig{OrganizationID: uuid.NewString(), TrackGCGrace: time.Minute},
+		legionClient: legion,
+		updateBuffer: core.NewUpdateBuffer(legion, "", 50, time.Second),
+		simLogger:    reporting.NewSimulationLogger("test"),
+		uasThreats:   make(map[uuid.UUID]*UASThreat),
+	}
+	for i := 0; i < trackGCBatch+5; i++ {
+		threat := &UASThreat{ID: uuid.New(), TerminalSince: time.Now().Add(-2 * time.Minute)}
+		s.uasThreats[threat.ID] = threat
+	}
+
+	s.collectStaleTracks(context.Background())
+	if got := len(memory.CallsTo("DELETE", "/v3/entities/{id}")); got != trackGCBatch {
+		t.Errorf("expected %d deletes on the first tick, got %d", trackGCBatch, got)
+	}
+	s.collectStaleTracks(context.Background())
+	if s.stats.TracksArchived != trackGCBatch+5 {
+		t.Errorf("expected the remainder archived on the next tick, got %d archived", s.stats.TracksArchived)
+	}
+}
@@ -0,0 +1,138 @@
+package simulation
+
+import (
+	"sync"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// Default track history settings
+const (
+	DefaultTrackHistoryDepth = 120 // Positions retained per track
+	DefaultTrailPoints       = 20  // Positions published for UI trail rendering
+)
+
+// TrackPoint is a single timestamped position in a track's history
+type TrackPoint struct {
+	Timestamp time.Time `json:"t"`
+	X         float64   `json:"-"` // ECEF meters
+	Y         float64   `json:"-"`
+	Z         float64   `json:"-"`
+	Lat       float64   `json:"lat"`
+	Lon       float64   `json:"lon"`
+	Alt       float64   `json:"alt"`
+}
+
+// TrackHistory is a fixed-depth rolling buffer of track positions
+type TrackHistory struct {
+	points []TrackPoint
+	next   int
+	full   bool
+	mu     sync.RWMutex
+}
+
+// NewTrackHistory creates a track history retaining up to depth positions
+func NewTrackHistory(depth int) *TrackHistory {
+	if depth < 1 {
+		depth = 1
+	}
+	return &TrackHistory{
+		points: make([]TrackPoint, depth),
+	}
+}
+
+// Record appends an ECEF position, overwriting the oldest once the buffer is full
+func (h *TrackHistory) Record(position *models.GeomPoint, timestamp time.Time) {
+	if position == nil || len(position.Coordinates) < 3 {
+		return
+	}
+
+	x, y, z := position.Coordinates[0], position.Coordinates[1], position.Coordinates[2]
+	lat, lon, alt := ecefToLatLonAlt(x, y, z)
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+
+	h.points[h.next] = TrackPoint{
+		Timestamp: timestamp,
+		X:         x,
+		Y:         y,
+		Z:         z,
+		Lat:       lat,
+		Lon:       lon,
+		Alt:       alt,
+	}
+	h.next = (h.next + 1) % len(h.points)
+	if h.next == 0 {
+		h.full = true
+	}
+}
+
+// Len returns the number of retained positions
+func (h *TrackHistory) Len() int {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+	if h.full {
+		return len(h.points)
+	}
+	return h.next
+}
+
+// Points returns all retained positions, oldest first
+func (h *TrackHistory) Points() []TrackPoint {
+	return h.Recent(0)
+}
+
+// Recent returns the n most recent positions, oldest first. n <= 0 returns all.
+func (h *TrackHistory) Recent(n int) []TrackPoint {
+	h.mu.RLock()
+	defer h.mu.RUnlock()
+
+	count := h.next
+	if h.full {
+		count = len(h.points)
+	}
+	if n <= 0 || n > count {
+		n = count
+	}
+
+	result := make([]TrackPoint, 0, n)
+	start := h.next - n
+	if start < 0 {
+		start += len(h.points)
+	}
+	for i := 0; i < n; i++ {
+		result = append(result, h.points[(start+i)%len(h.points)])
+	}
+	return result
+}
+
+// recordTrackHistory records the current position of a threat in its history
+func (s *DroneSwarmSimulation) recordTrackHistory(threat *UASThreat) {
+	if threat.History == nil {
+		threat.History = NewTrackHistory(s.config.TrackHistoryDepth)
+	}
+	threat.History.Record(threat.Position, s.scenarioNow())
+	s.recordTrackVertex(threat, time.Now())
+
+	// The rolling history forgets where the track started, so keep its approach bearing
+	if threat.SpawnBearing == nil && threat.Position != nil {
+		lat, lon, _ := ecefToLatLonAlt(threat.Position.Coordinates[0], threat.Position.Coordinates[1], threat.Position.Coordinates[2])
+		bearing := bearingDegrees(s.config.BaseLocation.Lat, s.config.BaseLocation.Lon, lat, lon)
+		threat.SpawnBearing = &bearing
+	}
+}
+
+// queueTrailUpdate publishes the most recent trail points for UI rendering. Trails show
+// the published positions so they don't reveal ground truth.
+func (s *DroneSwarmSimulation) queueTrailUpdate(threat *UASThreat) {
+	history := threat.ObservedHistory
+	if history == nil {
+		history = threat.History
+	}
+	if history == nil || s.config.TrailPoints <= 0 {
+		return
+	}
+	s.updateBuffer.QueueMetadataUpdate(threat.ID, "trail", history.Recent(s.config.TrailPoints))
+}
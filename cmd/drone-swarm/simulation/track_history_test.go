@@ -0,0 +1,45 @@
+package simulation
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestTrackHistoryRollsOver(t *testing.T) {
+	history := NewTrackHistory(3)
+	start := time.Now()
+
+	for i := 0; i < 5; i++ {
+		x, y, z := latLonAltToECEF(40.0+float64(i)*0.01, -76.3, 100)
+		history.Record(&models.GeomPoint{Coordinates: []float64{x, y, z}}, start.Add(time.Duration(i)*time.Second))
+	}
+
+	if history.Len() != 3 {
+		t.Fatalf("expected 3 retained points, got %d", history.Len())
+	}
+
+	points := history.Points()
+	for i, p := range points {
+		expectedLat := 40.0 + float64(i+2)*0.01
+		if math.Abs(p.Lat-expectedLat) > 1e-7 {
+			t.Errorf("point %d: expected lat %.5f, got %.7f", i, expectedLat, p.Lat)
+		}
+	}
+
+	recent := history.Recent(2)
+	if len(recent) != 2 || !recent[1].Timestamp.Equal(start.Add(4*time.Second)) {
+		t.Fatalf("expected the 2 newest points oldest-first, got %+v", recent)
+	}
+}
+
+func TestECEFRoundTrip(t *testing.T) {
+	x, y, z := latLonAltToECEF(40.044437, -76.306229, 350)
+	lat, lon, alt := ecefToLatLonAlt(x, y, z)
+
+	if math.Abs(lat-40.044437) > 1e-7 || math.Abs(lon+76.306229) > 1e-7 || math.Abs(alt-350) > 1e-3 {
+		t.Fatalf("round trip mismatch: got %.7f, %.7f, %.4f", lat, lon, alt)
+	}
+}
@@ -0,0 +1,114 @@
+package simulation
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// Training package selection
+const (
+	closeCallMargin     = 0.05 // Target selection scores this close are judgement calls
+	maxDecisionPoints   = 50   // Decision points kept for the debrief
+	maxRecordedDecision = 5000 // Bounds memory on long runs
+)
+
+// trainingLog captures target selections with competing tracks for the trainee debrief
+type trainingLog struct {
+	pending   map[uuid.UUID]reporting.DecisionPoint // Latest selection per system awaiting its engagement
+	decisions []reporting.DecisionPoint
+	leaked    map[string]bool // Track IDs that reached the target
+	mu        sync.Mutex
+}
+
+// noteDecision records what a system saw when it picked a target. Only selections with
+// a real choice are kept; the outcome is filled in when the engagement resolves.
+func (s *DroneSwarmSimulation) noteDecision(system *CounterUASSystem, candidates []reporting.DecisionCandidate) {
+	if !s.config.TrainingPackage || len(candidates) < 2 {
+		return
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].Score > candidates[j].Score })
+
+	now := time.Now()
+	decision := reporting.DecisionPoint{
+		Time:       now,
+		Elapsed:    now.Sub(s.scenarioStart).Round(time.Second).String(),
+		System:     fmt.Sprintf("%s (%s)", system.Callsign, system.Name),
+		Engagement: system.EngagementType,
+		Candidates: candidates,
+		Margin:     candidates[0].Score - candidates[1].Score,
+	}
+
+	s.training.mu.Lock()
+	defer s.training.mu.Unlock()
+	if s.training.pending == nil {
+		s.training.pending = make(map[uuid.UUID]reporting.DecisionPoint)
+	}
+	s.training.pending[system.ID] = decision
+}
+
+// resolveDecision attaches the engagement outcome to the system's pending selection
+func (s *DroneSwarmSimulation) resolveDecision(systemID uuid.UUID, hit bool) {
+	s.training.mu.Lock()
+	defer s.training.mu.Unlock()
+
+	decision, ok := s.training.pending[systemID]
+	if !ok {
+		return
+	}
+	delete(s.training.pending, systemID)
+
+	decision.Outcome = "miss"
+	if hit {
+		decision.Outcome = "hit"
+	}
+	if len(s.training.decisions) < maxRecordedDecision {
+		s.training.decisions = append(s.training.decisions, decision)
+	}
+}
+
+// recordTrainingLeaker notes a track that reached the target
+func (s *DroneSwarmSimulation) recordTrainingLeaker(threat *UASThreat) {
+	s.training.mu.Lock()
+	defer s.training.mu.Unlock()
+	if s.training.leaked == nil {
+		s.training.leaked = make(map[string]bool)
+	}
+	s.training.leaked[threat.ID.String()] = true
+}
+
+// saveTrainingPackage writes the decision points worth debriefing next to the AAR
+func (s *DroneSwarmSimulation) saveTrainingPackage() error {
+	if !s.config.TrainingPackage {
+		return nil
+	}
+
+	s.training.mu.Lock()
+	pkg := reporting.TrainingPackage{
+		RunID:          s.runID,
+		Simulation:     s.Name(),
+		Generated:      time.Now(),
+		CloseCall:      closeCallMargin,
+		TotalDecisions: len(s.training.decisions),
+		DecisionPoints: reporting.SelectDecisionPoints(s.training.decisions, s.training.leaked, closeCallMargin, maxDecisionPoints),
+	}
+	s.training.mu.Unlock()
+
+	filename := fmt.Sprintf("Training_%s_%s", s.runID[:8], time.Now().Format("20060102_150405"))
+	path, err := reporting.SaveTrainingPackage(pkg, reportsDir, filename)
+	if err != nil {
+		return err
+	}
+
+	s.artifacts = append(s.artifacts, path)
+	if s.aarGenerator != nil {
+		s.aarGenerator.AddAttachment(path)
+	}
+	logger.Successf("Training package saved to: %s (%d of %d decisions)", path, len(pkg.DecisionPoints), pkg.TotalDecisions)
+	return nil
+}
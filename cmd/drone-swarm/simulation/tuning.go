@@ -0,0 +1,126 @@
+package simulation
+
+import (
+	"context"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/control"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// Parameters facilitators can change while the run is live
+const (
+	TuneCohesionWeight      = "cohesion_weight"
+	TuneFormationSpacing    = "formation_spacing"
+	TuneSuccessRateModifier = "success_rate_modifier"
+	TuneUpdateDecimation    = "update_decimation"
+)
+
+// Tunable defaults match the behavior before these were configurable
+const (
+	DefaultCohesionWeight      = 0.05
+	DefaultFormationSpacing    = 100.0 // meters
+	DefaultSuccessRateModifier = 1.0
+	DefaultUpdateDecimation    = 1
+)
+
+// startControl brings up the live tuning endpoint when configured
+func (s *DroneSwarmSimulation) startControl() {
+	if s.config.ControlAddr == "" {
+		return
+	}
+
+	s.control = control.NewPanel(s.config.ControlToken)
+	for _, param := range s.tunables() {
+		s.control.Register(param)
+	}
+	s.control.OnReload(s.Reload)
+	s.controlServer = s.control.Serve(s.config.ControlAddr)
+}
+
+// tunables lists the live-tunable parameters with their current values and bounds
+func (s *DroneSwarmSimulation) tunables() []control.Param {
+	return []control.Param{
+		{
+			Name:        TuneCohesionWeight,
+			Description: "Pull of stragglers back toward their swarm center",
+			Value:       s.config.CohesionWeight,
+			Min:         0,
+			Max:         0.5,
+		},
+		{
+			Name:        TuneFormationSpacing,
+			Description: "Swarm spread in meters before cohesion kicks in",
+			Value:       s.config.FormationSpacing,
+			Min:         20,
+			Max:         1000,
+		},
+		{
+			Name:        TuneSuccessRateModifier,
+			Description: "Scales every engagement's kill probability",
+			Value:       s.config.SuccessRateModifier,
+			Min:         0.1,
+			Max:         2.0,
+		},
+		{
+			Name:        TuneUpdateDecimation,
+			Description: "Send threat positions to Legion every Nth tick",
+			Value:       float64(s.config.UpdateDecimation),
+			Min:         1,
+			Max:         20,
+			Integer:     true,
+		},
+	}
+}
+
+// setTuned writes a tunable into the config, reporting false for unknown names
+func (s *DroneSwarmSimulation) setTuned(name string, value float64) bool {
+	switch name {
+	case TuneCohesionWeight:
+		s.config.CohesionWeight = value
+	case TuneFormationSpacing:
+		s.config.FormationSpacing = value
+	case TuneSuccessRateModifier:
+		s.config.SuccessRateModifier = value
+	case TuneUpdateDecimation:
+		s.config.UpdateDecimation = int(value)
+	default:
+		return false
+	}
+	return true
+}
+
+// stopControl shuts the tuning endpoint down
+func (s *DroneSwarmSimulation) stopControl() {
+	if s.controlServer == nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 2*time.Second)
+	defer cancel()
+	_ = s.controlServer.Shutdown(ctx)
+}
+
+// applyTuning applies changes queued since the last tick and records each as an inject
+// so the AAR shows when and why the run's behavior shifted
+func (s *DroneSwarmSimulation) applyTuning() {
+	if s.control == nil {
+		return
+	}
+
+	for _, change := range s.control.Drain() {
+		if !s.setTuned(change.Name, change.New) {
+			continue
+		}
+
+		logger.Infof("Inject from %s: %s %g -> %g", change.Source, change.Name, change.Old, change.New)
+		if s.simLogger != nil {
+			s.simLogger.LogInject(change.Name, change.Old, change.New, change.Source)
+		}
+	}
+}
+
+// sendPositionsThisTick reports whether threat positions go to Legion on this tick
+func (s *DroneSwarmSimulation) sendPositionsThisTick() bool {
+	return s.config.UpdateDecimation <= 1 || s.tick%s.config.UpdateDecimation == 0
+}
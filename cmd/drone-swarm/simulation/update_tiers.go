@@ -0,0 +1,211 @@
+package simulation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// Update tiers, from the most often published to the least
+const (
+	UpdateTierHigh   = "high"   // Engaged or hostile tracks, tracks inside the high range, and systems with targets
+	UpdateTierNormal = "normal" // Everything else
+	UpdateTierLow    = "low"    // Grounded or assembling tracks, tracks beyond the low range, and idle systems
+)
+
+// updateTierOrder lists the tiers from the most significant down
+var updateTierOrder = []string{UpdateTierHigh, UpdateTierNormal, UpdateTierLow}
+
+// UpdateTier is how often a tier's entities are published and the range that puts a
+// track in it
+type UpdateTier struct {
+	Interval time.Duration
+	RangeKm  float64 // high: tracks within it; low: tracks beyond it (0 for no range)
+}
+
+// UpdateTiers are the publish tiers by name
+type UpdateTiers map[string]UpdateTier
+
+// parseUpdateTiers parses "tier:interval[:range_km]" entries separated by semicolons,
+// e.g. "high:1s:3;normal:2s;low:6s:10". A tier left out publishes as often as the tier
+// above it.
+func parseUpdateTiers(spec string) (UpdateTiers, error) {
+	tiers := make(UpdateTiers)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+
+		fields := strings.Split(entry, ":")
+		if len(fields) < 2 || len(fields) > 3 {
+			return nil, fmt.Errorf("update tier %q: expected tier:interval[:range_km]", entry)
+		}
+		name := strings.ToLower(strings.TrimSpace(fields[0]))
+		if name != UpdateTierHigh && name != UpdateTierNormal && name != UpdateTierLow {
+			return nil, fmt.Errorf("update tier %q: tier must be high, normal or low", entry)
+		}
+		if _, dup := tiers[name]; dup {
+			return nil, fmt.Errorf("update tier %s is given twice", name)
+		}
+
+		var tier UpdateTier
+		var err error
+		if tier.Interval, err = time.ParseDuration(strings.TrimSpace(fields[1])); err != nil || tier.Interval < 0 {
+			return nil, fmt.Errorf("update tier %q: invalid interval %q", entry, fields[1])
+		}
+		if len(fields) == 3 {
+			if name == UpdateTierNormal {
+				return nil, fmt.Errorf("update tier %q: only high and low tiers take a range", entry)
+			}
+			if tier.RangeKm, err = strconv.ParseFloat(strings.TrimSpace(fields[2]), 64); err != nil || tier.RangeKm <= 0 {
+				return nil, fmt.Errorf("update tier %q: invalid range %q", entry, fields[2])
+			}
+		}
+		tiers[name] = tier
+	}
+	if len(tiers) == 0 {
+		return nil, nil
+	}
+
+	// Fill gaps from the tier above and keep less significant tiers no faster
+	var above UpdateTier
+	for _, name := range updateTierOrder {
+		tier, ok := tiers[name]
+		if !ok {
+			tiers[name] = UpdateTier{Interval: above.Interval}
+			continue
+		}
+		if tier.Interval < above.Interval {
+			return nil, fmt.Errorf("update tier %s publishes more often than a more significant tier", name)
+		}
+		above = tier
+	}
+	if high, low := tiers[UpdateTierHigh].RangeKm, tiers[UpdateTierLow].RangeKm; high > 0 && low > 0 && high >= low {
+		return nil, fmt.Errorf("high tier range %.1fkm must be inside the low tier range %.1fkm", high, low)
+	}
+	return tiers, nil
+}
+
+// tierPolicy paces each entity's updates by the interval of the tier it was last put
+// in; entities never assigned a tier publish as normal. Implements core.PublishPolicy.
+type tierPolicy struct {
+	tiers    UpdateTiers
+	mu       sync.RWMutex
+	byEntity map[uuid.UUID]string
+	counts   map[string]int // Entity-ticks spent in each tier
+}
+
+// newTierPolicy creates a policy publishing at the given tiers' intervals
+func newTierPolicy(tiers UpdateTiers) *tierPolicy {
+	return &tierPolicy{
+		tiers:    tiers,
+		byEntity: make(map[uuid.UUID]string),
+		counts:   make(map[string]int),
+	}
+}
+
+// Interval returns the publish interval of an entity's tier
+func (p *tierPolicy) Interval(entityID uuid.UUID) time.Duration {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	tier, ok := p.byEntity[entityID]
+	if !ok {
+		tier = UpdateTierNormal
+	}
+	return p.tiers[tier].Interval
+}
+
+// assign puts an entity in a tier for this tick
+func (p *tierPolicy) assign(entityID uuid.UUID, tier string) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.byEntity[entityID] = tier
+	p.counts[tier]++
+}
+
+// startUpdateTiers paces Legion updates by entity significance when update_tiers is set
+func (s *DroneSwarmSimulation) startUpdateTiers() {
+	if len(s.config.UpdateTiers) == 0 {
+		return
+	}
+	s.tierPolicy = newTierPolicy(s.config.UpdateTiers)
+	s.updateBuffer.SetPolicy(s.tierPolicy)
+
+	tiers := make([]string, 0, len(updateTierOrder))
+	for _, name := range updateTierOrder {
+		tiers = append(tiers, fmt.Sprintf("%s every %s", name, s.config.UpdateTiers[name].Interval))
+	}
+	logger.Infof("Update tiers: %s", strings.Join(tiers, ", "))
+}
+
+// assignUpdateTiers sorts this tick's threats and systems into update tiers
+func (s *DroneSwarmSimulation) assignUpdateTiers() {
+	if s.tierPolicy == nil {
+		return
+	}
+	for _, threat := range s.uasThreats {
+		if threat.Remote || threat.Classification == TrackStatusDestroyed || threat.Classification == TrackStatusLost {
+			continue
+		}
+		s.tierPolicy.assign(threat.ID, s.threatTier(threat))
+	}
+	for _, system := range s.counterUASSystems {
+		tier := UpdateTierLow
+		if system.EngagedTarget != nil || len(system.CurrentTargets) > 0 {
+			tier = UpdateTierHigh
+		}
+		s.tierPolicy.assign(system.ID, tier)
+	}
+}
+
+// threatTier places a track by its operational significance: engaged and hostile
+// tracks first, then range, with tracks still at their launch site idle
+func (s *DroneSwarmSimulation) threatTier(threat *UASThreat) string {
+	if !threat.AssignedAt.IsZero() || threat.Classification == TrackStatusHostile {
+		return UpdateTierHigh
+	}
+	distance := s.distanceToBaseKm(threat.Position)
+	if high := s.config.UpdateTiers[UpdateTierHigh]; high.RangeKm > 0 && distance <= high.RangeKm {
+		return UpdateTierHigh
+	}
+	if threat.LaunchPhase == LaunchPhaseGrounded || threat.LaunchPhase == LaunchPhaseForming {
+		return UpdateTierLow
+	}
+	if low := s.config.UpdateTiers[UpdateTierLow]; low.RangeKm > 0 && distance > low.RangeKm {
+		return UpdateTierLow
+	}
+	return UpdateTierNormal
+}
+
+// recordTierMetrics records how entities were spread across update tiers and how many
+// updates the tiers held back
+func (s *DroneSwarmSimulation) recordTierMetrics() {
+	if s.tierPolicy == nil {
+		return
+	}
+	s.tierPolicy.mu.RLock()
+	high, normal, low := s.tierPolicy.counts[UpdateTierHigh], s.tierPolicy.counts[UpdateTierNormal], s.tierPolicy.counts[UpdateTierLow]
+	s.tierPolicy.mu.RUnlock()
+	deferred := s.updateBuffer.GetStats().UpdatesDeferred
+
+	s.simLogger.UpdateMetric(reporting.MetricUpdatesDeferred, float64(deferred), "count")
+	total := high + normal + low
+	if total == 0 {
+		return
+	}
+	share := func(n int) float64 { return float64(n) / float64(total) }
+	s.simLogger.UpdateMetric(reporting.MetricUpdateTierHigh, share(high), "ratio")
+	s.simLogger.UpdateMetric(reporting.MetricUpdateTierNormal, share(normal), "ratio")
+	s.simLogger.UpdateMetric(reporting.MetricUpdateTierLow, share(low), "ratio")
+
+	logger.Infof("Update tiers: %.0f%% high, %.0f%% normal, %.0f%% low; %d updates held for their tier",
+		share(high)*100, share(normal)*100, share(low)*100, deferred)
+}
@@ -0,0 +1,90 @@
+package simulation
+
+import (
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestParseUpdateTiers(t *testing.T) {
+	tiers, err := parseUpdateTiers("high:1s:3; Low:6s:10")
+	if err != nil {
+		t.Fatalf("parseUpdateTiers: %v", err)
+	}
+	if tiers[UpdateTierHigh] != (UpdateTier{Interval: time.Second, RangeKm: 3}) ||
+		tiers[UpdateTierNormal] != (UpdateTier{Interval: time.Second}) ||
+		tiers[UpdateTierLow] != (UpdateTier{Interval: 6 * time.Second, RangeKm: 10}) {
+		t.Errorf("unexpected tiers %+v", tiers)
+	}
+	if tiers, err := parseUpdateTiers(""); err != nil || tiers != nil {
+		t.Errorf("empty spec = %v, %v; want no tiers", tiers, err)
+	}
+
+	for _, spec := range []string{
+		"high",                   // no interval
+		"urgent:1s",              // unknown tier
+		"high:soon",              // bad interval
+		"normal:2s:5",            // range on the normal tier
+		"low:6s:-1",              // negative range
+		"high:1s;high:2s",        // duplicate
+		"high:5s;low:1s",         // low faster than high
+		"high:1s:10;low:5s:8",    // high range outside low range
+		"high:1s:3:extra;low:5s", // too many fields
+	} {
+		if _, err := parseUpdateTiers(spec); err == nil {
+			t.Errorf("parseUpdateTiers(%q) succeeded, want an error", spec)
+		}
+	}
+}
+
+func TestThreatTier(t *testing.T) {
+	tiers, _ := parseUpdateTiers("high:1s:3;normal:2s;low:6s:10")
+	s := &DroneSwarmSimulation{config: SimulationConfig{
+		BaseLocation: Location{Lat: 38.8977, Lon: -77.0365, Alt: 50},
+		UpdateTiers:  tiers,
+	}}
+	at := func(km float64) *models.GeomPoint {
+		lat, lon := destinationPoint(s.config.BaseLocation.Lat, s.config.BaseLocation.Lon, 90, km*1000)
+		x, y, z := latLonAltToECEF(lat, lon, s.config.BaseLocation.Alt)
+		return &models.GeomPoint{Coordinates: []float64{x, y, z}}
+	}
+
+	tests := []struct {
+		name   string
+		threat *UASThreat
+		want   string
+	}{
+		{"near", &UASThreat{Position: at(2), Classification: TrackStatusUnknown}, UpdateTierHigh},
+		{"mid-range", &UASThreat{Position: at(6), Classification: TrackStatusUnknown}, UpdateTierNormal},
+		{"distant", &UASThreat{Position: at(15), Classification: TrackStatusUnknown}, UpdateTierLow},
+		{"distant hostile", &UASThreat{Position: at(15), Classification: TrackStatusHostile}, UpdateTierHigh},
+		{"distant assigned", &UASThreat{Position: at(15), Classification: TrackStatusSuspected, AssignedAt: time.Now()}, UpdateTierHigh},
+		{"assembling", &UASThreat{Position: at(6), Classification: TrackStatusPending, LaunchPhase: LaunchPhaseForming}, UpdateTierLow},
+	}
+	for _, tt := range tests {
+		if got := s.threatTier(tt.threat); got != tt.want {
+			t.Errorf("%s: tier %s, want %s", tt.name, got, tt.want)
+		}
+	}
+}
+
+func TestTierPolicyInterval(t *testing.T) {
+	tiers, _ := parseUpdateTiers("high:1s;normal:2s;low:6s")
+	policy := newTierPolicy(tiers)
+	engaged, idle, unknown := uuid.New(), uuid.New(), uuid.New()
+	policy.assign(engaged, UpdateTierHigh)
+	policy.assign(idle, UpdateTierLow)
+
+	if got := policy.Interval(engaged); got != time.Second {
+		t.Errorf("high tier interval %s, want 1s", got)
+	}
+	if got := policy.Interval(idle); got != 6*time.Second {
+		t.Errorf("low tier interval %s, want 6s", got)
+	}
+	if got := policy.Interval(unknown); got != 2*time.Second {
+		t.Errorf("unassigned entity interval %s, want the normal 2s", got)
+	}
+}
@@ -0,0 +1,161 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/core"
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// Post-run verification
+const (
+	verifyTimeout          = 2 * time.Minute
+	verifyOffsetToleranceM = 5.0 // Legion stores coordinates as float32, about 0.5m at ECEF scale
+	verifyLoggedIssues     = 5   // Mismatched entities printed to the console
+)
+
+// legionCheck compares one entity's Legion record with what the run sent
+type legionCheck struct {
+	Entity            string   `json:"entity"`
+	ID                string   `json:"id"`
+	SentPositions     int      `json:"sent_positions"`
+	RecordedPositions int      `json:"recorded_positions"`
+	FinalOffsetM      float64  `json:"final_offset_m"`
+	SentStatus        string   `json:"sent_status,omitempty"`
+	LegionStatus      string   `json:"legion_status"`
+	Issues            []string `json:"issues,omitempty"`
+}
+
+// legionVerification is the post-run record comparison written with the AAR
+type legionVerification struct {
+	RunID      string        `json:"run_id"`
+	Checked    int           `json:"checked"`
+	Mismatched int           `json:"mismatched"`
+	Entities   []legionCheck `json:"entities"`
+}
+
+// verifyLegionRecord reads back each entity's status and location history and checks
+// it against what the update buffer believes it wrote
+func (s *DroneSwarmSimulation) verifyLegionRecord(ctx context.Context) error {
+	if !s.config.VerifyLegion || s.legionClient == nil {
+		return nil
+	}
+	logger.Info("Verifying Legion's record of the run...")
+
+	ctx, cancel := context.WithTimeout(ctx, verifyTimeout)
+	defer cancel()
+	if err := s.updateBuffer.ForceFlush(ctx); err != nil {
+		logger.Warnf("Final flush before verification failed: %v", err)
+	}
+
+	type owned struct {
+		id   uuid.UUID
+		name string
+	}
+	var entities []owned
+	for _, system := range s.counterUASSystems {
+		entities = append(entities, owned{system.ID, system.Name})
+	}
+	for _, threat := range s.uasThreats {
+		if !threat.Remote {
+			entities = append(entities, owned{threat.ID, "Track " + threat.TrackNumber})
+		}
+	}
+
+	report := legionVerification{RunID: s.runID}
+	orgCtx := client.WithOrgID(ctx, s.config.OrganizationID)
+	for _, entity := range entities {
+		sent, ok := s.updateBuffer.Sent(entity.id)
+		if !ok {
+			continue
+		}
+		check, err := s.checkEntityRecord(orgCtx, entity.id, entity.name, sent)
+		if err != nil {
+			return err
+		}
+		report.Checked++
+		if len(check.Issues) > 0 {
+			report.Mismatched++
+			if report.Mismatched <= verifyLoggedIssues {
+				logger.Warnf("Legion record for %s differs: %s", check.Entity, strings.Join(check.Issues, "; "))
+			}
+		}
+		report.Entities = append(report.Entities, check)
+	}
+
+	path := filepath.Join(reportsDir, fmt.Sprintf("Verification_%s_%s.json", s.runID[:8], time.Now().Format("20060102_150405")))
+	if err := writeVerification(path, report); err != nil {
+		return err
+	}
+	s.artifacts = append(s.artifacts, path)
+	if s.aarGenerator != nil {
+		s.aarGenerator.AddAttachment(path)
+	}
+
+	if report.Mismatched > 0 {
+		logger.Warnf("Legion record matches for %d of %d entities; details in %s", report.Checked-report.Mismatched, report.Checked, path)
+	} else {
+		logger.Successf("Legion record matches for all %d entities", report.Checked)
+	}
+	return nil
+}
+
+// checkEntityRecord compares one entity's Legion history with its sent record
+func (s *DroneSwarmSimulation) checkEntityRecord(ctx context.Context, id uuid.UUID, name string, sent core.SentRecord) (legionCheck, error) {
+	check := legionCheck{
+		Entity:        name,
+		ID:            id.String(),
+		SentPositions: sent.Positions,
+		SentStatus:    sent.LastStatus,
+	}
+
+	// Start just before the first write; the server may truncate sub-second timestamps
+	since := sent.FirstSentAt.Add(-time.Second)
+	history, err := s.legionClient.GetEntityHistory(ctx, id, &since, nil, core.LocationSource)
+	if err != nil {
+		return check, fmt.Errorf("failed to verify %s: %w", name, err)
+	}
+	check.RecordedPositions = len(history.Locations)
+	check.LegionStatus = history.Entity.Status
+
+	if check.RecordedPositions < sent.Positions {
+		check.Issues = append(check.Issues, fmt.Sprintf("%d of %d locations missing", sent.Positions-check.RecordedPositions, sent.Positions))
+	}
+	if sent.LastStatus != "" && sent.LastStatus != history.Entity.Status {
+		check.Issues = append(check.Issues, fmt.Sprintf("status %s, expected %s", history.Entity.Status, sent.LastStatus))
+	}
+	if sent.LastPosition != nil && len(history.Locations) > 0 {
+		last := history.Locations[len(history.Locations)-1].Position.Coordinates
+		expected := sent.LastPosition.Coordinates
+		if len(last) >= 3 && len(expected) >= 3 {
+			check.FinalOffsetM = math.Sqrt(math.Pow(last[0]-expected[0], 2) + math.Pow(last[1]-expected[1], 2) + math.Pow(last[2]-expected[2], 2))
+			if check.FinalOffsetM > verifyOffsetToleranceM {
+				check.Issues = append(check.Issues, fmt.Sprintf("final position %.0fm from last sent", check.FinalOffsetM))
+			}
+		}
+	}
+	return check, nil
+}
+
+func writeVerification(path string, report legionVerification) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create output directory: %w", err)
+	}
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal verification: %w", err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("failed to write verification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,125 @@
+package simulation
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/core"
+	"github.com/picogrid/legion-simulations/cmd/drone-swarm/reporting"
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// newWarmupSimulation builds a run with two Counter-UAS systems, each with a health
+// telemetry feed, held in memory
+func newWarmupSimulation(t *testing.T, warmup time.Duration) (*DroneSwarmSimulation, *client.Memory) {
+	t.Helper()
+	legion, memory := client.NewMemoryClient()
+	orgID := uuid.New()
+	s := &DroneSwarmSimulation{
+		config:             SimulationConfig{OrganizationID: orgID.String(), WarmupDuration: warmup},
+		legionClient:       legion,
+		updateBuffer:       core.NewUpdateBuffer(legion, orgID.String(), 50, time.Second),
+		simLogger:          reporting.NewSimulationLogger("test"),
+		counterUASSystems:  make(map[uuid.UUID]*CounterUASSystem),
+		systemHealthFeeds:  make(map[uuid.UUID]uuid.UUID),
+		lastReportedHealth: make(map[uuid.UUID]float64),
+		stopChan:           make(chan struct{}),
+	}
+
+	ctx := client.WithOrgID(context.Background(), orgID.String())
+	for _, name := range []string{"CUAS-01", "CUAS-02"} {
+		category, entityType, status := models.CategoryDEVICE, EntityTypeCounterUAS, CounterUASStatusIdle
+		entity, err := legion.CreateEntity(ctx, &models.CreateEntityRequest{
+			OrganizationID: &orgID, Name: &name, Category: &category, Type: &entityType, Status: &status,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		feedID, err := s.createHealthTelemetryFeed(context.Background(), entity.ID, name)
+		if err != nil {
+			t.Fatal(err)
+		}
+		s.systemHealthFeeds[entity.ID] = feedID
+		s.counterUASSystems[entity.ID] = &CounterUASSystem{
+			ID: entity.ID, Name: name, Callsign: name, Status: CounterUASStatusIdle, SystemHealth: 1.0, PowerLevel: 1.0,
+		}
+	}
+	return s, memory
+}
+
+func TestRunWarmupPublishesBITAndHolds(t *testing.T) {
+	s, memory := newWarmupSimulation(t, 300*time.Millisecond)
+
+	start := time.Now()
+	if err := s.runWarmup(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if elapsed := time.Since(start); elapsed < 300*time.Millisecond {
+		t.Errorf("expected warm-up to hold for its 300ms, took %s", elapsed)
+	}
+
+	if got := memory.Summary().FeedMessages; got != len(s.counterUASSystems) {
+		t.Errorf("expected initial health telemetry from each system, got %d messages", got)
+	}
+	for _, system := range s.counterUASSystems {
+		if system.Status == CounterUASStatusBIT {
+			t.Errorf("expected %s operational after warm-up, still in BIT", system.Callsign)
+		}
+		if _, ok := s.lastReportedHealth[system.ID]; !ok || system.LastHealthUpdate.IsZero() {
+			t.Errorf("expected %s's BIT health recorded", system.Callsign)
+		}
+	}
+	bits := 0
+	for _, call := range memory.CallsTo("PUT", "/v3/entities/{id}") {
+		var body struct {
+			Metadata map[string]json.RawMessage `json:"metadata"`
+		}
+		if json.Unmarshal(call.Body, &body) == nil && body.Metadata["bit"] != nil {
+			bits++
+		}
+	}
+	if bits != len(s.counterUASSystems) {
+		t.Errorf("expected each system's BIT result published, got %d", bits)
+	}
+	if got := s.updateBuffer.GetPendingCount(); got != 0 {
+		t.Errorf("expected warm-up updates flushed, got %d pending", got)
+	}
+}
+
+func TestRunWarmupDisabled(t *testing.T) {
+	s, memory := newWarmupSimulation(t, 0)
+	before := memory.Summary().Requests
+
+	if err := s.runWarmup(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := memory.Summary().Requests; got != before {
+		t.Errorf("expected no warm-up traffic with warmup_duration 0, got %d requests", got-before)
+	}
+}
+
+func TestRunWarmupCancelled(t *testing.T) {
+	s, _ := newWarmupSimulation(t, time.Minute)
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	err := s.runWarmup(ctx)
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the context's error so the run stops before wave 1, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Errorf("expected a cancelled warm-up to return promptly, took %s", elapsed)
+	}
+	for _, system := range s.counterUASSystems {
+		if system.Status != CounterUASStatusBIT && system.Status != CounterUASStatusDegraded {
+			t.Errorf("expected %s held in BIT, got %s", system.Callsign, system.Status)
+		}
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"math"
 	"math/rand"
+	"strings"
 	"sync"
 	"time"
 
@@ -17,20 +18,26 @@ import (
 	"github.com/picogrid/legion-simulations/pkg/simulation"
 )
 
+// droneType is the entity type of every drone the simulation creates
+const droneType = "Drone"
+
 // DroneTornadoSimulation creates drones and moves them in a circle at constant speed
 type DroneTornadoSimulation struct {
 	config         *Config
 	entityIDs      []string
+	deleted        map[string]bool // Removed by delete-on-exit
 	perDroneRadius []float64
 	mu             sync.Mutex
 	stopChan       chan struct{}
 	startTime      time.Time
+	seed           int64
 }
 
 // NewDroneTornadoSimulation creates a new instance
 func NewDroneTornadoSimulation() simulation.Simulation {
 	return &DroneTornadoSimulation{
 		entityIDs: make([]string, 0),
+		deleted:   make(map[string]bool),
 		stopChan:  make(chan struct{}),
 	}
 }
@@ -56,9 +63,9 @@ func (s *DroneTornadoSimulation) Configure(params map[string]interface{}) error
 }
 
 // Run executes the simulation
-func (s *DroneTornadoSimulation) Run(ctx context.Context, legionClient *client.Legion) error {
+func (s *DroneTornadoSimulation) Run(ctx context.Context, legionClient *client.Legion) (*simulation.Result, error) {
 	if s.config == nil {
-		return fmt.Errorf("simulation not configured")
+		return nil, fmt.Errorf("simulation not configured")
 	}
 
 	logger.Infof("Starting %s with %d drones, radius=%.1fm, speed=%.1fm/s", s.Name(), s.config.NumDrones, s.config.RadiusMeters, s.config.SpeedMetersPerS)
@@ -77,7 +84,7 @@ func (s *DroneTornadoSimulation) Run(ctx context.Context, legionClient *client.L
 	for i := 0; i < s.config.NumDrones; i++ {
 		entityID, err := s.createDroneEntity(ctx, legionClient, i)
 		if err != nil {
-			return fmt.Errorf("failed to create entity %d: %w", i+1, err)
+			return s.result(simulation.OutcomeStopped, "Failed while creating drones"), fmt.Errorf("failed to create entity %d: %w", i+1, err)
 		}
 		s.mu.Lock()
 		s.entityIDs = append(s.entityIDs, entityID)
@@ -89,7 +96,8 @@ func (s *DroneTornadoSimulation) Run(ctx context.Context, legionClient *client.L
 	// Initialize per-drone radii with ±offset randomness, fixed for the run
 	s.mu.Lock()
 	s.perDroneRadius = make([]float64, len(s.entityIDs))
-	r := rand.New(rand.NewSource(s.startTime.UnixNano()))
+	var r *rand.Rand
+	r, s.seed = simulation.NewRand(s.config.Seed)
 	for i := range s.entityIDs {
 		if s.config.RadiusOffsetM > 0 {
 			offset := (r.Float64()*2 - 1) * s.config.RadiusOffsetM
@@ -113,19 +121,19 @@ func (s *DroneTornadoSimulation) Run(ctx context.Context, legionClient *client.L
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return s.result(simulation.OutcomeStopped, "Cancelled"), ctx.Err()
 		case <-s.stopChan:
 			logger.Info("Simulation stopped by user")
 			if s.config.DeleteOnExit {
 				s.deleteCreatedEntities(ctx, legionClient)
 			}
-			return nil
+			return s.result(simulation.OutcomeStopped, "Stopped by user"), nil
 		case <-timeout:
 			logger.Infof("Simulation completed after %s", s.config.Duration)
 			if s.config.DeleteOnExit {
 				s.deleteCreatedEntities(ctx, legionClient)
 			}
-			return nil
+			return s.result(simulation.OutcomeCompleted, fmt.Sprintf("Circled %d drones for %s", s.config.NumDrones, s.config.Duration)), nil
 		case <-ticker.C:
 			if err := s.updateLocations(ctx, legionClient); err != nil {
 				logger.Errorf("Error updating locations: %v", err)
@@ -134,6 +142,30 @@ func (s *DroneTornadoSimulation) Run(ctx context.Context, legionClient *client.L
 	}
 }
 
+// result describes the run with the drones it left in Legion
+func (s *DroneTornadoSimulation) result(outcome, summary string) *simulation.Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := simulation.NewResult(outcome, summary)
+	for i, id := range s.entityIDs {
+		if !s.deleted[id] {
+			result.AddEntity(id, s.droneName(i), droneType)
+		}
+	}
+	result.Stats["drones"] = float64(s.config.NumDrones)
+	result.Seed = s.seed
+	return result
+}
+
+// droneNamePrefix starts every drone's name
+const droneNamePrefix = "Drone "
+
+// droneName returns the name of the drone at index, in the run's namespace
+func (s *DroneTornadoSimulation) droneName(index int) string {
+	return simulation.Namespace(s.config.RunID, fmt.Sprintf("%s%d", droneNamePrefix, index+1))
+}
+
 // Stop gracefully stops the simulation
 func (s *DroneTornadoSimulation) Stop() error {
 	close(s.stopChan)
@@ -141,11 +173,11 @@ func (s *DroneTornadoSimulation) Stop() error {
 }
 
 // createDroneEntity creates a single drone entity in Legion
-func (s *DroneTornadoSimulation) createDroneEntity(ctx context.Context, legionClient *client.Legion, index int) (string, error) {
+func (s *DroneTornadoSimulation) createDroneEntity(ctx context.Context, legionClient client.LegionAPI, index int) (string, error) {
 	number := index + 1
-	name := fmt.Sprintf("Drone %d", number)
+	name := s.droneName(index)
 	category := models.CategoryDEVICE
-	entityType := "Drone"
+	entityType := droneType
 	status := "ACTIVE"
 
 	// Ensure organization ID valid
@@ -160,8 +192,16 @@ func (s *DroneTornadoSimulation) createDroneEntity(ctx context.Context, legionCl
 		Type:     entityType,
 	}
 	searchReq := &models.SearchEntitiesRequest{OrganizationID: &orgUUID, Filters: searchFilters}
-	if resp, err := legionClient.SearchEntities(ctx, searchReq); err == nil && resp != nil && len(resp.Results) > 0 {
-		existing := resp.Results[0]
+	// The partial match also finds Drone 10 for Drone 1, and other runs' namespaces
+	var existing *models.EntityResponse
+	_ = legionClient.ForEachEntity(ctx, searchReq, func(entity models.EntityResponse) bool {
+		if entity.Name == name {
+			existing = &entity
+			return false
+		}
+		return true
+	})
+	if existing != nil {
 		logger.Infof("Using existing entity: %s (%s)", existing.Name, existing.ID)
 		return existing.ID.String(), nil
 	}
@@ -192,7 +232,7 @@ func (s *DroneTornadoSimulation) createDroneEntity(ctx context.Context, legionCl
 }
 
 // updateLocations updates locations for all drones along the circular path
-func (s *DroneTornadoSimulation) updateLocations(ctx context.Context, legionClient *client.Legion) error {
+func (s *DroneTornadoSimulation) updateLocations(ctx context.Context, legionClient client.LegionAPI) error {
 	s.mu.Lock()
 	ids := make([]string, len(s.entityIDs))
 	copy(ids, s.entityIDs)
@@ -243,7 +283,7 @@ func (s *DroneTornadoSimulation) updateLocations(ctx context.Context, legionClie
 }
 
 // cleanupExistingEntities removes pre-existing Drone Tornado-like entities
-func (s *DroneTornadoSimulation) cleanupExistingEntities(ctx context.Context, legionClient *client.Legion) error {
+func (s *DroneTornadoSimulation) cleanupExistingEntities(ctx context.Context, legionClient client.LegionAPI) error {
 	category := models.CategoryDEVICE
 	entityType := "Drone"
 
@@ -251,22 +291,21 @@ func (s *DroneTornadoSimulation) cleanupExistingEntities(ctx context.Context, le
 	if err != nil {
 		return fmt.Errorf("invalid organization ID: %w", err)
 	}
-	// Search for any entities named with prefix "Drone " and type/category matching
+	// Search for any entities named with the drone prefix, in this run's namespace, and type/category matching
+	prefix := simulation.Namespace(s.config.RunID, droneNamePrefix)
 	filters := &models.SearchFilters{
-		Name:     "Drone ", // partial match
+		Name:     prefix, // partial match
 		Category: []models.Category{category},
 		Type:     entityType,
 	}
 	req := &models.SearchEntitiesRequest{OrganizationID: &orgUUID, Filters: filters}
-	resp, err := legionClient.SearchEntities(ctx, req)
+	// Collect every page before deleting, since deletions shift the pages after them
+	entities, err := legionClient.SearchAllEntities(ctx, req)
 	if err != nil {
 		return err
 	}
-	if resp == nil || len(resp.Results) == 0 {
-		return nil
-	}
-	for _, e := range resp.Results {
-		if e.ID == uuid.Nil {
+	for _, e := range entities {
+		if e.ID == uuid.Nil || !strings.HasPrefix(e.Name, prefix) {
 			continue
 		}
 		if err := legionClient.DeleteEntity(ctx, e.ID.String()); err != nil {
@@ -279,7 +318,7 @@ func (s *DroneTornadoSimulation) cleanupExistingEntities(ctx context.Context, le
 }
 
 // deleteCreatedEntities removes entities created during this run
-func (s *DroneTornadoSimulation) deleteCreatedEntities(ctx context.Context, legionClient *client.Legion) {
+func (s *DroneTornadoSimulation) deleteCreatedEntities(ctx context.Context, legionClient client.LegionAPI) {
 	s.mu.Lock()
 	ids := make([]string, len(s.entityIDs))
 	copy(ids, s.entityIDs)
@@ -288,7 +327,11 @@ func (s *DroneTornadoSimulation) deleteCreatedEntities(ctx context.Context, legi
 	for _, id := range ids {
 		if err := legionClient.DeleteEntity(ctx, id); err != nil {
 			logger.Warnf("Failed to delete entity %s on exit: %v", id, err)
+			continue
 		}
+		s.mu.Lock()
+		s.deleted[id] = true
+		s.mu.Unlock()
 	}
 }
 
@@ -3,6 +3,8 @@ package simple
 import (
 	"fmt"
 	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
 )
 
 // Config holds the configuration for the simple simulation
@@ -17,60 +19,30 @@ type Config struct {
 // ValidateAndParse validates and parses the raw parameters into a Config
 func ValidateAndParse(params map[string]interface{}) (*Config, error) {
 	config := &Config{}
+	p := simulation.ReadParams(params)
 
-	// Parse num_entities
-	if v, ok := params["num_entities"]; ok {
-		switch val := v.(type) {
-		case int:
-			config.NumEntities = val
-		case float64:
-			config.NumEntities = int(val)
-		default:
-			return nil, fmt.Errorf("num_entities must be an integer")
-		}
+	p.Int("num_entities", &config.NumEntities)
+	p.String("entity_type", &config.EntityType)
+	p.Seconds("update_interval", &config.UpdateInterval)
+	p.Duration("duration", &config.Duration)
+	p.String("organization_id", &config.OrganizationID)
+	if err := p.Err(); err != nil {
+		return nil, err
 	}
+
 	if config.NumEntities < 1 || config.NumEntities > 5 {
 		return nil, fmt.Errorf("num_entities must be between 1 and 5")
 	}
 
-	// Parse entity_type
-	if v, ok := params["entity_type"]; ok {
-		config.EntityType = fmt.Sprintf("%v", v)
-	}
 	validTypes := map[string]bool{"Camera": true, "Drone": true, "Sensor": true}
 	if !validTypes[config.EntityType] {
 		return nil, fmt.Errorf("entity_type must be one of: Camera, Drone, Sensor")
 	}
 
-	// Parse update_interval
-	if v, ok := params["update_interval"]; ok {
-		switch val := v.(type) {
-		case float64:
-			config.UpdateInterval = time.Duration(val * float64(time.Second))
-		case int:
-			config.UpdateInterval = time.Duration(val) * time.Second
-		default:
-			return nil, fmt.Errorf("update_interval must be a number")
-		}
-	}
 	if config.UpdateInterval < time.Second || config.UpdateInterval > 60*time.Second {
 		return nil, fmt.Errorf("update_interval must be between 1 and 60 seconds")
 	}
 
-	// Parse duration
-	if v, ok := params["duration"]; ok {
-		durationStr := fmt.Sprintf("%v", v)
-		duration, err := time.ParseDuration(durationStr)
-		if err != nil {
-			return nil, fmt.Errorf("invalid duration format: %w", err)
-		}
-		config.Duration = duration
-	}
-
-	// Parse organization_id
-	if v, ok := params["organization_id"]; ok {
-		config.OrganizationID = fmt.Sprintf("%v", v)
-	}
 	if config.OrganizationID == "" {
 		return nil, fmt.Errorf("organization_id is required")
 	}
@@ -23,6 +23,9 @@ type Location struct {
 	Lon   float64
 }
 
+// droneType is the entity type of every drone the simulation creates
+const droneType = "UAV"
+
 // SimpleSimulation implements a basic simulation with a few entities
 type SimpleSimulation struct {
 	config    *Config
@@ -78,7 +81,7 @@ func (s *SimpleSimulation) Configure(params map[string]interface{}) error {
 }
 
 // Run executes the simulation
-func (s *SimpleSimulation) Run(ctx context.Context, legionClient *client.Legion) error {
+func (s *SimpleSimulation) Run(ctx context.Context, legionClient *client.Legion) (*simulation.Result, error) {
 	logger.Infof("Starting %s simulation with %d drones", s.Name(), s.config.NumEntities)
 
 	// Add organization ID to context for all API calls
@@ -89,7 +92,7 @@ func (s *SimpleSimulation) Run(ctx context.Context, legionClient *client.Legion)
 
 		entityID, err := s.createEntity(ctx, legionClient, i, location)
 		if err != nil {
-			return fmt.Errorf("failed to create drone %d: %w", i+1, err)
+			return s.result(simulation.OutcomeStopped, "Failed while creating drones"), fmt.Errorf("failed to create drone %d: %w", i+1, err)
 		}
 		s.mu.Lock()
 		s.entities = append(s.entities, entityID)
@@ -104,13 +107,13 @@ func (s *SimpleSimulation) Run(ctx context.Context, legionClient *client.Legion)
 	for {
 		select {
 		case <-ctx.Done():
-			return ctx.Err()
+			return s.result(simulation.OutcomeStopped, "Cancelled"), ctx.Err()
 		case <-s.stopChan:
 			logger.Info("Simulation stopped by user")
-			return nil
+			return s.result(simulation.OutcomeStopped, "Stopped by user"), nil
 		case <-timeout:
 			logger.Infof("Simulation completed after %s", s.config.Duration)
-			return nil
+			return s.result(simulation.OutcomeCompleted, fmt.Sprintf("Moved %d drones for %s", s.config.NumEntities, s.config.Duration)), nil
 		case <-ticker.C:
 			if err := s.updateLocations(ctx, legionClient); err != nil {
 				logger.Errorf("Error updating locations: %v", err)
@@ -119,6 +122,24 @@ func (s *SimpleSimulation) Run(ctx context.Context, legionClient *client.Legion)
 	}
 }
 
+// result describes the run with the drones it created or reused
+func (s *SimpleSimulation) result(outcome, summary string) *simulation.Result {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	result := simulation.NewResult(outcome, summary)
+	for i, id := range s.entities {
+		result.AddEntity(id, s.droneName(i), droneType)
+	}
+	result.Stats["entities"] = float64(len(s.entities))
+	return result
+}
+
+// droneName returns the name of the drone at index
+func (s *SimpleSimulation) droneName(index int) string {
+	return fmt.Sprintf("Simulator Drone %d - %s", index+1, s.locations[index%len(s.locations)].City)
+}
+
 // Stop gracefully shuts down the simulation
 func (s *SimpleSimulation) Stop() error {
 	close(s.stopChan)
@@ -131,11 +152,11 @@ func (s *SimpleSimulation) getEntityCategory() string {
 }
 
 // createEntity creates a single entity in Legion
-func (s *SimpleSimulation) createEntity(ctx context.Context, legionClient *client.Legion, index int, location Location) (string, error) {
+func (s *SimpleSimulation) createEntity(ctx context.Context, legionClient client.LegionAPI, index int, location Location) (string, error) {
 	droneNumber := index + 1
-	droneName := fmt.Sprintf("Simulator Drone %d - %s", droneNumber, location.City)
+	droneName := s.droneName(index)
 	category := models.CategoryUXV
-	entityType := "UAV"
+	entityType := droneType
 	status := "ACTIVE"
 
 	orgID, err := uuid.Parse(s.config.OrganizationID)
@@ -200,7 +221,7 @@ func (s *SimpleSimulation) createEntity(ctx context.Context, legionClient *clien
 }
 
 // updateLocations updates the location of all entities
-func (s *SimpleSimulation) updateLocations(ctx context.Context, legionClient *client.Legion) error {
+func (s *SimpleSimulation) updateLocations(ctx context.Context, legionClient client.LegionAPI) error {
 	s.mu.Lock()
 	entityIDs := make([]string, len(s.entities))
 	copy(entityIDs, s.entities)
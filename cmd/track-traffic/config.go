@@ -2,7 +2,11 @@ package tracktraffic
 
 import (
 	"fmt"
+	"strings"
 	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+	"github.com/picogrid/legion-simulations/pkg/world"
 )
 
 // Config holds the configuration for the track traffic simulation.
@@ -20,38 +24,41 @@ type Config struct {
 	HistoryStep     time.Duration
 	DeleteOnExit    bool
 	OrganizationID  string
+	WorldURL        string // Shared world with other simulations (empty disables)
+	WorldRole       string // Role the tracks take in the shared world
 }
 
 // ValidateAndParse validates and parses raw parameters into a Config.
 func ValidateAndParse(params map[string]interface{}) (*Config, error) {
 	cfg := &Config{
 		DeleteOnExit: true,
+		WorldRole:    world.RoleTraffic,
+	}
+	p := simulation.ReadParams(params)
+
+	p.Int("total_tracks", &cfg.TotalTracks)
+	p.Int("max_concurrency", &cfg.MaxConcurrency)
+	p.Seconds("update_interval", &cfg.UpdateInterval)
+	p.Duration("duration", &cfg.Duration)
+	p.Float("center_lat", &cfg.CenterLat)
+	p.Float("center_lon", &cfg.CenterLon)
+	p.Float("center_alt_m", &cfg.CenterAltMeters)
+	p.Float("grid_spacing_m", &cfg.GridSpacingM)
+	p.Float("grid_jitter_m", &cfg.GridJitterM)
+	p.Int("history_points", &cfg.HistoryPoints)
+	p.Seconds("history_step_seconds", &cfg.HistoryStep)
+	p.Bool("delete_on_exit", &cfg.DeleteOnExit)
+	p.String("world_url", &cfg.WorldURL)
+	p.String("world_role", &cfg.WorldRole)
+	p.String("organization_id", &cfg.OrganizationID)
+	if err := p.Err(); err != nil {
+		return nil, err
 	}
 
-	if v, ok := params["total_tracks"]; ok {
-		switch val := v.(type) {
-		case int:
-			cfg.TotalTracks = val
-		case float64:
-			cfg.TotalTracks = int(val)
-		default:
-			return nil, fmt.Errorf("total_tracks must be an integer")
-		}
-	}
 	if cfg.TotalTracks < 1 {
 		return nil, fmt.Errorf("total_tracks must be at least 1")
 	}
 
-	if v, ok := params["max_concurrency"]; ok {
-		switch val := v.(type) {
-		case int:
-			cfg.MaxConcurrency = val
-		case float64:
-			cfg.MaxConcurrency = int(val)
-		default:
-			return nil, fmt.Errorf("max_concurrency must be an integer")
-		}
-	}
 	if cfg.MaxConcurrency <= 0 {
 		cfg.MaxConcurrency = minInt(maxInt(cfg.TotalTracks/10, 8), 64)
 	}
@@ -59,89 +66,17 @@ func ValidateAndParse(params map[string]interface{}) (*Config, error) {
 		cfg.MaxConcurrency = cfg.TotalTracks
 	}
 
-	if v, ok := params["update_interval"]; ok {
-		switch val := v.(type) {
-		case float64:
-			cfg.UpdateInterval = time.Duration(val * float64(time.Second))
-		case int:
-			cfg.UpdateInterval = time.Duration(val) * time.Second
-		default:
-			return nil, fmt.Errorf("update_interval must be a number (seconds)")
-		}
-	}
 	if cfg.UpdateInterval < time.Second {
 		return nil, fmt.Errorf("update_interval must be at least 1 second")
 	}
 
-	if v, ok := params["duration"]; ok {
-		durationStr := fmt.Sprintf("%v", v)
-		duration, err := time.ParseDuration(durationStr)
-		if err != nil {
-			return nil, fmt.Errorf("invalid duration format: %w", err)
-		}
-		cfg.Duration = duration
-	}
 	if cfg.Duration <= 0 {
 		return nil, fmt.Errorf("duration must be greater than 0")
 	}
 
-	if v, ok := params["center_lat"]; ok {
-		switch val := v.(type) {
-		case float64:
-			cfg.CenterLat = val
-		case int:
-			cfg.CenterLat = float64(val)
-		default:
-			return nil, fmt.Errorf("center_lat must be a number")
-		}
-	}
-
-	if v, ok := params["center_lon"]; ok {
-		switch val := v.(type) {
-		case float64:
-			cfg.CenterLon = val
-		case int:
-			cfg.CenterLon = float64(val)
-		default:
-			return nil, fmt.Errorf("center_lon must be a number")
-		}
-	}
-
-	if v, ok := params["center_alt_m"]; ok {
-		switch val := v.(type) {
-		case float64:
-			cfg.CenterAltMeters = val
-		case int:
-			cfg.CenterAltMeters = float64(val)
-		default:
-			return nil, fmt.Errorf("center_alt_m must be a number (meters)")
-		}
-	}
-
-	if v, ok := params["grid_spacing_m"]; ok {
-		switch val := v.(type) {
-		case float64:
-			cfg.GridSpacingM = val
-		case int:
-			cfg.GridSpacingM = float64(val)
-		default:
-			return nil, fmt.Errorf("grid_spacing_m must be a number (meters)")
-		}
-	}
 	if cfg.GridSpacingM < 100 {
 		return nil, fmt.Errorf("grid_spacing_m must be at least 100 meters")
 	}
-
-	if v, ok := params["grid_jitter_m"]; ok {
-		switch val := v.(type) {
-		case float64:
-			cfg.GridJitterM = val
-		case int:
-			cfg.GridJitterM = float64(val)
-		default:
-			return nil, fmt.Errorf("grid_jitter_m must be a number (meters)")
-		}
-	}
 	if cfg.GridJitterM < 0 {
 		return nil, fmt.Errorf("grid_jitter_m must be greater than or equal to 0")
 	}
@@ -149,48 +84,23 @@ func ValidateAndParse(params map[string]interface{}) (*Config, error) {
 		return nil, fmt.Errorf("grid_jitter_m must be no more than 35%% of grid_spacing_m to keep tracks separated")
 	}
 
-	if v, ok := params["history_points"]; ok {
-		switch val := v.(type) {
-		case int:
-			cfg.HistoryPoints = val
-		case float64:
-			cfg.HistoryPoints = int(val)
-		default:
-			return nil, fmt.Errorf("history_points must be an integer")
-		}
-	}
 	if cfg.HistoryPoints < 2 {
 		return nil, fmt.Errorf("history_points must be at least 2")
 	}
-
-	if v, ok := params["history_step_seconds"]; ok {
-		switch val := v.(type) {
-		case float64:
-			cfg.HistoryStep = time.Duration(val * float64(time.Second))
-		case int:
-			cfg.HistoryStep = time.Duration(val) * time.Second
-		default:
-			return nil, fmt.Errorf("history_step_seconds must be a number (seconds)")
-		}
-	}
 	if cfg.HistoryStep < time.Second {
 		return nil, fmt.Errorf("history_step_seconds must be at least 1 second")
 	}
 
-	if v, ok := params["delete_on_exit"]; ok {
-		switch val := v.(type) {
-		case bool:
-			cfg.DeleteOnExit = val
-		case string:
-			cfg.DeleteOnExit = val == "true" || val == "1" || val == "yes"
-		default:
-			return nil, fmt.Errorf("delete_on_exit must be a boolean")
-		}
+	cfg.WorldURL = strings.TrimSpace(cfg.WorldURL)
+	if _, err := world.Open(cfg.WorldURL); err != nil {
+		return nil, fmt.Errorf("invalid world_url: %w", err)
 	}
 
-	if v, ok := params["organization_id"]; ok {
-		cfg.OrganizationID = fmt.Sprintf("%v", v)
+	cfg.WorldRole = strings.ToLower(strings.TrimSpace(cfg.WorldRole))
+	if cfg.WorldRole != world.RoleTraffic && cfg.WorldRole != world.RoleDefended {
+		return nil, fmt.Errorf("world_role must be %s or %s", world.RoleTraffic, world.RoleDefended)
 	}
+
 	if cfg.OrganizationID == "" {
 		return nil, fmt.Errorf("organization_id is required")
 	}
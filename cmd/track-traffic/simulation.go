@@ -14,6 +14,7 @@ import (
 	"github.com/picogrid/legion-simulations/pkg/logger"
 	"github.com/picogrid/legion-simulations/pkg/models"
 	"github.com/picogrid/legion-simulations/pkg/simulation"
+	"github.com/picogrid/legion-simulations/pkg/world"
 )
 
 type routePattern string
@@ -73,6 +74,9 @@ type createdTrack struct {
 type TrackTrafficSimulation struct {
 	config      *Config
 	tracks      []createdTrack
+	deleted     map[string]bool // Track IDs removed by delete-on-exit
+	world       world.World     // Shared with other simulations (nil when not configured)
+	worldHits   map[string]int  // Strikes other simulations reported, by track ID
 	startTime   time.Time
 	stopChan    chan struct{}
 	stopOnce    sync.Once
@@ -84,6 +88,7 @@ type TrackTrafficSimulation struct {
 func NewTrackTrafficSimulation() simulation.Simulation {
 	return &TrackTrafficSimulation{
 		tracks:   make([]createdTrack, 0),
+		deleted:  make(map[string]bool),
 		stopChan: make(chan struct{}),
 	}
 }
@@ -106,11 +111,19 @@ func (s *TrackTrafficSimulation) Configure(params map[string]interface{}) error
 	return nil
 }
 
-func (s *TrackTrafficSimulation) Run(ctx context.Context, legionClient *client.Legion) error {
+func (s *TrackTrafficSimulation) Run(ctx context.Context, legionClient *client.Legion) (*simulation.Result, error) {
 	if s.config == nil {
-		return fmt.Errorf("simulation not configured")
+		return nil, fmt.Errorf("simulation not configured")
 	}
 
+	outcome, err := s.run(ctx, legionClient)
+	return s.result(outcome), err
+}
+
+// run drives the tracks until the run ends and returns how it ended. Tracks are
+// deleted on the way out if configured.
+func (s *TrackTrafficSimulation) run(ctx context.Context, legionClient client.LegionAPI) (string, error) {
+
 	ctx = client.WithOrgID(ctx, s.config.OrganizationID)
 	s.startTime = time.Now().UTC()
 	defer s.cleanupTracks(legionClient)
@@ -119,16 +132,22 @@ func (s *TrackTrafficSimulation) Run(ctx context.Context, legionClient *client.L
 	logger.Infof("Starting %s with %d tracks (max concurrency %d)", s.Name(), len(specs), s.config.MaxConcurrency)
 
 	if err := s.createTracksConcurrently(ctx, legionClient, specs); err != nil {
-		return fmt.Errorf("failed to create tracks: %w", err)
+		return simulation.OutcomeStopped, fmt.Errorf("failed to create tracks: %w", err)
 	}
 
 	if err := s.seedHistory(ctx, legionClient, s.startTime); err != nil {
-		return fmt.Errorf("failed to seed historical track locations: %w", err)
+		return simulation.OutcomeStopped, fmt.Errorf("failed to seed historical track locations: %w", err)
 	}
 
 	if err := s.appendCurrentLocations(ctx, legionClient, s.startTime); err != nil {
-		return fmt.Errorf("failed to write initial track locations: %w", err)
+		return simulation.OutcomeStopped, fmt.Errorf("failed to write initial track locations: %w", err)
+	}
+
+	if err := s.startWorld(); err != nil {
+		return simulation.OutcomeStopped, fmt.Errorf("failed to join the shared world: %w", err)
 	}
+	defer s.stopWorld()
+	s.syncWorld(ctx, s.startTime)
 
 	ticker := time.NewTicker(s.config.UpdateInterval)
 	defer ticker.Stop()
@@ -139,21 +158,51 @@ func (s *TrackTrafficSimulation) Run(ctx context.Context, legionClient *client.L
 		select {
 		case <-ctx.Done():
 			if ctx.Err() == context.Canceled {
-				return nil
+				return simulation.OutcomeStopped, nil
 			}
-			return ctx.Err()
+			return simulation.OutcomeStopped, ctx.Err()
 		case <-s.stopChan:
 			logger.Info("Simulation stopped by user")
-			return nil
+			return simulation.OutcomeStopped, nil
 		case <-timeout:
 			logger.Infof("Simulation completed after %s", s.config.Duration)
-			return nil
+			return simulation.OutcomeCompleted, nil
 		case tickTime := <-ticker.C:
 			if err := s.appendCurrentLocations(ctx, legionClient, tickTime.UTC()); err != nil {
 				logger.Errorf("Failed to append track locations: %v", err)
 			}
+			s.syncWorld(ctx, tickTime.UTC())
+		}
+	}
+}
+
+// result describes the run with the tracks it left in Legion
+func (s *TrackTrafficSimulation) result(outcome string) *simulation.Result {
+	tracks := s.snapshotTracks()
+	summary := fmt.Sprintf("Moved %d tracks for %s", len(tracks), time.Since(s.startTime).Round(time.Second))
+	if outcome == simulation.OutcomeStopped {
+		summary = fmt.Sprintf("Stopped after %s with %d tracks", time.Since(s.startTime).Round(time.Second), len(tracks))
+	}
+
+	result := simulation.NewResult(outcome, summary)
+	s.mu.Lock()
+	for _, track := range tracks {
+		if !s.deleted[track.ID] {
+			result.AddEntity(track.ID, track.Spec.Name, track.Spec.Type)
 		}
 	}
+	s.mu.Unlock()
+	result.Stats["tracks"] = float64(len(tracks))
+	if s.world != nil {
+		var hits int
+		s.mu.Lock()
+		for _, n := range s.worldHits {
+			hits += n
+		}
+		s.mu.Unlock()
+		result.Stats["world_hits"] = float64(hits)
+	}
+	return result
 }
 
 func (s *TrackTrafficSimulation) Stop() error {
@@ -163,7 +212,7 @@ func (s *TrackTrafficSimulation) Stop() error {
 	return nil
 }
 
-func (s *TrackTrafficSimulation) createTrack(ctx context.Context, legionClient *client.Legion, spec trafficTrackSpec) (string, error) {
+func (s *TrackTrafficSimulation) createTrack(ctx context.Context, legionClient client.LegionAPI, spec trafficTrackSpec) (string, error) {
 	orgUUID, err := uuid.Parse(s.config.OrganizationID)
 	if err != nil {
 		return "", fmt.Errorf("invalid organization ID: %w", err)
@@ -198,7 +247,7 @@ func (s *TrackTrafficSimulation) createTrack(ctx context.Context, legionClient *
 	return created.ID.String(), nil
 }
 
-func (s *TrackTrafficSimulation) createTracksConcurrently(ctx context.Context, legionClient *client.Legion, specs []trafficTrackSpec) error {
+func (s *TrackTrafficSimulation) createTracksConcurrently(ctx context.Context, legionClient client.LegionAPI, specs []trafficTrackSpec) error {
 	results := make([]createdTrack, len(specs))
 
 	err := s.runBounded(ctx, len(specs), func(index int) error {
@@ -220,7 +269,7 @@ func (s *TrackTrafficSimulation) createTracksConcurrently(ctx context.Context, l
 	return nil
 }
 
-func (s *TrackTrafficSimulation) seedHistory(ctx context.Context, legionClient *client.Legion, now time.Time) error {
+func (s *TrackTrafficSimulation) seedHistory(ctx context.Context, legionClient client.LegionAPI, now time.Time) error {
 	tracks := s.snapshotTracks()
 
 	return s.runBounded(ctx, len(tracks), func(index int) error {
@@ -236,7 +285,7 @@ func (s *TrackTrafficSimulation) seedHistory(ctx context.Context, legionClient *
 	})
 }
 
-func (s *TrackTrafficSimulation) appendCurrentLocations(ctx context.Context, legionClient *client.Legion, recordedAt time.Time) error {
+func (s *TrackTrafficSimulation) appendCurrentLocations(ctx context.Context, legionClient client.LegionAPI, recordedAt time.Time) error {
 	tracks := s.snapshotTracks()
 	return s.runBounded(ctx, len(tracks), func(index int) error {
 		track := tracks[index]
@@ -248,7 +297,7 @@ func (s *TrackTrafficSimulation) appendCurrentLocations(ctx context.Context, leg
 	})
 }
 
-func (s *TrackTrafficSimulation) cleanupTracks(legionClient *client.Legion) {
+func (s *TrackTrafficSimulation) cleanupTracks(legionClient client.LegionAPI) {
 	if !s.config.DeleteOnExit {
 		return
 	}
@@ -266,6 +315,9 @@ func (s *TrackTrafficSimulation) cleanupTracks(legionClient *client.Legion) {
 				return nil
 			}
 			logger.Infof("Deleted track %s (%s)", track.Spec.Name, track.ID)
+			s.mu.Lock()
+			s.deleted[track.ID] = true
+			s.mu.Unlock()
 			return nil
 		}); err != nil {
 			logger.Warnf("Cleanup encountered an error: %v", err)
@@ -283,17 +335,7 @@ func (s *TrackTrafficSimulation) snapshotTracks() []createdTrack {
 }
 
 func (s *TrackTrafficSimulation) buildTrackLocation(spec trafficTrackSpec, recordedAt time.Time) *models.CreateEntityLocationRequest {
-	elapsed := recordedAt.Sub(s.startTime).Seconds()
-	offsetNorth, offsetEast, altOffset := routeOffsets(spec, elapsed)
-	lat, lon := offsetLatLon(
-		s.config.CenterLat,
-		s.config.CenterLon,
-		spec.AnchorNorthM+offsetNorth,
-		spec.AnchorEastM+offsetEast,
-	)
-	alt := s.config.CenterAltMeters + spec.BaseAltitudeM + altOffset
-
-	x, y, z := latLonAltToECEF(lat, lon, alt)
+	x, y, z := latLonAltToECEF(s.trackLatLonAlt(spec, recordedAt))
 	pointType := "Point"
 
 	return &models.CreateEntityLocationRequest{
@@ -306,6 +348,18 @@ func (s *TrackTrafficSimulation) buildTrackLocation(spec trafficTrackSpec, recor
 	}
 }
 
+// trackLatLonAlt returns where a track's route puts it at the given time
+func (s *TrackTrafficSimulation) trackLatLonAlt(spec trafficTrackSpec, at time.Time) (lat, lon, alt float64) {
+	offsetNorth, offsetEast, altOffset := routeOffsets(spec, at.Sub(s.startTime).Seconds())
+	lat, lon = offsetLatLon(
+		s.config.CenterLat,
+		s.config.CenterLon,
+		spec.AnchorNorthM+offsetNorth,
+		spec.AnchorEastM+offsetEast,
+	)
+	return lat, lon, s.config.CenterAltMeters + spec.BaseAltitudeM + altOffset
+}
+
 func (s *TrackTrafficSimulation) defaultTrackSpecs() []trafficTrackSpec {
 	templates := s.baseTrackTemplates()
 	slots := jitteredGridSlots(s.config.TotalTracks, s.config.GridSpacingM, s.config.GridJitterM)
@@ -0,0 +1,73 @@
+package tracktraffic
+
+import (
+	"context"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/world"
+)
+
+// startWorld joins the shared world when configured
+func (s *TrackTrafficSimulation) startWorld() error {
+	if s.config.WorldURL == "" {
+		return nil
+	}
+	shared, err := world.Open(s.config.WorldURL)
+	if err != nil {
+		return err
+	}
+	s.world = shared
+	s.worldHits = make(map[string]int)
+	logger.Infof("🌍 Sharing %d tracks as %s assets at %s", len(s.snapshotTracks()), s.config.WorldRole, s.config.WorldURL)
+	return nil
+}
+
+// stopWorld withdraws the tracks so other simulations stop reacting to them
+func (s *TrackTrafficSimulation) stopWorld() {
+	if s.world == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.world.Withdraw(ctx, s.Name()); err != nil {
+		logger.Warnf("Failed to withdraw from the shared world: %v", err)
+	}
+}
+
+// syncWorld publishes every track at its current position and reports strikes other
+// simulations recorded against them since the last sync
+func (s *TrackTrafficSimulation) syncWorld(ctx context.Context, at time.Time) {
+	if s.world == nil {
+		return
+	}
+
+	tracks := s.snapshotTracks()
+	assets := make([]world.Asset, 0, len(tracks))
+	for _, track := range tracks {
+		lat, lon, alt := s.trackLatLonAlt(track.Spec, at)
+		assets = append(assets, world.Asset{
+			ID: track.ID, Name: track.Spec.Name, Owner: s.Name(), Role: s.config.WorldRole,
+			Lat: lat, Lon: lon, Alt: alt,
+		})
+	}
+	if err := s.world.Publish(ctx, assets...); err != nil {
+		logger.Warnf("Failed to publish to the shared world: %v", err)
+		return
+	}
+
+	shared, err := s.world.Assets(ctx)
+	if err != nil {
+		logger.Warnf("Failed to read the shared world: %v", err)
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, asset := range shared {
+		if asset.Owner != s.Name() || asset.Hits <= s.worldHits[asset.ID] {
+			continue
+		}
+		logger.Warnf("💥 %s struck by %s (%d hits)", asset.Name, asset.LastHitBy, asset.Hits)
+		s.worldHits[asset.ID] = asset.Hits
+	}
+}
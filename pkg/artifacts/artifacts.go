@@ -0,0 +1,203 @@
+// Package artifacts publishes simulation outputs (AARs, replays, run databases) to
+// object storage so ephemeral CI and Kubernetes runs don't lose them.
+//
+// Destinations are configured with a URL:
+//
+//	s3://bucket/prefix       AWS S3 or S3-compatible (AWS_ACCESS_KEY_ID, AWS_SECRET_ACCESS_KEY,
+//	                         AWS_SESSION_TOKEN, AWS_REGION, AWS_ENDPOINT_URL)
+//	gs://bucket/prefix       Google Cloud Storage (GOOGLE_OAUTH_ACCESS_TOKEN)
+//	azblob://container/prefix Azure Blob Storage (AZURE_STORAGE_ACCOUNT, AZURE_STORAGE_SAS_TOKEN)
+//	file:///path             Local directory
+package artifacts
+
+import (
+	"context"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// EnvArtifactURL is the environment variable consulted when no destination is configured
+const EnvArtifactURL = "LEGION_ARTIFACT_URL"
+
+// Store writes objects to a storage backend
+type Store interface {
+	Put(ctx context.Context, key string, body []byte, contentType string) error
+	// Location returns a human-readable location for a key
+	Location(key string) string
+}
+
+// NewStore creates a store from a destination URL
+func NewStore(rawURL string) (Store, string, error) {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, "", fmt.Errorf("invalid artifact URL %q: %w", rawURL, err)
+	}
+
+	prefix := strings.Trim(u.Path, "/")
+	httpClient := &http.Client{Timeout: 60 * time.Second}
+
+	switch u.Scheme {
+	case "s3":
+		store, err := newS3Store(u.Host, httpClient)
+		return store, prefix, err
+	case "gs":
+		store, err := newGCSStore(u.Host, httpClient)
+		return store, prefix, err
+	case "azblob":
+		store, err := newAzureStore(u.Host, httpClient)
+		return store, prefix, err
+	case "file":
+		return &fileStore{root: filepath.FromSlash(path.Join(u.Host, u.Path))}, "", nil
+	default:
+		return nil, "", fmt.Errorf("unsupported artifact URL scheme %q (supported: s3, gs, azblob, file)", u.Scheme)
+	}
+}
+
+// Publisher uploads a run's artifacts under a deterministic key layout:
+//
+//	<prefix>/<org>/<simulation>/<YYYY-MM-DD>/<run-id>/<file>
+type Publisher struct {
+	store   Store
+	prefix  string
+	org     string
+	sim     string
+	runID   string
+	started time.Time
+}
+
+// NewPublisher creates a publisher for a single run. An empty destination falls back to
+// LEGION_ARTIFACT_URL; if neither is set, a nil publisher is returned.
+func NewPublisher(destination, orgID, simName, runID string, started time.Time) (*Publisher, error) {
+	if destination == "" {
+		destination = os.Getenv(EnvArtifactURL)
+	}
+	if destination == "" {
+		return nil, nil
+	}
+
+	store, prefix, err := NewStore(destination)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Publisher{
+		store:   store,
+		prefix:  prefix,
+		org:     orgID,
+		sim:     simName,
+		runID:   runID,
+		started: started,
+	}, nil
+}
+
+// Key returns the object key for a file name
+func (p *Publisher) Key(name string) string {
+	parts := []string{
+		p.prefix,
+		keySegment(p.org, "no-org"),
+		keySegment(p.sim, "simulation"),
+		p.started.UTC().Format("2006-01-02"),
+		keySegment(p.runID, "run"),
+		name,
+	}
+	if p.prefix == "" {
+		parts = parts[1:]
+	}
+	return strings.Join(parts, "/")
+}
+
+// PublishFile uploads a local file, keyed by its base name
+func (p *Publisher) PublishFile(ctx context.Context, localPath string) (string, error) {
+	data, err := os.ReadFile(localPath)
+	if err != nil {
+		return "", fmt.Errorf("failed to read artifact: %w", err)
+	}
+
+	name := filepath.Base(localPath)
+	return p.Publish(ctx, name, data)
+}
+
+// Publish uploads an in-memory artifact
+func (p *Publisher) Publish(ctx context.Context, name string, data []byte) (string, error) {
+	key := p.Key(name)
+	if err := p.store.Put(ctx, key, data, contentType(name)); err != nil {
+		return "", fmt.Errorf("failed to upload %s: %w", name, err)
+	}
+
+	location := p.store.Location(key)
+	logger.Debugf("Uploaded artifact %s (%d bytes) to %s", name, len(data), location)
+	return location, nil
+}
+
+// PublishAll uploads every file, continuing past failures. Returns the uploaded locations
+// and the first error encountered.
+func (p *Publisher) PublishAll(ctx context.Context, localPaths []string) ([]string, error) {
+	var locations []string
+	var firstErr error
+	for _, localPath := range localPaths {
+		location, err := p.PublishFile(ctx, localPath)
+		if err != nil {
+			if firstErr == nil {
+				firstErr = err
+			}
+			logger.Warnf("Artifact upload failed: %v", err)
+			continue
+		}
+		locations = append(locations, location)
+	}
+	return locations, firstErr
+}
+
+var keySegmentInvalid = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// keySegment makes a value safe for use as a single key path segment
+func keySegment(value, fallback string) string {
+	segment := strings.Trim(keySegmentInvalid.ReplaceAllString(value, "-"), "-.")
+	if segment == "" {
+		return fallback
+	}
+	return segment
+}
+
+// contentType guesses a content type from the file extension
+func contentType(name string) string {
+	switch strings.ToLower(filepath.Ext(name)) {
+	case ".json":
+		return "application/json"
+	case ".md", ".markdown":
+		return "text/markdown; charset=utf-8"
+	case ".jsonl", ".ndjson":
+		return "application/x-ndjson"
+	}
+	if t := mime.TypeByExtension(filepath.Ext(name)); t != "" {
+		return t
+	}
+	return "application/octet-stream"
+}
+
+// fileStore writes objects beneath a local directory
+type fileStore struct {
+	root string
+}
+
+func (f *fileStore) Put(_ context.Context, key string, body []byte, _ string) error {
+	dest := filepath.Join(f.root, filepath.FromSlash(key))
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return fmt.Errorf("failed to create artifact directory: %w", err)
+	}
+	return os.WriteFile(dest, body, 0644)
+}
+
+func (f *fileStore) Location(key string) string {
+	return filepath.Join(f.root, filepath.FromSlash(key))
+}
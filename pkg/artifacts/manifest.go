@@ -0,0 +1,214 @@
+package artifacts
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ManifestVersion is the manifest format written by BuildManifest
+const ManifestVersion = 1
+
+// Manifest lists a run's output files with their SHA-256 digests. Signed, it makes the
+// bundle tamper-evident: editing, removing or swapping any listed file fails verification.
+type Manifest struct {
+	Version      int            `json:"version"`
+	RunID        string         `json:"run_id"`
+	Simulation   string         `json:"simulation"`
+	Organization string         `json:"organization_id,omitempty"`
+	CreatedAt    time.Time      `json:"created_at"`
+	KeyID        string         `json:"key_id,omitempty"` // Of the key that signed it
+	Files        []ManifestFile `json:"files"`
+}
+
+// ManifestFile is one file of the bundle, named by its base name as it is published
+type ManifestFile struct {
+	Name   string `json:"name"`
+	Size   int64  `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// HashFile returns a file's hex SHA-256 digest and size
+func HashFile(path string) (string, int64, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", 0, err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	size, err := io.Copy(h, f)
+	if err != nil {
+		return "", 0, err
+	}
+	return hex.EncodeToString(h.Sum(nil)), size, nil
+}
+
+// BuildManifest hashes each file into a manifest for the run
+func BuildManifest(runID, simName, orgID string, paths []string) (*Manifest, error) {
+	m := &Manifest{
+		Version:      ManifestVersion,
+		RunID:        runID,
+		Simulation:   simName,
+		Organization: orgID,
+		CreatedAt:    time.Now().UTC(),
+	}
+	seen := make(map[string]bool)
+	for _, path := range paths {
+		name := filepath.Base(path)
+		if seen[name] {
+			return nil, fmt.Errorf("two artifacts are named %s", name)
+		}
+		seen[name] = true
+
+		digest, size, err := HashFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to hash artifact: %w", err)
+		}
+		m.Files = append(m.Files, ManifestFile{Name: name, Size: size, SHA256: digest})
+	}
+	sort.Slice(m.Files, func(i, j int) bool { return m.Files[i].Name < m.Files[j].Name })
+	return m, nil
+}
+
+// LoadSigningKey reads an Ed25519 private key from a PKCS#8 PEM file, as written by
+// `openssl genpkey -algorithm ed25519`
+func LoadSigningKey(path string) (ed25519.PrivateKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKCS8PrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid signing key %s: %w", path, err)
+	}
+	private, ok := key.(ed25519.PrivateKey)
+	if !ok {
+		return nil, fmt.Errorf("signing key %s is %T, not Ed25519", path, key)
+	}
+	return private, nil
+}
+
+// LoadPublicKey reads an Ed25519 public key from a PKIX PEM file, as written by
+// `openssl pkey -pubout`
+func LoadPublicKey(path string) (ed25519.PublicKey, error) {
+	block, err := readPEM(path)
+	if err != nil {
+		return nil, err
+	}
+	key, err := x509.ParsePKIXPublicKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid public key %s: %w", path, err)
+	}
+	public, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("public key %s is %T, not Ed25519", path, key)
+	}
+	return public, nil
+}
+
+func readPEM(path string) (*pem.Block, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("%s is not a PEM file", path)
+	}
+	return block, nil
+}
+
+// KeyID is a short fingerprint of a public key, recorded in manifests it signs
+func KeyID(public ed25519.PublicKey) string {
+	sum := sha256.Sum256(public)
+	return hex.EncodeToString(sum[:8])
+}
+
+// SignManifest encodes the manifest and signs the encoded bytes. Returns the manifest as
+// written and its detached signature, base64 encoded.
+func SignManifest(m *Manifest, key ed25519.PrivateKey) (data, signature []byte, err error) {
+	m.KeyID = KeyID(key.Public().(ed25519.PublicKey))
+	data, err = json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to encode manifest: %w", err)
+	}
+	data = append(data, '\n')
+	sig := ed25519.Sign(key, data)
+	return data, []byte(base64.StdEncoding.EncodeToString(sig) + "\n"), nil
+}
+
+// FileCheck is the verification result of one manifest file
+type FileCheck struct {
+	ManifestFile
+	Status string `json:"status"` // ok, modified or missing
+}
+
+// File check statuses
+const (
+	FileOK       = "ok"
+	FileModified = "modified"
+	FileMissing  = "missing"
+)
+
+// ErrTampered is returned when the signature holds but files no longer match it
+var ErrTampered = errors.New("artifacts do not match the signed manifest")
+
+// VerifyBundle checks a manifest's signature against the public key, then re-hashes
+// each file it lists from dir. The manifest is returned whenever its signature is good,
+// along with each file's result; ErrTampered reports any file that fails.
+func VerifyBundle(manifestPath, signaturePath, dir string, public ed25519.PublicKey) (*Manifest, []FileCheck, error) {
+	data, err := os.ReadFile(manifestPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read manifest: %w", err)
+	}
+	encoded, err := os.ReadFile(signaturePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to read signature: %w", err)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(encoded)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid signature encoding: %w", err)
+	}
+	if !ed25519.Verify(public, data, sig) {
+		return nil, nil, fmt.Errorf("manifest signature does not verify with key %s", KeyID(public))
+	}
+
+	var m Manifest
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, nil, fmt.Errorf("invalid manifest: %w", err)
+	}
+
+	checks := make([]FileCheck, 0, len(m.Files))
+	failed := false
+	for _, file := range m.Files {
+		check := FileCheck{ManifestFile: file, Status: FileOK}
+		digest, size, err := HashFile(filepath.Join(dir, filepath.Base(file.Name)))
+		switch {
+		case errors.Is(err, os.ErrNotExist):
+			check.Status = FileMissing
+		case err != nil:
+			return &m, checks, fmt.Errorf("failed to hash %s: %w", file.Name, err)
+		case digest != file.SHA256 || size != file.Size:
+			check.Status = FileModified
+		}
+		failed = failed || check.Status != FileOK
+		checks = append(checks, check)
+	}
+	if failed {
+		return &m, checks, ErrTampered
+	}
+	return &m, checks, nil
+}
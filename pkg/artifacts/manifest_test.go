@@ -0,0 +1,82 @@
+package artifacts
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestSignAndVerifyBundle(t *testing.T) {
+	dir := t.TempDir()
+	write := func(name, body string) string {
+		path := filepath.Join(dir, name)
+		if err := os.WriteFile(path, []byte(body), 0644); err != nil {
+			t.Fatal(err)
+		}
+		return path
+	}
+	aar := write("AAR.md", "# After Action Report\n")
+	timeline := write("Timeline.json", `{"events":[]}`)
+
+	// Keys round-trip through the PEM files openssl writes
+	public, private, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	der, _ := x509.MarshalPKCS8PrivateKey(private)
+	keyPath := write("signing.key", string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: der})))
+	der, _ = x509.MarshalPKIXPublicKey(public)
+	pubPath := write("signing.pub", string(pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})))
+	key, err := LoadSigningKey(keyPath)
+	if err != nil {
+		t.Fatalf("LoadSigningKey: %v", err)
+	}
+	pub, err := LoadPublicKey(pubPath)
+	if err != nil {
+		t.Fatalf("LoadPublicKey: %v", err)
+	}
+
+	m, err := BuildManifest("run-42", "Drone Swarm Combat", "org-123", []string{timeline, aar})
+	if err != nil {
+		t.Fatalf("BuildManifest: %v", err)
+	}
+	data, sig, err := SignManifest(m, key)
+	if err != nil {
+		t.Fatalf("SignManifest: %v", err)
+	}
+	manifestPath := write("Manifest.json", string(data))
+	write("Manifest.json.sig", string(sig))
+
+	got, checks, err := VerifyBundle(manifestPath, manifestPath+".sig", dir, pub)
+	if err != nil {
+		t.Fatalf("VerifyBundle: %v", err)
+	}
+	if got.KeyID != KeyID(pub) || len(checks) != 2 || checks[0].Name != "AAR.md" {
+		t.Fatalf("unexpected manifest %+v with checks %+v", got, checks)
+	}
+
+	// An edited report and a deleted attachment are both caught
+	write("AAR.md", "# After Action Report\nOutcome: SUCCESS\n")
+	if err := os.Remove(timeline); err != nil {
+		t.Fatal(err)
+	}
+	_, checks, err = VerifyBundle(manifestPath, manifestPath+".sig", dir, pub)
+	if !errors.Is(err, ErrTampered) {
+		t.Fatalf("expected ErrTampered, got %v", err)
+	}
+	if checks[0].Status != FileModified || checks[1].Status != FileMissing {
+		t.Errorf("expected modified and missing, got %+v", checks)
+	}
+
+	// So is an edited manifest
+	write("Manifest.json", strings.Replace(string(data), "run-42", "run-43", 1))
+	if _, _, err := VerifyBundle(manifestPath, manifestPath+".sig", dir, pub); err == nil || errors.Is(err, ErrTampered) {
+		t.Errorf("expected a signature failure, got %v", err)
+	}
+}
@@ -0,0 +1,268 @@
+package artifacts
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"sort"
+	"strings"
+	"time"
+)
+
+// s3Store uploads objects with SigV4-signed PUT requests
+type s3Store struct {
+	bucket       string
+	region       string
+	endpoint     string // Custom endpoint for S3-compatible stores (path-style addressing)
+	accessKey    string
+	secretKey    string
+	sessionToken string
+	httpClient   *http.Client
+}
+
+func newS3Store(bucket string, httpClient *http.Client) (*s3Store, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("s3 artifact URL is missing a bucket")
+	}
+
+	store := &s3Store{
+		bucket:       bucket,
+		region:       os.Getenv("AWS_REGION"),
+		endpoint:     strings.TrimRight(os.Getenv("AWS_ENDPOINT_URL"), "/"),
+		accessKey:    os.Getenv("AWS_ACCESS_KEY_ID"),
+		secretKey:    os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		sessionToken: os.Getenv("AWS_SESSION_TOKEN"),
+		httpClient:   httpClient,
+	}
+	if store.region == "" {
+		store.region = os.Getenv("AWS_DEFAULT_REGION")
+	}
+	if store.region == "" {
+		store.region = "us-east-1"
+	}
+	if store.accessKey == "" || store.secretKey == "" {
+		return nil, fmt.Errorf("s3 uploads require AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY")
+	}
+	return store, nil
+}
+
+func (s *s3Store) objectURL(key string) string {
+	if s.endpoint != "" {
+		return fmt.Sprintf("%s/%s/%s", s.endpoint, s.bucket, escapeKey(key))
+	}
+	return fmt.Sprintf("https://%s.s3.%s.amazonaws.com/%s", s.bucket, s.region, escapeKey(key))
+}
+
+func (s *s3Store) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, s.objectURL(key), bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	if s.sessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", s.sessionToken)
+	}
+
+	signV4(req, sha256Hex(body), s.accessKey, s.secretKey, s.region, "s3", time.Now())
+	return doUpload(s.httpClient, req)
+}
+
+func (s *s3Store) Location(key string) string {
+	return fmt.Sprintf("s3://%s/%s", s.bucket, key)
+}
+
+// signV4 adds AWS Signature Version 4 headers to a request. Every header already set on
+// the request is signed, along with host, x-amz-date and x-amz-content-sha256.
+func signV4(req *http.Request, payloadHash, accessKey, secretKey, region, service string, now time.Time) {
+	amzDate := now.UTC().Format("20060102T150405Z")
+	date := amzDate[:8]
+
+	req.Header.Set("X-Amz-Date", amzDate)
+	req.Header.Set("X-Amz-Content-Sha256", payloadHash)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	canonicalURI := req.URL.EscapedPath()
+	if canonicalURI == "" {
+		canonicalURI = "/"
+	}
+
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		canonicalURI,
+		canonicalQuery(req.URL.Query()),
+		canonicalHeaders.String(),
+		signedHeaders,
+		payloadHash,
+	}, "\n")
+
+	scope := fmt.Sprintf("%s/%s/%s/aws4_request", date, region, service)
+	stringToSign := strings.Join([]string{
+		"AWS4-HMAC-SHA256",
+		amzDate,
+		scope,
+		sha256Hex([]byte(canonicalRequest)),
+	}, "\n")
+
+	signingKey := hmacSHA256([]byte("AWS4"+secretKey), date)
+	signingKey = hmacSHA256(signingKey, region)
+	signingKey = hmacSHA256(signingKey, service)
+	signingKey = hmacSHA256(signingKey, "aws4_request")
+	signature := hex.EncodeToString(hmacSHA256(signingKey, stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		accessKey, scope, signedHeaders, signature))
+}
+
+// canonicalQuery encodes query parameters sorted by key, as SigV4 requires
+func canonicalQuery(values url.Values) string {
+	keys := make([]string, 0, len(values))
+	for key := range values {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var parts []string
+	for _, key := range keys {
+		vals := append([]string(nil), values[key]...)
+		sort.Strings(vals)
+		for _, v := range vals {
+			parts = append(parts, awsEscape(key)+"="+awsEscape(v))
+		}
+	}
+	return strings.Join(parts, "&")
+}
+
+// awsEscape percent-encodes everything except RFC 3986 unreserved characters
+func awsEscape(s string) string {
+	return strings.ReplaceAll(url.QueryEscape(s), "+", "%20")
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// gcsStore uploads objects with the GCS JSON API using an OAuth access token
+type gcsStore struct {
+	bucket     string
+	token      string
+	httpClient *http.Client
+}
+
+func newGCSStore(bucket string, httpClient *http.Client) (*gcsStore, error) {
+	if bucket == "" {
+		return nil, fmt.Errorf("gs artifact URL is missing a bucket")
+	}
+	token := os.Getenv("GOOGLE_OAUTH_ACCESS_TOKEN")
+	if token == "" {
+		return nil, fmt.Errorf("gcs uploads require GOOGLE_OAUTH_ACCESS_TOKEN")
+	}
+	return &gcsStore{bucket: bucket, token: token, httpClient: httpClient}, nil
+}
+
+func (g *gcsStore) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	endpoint := fmt.Sprintf("https://storage.googleapis.com/upload/storage/v1/b/%s/o?uploadType=media&name=%s",
+		url.PathEscape(g.bucket), url.QueryEscape(key))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+g.token)
+	req.Header.Set("Content-Type", contentType)
+	return doUpload(g.httpClient, req)
+}
+
+func (g *gcsStore) Location(key string) string {
+	return fmt.Sprintf("gs://%s/%s", g.bucket, key)
+}
+
+// azureStore uploads block blobs authorized with a SAS token
+type azureStore struct {
+	account    string
+	container  string
+	sasToken   string
+	httpClient *http.Client
+}
+
+func newAzureStore(container string, httpClient *http.Client) (*azureStore, error) {
+	if container == "" {
+		return nil, fmt.Errorf("azblob artifact URL is missing a container")
+	}
+	account := os.Getenv("AZURE_STORAGE_ACCOUNT")
+	sasToken := strings.TrimPrefix(os.Getenv("AZURE_STORAGE_SAS_TOKEN"), "?")
+	if account == "" || sasToken == "" {
+		return nil, fmt.Errorf("azure uploads require AZURE_STORAGE_ACCOUNT and AZURE_STORAGE_SAS_TOKEN")
+	}
+	return &azureStore{account: account, container: container, sasToken: sasToken, httpClient: httpClient}, nil
+}
+
+func (a *azureStore) Put(ctx context.Context, key string, body []byte, contentType string) error {
+	endpoint := fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s?%s",
+		a.account, a.container, escapeKey(key), a.sasToken)
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("x-ms-blob-type", "BlockBlob")
+	req.Header.Set("x-ms-version", "2021-08-06")
+	req.Header.Set("Content-Type", contentType)
+	return doUpload(a.httpClient, req)
+}
+
+func (a *azureStore) Location(key string) string {
+	return fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", a.account, a.container, key)
+}
+
+// escapeKey percent-encodes each segment of an object key
+func escapeKey(key string) string {
+	segments := strings.Split(key, "/")
+	for i, segment := range segments {
+		segments[i] = awsEscape(segment)
+	}
+	return strings.Join(segments, "/")
+}
+
+// doUpload sends an upload request and converts non-2xx responses to errors
+func doUpload(httpClient *http.Client, req *http.Request) error {
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("upload request failed: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("upload failed with status %d: %s", resp.StatusCode, strings.TrimSpace(string(body)))
+	}
+	return nil
+}
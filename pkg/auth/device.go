@@ -0,0 +1,154 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// DeviceAuthorization is the response from the Keycloak device authorization endpoint
+// (RFC 8628). The user opens VerificationURIComplete, or VerificationURI and types
+// UserCode, while the CLI polls for the token.
+type DeviceAuthorization struct {
+	DeviceCode              string `json:"device_code"`
+	UserCode                string `json:"user_code"`
+	VerificationURI         string `json:"verification_uri"`
+	VerificationURIComplete string `json:"verification_uri_complete"`
+	ExpiresIn               int    `json:"expires_in"`
+	Interval                int    `json:"interval"`
+}
+
+// ErrDeviceCodeExpired is returned when the user does not approve the login in time
+var ErrDeviceCodeExpired = errors.New("device code expired before the login was approved")
+
+// ErrAccessDenied is returned when the user denies the login
+var ErrAccessDenied = errors.New("login was denied")
+
+// oauthError is the error body returned by the Keycloak token endpoints
+type oauthError struct {
+	Error            string `json:"error"`
+	ErrorDescription string `json:"error_description"`
+}
+
+// StartDeviceAuthorization requests a device and user code for the device flow
+func (k *KeycloakClient) StartDeviceAuthorization(ctx context.Context) (*DeviceAuthorization, error) {
+	deviceURL := fmt.Sprintf("%s/realms/%s/protocol/openid-connect/auth/device", k.config.BaseURL, k.config.Realm)
+
+	data := url.Values{}
+	data.Set("client_id", k.config.ClientID)
+	data.Set("scope", "openid offline_access")
+
+	body, status, err := k.postForm(ctx, deviceURL, data)
+	if err != nil {
+		return nil, fmt.Errorf("device authorization request failed: %w", err)
+	}
+	if status != http.StatusOK {
+		var errorResp oauthError
+		_ = json.Unmarshal(body, &errorResp)
+		if errorResp.ErrorDescription == "" {
+			errorResp.ErrorDescription = fmt.Sprintf("status %d", status)
+		}
+		return nil, fmt.Errorf("device authorization failed: %s", errorResp.ErrorDescription)
+	}
+
+	var device DeviceAuthorization
+	if err := json.Unmarshal(body, &device); err != nil {
+		return nil, fmt.Errorf("failed to parse device authorization response: %w", err)
+	}
+	if device.DeviceCode == "" || device.VerificationURI == "" {
+		return nil, fmt.Errorf("device authorization response is missing the device code or verification URI")
+	}
+	return &device, nil
+}
+
+// PollDeviceToken polls the token endpoint until the user approves or denies the login,
+// the device code expires, or ctx is done
+func (k *KeycloakClient) PollDeviceToken(ctx context.Context, device *DeviceAuthorization) (*TokenResponse, error) {
+	tokenURL := fmt.Sprintf("%s/realms/%s/protocol/openid-connect/token", k.config.BaseURL, k.config.Realm)
+
+	interval := time.Duration(device.Interval) * time.Second
+	if interval <= 0 {
+		interval = 5 * time.Second
+	}
+	deadline := time.Now().Add(time.Duration(device.ExpiresIn) * time.Second)
+
+	data := url.Values{}
+	data.Set("grant_type", "urn:ietf:params:oauth:grant-type:device_code")
+	data.Set("client_id", k.config.ClientID)
+	data.Set("device_code", device.DeviceCode)
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(interval):
+		}
+		if device.ExpiresIn > 0 && time.Now().After(deadline) {
+			return nil, ErrDeviceCodeExpired
+		}
+
+		body, status, err := k.postForm(ctx, tokenURL, data)
+		if err != nil {
+			// A dropped request is retried on the next poll
+			logger.Debugf("Device token poll failed: %v", err)
+			continue
+		}
+		if status == http.StatusOK {
+			var tokenResp TokenResponse
+			if err := json.Unmarshal(body, &tokenResp); err != nil {
+				return nil, fmt.Errorf("failed to parse token response: %w", err)
+			}
+			return &tokenResp, nil
+		}
+
+		var errorResp oauthError
+		if err := json.Unmarshal(body, &errorResp); err != nil {
+			return nil, fmt.Errorf("device token request failed: status %d", status)
+		}
+		switch errorResp.Error {
+		case "authorization_pending":
+		case "slow_down":
+			interval += 5 * time.Second
+		case "expired_token":
+			return nil, ErrDeviceCodeExpired
+		case "access_denied":
+			return nil, ErrAccessDenied
+		default:
+			return nil, fmt.Errorf("device token request failed: %s %s", errorResp.Error, errorResp.ErrorDescription)
+		}
+	}
+}
+
+// postForm posts a form to a Keycloak endpoint and returns the body and status code
+func (k *KeycloakClient) postForm(ctx context.Context, endpoint string, data url.Values) ([]byte, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, strings.NewReader(data.Encode()))
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+
+	resp, err := k.httpClient.Do(req)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer func(Body io.ReadCloser) {
+		err := Body.Close()
+		if err != nil {
+			logger.Errorf("failed to close response body: %v", err)
+		}
+	}(resp.Body)
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to read response: %w", err)
+	}
+	return body, resp.StatusCode, nil
+}
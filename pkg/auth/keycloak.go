@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 
+	"github.com/picogrid/legion-simulations/pkg/client"
 	"github.com/picogrid/legion-simulations/pkg/logger"
 )
 
@@ -95,7 +96,7 @@ func (k *KeycloakClient) Authenticate(ctx context.Context, username, password st
 		}
 
 		if resp.StatusCode == http.StatusUnauthorized {
-			return nil, fmt.Errorf("invalid credentials")
+			return nil, fmt.Errorf("invalid credentials: %w", client.ErrUnauthorized)
 		}
 		return nil, fmt.Errorf("authentication failed: %s", errorResp.ErrorDescription)
 	}
@@ -149,6 +150,10 @@ func (k *KeycloakClient) RefreshToken(ctx context.Context, refreshToken string)
 		if err != nil {
 			return nil, err
 		}
+		// invalid_grant is OAuth's code for a refresh token that expired or was revoked
+		if errorResp.Error == "invalid_grant" {
+			return nil, fmt.Errorf("token refresh failed: %s: %w", errorResp.ErrorDescription, client.ErrUnauthorized)
+		}
 		return nil, fmt.Errorf("token refresh failed: %s", errorResp.ErrorDescription)
 	}
 
@@ -191,6 +191,38 @@ func GetAuthConfigFromLegion(ctx context.Context, legionURL string) (AuthConfig,
 
 // AuthenticateUserWithLegion authenticates a user using the auth URL from Legion
 func AuthenticateUserWithLegion(ctx context.Context, legionURL string) (*TokenManager, error) {
+	return AuthenticateUser(ctx, ResolveAuthConfig(ctx, legionURL))
+}
+
+// AuthenticateDeviceWithLegion logs in with the OAuth device flow against the Keycloak
+// realm Legion uses. show is called with the code and URL for the user to open, and
+// the login finishes once they approve it in a browser.
+func AuthenticateDeviceWithLegion(ctx context.Context, legionURL, clientID string, show func(*DeviceAuthorization)) (*TokenManager, error) {
+	config := ResolveAuthConfig(ctx, legionURL)
+	if clientID != "" {
+		config.ClientID = clientID
+	}
+
+	keycloakClient := NewKeycloakClient(KeycloakConfig{
+		BaseURL:  config.KeycloakURL,
+		Realm:    config.Realm,
+		ClientID: config.ClientID,
+	})
+	device, err := keycloakClient.StartDeviceAuthorization(ctx)
+	if err != nil {
+		return nil, err
+	}
+	show(device)
+
+	tokenResp, err := keycloakClient.PollDeviceToken(ctx, device)
+	if err != nil {
+		return nil, err
+	}
+	return NewTokenManager(keycloakClient, tokenResp), nil
+}
+
+// ResolveAuthConfig fetches the auth config from Legion, falling back to the defaults
+func ResolveAuthConfig(ctx context.Context, legionURL string) AuthConfig {
 	config, err := GetAuthConfigFromLegion(ctx, legionURL)
 	if err != nil {
 		fmt.Println("⚠️  Could not fetch auth config from Legion, using defaults")
@@ -203,5 +235,5 @@ func AuthenticateUserWithLegion(ctx context.Context, legionURL string) (*TokenMa
 		fmt.Println("⚠️  Adjusted Keycloak URL for localhost: using port 8443 instead of 8080")
 	}
 
-	return AuthenticateUser(ctx, config)
+	return config
 }
@@ -0,0 +1,48 @@
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Session is an OAuth login cached between runs: the tokens and the Keycloak client
+// they were issued to, so the refresh token can be used without asking Legion again
+type Session struct {
+	KeycloakURL      string    `json:"keycloak_url"`
+	Realm            string    `json:"realm"`
+	ClientID         string    `json:"client_id"`
+	AccessToken      string    `json:"access_token"`
+	RefreshToken     string    `json:"refresh_token"`
+	ExpiresAt        time.Time `json:"expires_at"`
+	RefreshExpiresAt time.Time `json:"refresh_expires_at"` // Zero for offline tokens
+}
+
+// RefreshExpired reports whether the session can no longer be refreshed
+func (s Session) RefreshExpired() bool {
+	if s.RefreshToken == "" {
+		return time.Now().After(s.ExpiresAt)
+	}
+	return !s.RefreshExpiresAt.IsZero() && time.Now().After(s.RefreshExpiresAt)
+}
+
+// Marshal encodes the session for a credential store
+func (s Session) Marshal() (string, error) {
+	data, err := json.Marshal(s)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode session: %w", err)
+	}
+	return string(data), nil
+}
+
+// ParseSession decodes a session saved with Marshal
+func ParseSession(data string) (Session, error) {
+	var s Session
+	if err := json.Unmarshal([]byte(data), &s); err != nil {
+		return Session{}, fmt.Errorf("failed to decode session: %w", err)
+	}
+	if s.KeycloakURL == "" || (s.AccessToken == "" && s.RefreshToken == "") {
+		return Session{}, fmt.Errorf("cached session is incomplete")
+	}
+	return s, nil
+}
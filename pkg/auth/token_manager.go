@@ -5,29 +5,50 @@ import (
 	"fmt"
 	"sync"
 	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
 )
 
+// refreshRetry is how long the background refresher waits after a failed refresh
+const refreshRetry = 15 * time.Second
+
 // TokenManager manages access and refresh tokens with automatic renewal
 type TokenManager struct {
-	keycloak      *KeycloakClient
-	accessToken   string
-	refreshToken  string
-	expiresAt     time.Time
-	refreshMargin time.Duration
-	mu            sync.RWMutex
+	keycloak         *KeycloakClient
+	accessToken      string
+	refreshToken     string
+	expiresAt        time.Time
+	refreshExpiresAt time.Time // Zero when the refresh token does not expire
+	refreshMargin    time.Duration
+	onRefresh        func(Session)
+	mu               sync.RWMutex
 }
 
 // NewTokenManager creates a new token manager
 func NewTokenManager(keycloak *KeycloakClient, tokenResp *TokenResponse) *TokenManager {
-	expiresAt := time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
-
-	return &TokenManager{
+	tm := &TokenManager{
 		keycloak:      keycloak,
-		accessToken:   tokenResp.AccessToken,
-		refreshToken:  tokenResp.RefreshToken,
-		expiresAt:     expiresAt,
 		refreshMargin: 30 * time.Second, // Refresh 30 seconds before expiry
 	}
+	tm.setTokens(tokenResp)
+	return tm
+}
+
+// NewTokenManagerFromSession restores a token manager from a cached session
+func NewTokenManagerFromSession(session Session) *TokenManager {
+	keycloak := NewKeycloakClient(KeycloakConfig{
+		BaseURL:  session.KeycloakURL,
+		Realm:    session.Realm,
+		ClientID: session.ClientID,
+	})
+	return &TokenManager{
+		keycloak:         keycloak,
+		accessToken:      session.AccessToken,
+		refreshToken:     session.RefreshToken,
+		expiresAt:        session.ExpiresAt,
+		refreshExpiresAt: session.RefreshExpiresAt,
+		refreshMargin:    30 * time.Second,
+	}
 }
 
 // GetAccessToken returns a valid access token, refreshing if necessary
@@ -48,22 +69,41 @@ func (tm *TokenManager) GetAccessToken(ctx context.Context) (string, error) {
 // refreshAccessToken refreshes the access token
 func (tm *TokenManager) refreshAccessToken(ctx context.Context) (string, error) {
 	tm.mu.Lock()
-	defer tm.mu.Unlock()
 
 	if time.Now().Before(tm.expiresAt.Add(-tm.refreshMargin)) {
+		defer tm.mu.Unlock()
 		return tm.accessToken, nil
 	}
 
 	tokenResp, err := tm.keycloak.RefreshToken(ctx, tm.refreshToken)
 	if err != nil {
+		tm.mu.Unlock()
 		return "", fmt.Errorf("failed to refresh token: %w", err)
 	}
 
-	tm.accessToken = tokenResp.AccessToken
-	tm.refreshToken = tokenResp.RefreshToken
-	tm.expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	tm.setTokens(tokenResp)
+	token, onRefresh, session := tm.accessToken, tm.onRefresh, tm.sessionLocked()
+	tm.mu.Unlock()
+
+	if onRefresh != nil {
+		onRefresh(session)
+	}
+	return token, nil
+}
 
-	return tm.accessToken, nil
+// setTokens stores a token response. Caller must hold tm.mu or own tm.
+func (tm *TokenManager) setTokens(tokenResp *TokenResponse) {
+	now := time.Now()
+	tm.accessToken = tokenResp.AccessToken
+	// Keycloak may keep the refresh token when it does not rotate them
+	if tokenResp.RefreshToken != "" {
+		tm.refreshToken = tokenResp.RefreshToken
+	}
+	tm.expiresAt = now.Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	tm.refreshExpiresAt = time.Time{}
+	if tokenResp.RefreshExpiresIn > 0 {
+		tm.refreshExpiresAt = now.Add(time.Duration(tokenResp.RefreshExpiresIn) * time.Second)
+	}
 }
 
 // UpdateTokens updates the token manager with new authentication data
@@ -71,9 +111,7 @@ func (tm *TokenManager) UpdateTokens(tokenResp *TokenResponse) {
 	tm.mu.Lock()
 	defer tm.mu.Unlock()
 
-	tm.accessToken = tokenResp.AccessToken
-	tm.refreshToken = tokenResp.RefreshToken
-	tm.expiresAt = time.Now().Add(time.Duration(tokenResp.ExpiresIn) * time.Second)
+	tm.setTokens(tokenResp)
 }
 
 // IsExpired checks if the current token is expired
@@ -83,3 +121,67 @@ func (tm *TokenManager) IsExpired() bool {
 
 	return time.Now().After(tm.expiresAt)
 }
+
+// OnRefresh registers a callback run with the new session after every refresh, so a
+// cached session stays current
+func (tm *TokenManager) OnRefresh(fn func(Session)) {
+	tm.mu.Lock()
+	defer tm.mu.Unlock()
+
+	tm.onRefresh = fn
+}
+
+// Session returns the current tokens in their cacheable form
+func (tm *TokenManager) Session() Session {
+	tm.mu.RLock()
+	defer tm.mu.RUnlock()
+
+	return tm.sessionLocked()
+}
+
+func (tm *TokenManager) sessionLocked() Session {
+	return Session{
+		KeycloakURL:      tm.keycloak.config.BaseURL,
+		Realm:            tm.keycloak.config.Realm,
+		ClientID:         tm.keycloak.config.ClientID,
+		AccessToken:      tm.accessToken,
+		RefreshToken:     tm.refreshToken,
+		ExpiresAt:        tm.expiresAt,
+		RefreshExpiresAt: tm.refreshExpiresAt,
+	}
+}
+
+// StartAutoRefresh refreshes the access token ahead of expiry until ctx is done. Without
+// it tokens are only refreshed when a request needs one, and a run that goes quiet for
+// longer than the refresh token lifetime (paused, or between waves) loses its session.
+func (tm *TokenManager) StartAutoRefresh(ctx context.Context) {
+	go func() {
+		for {
+			tm.mu.RLock()
+			wait := max(time.Until(tm.expiresAt.Add(-tm.refreshMargin)), time.Second)
+			tm.mu.RUnlock()
+
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(wait):
+			}
+
+			if _, err := tm.refreshAccessToken(ctx); err != nil {
+				if ctx.Err() != nil {
+					return
+				}
+				if tm.Session().RefreshExpired() {
+					logger.Errorf("Legion session expired and could not be refreshed: %v", err)
+					return
+				}
+				logger.Warnf("Token refresh failed, retrying in %s: %v", refreshRetry, err)
+				select {
+				case <-ctx.Done():
+					return
+				case <-time.After(refreshRetry):
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,66 @@
+package auth
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+)
+
+func TestTokenManagerRefreshesStoredSession(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/realms/legion/protocol/openid-connect/token" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := r.ParseForm(); err != nil || r.Form.Get("refresh_token") != "refresh-1" {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant","error_description":"bad refresh token"}`))
+			return
+		}
+		// No refresh_token: Keycloak keeps the old one when rotation is off
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"access_token": "access-2", "expires_in": 300, "refresh_expires_in": 1800})
+	}))
+	defer server.Close()
+
+	tm := NewTokenManagerFromSession(Session{
+		KeycloakURL:  server.URL,
+		Realm:        "legion",
+		ClientID:     "legion-sim",
+		AccessToken:  "access-1",
+		RefreshToken: "refresh-1",
+		ExpiresAt:    time.Now().Add(-time.Minute),
+	})
+	var refreshed Session
+	tm.OnRefresh(func(s Session) { refreshed = s })
+
+	token, err := tm.GetAccessToken(context.Background())
+	if err != nil {
+		t.Fatalf("GetAccessToken: %v", err)
+	}
+	if token != "access-2" {
+		t.Errorf("token = %q, want access-2", token)
+	}
+	if refreshed.AccessToken != "access-2" || refreshed.RefreshToken != "refresh-1" || refreshed.RefreshExpired() {
+		t.Errorf("unexpected refreshed session: %+v", refreshed)
+	}
+
+	data, err := refreshed.Marshal()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if restored, err := ParseSession(data); err != nil || restored.KeycloakURL != server.URL || !restored.ExpiresAt.Equal(refreshed.ExpiresAt) {
+		t.Errorf("session did not round-trip: %+v, %v", restored, err)
+	}
+
+	// A revoked or expired refresh token means logging in again
+	keycloak := NewKeycloakClient(KeycloakConfig{BaseURL: server.URL, Realm: "legion", ClientID: "legion-sim"})
+	if _, err := keycloak.RefreshToken(context.Background(), "revoked"); !errors.Is(err, client.ErrUnauthorized) {
+		t.Errorf("expected a rejected refresh to match ErrUnauthorized, got %v", err)
+	}
+}
@@ -0,0 +1,236 @@
+// Package cleanup finds and deletes the entities and feed definitions simulations leave
+// in a Legion organization, by name pattern or metadata tag. Simulations use it to
+// start from a clean slate; the CLI uses it to purge an organization without a run.
+package cleanup
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// Selector says which entities and feed definitions to remove. An entity matches when
+// its name starts with one of the prefixes, a feed definition when its name contains
+// one of the patterns; with no prefixes or patterns, tags alone select. Either must
+// also carry every tag as a top-level metadata value.
+//
+// Names are matched inside the RunID's namespace (see simulation.Namespace), so a
+// selector never reaches another run ID's entities. Without a RunID, prefixes and
+// patterns skip namespaced names.
+type Selector struct {
+	EntityPrefixes []string
+	FeedPatterns   []string
+	Tags           map[string]string
+	RunID          string
+}
+
+// Empty reports whether the selector would match nothing
+func (s Selector) Empty() bool {
+	return len(s.EntityPrefixes) == 0 && len(s.FeedPatterns) == 0 && len(s.Tags) == 0
+}
+
+// Plan is what a selector matched in an organization
+type Plan struct {
+	Entities []models.EntityResponse
+	Feeds    []models.FeedDefinitionResponse
+}
+
+// Result counts what Delete removed
+type Result struct {
+	EntitiesDeleted int `json:"entities_deleted"`
+	FeedsDeleted    int `json:"feeds_deleted"`
+	Failed          int `json:"failed"`
+}
+
+// ParseTags parses key=value tags
+func ParseTags(values []string) (map[string]string, error) {
+	tags := make(map[string]string, len(values))
+	for _, value := range values {
+		key, want, ok := strings.Cut(value, "=")
+		key = strings.TrimSpace(key)
+		if !ok || key == "" {
+			return nil, fmt.Errorf("invalid tag %q: expected key=value", value)
+		}
+		if _, dup := tags[key]; dup {
+			return nil, fmt.Errorf("tag %s is given twice", key)
+		}
+		tags[key] = strings.TrimSpace(want)
+	}
+	return tags, nil
+}
+
+// Find searches an organization for what the selector matches. A failed search is
+// returned alongside whatever the other searches found.
+func Find(ctx context.Context, c client.LegionAPI, orgID uuid.UUID, sel Selector) (*Plan, error) {
+	orgCtx := client.WithOrgID(ctx, orgID.String())
+	plan := &Plan{}
+	var errs []error
+
+	// Search each prefix separately to avoid overwhelming the API
+	prefixes := sel.EntityPrefixes
+	if len(prefixes) == 0 && len(sel.Tags) > 0 {
+		prefixes = []string{""}
+	}
+	seen := make(map[uuid.UUID]bool)
+	for _, prefix := range prefixes {
+		req := &models.SearchEntitiesRequest{OrganizationID: &orgID}
+		if search := simulation.Namespace(sel.RunID, prefix); search != "" {
+			req.Filters = &models.SearchFilters{Name: search} // Prefix match
+		}
+		err := c.ForEachEntity(orgCtx, req, func(entity models.EntityResponse) bool {
+			name, ok := sel.unscoped(entity.Name)
+			if seen[entity.ID] || !ok || !strings.HasPrefix(name, prefix) || !hasTags(entity.Metadata, sel.Tags) {
+				return true
+			}
+			seen[entity.ID] = true
+			plan.Entities = append(plan.Entities, entity)
+			return true
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("search entities with prefix %q: %w", prefix, err))
+		}
+	}
+
+	if len(sel.FeedPatterns) > 0 || len(sel.Tags) > 0 {
+		result, err := c.SearchFeedDefinitions(orgCtx, &models.FeedDefinitionSearchRequest{
+			Category:       models.MessageCategoryMESSAGE,
+			OrganizationID: &orgID,
+		})
+		if err != nil {
+			errs = append(errs, fmt.Errorf("search feed definitions: %w", err))
+		} else {
+			for _, feed := range result.Results {
+				name, ok := sel.unscoped(feed.FeedName)
+				if ok && matchesFeed(name, sel.FeedPatterns) && hasTags(feed.Metadata, sel.Tags) {
+					plan.Feeds = append(plan.Feeds, feed)
+				}
+			}
+		}
+	}
+
+	return plan, errors.Join(errs...)
+}
+
+// ForRun plans the removal of what a run recorded in its result: its entities and the
+// feed definitions attached to them. Entities from other runs are left alone, unlike a
+// selector's name patterns.
+func ForRun(ctx context.Context, c client.LegionAPI, orgID uuid.UUID, records []simulation.EntityRecord) (*Plan, error) {
+	plan := &Plan{}
+	var errs []error
+
+	owned := make(map[uuid.UUID]bool, len(records))
+	for _, record := range records {
+		id, err := uuid.Parse(record.ID)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("entity %s has an invalid ID: %w", record.Name, err))
+			continue
+		}
+		if owned[id] {
+			continue
+		}
+		owned[id] = true
+		plan.Entities = append(plan.Entities, models.EntityResponse{ID: id, Name: record.Name, Type: record.Type})
+	}
+	if len(owned) == 0 {
+		return plan, errors.Join(errs...)
+	}
+
+	result, err := c.SearchFeedDefinitions(client.WithOrgID(ctx, orgID.String()), &models.FeedDefinitionSearchRequest{
+		Category:       models.MessageCategoryMESSAGE,
+		OrganizationID: &orgID,
+	})
+	if err != nil {
+		errs = append(errs, fmt.Errorf("search feed definitions: %w", err))
+	} else {
+		for _, feed := range result.Results {
+			if owned[feed.EntityID] {
+				plan.Feeds = append(plan.Feeds, feed)
+			}
+		}
+	}
+
+	return plan, errors.Join(errs...)
+}
+
+// Delete removes a plan's entities and then its feed definitions. Failures are logged
+// and counted; the rest are still deleted.
+func Delete(ctx context.Context, c client.LegionAPI, orgID uuid.UUID, plan *Plan) Result {
+	orgCtx := client.WithOrgID(ctx, orgID.String())
+	var result Result
+
+	for _, entity := range plan.Entities {
+		if err := c.DeleteEntity(orgCtx, entity.ID.String()); err != nil {
+			logger.Debugf("Failed to delete entity %s (%s): %v", entity.Name, entity.ID, err)
+			result.Failed++
+			continue
+		}
+		logger.Debugf("Deleted entity: %s", entity.Name)
+		result.EntitiesDeleted++
+	}
+
+	for _, feed := range plan.Feeds {
+		if err := c.DeleteFeedDefinition(orgCtx, feed.ID.String()); err != nil {
+			logger.Warnf("Failed to delete feed %s (ID: %s): %v", feed.FeedName, feed.ID, err)
+			result.Failed++
+			continue
+		}
+		logger.Debugf("Deleted feed: %s", feed.FeedName)
+		result.FeedsDeleted++
+	}
+
+	return result
+}
+
+// unscoped strips the selector's namespace from a name, reporting false for a name in
+// another namespace. Tags alone select across namespaces unless a RunID is given.
+func (s Selector) unscoped(name string) (string, bool) {
+	runID, rest := simulation.SplitNamespace(name)
+	if s.RunID == "" && len(s.EntityPrefixes) == 0 && len(s.FeedPatterns) == 0 {
+		return name, true
+	}
+	return rest, runID == s.RunID
+}
+
+// matchesFeed reports whether a feed name contains one of the patterns, or any name
+// when there are none
+func matchesFeed(name string, patterns []string) bool {
+	if len(patterns) == 0 {
+		return true
+	}
+	for _, pattern := range patterns {
+		if strings.Contains(name, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// hasTags reports whether metadata carries every tag as a top-level value
+func hasTags(metadata *json.RawMessage, tags map[string]string) bool {
+	if len(tags) == 0 {
+		return true
+	}
+	if metadata == nil {
+		return false
+	}
+	var fields map[string]interface{}
+	if err := json.Unmarshal(*metadata, &fields); err != nil {
+		return false
+	}
+	for key, want := range tags {
+		value, ok := fields[key]
+		if !ok || value == nil || fmt.Sprint(value) != want {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,168 @@
+package cleanup
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+func TestParseTags(t *testing.T) {
+	tags, err := ParseTags([]string{"run_id=abc", " team = red "})
+	if err != nil {
+		t.Fatalf("ParseTags: %v", err)
+	}
+	if tags["run_id"] != "abc" || tags["team"] != "red" {
+		t.Fatalf("unexpected tags: %v", tags)
+	}
+
+	for _, bad := range [][]string{{"run_id"}, {"=abc"}, {"a=1", "a=2"}} {
+		if _, err := ParseTags(bad); err == nil {
+			t.Errorf("ParseTags(%q) should fail", bad)
+		}
+	}
+}
+
+func TestHasTags(t *testing.T) {
+	raw := json.RawMessage(`{"run_id":"abc","wave":2,"nested":{"run_id":"xyz"}}`)
+	tests := []struct {
+		tags map[string]string
+		want bool
+	}{
+		{nil, true},
+		{map[string]string{"run_id": "abc"}, true},
+		{map[string]string{"run_id": "abc", "wave": "2"}, true},
+		{map[string]string{"run_id": "xyz"}, false},
+		{map[string]string{"missing": ""}, false},
+	}
+	for _, tt := range tests {
+		if got := hasTags(&raw, tt.tags); got != tt.want {
+			t.Errorf("hasTags(%v) = %v, want %v", tt.tags, got, tt.want)
+		}
+	}
+	if hasTags(nil, map[string]string{"run_id": "abc"}) {
+		t.Error("entity without metadata should not match a tag")
+	}
+}
+
+func TestMatchesFeed(t *testing.T) {
+	patterns := []string{"cuas_datalink_", "threat_board"}
+	if !matchesFeed("cuas_datalink_1a2b3c4d", patterns) {
+		t.Error("datalink feed should match")
+	}
+	if matchesFeed("weather_observations", patterns) {
+		t.Error("unrelated feed should not match")
+	}
+	if !matchesFeed("weather_observations", nil) {
+		t.Error("with no patterns every feed should match")
+	}
+}
+
+func TestForRun(t *testing.T) {
+	legion, _ := client.NewMemoryClient()
+	orgID := uuid.New()
+	ctx := client.WithOrgID(context.Background(), orgID.String())
+
+	category, status, entityType, message := models.CategoryUXV, "active", "Drone", models.MessageCategoryMESSAGE
+	dataType, active := "json", true
+	var entities []*models.EntityResponse
+	for _, name := range []string{"HAWK-1", "HAWK-2"} {
+		entity, err := legion.CreateEntity(ctx, &models.CreateEntityRequest{
+			Category:       &category,
+			Name:           &name,
+			OrganizationID: &orgID,
+			Status:         &status,
+			Type:           &entityType,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		feedName := "cuas_datalink_" + name
+		if _, err := legion.CreateFeedDefinition(ctx, &models.CreateFeedDefinitionRequest{
+			Category: &message,
+			DataType: &dataType,
+			EntityID: entity.ID,
+			FeedName: &feedName,
+			IsActive: &active,
+		}); err != nil {
+			t.Fatal(err)
+		}
+		entities = append(entities, entity)
+	}
+
+	// Only the first entity belongs to the run; the second is another run's
+	records := []simulation.EntityRecord{
+		{ID: entities[0].ID.String(), Name: "HAWK-1", Type: entityType},
+		{ID: entities[0].ID.String(), Name: "HAWK-1", Type: entityType},
+		{ID: "not-a-uuid", Name: "broken"},
+	}
+	plan, err := ForRun(context.Background(), legion, orgID, records)
+	if err == nil {
+		t.Error("an invalid entity ID should be reported")
+	}
+	if len(plan.Entities) != 1 || plan.Entities[0].ID != entities[0].ID {
+		t.Fatalf("expected only the run's entity, got %+v", plan.Entities)
+	}
+	if len(plan.Feeds) != 1 || plan.Feeds[0].FeedName != "cuas_datalink_HAWK-1" {
+		t.Fatalf("expected only the run's feed, got %+v", plan.Feeds)
+	}
+}
+
+func TestFindStaysInRunNamespace(t *testing.T) {
+	legion, _ := client.NewMemoryClient()
+	orgID := uuid.New()
+	ctx := client.WithOrgID(context.Background(), orgID.String())
+
+	category, status, entityType, message := models.CategoryUXV, "active", "Drone", models.MessageCategoryMESSAGE
+	dataType, active := "json", true
+	for _, runID := range []string{"", "alice", "bob"} {
+		name := simulation.Namespace(runID, "HAWK-1")
+		entity, err := legion.CreateEntity(ctx, &models.CreateEntityRequest{
+			Category:       &category,
+			Name:           &name,
+			OrganizationID: &orgID,
+			Status:         &status,
+			Type:           &entityType,
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+		feedName := simulation.Namespace(runID, "cuas_datalink_1")
+		if _, err := legion.CreateFeedDefinition(ctx, &models.CreateFeedDefinitionRequest{
+			Category: &message,
+			DataType: &dataType,
+			EntityID: entity.ID,
+			FeedName: &feedName,
+			IsActive: &active,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	tests := []struct {
+		runID  string
+		entity string
+		feed   string
+	}{
+		{"", "HAWK-1", "cuas_datalink_1"},
+		{"alice", "alice/HAWK-1", "alice/cuas_datalink_1"},
+	}
+	for _, tt := range tests {
+		sel := Selector{EntityPrefixes: []string{"HAWK-"}, FeedPatterns: []string{"cuas_datalink_"}, RunID: tt.runID}
+		plan, err := Find(context.Background(), legion, orgID, sel)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(plan.Entities) != 1 || plan.Entities[0].Name != tt.entity {
+			t.Errorf("run ID %q: expected only %s, got %+v", tt.runID, tt.entity, plan.Entities)
+		}
+		if len(plan.Feeds) != 1 || plan.Feeds[0].FeedName != tt.feed {
+			t.Errorf("run ID %q: expected only %s, got %+v", tt.runID, tt.feed, plan.Feeds)
+		}
+	}
+}
@@ -0,0 +1,49 @@
+package client
+
+import (
+	"context"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// LegionAPI is the Legion API as simulations and tools call it: entities, locations,
+// feeds and the organization. *Legion implements it against a server; a client from
+// NewMemoryClient implements it in memory, with Memory recording each call for tests.
+// Code that only reads and writes Legion should take a LegionAPI, leaving the setup of
+// budgets, breakers and hooks to whoever builds the client.
+type LegionAPI interface {
+	CreateEntity(ctx context.Context, req *models.CreateEntityRequest) (*models.EntityResponse, error)
+	CreateEntities(ctx context.Context, reqs []*models.CreateEntityRequest, concurrency int) ([]*models.EntityResponse, error)
+	GetEntity(ctx context.Context, entityID string) (*models.EntityResponse, error)
+	UpdateEntity(ctx context.Context, entityID string, req *models.UpdateEntityRequest) (*models.EntityResponse, error)
+	PatchEntity(ctx context.Context, entityID string, patch *models.EntityPatch) (*models.EntityResponse, error)
+	DeleteEntity(ctx context.Context, entityID string) error
+	SearchEntities(ctx context.Context, req *models.SearchEntitiesRequest) (*models.EntityPaginatedResponse, error)
+	ForEachEntity(ctx context.Context, req *models.SearchEntitiesRequest, fn func(models.EntityResponse) bool) error
+	SearchAllEntities(ctx context.Context, req *models.SearchEntitiesRequest) ([]models.EntityResponse, error)
+	SubscribeEntities(ctx context.Context, req *models.SearchEntitiesRequest, interval time.Duration) *EntitySubscription
+	GetEntityHistory(ctx context.Context, entityID uuid.UUID, since, until *time.Time, sources ...string) (*models.EntityHistory, error)
+
+	CreateEntityLocation(ctx context.Context, entityID string, req *models.CreateEntityLocationRequest) (*models.EntityLocationResponse, error)
+	CreateEntityLocations(ctx context.Context, locations []EntityLocation, concurrency int) ([]*models.EntityLocationResponse, []error)
+	GetEntityLocations(ctx context.Context, entityID string) (*models.EntityLocationPaginatedResponse, error)
+	SearchEntityLocations(ctx context.Context, req *models.SearchEntityLocationsRequest) (*models.EntityLocationPaginatedResponse, error)
+	GetEntityLocationHistory(ctx context.Context, entityID uuid.UUID, since, until *time.Time, sources ...string) ([]models.EntityLocationResponse, error)
+
+	CreateFeedDefinition(ctx context.Context, req *models.CreateFeedDefinitionRequest) (*models.FeedDefinitionResponse, error)
+	GetFeedDefinition(ctx context.Context, feedID string) (*models.FeedDefinitionResponse, error)
+	UpdateFeedDefinition(ctx context.Context, feedID string, req *models.UpdateFeedDefinitionRequest) (*models.FeedDefinitionResponse, error)
+	DeleteFeedDefinition(ctx context.Context, feedID string) error
+	SearchFeedDefinitions(ctx context.Context, req *models.FeedDefinitionSearchRequest) (*models.FeedDefinitionListResponse, error)
+	SearchFeedData(ctx context.Context, req *models.FeedDataSearchRequest) (*models.FeedDataListResponse, error)
+	IngestServiceMessage(ctx context.Context, req *models.ServiceIngestMessageRequest) error
+	IngestFeedData(ctx context.Context, req *models.IngestFeedDataRequest) error
+
+	GetOrganization(ctx context.Context) (*models.OrganizationResponse, error)
+	GetMe(ctx context.Context) (*models.UserResponse, error)
+}
+
+var _ LegionAPI = (*Legion)(nil)
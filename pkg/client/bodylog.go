@@ -0,0 +1,146 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// maxLoggedBody caps each logged body so a large search result doesn't flood the log
+const maxLoggedBody = 8 << 10
+
+// redacted replaces every value body logging hides
+const redacted = "[REDACTED]"
+
+// sensitiveHeaders carry credentials
+var sensitiveHeaders = map[string]bool{
+	"Authorization":       true,
+	"Proxy-Authorization": true,
+	"Cookie":              true,
+	"Set-Cookie":          true,
+	"X-Api-Key":           true,
+}
+
+// sensitiveFields are JSON keys, lowercased without '_' or '-', whose values are
+// credentials or personal details. Keys containing token, secret or password are
+// hidden too.
+var sensitiveFields = map[string]bool{
+	"apikey":        true,
+	"authorization": true,
+	"email":         true,
+	"phone":         true,
+	"phonenumber":   true,
+	"firstname":     true,
+	"lastname":      true,
+	"username":      true,
+}
+
+// Credentials and personal details that turn up inside other text, e.g. an error message
+var (
+	bearerPattern = regexp.MustCompile(`(?i)(bearer\s+)[A-Za-z0-9._~+/=-]+`)
+	emailPattern  = regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)
+)
+
+// SetLogBodies turns on logging of every request and response in full, at debug level
+// under the client module, for debugging failed calls in the field. Credentials and
+// personal details such as emails and names are redacted, and each body is cut at 8KB.
+func (c *Legion) SetLogBodies(on bool) {
+	c.logBodies = on
+}
+
+// logRequest logs an attempt about to be sent
+func logRequest(req *http.Request, body []byte) {
+	clientLog.Debugf("%s %s request: headers %s body %s", req.Method, req.URL.Path, redactHeaders(req.Header), redactBody(body))
+}
+
+// logResponse logs a response, leaving its body in place for the caller to read
+func logResponse(req *http.Request, resp *http.Response) {
+	data, err := io.ReadAll(resp.Body)
+	_ = resp.Body.Close()
+	resp.Body = io.NopCloser(bytes.NewReader(data))
+	if err != nil {
+		clientLog.Debugf("%s %s response %d: reading body failed: %v", req.Method, req.URL.Path, resp.StatusCode, err)
+		return
+	}
+	clientLog.Debugf("%s %s response %d: headers %s body %s", req.Method, req.URL.Path, resp.StatusCode, redactHeaders(resp.Header), redactBody(data))
+}
+
+// redactHeaders formats headers in name order with credentials hidden
+func redactHeaders(h http.Header) string {
+	names := make([]string, 0, len(h))
+	for name := range h {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		value := strings.Join(h[name], ", ")
+		if sensitiveHeaders[http.CanonicalHeaderKey(name)] {
+			value = redacted
+		}
+		parts = append(parts, name+"="+value)
+	}
+	return "{" + strings.Join(parts, "; ") + "}"
+}
+
+// redactBody formats a body with credentials and personal details hidden. JSON is
+// redacted field by field; anything else only has tokens and emails masked.
+func redactBody(data []byte) string {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return "(empty)"
+	}
+
+	text := string(data)
+	var decoded interface{}
+	if json.Unmarshal(data, &decoded) == nil {
+		if encoded, err := json.Marshal(redactValue(decoded)); err == nil {
+			text = string(encoded)
+		}
+	} else {
+		text = redactText(text)
+	}
+
+	if len(text) > maxLoggedBody {
+		text = text[:maxLoggedBody] + "...(truncated)"
+	}
+	return text
+}
+
+// redactValue hides sensitive fields anywhere in a decoded JSON value
+func redactValue(v interface{}) interface{} {
+	switch v := v.(type) {
+	case map[string]interface{}:
+		for key, value := range v {
+			if sensitiveField(key) {
+				v[key] = redacted
+				continue
+			}
+			v[key] = redactValue(value)
+		}
+		return v
+	case []interface{}:
+		for i, value := range v {
+			v[i] = redactValue(value)
+		}
+		return v
+	case string:
+		return redactText(v)
+	}
+	return v
+}
+
+func sensitiveField(key string) bool {
+	key = strings.NewReplacer("_", "", "-", "").Replace(strings.ToLower(key))
+	return sensitiveFields[key] || strings.Contains(key, "token") || strings.Contains(key, "secret") || strings.Contains(key, "password")
+}
+
+// redactText masks bearer tokens and emails within free text
+func redactText(s string) string {
+	s = bearerPattern.ReplaceAllString(s, "${1}"+redacted)
+	return emailPattern.ReplaceAllString(s, redacted)
+}
@@ -0,0 +1,29 @@
+package client
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+func TestRedactBodyHidesCredentialsAndPersonalDetails(t *testing.T) {
+	body := redactBody([]byte(`{"name":"Counter-UAS-01","user":{"email":"jo@example.com","first_name":"Jo"},` +
+		`"refresh_token":"abc","client_secret":"xyz","message":"key Bearer eyJhbGci rejected for ops@example.mil"}`))
+	for _, leaked := range []string{"jo@example.com", `"Jo"`, "abc", "xyz", "eyJhbGci", "ops@example.mil"} {
+		if strings.Contains(body, leaked) {
+			t.Errorf("%s leaked in %s", leaked, body)
+		}
+	}
+	if !strings.Contains(body, "Counter-UAS-01") {
+		t.Errorf("expected entity names kept, got %s", body)
+	}
+
+	if text := redactBody([]byte("upstream error: Bearer abc.def")); text != "upstream error: Bearer [REDACTED]" {
+		t.Errorf("unexpected redacted text %q", text)
+	}
+
+	headers := redactHeaders(http.Header{"Authorization": {"Bearer abc"}, "X-Org-Id": {"org-1"}})
+	if headers != "{Authorization=[REDACTED]; X-Org-Id=org-1}" {
+		t.Errorf("unexpected headers %s", headers)
+	}
+}
@@ -0,0 +1,166 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+)
+
+// ErrCircuitOpen is returned instead of sending a request while the circuit breaker is open
+var ErrCircuitOpen = errors.New("circuit breaker open: Legion is failing")
+
+// CircuitState is where a circuit breaker stands
+type CircuitState int
+
+// Circuit breaker states
+const (
+	CircuitClosed   CircuitState = iota // Requests are sent
+	CircuitOpen                         // Requests fail at once with ErrCircuitOpen
+	CircuitHalfOpen                     // One request is sent to see if Legion has recovered
+)
+
+func (s CircuitState) String() string {
+	switch s {
+	case CircuitOpen:
+		return "open"
+	case CircuitHalfOpen:
+		return "half-open"
+	}
+	return "closed"
+}
+
+// CircuitBreaker stops a client sending to a Legion that keeps failing. After threshold
+// requests in a row fail with a server error, throttling or no response, it opens and
+// later requests fail at once for the cooldown. Then one request is let through: if it
+// succeeds the breaker closes, otherwise it opens for another cooldown. Client errors
+// such as 404 mean Legion is answering, so they count as successes.
+type CircuitBreaker struct {
+	threshold int
+	cooldown  time.Duration
+	state     CircuitState
+	failures  int // Failed requests in a row
+	openedAt  time.Time
+	probing   bool // A half-open request is in flight
+	opened    int
+	rejected  int
+	mu        sync.Mutex
+}
+
+// CircuitBreakerStats summarizes what a breaker has done
+type CircuitBreakerStats struct {
+	State    string `json:"state"`
+	Opened   int    `json:"opened"`   // Times the breaker opened
+	Rejected int    `json:"rejected"` // Requests failed without being sent
+}
+
+// NewCircuitBreaker opens after threshold failures in a row, a threshold below 1 taken
+// as 1, and stays open for cooldown before trying Legion again
+func NewCircuitBreaker(threshold int, cooldown time.Duration) *CircuitBreaker {
+	return &CircuitBreaker{threshold: max(threshold, 1), cooldown: cooldown}
+}
+
+// State returns where the breaker stands. An open breaker whose cooldown has passed
+// reports half-open, as the next request would be sent.
+func (b *CircuitBreaker) State() CircuitState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && time.Since(b.openedAt) >= b.cooldown {
+		return CircuitHalfOpen
+	}
+	return b.state
+}
+
+// Stats returns what the breaker has done so far
+func (b *CircuitBreaker) Stats() CircuitBreakerStats {
+	state := b.State()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return CircuitBreakerStats{State: state.String(), Opened: b.opened, Rejected: b.rejected}
+}
+
+// allow reports whether a request may be sent at now, and whether it is the one request
+// let through while half-open. No other is sent until the probe is recorded.
+func (b *CircuitBreaker) allow(now time.Time) (ok, probe bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.state == CircuitOpen && now.Sub(b.openedAt) >= b.cooldown {
+		b.state = CircuitHalfOpen
+	}
+	switch {
+	case b.state == CircuitClosed:
+		return true, false
+	case b.state == CircuitHalfOpen && !b.probing:
+		b.probing = true
+		return true, true
+	}
+	b.rejected++
+	return false, false
+}
+
+// breakerOutcome is how a finished request bears on the breaker
+type breakerOutcome int
+
+const (
+	outcomeSuccess breakerOutcome = iota
+	outcomeFailure
+	outcomeIgnored // Cancelled by the caller or stopped before sending; says nothing about Legion
+)
+
+// record applies the outcome of a request allowed earlier, probe or not, at now
+func (b *CircuitBreaker) record(outcome breakerOutcome, probe bool, now time.Time) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probe {
+		b.probing = false
+	}
+
+	switch outcome {
+	case outcomeSuccess:
+		if b.state != CircuitClosed {
+			clientLog.Infof("Circuit breaker closed; Legion is answering again")
+		}
+		b.state, b.failures = CircuitClosed, 0
+	case outcomeFailure:
+		b.failures++
+		if b.state == CircuitClosed && b.failures >= b.threshold {
+			clientLog.Warnf("Circuit breaker opened after %d failed requests; pausing for %s", b.failures, b.cooldown)
+			b.state, b.openedAt = CircuitOpen, now
+			b.opened++
+		} else if probe {
+			b.state, b.openedAt = CircuitOpen, now
+			b.opened++
+		}
+	}
+}
+
+// classify decides how a request that ended with status and err bears on the breaker.
+// Status is 0 when no response came back.
+func classify(ctx context.Context, status int, err error) breakerOutcome {
+	var urlErr *url.Error
+	switch {
+	case err == nil:
+		return outcomeSuccess
+	case ctx.Err() != nil:
+		return outcomeIgnored
+	case status == http.StatusTooManyRequests || status >= http.StatusInternalServerError:
+		return outcomeFailure
+	case status == 0 && errors.As(err, &urlErr):
+		return outcomeFailure
+	case status == 0:
+		return outcomeIgnored // Failed before sending, e.g. a request hook or token refresh
+	}
+	return outcomeSuccess
+}
+
+// SetCircuitBreaker guards every later request with b. Nil removes it.
+func (c *Legion) SetCircuitBreaker(b *CircuitBreaker) {
+	c.breaker = b
+}
+
+// CircuitBreaker returns the installed circuit breaker, or nil
+func (c *Legion) CircuitBreaker() *CircuitBreaker {
+	return c.breaker
+}
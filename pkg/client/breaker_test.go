@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestCircuitBreakerOpensAndRecovers(t *testing.T) {
+	var calls, status atomic.Int32
+	status.Store(http.StatusServiceUnavailable)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls.Add(1)
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(int(status.Load()))
+		_, _ = w.Write([]byte(`{"id":"8c3f3b0e-8d0a-4a8e-9a8e-3c2d1b0a9f8e"}`))
+	}))
+	defer server.Close()
+
+	legion, err := NewClient(Config{BaseURL: server.URL, Retry: &RetryPolicy{MaxAttempts: 1}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	breaker := NewCircuitBreaker(2, 50*time.Millisecond)
+	legion.SetCircuitBreaker(breaker)
+	ctx := context.Background()
+
+	for i := 0; i < 2; i++ {
+		if _, err := legion.GetMe(ctx); err == nil || errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("expected the server's error, got %v", err)
+		}
+	}
+	if breaker.State() != CircuitOpen {
+		t.Fatalf("expected the breaker open after 2 failures, got %s", breaker.State())
+	}
+	if _, err := legion.GetMe(ctx); !errors.Is(err, ErrCircuitOpen) || calls.Load() != 2 {
+		t.Fatalf("expected a request failed without sending, got %v after %d calls", err, calls.Load())
+	}
+
+	// After the cooldown one failed probe reopens it at once
+	time.Sleep(60 * time.Millisecond)
+	if _, err := legion.GetMe(ctx); err == nil || errors.Is(err, ErrCircuitOpen) || breaker.State() != CircuitOpen {
+		t.Fatalf("expected the probe to fail and reopen the breaker, got %v (%s)", err, breaker.State())
+	}
+
+	// A successful probe closes it
+	time.Sleep(60 * time.Millisecond)
+	status.Store(http.StatusOK)
+	if _, err := legion.GetMe(ctx); err != nil {
+		t.Fatal(err)
+	}
+	stats := breaker.Stats()
+	if stats.State != "closed" || stats.Opened != 2 || stats.Rejected != 1 {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	// Client errors mean Legion is answering
+	status.Store(http.StatusNotFound)
+	for i := 0; i < 3; i++ {
+		_, _ = legion.GetMe(ctx)
+	}
+	if breaker.State() != CircuitClosed {
+		t.Errorf("expected client errors to leave the breaker closed, got %s", breaker.State())
+	}
+}
@@ -0,0 +1,157 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+)
+
+// Priority orders requests for shedding when a budget runs short
+type Priority int
+
+// Request priorities, most important first
+const (
+	PriorityCritical Priority = iota // Creates, deletes, searches and status changes; never shed
+	PriorityNormal                   // Metadata patches and feed messages
+	PriorityPosition                 // Location updates; shed first
+)
+
+// Headroom each priority leaves in the per-minute budget for more important requests
+const (
+	positionReserve = 0.3
+	normalReserve   = 0.1
+)
+
+// ErrBudgetExceeded is returned instead of sending a request the budget sheds
+var ErrBudgetExceeded = errors.New("API budget exceeded")
+
+type priorityContextKey struct{}
+
+// WithPriority marks requests made with ctx for budget shedding
+func WithPriority(ctx context.Context, p Priority) context.Context {
+	return context.WithValue(ctx, priorityContextKey{}, p)
+}
+
+// withDefaultPriority sets p unless the caller already chose a priority
+func withDefaultPriority(ctx context.Context, p Priority) context.Context {
+	if _, ok := ctx.Value(priorityContextKey{}).(Priority); ok {
+		return ctx
+	}
+	return WithPriority(ctx, p)
+}
+
+func priorityFrom(ctx context.Context) Priority {
+	if p, ok := ctx.Value(priorityContextKey{}).(Priority); ok {
+		return p
+	}
+	return PriorityCritical
+}
+
+// Budget caps API calls per minute and per run. Positions are shed first, then metadata;
+// critical requests always go out but still draw down the budget.
+type Budget struct {
+	perMinute int
+	perRun    int
+	tokens    float64
+	last      time.Time
+	used      int
+	shed      [PriorityPosition + 1]int
+	seconds   [60]int   // Calls per second over the last minute
+	stamps    [60]int64 // Unix second each slot holds
+	peak      int
+	mu        sync.Mutex
+}
+
+// BudgetStats summarizes budget use
+type BudgetStats struct {
+	PerMinute     int `json:"per_minute"`
+	PerRun        int `json:"per_run"`
+	Used          int `json:"used"`
+	ShedPositions int `json:"shed_positions"`
+	ShedNormal    int `json:"shed_normal"`
+	PeakPerMinute int `json:"peak_per_minute"` // Most calls in any 60s window
+}
+
+// NewBudget creates a budget. Zero leaves that limit off.
+func NewBudget(perMinute, perRun int) *Budget {
+	return &Budget{
+		perMinute: perMinute,
+		perRun:    perRun,
+		tokens:    float64(perMinute),
+		last:      time.Now(),
+	}
+}
+
+// Allow reports whether a request of priority p may be sent now and records it
+func (b *Budget) Allow(p Priority) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.allow(p, time.Now())
+}
+
+func (b *Budget) allow(p Priority, now time.Time) bool {
+	if b.perMinute > 0 {
+		rate := float64(b.perMinute) / time.Minute.Seconds()
+		b.tokens = min(b.tokens+now.Sub(b.last).Seconds()*rate, float64(b.perMinute))
+	}
+	b.last = now
+
+	if p != PriorityCritical && !b.hasRoom(p) {
+		b.shed[p]++
+		return false
+	}
+
+	b.tokens--
+	b.used++
+	b.count(now)
+	return true
+}
+
+// hasRoom checks the run cap and the headroom reserved for higher priorities
+func (b *Budget) hasRoom(p Priority) bool {
+	if b.perRun > 0 && b.used >= b.perRun {
+		return false
+	}
+	if b.perMinute == 0 {
+		return true
+	}
+	reserve := normalReserve
+	if p == PriorityPosition {
+		reserve = positionReserve
+	}
+	return b.tokens >= 1+reserve*float64(b.perMinute)
+}
+
+// count tallies a call in its one-second slot and tracks the busiest minute
+func (b *Budget) count(now time.Time) {
+	sec := now.Unix()
+	slot := sec % 60
+	if b.stamps[slot] != sec {
+		b.stamps[slot] = sec
+		b.seconds[slot] = 0
+	}
+	b.seconds[slot]++
+
+	total := 0
+	for i, stamp := range b.stamps {
+		if sec-stamp < 60 {
+			total += b.seconds[i]
+		}
+	}
+	b.peak = max(b.peak, total)
+}
+
+// Stats returns budget use so far
+func (b *Budget) Stats() BudgetStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return BudgetStats{
+		PerMinute:     b.perMinute,
+		PerRun:        b.perRun,
+		Used:          b.used,
+		ShedPositions: b.shed[PriorityPosition],
+		ShedNormal:    b.shed[PriorityNormal],
+		PeakPerMinute: b.peak,
+	}
+}
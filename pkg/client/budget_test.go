@@ -0,0 +1,57 @@
+package client
+
+import (
+	"testing"
+	"time"
+)
+
+func TestBudgetShedsPositionsFirst(t *testing.T) {
+	b := NewBudget(100, 0)
+	now := b.last
+
+	// Positions stop once 30% headroom is left
+	sent := 0
+	for b.allow(PriorityPosition, now) {
+		sent++
+	}
+	if sent != 70 {
+		t.Fatalf("expected 70 positions before shedding, got %d", sent)
+	}
+
+	// Metadata still fits into the next 20%, statuses always go out
+	if !b.allow(PriorityNormal, now) {
+		t.Error("metadata shed while headroom remained")
+	}
+	for i := 0; i < 50; i++ {
+		if !b.allow(PriorityCritical, now) {
+			t.Fatal("critical request shed")
+		}
+	}
+	if b.allow(PriorityNormal, now) {
+		t.Error("metadata sent with the budget spent")
+	}
+
+	// Refills at the per-minute rate
+	if !b.allow(PriorityPosition, now.Add(time.Minute)) {
+		t.Error("positions still shed after a full minute")
+	}
+
+	stats := b.Stats()
+	if stats.ShedPositions != 1 || stats.ShedNormal != 1 || stats.Used != 122 {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestBudgetRunCap(t *testing.T) {
+	b := NewBudget(0, 2)
+	now := time.Now()
+	if !b.allow(PriorityPosition, now) || !b.allow(PriorityNormal, now) {
+		t.Fatal("requests under the run cap were shed")
+	}
+	if b.allow(PriorityPosition, now) {
+		t.Error("position sent past the run cap")
+	}
+	if !b.allow(PriorityCritical, now) {
+		t.Error("critical request shed by the run cap")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"bytes"
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -13,6 +14,9 @@ import (
 	"github.com/picogrid/legion-simulations/pkg/logger"
 )
 
+// clientLog logs API traffic under the "client" module level
+var clientLog = logger.Module(logger.ModuleClient)
+
 // contextKey is a custom type for context keys to avoid collisions
 type contextKey string
 
@@ -28,6 +32,15 @@ type Legion struct {
 	apiKey       string
 	httpClient   *http.Client
 	tokenManager TokenManager
+	budget       *Budget         // Optional call budget with priority shedding
+	recorder     Recorder        // Optional record of accepted entity writes
+	limiter      *RateLimiter    // Optional pacing shared by every request
+	breaker      *CircuitBreaker // Optional fail-fast while Legion keeps failing
+	retry        RetryPolicy
+	logBodies    bool // Log every request and response in full, redacted
+
+	requestHooks  []RequestHook
+	responseHooks []ResponseHook
 }
 
 // TokenManager interface for token management
@@ -39,8 +52,11 @@ type TokenManager interface {
 type Config struct {
 	BaseURL      string
 	APIKey       string
-	Timeout      time.Duration
-	TokenManager TokenManager // Optional: for OAuth2 authentication
+	Timeout      time.Duration   // Per request, retries each getting their own; 30s when zero
+	TokenManager TokenManager    // Optional: for OAuth2 authentication
+	Retry        *RetryPolicy    // Optional: DefaultRetryPolicy when nil
+	Transport    TransportConfig // Optional: connection pooling, keep-alive and HTTP/2 settings
+	LogBodies    bool            // Optional: log requests and responses in full, redacted (see SetLogBodies)
 }
 
 // NewClient creates a new Legion client with the given configuration
@@ -57,28 +73,116 @@ func NewClient(cfg Config) (*Legion, error) {
 		timeout = 30 * time.Second
 	}
 
+	retry := DefaultRetryPolicy()
+	if cfg.Retry != nil {
+		retry = *cfg.Retry
+	}
+
 	return &Legion{
 		baseURL:      u.String(),
 		apiKey:       cfg.APIKey,
 		tokenManager: cfg.TokenManager,
-		httpClient: &http.Client{
-			Timeout: timeout,
-		},
+		httpClient:   newHTTPClient(timeout, cfg.Transport),
+		retry:        retry,
+		logBodies:    cfg.LogBodies,
 	}, nil
 }
 
-// doRequest performs an HTTP request with authentication and error handling
+// SetBudget enforces a call budget on every later request. Nil removes it.
+func (c *Legion) SetBudget(b *Budget) {
+	c.budget = b
+}
+
+// Budget returns the enforced call budget, or nil
+func (c *Legion) Budget() *Budget {
+	return c.budget
+}
+
+// doRequest performs an HTTP request with authentication and error handling. Error
+// responses come back as *APIError. Failed attempts are retried as the client's
+// RetryPolicy allows; while the circuit breaker is open nothing is sent.
 func (c *Legion) doRequest(ctx context.Context, method, path string, body interface{}) (*http.Response, error) {
+	if c.budget != nil && !c.budget.Allow(priorityFrom(ctx)) {
+		clientLog.Debugf("%s %s shed by API budget", method, path)
+		return nil, ErrBudgetExceeded
+	}
+
 	// Build the full URL
 	fullURL := c.baseURL + path
 
 	// Marshal body if provided
-	var bodyReader io.Reader
+	var jsonData []byte
 	if body != nil {
-		jsonData, err := json.Marshal(body)
+		var err error
+		jsonData, err = json.Marshal(body)
 		if err != nil {
 			return nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
+	}
+
+	probe := false
+	if c.breaker != nil {
+		var ok bool
+		if ok, probe = c.breaker.allow(time.Now()); !ok {
+			return nil, ErrCircuitOpen
+		}
+	}
+	resp, status, err := c.attempt(ctx, method, fullURL, path, jsonData)
+	if c.breaker != nil {
+		c.breaker.record(classify(ctx, status, err), probe, time.Now())
+	}
+	return resp, err
+}
+
+// attempt sends a request, retrying failures as the client's RetryPolicy allows. It
+// returns the last attempt's status, 0 when no response came back.
+func (c *Legion) attempt(ctx context.Context, method, fullURL, path string, jsonData []byte) (*http.Response, int, error) {
+	key := idempotencyKeyFrom(ctx)
+	repeatable := key != "" || repeatableMethod(method)
+	for attempt := 1; ; attempt++ {
+		if c.limiter != nil {
+			if err := c.limiter.Wait(ctx); err != nil {
+				return nil, 0, fmt.Errorf("waiting for rate limiter: %w", err)
+			}
+		}
+		resp, err := c.send(ctx, method, fullURL, path, jsonData, key, attempt)
+		var hookErr *requestHookError
+		if errors.As(err, &hookErr) {
+			return nil, 0, err
+		}
+		var apiErr *APIError
+		status := 0
+		if err == nil {
+			if resp.StatusCode < 400 {
+				return resp, resp.StatusCode, nil
+			}
+
+			// Check for HTTP errors
+			bodyBytes, _ := io.ReadAll(resp.Body)
+			if err := resp.Body.Close(); err != nil {
+				clientLog.Errorf("failed to close response body: %v", err)
+			}
+			apiErr = newAPIError(resp, bodyBytes)
+			status, err = resp.StatusCode, apiErr
+		}
+		if attempt >= c.retry.MaxAttempts || ctx.Err() != nil || !c.retry.retryable(status, repeatable) {
+			return nil, status, err
+		}
+
+		delay := c.retry.delay(apiErr, attempt-1)
+		clientLog.Debugf("%s %s failed (%v); retrying in %s", method, path, err, delay.Round(time.Millisecond))
+		select {
+		case <-ctx.Done():
+			return nil, status, err
+		case <-time.After(delay):
+		}
+	}
+}
+
+// send makes a single attempt at a request, running the client's hooks around it
+func (c *Legion) send(ctx context.Context, method, fullURL, path string, jsonData []byte, idempotencyKey string, attempt int) (*http.Response, error) {
+	var bodyReader io.Reader
+	if jsonData != nil {
 		bodyReader = bytes.NewReader(jsonData)
 	}
 
@@ -96,6 +200,9 @@ func (c *Legion) doRequest(ctx context.Context, method, path string, body interf
 	if orgID, ok := ctx.Value(OrgIDContextKey).(string); ok && orgID != "" {
 		req.Header.Set("X-ORG-ID", orgID)
 	}
+	if idempotencyKey != "" {
+		req.Header.Set(IdempotencyHeader, idempotencyKey)
+	}
 
 	// Set authorization header
 	if c.tokenManager != nil {
@@ -110,24 +217,31 @@ func (c *Legion) doRequest(ctx context.Context, method, path string, body interf
 		req.Header.Set("Authorization", "Bearer "+c.apiKey)
 	}
 
+	for _, hook := range c.requestHooks {
+		if err := hook(req); err != nil {
+			return nil, &requestHookError{err: err}
+		}
+	}
+
+	if c.logBodies {
+		logRequest(req, jsonData)
+	}
+
 	// Perform the request
+	started := time.Now()
 	resp, err := c.httpClient.Do(req)
+	took := time.Since(started)
+	for _, hook := range c.responseHooks {
+		hook(Exchange{Request: req, Response: resp, Err: err, Attempt: attempt, Duration: took})
+	}
 	if err != nil {
+		clientLog.Debugf("%s %s failed after %s: %v", method, path, took.Round(time.Millisecond), err)
 		return nil, fmt.Errorf("request failed: %w", err)
 	}
-
-	// Check for HTTP errors
-	if resp.StatusCode >= 400 {
-		defer func(Body io.ReadCloser) {
-			err := Body.Close()
-			if err != nil {
-				logger.Errorf("failed to close response body: %v", err)
-			}
-		}(resp.Body)
-		bodyBytes, _ := io.ReadAll(resp.Body)
-		return nil, fmt.Errorf("HTTP %d: %s", resp.StatusCode, string(bodyBytes))
+	clientLog.Debugf("%s %s -> %d (%s)", method, path, resp.StatusCode, took.Round(time.Millisecond))
+	if c.logBodies {
+		logResponse(req, resp)
 	}
-
 	return resp, nil
 }
 
@@ -136,7 +250,7 @@ func decodeResponse(resp *http.Response, v interface{}) error {
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
 		if err != nil {
-			logger.Errorf("failed to close response body: %v", err)
+			clientLog.Errorf("failed to close response body: %v", err)
 		}
 	}(resp.Body)
 
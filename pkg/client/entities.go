@@ -2,18 +2,20 @@ package client
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sync"
 
 	openapi_types "github.com/oapi-codegen/runtime/types"
 
 	"github.com/google/uuid"
-	"github.com/picogrid/legion-simulations/pkg/logger"
 	"github.com/picogrid/legion-simulations/pkg/models"
 )
 
-// CreateEntity creates a new entity in Legion
+// CreateEntity creates a new entity in Legion. A key set with WithIdempotencyKey makes
+// a retry after a timeout return the first entity instead of creating another.
 func (c *Legion) CreateEntity(ctx context.Context, req *models.CreateEntityRequest) (*models.EntityResponse, error) {
 	body, err := toCreateEntityRequest(req)
 	if err != nil {
@@ -25,22 +27,99 @@ func (c *Legion) CreateEntity(ctx context.Context, req *models.CreateEntityReque
 		return nil, fmt.Errorf("failed to create entity: %w", err)
 	}
 
+	var entity *models.EntityResponse
 	switch resp.StatusCode {
 	case http.StatusOK:
 		var raw models.PostV3Entities200Response
 		if err := decodeResponse(resp, &raw); err != nil {
 			return nil, fmt.Errorf("failed to decode entity response: %w", err)
 		}
-		return fromEntityResponse200(raw)
+		entity, err = fromEntityResponse200(raw)
 	case http.StatusCreated:
 		var raw models.PostV3Entities201Response
 		if err := decodeResponse(resp, &raw); err != nil {
 			return nil, fmt.Errorf("failed to decode entity response: %w", err)
 		}
-		return fromEntityResponse201(raw)
+		entity, err = fromEntityResponse201(raw)
 	default:
 		return nil, fmt.Errorf("unexpected create entity status: %d", resp.StatusCode)
 	}
+	if err == nil && c.recorder != nil {
+		c.recorder.EntityCreated(entity, req)
+	}
+	return entity, err
+}
+
+// DefaultCreateConcurrency is how many creates CreateEntities keeps in flight when the
+// caller doesn't say
+const DefaultCreateConcurrency = 16
+
+// CreateEntities creates many entities with up to concurrency requests in flight, since
+// Legion has no bulk create. Results are in request order. After the first failure no
+// more creates are started and that error is returned; entities created before then are
+// still in the results, nil elsewhere, so the caller can clean them up. A key set with
+// WithIdempotencyKey is combined with each entity's name so every create is keyed.
+func (c *Legion) CreateEntities(ctx context.Context, reqs []*models.CreateEntityRequest, concurrency int) ([]*models.EntityResponse, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultCreateConcurrency
+	}
+	key := idempotencyKeyFrom(ctx)
+
+	entities := make([]*models.EntityResponse, len(reqs))
+	var (
+		mu       sync.Mutex
+		firstErr error
+		wg       sync.WaitGroup
+	)
+	failed := func() bool {
+		mu.Lock()
+		defer mu.Unlock()
+		return firstErr != nil
+	}
+
+	slots := make(chan struct{}, concurrency)
+	cancelled := false
+	for i, req := range reqs {
+		slots <- struct{}{}
+		if failed() {
+			break
+		}
+		if ctx.Err() != nil {
+			cancelled = true
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, req *models.CreateEntityRequest) {
+			defer wg.Done()
+			defer func() { <-slots }()
+
+			reqCtx := ctx
+			name := ""
+			if req.Name != nil {
+				name = *req.Name
+			}
+			if key != "" {
+				reqCtx = WithIdempotencyKey(ctx, IdempotencyKey(key, name))
+			}
+			entity, err := c.CreateEntity(reqCtx, req)
+			if err != nil {
+				mu.Lock()
+				if firstErr == nil {
+					firstErr = fmt.Errorf("entity %s: %w", name, err)
+				}
+				mu.Unlock()
+				return
+			}
+			entities[i] = entity
+		}(i, req)
+	}
+	wg.Wait()
+
+	if firstErr == nil && cancelled {
+		return entities, fmt.Errorf("failed to create entities: %w", ctx.Err())
+	}
+	return entities, firstErr
 }
 
 // GetEntity retrieves an entity by ID
@@ -80,6 +159,38 @@ func (c *Legion) UpdateEntity(ctx context.Context, entityID string, req *models.
 	return fromPutEntityResponse(raw)
 }
 
+// PatchEntity applies a partial update to an entity, changing only the fields set on the
+// patch. Use it for affiliation changes, renames and type corrections instead of
+// deleting and recreating the entity.
+func (c *Legion) PatchEntity(ctx context.Context, entityID string, patch *models.EntityPatch) (*models.EntityResponse, error) {
+	body, err := toPatchEntityRequest(patch)
+	if err != nil {
+		return nil, fmt.Errorf("build patch entity request: %w", err)
+	}
+
+	// Status changes are never shed; metadata-only patches can be
+	if patch.Status == nil {
+		ctx = withDefaultPriority(ctx, PriorityNormal)
+	}
+
+	// The API accepts partial bodies on PUT; omitted fields are left as they are
+	path := fmt.Sprintf("/v3/entities/%s", entityID)
+	resp, err := c.doRequest(ctx, http.MethodPut, path, body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to patch entity: %w", err)
+	}
+
+	var raw models.PutV3EntitiesbyEntityId200Response
+	if err := decodeResponse(resp, &raw); err != nil {
+		return nil, fmt.Errorf("failed to decode entity response: %w", err)
+	}
+
+	if c.recorder != nil {
+		c.recorder.EntityPatched(entityID, patch)
+	}
+	return fromPutEntityResponse(raw)
+}
+
 // DeleteEntity deletes an entity by ID
 func (c *Legion) DeleteEntity(ctx context.Context, entityID string) error {
 	path := fmt.Sprintf("/v3/entities/%s", entityID)
@@ -89,21 +200,89 @@ func (c *Legion) DeleteEntity(ctx context.Context, entityID string) error {
 	}
 	defer func(body io.ReadCloser) {
 		if closeErr := body.Close(); closeErr != nil {
-			logger.Errorf("failed to close response body: %v", closeErr)
+			clientLog.Errorf("failed to close response body: %v", closeErr)
 		}
 	}(resp.Body)
 
+	if c.recorder != nil {
+		c.recorder.EntityDeleted(entityID)
+	}
 	return nil
 }
 
-// SearchEntities searches for entities based on the provided criteria
+// searchPageSize is how many entities each page of a search asks for
+const searchPageSize = 500
+
+// searchMaxPages caps a single search at 100k entities
+var searchMaxPages = 200
+
+// ErrSearchTruncated is returned when a search has more results than ForEachEntity will
+// page through, so the entities seen are only part of them
+var ErrSearchTruncated = errors.New("entity search truncated")
+
+// SearchEntities searches for entities based on the provided criteria. Only the first
+// page of results is returned; ForEachEntity visits them all.
 func (c *Legion) SearchEntities(ctx context.Context, req *models.SearchEntitiesRequest) (*models.EntityPaginatedResponse, error) {
+	return c.searchEntities(ctx, req, "/v3/entities/search")
+}
+
+// ForEachEntity calls fn with every entity matching req, paging through the results,
+// until fn returns false. Without a sort in req, results come oldest first so pages stay
+// stable while entities are created; an entity moved between pages is visited once.
+// Collect entities before deleting them, since deletions shift later pages. A search
+// with more than 100k results stops there with ErrSearchTruncated.
+func (c *Legion) ForEachEntity(ctx context.Context, req *models.SearchEntitiesRequest, fn func(models.EntityResponse) bool) error {
+	search := models.SearchEntitiesRequest{}
+	if req != nil {
+		search = *req
+	}
+	if len(search.Sort) == 0 {
+		search.Sort = []models.SortFieldSpec{{Field: "created_at", Order: "asc"}}
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	offset := 0
+	for page := 0; page < searchMaxPages; page++ {
+		path := fmt.Sprintf("/v3/entities/search?limit=%d&offset=%d", searchPageSize, offset)
+		result, err := c.searchEntities(ctx, &search, path)
+		if err != nil {
+			return err
+		}
+		for _, entity := range result.Results {
+			if seen[entity.ID] {
+				continue
+			}
+			seen[entity.ID] = true
+			if !fn(entity) {
+				return nil
+			}
+		}
+		offset += len(result.Results)
+		if len(result.Results) < searchPageSize || offset >= result.TotalCount {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w after %d results; narrow the search to see the rest", ErrSearchTruncated, offset)
+}
+
+// SearchAllEntities returns every entity matching req, across all pages. On an error
+// the entities collected so far are returned with it.
+func (c *Legion) SearchAllEntities(ctx context.Context, req *models.SearchEntitiesRequest) ([]models.EntityResponse, error) {
+	var entities []models.EntityResponse
+	err := c.ForEachEntity(ctx, req, func(entity models.EntityResponse) bool {
+		entities = append(entities, entity)
+		return true
+	})
+	return entities, err
+}
+
+func (c *Legion) searchEntities(ctx context.Context, req *models.SearchEntitiesRequest, path string) (*models.EntityPaginatedResponse, error) {
 	body, err := toSearchEntitiesRequest(req)
 	if err != nil {
 		return nil, fmt.Errorf("build search entities request: %w", err)
 	}
 
-	resp, err := c.doRequest(ctx, http.MethodPost, "/v3/entities/search", body)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search entities: %w", err)
 	}
@@ -233,6 +412,40 @@ func toUpdateEntityRequest(req *models.UpdateEntityRequest) (*models.PutV3Entiti
 	return body, nil
 }
 
+func toPatchEntityRequest(patch *models.EntityPatch) (*models.PutV3EntitiesbyEntityIdRequest, error) {
+	if patch == nil || patch.IsEmpty() {
+		return nil, fmt.Errorf("entity patch is empty")
+	}
+
+	metadata, err := rawMessageToMap(patch.Metadata)
+	if err != nil {
+		return nil, err
+	}
+	classification, err := rawMessageToMap(patch.Classification)
+	if err != nil {
+		return nil, err
+	}
+
+	body := &models.PutV3EntitiesbyEntityIdRequest{
+		Classification: classification,
+		Metadata:       metadata,
+		Name:           patch.Name,
+		ParentId:       toOpenapiUUIDPtr(patch.ParentID),
+		Status:         patch.Status,
+		Type:           patch.Type,
+	}
+	if patch.Affiliation != nil {
+		affiliation := models.PutV3EntitiesbyEntityIdRequestAffiliation(*patch.Affiliation)
+		body.Affiliation = &affiliation
+	}
+	if patch.Category != nil {
+		category := models.PutV3EntitiesbyEntityIdRequestCategory(*patch.Category)
+		body.Category = &category
+	}
+
+	return body, nil
+}
+
 func toSearchEntitiesRequest(req *models.SearchEntitiesRequest) (*models.PostV3EntitiesSearchRequest, error) {
 	if req == nil {
 		return &models.PostV3EntitiesSearchRequest{}, nil
@@ -0,0 +1,116 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestToPatchEntityRequestOmitsUnsetFields(t *testing.T) {
+	affiliation := models.AffiliationHOSTILE
+	body, err := toPatchEntityRequest(&models.EntityPatch{Affiliation: &affiliation})
+	if err != nil {
+		t.Fatalf("toPatchEntityRequest: %v", err)
+	}
+
+	data, err := json.Marshal(body)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != `{"affiliation":"HOSTILE"}` {
+		t.Fatalf("expected only affiliation in body, got %s", data)
+	}
+
+	if _, err := toPatchEntityRequest(&models.EntityPatch{}); err == nil {
+		t.Fatal("expected an error for an empty patch")
+	}
+}
+
+func TestCreateEntitiesKeepsRequestOrder(t *testing.T) {
+	legion, memory := NewMemoryClient()
+	orgID := uuid.New()
+	ctx := WithOrgID(context.Background(), orgID.String())
+
+	category, entityType, status := models.CategoryTRACK, "UAS", "UNKNOWN"
+	reqs := make([]*models.CreateEntityRequest, 50)
+	for i := range reqs {
+		name := fmt.Sprintf("TRK-%03d", i)
+		reqs[i] = &models.CreateEntityRequest{
+			OrganizationID: &orgID,
+			Name:           &name,
+			Category:       &category,
+			Type:           &entityType,
+			Status:         &status,
+		}
+	}
+
+	entities, err := legion.CreateEntities(ctx, reqs, 4)
+	if err != nil {
+		t.Fatalf("CreateEntities: %v", err)
+	}
+	for i, entity := range entities {
+		if entity == nil || entity.Name != *reqs[i].Name {
+			t.Fatalf("result %d does not match request %s: %+v", i, *reqs[i].Name, entity)
+		}
+	}
+	if got := memory.Summary().Entities; got != len(reqs) {
+		t.Errorf("expected %d entities, got %d", len(reqs), got)
+	}
+}
+
+func TestForEachEntityPagesThroughAllResults(t *testing.T) {
+	legion, memory := NewMemoryClient()
+	orgID := uuid.New()
+	ctx := WithOrgID(context.Background(), orgID.String())
+
+	category, entityType, status := models.CategoryTRACK, "UAS", "UNKNOWN"
+	reqs := make([]*models.CreateEntityRequest, searchPageSize*2+10)
+	for i := range reqs {
+		name := fmt.Sprintf("TRK-%04d", i)
+		reqs[i] = &models.CreateEntityRequest{OrganizationID: &orgID, Name: &name, Category: &category, Type: &entityType, Status: &status}
+	}
+	if _, err := legion.CreateEntities(ctx, reqs, 0); err != nil {
+		t.Fatal(err)
+	}
+
+	search := &models.SearchEntitiesRequest{OrganizationID: &orgID, Filters: &models.SearchFilters{Name: "TRK-"}}
+	entities, err := legion.SearchAllEntities(ctx, search)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(entities) != len(reqs) {
+		t.Errorf("expected all %d entities, got %d", len(reqs), len(entities))
+	}
+	if got := memory.Summary().ByRoute["POST /v3/entities/search"]; got != 3 {
+		t.Errorf("expected 3 pages, got %d", got)
+	}
+
+	// Returning false stops the search without reading more pages
+	visited := 0
+	if err := legion.ForEachEntity(ctx, search, func(models.EntityResponse) bool {
+		visited++
+		return visited < 10
+	}); err != nil {
+		t.Fatal(err)
+	}
+	if visited != 10 || memory.Summary().ByRoute["POST /v3/entities/search"] != 4 {
+		t.Errorf("expected to stop after 10 entities on the first page, visited %d", visited)
+	}
+
+	// Past the page cap the search fails rather than passing for complete
+	defer func(pages int) { searchMaxPages = pages }(searchMaxPages)
+	searchMaxPages = 2
+	entities, err = legion.SearchAllEntities(ctx, search)
+	if !errors.Is(err, ErrSearchTruncated) {
+		t.Errorf("expected a truncated search, got %v", err)
+	}
+	if len(entities) != searchPageSize*2 {
+		t.Errorf("expected the %d entities read before the cap, got %d", searchPageSize*2, len(entities))
+	}
+}
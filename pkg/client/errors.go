@@ -0,0 +1,96 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Errors an APIError matches with errors.Is, by status code
+var (
+	ErrUnauthorized = errors.New("not authorized") // 401 or 403
+	ErrNotFound     = errors.New("not found")      // 404
+	ErrConflict     = errors.New("already exists") // 409
+	ErrRateLimited  = errors.New("rate limited")   // 429
+)
+
+// APIError is an error response from the Legion API
+type APIError struct {
+	StatusCode int
+	Message    string        // The API's message, or the raw body if it had none
+	RetryAfter time.Duration // From the Retry-After header
+
+	hasRetryAfter bool
+}
+
+// Error keeps the "HTTP <code>: <body>" form earlier versions returned
+func (e *APIError) Error() string {
+	return fmt.Sprintf("HTTP %d: %s", e.StatusCode, e.Message)
+}
+
+// Unwrap maps the status code to one of the package's sentinel errors
+func (e *APIError) Unwrap() error {
+	switch e.StatusCode {
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return ErrUnauthorized
+	case http.StatusNotFound:
+		return ErrNotFound
+	case http.StatusConflict:
+		return ErrConflict
+	case http.StatusTooManyRequests:
+		return ErrRateLimited
+	}
+	return nil
+}
+
+// newAPIError builds an APIError from a failed response and its body
+func newAPIError(resp *http.Response, body []byte) *APIError {
+	apiErr := &APIError{StatusCode: resp.StatusCode, Message: strings.TrimSpace(string(body))}
+
+	var decoded struct {
+		Message string `json:"message"`
+		Error   string `json:"error"`
+	}
+	if json.Unmarshal(body, &decoded) == nil {
+		if decoded.Message != "" {
+			apiErr.Message = decoded.Message
+		} else if decoded.Error != "" {
+			apiErr.Message = decoded.Error
+		}
+	}
+
+	if retryAfter := resp.Header.Get("Retry-After"); retryAfter != "" {
+		if seconds, err := strconv.Atoi(retryAfter); err == nil && seconds >= 0 {
+			apiErr.RetryAfter, apiErr.hasRetryAfter = time.Duration(seconds)*time.Second, true
+		} else if at, err := http.ParseTime(retryAfter); err == nil {
+			apiErr.RetryAfter, apiErr.hasRetryAfter = max(time.Until(at), 0), true
+		}
+	}
+	return apiErr
+}
+
+// Describe turns an API error into a message for the operator, with what to do about
+// it where that's clear. Other errors are returned as they are.
+func Describe(err error) string {
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) {
+		return err.Error()
+	}
+
+	switch {
+	case errors.Is(err, ErrUnauthorized):
+		return fmt.Sprintf("Legion rejected the credentials (HTTP %d: %s). Run 'legion-sim auth login' or check LEGION_API_KEY and the organization ID",
+			apiErr.StatusCode, apiErr.Message)
+	case errors.Is(err, ErrRateLimited):
+		return fmt.Sprintf("Legion is rate limiting requests (%s). Lower the update rate or set an API budget", apiErr.Message)
+	case errors.Is(err, ErrNotFound):
+		return fmt.Sprintf("not found in Legion: %s", apiErr.Message)
+	case errors.Is(err, ErrConflict):
+		return fmt.Sprintf("already exists in Legion: %s. Clean up the earlier run or use unique names", apiErr.Message)
+	}
+	return err.Error()
+}
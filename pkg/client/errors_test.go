@@ -0,0 +1,66 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestAPIErrorsMatchSentinels(t *testing.T) {
+	for status, want := range map[int]error{
+		http.StatusUnauthorized:    ErrUnauthorized,
+		http.StatusForbidden:       ErrUnauthorized,
+		http.StatusNotFound:        ErrNotFound,
+		http.StatusConflict:        ErrConflict,
+		http.StatusTooManyRequests: ErrRateLimited,
+	} {
+		err := error(&APIError{StatusCode: status, Message: "nope"})
+		if !errors.Is(err, want) {
+			t.Errorf("HTTP %d: expected %v", status, want)
+		}
+	}
+	if err := error(&APIError{StatusCode: http.StatusInternalServerError}); errors.Is(err, ErrNotFound) {
+		t.Error("HTTP 500 matched ErrNotFound")
+	}
+}
+
+func TestDoRequestRetriesRateLimits(t *testing.T) {
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		switch {
+		case calls == 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case r.URL.Path == "/v3/entities/missing":
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"message":"entity missing not found"}`))
+		default:
+			_, _ = w.Write([]byte(`{}`))
+		}
+	}))
+	defer server.Close()
+
+	legion, err := NewClient(Config{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = legion.GetEntity(context.Background(), "missing")
+	if calls != 2 {
+		t.Errorf("expected the rate-limited request to be retried once, got %d calls", calls)
+	}
+	if !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound through the wrapping, got %v", err)
+	}
+	var apiErr *APIError
+	if !errors.As(err, &apiErr) || apiErr.Message != "entity missing not found" {
+		t.Errorf("expected the API's message, got %+v", apiErr)
+	}
+	if msg := Describe(err); !strings.HasPrefix(msg, "not found in Legion") {
+		t.Errorf("unexpected description %q", msg)
+	}
+}
@@ -10,11 +10,11 @@ import (
 	openapi_types "github.com/oapi-codegen/runtime/types"
 
 	"github.com/google/uuid"
-	"github.com/picogrid/legion-simulations/pkg/logger"
 	"github.com/picogrid/legion-simulations/pkg/models"
 )
 
-// CreateFeedDefinition creates a new feed definition
+// CreateFeedDefinition creates a new feed definition. Set a key with WithIdempotencyKey
+// to make retries safe.
 func (c *Legion) CreateFeedDefinition(ctx context.Context, req *models.CreateFeedDefinitionRequest) (*models.FeedDefinitionResponse, error) {
 	body, err := toCreateFeedDefinitionRequest(req)
 	if err != nil {
@@ -80,7 +80,7 @@ func (c *Legion) DeleteFeedDefinition(ctx context.Context, feedID string) error
 	}
 	defer func(body io.ReadCloser) {
 		if closeErr := body.Close(); closeErr != nil {
-			logger.Errorf("failed to close response body: %v", closeErr)
+			clientLog.Errorf("failed to close response body: %v", closeErr)
 		}
 	}(resp.Body)
 
@@ -244,35 +244,36 @@ func (c *Legion) IngestServiceMessage(ctx context.Context, req *models.ServiceIn
 		return fmt.Errorf("build service message request: %w", err)
 	}
 
-	resp, err := c.doRequest(ctx, http.MethodPost, "/v3/feeds/messages", body)
+	resp, err := c.doRequest(withDefaultPriority(ctx, PriorityNormal), http.MethodPost, "/v3/feeds/messages", body)
 	if err != nil {
 		return fmt.Errorf("failed to ingest service message: %w", err)
 	}
 	defer func(body io.ReadCloser) {
 		if closeErr := body.Close(); closeErr != nil {
-			logger.Errorf("failed to close response body: %v", closeErr)
+			clientLog.Errorf("failed to close response body: %v", closeErr)
 		}
 	}(resp.Body)
 
 	return nil
 }
 
-// IngestFeedData ingests feed data using the standard ingestion endpoint
+// IngestFeedData ingests feed data using the standard ingestion endpoint. Set a key with
+// WithIdempotencyKey so a retried record isn't stored twice.
 func (c *Legion) IngestFeedData(ctx context.Context, req *models.IngestFeedDataRequest) error {
 	body, err := toFeedMessageRequest(req)
 	if err != nil {
 		return fmt.Errorf("build ingest feed data request: %w", err)
 	}
 
-	logger.Debugf("Ingesting feed data - Entity: %s, FeedDef: %s", body.EntityId, body.FeedDefinitionId)
+	clientLog.Debugf("Ingesting feed data - Entity: %s, FeedDef: %s", body.EntityId, body.FeedDefinitionId)
 
-	resp, err := c.doRequest(ctx, http.MethodPost, "/v3/feeds/messages", body)
+	resp, err := c.doRequest(withDefaultPriority(ctx, PriorityNormal), http.MethodPost, "/v3/feeds/messages", body)
 	if err != nil {
 		return fmt.Errorf("failed to ingest feed data: %w", err)
 	}
 	defer func(body io.ReadCloser) {
 		if closeErr := body.Close(); closeErr != nil {
-			logger.Errorf("failed to close response body: %v", closeErr)
+			clientLog.Errorf("failed to close response body: %v", closeErr)
 		}
 	}(resp.Body)
 
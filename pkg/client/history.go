@@ -0,0 +1,69 @@
+package client
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// Location history paging
+const (
+	historyPageSize = 500
+	historyMaxPages = 100 // Caps a single history query at 50k locations
+)
+
+// GetEntityLocationHistory returns every location recorded for an entity between since and
+// until (either may be nil), oldest first. Sources narrows the results to those writers.
+func (c *Legion) GetEntityLocationHistory(ctx context.Context, entityID uuid.UUID, since, until *time.Time, sources ...string) ([]models.EntityLocationResponse, error) {
+	req := &models.SearchEntityLocationsRequest{
+		EntityIDs:      []uuid.UUID{entityID},
+		RecordedAfter:  since,
+		RecordedBefore: until,
+		Sources:        sources,
+	}
+
+	var locations []models.EntityLocationResponse
+	for page := 0; page < historyMaxPages; page++ {
+		path := fmt.Sprintf("/v3/entities/locations/search?limit=%d&offset=%d", historyPageSize, len(locations))
+		resp, err := c.searchEntityLocations(ctx, req, path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get location history for %s: %w", entityID, err)
+		}
+		locations = append(locations, resp.Results...)
+		if len(resp.Results) < historyPageSize || len(locations) >= resp.TotalCount {
+			break
+		}
+	}
+
+	sort.SliceStable(locations, func(i, j int) bool {
+		return recordedTime(locations[i]).Before(recordedTime(locations[j]))
+	})
+	return locations, nil
+}
+
+// GetEntityHistory returns an entity's current record with its location history
+func (c *Legion) GetEntityHistory(ctx context.Context, entityID uuid.UUID, since, until *time.Time, sources ...string) (*models.EntityHistory, error) {
+	entity, err := c.GetEntity(ctx, entityID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get entity %s: %w", entityID, err)
+	}
+
+	locations, err := c.GetEntityLocationHistory(ctx, entityID, since, until, sources...)
+	if err != nil {
+		return nil, err
+	}
+	return &models.EntityHistory{Entity: entity, Locations: locations}, nil
+}
+
+// recordedTime is when a location was observed, falling back to when Legion stored it
+func recordedTime(location models.EntityLocationResponse) time.Time {
+	if location.RecordedAt != nil {
+		return *location.RecordedAt
+	}
+	return location.CreatedAt
+}
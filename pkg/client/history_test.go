@@ -0,0 +1,62 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+func TestGetEntityLocationHistoryPages(t *testing.T) {
+	entityID := uuid.New()
+	total := historyPageSize + 3
+	start := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		offset, _ := strconv.Atoi(r.URL.Query().Get("offset"))
+		limit, _ := strconv.Atoi(r.URL.Query().Get("limit"))
+
+		var results []map[string]interface{}
+		// Newest first, as the server is free to return them in any order
+		for i := total - 1 - offset; i >= 0 && len(results) < limit; i-- {
+			results = append(results, map[string]interface{}{
+				"id":          uuid.New(),
+				"entity_id":   entityID,
+				"created_at":  start.Format(time.RFC3339),
+				"recorded_at": start.Add(time.Duration(i) * time.Second).Format(time.RFC3339),
+				"source":      "test",
+				"position":    map[string]interface{}{"type": "Point", "coordinates": []float32{0, 0, float32(i)}},
+			})
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{
+			"crs":         "EPSG:4978",
+			"paging":      map[string]interface{}{},
+			"results":     results,
+			"total_count": total,
+		})
+	}))
+	defer server.Close()
+
+	legion, err := NewClient(Config{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	locations, err := legion.GetEntityLocationHistory(context.Background(), entityID, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locations) != total {
+		t.Fatalf("expected %d locations across pages, got %d", total, len(locations))
+	}
+	for i, location := range locations {
+		if got := location.Position.Coordinates[2]; got != float64(i) {
+			t.Fatalf("locations not oldest first: index %d has %v", i, got)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestHook runs on every attempt at a request just before it is sent, with the
+// client's own headers already set. It may add headers, e.g. a correlation ID taken from
+// req.Context(). An error stops the attempt and is returned to the caller unretried.
+type RequestHook func(req *http.Request) error
+
+// ResponseHook runs after every attempt at a request, for logging or metrics. The
+// response body belongs to the caller and must not be read.
+type ResponseHook func(e Exchange)
+
+// Exchange is one attempt at a request and its outcome
+type Exchange struct {
+	Request  *http.Request
+	Response *http.Response // Nil when the request failed without a response
+	Err      error          // Transport failure, if any; error statuses come with a Response
+	Attempt  int            // 1 for the first try, counting retries after it
+	Duration time.Duration  // From sending the request to receiving response headers
+}
+
+// OnRequest adds a hook run before each request attempt. Hooks run in the order they
+// were added; add them before the client is shared between goroutines.
+func (c *Legion) OnRequest(h RequestHook) {
+	c.requestHooks = append(c.requestHooks, h)
+}
+
+// OnResponse adds a hook run after each request attempt. Hooks run in the order they
+// were added; add them before the client is shared between goroutines.
+func (c *Legion) OnResponse(h ResponseHook) {
+	c.responseHooks = append(c.responseHooks, h)
+}
+
+// SetHeader returns a hook that sets a header on every request, replacing any value
+// already set
+func SetHeader(name, value string) RequestHook {
+	return func(req *http.Request) error {
+		req.Header.Set(name, value)
+		return nil
+	}
+}
+
+// requestHookError marks a failure from a RequestHook so it isn't retried
+type requestHookError struct {
+	err error
+}
+
+func (e *requestHookError) Error() string {
+	return "request hook: " + e.err.Error()
+}
+
+func (e *requestHookError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,58 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestHooksRunOnEveryAttempt(t *testing.T) {
+	calls := 0
+	var correlationIDs []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		correlationIDs = append(correlationIDs, r.Header.Get("X-Correlation-ID"))
+		if calls == 1 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"id":"8c3f3b0e-8d0a-4a8e-9a8e-3c2d1b0a9f8e"}`))
+	}))
+	defer server.Close()
+
+	policy := DefaultRetryPolicy()
+	policy.BaseDelay, policy.Jitter = time.Millisecond, 0
+	legion, err := NewClient(Config{BaseURL: server.URL, Retry: &policy})
+	if err != nil {
+		t.Fatal(err)
+	}
+	legion.OnRequest(SetHeader("X-Correlation-ID", "run-1"))
+	var exchanges []Exchange
+	legion.OnResponse(func(e Exchange) { exchanges = append(exchanges, e) })
+
+	if _, err := legion.GetMe(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if len(correlationIDs) != 2 || correlationIDs[0] != "run-1" || correlationIDs[1] != "run-1" {
+		t.Errorf("expected the header on both attempts, got %q", correlationIDs)
+	}
+	if len(exchanges) != 2 || exchanges[0].Response.StatusCode != http.StatusServiceUnavailable ||
+		exchanges[1].Attempt != 2 || exchanges[1].Response.StatusCode != http.StatusOK {
+		t.Errorf("unexpected exchanges: %+v", exchanges)
+	}
+
+	// A failing request hook stops the request before it is sent and isn't retried
+	errRefused := errors.New("refused")
+	legion.OnRequest(func(*http.Request) error { return errRefused })
+	calls = 0
+	if _, err := legion.GetMe(context.Background()); !errors.Is(err, errRefused) {
+		t.Errorf("expected the hook's error, got %v", err)
+	}
+	if calls != 0 {
+		t.Errorf("expected no request to be sent, got %d", calls)
+	}
+}
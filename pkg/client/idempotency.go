@@ -0,0 +1,33 @@
+package client
+
+import (
+	"context"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// IdempotencyHeader carries the key Legion uses to recognize a repeated request
+const IdempotencyHeader = "Idempotency-Key"
+
+// idempotencyNamespace scopes derived keys so they can't collide with other UUIDs
+var idempotencyNamespace = uuid.MustParse("6f1d3c52-5b8e-4a7e-9d2f-3c4b1a0e8f71")
+
+type idempotencyContextKey struct{}
+
+// WithIdempotencyKey attaches key to requests made with ctx. Use it for creates and
+// ingests that must not be applied twice when a timed-out request is retried.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyContextKey{}, key)
+}
+
+// IdempotencyKey derives a stable key from its parts, e.g. a run ID and an entity name,
+// so the same logical request always carries the same key
+func IdempotencyKey(parts ...string) string {
+	return uuid.NewSHA1(idempotencyNamespace, []byte(strings.Join(parts, "\x00"))).String()
+}
+
+func idempotencyKeyFrom(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyContextKey{}).(string)
+	return key
+}
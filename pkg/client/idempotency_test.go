@@ -0,0 +1,75 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestIdempotentRequestResentAfterDroppedConnection(t *testing.T) {
+	var (
+		keys []string
+		mu   sync.Mutex
+	)
+	seen := func() []string {
+		mu.Lock()
+		defer mu.Unlock()
+		return append([]string(nil), keys...)
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mu.Lock()
+		keys = append(keys, r.Header.Get(IdempotencyHeader))
+		first := len(keys) == 1
+		mu.Unlock()
+		if first {
+			// Drop the connection as if the response timed out
+			conn, _, err := w.(http.Hijacker).Hijack()
+			if err == nil {
+				_ = conn.Close()
+			}
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	legion, err := NewClient(Config{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	entityID, feedID, recordedAt := uuid.New(), uuid.New(), time.Now()
+	payload := json.RawMessage(`{}`)
+	req := &models.IngestFeedDataRequest{EntityID: &entityID, FeedDefinitionID: &feedID, RecordedAt: &recordedAt, Payload: &payload}
+
+	key := IdempotencyKey("run-1", "ingest", "feed-1")
+	if key != IdempotencyKey("run-1", "ingest", "feed-1") || key == IdempotencyKey("run-2", "ingest", "feed-1") {
+		t.Fatal("expected keys to be stable per run and distinct across runs")
+	}
+
+	ctx := WithIdempotencyKey(context.Background(), key)
+	if err := legion.IngestFeedData(ctx, req); err != nil {
+		t.Fatalf("expected the keyed request to succeed on resend, got %v", err)
+	}
+	if got := seen(); len(got) != 2 || got[0] != key || got[1] != key {
+		t.Errorf("expected two attempts with key %s, got %v", key, got)
+	}
+
+	// Without a key the request isn't resent
+	mu.Lock()
+	keys = nil
+	mu.Unlock()
+	if err := legion.IngestFeedData(context.Background(), req); err == nil {
+		t.Error("expected an unkeyed request to fail on a dropped connection")
+	}
+	if got := seen(); len(got) != 1 || got[0] != "" {
+		t.Errorf("expected one unkeyed attempt, got %v", got)
+	}
+}
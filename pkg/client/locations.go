@@ -4,21 +4,31 @@ import (
 	"context"
 	"fmt"
 	"net/http"
+	"reflect"
+	"sync"
+	"time"
 
 	openapi_types "github.com/oapi-codegen/runtime/types"
 
 	"github.com/picogrid/legion-simulations/pkg/models"
 )
 
-// CreateEntityLocation creates a new location for an entity
+// CreateEntityLocation creates a new location for an entity. Unless ctx already carries
+// an idempotency key, a location with RecordedAt is keyed by entity and time so it can
+// be retried after a timeout or server error.
 func (c *Legion) CreateEntityLocation(ctx context.Context, entityID string, req *models.CreateEntityLocationRequest) (*models.EntityLocationResponse, error) {
 	body, err := toCreateEntityLocationRequest(req)
 	if err != nil {
 		return nil, fmt.Errorf("build entity location request: %w", err)
 	}
 
+	// A location is a sample at a time, so one keyed by entity and time is safe to resend
+	if idempotencyKeyFrom(ctx) == "" && req.RecordedAt != nil {
+		ctx = WithIdempotencyKey(ctx, IdempotencyKey("location", entityID, req.RecordedAt.Format(time.RFC3339Nano)))
+	}
+
 	path := fmt.Sprintf("/v3/entities/%s/locations", entityID)
-	resp, err := c.doRequest(ctx, http.MethodPost, path, body)
+	resp, err := c.doRequest(withDefaultPriority(ctx, PriorityPosition), http.MethodPost, path, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create entity location: %w", err)
 	}
@@ -28,9 +38,56 @@ func (c *Legion) CreateEntityLocation(ctx context.Context, entityID string, req
 		return nil, fmt.Errorf("failed to decode location response: %w", err)
 	}
 
+	if c.recorder != nil {
+		c.recorder.LocationCreated(entityID, req)
+	}
 	return fromLocation201(raw)
 }
 
+// DefaultLocationConcurrency is how many locations CreateEntityLocations keeps in flight
+// when the caller doesn't say
+const DefaultLocationConcurrency = 32
+
+// EntityLocation is one location for CreateEntityLocations and the entity it belongs to
+type EntityLocation struct {
+	EntityID string
+	Location *models.CreateEntityLocationRequest
+}
+
+// CreateEntityLocations writes many locations with up to concurrency requests in flight.
+// Legion takes one location per call, so the batch is pipelined over the client's pooled
+// connections instead of sent as one request; raise Config.Transport's idle connections
+// per host to match concurrency on HTTP/1.1. Every location is attempted, as they don't
+// depend on each other: results and errs are in request order, with errs[i] nil when
+// locations[i] was written. Locations not started before ctx ends fail with its error.
+func (c *Legion) CreateEntityLocations(ctx context.Context, locations []EntityLocation, concurrency int) ([]*models.EntityLocationResponse, []error) {
+	if concurrency <= 0 {
+		concurrency = DefaultLocationConcurrency
+	}
+
+	results := make([]*models.EntityLocationResponse, len(locations))
+	errs := make([]error, len(locations))
+	var wg sync.WaitGroup
+	slots := make(chan struct{}, concurrency)
+	for i, location := range locations {
+		select {
+		case <-ctx.Done():
+			errs[i] = fmt.Errorf("failed to create entity location: %w", ctx.Err())
+			continue
+		case slots <- struct{}{}:
+		}
+
+		wg.Add(1)
+		go func(i int, location EntityLocation) {
+			defer wg.Done()
+			defer func() { <-slots }()
+			results[i], errs[i] = c.CreateEntityLocation(ctx, location.EntityID, location.Location)
+		}(i, location)
+	}
+	wg.Wait()
+	return results, errs
+}
+
 // GetEntityLocation gets a specific location for an entity
 func (c *Legion) GetEntityLocation(ctx context.Context, entityID, locationID string) (*models.EntityLocationResponse, error) {
 	path := fmt.Sprintf("/v3/entities/%s/locations/%s", entityID, locationID)
@@ -96,6 +153,11 @@ func (c *Legion) GetEntityLocations(ctx context.Context, entityID string) (*mode
 
 // SearchEntityLocations searches for entity locations based on criteria
 func (c *Legion) SearchEntityLocations(ctx context.Context, req *models.SearchEntityLocationsRequest) (*models.EntityLocationPaginatedResponse, error) {
+	return c.searchEntityLocations(ctx, req, "/v3/entities/locations/search")
+}
+
+// searchEntityLocations posts a location search to path, which may carry paging parameters
+func (c *Legion) searchEntityLocations(ctx context.Context, req *models.SearchEntityLocationsRequest, path string) (*models.EntityLocationPaginatedResponse, error) {
 	body := &models.PostV3EntitiesLocationsSearchRequest{}
 	if req != nil {
 		filters := &struct {
@@ -128,7 +190,7 @@ func (c *Legion) SearchEntityLocations(ctx context.Context, req *models.SearchEn
 		body.Filters = filters
 	}
 
-	resp, err := c.doRequest(ctx, http.MethodPost, "/v3/entities/locations/search", body)
+	resp, err := c.doRequest(ctx, http.MethodPost, path, body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to search entity locations: %w", err)
 	}
@@ -299,6 +361,11 @@ func locationFromParts(
 }
 
 func entityFromAnonymous(raw interface{}) (*models.EntityResponse, error) {
+	// Unhydrated locations carry a typed nil pointer
+	if v := reflect.ValueOf(raw); v.Kind() == reflect.Ptr && v.IsNil() {
+		return nil, fmt.Errorf("entity not included")
+	}
+
 	switch entity := raw.(type) {
 	case *struct {
 		Affiliation    models.GetV3EntitiesbyEntityIdLocations200ResponseResultsEntityAffiliation `json:"affiliation"`
@@ -0,0 +1,417 @@
+package client
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryBaseURL is the base URL of a client answered by Memory; nothing listens there
+const memoryBaseURL = "memory://legion"
+
+// Memory is an in-memory stand-in for the Legion API, for dry runs that exercise a
+// simulation without network access or API quota. It keeps the entities, locations and
+// feed definitions written to it so later reads and searches see them, counts feed
+// messages without keeping them, and answers requests it doesn't model with 501. Every
+// call is recorded, so tests can assert on what a simulation sent.
+type Memory struct {
+	mu          sync.Mutex
+	entities    map[string]memoryRecord
+	entityOrder []string                  // Entity IDs in creation order, so searches are stable
+	locations   map[string][]memoryRecord // By entity ID, in creation order
+	feeds       map[string]memoryRecord
+	feedOrder   []string
+	messages    int
+	requests    map[string]int // By method and route, with IDs elided
+	calls       []MemoryCall
+}
+
+// MemoryCall is one request a Memory answered
+type MemoryCall struct {
+	Method string
+	Route  string          // e.g. "/v3/entities/{id}/locations"
+	Path   string          // With IDs, e.g. "/v3/entities/6f1c.../locations"
+	Body   json.RawMessage // Nil when the request had none
+	Status int
+}
+
+// memoryRecord is a stored resource as the API would return it
+type memoryRecord map[string]interface{}
+
+// MemorySummary describes what a Memory was sent
+type MemorySummary struct {
+	Requests        int            `json:"requests"`
+	ByRoute         map[string]int `json:"by_route"` // e.g. "POST /v3/entities/{id}/locations"
+	Entities        int            `json:"entities"` // Still present, i.e. created and not deleted
+	Locations       int            `json:"locations"`
+	FeedDefinitions int            `json:"feed_definitions"`
+	FeedMessages    int            `json:"feed_messages"`
+}
+
+// NewMemoryClient returns a client whose requests are answered by a new Memory instead of
+// a Legion server. Budgets and recorders set on the client apply as usual.
+func NewMemoryClient() (*Legion, *Memory) {
+	m := &Memory{
+		entities:  make(map[string]memoryRecord),
+		locations: make(map[string][]memoryRecord),
+		feeds:     make(map[string]memoryRecord),
+		requests:  make(map[string]int),
+	}
+	return &Legion{baseURL: memoryBaseURL, httpClient: &http.Client{Transport: m}, retry: DefaultRetryPolicy()}, m
+}
+
+// Summary counts the requests answered and what is held
+func (m *Memory) Summary() MemorySummary {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	summary := MemorySummary{
+		ByRoute:         make(map[string]int, len(m.requests)),
+		Entities:        len(m.entities),
+		FeedDefinitions: len(m.feeds),
+		FeedMessages:    m.messages,
+	}
+	for route, n := range m.requests {
+		summary.ByRoute[route] = n
+		summary.Requests += n
+	}
+	for _, locations := range m.locations {
+		summary.Locations += len(locations)
+	}
+	return summary
+}
+
+// Calls returns the requests answered so far, oldest first
+func (m *Memory) Calls() []MemoryCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MemoryCall(nil), m.calls...)
+}
+
+// CallsTo returns the requests answered so far for one method and route, e.g.
+// CallsTo("POST", "/v3/entities/{id}/locations"), oldest first
+func (m *Memory) CallsTo(method, route string) []MemoryCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var calls []MemoryCall
+	for _, call := range m.calls {
+		if call.Method == method && call.Route == route {
+			calls = append(calls, call)
+		}
+	}
+	return calls
+}
+
+// RoundTrip answers a request from memory
+func (m *Memory) RoundTrip(req *http.Request) (*http.Response, error) {
+	var data []byte
+	if req.Body != nil {
+		var err error
+		data, err = io.ReadAll(req.Body)
+		_ = req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	parts := strings.Split(strings.Trim(strings.TrimPrefix(req.URL.Path, "/v3"), "/"), "/")
+	route := make([]string, len(parts))
+	for i, part := range parts {
+		if _, err := uuid.Parse(part); err == nil {
+			part = "{id}"
+		}
+		route[i] = part
+	}
+
+	call := MemoryCall{Method: req.Method, Route: "/v3/" + strings.Join(route, "/"), Path: req.URL.Path}
+	if len(data) > 0 {
+		call.Body = json.RawMessage(data)
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.requests[req.Method+" "+call.Route]++
+
+	var body memoryRecord
+	if len(data) > 0 {
+		if err := json.Unmarshal(data, &body); err != nil {
+			call.Status = http.StatusBadRequest
+			m.calls = append(m.calls, call)
+			return memoryResponse(req, http.StatusBadRequest, memoryRecord{"message": err.Error()}), nil
+		}
+	}
+	resp := m.answer(req, parts, route, body)
+	call.Status = resp.StatusCode
+	m.calls = append(m.calls, call)
+	return resp, nil
+}
+
+// answer serves a request to route, whose parts are the path below /v3 with IDs elided
+func (m *Memory) answer(req *http.Request, parts, route []string, body memoryRecord) *http.Response {
+	now := time.Now().UTC().Format(time.RFC3339)
+	query := req.URL.Query()
+	switch key := req.Method + " " + strings.Join(route, "/"); key {
+	case "POST entities":
+		return m.createEntity(req, body, now)
+	case "POST entities/search":
+		return m.searchEntities(req, body, query)
+	case "GET entities/{id}", "PUT entities/{id}", "DELETE entities/{id}":
+		return m.entity(req, parts[1], body, now)
+	case "POST entities/{id}/locations":
+		return m.createLocation(req, parts[1], body, now)
+	case "GET entities/{id}/locations":
+		return memoryPage(req, m.locations[parts[1]], query)
+	case "POST entities/locations/search":
+		return m.searchLocations(req, body, query)
+	case "POST feeds/definitions":
+		return m.createFeed(req, body, now)
+	case "POST feeds/definitions/search":
+		return m.searchFeeds(req, body, query)
+	case "GET feeds/definitions/{id}", "PUT feeds/definitions/{id}", "DELETE feeds/definitions/{id}":
+		return m.feed(req, parts[2], body, now)
+	case "POST feeds/messages":
+		m.messages++
+		return memoryResponse(req, http.StatusCreated, memoryRecord{})
+	case "POST feeds/search":
+		return memoryPage(req, nil, query)
+	default:
+		return memoryResponse(req, http.StatusNotImplemented, memoryRecord{"message": key + " is not available in a dry run"})
+	}
+}
+
+func (m *Memory) createEntity(req *http.Request, body memoryRecord, now string) *http.Response {
+	id := uuid.NewString()
+	entity := memoryRecord{"parent_id": nil, "affiliation": "UNKNOWN"}
+	for k, v := range body {
+		entity[k] = v
+	}
+	entity["id"], entity["created_at"], entity["updated_at"] = id, now, now
+	m.entities[id] = entity
+	m.entityOrder = append(m.entityOrder, id)
+	return memoryResponse(req, http.StatusCreated, entity)
+}
+
+func (m *Memory) entity(req *http.Request, id string, body memoryRecord, now string) *http.Response {
+	entity, ok := m.entities[id]
+	if !ok {
+		return memoryResponse(req, http.StatusNotFound, memoryRecord{"message": "entity not found"})
+	}
+	switch req.Method {
+	case http.MethodPut:
+		for k, v := range body {
+			if v != nil {
+				entity[k] = v
+			}
+		}
+		entity["updated_at"] = now
+	case http.MethodDelete:
+		delete(m.entities, id)
+		delete(m.locations, id)
+		return memoryResponse(req, http.StatusOK, memoryRecord{})
+	}
+	return memoryResponse(req, http.StatusOK, entity)
+}
+
+func (m *Memory) searchEntities(req *http.Request, body memoryRecord, query map[string][]string) *http.Response {
+	filters, _ := body["filters"].(map[string]interface{})
+	var results []memoryRecord
+	for _, id := range m.entityOrder {
+		entity, ok := m.entities[id]
+		if !ok {
+			continue
+		}
+		if name, _ := filters["name"].(string); name != "" && !strings.Contains(strings.ToLower(fmt.Sprint(entity["name"])), strings.ToLower(name)) {
+			continue
+		}
+		if !memoryMatches(filters, "entity_ids", entity["id"]) || !memoryMatches(filters, "types", entity["type"]) ||
+			!memoryMatches(filters, "category", entity["category"]) || !memoryMatches(filters, "affiliation", entity["affiliation"]) ||
+			!memoryMatches(filters, "status", entity["status"]) || !memoryMatches(filters, "parent_ids", entity["parent_id"]) {
+			continue
+		}
+		if after, _ := filters["updated_after"].(string); after != "" && !memoryAfter(fmt.Sprint(entity["updated_at"]), after) {
+			continue
+		}
+		results = append(results, entity)
+	}
+	return memoryPage(req, results, query)
+}
+
+func (m *Memory) createLocation(req *http.Request, entityID string, body memoryRecord, now string) *http.Response {
+	if _, ok := m.entities[entityID]; !ok {
+		return memoryResponse(req, http.StatusNotFound, memoryRecord{"message": "entity not found"})
+	}
+	location := memoryRecord{}
+	for k, v := range body {
+		location[k] = v
+	}
+	location["id"], location["entity_id"], location["created_at"] = uuid.NewString(), entityID, now
+	m.locations[entityID] = append(m.locations[entityID], location)
+	return memoryResponse(req, http.StatusCreated, location)
+}
+
+func (m *Memory) searchLocations(req *http.Request, body memoryRecord, query map[string][]string) *http.Response {
+	filters, _ := body["filters"].(map[string]interface{})
+	var results []memoryRecord
+	for _, id := range m.entityOrder {
+		if !memoryMatches(filters, "entity_ids", id) {
+			continue
+		}
+		for _, location := range m.locations[id] {
+			if !memoryMatches(filters, "sources", location["source"]) {
+				continue
+			}
+			recorded, _ := location["recorded_at"].(string)
+			if recorded == "" {
+				recorded, _ = location["created_at"].(string)
+			}
+			if after, _ := filters["recorded_after"].(string); after != "" && !memoryAfter(recorded, after) {
+				continue
+			}
+			if before, _ := filters["recorded_before"].(string); before != "" && !memoryAfter(before, recorded) {
+				continue
+			}
+			results = append(results, location)
+		}
+	}
+	return memoryPage(req, results, query)
+}
+
+func (m *Memory) createFeed(req *http.Request, body memoryRecord, now string) *http.Response {
+	id := uuid.NewString()
+	orgID := req.Header.Get("X-ORG-ID")
+	if orgID == "" {
+		orgID = uuid.Nil.String()
+	}
+	feed := memoryRecord{"organization_id": orgID}
+	for k, v := range body {
+		feed[k] = v
+	}
+	feed["id"], feed["created_at"], feed["updated_at"] = id, now, now
+	m.feeds[id] = feed
+	m.feedOrder = append(m.feedOrder, id)
+	return memoryResponse(req, http.StatusCreated, feed)
+}
+
+func (m *Memory) feed(req *http.Request, id string, body memoryRecord, now string) *http.Response {
+	feed, ok := m.feeds[id]
+	if !ok {
+		return memoryResponse(req, http.StatusNotFound, memoryRecord{"message": "feed definition not found"})
+	}
+	switch req.Method {
+	case http.MethodPut:
+		for k, v := range body {
+			if v != nil {
+				feed[k] = v
+			}
+		}
+		feed["updated_at"] = now
+	case http.MethodDelete:
+		delete(m.feeds, id)
+		return memoryResponse(req, http.StatusOK, memoryRecord{})
+	}
+	return memoryResponse(req, http.StatusOK, feed)
+}
+
+func (m *Memory) searchFeeds(req *http.Request, body memoryRecord, query map[string][]string) *http.Response {
+	var results []memoryRecord
+	for _, id := range m.feedOrder {
+		feed, ok := m.feeds[id]
+		if !ok {
+			continue
+		}
+		if name, _ := body["feed_name"].(string); name != "" && !strings.Contains(fmt.Sprint(feed["feed_name"]), name) {
+			continue
+		}
+		matches := true
+		for _, field := range []string{"category", "data_type", "entity_id", "organization_id", "is_active", "is_template"} {
+			if want, ok := body[field]; ok && want != nil && fmt.Sprint(feed[field]) != fmt.Sprint(want) {
+				matches = false
+				break
+			}
+		}
+		if matches {
+			results = append(results, feed)
+		}
+	}
+	return memoryPage(req, results, query)
+}
+
+// memoryMatches reports whether value is in the filter list named field, or the filter
+// isn't set
+func memoryMatches(filters map[string]interface{}, field string, value interface{}) bool {
+	list, ok := filters[field].([]interface{})
+	if !ok || len(list) == 0 {
+		return true
+	}
+	for _, item := range list {
+		if fmt.Sprint(item) == fmt.Sprint(value) {
+			return true
+		}
+	}
+	return false
+}
+
+// memoryAfter reports whether RFC 3339 time a is at or after b
+func memoryAfter(a, b string) bool {
+	at, errA := time.Parse(time.RFC3339Nano, a)
+	bt, errB := time.Parse(time.RFC3339Nano, b)
+	if errA != nil || errB != nil {
+		return true
+	}
+	return !at.Before(bt)
+}
+
+// memoryPage answers a search with the page of results its limit and offset select
+func memoryPage(req *http.Request, results []memoryRecord, query map[string][]string) *http.Response {
+	total := len(results)
+	offset, limit := 0, total
+	if v, err := strconv.Atoi(firstValue(query["offset"])); err == nil && v > 0 {
+		offset = min(v, total)
+	}
+	if v, err := strconv.Atoi(firstValue(query["limit"])); err == nil && v >= 0 {
+		limit = v
+	}
+	page := results[offset:min(offset+limit, total)]
+	if page == nil {
+		page = []memoryRecord{}
+	}
+	return memoryResponse(req, http.StatusOK, memoryRecord{
+		"results":     page,
+		"total_count": total,
+		"paging":      memoryRecord{"next": nil, "previous": nil},
+	})
+}
+
+func firstValue(values []string) string {
+	if len(values) == 0 {
+		return ""
+	}
+	return values[0]
+}
+
+// memoryResponse encodes body as the JSON response to req
+func memoryResponse(req *http.Request, status int, body interface{}) *http.Response {
+	data, err := json.Marshal(body)
+	if err != nil {
+		status, data = http.StatusInternalServerError, []byte(fmt.Sprintf(`{"message":%q}`, err.Error()))
+	}
+	return &http.Response{
+		StatusCode:    status,
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": []string{"application/json"}},
+		Body:          io.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+		Request:       req,
+	}
+}
@@ -0,0 +1,175 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestMemoryClient(t *testing.T) {
+	legion, memory := NewMemoryClient()
+	orgID := uuid.New()
+	ctx := WithOrgID(context.Background(), orgID.String())
+
+	name, entityType, category, status := "Interceptor 1", "Interceptor", models.CategoryUXV, "active"
+	entity, err := legion.CreateEntity(ctx, &models.CreateEntityRequest{
+		Affiliation:    models.AffiliationFRIEND,
+		Category:       &category,
+		Name:           &name,
+		OrganizationID: &orgID,
+		Status:         &status,
+		Type:           &entityType,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := legion.CreateEntity(ctx, &models.CreateEntityRequest{
+		Category:       &category,
+		Name:           &entityType,
+		OrganizationID: &orgID,
+		Status:         &status,
+		Type:           &entityType,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	found, err := legion.SearchEntities(ctx, &models.SearchEntitiesRequest{
+		Filters: &models.SearchFilters{Name: "interceptor 1", Affiliation: []models.Affiliation{models.AffiliationFRIEND}},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found.Results) != 1 || found.Results[0].ID != entity.ID {
+		t.Fatalf("expected the search to find only %s, got %+v", entity.ID, found.Results)
+	}
+
+	renamed, err := legion.PatchEntity(ctx, entity.ID.String(), &models.EntityPatch{Name: ptr("Interceptor 2")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if renamed.Name != "Interceptor 2" || renamed.Type != entityType {
+		t.Fatalf("expected a rename to keep the rest of the entity, got %+v", renamed)
+	}
+
+	start, point := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC), "Point"
+	for i := 0; i < 3; i++ {
+		recorded := start.Add(time.Duration(i) * time.Second)
+		if _, err := legion.CreateEntityLocation(ctx, entity.ID.String(), &models.CreateEntityLocationRequest{
+			Position:   &models.GeomPoint{Type: &point, Coordinates: []float64{0, 0, float64(i)}},
+			Source:     "test",
+			RecordedAt: &recorded,
+		}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	since := start.Add(time.Second)
+	locations, err := legion.GetEntityLocationHistory(ctx, entity.ID, &since, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(locations) != 2 || locations[0].Position.Coordinates[2] != 1 {
+		t.Fatalf("expected the last 2 locations, oldest first, got %+v", locations)
+	}
+
+	feedName, dataType, active := "status", "status.v1", true
+	feed, err := legion.CreateFeedDefinition(ctx, &models.CreateFeedDefinitionRequest{
+		Category: ptr(models.MessageCategoryMESSAGE),
+		DataType: &dataType,
+		EntityID: entity.ID,
+		FeedName: &feedName,
+		IsActive: &active,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	feeds, err := legion.SearchFeedDefinitions(ctx, &models.FeedDefinitionSearchRequest{EntityID: entity.ID})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(feeds.Results) != 1 || feeds.Results[0].ID != feed.ID {
+		t.Fatalf("expected the feed search to find %s, got %+v", feed.ID, feeds.Results)
+	}
+	if err := legion.IngestFeedData(ctx, &models.IngestFeedDataRequest{
+		EntityID:         &entity.ID,
+		FeedDefinitionID: &feed.ID,
+		Payload:          ptr(json.RawMessage(`{"status":"ok"}`)),
+		RecordedAt:       &start,
+	}); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := legion.DeleteEntity(ctx, entity.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	var apiErr *APIError
+	if _, err := legion.GetEntity(ctx, entity.ID.String()); !errors.As(err, &apiErr) || apiErr.StatusCode != http.StatusNotFound {
+		t.Fatalf("expected a deleted entity to be not found, got %v", err)
+	}
+
+	summary := memory.Summary()
+	if summary.Entities != 1 || summary.Locations != 0 || summary.FeedDefinitions != 1 || summary.FeedMessages != 1 {
+		t.Fatalf("unexpected summary %+v", summary)
+	}
+	if got := summary.ByRoute["POST /v3/entities/{id}/locations"]; got != 3 {
+		t.Fatalf("expected 3 location writes, got %d", got)
+	}
+
+	writes := memory.CallsTo(http.MethodPost, "/v3/entities/{id}/locations")
+	if len(writes) != 3 || writes[0].Path != "/v3/entities/"+entity.ID.String()+"/locations" || writes[0].Status != http.StatusCreated {
+		t.Fatalf("expected 3 recorded location writes to %s, got %+v", entity.ID, writes)
+	}
+	var sent models.CreateEntityLocationRequest
+	if err := json.Unmarshal(writes[2].Body, &sent); err != nil || sent.Source != "test" || sent.Position.Coordinates[2] != 2 {
+		t.Fatalf("expected the last write to carry its location, got %+v (%v)", sent, err)
+	}
+	calls := memory.Calls()
+	if len(calls) != summary.Requests || calls[len(calls)-1].Method != http.MethodGet || calls[len(calls)-1].Status != http.StatusNotFound {
+		t.Fatalf("expected every request recorded in order, got %d calls ending %+v", len(calls), calls[len(calls)-1])
+	}
+}
+
+func TestMemoryClientIsALegionAPI(t *testing.T) {
+	legion, memory := NewMemoryClient()
+	var api LegionAPI = legion
+	orgID := uuid.New()
+	ctx := WithOrgID(context.Background(), orgID.String())
+
+	name, entityType, category, status := "Track 1", "Track", models.CategoryTRACK, "active"
+	entity, err := api.CreateEntity(ctx, &models.CreateEntityRequest{
+		Category:       &category,
+		Name:           &name,
+		OrganizationID: &orgID,
+		Status:         &status,
+		Type:           &entityType,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := api.DeleteEntity(ctx, entity.ID.String()); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := api.GetMe(ctx); err == nil {
+		t.Fatal("expected a route the fake doesn't model to fail")
+	}
+
+	var routes []string
+	for _, call := range memory.Calls() {
+		routes = append(routes, call.Method+" "+call.Route)
+	}
+	want := []string{"POST /v3/entities", "DELETE /v3/entities/{id}", "GET /v3/me"}
+	if strings.Join(routes, ", ") != strings.Join(want, ", ") {
+		t.Fatalf("expected calls %v, got %v", want, routes)
+	}
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
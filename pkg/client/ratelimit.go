@@ -0,0 +1,87 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// RateLimiter paces requests with a token bucket so a client never sends faster than
+// Legion allows. Unlike a Budget it sheds nothing: a request waits for its turn. One
+// limiter on a client is shared by everything that client sends.
+type RateLimiter struct {
+	rate   float64 // Tokens added per second
+	burst  float64
+	tokens float64
+	last   time.Time
+	waited int
+	delay  time.Duration
+	mu     sync.Mutex
+}
+
+// RateLimiterStats summarizes how much a limiter slowed requests down
+type RateLimiterStats struct {
+	PerSecond float64       `json:"per_second"`
+	Waited    int           `json:"waited"`     // Requests that had to wait
+	TotalWait time.Duration `json:"total_wait"` // Summed over every request
+}
+
+// NewRateLimiter allows perSecond requests per second on average, and up to burst at
+// once after a quiet spell. A burst below 1 is taken as 1.
+func NewRateLimiter(perSecond float64, burst int) *RateLimiter {
+	b := max(float64(burst), 1)
+	return &RateLimiter{rate: perSecond, burst: b, tokens: b, last: time.Now()}
+}
+
+// Wait blocks until a request may be sent, or ctx is done
+func (l *RateLimiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	wait := l.reserve(time.Now())
+	l.mu.Unlock()
+	if wait <= 0 {
+		return nil
+	}
+
+	timer := time.NewTimer(wait)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		// The reservation stands; giving it back would let later requests jump ahead
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// reserve takes a token, going into debt if none is left, and returns how long the
+// caller must wait for the debt to be repaid
+func (l *RateLimiter) reserve(now time.Time) time.Duration {
+	l.tokens = min(l.tokens+now.Sub(l.last).Seconds()*l.rate, l.burst)
+	l.last = now
+	l.tokens--
+	if l.tokens >= 0 {
+		return 0
+	}
+
+	wait := time.Duration(-l.tokens / l.rate * float64(time.Second))
+	l.waited++
+	l.delay += wait
+	return wait
+}
+
+// Stats returns how much the limiter has delayed requests so far
+func (l *RateLimiter) Stats() RateLimiterStats {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return RateLimiterStats{PerSecond: l.rate, Waited: l.waited, TotalWait: l.delay}
+}
+
+// SetRateLimiter paces every later request, retries included, through l. Nil removes it.
+func (c *Legion) SetRateLimiter(l *RateLimiter) {
+	c.limiter = l
+}
+
+// RateLimiter returns the installed rate limiter, or nil
+func (c *Legion) RateLimiter() *RateLimiter {
+	return c.limiter
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestRateLimiterPacesBursts(t *testing.T) {
+	l := NewRateLimiter(10, 5)
+	now := l.last
+
+	// A full bucket lets the burst through, then each call waits a tenth of a second more
+	for i := 0; i < 5; i++ {
+		if wait := l.reserve(now); wait != 0 {
+			t.Fatalf("call %d of the burst waited %s", i+1, wait)
+		}
+	}
+	for i := 1; i <= 3; i++ {
+		if wait := l.reserve(now); wait != time.Duration(i)*100*time.Millisecond {
+			t.Fatalf("call %d after the burst: expected %dms, got %s", i, i*100, wait)
+		}
+	}
+
+	// The debt is repaid before the bucket refills
+	if wait := l.reserve(now.Add(time.Second)); wait != 0 {
+		t.Errorf("expected no wait a second later, got %s", wait)
+	}
+
+	stats := l.Stats()
+	if stats.Waited != 3 || stats.TotalWait != 600*time.Millisecond {
+		t.Errorf("unexpected stats %+v", stats)
+	}
+}
+
+func TestRateLimiterWaitHonorsContext(t *testing.T) {
+	l := NewRateLimiter(1, 1)
+	if err := l.Wait(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if err := l.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("expected the wait to end with the context, got %v", err)
+	}
+}
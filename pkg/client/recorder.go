@@ -0,0 +1,17 @@
+package client
+
+import "github.com/picogrid/legion-simulations/pkg/models"
+
+// Recorder is told about every entity write Legion accepts, e.g. to record a run for
+// replay. Methods are called from the goroutine that made the request.
+type Recorder interface {
+	EntityCreated(entity *models.EntityResponse, req *models.CreateEntityRequest)
+	LocationCreated(entityID string, req *models.CreateEntityLocationRequest)
+	EntityPatched(entityID string, patch *models.EntityPatch)
+	EntityDeleted(entityID string)
+}
+
+// SetRecorder reports every later entity write to r. Nil removes it.
+func (c *Legion) SetRecorder(r Recorder) {
+	c.recorder = r
+}
@@ -0,0 +1,88 @@
+package client
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"time"
+)
+
+// RetryPolicy decides which failed requests are tried again and how long to wait
+// between tries. Throttled (429) and unavailable (503) responses mean the request was
+// not applied, so any request is retried. After other server errors and dropped
+// connections a request may have landed, so only reads, PUTs, DELETEs and requests
+// carrying an idempotency key are retried.
+type RetryPolicy struct {
+	MaxAttempts int           // Tries per request, the first included; 1 or less turns retries off
+	BaseDelay   time.Duration // Wait before the first retry; each later wait doubles
+	MaxDelay    time.Duration // Longest wait, which also caps a server's Retry-After
+	Jitter      float64       // Fraction of each wait that is randomized, 0 to 1, so clients don't retry in step
+	RetryOn     []int         // Status codes worth retrying
+}
+
+// DefaultRetryPolicy is used by clients whose Config doesn't set one
+func DefaultRetryPolicy() RetryPolicy {
+	return RetryPolicy{
+		MaxAttempts: 4,
+		BaseDelay:   time.Second,
+		MaxDelay:    10 * time.Second,
+		Jitter:      0.2,
+		RetryOn: []int{
+			http.StatusTooManyRequests,
+			http.StatusInternalServerError,
+			http.StatusBadGateway,
+			http.StatusServiceUnavailable,
+			http.StatusGatewayTimeout,
+		},
+	}
+}
+
+// SetRetryPolicy replaces how later requests are retried
+func (c *Legion) SetRetryPolicy(p RetryPolicy) {
+	c.retry = p
+}
+
+// retryable reports whether an attempt that failed with status, or with no response
+// when status is 0, should be tried again. repeatable is whether the request is safe
+// to apply twice.
+func (p RetryPolicy) retryable(status int, repeatable bool) bool {
+	if status == 0 {
+		return repeatable
+	}
+	for _, code := range p.RetryOn {
+		if code != status {
+			continue
+		}
+		return repeatable || status == http.StatusTooManyRequests || status == http.StatusServiceUnavailable
+	}
+	return false
+}
+
+// delay is how long to wait before retry number retry, counting from 0. A server's
+// Retry-After is honored up to MaxDelay; otherwise the wait backs off exponentially.
+func (p RetryPolicy) delay(apiErr *APIError, retry int) time.Duration {
+	if apiErr != nil && apiErr.hasRetryAfter {
+		return p.capped(apiErr.RetryAfter)
+	}
+	d := p.capped(p.BaseDelay << min(retry, 30))
+	if p.Jitter > 0 {
+		d -= time.Duration(float64(d) * min(p.Jitter, 1) * rand.Float64())
+	}
+	return d
+}
+
+// capped limits d to MaxDelay, if one is set
+func (p RetryPolicy) capped(d time.Duration) time.Duration {
+	if p.MaxDelay > 0 && (d > p.MaxDelay || d < 0) {
+		return p.MaxDelay
+	}
+	return d
+}
+
+// repeatableMethod reports whether applying a request twice has the same effect as once
+func repeatableMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPut, http.MethodDelete:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,90 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestDoRequestRetriesServerErrors(t *testing.T) {
+	var statuses []int
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if calls < len(statuses) {
+			w.WriteHeader(statuses[calls])
+			calls++
+			return
+		}
+		calls++
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	legion, err := NewClient(Config{BaseURL: server.URL, Retry: &RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   time.Millisecond,
+		MaxDelay:    5 * time.Millisecond,
+		Jitter:      0.5,
+		RetryOn:     DefaultRetryPolicy().RetryOn,
+	}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	entityID, feedID, recordedAt := uuid.New(), uuid.New(), time.Now()
+	payload := json.RawMessage(`{}`)
+	req := &models.IngestFeedDataRequest{EntityID: &entityID, FeedDefinitionID: &feedID, RecordedAt: &recordedAt, Payload: &payload}
+	keyed := WithIdempotencyKey(context.Background(), IdempotencyKey("run-1", "ingest"))
+
+	for _, tc := range []struct {
+		name      string
+		ctx       context.Context
+		statuses  []int
+		wantCalls int
+		wantErr   bool
+	}{
+		{"unavailable is retried without a key", context.Background(), []int{503, 503}, 3, false},
+		{"server error is not retried without a key", context.Background(), []int{500}, 1, true},
+		{"server error is retried with a key", keyed, []int{502, 504}, 3, false},
+		{"attempts are bounded", keyed, []int{500, 500, 500, 500}, 3, true},
+		{"client errors are not retried", keyed, []int{400}, 1, true},
+	} {
+		statuses, calls = tc.statuses, 0
+		err := legion.IngestFeedData(tc.ctx, req)
+		if calls != tc.wantCalls {
+			t.Errorf("%s: expected %d calls, got %d", tc.name, tc.wantCalls, calls)
+		}
+		if (err != nil) != tc.wantErr {
+			t.Errorf("%s: unexpected error %v", tc.name, err)
+		}
+		var apiErr *APIError
+		if err != nil && !errors.As(err, &apiErr) {
+			t.Errorf("%s: expected an *APIError, got %v", tc.name, err)
+		}
+	}
+}
+
+func TestRetryDelayBacksOff(t *testing.T) {
+	p := RetryPolicy{BaseDelay: time.Second, MaxDelay: 10 * time.Second}
+	for retry, want := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 8 * time.Second, 10 * time.Second} {
+		if got := p.delay(nil, retry); got != want {
+			t.Errorf("retry %d: expected %s, got %s", retry, want, got)
+		}
+	}
+	if got := p.delay(&APIError{RetryAfter: time.Minute, hasRetryAfter: true}, 0); got != 10*time.Second {
+		t.Errorf("expected Retry-After to be capped at MaxDelay, got %s", got)
+	}
+
+	p.Jitter = 0.2
+	for i := 0; i < 100; i++ {
+		if got := p.delay(nil, 1); got < 1600*time.Millisecond || got > 2*time.Second {
+			t.Fatalf("jittered delay %s outside 1.6s-2s", got)
+		}
+	}
+}
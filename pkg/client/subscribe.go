@@ -0,0 +1,119 @@
+package client
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// DefaultSubscribeInterval is how often a subscription polls when the caller doesn't say
+const DefaultSubscribeInterval = 2 * time.Second
+
+// subscribeOverlap widens each poll's window back in time, so a change stamped on the
+// edge of the last poll, or by a server clock running behind ours, isn't missed
+const subscribeOverlap = 2 * time.Second
+
+// EntitySubscription delivers entities matching a search each time they change in Legion,
+// e.g. a status an operator overrides or a command another system writes. Legion's API
+// has no push channel for entity changes, so a subscription polls search for entities
+// updated since its last poll; each change is delivered once.
+type EntitySubscription struct {
+	changes chan models.EntityResponse
+	cancel  context.CancelFunc
+	done    chan struct{}
+	mu      sync.Mutex
+	err     error
+}
+
+// SubscribeEntities starts delivering changes, made from now on, to entities matching
+// req. An interval of 0 polls every DefaultSubscribeInterval. The subscription ends when
+// ctx is done or it is closed. The organization is taken from ctx as for any request.
+func (c *Legion) SubscribeEntities(ctx context.Context, req *models.SearchEntitiesRequest, interval time.Duration) *EntitySubscription {
+	if interval <= 0 {
+		interval = DefaultSubscribeInterval
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	sub := &EntitySubscription{
+		changes: make(chan models.EntityResponse, 64),
+		cancel:  cancel,
+		done:    make(chan struct{}),
+	}
+	go sub.poll(ctx, c, req, interval)
+	return sub
+}
+
+// Changes returns the channel changed entities arrive on. It is closed when the
+// subscription ends.
+func (s *EntitySubscription) Changes() <-chan models.EntityResponse {
+	return s.changes
+}
+
+// Err returns the error from the most recent poll, or nil once a poll succeeds again
+func (s *EntitySubscription) Err() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.err
+}
+
+// Close ends the subscription and waits for its last poll to finish
+func (s *EntitySubscription) Close() {
+	s.cancel()
+	<-s.done
+}
+
+func (s *EntitySubscription) poll(ctx context.Context, c *Legion, req *models.SearchEntitiesRequest, interval time.Duration) {
+	defer close(s.done)
+	defer close(s.changes)
+
+	since := time.Now()
+	seen := make(map[uuid.UUID]time.Time) // Last UpdatedAt delivered per entity
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		search := *req
+		filters := models.SearchFilters{}
+		if req.Filters != nil {
+			filters = *req.Filters
+		}
+		after := since.Add(-subscribeOverlap)
+		filters.UpdatedAfter = &after
+		search.Filters = &filters
+		search.Sort = []models.SortFieldSpec{{Field: "updated_at", Order: "asc"}}
+
+		result, err := c.SearchEntities(ctx, &search)
+		s.mu.Lock()
+		s.err = err
+		s.mu.Unlock()
+		if err != nil {
+			if ctx.Err() == nil {
+				clientLog.Debugf("Entity subscription poll failed: %v", err)
+			}
+			continue
+		}
+
+		for _, entity := range result.Results {
+			if last, ok := seen[entity.ID]; ok && !entity.UpdatedAt.After(last) {
+				continue
+			}
+			seen[entity.ID] = entity.UpdatedAt
+			if entity.UpdatedAt.After(since) {
+				since = entity.UpdatedAt
+			}
+			select {
+			case s.changes <- entity:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
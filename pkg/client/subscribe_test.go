@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+func TestSubscribeEntitiesDeliversChanges(t *testing.T) {
+	legion, _ := NewMemoryClient()
+	orgID := uuid.New()
+	ctx := WithOrgID(context.Background(), orgID.String())
+
+	name, entityType, category, status := "Counter-UAS-01", "Counter-UAS", models.CategoryDEVICE, "IDLE"
+	entity, err := legion.CreateEntity(ctx, &models.CreateEntityRequest{
+		OrganizationID: &orgID,
+		Name:           &name,
+		Category:       &category,
+		Type:           &entityType,
+		Status:         &status,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sub := legion.SubscribeEntities(ctx, &models.SearchEntitiesRequest{
+		Filters: &models.SearchFilters{EntityIDs: []uuid.UUID{entity.ID}},
+	}, 10*time.Millisecond)
+	defer sub.Close()
+
+	offline := "OFFLINE"
+	if _, err := legion.PatchEntity(ctx, entity.ID.String(), &models.EntityPatch{Status: &offline}); err != nil {
+		t.Fatal(err)
+	}
+
+	// The create and the patch land within the overlap; only the latest state arrives, once
+	deadline := time.After(2 * time.Second)
+	select {
+	case changed := <-sub.Changes():
+		if changed.ID != entity.ID || changed.Status != offline {
+			t.Fatalf("expected %s to arrive OFFLINE, got %+v", entity.ID, changed)
+		}
+	case <-deadline:
+		t.Fatalf("no change delivered (last error %v)", sub.Err())
+	}
+	select {
+	case changed := <-sub.Changes():
+		t.Fatalf("unchanged entity delivered again: %+v", changed)
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	sub.Close()
+	if _, open := <-sub.Changes(); open {
+		t.Error("expected Changes to be closed after Close")
+	}
+}
@@ -0,0 +1,54 @@
+package client
+
+import (
+	"net"
+	"net/http"
+	"time"
+)
+
+// TransportConfig tunes the client's HTTP connections. Zero values keep Go's defaults,
+// which suit a few requests at a time; simulations sending hundreds of updates a second
+// mostly want more idle connections per host so each request doesn't dial anew.
+type TransportConfig struct {
+	MaxConnsPerHost       int           // Cap on connections to Legion, busy or idle (0 is unlimited)
+	MaxIdleConnsPerHost   int           // Idle connections kept open for reuse (Go's default is 2)
+	IdleConnTimeout       time.Duration // How long an idle connection is kept
+	KeepAlive             time.Duration // TCP keep-alive probe interval; negative turns probes off
+	DisableKeepAlives     bool          // Use each connection for one request only
+	DisableHTTP2          bool          // Stay on HTTP/1.1, e.g. behind proxies that mishandle HTTP/2
+	TLSHandshakeTimeout   time.Duration
+	ResponseHeaderTimeout time.Duration // How long to wait for a response after sending a request
+}
+
+// newHTTPClient builds the HTTP client for a Config: Go's default transport with the
+// settings in t applied, and timeout on each request as a whole
+func newHTTPClient(timeout time.Duration, t TransportConfig) *http.Client {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if t.MaxConnsPerHost > 0 {
+		transport.MaxConnsPerHost = t.MaxConnsPerHost
+	}
+	if t.MaxIdleConnsPerHost > 0 {
+		transport.MaxIdleConnsPerHost = t.MaxIdleConnsPerHost
+		transport.MaxIdleConns = max(transport.MaxIdleConns, t.MaxIdleConnsPerHost)
+	}
+	if t.IdleConnTimeout > 0 {
+		transport.IdleConnTimeout = t.IdleConnTimeout
+	}
+	if t.KeepAlive != 0 {
+		dialer := &net.Dialer{Timeout: 30 * time.Second, KeepAlive: t.KeepAlive}
+		transport.DialContext = dialer.DialContext
+	}
+	transport.DisableKeepAlives = t.DisableKeepAlives
+	if t.DisableHTTP2 {
+		protocols := new(http.Protocols)
+		protocols.SetHTTP1(true)
+		transport.Protocols = protocols
+	}
+	if t.TLSHandshakeTimeout > 0 {
+		transport.TLSHandshakeTimeout = t.TLSHandshakeTimeout
+	}
+	if t.ResponseHeaderTimeout > 0 {
+		transport.ResponseHeaderTimeout = t.ResponseHeaderTimeout
+	}
+	return &http.Client{Timeout: timeout, Transport: transport}
+}
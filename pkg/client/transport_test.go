@@ -0,0 +1,33 @@
+package client
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestNewHTTPClientAppliesTransportConfig(t *testing.T) {
+	c := newHTTPClient(5*time.Second, TransportConfig{
+		MaxConnsPerHost:     32,
+		MaxIdleConnsPerHost: 200,
+		IdleConnTimeout:     time.Minute,
+		DisableHTTP2:        true,
+	})
+	transport := c.Transport.(*http.Transport)
+	if c.Timeout != 5*time.Second || transport.MaxConnsPerHost != 32 || transport.MaxIdleConnsPerHost != 200 || transport.IdleConnTimeout != time.Minute {
+		t.Errorf("settings not applied: timeout %s, transport %+v", c.Timeout, transport)
+	}
+	if transport.MaxIdleConns < 200 {
+		t.Errorf("expected the pool to fit every idle connection per host, got MaxIdleConns %d", transport.MaxIdleConns)
+	}
+	if transport.Protocols == nil || transport.Protocols.HTTP2() || !transport.Protocols.HTTP1() {
+		t.Error("expected HTTP/1.1 only")
+	}
+
+	// Unset fields keep Go's defaults
+	defaults := http.DefaultTransport.(*http.Transport)
+	transport = newHTTPClient(time.Second, TransportConfig{}).Transport.(*http.Transport)
+	if transport.MaxIdleConnsPerHost != defaults.MaxIdleConnsPerHost || transport.IdleConnTimeout != defaults.IdleConnTimeout || !transport.ForceAttemptHTTP2 {
+		t.Errorf("expected default settings, got %+v", transport)
+	}
+}
@@ -5,12 +5,16 @@ import (
 	"fmt"
 	"io"
 	"net/http"
-
-	"github.com/picogrid/legion-simulations/pkg/logger"
+	"strings"
 
 	"github.com/picogrid/legion-simulations/pkg/models"
 )
 
+const (
+	orgsPageSize = 100
+	orgsMaxPages = 20
+)
+
 // GetMe retrieves the current user information
 func (c *Legion) GetMe(ctx context.Context) (*models.UserResponse, error) {
 	resp, err := c.doRequest(ctx, http.MethodGet, "/v3/me", nil)
@@ -27,18 +31,69 @@ func (c *Legion) GetMe(ctx context.Context) (*models.UserResponse, error) {
 }
 
 // GetMyOrganizations gets the organizations the current user belongs to
-func (c *Legion) GetMyOrganizations(ctx context.Context) (*models.OrganizationResponse, error) {
-	resp, err := c.doRequest(ctx, http.MethodGet, "/v3/me/orgs", nil)
+func (c *Legion) GetMyOrganizations(ctx context.Context) (*models.OrganizationUserWithOrgDetailsPaginatedResponse, error) {
+	var orgs models.OrganizationUserWithOrgDetailsPaginatedResponse
+	for page := 0; page < orgsMaxPages; page++ {
+		path := fmt.Sprintf("/v3/me/orgs?limit=%d&offset=%d", orgsPageSize, len(orgs.Results))
+		resp, err := c.doRequest(ctx, http.MethodGet, path, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user organizations: %w", err)
+		}
+
+		var result models.OrganizationUserWithOrgDetailsPaginatedResponse
+		if err := decodeResponse(resp, &result); err != nil {
+			return nil, fmt.Errorf("failed to decode organizations response: %w", err)
+		}
+		orgs.Results = append(orgs.Results, result.Results...)
+		orgs.TotalCount = result.TotalCount
+		if len(result.Results) < orgsPageSize || len(orgs.Results) >= result.TotalCount {
+			break
+		}
+	}
+
+	return &orgs, nil
+}
+
+// FindOrganization looks up an organization the current user belongs to by name. An
+// exact name wins, ignoring case; otherwise the name must be part of exactly one
+// organization's name.
+func (c *Legion) FindOrganization(ctx context.Context, name string) (*models.OrganizationUserWithOrgDetailsResponse, error) {
+	orgs, err := c.GetMyOrganizations(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get user organizations: %w", err)
+		return nil, err
 	}
 
-	var orgs models.OrganizationResponse
-	if err := decodeResponse(resp, &orgs); err != nil {
-		return nil, fmt.Errorf("failed to decode organizations response: %w", err)
+	want := strings.ToLower(strings.TrimSpace(name))
+	var partial []models.OrganizationUserWithOrgDetailsResponse
+	for _, org := range orgs.Results {
+		have := strings.ToLower(org.OrganizationName)
+		if have == want {
+			return &org, nil
+		}
+		if strings.Contains(have, want) {
+			partial = append(partial, org)
+		}
 	}
 
-	return &orgs, nil
+	switch len(partial) {
+	case 1:
+		return &partial[0], nil
+	case 0:
+		names := make([]string, len(orgs.Results))
+		for i, org := range orgs.Results {
+			names[i] = org.OrganizationName
+		}
+		if len(names) == 0 {
+			return nil, fmt.Errorf("no organization named %q: you belong to no organizations", name)
+		}
+		return nil, fmt.Errorf("no organization named %q; you belong to: %s", name, strings.Join(names, ", "))
+	default:
+		names := make([]string, len(partial))
+		for i, org := range partial {
+			names[i] = org.OrganizationName
+		}
+		return nil, fmt.Errorf("organization name %q is ambiguous; it matches: %s", name, strings.Join(names, ", "))
+	}
 }
 
 // ValidateConnection tests the connection to Legion by calling the /v3/me endpoint
@@ -50,7 +105,7 @@ func (c *Legion) ValidateConnection(ctx context.Context) error {
 	defer func(Body io.ReadCloser) {
 		err := Body.Close()
 		if err != nil {
-			logger.Errorf("failed to close response body: %v", err)
+			clientLog.Errorf("failed to close response body: %v", err)
 		}
 	}(resp.Body)
 
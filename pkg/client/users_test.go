@@ -0,0 +1,60 @@
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestFindOrganization(t *testing.T) {
+	names := []string{"1st MARDIV", "2nd MARDIV", "Picogrid Test Range"}
+	ids := make([]uuid.UUID, len(names))
+	for i := range ids {
+		ids[i] = uuid.New()
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/v3/me/orgs" {
+			http.NotFound(w, r)
+			return
+		}
+		if r.URL.Query().Get("limit") != strconv.Itoa(orgsPageSize) {
+			t.Errorf("unexpected query: %s", r.URL.RawQuery)
+		}
+		results := make([]map[string]interface{}, len(names))
+		for i, name := range names {
+			results[i] = map[string]interface{}{"organization_id": ids[i], "organization_name": name, "organization_role": "ADMIN"}
+		}
+		_ = json.NewEncoder(w).Encode(map[string]interface{}{"paging": map[string]interface{}{}, "results": results, "total_count": len(names)})
+	}))
+	defer server.Close()
+
+	legion, err := NewClient(Config{BaseURL: server.URL})
+	if err != nil {
+		t.Fatal(err)
+	}
+	ctx := context.Background()
+
+	for name, want := range map[string]uuid.UUID{"1st mardiv": ids[0], "Test Range": ids[2]} {
+		org, err := legion.FindOrganization(ctx, name)
+		if err != nil {
+			t.Fatalf("FindOrganization(%q): %v", name, err)
+		}
+		if org.OrganizationId != want {
+			t.Errorf("FindOrganization(%q) = %s, want %s", name, org.OrganizationId, want)
+		}
+	}
+
+	if _, err := legion.FindOrganization(ctx, "MARDIV"); err == nil || !strings.Contains(err.Error(), "ambiguous") {
+		t.Errorf("expected an ambiguous name error, got %v", err)
+	}
+	if _, err := legion.FindOrganization(ctx, "3rd MARDIV"); err == nil || !strings.Contains(err.Error(), "Picogrid Test Range") {
+		t.Errorf("expected an error listing the organizations, got %v", err)
+	}
+}
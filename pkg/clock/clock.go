@@ -0,0 +1,62 @@
+// Package clock disciplines a simulation's clock to an external time reference, so tools
+// federated on one exercise act on the same wall-clock instants even when their hosts'
+// clocks disagree.
+//
+// A Source measures how far the reference is ahead of the local clock. A Governor samples
+// it periodically, slews its offset toward the measurement the way NTP does, and provides
+// the governed time and tickers aligned to it. Sources are opened from a spec:
+//
+//	ntp://host[:port]     an NTP server, queried with SNTP
+//	http(s)://host/path   a master exercise clock answering {"time": "<RFC3339>"}
+//	offset:<duration>     a fixed offset, e.g. the one a PTP daemon reports
+package clock
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Source measures the offset of a reference clock from the local clock.
+// A positive offset means the reference is ahead.
+type Source interface {
+	Offset(ctx context.Context) (time.Duration, error)
+	String() string
+}
+
+// Open returns the source for spec, or nil when spec is empty
+func Open(spec string) (Source, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "":
+		return nil, nil
+	case strings.HasPrefix(spec, "ntp://"):
+		host := strings.TrimSuffix(strings.TrimPrefix(spec, "ntp://"), "/")
+		if host == "" {
+			return nil, fmt.Errorf("clock %q: missing NTP server", spec)
+		}
+		return NewNTP(host), nil
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return NewExerciseClock(spec), nil
+	case strings.HasPrefix(spec, "offset:"):
+		offset, err := time.ParseDuration(strings.TrimPrefix(spec, "offset:"))
+		if err != nil {
+			return nil, fmt.Errorf("clock %q: invalid offset: %w", spec, err)
+		}
+		return Fixed(offset), nil
+	}
+	return nil, fmt.Errorf("clock %q: expected ntp://host, an http(s) URL, or offset:<duration>", spec)
+}
+
+// Fixed is a constant offset, for references disciplined outside the process
+type Fixed time.Duration
+
+// Offset returns the fixed offset
+func (f Fixed) Offset(context.Context) (time.Duration, error) {
+	return time.Duration(f), nil
+}
+
+func (f Fixed) String() string {
+	return "offset:" + time.Duration(f).String()
+}
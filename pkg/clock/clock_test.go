@@ -0,0 +1,135 @@
+package clock
+
+import (
+	"context"
+	"net"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestOpen(t *testing.T) {
+	for spec, want := range map[string]string{
+		"ntp://time.example.com":      "ntp://time.example.com:123",
+		"ntp://10.0.0.1:1123":         "ntp://10.0.0.1:1123",
+		"http://master:7800/v1/clock": "http://master:7800/v1/clock",
+		"offset:-1.5ms":               "offset:-1.5ms",
+	} {
+		source, err := Open(spec)
+		if err != nil || source.String() != want {
+			t.Errorf("%s: expected %s, got %v (%v)", spec, want, source, err)
+		}
+	}
+	if source, err := Open(""); source != nil || err != nil {
+		t.Errorf("expected no source for an empty spec, got %v (%v)", source, err)
+	}
+	for _, spec := range []string{"ptp", "ntp://", "offset:soon"} {
+		if _, err := Open(spec); err == nil {
+			t.Errorf("expected %q to be rejected", spec)
+		}
+	}
+}
+
+// fakeNTP answers SNTP requests with the local clock shifted by ahead
+func fakeNTP(t *testing.T, ahead time.Duration) string {
+	conn, err := net.ListenPacket("udp", "127.0.0.1:0")
+	if err != nil {
+		t.Skipf("UDP unavailable: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	go func() {
+		buf := make([]byte, ntpPacketSize)
+		for {
+			n, addr, err := conn.ReadFrom(buf)
+			if err != nil {
+				return
+			}
+			if n < ntpPacketSize {
+				continue
+			}
+			reply := make([]byte, ntpPacketSize)
+			reply[0], reply[1] = 0x24, 2 // Version 4, server mode, stratum 2
+			copy(reply[24:32], buf[40:48])
+			putNTPTime(reply[32:], time.Now().Add(ahead))
+			putNTPTime(reply[40:], time.Now().Add(ahead))
+			_, _ = conn.WriteTo(reply, addr)
+		}
+	}()
+	return conn.LocalAddr().String()
+}
+
+func TestSources(t *testing.T) {
+	ctx := context.Background()
+
+	offset, err := NewNTP(fakeNTP(t, 250*time.Millisecond)).Offset(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := offset - 250*time.Millisecond; diff > 20*time.Millisecond || diff < -20*time.Millisecond {
+		t.Errorf("expected an NTP offset near 250ms, got %s", offset)
+	}
+
+	master := httptest.NewServer(Handler(func() time.Time { return time.Now().Add(-2 * time.Second) }))
+	defer master.Close()
+	offset, err = NewExerciseClock(master.URL).Offset(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := offset + 2*time.Second; diff > 20*time.Millisecond || diff < -20*time.Millisecond {
+		t.Errorf("expected an exercise clock offset near -2s, got %s", offset)
+	}
+}
+
+func TestGovernorSlewsSmallErrorsAndStepsLargeOnes(t *testing.T) {
+	local := time.Unix(1_700_000_000, 0)
+	g := NewGovernor(Fixed(10*time.Millisecond), time.Second)
+	g.local = func() time.Time { return local }
+
+	if err := g.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+	if got := g.Now(); !got.Equal(local.Add(10 * time.Millisecond)) {
+		t.Fatalf("expected the first sync to step, got %s", got.Sub(local))
+	}
+
+	// A 20ms change is slewed at 500µs per second: 5ms after 10s, all of it after 40s
+	g.measured(30 * time.Millisecond)
+	local = local.Add(10 * time.Second)
+	if offset := g.Offset(); offset != 15*time.Millisecond {
+		t.Errorf("expected 15ms after 10s of slew, got %s", offset)
+	}
+	local = local.Add(60 * time.Second)
+	if offset := g.Offset(); offset != 30*time.Millisecond {
+		t.Errorf("expected the slew to settle on 30ms, got %s", offset)
+	}
+
+	// Anything past the step threshold is applied at once
+	g.measured(-time.Second)
+	if offset := g.Offset(); offset != -time.Second {
+		t.Errorf("expected a step to -1s, got %s", offset)
+	}
+}
+
+func TestTickerAlignsToGovernedClock(t *testing.T) {
+	g := NewGovernor(Fixed(-3*time.Millisecond), time.Second)
+	if err := g.Sync(context.Background()); err != nil {
+		t.Fatal(err)
+	}
+
+	epoch := g.Now().Truncate(time.Second)
+	ticker := g.NewTicker(epoch, 20*time.Millisecond)
+	defer ticker.Stop()
+
+	var last time.Time
+	for i := 0; i < 3; i++ {
+		tick := <-ticker.C
+		if tick.Sub(epoch)%(20*time.Millisecond) != 0 || !tick.After(last) {
+			t.Fatalf("tick %s is not on a fresh 20ms boundary from the epoch", tick.Sub(epoch))
+		}
+		if late := g.Now().Sub(tick); late < 0 || late > 50*time.Millisecond {
+			t.Errorf("tick for %s delivered %s off the governed clock", tick.Sub(epoch), late)
+		}
+		last = tick
+	}
+}
@@ -0,0 +1,60 @@
+package clock
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// ExerciseClock reads a master exercise clock over HTTP. The endpoint answers GET with
+// {"time": "<RFC3339 with fractional seconds>"}.
+type ExerciseClock struct {
+	url  string
+	http *http.Client
+}
+
+// NewExerciseClock creates a source for the exercise clock at url
+func NewExerciseClock(url string) *ExerciseClock {
+	return &ExerciseClock{url: url, http: &http.Client{Timeout: queryTimeout}}
+}
+
+func (e *ExerciseClock) String() string {
+	return e.url
+}
+
+// Offset reads the clock and assumes it was sampled halfway through the round trip
+func (e *ExerciseClock) Offset(ctx context.Context) (time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.url, nil)
+	if err != nil {
+		return 0, err
+	}
+
+	sent := time.Now()
+	resp, err := e.http.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to read exercise clock: %w", err)
+	}
+	defer resp.Body.Close()
+	received := time.Now()
+
+	if resp.StatusCode != http.StatusOK {
+		return 0, fmt.Errorf("exercise clock returned HTTP %d", resp.StatusCode)
+	}
+	var reading struct {
+		Time time.Time `json:"time"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&reading); err != nil || reading.Time.IsZero() {
+		return 0, fmt.Errorf("exercise clock sent no time")
+	}
+	return reading.Time.Sub(sent.Add(received.Sub(sent) / 2)), nil
+}
+
+// Handler serves now as an exercise clock, for a tool acting as the master
+func Handler(now func() time.Time) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(map[string]string{"time": now().UTC().Format(time.RFC3339Nano)})
+	})
+}
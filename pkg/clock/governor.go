@@ -0,0 +1,166 @@
+package clock
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// Governor defaults, after NTP's clock discipline
+const (
+	DefaultSyncInterval = 10 * time.Second
+	maxSlewRate         = 0.0005                 // 500µs of correction per second
+	stepThreshold       = 128 * time.Millisecond // Larger errors are stepped rather than slewed
+)
+
+// Governor keeps a local view of the reference clock. Small errors are slewed out
+// gradually so the governed clock never jumps or runs backwards; large ones are stepped.
+type Governor struct {
+	source   Source
+	interval time.Duration
+	local    func() time.Time // The local clock; replaced in tests
+
+	mu      sync.Mutex
+	applied time.Duration // Offset in use
+	target  time.Duration // Last measured offset
+	at      time.Time     // Local time applied was last advanced
+}
+
+// NewGovernor creates a governor sampling source every interval
+func NewGovernor(source Source, interval time.Duration) *Governor {
+	if interval <= 0 {
+		interval = DefaultSyncInterval
+	}
+	return &Governor{source: source, interval: interval, local: time.Now}
+}
+
+// Sync measures the offset and steps to it. Call it once before the governed clock is used.
+func (g *Governor) Sync(ctx context.Context) error {
+	offset, err := g.source.Offset(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to sync with %s: %w", g.source, err)
+	}
+
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.applied, g.target, g.at = offset, offset, g.local()
+	return nil
+}
+
+// Run samples the source until ctx is done. A failed sample keeps the last offset.
+func (g *Governor) Run(ctx context.Context) {
+	ticker := time.NewTicker(g.interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			offset, err := g.source.Offset(ctx)
+			if err != nil {
+				logger.Warnf("Clock sample from %s failed, holding the last offset: %v", g.source, err)
+				continue
+			}
+			g.measured(offset)
+		}
+	}
+}
+
+// measured records a new offset measurement
+func (g *Governor) measured(offset time.Duration) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	g.advance(g.local())
+	g.target = offset
+	if step := offset - g.applied; step > stepThreshold || step < -stepThreshold {
+		logger.Warnf("Clock stepped %s to match %s", step.Round(time.Millisecond), g.source)
+		g.applied = offset
+	}
+}
+
+// advance slews the applied offset toward the target for the time since it was last moved
+func (g *Governor) advance(now time.Time) {
+	if !g.at.IsZero() {
+		limit := time.Duration(float64(now.Sub(g.at)) * maxSlewRate)
+		switch diff := g.target - g.applied; {
+		case diff > limit:
+			g.applied += limit
+		case diff < -limit:
+			g.applied -= limit
+		default:
+			g.applied = g.target
+		}
+	}
+	g.at = now
+}
+
+// Offset returns the offset currently applied to the local clock
+func (g *Governor) Offset() time.Duration {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	g.advance(g.local())
+	return g.applied
+}
+
+// Now returns the governed time
+func (g *Governor) Now() time.Time {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	now := g.local()
+	g.advance(now)
+	return now.Add(g.applied)
+}
+
+// Ticker delivers ticks at epoch + n*interval on the governed clock. Like time.Ticker it
+// drops ticks for a slow receiver rather than queueing them.
+type Ticker struct {
+	C    <-chan time.Time
+	stop chan struct{}
+	once sync.Once
+}
+
+// Stop ends the ticker
+func (t *Ticker) Stop() {
+	t.once.Do(func() { close(t.stop) })
+}
+
+// NewTicker starts a ticker aligned to epoch on the governed clock
+func (g *Governor) NewTicker(epoch time.Time, interval time.Duration) *Ticker {
+	c := make(chan time.Time, 1)
+	t := &Ticker{C: c, stop: make(chan struct{})}
+
+	go func() {
+		var last time.Time
+		for {
+			now := g.Now()
+			next := epoch
+			if elapsed := now.Sub(epoch); elapsed >= 0 {
+				next = epoch.Add((elapsed/interval + 1) * interval)
+			}
+			// A slew can leave the clock just short of the boundary that fired
+			if !last.IsZero() && !next.After(last) {
+				next = last.Add(interval)
+			}
+
+			timer := time.NewTimer(next.Sub(now))
+			select {
+			case <-t.stop:
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+
+			last = next
+			select {
+			case c <- next:
+			default:
+			}
+		}
+	}()
+	return t
+}
@@ -0,0 +1,107 @@
+package clock
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+)
+
+// NTP packet layout (RFC 4330)
+const (
+	ntpPacketSize  = 48
+	ntpDefaultPort = "123"
+	ntpEpochOffset = 2208988800 // Seconds from 1900 to 1970
+	ntpClientMode  = 0x1B       // LI 0, version 3, mode 3 (client)
+	ntpServerMode  = 4
+	queryTimeout   = 2 * time.Second
+)
+
+// NTP queries an NTP server with SNTP
+type NTP struct {
+	addr string
+}
+
+// NewNTP creates a source for the server at host, with the port defaulting to 123
+func NewNTP(host string) *NTP {
+	if _, _, err := net.SplitHostPort(host); err != nil {
+		host = net.JoinHostPort(host, ntpDefaultPort)
+	}
+	return &NTP{addr: host}
+}
+
+func (n *NTP) String() string {
+	return "ntp://" + n.addr
+}
+
+// Offset sends one request and computes the offset from the four timestamps, cancelling
+// out the network delay on the assumption that it is symmetric
+func (n *NTP) Offset(ctx context.Context) (time.Duration, error) {
+	var dialer net.Dialer
+	conn, err := dialer.DialContext(ctx, "udp", n.addr)
+	if err != nil {
+		return 0, fmt.Errorf("failed to reach NTP server %s: %w", n.addr, err)
+	}
+	defer conn.Close()
+
+	deadline := time.Now().Add(queryTimeout)
+	if d, ok := ctx.Deadline(); ok && d.Before(deadline) {
+		deadline = d
+	}
+	_ = conn.SetDeadline(deadline)
+
+	request := make([]byte, ntpPacketSize)
+	request[0] = ntpClientMode
+	sent := time.Now()
+	putNTPTime(request[40:], sent) // Echoed back as the originate timestamp
+	if _, err := conn.Write(request); err != nil {
+		return 0, fmt.Errorf("failed to query NTP server %s: %w", n.addr, err)
+	}
+
+	response := make([]byte, ntpPacketSize)
+	size, err := conn.Read(response)
+	received := time.Now()
+	if err != nil {
+		return 0, fmt.Errorf("no reply from NTP server %s: %w", n.addr, err)
+	}
+	if err := checkNTPResponse(response[:size], request[40:48]); err != nil {
+		return 0, fmt.Errorf("NTP server %s: %w", n.addr, err)
+	}
+
+	serverReceived, serverSent := ntpTime(response[32:40]), ntpTime(response[40:48])
+	return (serverReceived.Sub(sent) + serverSent.Sub(received)) / 2, nil
+}
+
+// checkNTPResponse rejects replies that aren't a usable answer to our request
+func checkNTPResponse(response, originate []byte) error {
+	switch {
+	case len(response) < ntpPacketSize:
+		return errors.New("short reply")
+	case response[0]&0x07 != ntpServerMode:
+		return errors.New("reply is not from a server")
+	case response[0]>>6 == 3:
+		return errors.New("server clock is unsynchronized")
+	case response[1] == 0:
+		return errors.New("server sent a kiss-of-death")
+	case string(response[24:32]) != string(originate):
+		return errors.New("reply does not match the request")
+	}
+	return nil
+}
+
+// putNTPTime writes t as a 64-bit NTP timestamp
+func putNTPTime(b []byte, t time.Time) {
+	seconds := uint64(t.Unix() + ntpEpochOffset)
+	fraction := uint64(t.Nanosecond()) << 32 / uint64(time.Second)
+	binary.BigEndian.PutUint32(b[0:], uint32(seconds))
+	binary.BigEndian.PutUint32(b[4:], uint32(fraction))
+}
+
+// ntpTime reads a 64-bit NTP timestamp
+func ntpTime(b []byte) time.Time {
+	seconds := int64(binary.BigEndian.Uint32(b[0:])) - ntpEpochOffset
+	fraction := int64(binary.BigEndian.Uint32(b[4:]))
+	return time.Unix(seconds, fraction*int64(time.Second)>>32)
+}
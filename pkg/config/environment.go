@@ -4,31 +4,80 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
+	"time"
 
 	"gopkg.in/yaml.v3"
 )
 
-// Environment represents a Legion environment configuration
+// ProfilesFileEnv names the environment variable that points at a profiles file other
+// than ~/.legion-sim/environments.yaml
+const ProfilesFileEnv = "LEGION_PROFILES_FILE"
+
+// Environment represents a Legion environment configuration, or profile
 type Environment struct {
-	Name   string `yaml:"name"`
-	URL    string `yaml:"url"`
-	APIKey string `yaml:"api_key,omitempty"`
+	Name   string `yaml:"name" json:"name"`
+	URL    string `yaml:"url" json:"url"`
+	APIKey string `yaml:"api_key,omitempty" json:"api_key,omitempty"` // Name of the environment variable holding the key
+	OrgID  string `yaml:"org_id,omitempty" json:"org_id,omitempty"`   // Organization used when none is given
+	Quota  Quota  `yaml:"quota,omitempty" json:"quota"`
+	HTTP   HTTP   `yaml:"http,omitempty" json:"http"`
+}
+
+// Quota holds an organization's Legion limits. Zero leaves a limit unchecked.
+type Quota struct {
+	CallsPerMinute        int `yaml:"calls_per_minute,omitempty" json:"calls_per_minute,omitempty"`
+	FeedMessagesPerMinute int `yaml:"feed_messages_per_minute,omitempty" json:"feed_messages_per_minute,omitempty"`
+	Entities              int `yaml:"entities,omitempty" json:"entities,omitempty"`
+}
+
+// HTTP tunes the connections made to an environment, e.g. more idle connections for
+// simulations sending hundreds of updates a second. Zero keeps the client's default.
+type HTTP struct {
+	Timeout             time.Duration `yaml:"timeout,omitempty" json:"timeout,omitempty"` // Per request
+	MaxConnsPerHost     int           `yaml:"max_conns_per_host,omitempty" json:"max_conns_per_host,omitempty"`
+	MaxIdleConnsPerHost int           `yaml:"max_idle_conns_per_host,omitempty" json:"max_idle_conns_per_host,omitempty"`
+	IdleConnTimeout     time.Duration `yaml:"idle_conn_timeout,omitempty" json:"idle_conn_timeout,omitempty"`
+	KeepAlive           time.Duration `yaml:"keep_alive,omitempty" json:"keep_alive,omitempty"` // TCP keep-alive probe interval; negative turns probes off
+	DisableKeepAlives   bool          `yaml:"disable_keep_alives,omitempty" json:"disable_keep_alives,omitempty"`
+	DisableHTTP2        bool          `yaml:"disable_http2,omitempty" json:"disable_http2,omitempty"`
 }
 
 // Config holds the environment configurations
 type Config struct {
 	Environments []Environment `yaml:"environments"`
-	Selected     string        `yaml:"selected,omitempty"`
+	Selected     string        `yaml:"selected,omitempty"` // Profile used when none is given
 }
 
-// LoadEnvironments loads environment configurations from the default location
-func LoadEnvironments() (*Config, error) {
+// Find returns the environment with the given name
+func (c *Config) Find(name string) (*Environment, error) {
+	for i := range c.Environments {
+		if c.Environments[i].Name == name {
+			env := c.Environments[i]
+			return &env, nil
+		}
+	}
+	return nil, fmt.Errorf("environment %s not found", name)
+}
+
+// EnvironmentsPath returns the profiles file: $LEGION_PROFILES_FILE when set, otherwise
+// ~/.legion-sim/environments.yaml
+func EnvironmentsPath() (string, error) {
+	if path := os.Getenv(ProfilesFileEnv); path != "" {
+		return path, nil
+	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		return nil, fmt.Errorf("failed to get home directory: %w", err)
+		return "", fmt.Errorf("failed to get home directory: %w", err)
 	}
+	return filepath.Join(homeDir, ".legion-sim", "environments.yaml"), nil
+}
 
-	configPath := filepath.Join(homeDir, ".legion-sim", "environments.yaml")
+// LoadEnvironments loads environment configurations from the default location
+func LoadEnvironments() (*Config, error) {
+	configPath, err := EnvironmentsPath()
+	if err != nil {
+		return nil, err
+	}
 	return LoadEnvironmentsFromFile(configPath)
 }
 
@@ -48,23 +97,25 @@ func LoadEnvironmentsFromFile(path string) (*Config, error) {
 	if err := yaml.Unmarshal(data, &config); err != nil {
 		return nil, fmt.Errorf("failed to parse config file: %w", err)
 	}
+	if config.Selected != "" {
+		if _, err := config.Find(config.Selected); err != nil {
+			return nil, fmt.Errorf("selected profile: %w", err)
+		}
+	}
 
 	return &config, nil
 }
 
 // SaveEnvironments saves the environment configuration
 func SaveEnvironments(config *Config) error {
-	homeDir, err := os.UserHomeDir()
+	configPath, err := EnvironmentsPath()
 	if err != nil {
-		return fmt.Errorf("failed to get home directory: %w", err)
+		return err
 	}
-
-	configDir := filepath.Join(homeDir, ".legion-sim")
-	if err := os.MkdirAll(configDir, 0755); err != nil {
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	configPath := filepath.Join(configDir, "environments.yaml")
 	data, err := yaml.Marshal(config)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
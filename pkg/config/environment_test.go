@@ -0,0 +1,54 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadProfiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "environments.yaml")
+	data := `selected: staging
+environments:
+  - name: dev
+    url: https://legion-dev.example.com
+    api_key: LEGION_DEV_API_KEY
+  - name: staging
+    url: https://legion-staging.example.com
+    org_id: 4f0c2a9e-1b7d-4c55-9a7e-2d9f8e6b1c30
+    http:
+      timeout: 10s
+      max_idle_conns_per_host: 64
+      disable_http2: true
+`
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv(ProfilesFileEnv, path)
+
+	cfg, err := LoadEnvironments()
+	if err != nil {
+		t.Fatalf("LoadEnvironments: %v", err)
+	}
+	env, err := cfg.Find(cfg.Selected)
+	if err != nil {
+		t.Fatalf("Find: %v", err)
+	}
+	if env.URL != "https://legion-staging.example.com" || env.OrgID != "4f0c2a9e-1b7d-4c55-9a7e-2d9f8e6b1c30" {
+		t.Errorf("unexpected profile %+v", env)
+	}
+	if env.HTTP.Timeout != 10*time.Second || env.HTTP.MaxIdleConnsPerHost != 64 || !env.HTTP.DisableHTTP2 {
+		t.Errorf("unexpected HTTP settings %+v", env.HTTP)
+	}
+	if _, err := cfg.Find("prod"); err == nil {
+		t.Error("expected an unknown profile to fail")
+	}
+
+	if err := os.WriteFile(path, []byte("selected: prod\n"+data[len("selected: staging\n"):]), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadEnvironments(); err == nil {
+		t.Error("expected a selected profile that does not exist to fail")
+	}
+}
@@ -0,0 +1,93 @@
+package control
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// ListParams fetches the tunable parameters from a simulation's control endpoint
+func ListParams(ctx context.Context, baseURL string) ([]Param, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+"/v1/params", nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	var params []Param
+	if err := do(req, &params); err != nil {
+		return nil, err
+	}
+	return params, nil
+}
+
+// SetParam changes one parameter on a simulation's control endpoint
+func SetParam(ctx context.Context, baseURL, token, name string, value float64) (Param, error) {
+	body, err := json.Marshal(SetRequest{Value: value})
+	if err != nil {
+		return Param{}, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	endpoint := strings.TrimRight(baseURL, "/") + "/v1/params/" + url.PathEscape(name)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return Param{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	var param Param
+	if err := do(req, &param); err != nil {
+		return Param{}, err
+	}
+	return param, nil
+}
+
+// Reload sends configuration values to a simulation's control endpoint. Values that
+// can't change while running come back in the result's Rejected list.
+func Reload(ctx context.Context, baseURL, token string, params map[string]interface{}) (simulation.ReloadResult, error) {
+	body, err := json.Marshal(ReloadRequest{Params: params})
+	if err != nil {
+		return simulation.ReloadResult{}, fmt.Errorf("failed to encode request: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, strings.TrimRight(baseURL, "/")+"/v1/reload", bytes.NewReader(body))
+	if err != nil {
+		return simulation.ReloadResult{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+
+	var result simulation.ReloadResult
+	if err := do(req, &result); err != nil {
+		return simulation.ReloadResult{}, err
+	}
+	return result, nil
+}
+
+func do(req *http.Request, out interface{}) error {
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to control endpoint: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("control endpoint returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,240 @@
+// Package control lets facilitators adjust a whitelisted set of parameters on a running
+// simulation over HTTP.
+//
+// The simulation serves:
+//
+//	GET  /v1/params         parameters with current values and bounds
+//	PUT  /v1/params/{name}  {"value": 0.8} sets one parameter
+//	POST /v1/reload         {"params": {...}} reloads configuration values
+//
+// Writes require "Authorization: Bearer <token>" when the panel has a token.
+package control
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// DefaultListenAddr is the default control endpoint address
+const DefaultListenAddr = ":7600"
+
+// Param is a runtime-tunable parameter
+type Param struct {
+	Name        string  `json:"name"`
+	Description string  `json:"description"`
+	Value       float64 `json:"value"`
+	Min         float64 `json:"min"`
+	Max         float64 `json:"max"`
+	Integer     bool    `json:"integer,omitempty"`
+}
+
+// Change is an accepted parameter update waiting to be applied
+type Change struct {
+	Name   string    `json:"name"`
+	Old    float64   `json:"old"`
+	New    float64   `json:"new"`
+	Source string    `json:"source"` // Remote address of the facilitator
+	Time   time.Time `json:"time"`
+}
+
+// SetRequest is the body of a parameter update
+type SetRequest struct {
+	Value float64 `json:"value"`
+}
+
+// ReloadRequest is the body of a configuration reload
+type ReloadRequest struct {
+	Params map[string]interface{} `json:"params"`
+}
+
+// ReloadFunc applies configuration values to the running simulation
+type ReloadFunc func(params map[string]interface{}, source string) (simulation.ReloadResult, error)
+
+// Panel holds the tunable parameters and the changes not yet picked up by the simulation
+type Panel struct {
+	params  map[string]*Param
+	order   []string
+	pending []Change
+	token   string
+	reload  ReloadFunc
+	mu      sync.Mutex
+}
+
+// NewPanel creates an empty panel. An empty token leaves writes unauthenticated.
+func NewPanel(token string) *Panel {
+	return &Panel{params: make(map[string]*Param), token: token}
+}
+
+// Register adds a parameter to the whitelist with its starting value
+func (p *Panel) Register(param Param) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if _, exists := p.params[param.Name]; !exists {
+		p.order = append(p.order, param.Name)
+	}
+	p.params[param.Name] = &param
+}
+
+// OnReload enables the reload endpoint
+func (p *Panel) OnReload(fn ReloadFunc) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reload = fn
+}
+
+// Params returns the registered parameters in registration order
+func (p *Panel) Params() []Param {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	params := make([]Param, 0, len(p.order))
+	for _, name := range p.order {
+		params = append(params, *p.params[name])
+	}
+	return params
+}
+
+// Check reports whether value is within the parameter's bounds
+func (p Param) Check(value float64) error {
+	if value < p.Min || value > p.Max {
+		return fmt.Errorf("%s must be between %g and %g", p.Name, p.Min, p.Max)
+	}
+	if p.Integer && value != float64(int64(value)) {
+		return fmt.Errorf("%s must be a whole number", p.Name)
+	}
+	return nil
+}
+
+// Set validates and queues a new value. The simulation applies it on its next Drain.
+func (p *Panel) Set(name string, value float64, source string) (Param, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	param, ok := p.params[name]
+	if !ok {
+		return Param{}, fmt.Errorf("unknown or non-tunable parameter %q", name)
+	}
+	if err := param.Check(value); err != nil {
+		return *param, err
+	}
+
+	if value != param.Value {
+		p.pending = append(p.pending, Change{Name: name, Old: param.Value, New: value, Source: source, Time: time.Now()})
+		param.Value = value
+	}
+	return *param, nil
+}
+
+// Drain returns and clears the changes accepted since the last call
+func (p *Panel) Drain() []Change {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	changes := p.pending
+	p.pending = nil
+	return changes
+}
+
+// Handler serves the parameter endpoints
+func (p *Panel) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/params", func(w http.ResponseWriter, _ *http.Request) {
+		writeJSON(w, http.StatusOK, p.Params())
+	})
+	mux.HandleFunc("PUT /v1/params/{name}", func(w http.ResponseWriter, r *http.Request) {
+		if !p.authorized(r) {
+			http.Error(w, "missing or invalid control token", http.StatusUnauthorized)
+			return
+		}
+
+		var req SetRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		param, err := p.Set(r.PathValue("name"), req.Value, clientAddr(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		writeJSON(w, http.StatusOK, param)
+	})
+	mux.HandleFunc("POST /v1/reload", func(w http.ResponseWriter, r *http.Request) {
+		if !p.authorized(r) {
+			http.Error(w, "missing or invalid control token", http.StatusUnauthorized)
+			return
+		}
+
+		p.mu.Lock()
+		reload := p.reload
+		p.mu.Unlock()
+		if reload == nil {
+			http.Error(w, "this simulation does not support configuration reload", http.StatusNotFound)
+			return
+		}
+
+		var req ReloadRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 64*1024)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+
+		result, err := reload(req.Params, clientAddr(r))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusServiceUnavailable)
+			return
+		}
+		writeJSON(w, http.StatusOK, result)
+	})
+	return mux
+}
+
+// authorized checks the bearer token when one is configured
+func (p *Panel) authorized(r *http.Request) bool {
+	if p.token == "" {
+		return true
+	}
+	token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+	return ok && subtle.ConstantTimeCompare([]byte(token), []byte(p.token)) == 1
+}
+
+// Serve starts the control endpoint in the background. Shut it down with the returned server.
+func (p *Panel) Serve(addr string) *http.Server {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           p.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("Control endpoint stopped: %v", err)
+		}
+	}()
+	if p.token == "" {
+		logger.Warnf("Control endpoint on %s has no token; anyone who can reach it can change the run", addr)
+	}
+	logger.Infof("%s Facilitators can tune the run with: legion-sim tune http://<host>%s", logger.IconNetwork, addr)
+	return server
+}
+
+func clientAddr(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
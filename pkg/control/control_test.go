@@ -0,0 +1,92 @@
+package control
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+func TestSetParamQueuesValidatedChanges(t *testing.T) {
+	panel := NewPanel("secret")
+	panel.Register(Param{Name: "success_rate_modifier", Value: 1, Min: 0.1, Max: 2})
+	panel.Register(Param{Name: "update_decimation", Value: 1, Min: 1, Max: 20, Integer: true})
+
+	server := httptest.NewServer(panel.Handler())
+	defer server.Close()
+	ctx := context.Background()
+
+	if _, err := SetParam(ctx, server.URL, "", "success_rate_modifier", 0.5); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected unauthorized without a token, got %v", err)
+	}
+	if _, err := SetParam(ctx, server.URL, "secret", "success_rate_modifier", 5); err == nil {
+		t.Error("expected out-of-range value to be rejected")
+	}
+	if _, err := SetParam(ctx, server.URL, "secret", "update_decimation", 2.5); err == nil {
+		t.Error("expected fractional value for an integer parameter to be rejected")
+	}
+	if _, err := SetParam(ctx, server.URL, "secret", "sim_duration", 10); err == nil {
+		t.Error("expected parameter outside the whitelist to be rejected")
+	}
+
+	param, err := SetParam(ctx, server.URL, "secret", "success_rate_modifier", 0.5)
+	if err != nil {
+		t.Fatalf("SetParam: %v", err)
+	}
+	if param.Value != 0.5 {
+		t.Errorf("expected 0.5, got %v", param.Value)
+	}
+
+	changes := panel.Drain()
+	if len(changes) != 1 || changes[0].Old != 1 || changes[0].New != 0.5 || changes[0].Source == "" {
+		t.Fatalf("unexpected changes: %+v", changes)
+	}
+	if len(panel.Drain()) != 0 {
+		t.Error("expected Drain to clear pending changes")
+	}
+
+	params, err := ListParams(ctx, server.URL)
+	if err != nil {
+		t.Fatalf("ListParams: %v", err)
+	}
+	if len(params) != 2 || params[0].Value != 0.5 {
+		t.Errorf("unexpected params: %+v", params)
+	}
+}
+
+func TestReload(t *testing.T) {
+	panel := NewPanel("secret")
+	server := httptest.NewServer(panel.Handler())
+	defer server.Close()
+	ctx := context.Background()
+
+	if _, err := Reload(ctx, server.URL, "secret", nil); err == nil || !strings.Contains(err.Error(), "404") {
+		t.Errorf("expected not found before a reloader is set, got %v", err)
+	}
+
+	var got map[string]interface{}
+	panel.OnReload(func(params map[string]interface{}, source string) (simulation.ReloadResult, error) {
+		got = params
+		return simulation.ReloadResult{
+			Applied:  []simulation.ParamChange{{Name: "log_level", Old: "info", New: "debug"}},
+			Rejected: []simulation.ParamChange{{Name: "num_waves", Old: "3", New: "5", Reason: "requires a restart"}},
+		}, nil
+	})
+
+	if _, err := Reload(ctx, server.URL, "", map[string]interface{}{"log_level": "debug"}); err == nil || !strings.Contains(err.Error(), "401") {
+		t.Errorf("expected unauthorized without a token, got %v", err)
+	}
+
+	result, err := Reload(ctx, server.URL, "secret", map[string]interface{}{"log_level": "debug", "num_waves": 5})
+	if err != nil {
+		t.Fatalf("Reload: %v", err)
+	}
+	if got["log_level"] != "debug" || got["num_waves"] != float64(5) {
+		t.Errorf("unexpected params passed to the reloader: %v", got)
+	}
+	if len(result.Applied) != 1 || len(result.Rejected) != 1 || result.Rejected[0].Reason == "" {
+		t.Errorf("unexpected result: %+v", result)
+	}
+}
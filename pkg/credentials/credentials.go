@@ -0,0 +1,89 @@
+// Package credentials stores Legion API credentials in the OS keychain or, where no
+// keychain is available, in a passphrase-encrypted file under ~/.legion-sim.
+package credentials
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+)
+
+// Environment variables controlling credential storage
+const (
+	// EnvStore selects the backend: "keychain" or "file" (default: keychain when available)
+	EnvStore = "LEGION_CREDENTIAL_STORE"
+	// EnvPassphrase supplies the encrypted file passphrase non-interactively
+	EnvPassphrase = "LEGION_CREDENTIALS_PASSPHRASE"
+)
+
+// ServiceName identifies legion-sim entries in the OS keychain
+const ServiceName = "legion-sim"
+
+// ErrNotFound is returned when no credential is stored for an environment
+var ErrNotFound = errors.New("credential not found")
+
+// Store persists one secret per Legion environment
+type Store interface {
+	Get(environment string) (string, error)
+	Set(environment, secret string) error
+	Delete(environment string) error
+	// Name describes the backend for status output
+	Name() string
+}
+
+// PassphraseFunc supplies the passphrase for the encrypted file store
+type PassphraseFunc func(confirm bool) (string, error)
+
+// Open returns the configured credential store. The passphrase callback is only used
+// by the encrypted file backend, and only when LEGION_CREDENTIALS_PASSPHRASE is unset.
+func Open(passphrase PassphraseFunc) (Store, error) {
+	switch backend := os.Getenv(EnvStore); backend {
+	case "keychain":
+		store := newKeychainStore()
+		if store == nil {
+			return nil, fmt.Errorf("no OS keychain available on %s", runtime.GOOS)
+		}
+		return store, nil
+	case "file":
+		return openFileStore(passphrase)
+	case "":
+		if store := newKeychainStore(); store != nil {
+			return store, nil
+		}
+		return openFileStore(passphrase)
+	default:
+		return nil, fmt.Errorf("unknown credential store %q (supported: keychain, file)", backend)
+	}
+}
+
+func openFileStore(passphrase PassphraseFunc) (Store, error) {
+	path, err := DefaultFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return NewFileStore(path, func(confirm bool) (string, error) {
+		if value := os.Getenv(EnvPassphrase); value != "" {
+			return value, nil
+		}
+		if passphrase == nil {
+			return "", fmt.Errorf("credentials file is encrypted; set %s", EnvPassphrase)
+		}
+		return passphrase(confirm)
+	}), nil
+}
+
+// Mask returns a redacted form of a secret suitable for display
+func Mask(secret string) string {
+	if len(secret) <= 8 {
+		return "********"
+	}
+	return secret[:4] + "…" + secret[len(secret)-4:]
+}
+
+// commandAvailable reports whether an executable is on PATH
+func commandAvailable(name string) bool {
+	_, err := exec.LookPath(name)
+	return err == nil
+}
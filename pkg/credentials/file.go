@@ -0,0 +1,202 @@
+package credentials
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// pbkdf2Iterations follows current OWASP guidance for PBKDF2-HMAC-SHA256
+var pbkdf2Iterations = 600000
+
+// encryptedFile is the on-disk format of the credentials file
+type encryptedFile struct {
+	Version    int    `json:"version"`
+	KDF        string `json:"kdf"`
+	Iterations int    `json:"iterations"`
+	Salt       []byte `json:"salt"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// FileStore keeps credentials in an AES-256-GCM encrypted file keyed by a passphrase
+type FileStore struct {
+	path       string
+	passphrase PassphraseFunc
+	cached     string // Passphrase, once obtained
+}
+
+// DefaultFilePath returns ~/.legion-sim/credentials.enc
+func DefaultFilePath() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".legion-sim", "credentials.enc"), nil
+}
+
+// NewFileStore creates an encrypted file store at path
+func NewFileStore(path string, passphrase PassphraseFunc) *FileStore {
+	return &FileStore{path: path, passphrase: passphrase}
+}
+
+func (f *FileStore) Name() string {
+	return "encrypted file (" + f.path + ")"
+}
+
+func (f *FileStore) Get(environment string) (string, error) {
+	secrets, err := f.load()
+	if err != nil {
+		return "", err
+	}
+	secret, ok := secrets[environment]
+	if !ok {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (f *FileStore) Set(environment, secret string) error {
+	secrets, err := f.load()
+	if err != nil {
+		return err
+	}
+	secrets[environment] = secret
+	return f.save(secrets)
+}
+
+func (f *FileStore) Delete(environment string) error {
+	secrets, err := f.load()
+	if err != nil {
+		return err
+	}
+	if _, ok := secrets[environment]; !ok {
+		return ErrNotFound
+	}
+	delete(secrets, environment)
+	return f.save(secrets)
+}
+
+// load decrypts the credentials file; a missing file is an empty store
+func (f *FileStore) load() (map[string]string, error) {
+	data, err := os.ReadFile(f.path)
+	if errors.Is(err, os.ErrNotExist) {
+		return make(map[string]string), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read credentials file: %w", err)
+	}
+
+	var file encryptedFile
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("failed to parse credentials file: %w", err)
+	}
+	if file.Version != 1 || file.KDF != "pbkdf2-sha256" {
+		return nil, fmt.Errorf("unsupported credentials file format (version %d, kdf %q)", file.Version, file.KDF)
+	}
+
+	passphrase, err := f.getPassphrase(false)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(passphrase, file.Salt, file.Iterations)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, file.Nonce, file.Ciphertext, nil)
+	if err != nil {
+		f.cached = ""
+		return nil, fmt.Errorf("failed to decrypt credentials file (wrong passphrase?)")
+	}
+
+	secrets := make(map[string]string)
+	if err := json.Unmarshal(plaintext, &secrets); err != nil {
+		return nil, fmt.Errorf("failed to parse decrypted credentials: %w", err)
+	}
+	return secrets, nil
+}
+
+// save encrypts and writes the credentials file with a fresh salt and nonce
+func (f *FileStore) save(secrets map[string]string) error {
+	_, statErr := os.Stat(f.path)
+	passphrase, err := f.getPassphrase(errors.Is(statErr, os.ErrNotExist))
+	if err != nil {
+		return err
+	}
+
+	plaintext, err := json.Marshal(secrets)
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials: %w", err)
+	}
+
+	file := encryptedFile{
+		Version:    1,
+		KDF:        "pbkdf2-sha256",
+		Iterations: pbkdf2Iterations,
+		Salt:       make([]byte, 16),
+	}
+	if _, err := rand.Read(file.Salt); err != nil {
+		return fmt.Errorf("failed to generate salt: %w", err)
+	}
+
+	gcm, err := newGCM(passphrase, file.Salt, file.Iterations)
+	if err != nil {
+		return err
+	}
+	file.Nonce = make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(file.Nonce); err != nil {
+		return fmt.Errorf("failed to generate nonce: %w", err)
+	}
+	file.Ciphertext = gcm.Seal(nil, file.Nonce, plaintext, nil)
+
+	data, err := json.MarshalIndent(file, "", "  ")
+	if err != nil {
+		return fmt.Errorf("failed to marshal credentials file: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(f.path), 0700); err != nil {
+		return fmt.Errorf("failed to create config directory: %w", err)
+	}
+	if err := os.WriteFile(f.path, data, 0600); err != nil {
+		return fmt.Errorf("failed to write credentials file: %w", err)
+	}
+	return nil
+}
+
+func (f *FileStore) getPassphrase(confirm bool) (string, error) {
+	if f.cached != "" {
+		return f.cached, nil
+	}
+	if f.passphrase == nil {
+		return "", fmt.Errorf("no passphrase available for credentials file")
+	}
+	passphrase, err := f.passphrase(confirm)
+	if err != nil {
+		return "", err
+	}
+	if passphrase == "" {
+		return "", fmt.Errorf("passphrase must not be empty")
+	}
+	f.cached = passphrase
+	return passphrase, nil
+}
+
+func newGCM(passphrase string, salt []byte, iterations int) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, iterations, 32)
+	if err != nil {
+		return nil, fmt.Errorf("failed to derive key: %w", err)
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
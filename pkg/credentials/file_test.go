@@ -0,0 +1,47 @@
+package credentials
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestFileStoreRoundTrip(t *testing.T) {
+	pbkdf2Iterations = 1000 // Keep the test fast
+	path := filepath.Join(t.TempDir(), "credentials.enc")
+	passphrase := func(bool) (string, error) { return "correct horse", nil }
+
+	store := NewFileStore(path, passphrase)
+	if err := store.Set("staging", "sk-live-123456789"); err != nil {
+		t.Fatalf("Set: %v", err)
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(raw), "sk-live") {
+		t.Fatal("secret stored in plaintext")
+	}
+
+	// A fresh store with the same passphrase can read it back
+	secret, err := NewFileStore(path, passphrase).Get("staging")
+	if err != nil || secret != "sk-live-123456789" {
+		t.Fatalf("Get = %q, %v", secret, err)
+	}
+
+	// The wrong passphrase fails to decrypt
+	wrong := NewFileStore(path, func(bool) (string, error) { return "battery staple", nil })
+	if _, err := wrong.Get("staging"); err == nil {
+		t.Fatal("expected decryption failure with wrong passphrase")
+	}
+
+	if err := store.Delete("staging"); err != nil {
+		t.Fatalf("Delete: %v", err)
+	}
+	if _, err := store.Get("staging"); !errors.Is(err, ErrNotFound) {
+		t.Fatalf("expected ErrNotFound after delete, got %v", err)
+	}
+}
@@ -0,0 +1,98 @@
+package credentials
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+// keychainStore shells out to the platform keychain tool: `security` on macOS and
+// `secret-tool` (libsecret) on Linux.
+type keychainStore struct {
+	tool string
+}
+
+// newKeychainStore returns nil when no supported keychain tool is installed
+func newKeychainStore() *keychainStore {
+	switch runtime.GOOS {
+	case "darwin":
+		if commandAvailable("security") {
+			return &keychainStore{tool: "security"}
+		}
+	case "linux":
+		if commandAvailable("secret-tool") {
+			return &keychainStore{tool: "secret-tool"}
+		}
+	}
+	return nil
+}
+
+func (k *keychainStore) Name() string {
+	if k.tool == "security" {
+		return "macOS Keychain"
+	}
+	return "Secret Service (libsecret)"
+}
+
+func (k *keychainStore) Get(environment string) (string, error) {
+	var cmd *exec.Cmd
+	if k.tool == "security" {
+		cmd = exec.Command("security", "find-generic-password", "-s", ServiceName, "-a", environment, "-w")
+	} else {
+		cmd = exec.Command("secret-tool", "lookup", "service", ServiceName, "account", environment)
+	}
+
+	out, err := cmd.Output()
+	if err != nil {
+		// Both tools exit non-zero when the item doesn't exist
+		return "", ErrNotFound
+	}
+
+	secret := strings.TrimRight(string(out), "\r\n")
+	if secret == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (k *keychainStore) Set(environment, secret string) error {
+	// Both tools read the secret from stdin, keeping it out of the process list
+	var cmd *exec.Cmd
+	if k.tool == "security" {
+		// A trailing -w without a value makes security prompt for the password, then
+		// again to confirm it
+		cmd = exec.Command("security", "add-generic-password", "-U", "-s", ServiceName, "-a", environment,
+			"-l", fmt.Sprintf("Legion API key (%s)", environment), "-w")
+		cmd.Stdin = strings.NewReader(secret + "\n" + secret + "\n")
+	} else {
+		cmd = exec.Command("secret-tool", "store", "--label", fmt.Sprintf("Legion API key (%s)", environment),
+			"service", ServiceName, "account", environment)
+		cmd.Stdin = strings.NewReader(secret)
+	}
+	return runKeychain(cmd, "store credential")
+}
+
+func (k *keychainStore) Delete(environment string) error {
+	if _, err := k.Get(environment); err != nil {
+		return err
+	}
+
+	var cmd *exec.Cmd
+	if k.tool == "security" {
+		cmd = exec.Command("security", "delete-generic-password", "-s", ServiceName, "-a", environment)
+	} else {
+		cmd = exec.Command("secret-tool", "clear", "service", ServiceName, "account", environment)
+	}
+	return runKeychain(cmd, "delete credential")
+}
+
+func runKeychain(cmd *exec.Cmd, action string) error {
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("failed to %s: %w: %s", action, err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
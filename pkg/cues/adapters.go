@@ -0,0 +1,171 @@
+package cues
+
+import (
+	"bufio"
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+)
+
+// PollInterval is how often files and feeds are checked for new cues
+const PollInterval = 500 * time.Millisecond
+
+// Handler serves the cue webhook. Posts require "Authorization: Bearer <token>" when
+// token is set.
+func Handler(inbox *Inbox, token string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /v1/cues", func(w http.ResponseWriter, r *http.Request) {
+		if token != "" {
+			got, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+			if !ok || subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+				http.Error(w, "missing or invalid cue token", http.StatusUnauthorized)
+				return
+			}
+		}
+
+		body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		cues, err := Decode(body)
+		if err != nil {
+			http.Error(w, fmt.Sprintf("invalid cue: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := inbox.Push(SourceHTTP, cues...); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusAccepted)
+		_ = json.NewEncoder(w).Encode(map[string]int{"accepted": len(cues)})
+	})
+	return mux
+}
+
+// Serve starts the cue webhook in the background. Shut it down with the returned server.
+func Serve(inbox *Inbox, addr, token string) *http.Server {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           Handler(inbox, token),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("Cue endpoint stopped: %v", err)
+		}
+	}()
+	if token == "" {
+		logger.Warnf("Cue endpoint on %s has no token; anyone who can reach it can add tracks", addr)
+	}
+	logger.Infof("%s Accepting threat cues at http://<host>%s/v1/cues", logger.IconNetwork, addr)
+	return server
+}
+
+// Tail reads cues from a file of JSON lines, from its start and then as lines are
+// appended, until ctx is done. A line that is not a valid cue is logged and skipped.
+func Tail(ctx context.Context, inbox *Inbox, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return fmt.Errorf("failed to open cue file: %w", err)
+	}
+
+	go func() {
+		defer file.Close()
+		reader := bufio.NewReader(file)
+		var partial string
+		for {
+			line, err := reader.ReadString('\n')
+			partial += line
+			if err == nil {
+				pushLine(inbox, path, strings.TrimSpace(partial))
+				partial = ""
+				continue
+			}
+			if !errors.Is(err, io.EOF) {
+				logger.Errorf("Cue file %s: %v", path, err)
+				return
+			}
+			// Wait for the writer to append more
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(PollInterval):
+			}
+		}
+	}()
+	return nil
+}
+
+// pushLine queues the cues on one line of a cue file
+func pushLine(inbox *Inbox, path, line string) {
+	if line == "" {
+		return
+	}
+	cues, err := Decode([]byte(line))
+	if err == nil {
+		err = inbox.Push(SourceFile, cues...)
+	}
+	if err != nil {
+		logger.Warnf("Skipping cue in %s: %v", path, err)
+	}
+}
+
+// PollFeed reads cues from the payloads recorded on a Legion feed definition after
+// the poll starts, until ctx is done
+func PollFeed(ctx context.Context, inbox *Inbox, c client.LegionAPI, orgID string, feedID uuid.UUID) {
+	go func() {
+		orgCtx := client.WithOrgID(ctx, orgID)
+		since := time.Now()
+		seen := make(map[uuid.UUID]bool)
+		ticker := time.NewTicker(PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			start := since
+			result, err := c.SearchFeedData(orgCtx, &models.FeedDataSearchRequest{FeedID: feedID, StartTime: &start})
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.Debugf("Cue feed %s poll failed: %v", feedID, err)
+				}
+				continue
+			}
+			for _, data := range result.Results {
+				// The window overlaps the last poll so nothing recorded on its edge is missed
+				if seen[data.ID] || data.Payload == nil {
+					continue
+				}
+				seen[data.ID] = true
+				if data.RecordedAt.After(since) {
+					since = data.RecordedAt
+				}
+				cues, err := Decode(*data.Payload)
+				if err == nil {
+					err = inbox.Push(SourceFeed, cues...)
+				}
+				if err != nil {
+					logger.Warnf("Skipping cue on feed %s: %v", feedID, err)
+				}
+			}
+		}
+	}()
+}
@@ -0,0 +1,169 @@
+// Package cues takes external threat cues (a new track's position, heading and type)
+// from other sensor simulators or live feeds so a running simulation can spawn or
+// steer tracks it did not plan.
+//
+// Cues arrive through any of three adapters, which all push into an Inbox the
+// simulation drains once per tick:
+//
+//	POST /v1/cues        a cue or an array of cues as JSON (Serve)
+//	JSON lines in a file appended to while the run is going (Tail)
+//	payloads on a Legion feed, polled (PollFeed)
+package cues
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Source kinds
+const (
+	SourceHTTP = "http" // Webhook on an address
+	SourceFile = "file" // JSON lines appended to a file
+	SourceFeed = "feed" // Payloads on a Legion feed definition
+)
+
+// Cue describes an externally detected threat. ID ties later cues to the same track;
+// a cue without one always spawns a new track.
+type Cue struct {
+	ID         string    `json:"id,omitempty"`
+	Lat        float64   `json:"lat"`
+	Lon        float64   `json:"lon"`
+	Alt        float64   `json:"alt"`               // Meters above the WGS84 ellipsoid
+	HeadingDeg float64   `json:"heading"`           // True heading the track is flying
+	SpeedMps   float64   `json:"speed,omitempty"`   // 0 leaves the speed to the simulation
+	Type       string    `json:"type,omitempty"`    // Size class, e.g. GROUP_2; empty leaves it to the simulation
+	Faction    string    `json:"faction,omitempty"` // Attacking faction; empty uses the first
+	Time       time.Time `json:"time,omitempty"`    // When the cue was observed; set on receipt when empty
+	Source     string    `json:"source,omitempty"`  // Set by the adapter that received it
+}
+
+// Validate rejects cues that cannot place a track
+func (c Cue) Validate() error {
+	if c.Lat < -90 || c.Lat > 90 || math.IsNaN(c.Lat) {
+		return fmt.Errorf("latitude %g out of range", c.Lat)
+	}
+	if c.Lon < -180 || c.Lon > 180 || math.IsNaN(c.Lon) {
+		return fmt.Errorf("longitude %g out of range", c.Lon)
+	}
+	if c.HeadingDeg < 0 || c.HeadingDeg >= 360 || math.IsNaN(c.HeadingDeg) {
+		return fmt.Errorf("heading %g must be in [0, 360)", c.HeadingDeg)
+	}
+	if c.SpeedMps < 0 || math.IsNaN(c.SpeedMps) {
+		return fmt.Errorf("speed %g must not be negative", c.SpeedMps)
+	}
+	return nil
+}
+
+// Decode reads a cue or an array of cues
+func Decode(data []byte) ([]Cue, error) {
+	data = []byte(strings.TrimSpace(string(data)))
+	if len(data) > 0 && data[0] == '[' {
+		var cues []Cue
+		if err := json.Unmarshal(data, &cues); err != nil {
+			return nil, err
+		}
+		return cues, nil
+	}
+	var cue Cue
+	if err := json.Unmarshal(data, &cue); err != nil {
+		return nil, err
+	}
+	return []Cue{cue}, nil
+}
+
+// Inbox holds cues received but not yet picked up by the simulation
+type Inbox struct {
+	pending  []Cue
+	received int
+	rejected int
+	mu       sync.Mutex
+}
+
+// NewInbox creates an empty inbox
+func NewInbox() *Inbox {
+	return &Inbox{}
+}
+
+// Push validates cues from a source and queues them, rejecting the batch if any is invalid
+func (in *Inbox) Push(source string, cues ...Cue) error {
+	now := time.Now()
+	for i := range cues {
+		if err := cues[i].Validate(); err != nil {
+			in.mu.Lock()
+			in.rejected += len(cues)
+			in.mu.Unlock()
+			return fmt.Errorf("cue %d: %w", i+1, err)
+		}
+		cues[i].Source = source
+		if cues[i].Time.IsZero() {
+			cues[i].Time = now
+		}
+	}
+
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	in.pending = append(in.pending, cues...)
+	in.received += len(cues)
+	return nil
+}
+
+// Drain returns and clears the cues received since the last call
+func (in *Inbox) Drain() []Cue {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	cues := in.pending
+	in.pending = nil
+	return cues
+}
+
+// Counts returns how many cues were accepted and rejected
+func (in *Inbox) Counts() (received, rejected int) {
+	in.mu.Lock()
+	defer in.mu.Unlock()
+	return in.received, in.rejected
+}
+
+// Source is one place cues are read from
+type Source struct {
+	Kind   string // SourceHTTP, SourceFile or SourceFeed
+	Target string // Listen address, file path or feed definition ID
+}
+
+// String returns the source as written in a spec
+func (s Source) String() string {
+	return s.Kind + ":" + s.Target
+}
+
+// ParseSources parses "kind:target" entries separated by semicolons, e.g.
+// "http::7900;file:/tmp/cues.jsonl;feed:4f0c2a9e-1b7d-4c55-9a7e-2d9f8e6b1c30"
+func ParseSources(spec string) ([]Source, error) {
+	var sources []Source
+	seen := make(map[string]bool)
+	for _, entry := range strings.Split(spec, ";") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		kind, target, ok := strings.Cut(entry, ":")
+		kind, target = strings.ToLower(strings.TrimSpace(kind)), strings.TrimSpace(target)
+		if !ok || target == "" {
+			return nil, fmt.Errorf("cue source %q: expected kind:target", entry)
+		}
+		switch kind {
+		case SourceHTTP, SourceFile, SourceFeed:
+		default:
+			return nil, fmt.Errorf("cue source %q: kind must be %s, %s or %s", entry, SourceHTTP, SourceFile, SourceFeed)
+		}
+		source := Source{Kind: kind, Target: target}
+		if seen[source.String()] {
+			return nil, fmt.Errorf("cue source %s is given twice", source)
+		}
+		seen[source.String()] = true
+		sources = append(sources, source)
+	}
+	return sources, nil
+}
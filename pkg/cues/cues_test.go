@@ -0,0 +1,142 @@
+package cues
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseSources(t *testing.T) {
+	sources, err := ParseSources("http::7900; file:/tmp/cues.jsonl ;FEED:4f0c2a9e-1b7d-4c55-9a7e-2d9f8e6b1c30")
+	if err != nil {
+		t.Fatalf("ParseSources: %v", err)
+	}
+	want := []Source{
+		{Kind: SourceHTTP, Target: ":7900"},
+		{Kind: SourceFile, Target: "/tmp/cues.jsonl"},
+		{Kind: SourceFeed, Target: "4f0c2a9e-1b7d-4c55-9a7e-2d9f8e6b1c30"},
+	}
+	if len(sources) != len(want) {
+		t.Fatalf("expected %d sources, got %v", len(want), sources)
+	}
+	for i := range want {
+		if sources[i] != want[i] {
+			t.Errorf("source %d: expected %v, got %v", i, want[i], sources[i])
+		}
+	}
+
+	for _, spec := range []string{"http", "udp::9000", "file:", "http::7900;http::7900"} {
+		if _, err := ParseSources(spec); err == nil {
+			t.Errorf("expected %q to be rejected", spec)
+		}
+	}
+}
+
+func TestInboxRejectsInvalidBatch(t *testing.T) {
+	inbox := NewInbox()
+	batch, err := Decode([]byte(`[{"lat":34,"lon":-117,"heading":90},{"lat":95,"lon":-117,"heading":90}]`))
+	if err != nil {
+		t.Fatalf("Decode: %v", err)
+	}
+	if err := inbox.Push(SourceHTTP, batch...); err == nil {
+		t.Error("expected a batch with an out-of-range latitude to be rejected")
+	}
+	if err := inbox.Push(SourceHTTP, Cue{Lat: 34, Lon: -117, HeadingDeg: 360}); err == nil {
+		t.Error("expected heading 360 to be rejected")
+	}
+	if err := inbox.Push(SourceFile, Cue{ID: "a", Lat: 34, Lon: -117, HeadingDeg: 270}); err != nil {
+		t.Fatalf("Push: %v", err)
+	}
+
+	cues := inbox.Drain()
+	if len(cues) != 1 || cues[0].Source != SourceFile || cues[0].Time.IsZero() {
+		t.Errorf("expected one stamped cue from the file, got %+v", cues)
+	}
+	if len(inbox.Drain()) != 0 {
+		t.Error("expected Drain to clear the inbox")
+	}
+	if received, rejected := inbox.Counts(); received != 1 || rejected != 3 {
+		t.Errorf("expected 1 received and 3 rejected, got %d and %d", received, rejected)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	inbox := NewInbox()
+	server := httptest.NewServer(Handler(inbox, "secret"))
+	defer server.Close()
+
+	post := func(token, body string) int {
+		req, _ := http.NewRequest(http.MethodPost, server.URL+"/v1/cues", strings.NewReader(body))
+		if token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+		resp, err := http.DefaultClient.Do(req)
+		if err != nil {
+			t.Fatalf("post: %v", err)
+		}
+		resp.Body.Close()
+		return resp.StatusCode
+	}
+
+	cue := `{"id":"ext-1","lat":34.1,"lon":-117.6,"heading":210,"type":"GROUP_2"}`
+	if code := post("", cue); code != http.StatusUnauthorized {
+		t.Errorf("expected 401 without a token, got %d", code)
+	}
+	if code := post("secret", "{"); code != http.StatusBadRequest {
+		t.Errorf("expected 400 for malformed JSON, got %d", code)
+	}
+	if code := post("secret", `{"lat":34,"lon":200,"heading":0}`); code != http.StatusUnprocessableEntity {
+		t.Errorf("expected 422 for an out-of-range cue, got %d", code)
+	}
+	if code := post("secret", cue); code != http.StatusAccepted {
+		t.Errorf("expected 202, got %d", code)
+	}
+
+	cues := inbox.Drain()
+	if len(cues) != 1 || cues[0].ID != "ext-1" || cues[0].Type != "GROUP_2" || cues[0].Source != SourceHTTP {
+		t.Errorf("unexpected cues %+v", cues)
+	}
+}
+
+func TestTailFollowsAppends(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cues.jsonl")
+	if err := os.WriteFile(path, []byte("{\"id\":\"a\",\"lat\":34,\"lon\":-117,\"heading\":10}\nnot a cue\n"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	inbox := NewInbox()
+	if err := Tail(ctx, inbox, path); err != nil {
+		t.Fatalf("Tail: %v", err)
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, _ = file.WriteString("{\"id\":\"b\",\"lat\":34,\"lon\":-117,")
+	_, _ = file.WriteString("\"heading\":20}\n")
+	file.Close()
+
+	var got []Cue
+	deadline := time.Now().Add(5 * time.Second)
+	for len(got) < 2 && time.Now().Before(deadline) {
+		got = append(got, inbox.Drain()...)
+		time.Sleep(50 * time.Millisecond)
+	}
+	if len(got) != 2 || got[0].ID != "a" || got[1].ID != "b" {
+		t.Errorf("expected cues a and b, got %+v", got)
+	}
+}
+
+func TestTailMissingFile(t *testing.T) {
+	if err := Tail(context.Background(), NewInbox(), filepath.Join(t.TempDir(), "missing.jsonl")); err == nil {
+		t.Error("expected a missing cue file to fail")
+	}
+}
@@ -0,0 +1,177 @@
+// Package daemon runs simulations in the background and reports on them over a local
+// control socket.
+//
+// A daemon serves on a Unix socket in Dir():
+//
+//	GET /v1/status  the run's phase, stats and update buffer health
+//
+// The socket is only reachable by the user who started the run.
+package daemon
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// SocketExt is the extension of daemon control sockets in Dir
+const SocketExt = ".sock"
+
+// Report is what a daemon answers to a status request
+type Report struct {
+	PID        int               `json:"pid"`
+	Simulation string            `json:"simulation"`
+	Started    time.Time         `json:"started"`
+	Status     simulation.Status `json:"status"`
+}
+
+// StatusFunc returns the daemon's current report
+type StatusFunc func() Report
+
+// Dir returns the directory holding daemon sockets and logs, ~/.legion-sim/daemons
+func Dir() (string, error) {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to get home directory: %w", err)
+	}
+	return filepath.Join(homeDir, ".legion-sim", "daemons"), nil
+}
+
+// LogPath returns where the daemon serving a socket writes its output
+func LogPath(socket string) string {
+	return strings.TrimSuffix(socket, SocketExt) + ".log"
+}
+
+// Sockets lists the control sockets in Dir, including those of daemons that have died
+func Sockets() ([]string, error) {
+	dir, err := Dir()
+	if err != nil {
+		return nil, err
+	}
+	entries, err := os.ReadDir(dir)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to list daemons: %w", err)
+	}
+
+	var sockets []string
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), SocketExt) {
+			sockets = append(sockets, filepath.Join(dir, entry.Name()))
+		}
+	}
+	sort.Strings(sockets)
+	return sockets, nil
+}
+
+// Listen serves status on a Unix socket until the returned server is shut down. A socket
+// left by a daemon that died is replaced; one a live daemon is serving is not.
+func Listen(path string, status StatusFunc) (*http.Server, error) {
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return nil, fmt.Errorf("failed to create socket directory: %w", err)
+	}
+	if _, err := Query(context.Background(), path); err == nil {
+		return nil, fmt.Errorf("a daemon is already serving %s", path)
+	}
+	_ = os.Remove(path)
+
+	listener, err := net.Listen("unix", path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open control socket: %w", err)
+	}
+	if err := os.Chmod(path, 0600); err != nil {
+		_ = listener.Close()
+		return nil, fmt.Errorf("failed to restrict control socket: %w", err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/status", func(w http.ResponseWriter, _ *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(status())
+	})
+	server := &http.Server{Handler: mux, ReadHeaderTimeout: 5 * time.Second}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("Control socket stopped: %v", err)
+		}
+	}()
+	logger.Infof("%s Control socket on %s", logger.IconNetwork, path)
+	return server, nil
+}
+
+// Query asks the daemon on a socket for its status
+func Query(ctx context.Context, path string) (Report, error) {
+	httpClient := &http.Client{
+		Transport: &http.Transport{
+			DialContext: func(ctx context.Context, _, _ string) (net.Conn, error) {
+				var dialer net.Dialer
+				return dialer.DialContext(ctx, "unix", path)
+			},
+		},
+		Timeout: 5 * time.Second,
+	}
+
+	// The host is ignored; every request goes to the socket
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "http://daemon/v1/status", nil)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return Report{}, fmt.Errorf("failed to connect to daemon: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return Report{}, fmt.Errorf("daemon returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	var report Report
+	if err := json.NewDecoder(resp.Body).Decode(&report); err != nil {
+		return Report{}, fmt.Errorf("failed to parse response: %w", err)
+	}
+	return report, nil
+}
+
+// Start runs this program again in the background with args, detached from the terminal,
+// writing its output to logPath. It returns the daemon's process ID.
+func Start(args []string, logPath string) (int, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return 0, fmt.Errorf("failed to find executable: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(logPath), 0700); err != nil {
+		return 0, fmt.Errorf("failed to create log directory: %w", err)
+	}
+	logFile, err := os.OpenFile(logPath, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0600)
+	if err != nil {
+		return 0, fmt.Errorf("failed to open daemon log: %w", err)
+	}
+	defer func() { _ = logFile.Close() }()
+
+	cmd := exec.Command(executable, args...)
+	cmd.Stdout = logFile
+	cmd.Stderr = logFile
+	cmd.SysProcAttr = detached()
+	if err := cmd.Start(); err != nil {
+		return 0, fmt.Errorf("failed to start daemon: %w", err)
+	}
+	pid := cmd.Process.Pid
+	_ = cmd.Process.Release()
+	return pid, nil
+}
@@ -0,0 +1,61 @@
+package daemon
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+func TestListenAndQuery(t *testing.T) {
+	// Unix socket paths are limited to about 100 bytes, which t.TempDir can exceed
+	dir, err := os.MkdirTemp("", "daemon")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "run"+SocketExt)
+
+	started := time.Now().Truncate(time.Second)
+	server, err := Listen(path, func() Report {
+		return Report{PID: 42, Simulation: "Drone Swarm Combat", Started: started, Status: simulation.Status{
+			Phase:  "engagement",
+			Tick:   17,
+			Stats:  map[string]float64{"uas_eliminated": 3},
+			Buffer: &simulation.BufferHealth{State: simulation.BufferOK, Pending: 5},
+		}}
+	})
+	if err != nil {
+		t.Fatalf("Listen: %v", err)
+	}
+
+	report, err := Query(context.Background(), path)
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if report.PID != 42 || !report.Started.Equal(started) || report.Status.Phase != "engagement" || report.Status.Tick != 17 ||
+		report.Status.Stats["uas_eliminated"] != 3 || report.Status.Buffer == nil || report.Status.Buffer.Pending != 5 {
+		t.Errorf("unexpected report %+v", report)
+	}
+
+	if _, err := Listen(path, func() Report { return Report{} }); err == nil {
+		t.Error("expected a socket a live daemon serves to be refused")
+	}
+
+	// A socket left behind by a daemon that died is taken over
+	_ = server.Close()
+	if _, err := Query(context.Background(), path); err == nil {
+		t.Error("expected a closed daemon not to answer")
+	}
+	server, err = Listen(path, func() Report { return Report{PID: 7} })
+	if err != nil {
+		t.Fatalf("Listen over a stale socket: %v", err)
+	}
+	defer server.Close()
+	if report, err := Query(context.Background(), path); err != nil || report.PID != 7 {
+		t.Errorf("expected the new daemon to answer, got %+v, %v", report, err)
+	}
+}
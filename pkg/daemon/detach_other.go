@@ -0,0 +1,11 @@
+//go:build !unix
+
+package daemon
+
+import "syscall"
+
+// detached leaves the daemon in the starting process's group; it keeps running after
+// the starting command exits
+func detached() *syscall.SysProcAttr {
+	return nil
+}
@@ -0,0 +1,261 @@
+// Package datapack fetches versioned model data (capability catalogs, Pk tables,
+// terrain) that scenarios reference by name and version. Packs are cached locally and
+// checked against a pinned checksum so analytic results can be traced to the exact
+// data they were computed from.
+//
+// A pack is a single YAML file served from a source as <source>/<name>/<version>/pack.yaml.
+// The source may be an http(s) URL or a local directory. Its contents are interpreted by
+// the simulation that uses it.
+package datapack
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// packFile is the file name of a pack inside its name/version directory
+const packFile = "pack.yaml"
+
+// maxPackSize bounds a download so a bad source can't fill the disk
+const maxPackSize = 64 << 20
+
+// namePattern restricts names and versions to characters that are safe in paths and URLs
+var namePattern = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9._-]*$`)
+
+// Ref names a pack version, optionally pinned to a checksum
+type Ref struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+	SHA256  string `json:"sha256,omitempty"` // Hex checksum of pack.yaml; empty accepts whatever the source serves
+}
+
+// ParseRef parses "name@version" or "name@version#sha256:<hex>"
+func ParseRef(s string) (Ref, error) {
+	spec, pin, pinned := strings.Cut(strings.TrimSpace(s), "#")
+	name, version, ok := strings.Cut(spec, "@")
+	if !ok {
+		return Ref{}, fmt.Errorf("data pack %q: expected name@version[#sha256:<hex>]", s)
+	}
+
+	ref := Ref{Name: strings.TrimSpace(name), Version: strings.TrimSpace(version)}
+	if !namePattern.MatchString(ref.Name) || !namePattern.MatchString(ref.Version) {
+		return Ref{}, fmt.Errorf("data pack %q: name and version may only contain letters, digits, '.', '_' and '-'", s)
+	}
+
+	if pinned {
+		sum, ok := strings.CutPrefix(strings.TrimSpace(pin), "sha256:")
+		if !ok {
+			return Ref{}, fmt.Errorf("data pack %q: checksum must be sha256:<hex>", s)
+		}
+		sum = strings.ToLower(sum)
+		if decoded, err := hex.DecodeString(sum); err != nil || len(decoded) != sha256.Size {
+			return Ref{}, fmt.Errorf("data pack %q: invalid sha256 checksum", s)
+		}
+		ref.SHA256 = sum
+	}
+	return ref, nil
+}
+
+// String returns the reference in the form ParseRef accepts
+func (r Ref) String() string {
+	if r.SHA256 == "" {
+		return r.Name + "@" + r.Version
+	}
+	return r.Name + "@" + r.Version + "#sha256:" + r.SHA256
+}
+
+// Pack is a resolved pack. Its Ref always carries the actual checksum.
+type Pack struct {
+	Ref
+	Path string // Cached copy
+	Data []byte
+}
+
+// Decode unmarshals the pack's YAML into v
+func (p *Pack) Decode(v interface{}) error {
+	if err := yaml.Unmarshal(p.Data, v); err != nil {
+		return fmt.Errorf("failed to parse data pack %s: %w", p.Ref, err)
+	}
+	return nil
+}
+
+// Store resolves packs from a source through a local cache
+type Store struct {
+	CacheDir   string
+	Source     string // http(s) base URL or local directory; empty only serves cached packs
+	HTTPClient *http.Client
+}
+
+// DefaultStore caches under ~/.legion-sim/packs (or LEGION_DATA_PACK_CACHE) and fetches
+// from LEGION_DATA_PACK_SOURCE
+func DefaultStore() (*Store, error) {
+	cacheDir := os.Getenv("LEGION_DATA_PACK_CACHE")
+	if cacheDir == "" {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("failed to get home directory: %w", err)
+		}
+		cacheDir = filepath.Join(homeDir, ".legion-sim", "packs")
+	}
+	return &Store{
+		CacheDir:   cacheDir,
+		Source:     os.Getenv("LEGION_DATA_PACK_SOURCE"),
+		HTTPClient: &http.Client{Timeout: 2 * time.Minute},
+	}, nil
+}
+
+// Resolve returns the pack from the cache, fetching it from the source first if needed.
+// A pinned checksum must match; a cached version is never silently replaced.
+func (s *Store) Resolve(ctx context.Context, ref Ref) (*Pack, error) {
+	path := s.cachePath(ref)
+	data, err := os.ReadFile(path)
+	if err == nil {
+		if err := verify(ref, data); err != nil {
+			return nil, fmt.Errorf("%w (cached at %s)", err, path)
+		}
+	} else {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("failed to read cached data pack %s: %w", ref, err)
+		}
+		if data, err = s.fetch(ctx, ref); err != nil {
+			return nil, err
+		}
+		if err := verify(ref, data); err != nil {
+			return nil, err
+		}
+		if err := writeAtomic(path, data); err != nil {
+			return nil, fmt.Errorf("failed to cache data pack %s: %w", ref, err)
+		}
+	}
+
+	pack := &Pack{Ref: ref, Path: path, Data: data}
+	pack.SHA256 = checksum(data)
+	return pack, nil
+}
+
+// List returns every cached pack with its checksum
+func (s *Store) List() ([]Ref, error) {
+	var refs []Ref
+	names, err := os.ReadDir(s.CacheDir)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read data pack cache: %w", err)
+	}
+
+	for _, name := range names {
+		if !name.IsDir() {
+			continue
+		}
+		versions, err := os.ReadDir(filepath.Join(s.CacheDir, name.Name()))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data pack cache: %w", err)
+		}
+		for _, version := range versions {
+			data, err := os.ReadFile(filepath.Join(s.CacheDir, name.Name(), version.Name(), packFile))
+			if err != nil {
+				continue
+			}
+			refs = append(refs, Ref{Name: name.Name(), Version: version.Name(), SHA256: checksum(data)})
+		}
+	}
+	return refs, nil
+}
+
+// cachePath returns where a pack version is cached
+func (s *Store) cachePath(ref Ref) string {
+	return filepath.Join(s.CacheDir, ref.Name, ref.Version, packFile)
+}
+
+// fetch reads a pack from the source
+func (s *Store) fetch(ctx context.Context, ref Ref) ([]byte, error) {
+	if s.Source == "" {
+		return nil, fmt.Errorf("data pack %s is not cached and no source is configured (set LEGION_DATA_PACK_SOURCE)", ref)
+	}
+
+	if !strings.HasPrefix(s.Source, "http://") && !strings.HasPrefix(s.Source, "https://") {
+		dir := strings.TrimPrefix(s.Source, "file://")
+		data, err := os.ReadFile(filepath.Join(dir, ref.Name, ref.Version, packFile))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read data pack %s: %w", ref, err)
+		}
+		return data, nil
+	}
+
+	url := strings.TrimSuffix(s.Source, "/") + "/" + ref.Name + "/" + ref.Version + "/" + packFile
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	client := s.HTTPClient
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download data pack %s: %w", ref, err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download data pack %s: %s", ref, resp.Status)
+	}
+	data, err := io.ReadAll(io.LimitReader(resp.Body, maxPackSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to download data pack %s: %w", ref, err)
+	}
+	if len(data) > maxPackSize {
+		return nil, fmt.Errorf("data pack %s is larger than %d bytes", ref, maxPackSize)
+	}
+	return data, nil
+}
+
+// verify checks data against the pinned checksum, if any
+func verify(ref Ref, data []byte) error {
+	if ref.SHA256 == "" {
+		return nil
+	}
+	if sum := checksum(data); sum != ref.SHA256 {
+		return fmt.Errorf("data pack %s@%s checksum mismatch: got sha256:%s", ref.Name, ref.Version, sum)
+	}
+	return nil
+}
+
+// checksum returns the hex SHA-256 of data
+func checksum(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
+
+// writeAtomic writes data via a temporary file so a partial download is never cached
+func writeAtomic(path string, data []byte) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(path), ".pack-*")
+	if err != nil {
+		return err
+	}
+	defer func() { _ = os.Remove(tmp.Name()) }()
+
+	if _, err := tmp.Write(data); err != nil {
+		_ = tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
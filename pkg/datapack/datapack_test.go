@@ -0,0 +1,64 @@
+package datapack
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestParseRef(t *testing.T) {
+	sum := strings.Repeat("ab", 32)
+	ref, err := ParseRef("cuas-baseline@1.2.0#sha256:" + strings.ToUpper(sum))
+	if err != nil {
+		t.Fatalf("ParseRef: %v", err)
+	}
+	if ref.Name != "cuas-baseline" || ref.Version != "1.2.0" || ref.SHA256 != sum {
+		t.Fatalf("unexpected ref: %+v", ref)
+	}
+
+	for _, bad := range []string{"cuas-baseline", "../etc@1", "pack@1#md5:abcd", "pack@1#sha256:abcd"} {
+		if _, err := ParseRef(bad); err == nil {
+			t.Errorf("ParseRef(%q): expected an error", bad)
+		}
+	}
+}
+
+func TestResolveCachesAndVerifies(t *testing.T) {
+	source := t.TempDir()
+	packDir := filepath.Join(source, "cuas", "1.0.0")
+	if err := os.MkdirAll(packDir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	data := []byte("pk:\n  kinetic: [0.6, 0.8]\n")
+	if err := os.WriteFile(filepath.Join(packDir, packFile), data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := &Store{CacheDir: t.TempDir(), Source: source}
+	pack, err := store.Resolve(context.Background(), Ref{Name: "cuas", Version: "1.0.0"})
+	if err != nil {
+		t.Fatalf("Resolve: %v", err)
+	}
+	if pack.SHA256 != checksum(data) {
+		t.Fatalf("checksum = %s, want %s", pack.SHA256, checksum(data))
+	}
+
+	// The cached copy is served even once the source is gone
+	store.Source = ""
+	if _, err := store.Resolve(context.Background(), pack.Ref); err != nil {
+		t.Fatalf("Resolve from cache: %v", err)
+	}
+
+	wrong := pack.Ref
+	wrong.SHA256 = strings.Repeat("0", 64)
+	if _, err := store.Resolve(context.Background(), wrong); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("expected a checksum mismatch, got %v", err)
+	}
+
+	refs, err := store.List()
+	if err != nil || len(refs) != 1 || refs[0].Name != "cuas" {
+		t.Fatalf("List() = %v, %v", refs, err)
+	}
+}
@@ -0,0 +1,102 @@
+package flightlog
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Header names accepted for each CSV column, matched case-insensitively
+var csvColumns = map[string][]string{
+	"id":   {"id", "track_id", "track", "vehicle", "icao"},
+	"time": {"time", "timestamp", "recorded_at"},
+	"lat":  {"lat", "latitude"},
+	"lon":  {"lon", "lng", "long", "longitude"},
+	"alt":  {"alt", "altitude", "alt_m", "altitude_m"},
+}
+
+// readCSV reads a CSV log. The id column is optional; without one every row belongs to
+// a single track. Times are RFC3339 or Unix seconds; a missing altitude is 0.
+func readCSV(r io.Reader) (map[string][]Sample, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+
+	header, err := reader.Read()
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CSV header: %w", err)
+	}
+	cols := map[string]int{}
+	for i, name := range header {
+		name = strings.ToLower(strings.TrimSpace(name))
+		for col, aliases := range csvColumns {
+			for _, alias := range aliases {
+				if name == alias {
+					cols[col] = i
+				}
+			}
+		}
+	}
+	for _, required := range []string{"time", "lat", "lon"} {
+		if _, ok := cols[required]; !ok {
+			return nil, fmt.Errorf("CSV header has no %s column", required)
+		}
+	}
+
+	samples := map[string][]Sample{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("CSV line %d: %w", line, err)
+		}
+
+		field := func(col string) string {
+			if i, ok := cols[col]; ok && i < len(record) {
+				return strings.TrimSpace(record[i])
+			}
+			return ""
+		}
+
+		at, err := parseTime(field("time"))
+		if err != nil {
+			return nil, fmt.Errorf("CSV line %d: %w", line, err)
+		}
+		var sample Sample
+		sample.Time = at
+		if sample.Lat, err = strconv.ParseFloat(field("lat"), 64); err != nil {
+			return nil, fmt.Errorf("CSV line %d: invalid latitude %q", line, field("lat"))
+		}
+		if sample.Lon, err = strconv.ParseFloat(field("lon"), 64); err != nil {
+			return nil, fmt.Errorf("CSV line %d: invalid longitude %q", line, field("lon"))
+		}
+		if alt := field("alt"); alt != "" {
+			if sample.Alt, err = strconv.ParseFloat(alt, 64); err != nil {
+				return nil, fmt.Errorf("CSV line %d: invalid altitude %q", line, alt)
+			}
+		}
+
+		id := field("id")
+		if id == "" {
+			id = "1"
+		}
+		samples[id] = append(samples[id], sample)
+	}
+	return samples, nil
+}
+
+// parseTime accepts RFC3339 timestamps or Unix seconds with an optional fraction
+func parseTime(value string) (time.Time, error) {
+	if at, err := time.Parse(time.RFC3339Nano, value); err == nil {
+		return at, nil
+	}
+	seconds, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid time %q: expected RFC3339 or Unix seconds", value)
+	}
+	return time.Unix(0, int64(seconds*float64(time.Second))).UTC(), nil
+}
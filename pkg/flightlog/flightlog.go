@@ -0,0 +1,152 @@
+// Package flightlog reads recorded drone and aircraft tracks so they can be replayed
+// in a simulation in place of synthetic behavior. It understands three formats:
+//
+//   - CSV with a header row naming id, time, lat, lon and alt columns
+//   - MAVLink telemetry logs (.tlog) as written by ground control stations, using each
+//     vehicle's GLOBAL_POSITION_INT messages
+//   - ADS-B dumps in the SBS BaseStation format (dump1090 port 30003), using the
+//     airborne position messages
+//
+// Positions are WGS84 degrees with altitude in meters above mean sea level.
+package flightlog
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Formats Load understands
+const (
+	FormatCSV  = "csv"
+	FormatTlog = "tlog"
+	FormatSBS  = "sbs"
+)
+
+// Sample is one recorded position
+type Sample struct {
+	Time time.Time
+	Lat  float64
+	Lon  float64
+	Alt  float64 // Meters MSL
+}
+
+// Track is one vehicle's recorded positions in time order
+type Track struct {
+	ID      string // Vehicle identifier from the log (CSV id, MAVLink system ID, ICAO address)
+	Samples []Sample
+}
+
+// Start is the time of the track's first sample
+func (t Track) Start() time.Time {
+	return t.Samples[0].Time
+}
+
+// Duration is the time between the track's first and last samples
+func (t Track) Duration() time.Duration {
+	return t.Samples[len(t.Samples)-1].Time.Sub(t.Samples[0].Time)
+}
+
+// At interpolates the track's position at offset from its first sample. Offsets outside
+// the recording return its first or last sample.
+func (t Track) At(offset time.Duration) Sample {
+	at := t.Start().Add(offset)
+	i := sort.Search(len(t.Samples), func(i int) bool { return !t.Samples[i].Time.Before(at) })
+	switch {
+	case i == 0:
+		return t.Samples[0]
+	case i == len(t.Samples):
+		return t.Samples[len(t.Samples)-1]
+	}
+
+	prev, next := t.Samples[i-1], t.Samples[i]
+	span := next.Time.Sub(prev.Time)
+	if span <= 0 {
+		return next
+	}
+	f := float64(at.Sub(prev.Time)) / float64(span)
+	return Sample{
+		Time: at,
+		Lat:  prev.Lat + (next.Lat-prev.Lat)*f,
+		Lon:  prev.Lon + (next.Lon-prev.Lon)*f,
+		Alt:  prev.Alt + (next.Alt-prev.Alt)*f,
+	}
+}
+
+// Load reads the tracks in the file at path, choosing the format from its extension:
+// .csv, .tlog, or .sbs/.basestation for ADS-B dumps
+func Load(path string) ([]Track, error) {
+	format, err := FormatOf(path)
+	if err != nil {
+		return nil, err
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open flight log: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+
+	tracks, err := Read(f, format)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", filepath.Base(path), err)
+	}
+	return tracks, nil
+}
+
+// FormatOf returns the format of a flight log from its file extension
+func FormatOf(path string) (string, error) {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv":
+		return FormatCSV, nil
+	case ".tlog":
+		return FormatTlog, nil
+	case ".sbs", ".basestation":
+		return FormatSBS, nil
+	}
+	return "", fmt.Errorf("flight log %s: unknown format (expected .csv, .tlog or .sbs)", filepath.Base(path))
+}
+
+// Read parses tracks in the given format from r
+func Read(r io.Reader, format string) ([]Track, error) {
+	var (
+		samples map[string][]Sample
+		err     error
+	)
+	switch format {
+	case FormatCSV:
+		samples, err = readCSV(r)
+	case FormatTlog:
+		samples, err = readTlog(r)
+	case FormatSBS:
+		samples, err = readSBS(r)
+	default:
+		return nil, fmt.Errorf("unknown flight log format %q", format)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buildTracks(samples)
+}
+
+// buildTracks sorts each vehicle's samples by time and orders tracks by ID. Vehicles
+// with fewer than two positions are dropped since they can't be replayed as movement.
+func buildTracks(samples map[string][]Sample) ([]Track, error) {
+	tracks := make([]Track, 0, len(samples))
+	for id, points := range samples {
+		if len(points) < 2 {
+			continue
+		}
+		sort.SliceStable(points, func(i, j int) bool { return points[i].Time.Before(points[j].Time) })
+		tracks = append(tracks, Track{ID: id, Samples: points})
+	}
+	if len(tracks) == 0 {
+		return nil, fmt.Errorf("no tracks with at least two positions")
+	}
+	sort.Slice(tracks, func(i, j int) bool { return tracks[i].ID < tracks[j].ID })
+	return tracks, nil
+}
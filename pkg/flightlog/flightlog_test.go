@@ -0,0 +1,118 @@
+package flightlog
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadCSV(t *testing.T) {
+	log := `Track_ID,Timestamp,Latitude,Longitude,Alt_M
+b,2025-03-01T12:00:10Z,38.9000,-77.0000,120
+a,1740830400,38.8000,-77.1000,100
+a,1740830410.5,38.8010,-77.1010,110
+b,2025-03-01T12:00:00Z,38.9010,-77.0010,130
+`
+	tracks, err := Read(strings.NewReader(log), FormatCSV)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracks) != 2 || tracks[0].ID != "a" || tracks[1].ID != "b" {
+		t.Fatalf("expected tracks a and b, got %+v", tracks)
+	}
+	if got := tracks[1].Samples[0].Alt; got != 130 {
+		t.Errorf("expected b's samples sorted by time, first altitude %v", got)
+	}
+	if got := tracks[0].Duration(); got != 10500*time.Millisecond {
+		t.Errorf("expected a to last 10.5s, got %s", got)
+	}
+
+	if _, err := Read(strings.NewReader("lat,lon\n1,2\n"), FormatCSV); err == nil {
+		t.Error("expected a CSV without a time column to be rejected")
+	}
+}
+
+func TestReadTlog(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	var buf bytes.Buffer
+	// MAVLink 1 and 2 position fixes from system 7, a heartbeat, and noise between packets
+	writeTlogPacket(&buf, start, mavlinkV1Magic, 7, msgGlobalPositionInt, positionPayload(38.8, -77.1, 100))
+	buf.Write([]byte{0x00, 0x13, 0x37})
+	writeTlogPacket(&buf, start.Add(time.Second), mavlinkV2Magic, 7, 0, make([]byte, 9))
+	writeTlogPacket(&buf, start.Add(2*time.Second), mavlinkV2Magic, 7, msgGlobalPositionInt, positionPayload(38.801, -77.101, 110))
+
+	tracks, err := Read(&buf, FormatTlog)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracks) != 1 || tracks[0].ID != "7" || len(tracks[0].Samples) != 2 {
+		t.Fatalf("expected one track from system 7 with two fixes, got %+v", tracks)
+	}
+	last := tracks[0].Samples[1]
+	if math.Abs(last.Lat-38.801) > 1e-7 || math.Abs(last.Lon+77.101) > 1e-7 || last.Alt != 110 {
+		t.Errorf("unexpected decoded fix %+v", last)
+	}
+}
+
+func TestReadSBS(t *testing.T) {
+	dump := `MSG,1,1,1,A1B2C3,1,2025/03/01,12:00:00.000,2025/03/01,12:00:00.000,DRONE1,,,,,,,,,,,0
+MSG,3,1,1,a1b2c3,1,2025/03/01,12:00:00.000,2025/03/01,12:00:00.000,,1000,,,38.80000,-77.10000,,,,,,0
+MSG,3,1,1,A1B2C3,1,2025/03/01,12:00:05.000,2025/03/01,12:00:05.000,,1100,,,38.80500,-77.10500,,,,,,0
+MSG,3,1,1,D4E5F6,1,2025/03/01,12:00:05.000,2025/03/01,12:00:05.000,,900,,,38.9,-77.0
+`
+	tracks, err := Read(strings.NewReader(dump), FormatSBS)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tracks) != 1 || tracks[0].ID != "A1B2C3" {
+		t.Fatalf("expected only A1B2C3 to have a track, got %+v", tracks)
+	}
+	if got := tracks[0].Samples[0].Alt; math.Abs(got-304.8) > 1e-9 {
+		t.Errorf("expected altitude in meters, got %v", got)
+	}
+}
+
+func TestTrackAt(t *testing.T) {
+	start := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	track := Track{ID: "a", Samples: []Sample{
+		{Time: start, Lat: 10, Lon: 20, Alt: 100},
+		{Time: start.Add(10 * time.Second), Lat: 11, Lon: 22, Alt: 200},
+	}}
+
+	mid := track.At(5 * time.Second)
+	if mid.Lat != 10.5 || mid.Lon != 21 || mid.Alt != 150 {
+		t.Errorf("expected the midpoint, got %+v", mid)
+	}
+	if before := track.At(-time.Second); before.Lat != 10 {
+		t.Errorf("expected the first sample before the recording, got %+v", before)
+	}
+	if after := track.At(time.Minute); after.Lat != 11 {
+		t.Errorf("expected the last sample after the recording, got %+v", after)
+	}
+}
+
+func positionPayload(lat, lon, altM float64) []byte {
+	payload := make([]byte, globalPositionIntLength)
+	binary.LittleEndian.PutUint32(payload[4:], uint32(int32(math.Round(lat*1e7))))
+	binary.LittleEndian.PutUint32(payload[8:], uint32(int32(math.Round(lon*1e7))))
+	binary.LittleEndian.PutUint32(payload[12:], uint32(int32(altM*1000)))
+	return payload
+}
+
+func writeTlogPacket(buf *bytes.Buffer, at time.Time, magic byte, sysID byte, msgID uint32, payload []byte) {
+	_ = binary.Write(buf, binary.BigEndian, uint64(at.UnixMicro()))
+	if magic == mavlinkV1Magic {
+		buf.Write([]byte{magic, byte(len(payload)), 0, sysID, 1, byte(msgID)})
+	} else {
+		// MAVLink 2 drops trailing zero bytes from the payload
+		for len(payload) > 1 && payload[len(payload)-1] == 0 {
+			payload = payload[:len(payload)-1]
+		}
+		buf.Write([]byte{magic, byte(len(payload)), 0, 0, 0, sysID, 1, byte(msgID), byte(msgID >> 8), byte(msgID >> 16)})
+	}
+	buf.Write(payload)
+	buf.Write([]byte{0, 0}) // Checksum
+}
@@ -0,0 +1,60 @@
+package flightlog
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SBS BaseStation fields used from "MSG,3" airborne position messages
+const (
+	sbsTransmissionType = 1
+	sbsHexIdent         = 4
+	sbsDateGenerated    = 6
+	sbsTimeGenerated    = 7
+	sbsAltitude         = 11 // Feet
+	sbsLatitude         = 14
+	sbsLongitude        = 15
+	sbsMinFields        = 16
+
+	sbsAirbornePosition = "3"
+	feetToMeters        = 0.3048
+)
+
+// readSBS reads an SBS BaseStation dump, building a track per ICAO address from its
+// airborne position messages. Other message types and malformed lines are skipped,
+// since live dumps routinely contain partial lines.
+func readSBS(r io.Reader) (map[string][]Sample, error) {
+	samples := map[string][]Sample{}
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		fields := strings.Split(strings.TrimSpace(scanner.Text()), ",")
+		if len(fields) < sbsMinFields || fields[0] != "MSG" || fields[sbsTransmissionType] != sbsAirbornePosition {
+			continue
+		}
+
+		at, err := time.Parse("2006/01/02 15:04:05.000", fields[sbsDateGenerated]+" "+fields[sbsTimeGenerated])
+		if err != nil {
+			continue
+		}
+		lat, errLat := strconv.ParseFloat(fields[sbsLatitude], 64)
+		lon, errLon := strconv.ParseFloat(fields[sbsLongitude], 64)
+		if errLat != nil || errLon != nil {
+			continue
+		}
+		altFt, _ := strconv.ParseFloat(fields[sbsAltitude], 64)
+
+		id := strings.ToUpper(strings.TrimSpace(fields[sbsHexIdent]))
+		samples[id] = append(samples[id], Sample{Time: at, Lat: lat, Lon: lon, Alt: altFt * feetToMeters})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read SBS dump: %w", err)
+	}
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("SBS dump has no airborne position messages")
+	}
+	return samples, nil
+}
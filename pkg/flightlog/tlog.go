@@ -0,0 +1,112 @@
+package flightlog
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+// MAVLink framing
+const (
+	mavlinkV1Magic        = 0xFE
+	mavlinkV2Magic        = 0xFD
+	mavlinkV1HeaderLen    = 6 // magic, len, seq, sysid, compid, msgid
+	mavlinkV2HeaderLen    = 10
+	mavlinkChecksumLen    = 2
+	mavlinkSignatureLen   = 13
+	mavlinkIncompatSigned = 0x01
+
+	msgGlobalPositionInt    = 33
+	globalPositionIntLength = 28
+	tlogTimestampLen        = 8 // Big-endian microseconds since the Unix epoch before each packet
+)
+
+// readTlog reads a MAVLink telemetry log: each packet is preceded by the time the ground
+// station received it. Each system ID becomes a track built from its GLOBAL_POSITION_INT
+// messages. Checksums aren't verified since that needs every message's CRC seed; bytes
+// that don't frame as a packet are skipped until the stream resynchronizes.
+func readTlog(r io.Reader) (map[string][]Sample, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read tlog: %w", err)
+	}
+
+	samples := map[string][]Sample{}
+	for i := 0; i+tlogTimestampLen < len(data); {
+		packet := data[i+tlogTimestampLen:]
+
+		var sysID byte
+		var msgID uint32
+		var payload []byte
+		var size int
+		switch packet[0] {
+		case mavlinkV1Magic:
+			if len(packet) < mavlinkV1HeaderLen {
+				i++
+				continue
+			}
+			n := int(packet[1])
+			size = mavlinkV1HeaderLen + n + mavlinkChecksumLen
+			if len(packet) < size {
+				i++
+				continue
+			}
+			sysID, msgID = packet[3], uint32(packet[5])
+			payload = packet[mavlinkV1HeaderLen : mavlinkV1HeaderLen+n]
+		case mavlinkV2Magic:
+			if len(packet) < mavlinkV2HeaderLen {
+				i++
+				continue
+			}
+			n := int(packet[1])
+			size = mavlinkV2HeaderLen + n + mavlinkChecksumLen
+			if packet[2]&mavlinkIncompatSigned != 0 {
+				size += mavlinkSignatureLen
+			}
+			if len(packet) < size {
+				i++
+				continue
+			}
+			sysID = packet[5]
+			msgID = uint32(packet[7]) | uint32(packet[8])<<8 | uint32(packet[9])<<16
+			payload = packet[mavlinkV2HeaderLen : mavlinkV2HeaderLen+n]
+		default:
+			i++
+			continue
+		}
+
+		if msgID == msgGlobalPositionInt {
+			received := time.UnixMicro(int64(binary.BigEndian.Uint64(data[i:]))).UTC()
+			if sample, ok := globalPositionInt(payload, received); ok {
+				id := strconv.Itoa(int(sysID))
+				samples[id] = append(samples[id], sample)
+			}
+		}
+		i += tlogTimestampLen + size
+	}
+
+	if len(samples) == 0 {
+		return nil, fmt.Errorf("tlog has no GLOBAL_POSITION_INT messages")
+	}
+	return samples, nil
+}
+
+// globalPositionInt decodes a GLOBAL_POSITION_INT payload. MAVLink 2 trims trailing zero
+// bytes, so short payloads are padded back out. Fixes at 0,0 mean no position yet.
+func globalPositionInt(payload []byte, received time.Time) (Sample, bool) {
+	if len(payload) < globalPositionIntLength {
+		padded := make([]byte, globalPositionIntLength)
+		copy(padded, payload)
+		payload = padded
+	}
+
+	lat := int32(binary.LittleEndian.Uint32(payload[4:]))
+	lon := int32(binary.LittleEndian.Uint32(payload[8:]))
+	alt := int32(binary.LittleEndian.Uint32(payload[12:])) // Millimeters MSL
+	if lat == 0 && lon == 0 {
+		return Sample{}, false
+	}
+	return Sample{Time: received, Lat: float64(lat) / 1e7, Lon: float64(lon) / 1e7, Alt: float64(alt) / 1000}, true
+}
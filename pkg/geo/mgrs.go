@@ -0,0 +1,174 @@
+package geo
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// MGRS lettering. I and O are never used.
+const (
+	latBands   = "CDEFGHJKLMNPQRSTUVWX" // 8° bands from 80°S; X spans 72°N-84°N
+	rowLetters = "ABCDEFGHJKLMNPQRSTUV" // 100 km rows, repeating every 2,000 km
+)
+
+// columnSets are the 100 km column letters, cycling every three zones
+var columnSets = [3]string{"ABCDEFGH", "JKLMNPQR", "STUVWXYZ"}
+
+// MGRS covers the UTM latitudes; the polar UPS regions are not supported
+const (
+	mgrsMinLat = -80.0
+	mgrsMaxLat = 84.0
+)
+
+// FormatMGRS converts a position to an MGRS grid reference with the given number of
+// digits per axis (0-5; 5 is 1 m, 4 is 10 m, ... 0 is the 100 km square)
+func FormatMGRS(lat, lon float64, precision int) (string, error) {
+	if precision < 0 || precision > 5 {
+		return "", fmt.Errorf("MGRS precision must be between 0 and 5, got %d", precision)
+	}
+	if lat < mgrsMinLat || lat >= mgrsMaxLat {
+		return "", fmt.Errorf("latitude %.6f is outside MGRS coverage (80°S to 84°N)", lat)
+	}
+
+	utm := ToUTM(lat, lon)
+	band := latBands[bandIndex(lat)]
+
+	column := int(math.Floor(utm.Easting/100000)) - 1
+	set := columnSets[(utm.Zone-1)%3]
+	if column < 0 || column >= len(set) {
+		return "", fmt.Errorf("easting %.0f is outside zone %d", utm.Easting, utm.Zone)
+	}
+
+	row := int(math.Floor(utm.Northing/100000)) % len(rowLetters)
+	if utm.Zone%2 == 0 {
+		row = (row + 5) % len(rowLetters)
+	}
+
+	divisor := math.Pow(10, float64(5-precision))
+	east := int(math.Floor(math.Mod(utm.Easting, 100000) / divisor))
+	north := int(math.Floor(math.Mod(utm.Northing, 100000) / divisor))
+
+	ref := fmt.Sprintf("%d%c%c%c", utm.Zone, band, set[column], rowLetters[row])
+	if precision > 0 {
+		ref += fmt.Sprintf("%0*d%0*d", precision, east, precision, north)
+	}
+	return ref, nil
+}
+
+// ParseMGRS converts an MGRS grid reference such as "18SUJ2348306479" (spaces allowed)
+// to the latitude and longitude of the south-west corner of the referenced square
+func ParseMGRS(ref string) (LatLon, error) {
+	s := strings.ToUpper(strings.Join(strings.Fields(ref), ""))
+
+	// Zone: one or two digits
+	i := 0
+	for i < len(s) && i < 2 && unicode.IsDigit(rune(s[i])) {
+		i++
+	}
+	if i == 0 {
+		return LatLon{}, fmt.Errorf("invalid MGRS reference %q: missing zone number", ref)
+	}
+	zone, _ := strconv.Atoi(s[:i])
+	if zone < 1 || zone > 60 {
+		return LatLon{}, fmt.Errorf("invalid MGRS reference %q: zone %d out of range", ref, zone)
+	}
+
+	if len(s) < i+3 {
+		return LatLon{}, fmt.Errorf("invalid MGRS reference %q: expected band and 100 km square letters", ref)
+	}
+	bandIdx := strings.IndexByte(latBands, s[i])
+	if bandIdx < 0 {
+		return LatLon{}, fmt.Errorf("invalid MGRS reference %q: unknown latitude band %c", ref, s[i])
+	}
+	column := strings.IndexByte(columnSets[(zone-1)%3], s[i+1])
+	if column < 0 {
+		return LatLon{}, fmt.Errorf("invalid MGRS reference %q: column %c is not used in zone %d", ref, s[i+1], zone)
+	}
+	row := strings.IndexByte(rowLetters, s[i+2])
+	if row < 0 {
+		return LatLon{}, fmt.Errorf("invalid MGRS reference %q: unknown row letter %c", ref, s[i+2])
+	}
+
+	digits := s[i+3:]
+	if len(digits)%2 != 0 || len(digits) > 10 {
+		return LatLon{}, fmt.Errorf("invalid MGRS reference %q: expected an even number of digits (up to 10)", ref)
+	}
+	for _, r := range digits {
+		if !unicode.IsDigit(r) {
+			return LatLon{}, fmt.Errorf("invalid MGRS reference %q: unexpected character %c", ref, r)
+		}
+	}
+
+	precision := len(digits) / 2
+	var east, north float64
+	if precision > 0 {
+		e, _ := strconv.Atoi(digits[:precision])
+		n, _ := strconv.Atoi(digits[precision:])
+		scale := math.Pow(10, float64(5-precision))
+		east, north = float64(e)*scale, float64(n)*scale
+	}
+
+	if zone%2 == 0 {
+		row = (row - 5 + len(rowLetters)) % len(rowLetters)
+	}
+
+	north += float64(row) * 100000
+	bandSouth := mgrsMinLat + float64(bandIdx)*8
+	isNorth := bandSouth >= 0
+
+	// Rows repeat every 2,000 km; pick the cycle that lands inside the latitude band
+	minNorthing := ToUTMZone(bandSouth, centralMeridian(zone), zone).Northing - 100000
+	for north < minNorthing {
+		north += 2000000
+	}
+
+	utm := UTM{
+		Zone:     zone,
+		North:    isNorth,
+		Easting:  float64(column+1)*100000 + east,
+		Northing: north,
+	}
+	pos := utm.LatLon()
+
+	bandNorth := bandSouth + 8
+	if latBands[bandIdx] == 'X' {
+		bandNorth = mgrsMaxLat
+	}
+	if pos.Lat < bandSouth-0.5 || pos.Lat > bandNorth+0.5 {
+		return LatLon{}, fmt.Errorf("invalid MGRS reference %q: square %c%c does not exist in band %c",
+			ref, s[i+1], s[i+2], s[i])
+	}
+	return pos, nil
+}
+
+// ParsePosition accepts either "lat,lon" in decimal degrees or an MGRS grid reference
+func ParsePosition(value string) (LatLon, error) {
+	value = strings.TrimSpace(value)
+	if latStr, lonStr, ok := strings.Cut(value, ","); ok {
+		lat, err := strconv.ParseFloat(strings.TrimSpace(latStr), 64)
+		if err != nil {
+			return LatLon{}, fmt.Errorf("invalid latitude %q", latStr)
+		}
+		lon, err := strconv.ParseFloat(strings.TrimSpace(lonStr), 64)
+		if err != nil {
+			return LatLon{}, fmt.Errorf("invalid longitude %q", lonStr)
+		}
+		if lat < -90 || lat > 90 || lon < -180 || lon > 180 {
+			return LatLon{}, fmt.Errorf("position %q is out of range", value)
+		}
+		return LatLon{Lat: lat, Lon: lon}, nil
+	}
+	return ParseMGRS(value)
+}
+
+// bandIndex returns the latitude band for a latitude inside MGRS coverage
+func bandIndex(lat float64) int {
+	idx := int(math.Floor((lat - mgrsMinLat) / 8))
+	if idx > len(latBands)-1 {
+		idx = len(latBands) - 1
+	}
+	return idx
+}
@@ -0,0 +1,128 @@
+package geo
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+// distanceM is an equirectangular distance, accurate enough for meter-level checks
+func distanceM(a, b LatLon) float64 {
+	dLat := (a.Lat - b.Lat) * math.Pi / 180
+	dLon := (a.Lon - b.Lon) * math.Pi / 180 * math.Cos(a.Lat*math.Pi/180)
+	return math.Hypot(dLat, dLon) * 6371000
+}
+
+func TestToUTMKnownValues(t *testing.T) {
+	utm := ToUTM(0, 0)
+	if utm.Zone != 31 || !utm.North {
+		t.Fatalf("expected zone 31N, got %d north=%v", utm.Zone, utm.North)
+	}
+	if math.Abs(utm.Easting-166021.4431) > 0.01 || math.Abs(utm.Northing) > 0.01 {
+		t.Fatalf("unexpected UTM for 0,0: %.4f E %.4f N", utm.Easting, utm.Northing)
+	}
+}
+
+func TestFormatMGRSKnownValues(t *testing.T) {
+	tests := []struct {
+		lat, lon  float64
+		precision int
+		want      string
+	}{
+		{0, 3, 5, "31NEA0000000000"},            // Equator on a central meridian
+		{38.8895, -77.0352, 4, "18SUJ23480648"}, // Washington Monument
+		{38.8895, -77.0352, 0, "18SUJ"},
+	}
+	for _, tt := range tests {
+		got, err := FormatMGRS(tt.lat, tt.lon, tt.precision)
+		if err != nil {
+			t.Fatalf("FormatMGRS(%v, %v): %v", tt.lat, tt.lon, err)
+		}
+		if got != tt.want {
+			t.Errorf("FormatMGRS(%v, %v, %d) = %s, want %s", tt.lat, tt.lon, tt.precision, got, tt.want)
+		}
+	}
+
+	// Norway and Svalbard zone exceptions
+	for _, tt := range []struct {
+		lat, lon float64
+		prefix   string
+	}{{60, 5, "32V"}, {78, 15, "33X"}} {
+		got, _ := FormatMGRS(tt.lat, tt.lon, 5)
+		if !strings.HasPrefix(got, tt.prefix) {
+			t.Errorf("FormatMGRS(%v, %v) = %s, want prefix %s", tt.lat, tt.lon, got, tt.prefix)
+		}
+	}
+}
+
+func TestParseMGRSKnownValues(t *testing.T) {
+	monument := LatLon{Lat: 38.8895, Lon: -77.0352}
+	for _, ref := range []string{"18SUJ2348706483", "18S UJ 23487 06483", "18suj2348706483"} {
+		got, err := ParseMGRS(ref)
+		if err != nil {
+			t.Fatalf("ParseMGRS(%q): %v", ref, err)
+		}
+		if d := distanceM(got, monument); d > 10 {
+			t.Errorf("ParseMGRS(%q) = %v, %.1f m from the Washington Monument", ref, got, d)
+		}
+	}
+}
+
+func TestMGRSRoundTrip(t *testing.T) {
+	positions := []LatLon{
+		{Lat: 40.044437, Lon: -76.306229},
+		{Lat: -33.8568, Lon: 151.2153},
+		{Lat: 64.1466, Lon: -21.9426},
+		{Lat: -54.8019, Lon: -68.3030},
+		{Lat: 1.2902, Lon: 103.8519},
+		{Lat: 83.5, Lon: 10},
+		{Lat: -79.5, Lon: -170},
+	}
+	for _, pos := range positions {
+		ref, err := FormatMGRS(pos.Lat, pos.Lon, 5)
+		if err != nil {
+			t.Fatalf("FormatMGRS(%v): %v", pos, err)
+		}
+		back, err := ParseMGRS(ref)
+		if err != nil {
+			t.Fatalf("ParseMGRS(%s): %v", ref, err)
+		}
+		// Parsing returns the south-west corner of the 1 m square
+		if d := distanceM(pos, back); d > 1.5 {
+			t.Errorf("%v -> %s -> %v is %.2f m off", pos, ref, back, d)
+		}
+	}
+}
+
+func TestParseMGRSRejectsInvalid(t *testing.T) {
+	for _, ref := range []string{
+		"",
+		"61SUJ2348706483", // Zone out of range
+		"18IUJ2348706483", // I is not a band letter
+		"18SAJ2348706483", // A is not a column in zone 18
+		"18SUJ234870648",  // Odd number of digits
+		"18SUJ23487064X3",
+		"18SUP2348706483", // Square does not exist in band S
+	} {
+		if _, err := ParseMGRS(ref); err == nil {
+			t.Errorf("ParseMGRS(%q) succeeded, expected an error", ref)
+		}
+	}
+
+	if _, err := FormatMGRS(85, 0, 5); err == nil {
+		t.Error("expected an error above 84°N")
+	}
+}
+
+func TestParsePosition(t *testing.T) {
+	got, err := ParsePosition(" 40.5, -76.25 ")
+	if err != nil || got.Lat != 40.5 || got.Lon != -76.25 {
+		t.Fatalf("ParsePosition(lat,lon) = %v, %v", got, err)
+	}
+	if _, err := ParsePosition("18SUJ2348706483"); err != nil {
+		t.Fatalf("ParsePosition(MGRS): %v", err)
+	}
+	if _, err := ParsePosition("95,0"); err == nil {
+		t.Fatal("expected an out-of-range error")
+	}
+}
@@ -0,0 +1,154 @@
+// Package geo converts between geographic coordinates and military grid systems.
+package geo
+
+import (
+	"fmt"
+	"math"
+)
+
+// WGS84 ellipsoid and UTM projection constants
+const (
+	wgs84A       = 6378137.0
+	wgs84F       = 1 / 298.257223563
+	utmK0        = 0.9996
+	falseEasting = 500000.0
+	falseNorthS  = 10000000.0
+)
+
+var (
+	wgs84E2  = wgs84F * (2 - wgs84F)
+	wgs84EP2 = wgs84E2 / (1 - wgs84E2)
+)
+
+// LatLon is a WGS84 position in decimal degrees
+type LatLon struct {
+	Lat float64
+	Lon float64
+}
+
+// String formats the position as "lat,lon" with six decimals (~0.1 m)
+func (p LatLon) String() string {
+	return fmt.Sprintf("%.6f,%.6f", p.Lat, p.Lon)
+}
+
+// UTM is a Universal Transverse Mercator coordinate
+type UTM struct {
+	Zone     int
+	North    bool
+	Easting  float64 // Meters, including the 500 km false easting
+	Northing float64 // Meters, including the 10,000 km false northing in the south
+}
+
+// UTMZone returns the standard UTM zone for a position, including the Norway and
+// Svalbard exceptions
+func UTMZone(lat, lon float64) int {
+	lon = normalizeLon(lon)
+	zone := int(math.Floor((lon+180)/6)) + 1
+	if zone > 60 {
+		zone = 60
+	}
+
+	switch {
+	case lat >= 56 && lat < 64 && lon >= 3 && lon < 12:
+		zone = 32
+	case lat >= 72 && lat < 84 && lon >= 0 && lon < 9:
+		zone = 31
+	case lat >= 72 && lat < 84 && lon >= 9 && lon < 21:
+		zone = 33
+	case lat >= 72 && lat < 84 && lon >= 21 && lon < 33:
+		zone = 35
+	case lat >= 72 && lat < 84 && lon >= 33 && lon < 42:
+		zone = 37
+	}
+	return zone
+}
+
+// ToUTM projects a position into its standard UTM zone
+func ToUTM(lat, lon float64) UTM {
+	return ToUTMZone(lat, lon, UTMZone(lat, lon))
+}
+
+// ToUTMZone projects a position into the given UTM zone
+func ToUTMZone(lat, lon float64, zone int) UTM {
+	phi := lat * math.Pi / 180
+	lambda := normalizeLon(lon-centralMeridian(zone)) * math.Pi / 180
+
+	sinPhi, cosPhi := math.Sin(phi), math.Cos(phi)
+	n := wgs84A / math.Sqrt(1-wgs84E2*sinPhi*sinPhi)
+	t := math.Tan(phi) * math.Tan(phi)
+	c := wgs84EP2 * cosPhi * cosPhi
+	a := lambda * cosPhi
+	m := meridianArc(phi)
+
+	easting := utmK0*n*(a+(1-t+c)*math.Pow(a, 3)/6+
+		(5-18*t+t*t+72*c-58*wgs84EP2)*math.Pow(a, 5)/120) + falseEasting
+	northing := utmK0 * (m + n*math.Tan(phi)*(a*a/2+
+		(5-t+9*c+4*c*c)*math.Pow(a, 4)/24+
+		(61-58*t+t*t+600*c-330*wgs84EP2)*math.Pow(a, 6)/720))
+
+	north := lat >= 0
+	if !north {
+		northing += falseNorthS
+	}
+	return UTM{Zone: zone, North: north, Easting: easting, Northing: northing}
+}
+
+// LatLon converts the UTM coordinate back to WGS84 latitude and longitude
+func (u UTM) LatLon() LatLon {
+	y := u.Northing
+	if !u.North {
+		y -= falseNorthS
+	}
+	x := u.Easting - falseEasting
+
+	e2 := wgs84E2
+	mu := (y / utmK0) / (wgs84A * (1 - e2/4 - 3*e2*e2/64 - 5*e2*e2*e2/256))
+	e1 := (1 - math.Sqrt(1-e2)) / (1 + math.Sqrt(1-e2))
+	phi1 := mu + (3*e1/2-27*math.Pow(e1, 3)/32)*math.Sin(2*mu) +
+		(21*e1*e1/16-55*math.Pow(e1, 4)/32)*math.Sin(4*mu) +
+		(151*math.Pow(e1, 3)/96)*math.Sin(6*mu) +
+		(1097*math.Pow(e1, 4)/512)*math.Sin(8*mu)
+
+	sinPhi1, cosPhi1 := math.Sin(phi1), math.Cos(phi1)
+	c1 := wgs84EP2 * cosPhi1 * cosPhi1
+	t1 := math.Tan(phi1) * math.Tan(phi1)
+	n1 := wgs84A / math.Sqrt(1-e2*sinPhi1*sinPhi1)
+	r1 := wgs84A * (1 - e2) / math.Pow(1-e2*sinPhi1*sinPhi1, 1.5)
+	d := x / (n1 * utmK0)
+
+	phi := phi1 - (n1*math.Tan(phi1)/r1)*(d*d/2-
+		(5+3*t1+10*c1-4*c1*c1-9*wgs84EP2)*math.Pow(d, 4)/24+
+		(61+90*t1+298*c1+45*t1*t1-252*wgs84EP2-3*c1*c1)*math.Pow(d, 6)/720)
+	lambda := (d - (1+2*t1+c1)*math.Pow(d, 3)/6 +
+		(5-2*c1+28*t1-3*c1*c1+8*wgs84EP2+24*t1*t1)*math.Pow(d, 5)/120) / cosPhi1
+
+	return LatLon{
+		Lat: phi * 180 / math.Pi,
+		Lon: normalizeLon(centralMeridian(u.Zone) + lambda*180/math.Pi),
+	}
+}
+
+// meridianArc returns the distance along the meridian from the equator to latitude phi
+func meridianArc(phi float64) float64 {
+	e2 := wgs84E2
+	e4 := e2 * e2
+	e6 := e4 * e2
+	return wgs84A * ((1-e2/4-3*e4/64-5*e6/256)*phi -
+		(3*e2/8+3*e4/32+45*e6/1024)*math.Sin(2*phi) +
+		(15*e4/256+45*e6/1024)*math.Sin(4*phi) -
+		(35*e6/3072)*math.Sin(6*phi))
+}
+
+// centralMeridian returns the central meridian of a UTM zone in degrees
+func centralMeridian(zone int) float64 {
+	return float64(zone-1)*6 - 180 + 3
+}
+
+// normalizeLon wraps a longitude into [-180, 180)
+func normalizeLon(lon float64) float64 {
+	lon = math.Mod(lon+180, 360)
+	if lon < 0 {
+		lon += 360
+	}
+	return lon - 180
+}
@@ -63,6 +63,11 @@ func Networkf(format string, args ...interface{}) {
 
 // LogSection creates a visual section separator
 func LogSection(title string) {
+	if IsJSON() {
+		Info(title)
+		return
+	}
+
 	width := 50
 	line := strings.Repeat("=", width)
 
@@ -79,6 +84,11 @@ func LogSection(title string) {
 
 // LogSubSection creates a visual subsection separator
 func LogSubSection(title string) {
+	if IsJSON() {
+		Info(title)
+		return
+	}
+
 	width := 40
 	line := strings.Repeat("-", width)
 
@@ -97,12 +107,20 @@ func LogSubSection(title string) {
 func LogList(title string, items []string) {
 	Info(title)
 	for _, item := range items {
+		if IsJSON() {
+			Info(item)
+			continue
+		}
 		fmt.Printf("  %s %s\n", IconDot, item)
 	}
 }
 
 // LogKeyValue logs a key-value pair with nice formatting
 func LogKeyValue(key string, value interface{}) {
+	if IsJSON() {
+		defaultLogger.WithField(key, value).Info(key)
+		return
+	}
 	if l, ok := defaultLogger.(*logger); ok && !l.noColor {
 		fmt.Printf("%s%s:%s %v\n", colorCyan, key, colorReset, value)
 	} else {
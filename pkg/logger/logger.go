@@ -1,6 +1,7 @@
 package logger
 
 import (
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
@@ -59,6 +60,7 @@ type logger struct {
 	prefix   string
 	noColor  bool
 	showTime bool
+	json     bool
 }
 
 // Default logger instance
@@ -70,6 +72,7 @@ type Config struct {
 	Writer   io.Writer
 	NoColor  bool
 	ShowTime bool
+	JSON     bool // Emit one JSON object per line (for log collectors)
 }
 
 // New creates a new logger with default configuration
@@ -90,6 +93,7 @@ func NewWithConfig(cfg Config) Logger {
 		fields:   make(map[string]interface{}),
 		noColor:  cfg.NoColor,
 		showTime: cfg.ShowTime,
+		json:     cfg.JSON,
 	}
 }
 
@@ -111,6 +115,34 @@ func SetNoColor(noColor bool) {
 	}
 }
 
+// SetFormat switches the global log format between "text" and "json"
+func SetFormat(format string) {
+	if l, ok := defaultLogger.(*logger); ok {
+		l.mu.Lock()
+		l.json = strings.EqualFold(format, "json")
+		l.mu.Unlock()
+	}
+}
+
+// SetOutput redirects the global logger, e.g. to stderr when stdout carries a result
+func SetOutput(w io.Writer) {
+	if l, ok := defaultLogger.(*logger); ok {
+		l.mu.Lock()
+		l.writer = w
+		l.mu.Unlock()
+	}
+}
+
+// IsJSON reports whether the global logger emits JSON lines
+func IsJSON() bool {
+	if l, ok := defaultLogger.(*logger); ok {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.json
+	}
+	return false
+}
+
 // Helper methods for the default logger
 func Debug(args ...interface{})                       { defaultLogger.Debug(args...) }
 func Debugf(format string, args ...interface{})       { defaultLogger.Debugf(format, args...) }
@@ -135,6 +167,15 @@ func (l *logger) log(level Level, args ...interface{}) {
 
 	l.mu.Lock()
 
+	if l.json {
+		l.writeJSON(level, fmt.Sprint(args...))
+		l.mu.Unlock()
+		if level == FatalLevel {
+			os.Exit(1)
+		}
+		return
+	}
+
 	// Build the log message
 	var parts []string
 
@@ -184,7 +225,7 @@ func (l *logger) log(level Level, args ...interface{}) {
 	parts = append(parts, message)
 
 	// Write to output
-	_, _ = fmt.Fprintln(l.writer, strings.Join(parts, " "))
+	writeLine(l.writer, strings.Join(parts, " "))
 
 	l.mu.Unlock()
 
@@ -194,6 +235,32 @@ func (l *logger) log(level Level, args ...interface{}) {
 	}
 }
 
+// writeJSON writes a single structured log line. Caller must hold l.mu.
+func (l *logger) writeJSON(level Level, message string) {
+	entry := make(map[string]interface{}, len(l.fields)+4)
+	for k, v := range l.fields {
+		entry[k] = v
+	}
+	entry["time"] = time.Now().UTC().Format(time.RFC3339Nano)
+	entry["level"] = strings.ToLower(strings.TrimSpace(levelName(level)))
+	entry["msg"] = message
+	if l.prefix != "" {
+		entry["component"] = l.prefix
+	}
+
+	data, err := json.Marshal(entry)
+	if err != nil {
+		data, _ = json.Marshal(map[string]string{"level": "error", "msg": fmt.Sprintf("unencodable log entry: %v", err)})
+	}
+	writeLine(l.writer, string(data))
+}
+
+// levelName returns the display name for a level
+func levelName(level Level) string {
+	name, _ := (&logger{}).getLevelString(level)
+	return name
+}
+
 func (l *logger) logf(level Level, format string, args ...interface{}) {
 	message := fmt.Sprintf(format, args...)
 	l.log(level, message)
@@ -266,6 +333,7 @@ func (l *logger) WithField(key string, value interface{}) Logger {
 		prefix:   l.prefix,
 		noColor:  l.noColor,
 		showTime: l.showTime,
+		json:     l.json,
 	}
 
 	// Copy existing fields
@@ -287,6 +355,7 @@ func (l *logger) WithFields(fields map[string]interface{}) Logger {
 		prefix:   l.prefix,
 		noColor:  l.noColor,
 		showTime: l.showTime,
+		json:     l.json,
 	}
 
 	// Copy existing fields
@@ -310,6 +379,7 @@ func (l *logger) WithPrefix(prefix string) Logger {
 		prefix:   prefix,
 		noColor:  l.noColor,
 		showTime: l.showTime,
+		json:     l.json,
 	}
 
 	// Copy existing fields
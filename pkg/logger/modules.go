@@ -0,0 +1,230 @@
+package logger
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Well-known modules with their own level settings
+const (
+	ModuleClient     = "client"     // Legion API client
+	ModuleBuffer     = "buffer"     // Batched entity update buffer
+	ModuleBehavior   = "behavior"   // Swarm coordination and movement
+	ModuleEngagement = "engagement" // Detection, engagement and resolution
+)
+
+var (
+	moduleMu     sync.RWMutex
+	moduleLevels = make(map[string]Level)
+)
+
+// SetModuleLevel overrides the log level for a single module
+func SetModuleLevel(module string, level Level) {
+	moduleMu.Lock()
+	defer moduleMu.Unlock()
+	moduleLevels[strings.ToLower(module)] = level
+}
+
+// ResetModuleLevels removes all module overrides so every module follows the global level
+func ResetModuleLevels() {
+	moduleMu.Lock()
+	defer moduleMu.Unlock()
+	moduleLevels = make(map[string]Level)
+}
+
+// ModuleLevel returns the effective level for a module
+func ModuleLevel(module string) Level {
+	moduleMu.RLock()
+	level, ok := moduleLevels[strings.ToLower(module)]
+	moduleMu.RUnlock()
+	if ok {
+		return level
+	}
+	return GetLevel()
+}
+
+// GetLevel returns the global log level
+func GetLevel() Level {
+	if l, ok := defaultLogger.(*logger); ok {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		return l.level
+	}
+	return InfoLevel
+}
+
+// ApplyLevels applies a level spec such as "info,client=debug,behavior=warn".
+// A bare level sets the global level; module=level entries replace all previous overrides.
+func ApplyLevels(spec string) error {
+	global, modules, err := parseLevels(spec)
+	if err != nil {
+		return err
+	}
+
+	if global != nil {
+		SetLevel(*global)
+	}
+	moduleMu.Lock()
+	moduleLevels = modules
+	moduleMu.Unlock()
+	return nil
+}
+
+// parseLevels splits a level spec into its global level and module overrides
+func parseLevels(spec string) (*Level, map[string]Level, error) {
+	var global *Level
+	modules := make(map[string]Level)
+
+	for _, entry := range strings.FieldsFunc(spec, func(r rune) bool { return r == ',' || r == '\n' || r == ' ' }) {
+		module, name, hasModule := strings.Cut(entry, "=")
+		if !hasModule {
+			name = module
+		}
+		level, err := parseLevelStrict(name)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !hasModule {
+			global = &level
+			continue
+		}
+		module = strings.ToLower(strings.TrimSpace(module))
+		if module == "" {
+			return nil, nil, fmt.Errorf("missing module name in %q", entry)
+		}
+		modules[module] = level
+	}
+
+	return global, modules, nil
+}
+
+// parseLevelStrict is ParseLevel without the silent fallback to info
+func parseLevelStrict(name string) (Level, error) {
+	switch strings.ToLower(strings.TrimSpace(name)) {
+	case "debug", "info", "warn", "warning", "error", "fatal":
+		return ParseLevel(strings.TrimSpace(name)), nil
+	default:
+		return InfoLevel, fmt.Errorf("unknown log level %q", name)
+	}
+}
+
+// moduleLogger filters by its module's level and writes through the default logger,
+// so format and color changes made after it was created still apply
+type moduleLogger struct {
+	module string
+	fields map[string]interface{}
+}
+
+// Module returns a logger for the named module. Its messages are prefixed with the
+// module name and filtered by the module's level (or the global level if unset).
+func Module(name string) Logger {
+	return &moduleLogger{module: name, fields: make(map[string]interface{})}
+}
+
+// target builds a one-off logger carrying the current default settings
+func (m *moduleLogger) target() Logger {
+	base, ok := defaultLogger.(*logger)
+	if !ok {
+		return defaultLogger.WithPrefix(m.module).WithFields(m.fields)
+	}
+
+	base.mu.Lock()
+	l := &logger{
+		level:    DebugLevel, // Already filtered by module level
+		writer:   base.writer,
+		fields:   make(map[string]interface{}, len(base.fields)+len(m.fields)),
+		prefix:   m.module,
+		noColor:  base.noColor,
+		showTime: base.showTime,
+		json:     base.json,
+	}
+	for k, v := range base.fields {
+		l.fields[k] = v
+	}
+	base.mu.Unlock()
+
+	for k, v := range m.fields {
+		l.fields[k] = v
+	}
+	return l
+}
+
+func (m *moduleLogger) enabled(level Level) bool {
+	return level >= ModuleLevel(m.module)
+}
+
+func (m *moduleLogger) Debug(args ...interface{}) {
+	if m.enabled(DebugLevel) {
+		m.target().Debug(args...)
+	}
+}
+
+func (m *moduleLogger) Debugf(format string, args ...interface{}) {
+	if m.enabled(DebugLevel) {
+		m.target().Debugf(format, args...)
+	}
+}
+
+func (m *moduleLogger) Info(args ...interface{}) {
+	if m.enabled(InfoLevel) {
+		m.target().Info(args...)
+	}
+}
+
+func (m *moduleLogger) Infof(format string, args ...interface{}) {
+	if m.enabled(InfoLevel) {
+		m.target().Infof(format, args...)
+	}
+}
+
+func (m *moduleLogger) Warn(args ...interface{}) {
+	if m.enabled(WarnLevel) {
+		m.target().Warn(args...)
+	}
+}
+
+func (m *moduleLogger) Warnf(format string, args ...interface{}) {
+	if m.enabled(WarnLevel) {
+		m.target().Warnf(format, args...)
+	}
+}
+
+func (m *moduleLogger) Error(args ...interface{}) {
+	if m.enabled(ErrorLevel) {
+		m.target().Error(args...)
+	}
+}
+
+func (m *moduleLogger) Errorf(format string, args ...interface{}) {
+	if m.enabled(ErrorLevel) {
+		m.target().Errorf(format, args...)
+	}
+}
+
+func (m *moduleLogger) Fatal(args ...interface{}) {
+	m.target().Fatal(args...)
+}
+
+func (m *moduleLogger) Fatalf(format string, args ...interface{}) {
+	m.target().Fatalf(format, args...)
+}
+
+func (m *moduleLogger) WithField(key string, value interface{}) Logger {
+	return m.WithFields(map[string]interface{}{key: value})
+}
+
+func (m *moduleLogger) WithFields(fields map[string]interface{}) Logger {
+	newLogger := &moduleLogger{module: m.module, fields: make(map[string]interface{}, len(m.fields)+len(fields))}
+	for k, v := range m.fields {
+		newLogger.fields[k] = v
+	}
+	for k, v := range fields {
+		newLogger.fields[k] = v
+	}
+	return newLogger
+}
+
+func (m *moduleLogger) WithPrefix(prefix string) Logger {
+	return &moduleLogger{module: prefix, fields: m.fields}
+}
@@ -0,0 +1,56 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+func TestModuleLevelsFilterIndependently(t *testing.T) {
+	var buf bytes.Buffer
+	previous := defaultLogger
+	defaultLogger = NewWithConfig(Config{Level: InfoLevel, Writer: &buf, NoColor: true})
+	defer func() {
+		defaultLogger = previous
+		ResetModuleLevels()
+	}()
+
+	if err := ApplyLevels("client=debug, behavior=warn"); err != nil {
+		t.Fatalf("ApplyLevels: %v", err)
+	}
+
+	Module(ModuleClient).Debug("client debug")
+	Module(ModuleBehavior).Info("behavior info")
+	Module(ModuleEngagement).Info("engagement info")
+	Module(ModuleEngagement).Debug("engagement debug")
+
+	out := buf.String()
+	for _, want := range []string{"[client] client debug", "[engagement] engagement info"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+	for _, unwanted := range []string{"behavior info", "engagement debug"} {
+		if strings.Contains(out, unwanted) {
+			t.Errorf("did not expect %q in output:\n%s", unwanted, out)
+		}
+	}
+
+	// Module loggers pick up format changes made after they were created
+	buf.Reset()
+	SetFormat("json")
+	Module(ModuleClient).Info("as json")
+	if !strings.Contains(buf.String(), `"component":"client"`) {
+		t.Errorf("expected JSON output with component, got %s", buf.String())
+	}
+}
+
+func TestApplyLevelsRejectsUnknownLevel(t *testing.T) {
+	defer ResetModuleLevels()
+	if err := ApplyLevels("client=verbose"); err == nil {
+		t.Fatal("expected an error for an unknown level")
+	}
+	if err := ApplyLevels("=debug"); err == nil {
+		t.Fatal("expected an error for a missing module")
+	}
+}
@@ -0,0 +1,206 @@
+package logger
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/term"
+)
+
+// Progress display modes
+const (
+	ProgressAuto  = "auto"  // Live on a terminal, log lines otherwise
+	ProgressLive  = "live"  // One line redrawn in place below the log
+	ProgressLog   = "log"   // A log line every interval
+	ProgressQuiet = "quiet" // A log line at each quarter of the run, for CI logs
+)
+
+// progressBarWidth is the width of the live progress bar in cells
+const progressBarWidth = 20
+
+// clearToEnd erases the rest of a terminal line
+const clearToEnd = "\033[K"
+
+// liveRedraw limits how often the live line is redrawn
+const liveRedraw = 250 * time.Millisecond
+
+var (
+	progressMu   sync.Mutex
+	progressMode = ProgressAuto
+)
+
+// statusLine is the live progress line kept at the bottom of a terminal. Log lines
+// written to the same writer clear it and draw it again below themselves.
+var statusLine struct {
+	mu   sync.Mutex
+	w    io.Writer
+	line string
+}
+
+// SetProgressMode sets how run progress is shown: auto, live, log or quiet
+func SetProgressMode(mode string) error {
+	mode = strings.ToLower(strings.TrimSpace(mode))
+	switch mode {
+	case "":
+		mode = ProgressAuto
+	case ProgressAuto, ProgressLive, ProgressLog, ProgressQuiet:
+	default:
+		return fmt.Errorf("invalid progress mode %q (valid: %s, %s, %s, %s)", mode, ProgressAuto, ProgressLive, ProgressLog, ProgressQuiet)
+	}
+	progressMu.Lock()
+	progressMode = mode
+	progressMu.Unlock()
+	return nil
+}
+
+// RunProgress shows how far a run is through its duration, when it will finish, and
+// counts the simulation supplies. Scenario time runs at scale times wall time, so the
+// finish estimate holds across time_scale; time spent paused pushes it back.
+type RunProgress struct {
+	mu       sync.Mutex
+	total    time.Duration // Scenario duration
+	scale    float64       // Scenario seconds per wall second
+	interval time.Duration // Between log lines in log mode
+	mode     string
+	writer   io.Writer
+	width    int // Terminal columns the live line is cut to
+	last     time.Time
+	quarter  int // Quarters of the run already reported in quiet mode
+}
+
+// NewRunProgress starts progress for a run of total scenario time at scale, logging
+// every interval in log mode
+func NewRunProgress(total time.Duration, scale float64, interval time.Duration) *RunProgress {
+	if scale <= 0 {
+		scale = 1
+	}
+	progressMu.Lock()
+	mode := progressMode
+	progressMu.Unlock()
+
+	p := &RunProgress{total: total, scale: scale, interval: interval, mode: mode}
+	if l, ok := defaultLogger.(*logger); ok {
+		l.mu.Lock()
+		p.writer = l.writer
+		if mode == ProgressAuto {
+			mode = ProgressLog
+			if f, ok := l.writer.(*os.File); ok && !l.json && term.IsTerminal(int(f.Fd())) {
+				mode = ProgressLive
+			}
+		}
+		if f, ok := l.writer.(*os.File); ok {
+			p.width, _, _ = term.GetSize(int(f.Fd()))
+		}
+		if l.json && mode == ProgressLive {
+			mode = ProgressLog // A redrawn line would corrupt the JSON stream
+		}
+		l.mu.Unlock()
+	}
+	p.mode = mode
+	return p
+}
+
+// SetInterval changes how often log mode logs progress
+func (p *RunProgress) SetInterval(interval time.Duration) {
+	p.mu.Lock()
+	p.interval = interval
+	p.mu.Unlock()
+}
+
+// Due reports whether an update at this scenario time would be shown, so callers can
+// skip gathering counts that would not be
+func (p *RunProgress) Due(elapsed time.Duration) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	switch p.mode {
+	case ProgressLive:
+		return time.Since(p.last) >= liveRedraw
+	case ProgressQuiet:
+		return p.total > 0 && int(4*elapsed/p.total) > p.quarter && p.quarter < 3
+	default:
+		return p.interval > 0 && time.Since(p.last) >= p.interval
+	}
+}
+
+// Update shows progress at a scenario time with the given counts, if it is due
+func (p *RunProgress) Update(elapsed time.Duration, counts string) {
+	if !p.Due(elapsed) {
+		return
+	}
+	p.mu.Lock()
+	p.last = time.Now()
+	if p.mode == ProgressQuiet {
+		p.quarter = min(int(4*elapsed/p.total), 3)
+	}
+	line := p.format(elapsed, counts)
+	p.mu.Unlock()
+
+	if p.mode == ProgressLive {
+		statusLine.mu.Lock()
+		statusLine.w, statusLine.line = p.writer, line
+		_, _ = fmt.Fprint(p.writer, "\r"+line+clearToEnd)
+		statusLine.mu.Unlock()
+		return
+	}
+	Info(line)
+}
+
+// Done clears the live line and logs where the run ended, in every mode
+func (p *RunProgress) Done(elapsed time.Duration, counts string) {
+	statusLine.mu.Lock()
+	if statusLine.line != "" && statusLine.w == p.writer {
+		_, _ = fmt.Fprint(p.writer, "\r"+clearToEnd)
+		statusLine.w, statusLine.line = nil, ""
+	}
+	statusLine.mu.Unlock()
+
+	p.mu.Lock()
+	line := p.format(elapsed, counts)
+	p.mu.Unlock()
+	Info(line)
+}
+
+// format renders the progress line. Caller must hold p.mu.
+func (p *RunProgress) format(elapsed time.Duration, counts string) string {
+	fraction := 1.0
+	if p.total > 0 {
+		fraction = min(max(float64(elapsed)/float64(p.total), 0), 1)
+	}
+	remaining := time.Duration(float64(max(p.total-elapsed, 0)) / p.scale)
+
+	var b strings.Builder
+	if p.mode == ProgressLive {
+		filled := int(fraction * progressBarWidth)
+		b.WriteString("[" + strings.Repeat("█", filled) + strings.Repeat("░", progressBarWidth-filled) + "] ")
+	}
+	fmt.Fprintf(&b, "%3.0f%% T+%s / %s", fraction*100, elapsed.Round(time.Second), p.total)
+	if remaining > 0 {
+		fmt.Fprintf(&b, " | ETA %s (in %s)", time.Now().Add(remaining).Format("15:04:05"), remaining.Round(time.Second))
+	}
+	if counts != "" {
+		b.WriteString(" | " + counts)
+	}
+
+	// A live line that wraps can no longer be redrawn in place
+	line := b.String()
+	if runes := []rune(line); p.mode == ProgressLive && p.width > 1 && len(runes) >= p.width {
+		line = string(runes[:p.width-2]) + "…"
+	}
+	return line
+}
+
+// writeLine writes a log line, keeping the live progress line below it when both go to
+// the same writer
+func writeLine(w io.Writer, text string) {
+	statusLine.mu.Lock()
+	defer statusLine.mu.Unlock()
+	if statusLine.line == "" || statusLine.w != w {
+		_, _ = fmt.Fprintln(w, text)
+		return
+	}
+	_, _ = fmt.Fprint(w, "\r"+clearToEnd+text+"\n"+statusLine.line+clearToEnd)
+}
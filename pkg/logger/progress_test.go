@@ -0,0 +1,59 @@
+package logger
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestRunProgressQuietLogsQuarters(t *testing.T) {
+	var buf bytes.Buffer
+	previous := defaultLogger
+	defaultLogger = NewWithConfig(Config{Level: InfoLevel, Writer: &buf, NoColor: true})
+	defer func() {
+		defaultLogger = previous
+		_ = SetProgressMode(ProgressAuto)
+	}()
+
+	if err := SetProgressMode("loud"); err == nil {
+		t.Error("expected an error for an unknown mode")
+	}
+	if err := SetProgressMode(ProgressQuiet); err != nil {
+		t.Fatalf("SetProgressMode: %v", err)
+	}
+
+	p := NewRunProgress(4*time.Minute, 2, time.Second)
+	for elapsed := time.Duration(0); elapsed < 4*time.Minute; elapsed += 10 * time.Second {
+		p.Update(elapsed, "kills: 3")
+	}
+	p.Done(4*time.Minute, "kills: 9")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected 25%%, 50%%, 75%% and final lines, got:\n%s", buf.String())
+	}
+	if !strings.Contains(lines[0], " 25% T+1m0s / 4m0s | ETA ") || !strings.Contains(lines[0], "(in 1m30s) | kills: 3") {
+		t.Errorf("unexpected first line: %s", lines[0])
+	}
+	if !strings.Contains(lines[3], "100% T+4m0s / 4m0s | kills: 9") {
+		t.Errorf("unexpected final line: %s", lines[3])
+	}
+}
+
+func TestWriteLineKeepsStatusLineBelow(t *testing.T) {
+	var buf bytes.Buffer
+	statusLine.w, statusLine.line = &buf, "[██░░] 50%"
+	defer func() { statusLine.w, statusLine.line = nil, "" }()
+
+	writeLine(&buf, "engaging track TK-0001")
+	if want := "\r" + clearToEnd + "engaging track TK-0001\n[██░░] 50%" + clearToEnd; buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+
+	var other bytes.Buffer
+	writeLine(&other, "elsewhere")
+	if other.String() != "elsewhere\n" {
+		t.Errorf("a different writer should get a plain line, got %q", other.String())
+	}
+}
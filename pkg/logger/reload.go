@@ -0,0 +1,64 @@
+package logger
+
+import (
+	"os"
+	"os/signal"
+	"syscall"
+)
+
+// ReloadOnSignal changes log levels at runtime whenever the process receives SIGHUP.
+// With a levels file its spec (see ApplyLevels) is re-read and applied; without one,
+// debug logging is toggled on and off for every module. Call the returned func to stop.
+func ReloadOnSignal(levelsFile string) func() {
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	done := make(chan struct{})
+
+	go func() {
+		var restore *Level
+		var restoreModules map[string]Level
+		for {
+			select {
+			case <-done:
+				return
+			case <-sigChan:
+			}
+
+			if levelsFile != "" {
+				data, err := os.ReadFile(levelsFile)
+				if err != nil {
+					Warnf("Failed to reload log levels from %s: %v", levelsFile, err)
+					continue
+				}
+				if err := ApplyLevels(string(data)); err != nil {
+					Warnf("Invalid log levels in %s: %v", levelsFile, err)
+					continue
+				}
+				Infof("Reloaded log levels from %s", levelsFile)
+				continue
+			}
+
+			if restore == nil {
+				previous := GetLevel()
+				restore = &previous
+				moduleMu.Lock()
+				restoreModules, moduleLevels = moduleLevels, make(map[string]Level)
+				moduleMu.Unlock()
+				SetLevel(DebugLevel)
+				Info("Debug logging enabled (send SIGHUP again to restore)")
+			} else {
+				SetLevel(*restore)
+				moduleMu.Lock()
+				moduleLevels = restoreModules
+				moduleMu.Unlock()
+				restore = nil
+				Info("Debug logging disabled")
+			}
+		}
+	}()
+
+	return func() {
+		signal.Stop(sigChan)
+		close(done)
+	}
+}
@@ -0,0 +1,49 @@
+package metrics
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestHandlerServesTextExposition(t *testing.T) {
+	r := NewRegistry()
+	r.Add("legion_requests_total", "Requests sent to Legion", Labels{"route": "entities", "status": "201"}, 2)
+	r.Add("legion_requests_total", "Requests sent to Legion", Labels{"status": "201", "route": "entities"}, 1)
+	r.Add("legion_requests_total", "Requests sent to Legion", Labels{"route": "locations", "status": "429"}, 1)
+	r.Set("sim_active_threats", "Threats still in the air", Labels{"team": `red "1"`}, 12)
+	r.Set("sim_active_threats", "Threats still in the air", Labels{"team": `red "1"`}, 7)
+
+	server := httptest.NewServer(r.Handler())
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/metrics")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := resp.Header.Get("Content-Type"); got != "text/plain; version=0.0.4" {
+		t.Errorf("expected the text exposition content type, got %q", got)
+	}
+	want := `# HELP legion_requests_total Requests sent to Legion
+# TYPE legion_requests_total counter
+legion_requests_total{route="entities",status="201"} 3
+legion_requests_total{route="locations",status="429"} 1
+# HELP sim_active_threats Threats still in the air
+# TYPE sim_active_threats gauge
+sim_active_threats{team="red \"1\""} 7
+`
+	if string(body) != want {
+		t.Errorf("unexpected exposition:\n%s\nwant:\n%s", body, want)
+	}
+
+	if got := r.Value("legion_requests_total", Labels{"status": "201", "route": "entities"}); got != 3 {
+		t.Errorf("expected labels in any order to name one series, got %g", got)
+	}
+}
@@ -78,6 +78,25 @@ type UpdateEntityRequest struct {
 	Type           *string          `json:"type,omitempty"`
 }
 
+// EntityPatch is a partial entity update. Only non-nil fields are sent; everything else
+// is left unchanged on the server.
+type EntityPatch struct {
+	Affiliation    *Affiliation     `json:"affiliation,omitempty"`
+	Category       *Category        `json:"category,omitempty"`
+	Classification *json.RawMessage `json:"classification,omitempty"`
+	Metadata       *json.RawMessage `json:"metadata,omitempty"`
+	Name           *string          `json:"name,omitempty"`
+	ParentID       *uuid.UUID       `json:"parent_id,omitempty"`
+	Status         *string          `json:"status,omitempty"`
+	Type           *string          `json:"type,omitempty"`
+}
+
+// IsEmpty reports whether the patch changes nothing
+func (p *EntityPatch) IsEmpty() bool {
+	return p.Affiliation == nil && p.Category == nil && p.Classification == nil && p.Metadata == nil &&
+		p.Name == nil && p.ParentID == nil && p.Status == nil && p.Type == nil
+}
+
 type EntityResponse struct {
 	ID                           uuid.UUID        `json:"id"`
 	OrganizationID               uuid.UUID        `json:"organization_id"`
@@ -163,6 +182,13 @@ type SearchEntityLocationsRequest struct {
 
 type EntityLocationPaginatedResponse = PaginatedResponse[EntityLocationResponse]
 
+// EntityHistory is what Legion has recorded for an entity. Legion keeps no status
+// history, so Entity holds the latest status written.
+type EntityHistory struct {
+	Entity    *EntityResponse          `json:"entity"`
+	Locations []EntityLocationResponse `json:"locations"` // Oldest first
+}
+
 type CreateFeedDefinitionRequest struct {
 	Category         *MessageCategory `json:"category,omitempty"`
 	DataType         *string          `json:"data_type,omitempty"`
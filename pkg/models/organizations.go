@@ -158,3 +158,16 @@ type OrganizationUserPaginatedResponse struct {
 	// Paging contains optional paging information.
 	Paging Paging `json:"paging,omitempty"`
 }
+
+// OrganizationUserWithOrgDetailsPaginatedResponse represents a paginated response of the
+// organizations a user belongs to
+// @Description A paginated list of the current user's organization memberships.
+// @name OrganizationUserWithOrgDetailsPaginatedResponse
+type OrganizationUserWithOrgDetailsPaginatedResponse struct {
+	// Results is a slice of items of type OrganizationUserWithOrgDetailsResponse.
+	Results []OrganizationUserWithOrgDetailsResponse `json:"results"`
+	// TotalCount is the total number of items available.
+	TotalCount int `json:"total_count" swaggertype:"integer" example:"3"`
+	// Paging contains optional paging information.
+	Paging Paging `json:"paging,omitempty"`
+}
@@ -0,0 +1,155 @@
+package runlog
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/logger"
+	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// Options tune a replay
+type Options struct {
+	Speed   float64                // Playback rate: 1 is the recorded pace, 0 sends as fast as Legion accepts
+	Keep    bool                   // Leave the replayed entities in Legion afterwards
+	OnEvent func(simulation.Event) // Called with each recorded event as it comes due
+}
+
+// Stats counts what a replay sent
+type Stats struct {
+	Entities  int `json:"entities"`
+	Locations int `json:"locations"`
+	Patches   int `json:"patches"`
+	Deletes   int `json:"deletes"`
+	Events    int `json:"events"`
+	Skipped   int `json:"skipped"` // Records for entities the recording never created
+	Failed    int `json:"failed"`
+}
+
+// Replay pushes a recording's writes to an organization with the recorded timing
+// scaled by opts.Speed. Entities are created afresh and the recording's IDs mapped to
+// them; unless opts.Keep is set they are deleted once the replay ends or is cancelled.
+func Replay(ctx context.Context, c client.LegionAPI, orgID uuid.UUID, log *Log, opts Options) (Stats, error) {
+	orgCtx := client.WithOrgID(ctx, orgID.String())
+	ids := make(map[string]uuid.UUID)
+	var stats Stats
+
+	defer func() {
+		if opts.Keep {
+			return
+		}
+		// Remove what is left even when the replay was cancelled
+		cleanupCtx, cancel := context.WithTimeout(client.WithOrgID(context.Background(), orgID.String()), 30*time.Second)
+		defer cancel()
+		for _, id := range ids {
+			if err := c.DeleteEntity(cleanupCtx, id.String()); err != nil {
+				logger.Debugf("Failed to delete replayed entity %s: %v", id, err)
+			}
+		}
+	}()
+
+	start := time.Now()
+	for _, record := range log.Records {
+		if opts.Speed > 0 {
+			due := start.Add(time.Duration(record.Elapsed / opts.Speed * float64(time.Second)))
+			if wait := time.Until(due); wait > 0 {
+				select {
+				case <-ctx.Done():
+					return stats, ctx.Err()
+				case <-time.After(wait):
+				}
+			}
+		}
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		if record.Kind == KindEvent {
+			stats.Events++
+			if opts.OnEvent != nil {
+				opts.OnEvent(*record.Event)
+			}
+			continue
+		}
+		if record.Kind == KindEntity {
+			if err := replayCreate(orgCtx, c, orgID, record, ids); err != nil {
+				logger.Warnf("Failed to create %s: %v", entityName(record.Create), err)
+				stats.Failed++
+				continue
+			}
+			stats.Entities++
+			continue
+		}
+
+		id, ok := ids[record.Entity]
+		if !ok {
+			stats.Skipped++
+			continue
+		}
+		var err error
+		switch record.Kind {
+		case KindLocation:
+			location := *record.Location
+			now := time.Now()
+			location.RecordedAt = &now
+			_, err = c.CreateEntityLocation(orgCtx, id.String(), &location)
+			stats.Locations++
+		case KindPatch:
+			patch := *record.Patch
+			patch.ParentID = mapParent(patch.ParentID, ids)
+			_, err = c.PatchEntity(orgCtx, id.String(), &patch)
+			stats.Patches++
+		case KindDelete:
+			err = c.DeleteEntity(orgCtx, id.String())
+			delete(ids, record.Entity)
+			stats.Deletes++
+		}
+		if err != nil {
+			if errors.Is(err, context.Canceled) {
+				return stats, err
+			}
+			logger.Debugf("Replay %s for %s failed: %v", record.Kind, id, err)
+			stats.Failed++
+		}
+	}
+	return stats, nil
+}
+
+// replayCreate creates a recorded entity in the organization and maps its recorded ID
+// to the new one
+func replayCreate(ctx context.Context, c client.LegionAPI, orgID uuid.UUID, record Record, ids map[string]uuid.UUID) error {
+	req := *record.Create
+	req.OrganizationID = &orgID
+	req.ParentID = mapParent(req.ParentID, ids)
+	entity, err := c.CreateEntity(ctx, &req)
+	if err != nil {
+		return err
+	}
+	ids[record.Entity] = entity.ID
+	return nil
+}
+
+// mapParent points a parent reference at the replayed entity when the recording
+// created the parent
+func mapParent(parent *uuid.UUID, ids map[string]uuid.UUID) *uuid.UUID {
+	if parent == nil {
+		return nil
+	}
+	if id, ok := ids[parent.String()]; ok {
+		return &id
+	}
+	return parent
+}
+
+// entityName names a recorded entity for messages
+func entityName(req *models.CreateEntityRequest) string {
+	if req.Name != nil {
+		return *req.Name
+	}
+	return "entity"
+}
@@ -0,0 +1,238 @@
+// Package runlog records the entity writes a simulation run makes to Legion, with the
+// run's events, so `legion-sim replay` can push the same picture again later without
+// recomputing the simulation.
+//
+// A log is gzipped JSON lines: a header, then one Record per write or event in the
+// order they happened, each stamped with seconds since the recording started.
+package runlog
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// Record kinds
+const (
+	KindHeader   = "header"   // First line: what was recorded
+	KindEntity   = "entity"   // Entity created or adopted
+	KindLocation = "location" // Position reported
+	KindPatch    = "patch"    // Status, affiliation or metadata changed
+	KindDelete   = "delete"   // Entity deleted
+	KindEvent    = "event"    // Run event, for narration
+)
+
+// Header describes a recorded run
+type Header struct {
+	Simulation string    `json:"simulation"`
+	RunID      string    `json:"run_id"`
+	Started    time.Time `json:"started"`
+}
+
+// Record is one write or event. Entity is the entity's ID in the recorded run.
+type Record struct {
+	Elapsed  float64                             `json:"t"`
+	Kind     string                              `json:"kind"`
+	Entity   string                              `json:"entity,omitempty"`
+	Header   *Header                             `json:"header,omitempty"`
+	Create   *models.CreateEntityRequest         `json:"create,omitempty"`
+	Location *models.CreateEntityLocationRequest `json:"location,omitempty"`
+	Patch    *models.EntityPatch                 `json:"patch,omitempty"`
+	Event    *simulation.Event                   `json:"event,omitempty"`
+}
+
+// Log is a loaded recording
+type Log struct {
+	Header  Header
+	Records []Record
+}
+
+// Duration returns the time from the start of the recording to its last record
+func (l *Log) Duration() time.Duration {
+	if len(l.Records) == 0 {
+		return 0
+	}
+	return time.Duration(l.Records[len(l.Records)-1].Elapsed * float64(time.Second))
+}
+
+// Writer records a run. It implements client.Recorder and is safe for concurrent use;
+// writes after Close are ignored.
+type Writer struct {
+	mu      sync.Mutex
+	start   time.Time
+	file    *os.File
+	gz      *gzip.Writer
+	buf     *bufio.Writer
+	enc     *json.Encoder
+	records int
+	err     error // First failed write
+	closed  bool
+}
+
+// Create starts a recording at path, gzipped when path ends in .gz
+func Create(path string, header Header) (*Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create run log: %w", err)
+	}
+
+	w := &Writer{start: time.Now(), file: file}
+	var out io.Writer = file
+	if strings.HasSuffix(path, ".gz") {
+		w.gz = gzip.NewWriter(file)
+		out = w.gz
+	}
+	w.buf = bufio.NewWriter(out)
+	w.enc = json.NewEncoder(w.buf)
+
+	if header.Started.IsZero() {
+		header.Started = w.start
+	}
+	w.write(Record{Kind: KindHeader, Header: &header})
+	return w, nil
+}
+
+// write stamps and appends a record
+func (w *Writer) write(r Record) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed || w.err != nil {
+		return
+	}
+	r.Elapsed = time.Since(w.start).Seconds()
+	if err := w.enc.Encode(r); err != nil {
+		w.err = err
+		return
+	}
+	w.records++
+}
+
+// EntityCreated records an entity the run created or adopted
+func (w *Writer) EntityCreated(entity *models.EntityResponse, req *models.CreateEntityRequest) {
+	w.write(Record{Kind: KindEntity, Entity: entity.ID.String(), Create: req})
+}
+
+// LocationCreated records a reported position
+func (w *Writer) LocationCreated(entityID string, req *models.CreateEntityLocationRequest) {
+	w.write(Record{Kind: KindLocation, Entity: entityID, Location: req})
+}
+
+// EntityPatched records a status, affiliation or metadata change
+func (w *Writer) EntityPatched(entityID string, patch *models.EntityPatch) {
+	w.write(Record{Kind: KindPatch, Entity: entityID, Patch: patch})
+}
+
+// EntityDeleted records a deletion
+func (w *Writer) EntityDeleted(entityID string) {
+	w.write(Record{Kind: KindDelete, Entity: entityID})
+}
+
+// Event records a run event; it can be passed to simulation.Observable.Observe
+func (w *Writer) Event(event simulation.Event) {
+	w.write(Record{Kind: KindEvent, Event: &event})
+}
+
+// Records returns how many records have been written, the header included
+func (w *Writer) Records() int {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.records
+}
+
+// Close flushes and closes the recording, reporting the first write that failed
+func (w *Writer) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return nil
+	}
+	w.closed = true
+
+	err := errors.Join(w.err, w.buf.Flush())
+	if w.gz != nil {
+		err = errors.Join(err, w.gz.Close())
+	}
+	return errors.Join(err, w.file.Close())
+}
+
+// Load reads a recording written by Writer, gzipped or not
+func Load(path string) (*Log, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open run log: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var in io.Reader = reader
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read run log %s: %w", path, err)
+		}
+		defer gz.Close()
+		in = gz
+	}
+
+	log := &Log{}
+	dec := json.NewDecoder(in)
+	for line := 1; ; line++ {
+		var r Record
+		if err := dec.Decode(&r); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("run log %s, record %d: %w", path, line, err)
+		}
+		if line == 1 {
+			if r.Kind != KindHeader || r.Header == nil {
+				return nil, fmt.Errorf("run log %s does not start with a header", path)
+			}
+			log.Header = *r.Header
+			continue
+		}
+		if err := checkRecord(r); err != nil {
+			return nil, fmt.Errorf("run log %s, record %d: %w", path, line, err)
+		}
+		log.Records = append(log.Records, r)
+	}
+	if len(log.Records) == 0 {
+		return nil, fmt.Errorf("run log %s has no records", path)
+	}
+	return log, nil
+}
+
+// checkRecord rejects records a replay can't apply
+func checkRecord(r Record) error {
+	var ok bool
+	switch r.Kind {
+	case KindEntity:
+		ok = r.Create != nil
+	case KindLocation:
+		ok = r.Location != nil && r.Location.Position != nil
+	case KindPatch:
+		ok = r.Patch != nil
+	case KindDelete:
+		ok = true
+	case KindEvent:
+		ok = r.Event != nil
+	default:
+		return fmt.Errorf("unknown record kind %q", r.Kind)
+	}
+	if !ok {
+		return fmt.Errorf("%s record is missing its body", r.Kind)
+	}
+	if r.Kind != KindEvent && r.Entity == "" {
+		return fmt.Errorf("%s record names no entity", r.Kind)
+	}
+	return nil
+}
@@ -0,0 +1,93 @@
+package runlog
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/uuid"
+
+	"github.com/picogrid/legion-simulations/pkg/models"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+func TestWriterRoundTrip(t *testing.T) {
+	for _, name := range []string{"run.jsonl.gz", "run.jsonl"} {
+		path := filepath.Join(t.TempDir(), name)
+		w, err := Create(path, Header{Simulation: "Drone Swarm Combat", RunID: "1a2b3c4d"})
+		if err != nil {
+			t.Fatalf("Create: %v", err)
+		}
+
+		id := uuid.New()
+		entityName, status := "UAS-W1-001", "ACTIVE"
+		w.EntityCreated(&models.EntityResponse{ID: id}, &models.CreateEntityRequest{Name: &entityName})
+		w.LocationCreated(id.String(), &models.CreateEntityLocationRequest{Position: &models.GeomPoint{Coordinates: []float64{-117.1, 32.7, 120}}})
+		w.EntityPatched(id.String(), &models.EntityPatch{Status: &status})
+		w.Event(simulation.Event{Type: "engagement", Message: "UAS-W1-001 destroyed"})
+		w.EntityDeleted(id.String())
+		if err := w.Close(); err != nil {
+			t.Fatalf("Close: %v", err)
+		}
+		w.EntityDeleted(id.String()) // Ignored after Close
+		if w.Records() != 6 {
+			t.Errorf("%s: wrote %d records, want 6", name, w.Records())
+		}
+
+		log, err := Load(path)
+		if err != nil {
+			t.Fatalf("Load: %v", err)
+		}
+		if log.Header.RunID != "1a2b3c4d" || log.Header.Started.IsZero() {
+			t.Errorf("%s: unexpected header %+v", name, log.Header)
+		}
+		kinds := []string{KindEntity, KindLocation, KindPatch, KindEvent, KindDelete}
+		if len(log.Records) != len(kinds) {
+			t.Fatalf("%s: loaded %d records, want %d", name, len(log.Records), len(kinds))
+		}
+		for i, r := range log.Records {
+			if r.Kind != kinds[i] {
+				t.Errorf("%s: record %d is %s, want %s", name, i, r.Kind, kinds[i])
+			}
+			if i > 0 && r.Elapsed < log.Records[i-1].Elapsed {
+				t.Errorf("%s: record %d goes back in time", name, i)
+			}
+		}
+		if got := log.Records[0]; got.Entity != id.String() || *got.Create.Name != entityName {
+			t.Errorf("%s: entity record %+v", name, got)
+		}
+	}
+}
+
+func TestLoadRejectsBadLogs(t *testing.T) {
+	tests := map[string]string{
+		"no header":    `{"t":0,"kind":"delete","entity":"a"}` + "\n",
+		"no records":   `{"t":0,"kind":"header","header":{"run_id":"x"}}` + "\n",
+		"unknown kind": `{"t":0,"kind":"header","header":{"run_id":"x"}}` + "\n" + `{"t":1,"kind":"teleport","entity":"a"}` + "\n",
+		"no body":      `{"t":0,"kind":"header","header":{"run_id":"x"}}` + "\n" + `{"t":1,"kind":"patch","entity":"a"}` + "\n",
+		"no entity":    `{"t":0,"kind":"header","header":{"run_id":"x"}}` + "\n" + `{"t":1,"kind":"delete"}` + "\n",
+	}
+	for name, content := range tests {
+		path := filepath.Join(t.TempDir(), "run.jsonl")
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := Load(path); err == nil {
+			t.Errorf("%s: Load should fail", name)
+		}
+	}
+}
+
+func TestMapParent(t *testing.T) {
+	recorded, replayed, outside := uuid.New(), uuid.New(), uuid.New()
+	ids := map[string]uuid.UUID{recorded.String(): replayed}
+	if got := mapParent(&recorded, ids); *got != replayed {
+		t.Errorf("recorded parent mapped to %s, want %s", got, replayed)
+	}
+	if got := mapParent(&outside, ids); *got != outside {
+		t.Errorf("parent outside the recording changed to %s", got)
+	}
+	if mapParent(nil, ids) != nil {
+		t.Error("no parent should stay nil")
+	}
+}
@@ -0,0 +1,207 @@
+// Package runner embeds simulations in other Go programs. It constructs a simulation
+// from the registry, configures it, runs it against a Legion client and reports what
+// happened, so a service can drive runs without going through the CLI.
+//
+//	r, err := runner.New("Drone Swarm Combat",
+//		runner.WithOrganization(orgID),
+//		runner.WithParam("num_uas_threats", 20),
+//	)
+//	if err != nil {
+//		return err
+//	}
+//	go func() {
+//		for event := range r.Events() {
+//			log.Println(event.Message)
+//		}
+//	}()
+//	result, err := r.Run(ctx, legionClient)
+package runner
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// DefaultEventBuffer is how many events queue for a slow reader before new ones are dropped
+const DefaultEventBuffer = 256
+
+// ErrAlreadyRun is returned when Run is called a second time
+var ErrAlreadyRun = errors.New("runner has already run")
+
+// Result describes a finished run: the simulation's own result plus timing
+type Result struct {
+	simulation.Result
+	Simulation    string    `json:"simulation"`
+	Started       time.Time `json:"started"`
+	Finished      time.Time `json:"finished"`
+	DroppedEvents int       `json:"dropped_events,omitempty"` // Events not delivered because the channel was full
+}
+
+// Duration returns how long the run took
+func (r Result) Duration() time.Duration {
+	return r.Finished.Sub(r.Started)
+}
+
+// Option configures a Runner
+type Option func(*Runner)
+
+// WithParams sets simulation parameters. Later options override earlier ones per name.
+func WithParams(params map[string]interface{}) Option {
+	return func(r *Runner) {
+		for name, value := range params {
+			r.params[name] = value
+		}
+	}
+}
+
+// WithParam sets a single simulation parameter
+func WithParam(name string, value interface{}) Option {
+	return func(r *Runner) {
+		r.params[name] = value
+	}
+}
+
+// WithOrganization sets the Legion organization the run creates entities in
+func WithOrganization(orgID string) Option {
+	return WithParam("organization_id", orgID)
+}
+
+// WithRegistry looks the simulation up in registry instead of simulation.DefaultRegistry
+func WithRegistry(registry *simulation.Registry) Option {
+	return func(r *Runner) {
+		r.registry = registry
+	}
+}
+
+// WithEventBuffer sets the events channel capacity
+func WithEventBuffer(size int) Option {
+	return func(r *Runner) {
+		r.eventBuffer = size
+	}
+}
+
+// Runner drives a single run of a simulation
+type Runner struct {
+	registry    *simulation.Registry
+	params      map[string]interface{}
+	eventBuffer int
+
+	sim     simulation.Simulation
+	events  chan simulation.Event
+	dropped int
+
+	mu      sync.Mutex
+	started bool
+	done    bool // events is closed
+}
+
+// New creates and configures the named simulation. Configuration errors are returned
+// here, before anything touches Legion.
+func New(name string, opts ...Option) (*Runner, error) {
+	r := &Runner{
+		registry:    simulation.DefaultRegistry,
+		params:      make(map[string]interface{}),
+		eventBuffer: DefaultEventBuffer,
+	}
+	for _, opt := range opts {
+		opt(r)
+	}
+
+	sim, err := r.registry.Get(name)
+	if err != nil {
+		return nil, err
+	}
+	if err := sim.Configure(r.params); err != nil {
+		return nil, fmt.Errorf("failed to configure simulation: %w", err)
+	}
+	r.sim = sim
+	r.events = make(chan simulation.Event, r.eventBuffer)
+
+	if observable, ok := sim.(simulation.Observable); ok {
+		observable.Observe(r.publish)
+	}
+	return r, nil
+}
+
+// Simulation returns the configured simulation, e.g. to check for simulation.Estimator
+func (r *Runner) Simulation() simulation.Simulation {
+	return r.sim
+}
+
+// Events streams the run's events. The channel is closed when Run returns. Simulations
+// that don't implement simulation.Observable send nothing.
+func (r *Runner) Events() <-chan simulation.Event {
+	return r.events
+}
+
+// Run executes the simulation until it finishes, ctx is cancelled or Stop is called.
+// A Runner runs once.
+func (r *Runner) Run(ctx context.Context, legion *client.Legion) (*Result, error) {
+	r.mu.Lock()
+	if r.started {
+		r.mu.Unlock()
+		return nil, ErrAlreadyRun
+	}
+	r.started = true
+	r.mu.Unlock()
+
+	result := &Result{Simulation: r.sim.Name(), Started: time.Now()}
+	simResult, err := r.sim.Run(ctx, legion)
+	result.Finished = time.Now()
+	if simResult != nil {
+		result.Result = *simResult
+	}
+	if result.Termination == "" {
+		result.Termination = defaultTermination(result.Outcome, err)
+	}
+
+	r.mu.Lock()
+	close(r.events)
+	r.done = true
+	result.DroppedEvents = r.dropped
+	r.mu.Unlock()
+
+	if err != nil {
+		return result, fmt.Errorf("simulation failed: %w", err)
+	}
+	return result, nil
+}
+
+// defaultTermination says why a run ended when its simulation didn't: stopped runs were
+// stopped, and others that finished without an objective to meet ran their duration
+func defaultTermination(outcome string, err error) string {
+	switch {
+	case err != nil:
+		return simulation.TerminationError
+	case outcome == simulation.OutcomeStopped:
+		return simulation.TerminationStopped
+	case outcome == simulation.OutcomeCompleted, outcome == simulation.OutcomePartial:
+		return simulation.TerminationDuration
+	}
+	return ""
+}
+
+// Stop asks a running simulation to shut down; Run returns once it has
+func (r *Runner) Stop() error {
+	return r.sim.Stop()
+}
+
+// publish hands an event to the channel without ever blocking the simulation
+func (r *Runner) publish(event simulation.Event) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.done {
+		return
+	}
+	select {
+	case r.events <- event:
+	default:
+		r.dropped++
+	}
+}
@@ -0,0 +1,86 @@
+package runner
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/picogrid/legion-simulations/pkg/client"
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// fakeSim emits one event per configured tick and reports the ticks in its result
+type fakeSim struct {
+	ticks   int
+	observe func(simulation.Event)
+}
+
+func (f *fakeSim) Name() string        { return "Fake" }
+func (f *fakeSim) Description() string { return "test simulation" }
+func (f *fakeSim) Stop() error         { return nil }
+
+func (f *fakeSim) Configure(params map[string]interface{}) error {
+	ticks, ok := params["ticks"].(int)
+	if !ok || ticks < 1 {
+		return errors.New("ticks must be at least 1")
+	}
+	f.ticks = ticks
+	return nil
+}
+
+func (f *fakeSim) Run(context.Context, *client.Legion) (*simulation.Result, error) {
+	for i := 0; i < f.ticks; i++ {
+		f.observe(simulation.Event{Type: "tick", Message: "tick"})
+	}
+	result := simulation.NewResult(simulation.OutcomeCompleted, "done")
+	result.Stats["ticks"] = float64(f.ticks)
+	return result, nil
+}
+
+func (f *fakeSim) Observe(fn func(simulation.Event)) { f.observe = fn }
+
+func testRegistry(t *testing.T) *simulation.Registry {
+	t.Helper()
+	registry := simulation.NewRegistry()
+	if err := registry.Register("Fake", func() simulation.Simulation { return &fakeSim{} }); err != nil {
+		t.Fatal(err)
+	}
+	return registry
+}
+
+func TestRunnerRunsAndReports(t *testing.T) {
+	r, err := New("Fake", WithRegistry(testRegistry(t)), WithParam("ticks", 3), WithEventBuffer(2))
+	if err != nil {
+		t.Fatalf("New: %v", err)
+	}
+
+	result, err := r.Run(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Run: %v", err)
+	}
+	if result.Outcome != simulation.OutcomeCompleted || result.Summary != "done" || result.Stats["ticks"] != 3 ||
+		result.Termination != simulation.TerminationDuration {
+		t.Fatalf("unexpected result: %+v", result.Result)
+	}
+
+	var received int
+	for range r.Events() {
+		received++
+	}
+	if received != 2 || result.DroppedEvents != 1 {
+		t.Fatalf("received %d events with %d dropped, want 2 and 1", received, result.DroppedEvents)
+	}
+
+	if _, err := r.Run(context.Background(), nil); !errors.Is(err, ErrAlreadyRun) {
+		t.Fatalf("second Run: got %v, want ErrAlreadyRun", err)
+	}
+}
+
+func TestNewReturnsConfigurationErrors(t *testing.T) {
+	if _, err := New("Fake", WithRegistry(testRegistry(t))); err == nil {
+		t.Fatal("expected a configuration error")
+	}
+	if _, err := New("Missing", WithRegistry(testRegistry(t))); err == nil {
+		t.Fatal("expected an unknown simulation error")
+	}
+}
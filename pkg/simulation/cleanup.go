@@ -0,0 +1,14 @@
+package simulation
+
+// CleanupPatterns name what a simulation leaves in Legion, so it can be purged
+// without starting a run
+type CleanupPatterns struct {
+	EntityPrefixes []string // Entity name prefixes
+	FeedPatterns   []string // Substrings of feed definition names
+}
+
+// Cleaner is implemented by simulations that can name the entities and feed
+// definitions their runs create
+type Cleaner interface {
+	CleanupPatterns() CleanupPatterns
+}
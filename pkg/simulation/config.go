@@ -3,21 +3,21 @@ package simulation
 // SimulationConfig represents the configuration structure for a simulation
 // loaded from simulation.yaml
 type SimulationConfig struct {
-	Name        string      `yaml:"name"`
-	Description string      `yaml:"description"`
-	Version     string      `yaml:"version"`
-	Category    string      `yaml:"category"`
-	Parameters  []Parameter `yaml:"parameters"`
+	Name        string      `yaml:"name" json:"name"`
+	Description string      `yaml:"description" json:"description"`
+	Version     string      `yaml:"version" json:"version"`
+	Category    string      `yaml:"category" json:"category"`
+	Parameters  []Parameter `yaml:"parameters" json:"parameters"`
 }
 
 // Parameter defines a configurable parameter for a simulation
 type Parameter struct {
-	Name        string      `yaml:"name"`
-	Type        string      `yaml:"type"` // integer, float, string, duration, boolean
-	Description string      `yaml:"description"`
-	Default     interface{} `yaml:"default"`
-	Required    bool        `yaml:"required"`
-	Min         interface{} `yaml:"min,omitempty"`
-	Max         interface{} `yaml:"max,omitempty"`
-	Options     []string    `yaml:"options,omitempty"` // For string enums
+	Name        string      `yaml:"name" json:"name"`
+	Type        string      `yaml:"type" json:"type"` // integer, float, string, duration, boolean
+	Description string      `yaml:"description" json:"description"`
+	Default     interface{} `yaml:"default" json:"default"`
+	Required    bool        `yaml:"required" json:"required"`
+	Min         interface{} `yaml:"min,omitempty" json:"min,omitempty"`
+	Max         interface{} `yaml:"max,omitempty" json:"max,omitempty"`
+	Options     []string    `yaml:"options,omitempty" json:"options,omitempty"` // For string enums
 }
@@ -0,0 +1,52 @@
+package simulation
+
+import (
+	"fmt"
+	"time"
+)
+
+// Estimate is the Legion load a configured run is expected to generate
+type Estimate struct {
+	Duration              time.Duration  `json:"-"`
+	Entities              int            `json:"entities"`              // Entities created
+	SetupCalls            int            `json:"setup_calls"`           // API calls before the first tick
+	RunCalls              int            `json:"run_calls"`             // API calls during the run
+	FeedMessages          int            `json:"feed_messages"`         // Feed messages during the run
+	PeakCallsPerMinute    float64        `json:"peak_calls_per_minute"` // With every threat airborne and tracked
+	FeedMessagesPerMinute float64        `json:"feed_messages_per_minute"`
+	Breakdown             []EstimateLine `json:"breakdown"`
+	Notes                 []string       `json:"notes,omitempty"` // Assumptions worth knowing
+}
+
+// EstimateLine is one source of API traffic
+type EstimateLine struct {
+	Source         string  `json:"source"`
+	Calls          int     `json:"calls"`
+	CallsPerMinute float64 `json:"calls_per_minute"`
+}
+
+// TotalCalls returns setup and run calls together
+func (e Estimate) TotalCalls() int {
+	return e.SetupCalls + e.RunCalls
+}
+
+// Estimator is implemented by simulations that can predict their Legion load from
+// their configuration without connecting
+type Estimator interface {
+	Estimate() Estimate
+}
+
+// QuotaWarnings compares the estimate with an organization's limits. Zero limits are skipped.
+func (e Estimate) QuotaWarnings(callsPerMinute, feedMessagesPerMinute, entities int) []string {
+	var warnings []string
+	if callsPerMinute > 0 && e.PeakCallsPerMinute > float64(callsPerMinute) {
+		warnings = append(warnings, fmt.Sprintf("peak of %.0f API calls/min exceeds the quota of %d; expect rate limiting", e.PeakCallsPerMinute, callsPerMinute))
+	}
+	if feedMessagesPerMinute > 0 && e.FeedMessagesPerMinute > float64(feedMessagesPerMinute) {
+		warnings = append(warnings, fmt.Sprintf("%.0f feed messages/min exceeds the quota of %d", e.FeedMessagesPerMinute, feedMessagesPerMinute))
+	}
+	if entities > 0 && e.Entities > entities {
+		warnings = append(warnings, fmt.Sprintf("%d entities exceeds the quota of %d", e.Entities, entities))
+	}
+	return warnings
+}
@@ -17,8 +17,9 @@ type Simulation interface {
 	// Configure sets up the simulation with the provided parameters
 	Configure(params map[string]interface{}) error
 
-	// Run executes the simulation using the provided Legion client
-	Run(ctx context.Context, client *client.Legion) error
+	// Run executes the simulation using the provided Legion client. The result may
+	// accompany an error to describe what the run did before it failed.
+	Run(ctx context.Context, client *client.Legion) (*Result, error)
 
 	// Stop gracefully shuts down the simulation
 	Stop() error
@@ -0,0 +1,166 @@
+package simulation
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Laydown geometry types
+const (
+	GeometryPoint   = "Point"
+	GeometryPolygon = "Polygon" // A single closed ring
+)
+
+// Laydown is where a configured run puts its entities at the start: defenders, the
+// assets they defend and where threats come from
+type Laydown struct {
+	Name     string
+	Features []LaydownFeature
+}
+
+// LaydownFeature is one placed entity or area. Coordinates are [lon, lat] in WGS84
+// degrees: one pair for a point, a closed ring for a polygon.
+type LaydownFeature struct {
+	Name        string
+	Kind        string // What the feature is, e.g. defended_asset or counter_uas
+	Geometry    string
+	Coordinates [][2]float64
+	AltitudeM   float64 // Above the ellipsoid; points only
+	Properties  map[string]interface{}
+}
+
+// Mapper is implemented by simulations that can lay out their entities from their
+// configuration without connecting
+type Mapper interface {
+	Laydown() Laydown
+}
+
+// WriteGeoJSON writes the laydown as a GeoJSON FeatureCollection. Each feature's
+// properties carry its name, kind and altitude alongside its own properties.
+func (l Laydown) WriteGeoJSON(w io.Writer) error {
+	type geometry struct {
+		Type        string      `json:"type"`
+		Coordinates interface{} `json:"coordinates"`
+	}
+	type feature struct {
+		Type       string                 `json:"type"`
+		Geometry   geometry               `json:"geometry"`
+		Properties map[string]interface{} `json:"properties"`
+	}
+
+	features := make([]feature, 0, len(l.Features))
+	for _, f := range l.Features {
+		if len(f.Coordinates) == 0 {
+			continue
+		}
+		props := map[string]interface{}{"name": f.Name, "kind": f.Kind}
+		for key, value := range f.Properties {
+			props[key] = value
+		}
+		geom := geometry{Type: f.Geometry}
+		switch f.Geometry {
+		case GeometryPoint:
+			geom.Coordinates = []float64{f.Coordinates[0][0], f.Coordinates[0][1], f.AltitudeM}
+			props["altitude_m"] = f.AltitudeM
+		case GeometryPolygon:
+			geom.Coordinates = [][][2]float64{f.Coordinates}
+		default:
+			return fmt.Errorf("feature %s has unsupported geometry %q", f.Name, f.Geometry)
+		}
+		features = append(features, feature{Type: "Feature", Geometry: geom, Properties: props})
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Type     string    `json:"type"`
+		Name     string    `json:"name,omitempty"`
+		Features []feature `json:"features"`
+	}{"FeatureCollection", l.Name, features})
+}
+
+// WriteKML writes the laydown as a KML document with a folder per feature kind.
+// Properties become the placemarks' extended data.
+func (l Laydown) WriteKML(w io.Writer) error {
+	type data struct {
+		Name  string `xml:"name,attr"`
+		Value string `xml:"value"`
+	}
+	type point struct {
+		AltitudeMode string `xml:"altitudeMode"`
+		Coordinates  string `xml:"coordinates"`
+	}
+	type polygon struct {
+		Coordinates string `xml:"outerBoundaryIs>LinearRing>coordinates"`
+	}
+	type placemark struct {
+		Name    string   `xml:"name"`
+		Data    []data   `xml:"ExtendedData>Data,omitempty"`
+		Point   *point   `xml:"Point,omitempty"`
+		Polygon *polygon `xml:"Polygon,omitempty"`
+	}
+	type folder struct {
+		Name       string      `xml:"name"`
+		Placemarks []placemark `xml:"Placemark"`
+	}
+
+	var folders []folder
+	index := make(map[string]int)
+	for _, f := range l.Features {
+		if len(f.Coordinates) == 0 {
+			continue
+		}
+		mark := placemark{Name: f.Name}
+		keys := make([]string, 0, len(f.Properties))
+		for key := range f.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		mark.Data = append(mark.Data, data{"kind", f.Kind})
+		for _, key := range keys {
+			mark.Data = append(mark.Data, data{key, fmt.Sprint(f.Properties[key])})
+		}
+		switch f.Geometry {
+		case GeometryPoint:
+			mark.Point = &point{"absolute", fmt.Sprintf("%.7f,%.7f,%.1f", f.Coordinates[0][0], f.Coordinates[0][1], f.AltitudeM)}
+		case GeometryPolygon:
+			ring := make([]string, len(f.Coordinates))
+			for i, c := range f.Coordinates {
+				ring[i] = fmt.Sprintf("%.7f,%.7f", c[0], c[1])
+			}
+			mark.Polygon = &polygon{strings.Join(ring, " ")}
+		default:
+			return fmt.Errorf("feature %s has unsupported geometry %q", f.Name, f.Geometry)
+		}
+
+		i, ok := index[f.Kind]
+		if !ok {
+			i = len(folders)
+			index[f.Kind] = i
+			folders = append(folders, folder{Name: f.Kind})
+		}
+		folders[i].Placemarks = append(folders[i].Placemarks, mark)
+	}
+
+	doc := struct {
+		XMLName xml.Name `xml:"kml"`
+		NS      string   `xml:"xmlns,attr"`
+		Name    string   `xml:"Document>name"`
+		Folders []folder `xml:"Document>Folder"`
+	}{NS: "http://www.opengis.net/kml/2.2", Name: l.Name, Folders: folders}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	enc.Indent("", "  ")
+	if err := enc.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
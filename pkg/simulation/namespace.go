@@ -0,0 +1,53 @@
+package simulation
+
+import (
+	"fmt"
+	"strings"
+)
+
+// RunIDParameter is the parameter a simulation declares to namespace its names from --run-id
+const RunIDParameter = "run_id"
+
+// maxRunIDLength keeps namespaced names readable in Legion's entity list
+const maxRunIDLength = 32
+
+// ValidateRunID checks that a run ID can namespace names: letters, digits, '-' and
+// '_', at most 32 characters
+func ValidateRunID(runID string) error {
+	if runID == "" {
+		return fmt.Errorf("run ID is empty")
+	}
+	if len(runID) > maxRunIDLength {
+		return fmt.Errorf("run ID %q is longer than %d characters", runID, maxRunIDLength)
+	}
+	for _, r := range runID {
+		if !validRunIDRune(r) {
+			return fmt.Errorf("run ID %q may only contain letters, digits, '-' and '_'", runID)
+		}
+	}
+	return nil
+}
+
+func validRunIDRune(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_'
+}
+
+// Namespace puts a name in a run's namespace, e.g. "alice/Counter-UAS-01", so runs
+// with different run IDs can share an organization. Without a run ID the name is
+// returned unchanged.
+func Namespace(runID, name string) string {
+	if runID == "" {
+		return name
+	}
+	return runID + "/" + name
+}
+
+// SplitNamespace returns the run ID a name was namespaced with and the name without
+// it. A name outside any namespace has an empty run ID.
+func SplitNamespace(name string) (runID, rest string) {
+	prefix, rest, ok := strings.Cut(name, "/")
+	if !ok || ValidateRunID(prefix) != nil {
+		return "", name
+	}
+	return prefix, rest
+}
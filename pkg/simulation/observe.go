@@ -0,0 +1,18 @@
+package simulation
+
+import "time"
+
+// Event is something notable that happened during a run
+type Event struct {
+	Time     time.Time              `json:"time"`
+	Type     string                 `json:"type"`
+	Severity string                 `json:"severity"`
+	Message  string                 `json:"message"`
+	Details  map[string]interface{} `json:"details,omitempty"`
+}
+
+// Observable is implemented by simulations that report events as they happen.
+// fn is called from the simulation loop and must not block.
+type Observable interface {
+	Observe(fn func(Event))
+}
@@ -0,0 +1,158 @@
+package simulation
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Parse converts a value typed by the user, on the command line or in a LEGION_*
+// variable, to the parameter's type
+func (p Parameter) Parse(value string) (interface{}, error) {
+	switch p.Type {
+	case "integer":
+		return strconv.Atoi(strings.TrimSpace(value))
+	case "float":
+		return strconv.ParseFloat(strings.TrimSpace(value), 64)
+	case "string":
+		return value, nil
+	case "boolean":
+		return strconv.ParseBool(strings.TrimSpace(value))
+	case "duration":
+		return time.ParseDuration(strings.TrimSpace(value))
+	default:
+		return nil, fmt.Errorf("unsupported parameter type: %s", p.Type)
+	}
+}
+
+// Convert coerces a decoded value, from YAML, JSON or a simulation's own defaults, to the
+// parameter's type. Whole floats are integers, integers are floats, strings are parsed
+// and bare numbers for a duration are seconds.
+func (p Parameter) Convert(value interface{}) (interface{}, error) {
+	if s, ok := value.(string); ok && p.Type != "string" {
+		return p.Parse(s)
+	}
+
+	switch p.Type {
+	case "integer":
+		switch v := value.(type) {
+		case int:
+			return v, nil
+		case int64:
+			return int(v), nil
+		case float64:
+			if v == float64(int(v)) {
+				return int(v), nil
+			}
+		}
+	case "float":
+		switch v := value.(type) {
+		case int:
+			return float64(v), nil
+		case int64:
+			return float64(v), nil
+		case float64:
+			return v, nil
+		}
+	case "boolean":
+		if v, ok := value.(bool); ok {
+			return v, nil
+		}
+	case "string":
+		if value == nil {
+			return "", nil
+		}
+		return fmt.Sprint(value), nil
+	case "duration":
+		switch v := value.(type) {
+		case time.Duration:
+			return v, nil
+		case int:
+			return time.Duration(v) * time.Second, nil
+		case int64:
+			return time.Duration(v) * time.Second, nil
+		case float64:
+			return time.Duration(v * float64(time.Second)), nil
+		}
+	default:
+		return nil, fmt.Errorf("unsupported parameter type: %s", p.Type)
+	}
+	return nil, fmt.Errorf("expected %s, got %v", p.Type, value)
+}
+
+// Validate checks a value already converted to the parameter's type against its range
+// and options
+func (p Parameter) Validate(value interface{}) error {
+	switch v := value.(type) {
+	case int:
+		return p.checkRange(float64(v))
+	case float64:
+		return p.checkRange(v)
+	case time.Duration:
+		return p.checkDurationRange(v)
+	case string:
+		// An optional parameter left empty takes the simulation's default
+		if len(p.Options) == 0 || v == "" && !p.Required {
+			return nil
+		}
+		for _, option := range p.Options {
+			if v == option {
+				return nil
+			}
+		}
+		return fmt.Errorf("must be one of %s", strings.Join(p.Options, ", "))
+	}
+	return nil
+}
+
+// checkRange checks a number against Min and Max
+func (p Parameter) checkRange(v float64) error {
+	if lo, ok := boundNumber(p.Min); ok && v < lo {
+		return fmt.Errorf("must be at least %v", p.Min)
+	}
+	if hi, ok := boundNumber(p.Max); ok && v > hi {
+		return fmt.Errorf("must be at most %v", p.Max)
+	}
+	return nil
+}
+
+// checkDurationRange checks a duration against Min and Max, given as durations or seconds
+func (p Parameter) checkDurationRange(v time.Duration) error {
+	bound := Parameter{Type: "duration"}
+	if p.Min != nil {
+		if lo, err := bound.Convert(p.Min); err == nil && v < lo.(time.Duration) {
+			return fmt.Errorf("must be at least %s", lo)
+		}
+	}
+	if p.Max != nil {
+		if hi, err := bound.Convert(p.Max); err == nil && v > hi.(time.Duration) {
+			return fmt.Errorf("must be at most %s", hi)
+		}
+	}
+	return nil
+}
+
+// boundNumber reads a Min or Max as a number
+func boundNumber(bound interface{}) (float64, bool) {
+	if bound == nil {
+		return 0, false
+	}
+	v, err := Parameter{Type: "float"}.Convert(bound)
+	if err != nil {
+		return 0, false
+	}
+	return v.(float64), true
+}
+
+// Resolve converts a value to the parameter's type and validates it
+func (p Parameter) Resolve(value interface{}) (interface{}, error) {
+	converted, err := p.Convert(value)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.Validate(converted); err != nil {
+		return nil, err
+	}
+	return converted, nil
+}
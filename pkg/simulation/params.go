@@ -0,0 +1,112 @@
+package simulation
+
+import (
+	"fmt"
+	"time"
+)
+
+// ParamReader reads typed values from the parameters passed to Configure, converting
+// them as the CLI does so a simulation doesn't care whether a value came from a prompt,
+// YAML, JSON or --set. Each method sets its destination only when the parameter was
+// given and reports whether it did; a value that can't be converted is kept as Err and
+// leaves the destination alone.
+type ParamReader struct {
+	params map[string]interface{}
+	err    error
+}
+
+// ReadParams returns a reader over Configure's parameters
+func ReadParams(params map[string]interface{}) *ParamReader {
+	return &ParamReader{params: params}
+}
+
+// Has reports whether the parameter was given
+func (r *ParamReader) Has(name string) bool {
+	_, ok := r.params[name]
+	return ok
+}
+
+// Int reads an integer parameter
+func (r *ParamReader) Int(name string, dst *int) bool {
+	v, ok := r.read(name, "integer")
+	if ok {
+		*dst = v.(int)
+	}
+	return ok
+}
+
+// Int64 reads an integer parameter into an int64
+func (r *ParamReader) Int64(name string, dst *int64) bool {
+	v, ok := r.read(name, "integer")
+	if ok {
+		*dst = int64(v.(int))
+	}
+	return ok
+}
+
+// Float reads a float parameter
+func (r *ParamReader) Float(name string, dst *float64) bool {
+	v, ok := r.read(name, "float")
+	if ok {
+		*dst = v.(float64)
+	}
+	return ok
+}
+
+// Bool reads a boolean parameter
+func (r *ParamReader) Bool(name string, dst *bool) bool {
+	v, ok := r.read(name, "boolean")
+	if ok {
+		*dst = v.(bool)
+	}
+	return ok
+}
+
+// String reads a string parameter
+func (r *ParamReader) String(name string, dst *string) bool {
+	v, ok := r.read(name, "string")
+	if ok {
+		*dst = v.(string)
+	}
+	return ok
+}
+
+// Duration reads a duration parameter; bare numbers are seconds
+func (r *ParamReader) Duration(name string, dst *time.Duration) bool {
+	v, ok := r.read(name, "duration")
+	if ok {
+		*dst = v.(time.Duration)
+	}
+	return ok
+}
+
+// Seconds reads a float parameter given in seconds as a duration
+func (r *ParamReader) Seconds(name string, dst *time.Duration) bool {
+	var seconds float64
+	if !r.Float(name, &seconds) {
+		return false
+	}
+	*dst = time.Duration(seconds * float64(time.Second))
+	return true
+}
+
+// Err returns the first parameter that could not be converted
+func (r *ParamReader) Err() error {
+	return r.err
+}
+
+// read converts a given parameter to typ, recording the first failure
+func (r *ParamReader) read(name, typ string) (interface{}, bool) {
+	value, ok := r.params[name]
+	if !ok {
+		return nil, false
+	}
+	converted, err := Parameter{Name: name, Type: typ}.Convert(value)
+	if err != nil {
+		if r.err == nil {
+			r.err = fmt.Errorf("invalid %s: %w", name, err)
+		}
+		return nil, false
+	}
+	return converted, true
+}
@@ -0,0 +1,11 @@
+package simulation
+
+// Pausable is implemented by simulations that can be held mid-run. While paused the
+// scenario clock stops and nothing moves, but entities stay fresh in Legion. Pause and
+// Resume may be called from any goroutine; they report false when the run was already
+// in that state. source says who asked, e.g. "signal" or "keyboard".
+type Pausable interface {
+	Pause(source string) bool
+	Resume(source string) bool
+	Paused() bool
+}
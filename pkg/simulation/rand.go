@@ -0,0 +1,50 @@
+package simulation
+
+import (
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// SeedParameter is the parameter a simulation declares to take its seed from --seed
+const SeedParameter = "seed"
+
+// NewRand returns the random source for a run and the seed it was made from. A zero
+// seed is replaced with one from the clock, so every run has a seed to log and to
+// reproduce it with. The source is safe for concurrent use.
+func NewRand(seed int64) (*rand.Rand, int64) {
+	for seed == 0 {
+		seed = time.Now().UnixNano() & (1<<53 - 1) // Exact in JSON and YAML numbers
+	}
+	return rand.New(&lockedSource{src: rand.NewSource(seed).(rand.Source64)}), seed
+}
+
+// DeriveRand returns an independent source seeded from r. Giving each entity its own
+// stream keeps its draws the same whichever order entities are visited in.
+func DeriveRand(r *rand.Rand) *rand.Rand {
+	return rand.New(&lockedSource{src: rand.NewSource(r.Int63()).(rand.Source64)})
+}
+
+// lockedSource serializes a rand.Source, which is not safe for concurrent use
+type lockedSource struct {
+	mu  sync.Mutex
+	src rand.Source64
+}
+
+func (s *lockedSource) Int63() int64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Int63()
+}
+
+func (s *lockedSource) Uint64() uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.src.Uint64()
+}
+
+func (s *lockedSource) Seed(seed int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.src.Seed(seed)
+}
@@ -0,0 +1,22 @@
+package simulation
+
+// Reloader is implemented by simulations that can take configuration changes while
+// running. Only non-structural parameters are applied; the rest are reported back as
+// rejected so the caller can tell the operator a restart is needed.
+type Reloader interface {
+	Reload(params map[string]interface{}, source string) (ReloadResult, error)
+}
+
+// ParamChange is one parameter considered by a reload
+type ParamChange struct {
+	Name   string `json:"name"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new"`
+	Reason string `json:"reason,omitempty"` // Why the change was rejected
+}
+
+// ReloadResult reports which changes a reload applied and which it refused
+type ReloadResult struct {
+	Applied  []ParamChange `json:"applied"`
+	Rejected []ParamChange `json:"rejected,omitempty"`
+}
@@ -0,0 +1,47 @@
+package simulation
+
+// Outcomes a run can end with. The CLI maps each to its own exit code.
+const (
+	OutcomeSuccess   = "success"   // Objectives met
+	OutcomePartial   = "partial"   // Ran its full duration without meeting every objective
+	OutcomeFailure   = "failure"   // Objectives failed
+	OutcomeCompleted = "completed" // Ran its full duration; nothing to win or lose
+	OutcomeStopped   = "stopped"   // Stopped or cancelled before it finished
+)
+
+// Terminations common to every simulation. Simulations with objectives add their own,
+// e.g. "threats_eliminated".
+const (
+	TerminationDuration = "duration_elapsed" // Ran its configured duration
+	TerminationStopped  = "stopped"          // Stopped, interrupted or cancelled
+	TerminationError    = "error"            // Ended by an error
+)
+
+// Result is what a run returns: how it ended, its headline numbers, the files it wrote
+// and the Legion entities it created, so scripts can act on a run without parsing logs
+type Result struct {
+	Outcome     string             `json:"outcome"`
+	Termination string             `json:"termination,omitempty"` // Why it ended, e.g. "duration_elapsed"
+	Summary     string             `json:"summary,omitempty"`     // One line, e.g. "SUCCESS - All threats eliminated"
+	Seed        int64              `json:"seed,omitempty"`        // Random seed the run used; --seed repeats it
+	Stats       map[string]float64 `json:"stats,omitempty"`       // Headline numbers by name
+	Artifacts   []string           `json:"artifacts,omitempty"`   // Local paths of reports and other outputs
+	Entities    []EntityRecord     `json:"entities,omitempty"`    // Created or adopted in Legion
+}
+
+// EntityRecord is a Legion entity a run created or adopted
+type EntityRecord struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// NewResult starts a result with the given outcome and summary
+func NewResult(outcome, summary string) *Result {
+	return &Result{Outcome: outcome, Summary: summary, Stats: make(map[string]float64)}
+}
+
+// AddEntity records an entity in the result's manifest
+func (r *Result) AddEntity(id, name, entityType string) {
+	r.Entities = append(r.Entities, EntityRecord{ID: id, Name: name, Type: entityType})
+}
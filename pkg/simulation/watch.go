@@ -0,0 +1,45 @@
+package simulation
+
+import (
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/spectate"
+)
+
+// Watchable is implemented by simulations that can describe their live state for the
+// run dashboard. Snapshot is called from another goroutine while the run is in progress.
+type Watchable interface {
+	Snapshot() spectate.Snapshot
+}
+
+// Buffer health states
+const (
+	BufferOK       = "ok"
+	BufferDegraded = "degraded" // Sending, but a share of updates is failing
+	BufferStalled  = "stalled"  // Updates are pending and nothing has been flushed for a while
+)
+
+// Status is a running simulation's progress, for "legion-sim status"
+type Status struct {
+	Phase  string             `json:"phase"` // Tick phase, or setup, shutdown, reporting or complete
+	Tick   int                `json:"tick"`
+	Stats  map[string]float64 `json:"stats,omitempty"`
+	Buffer *BufferHealth      `json:"buffer,omitempty"`
+}
+
+// BufferHealth describes the queue of updates waiting to go to Legion
+type BufferHealth struct {
+	State     string    `json:"state"`
+	Pending   int64     `json:"pending"`
+	Sent      int64     `json:"sent"`
+	Failed    int64     `json:"failed"`
+	Shed      int64     `json:"shed,omitempty"`
+	LastFlush time.Time `json:"last_flush"`
+	LastError string    `json:"last_error,omitempty"`
+}
+
+// StatusReporter is implemented by simulations that can report their progress while
+// running as a daemon. Status is called from another goroutine.
+type StatusReporter interface {
+	Status() Status
+}
@@ -0,0 +1,59 @@
+package spectate
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// Stream connects to a simulation's spectator endpoint and calls fn for every snapshot
+// until the context is cancelled, the stream ends, or fn returns false.
+func Stream(ctx context.Context, baseURL string, fn func(Snapshot) bool) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, strings.TrimRight(baseURL, "/")+"/v1/stream", nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Accept", "text/event-stream")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to connect to spectator endpoint: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("spectator endpoint returned status %d", resp.StatusCode)
+	}
+
+	scanner := bufio.NewScanner(resp.Body)
+	scanner.Buffer(make([]byte, 64*1024), 16*1024*1024)
+
+	var data strings.Builder
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "data:"):
+			data.WriteString(strings.TrimPrefix(strings.TrimPrefix(line, "data:"), " "))
+		case line == "":
+			if data.Len() == 0 {
+				continue
+			}
+			var snapshot Snapshot
+			if err := json.Unmarshal([]byte(data.String()), &snapshot); err != nil {
+				return fmt.Errorf("failed to parse snapshot: %w", err)
+			}
+			data.Reset()
+			if !fn(snapshot) {
+				return nil
+			}
+		}
+	}
+
+	if err := scanner.Err(); err != nil && ctx.Err() == nil {
+		return fmt.Errorf("spectator stream interrupted: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,238 @@
+package spectate
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/fatih/color"
+)
+
+// Map glyphs
+const (
+	glyphOrigin    = '+'
+	glyphBlue      = 'B'
+	glyphRed       = 'R'
+	glyphDestroyed = 'x'
+	glyphEmpty     = '.'
+)
+
+// RenderOptions controls dashboard layout
+type RenderOptions struct {
+	MapWidth  int  // Columns (default 61)
+	MapHeight int  // Rows (default 21)
+	MaxUnits  int  // Unit table rows (default 12)
+	MaxEvents int  // Recent events shown (default 8)
+	Live      bool // Drawing the local run rather than a spectated one
+}
+
+func (o RenderOptions) withDefaults() RenderOptions {
+	if o.MapWidth <= 0 {
+		o.MapWidth = 61
+	}
+	if o.MapHeight <= 0 {
+		o.MapHeight = 21
+	}
+	if o.MaxUnits <= 0 {
+		o.MaxUnits = 12
+	}
+	if o.MaxEvents <= 0 {
+		o.MaxEvents = 8
+	}
+	return o
+}
+
+// Render draws a text dashboard: header, scoreboard, map, unit table, and recent events
+func Render(w io.Writer, snapshot Snapshot, opts RenderOptions) {
+	opts = opts.withDefaults()
+	bold := color.New(color.Bold)
+
+	_, _ = bold.Fprintf(w, "%s  [%s]  %s", snapshot.Simulation, strings.ToUpper(snapshot.Status), formatClock(snapshot))
+	_, _ = fmt.Fprintf(w, "  run %s", shortID(snapshot.RunID))
+	if !opts.Live {
+		_, _ = fmt.Fprint(w, "  (spectating, read-only)")
+	}
+	_, _ = fmt.Fprintln(w)
+	if snapshot.Outcome != "" {
+		_, _ = bold.Fprintf(w, "Outcome: %s\n", snapshot.Outcome)
+	}
+
+	// Scoreboard
+	parts := make([]string, 0, len(snapshot.Counters))
+	for _, counter := range snapshot.Counters {
+		parts = append(parts, fmt.Sprintf("%s: %s", counter.Name, strconv.FormatFloat(counter.Value, 'f', -1, 64)))
+	}
+	_, _ = fmt.Fprintln(w, strings.Join(parts, "  |  "))
+	_, _ = fmt.Fprintf(w, "Blue: %s\n", StatusCounts(snapshot.Units, SideBlue))
+	_, _ = fmt.Fprintf(w, "Red:  %s\n", StatusCounts(snapshot.Units, SideRed))
+	_, _ = fmt.Fprintln(w)
+
+	// Map
+	for _, row := range RenderMap(snapshot, opts.MapWidth, opts.MapHeight) {
+		_, _ = fmt.Fprintln(w, colorizeRow(row))
+	}
+	_, _ = fmt.Fprintf(w, "%c origin  %c blue  %c red  %c destroyed  (±%.1f km)\n\n",
+		glyphOrigin, glyphBlue, glyphRed, glyphDestroyed, mapRadius(snapshot))
+
+	// Units
+	_, _ = bold.Fprintln(w, "Units")
+	shown := 0
+	for _, side := range []string{SideBlue, SideRed} {
+		for _, unit := range snapshot.Units {
+			if unit.Side != side || shown >= opts.MaxUnits {
+				continue
+			}
+			_, _ = fmt.Fprintf(w, "  %-4s %-18s %-12s %s\n", strings.ToUpper(unit.Side), unit.Name, unit.Status, unit.Detail)
+			shown++
+		}
+	}
+	if remaining := len(snapshot.Units) - shown; remaining > 0 {
+		_, _ = fmt.Fprintf(w, "  … %d more\n", remaining)
+	}
+	_, _ = fmt.Fprintln(w)
+
+	// Events
+	_, _ = bold.Fprintln(w, "Recent events")
+	events := snapshot.Events
+	if len(events) > opts.MaxEvents {
+		events = events[len(events)-opts.MaxEvents:]
+	}
+	for _, event := range events {
+		_, _ = fmt.Fprintf(w, "  %s  %-12s %s\n", event.Time.Format("15:04:05"), event.Type, event.Message)
+	}
+}
+
+// formatClock returns elapsed time, with the planned length and time left when known
+func formatClock(snapshot Snapshot) string {
+	elapsed := time.Duration(snapshot.ElapsedSeconds * float64(time.Second)).Round(time.Second)
+	if snapshot.DurationSeconds <= 0 {
+		return fmt.Sprintf("T+%s", elapsed)
+	}
+	duration := time.Duration(snapshot.DurationSeconds * float64(time.Second)).Round(time.Second)
+	return fmt.Sprintf("T+%s / %s (%s left)", elapsed, duration, max(duration-elapsed, 0))
+}
+
+// StatusCounts summarizes one side's units by status, most common first, e.g.
+// "3 TRACKING, 1 ENGAGING"
+func StatusCounts(units []Unit, side string) string {
+	counts := make(map[string]int)
+	for _, unit := range units {
+		if unit.Side == side {
+			counts[unit.Status]++
+		}
+	}
+	if len(counts) == 0 {
+		return "none"
+	}
+	statuses := make([]string, 0, len(counts))
+	for status := range counts {
+		statuses = append(statuses, status)
+	}
+	sort.Slice(statuses, func(i, j int) bool {
+		if counts[statuses[i]] != counts[statuses[j]] {
+			return counts[statuses[i]] > counts[statuses[j]]
+		}
+		return statuses[i] < statuses[j]
+	})
+	parts := make([]string, len(statuses))
+	for i, status := range statuses {
+		parts[i] = fmt.Sprintf("%d %s", counts[status], status)
+	}
+	return strings.Join(parts, ", ")
+}
+
+// RenderMap plots units on a character grid centered on the snapshot origin
+func RenderMap(snapshot Snapshot, width, height int) []string {
+	grid := make([][]rune, height)
+	for i := range grid {
+		grid[i] = []rune(strings.Repeat(string(glyphEmpty), width))
+	}
+
+	radius := mapRadius(snapshot)
+	plot := func(p Point, glyph rune) {
+		east, north := offsetKm(snapshot.Origin, p)
+		col := int(math.Round((east/radius + 1) / 2 * float64(width-1)))
+		row := int(math.Round((1 - north/radius) / 2 * float64(height-1)))
+		if col < 0 || col >= width || row < 0 || row >= height {
+			return
+		}
+		// Live units take precedence over wreckage sharing the cell
+		if glyph == glyphDestroyed && grid[row][col] != glyphEmpty {
+			return
+		}
+		grid[row][col] = glyph
+	}
+
+	plot(snapshot.Origin, glyphOrigin)
+	for _, unit := range snapshot.Units {
+		glyph := glyphRed
+		if unit.Side == SideBlue {
+			glyph = glyphBlue
+		}
+		if unit.Status == "DESTROYED" || unit.Status == "OFFLINE" {
+			glyph = glyphDestroyed
+		}
+		plot(unit.Point, glyph)
+	}
+
+	rows := make([]string, height)
+	for i, row := range grid {
+		rows[i] = string(row)
+	}
+	return rows
+}
+
+// mapRadius returns the half-width of the map in km
+func mapRadius(snapshot Snapshot) float64 {
+	if snapshot.RadiusKm > 0 {
+		return snapshot.RadiusKm
+	}
+	radius := 1.0
+	for _, unit := range snapshot.Units {
+		east, north := offsetKm(snapshot.Origin, unit.Point)
+		radius = math.Max(radius, math.Max(math.Abs(east), math.Abs(north)))
+	}
+	return radius
+}
+
+// offsetKm returns the east/north offset of p from origin (equirectangular approximation)
+func offsetKm(origin, p Point) (east, north float64) {
+	east = (p.Lon - origin.Lon) * 111.32 * math.Cos(origin.Lat*math.Pi/180)
+	north = (p.Lat - origin.Lat) * 110.57
+	return east, north
+}
+
+func colorizeRow(row string) string {
+	if color.NoColor {
+		return row
+	}
+	blue := color.New(color.FgBlue, color.Bold).SprintFunc()
+	red := color.New(color.FgRed, color.Bold).SprintFunc()
+	dim := color.New(color.Faint).SprintFunc()
+
+	var sb strings.Builder
+	for _, r := range row {
+		switch r {
+		case glyphBlue:
+			sb.WriteString(blue(string(r)))
+		case glyphRed:
+			sb.WriteString(red(string(r)))
+		case glyphEmpty:
+			sb.WriteString(dim(string(r)))
+		default:
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+func shortID(id string) string {
+	if len(id) > 8 {
+		return id[:8]
+	}
+	return id
+}
@@ -0,0 +1,218 @@
+// Package spectate streams read-only snapshots of a running simulation over HTTP so other
+// legion-sim instances can watch it without Legion credentials or write access.
+//
+// The simulation serves:
+//
+//	GET /v1/snapshot  latest snapshot as JSON
+//	GET /v1/stream    Server-Sent Events, one snapshot per "snapshot" event
+package spectate
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// DefaultListenAddr is the default spectator endpoint address
+const DefaultListenAddr = ":7500"
+
+// Run status values
+const (
+	StatusStarting = "starting"
+	StatusRunning  = "running"
+	StatusPaused   = "paused"
+	StatusComplete = "complete"
+)
+
+// Unit sides
+const (
+	SideBlue = "blue"
+	SideRed  = "red"
+)
+
+// Snapshot is the full state a spectator renders
+type Snapshot struct {
+	Simulation      string    `json:"simulation"`
+	RunID           string    `json:"run_id"`
+	Status          string    `json:"status"`
+	Outcome         string    `json:"outcome,omitempty"`
+	Started         time.Time `json:"started"`
+	ElapsedSeconds  float64   `json:"elapsed_s"`
+	DurationSeconds float64   `json:"duration_s,omitempty"` // Planned run length, when known
+	Origin          Point     `json:"origin"`
+	RadiusKm        float64   `json:"radius_km"`
+	Counters        []Counter `json:"counters"`
+	Units           []Unit    `json:"units"`
+	Events          []Event   `json:"events"`
+}
+
+// Point is a geodetic position
+type Point struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+	Alt float64 `json:"alt,omitempty"`
+}
+
+// Counter is a named scoreboard value
+type Counter struct {
+	Name  string  `json:"name"`
+	Value float64 `json:"value"`
+}
+
+// Unit is an entity shown on the map
+type Unit struct {
+	ID     string `json:"id"`
+	Name   string `json:"name"`
+	Side   string `json:"side"`
+	Kind   string `json:"kind"`
+	Status string `json:"status"`
+	Point
+	Detail string `json:"detail,omitempty"`
+}
+
+// Event is a recent log line
+type Event struct {
+	Time     time.Time `json:"time"`
+	Type     string    `json:"type"`
+	Severity string    `json:"severity"`
+	Message  string    `json:"message"`
+}
+
+// Broadcaster holds the latest snapshot and fans it out to stream subscribers
+type Broadcaster struct {
+	latest      []byte
+	subscribers map[chan []byte]struct{}
+	mu          sync.Mutex
+}
+
+// NewBroadcaster creates an empty broadcaster
+func NewBroadcaster() *Broadcaster {
+	return &Broadcaster{subscribers: make(map[chan []byte]struct{})}
+}
+
+// Publish replaces the latest snapshot and pushes it to subscribers. Slow subscribers
+// skip intermediate snapshots rather than blocking the simulation.
+func (b *Broadcaster) Publish(snapshot Snapshot) {
+	data, err := json.Marshal(snapshot)
+	if err != nil {
+		logger.Debugf("Failed to marshal spectator snapshot: %v", err)
+		return
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.latest = data
+	for ch := range b.subscribers {
+		select {
+		case <-ch: // Drop the stale snapshot
+		default:
+		}
+		ch <- data
+	}
+}
+
+// Subscribers returns the number of connected stream clients
+func (b *Broadcaster) Subscribers() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+func (b *Broadcaster) subscribe() (chan []byte, []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch := make(chan []byte, 1)
+	b.subscribers[ch] = struct{}{}
+	return ch, b.latest
+}
+
+func (b *Broadcaster) unsubscribe(ch chan []byte) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscribers, ch)
+}
+
+// Handler serves the snapshot and stream endpoints. Only GET is accepted.
+func (b *Broadcaster) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/snapshot", func(w http.ResponseWriter, _ *http.Request) {
+		b.mu.Lock()
+		data := b.latest
+		b.mu.Unlock()
+		if data == nil {
+			http.Error(w, "no snapshot yet", http.StatusServiceUnavailable)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write(data)
+	})
+	mux.HandleFunc("GET /v1/stream", b.serveStream)
+	return mux
+}
+
+func (b *Broadcaster) serveStream(w http.ResponseWriter, r *http.Request) {
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	ch, latest := b.subscribe()
+	defer b.unsubscribe(ch)
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	write := func(data []byte) bool {
+		if _, err := fmt.Fprintf(w, "event: snapshot\ndata: %s\n\n", data); err != nil {
+			return false
+		}
+		flusher.Flush()
+		return true
+	}
+
+	if latest != nil && !write(latest) {
+		return
+	}
+
+	keepAlive := time.NewTicker(15 * time.Second)
+	defer keepAlive.Stop()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case data := <-ch:
+			if !write(data) {
+				return
+			}
+		case <-keepAlive.C:
+			if _, err := fmt.Fprint(w, ": keep-alive\n\n"); err != nil {
+				return
+			}
+			flusher.Flush()
+		}
+	}
+}
+
+// Serve starts the spectator endpoint in the background. Shut it down with the returned server.
+func (b *Broadcaster) Serve(addr string) *http.Server {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           b.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("Spectator endpoint stopped: %v", err)
+		}
+	}()
+	logger.Infof("%s Spectators can watch with: legion-sim spectate http://<host>%s", logger.IconNetwork, addr)
+	return server
+}
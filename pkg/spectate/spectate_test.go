@@ -0,0 +1,86 @@
+package spectate
+
+import (
+	"context"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamDeliversPublishedSnapshots(t *testing.T) {
+	b := NewBroadcaster()
+	b.Publish(Snapshot{Simulation: "Test", Status: StatusRunning})
+
+	server := httptest.NewServer(b.Handler())
+	defer server.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	var received []Snapshot
+	err := Stream(ctx, server.URL, func(s Snapshot) bool {
+		received = append(received, s)
+		if len(received) == 1 {
+			// Wait for the stream to register before publishing the next snapshot
+			for b.Subscribers() == 0 {
+				time.Sleep(time.Millisecond)
+			}
+			b.Publish(Snapshot{Simulation: "Test", Status: StatusComplete})
+		}
+		return s.Status != StatusComplete
+	})
+	if err != nil {
+		t.Fatalf("Stream: %v", err)
+	}
+	if len(received) != 2 || received[0].Status != StatusRunning || received[1].Status != StatusComplete {
+		t.Fatalf("unexpected snapshots: %+v", received)
+	}
+}
+
+func TestRenderMapPlacesUnits(t *testing.T) {
+	snapshot := Snapshot{
+		Origin:   Point{Lat: 34.0, Lon: -118.0},
+		RadiusKm: 10,
+		Units: []Unit{
+			{Side: SideBlue, Point: Point{Lat: 34.0, Lon: -118.0}},            // On the origin
+			{Side: SideRed, Point: Point{Lat: 34.0 + 10/110.57, Lon: -118.0}}, // 10 km north
+		},
+	}
+
+	rows := RenderMap(snapshot, 11, 11)
+	if rows[5][5] != byte(glyphBlue) {
+		t.Errorf("expected blue unit at center, got row %q", rows[5])
+	}
+	if rows[0][5] != byte(glyphRed) {
+		t.Errorf("expected red unit at top center, got row %q", rows[0])
+	}
+	if strings.Count(strings.Join(rows, ""), string(glyphRed)) != 1 {
+		t.Errorf("expected exactly one red glyph")
+	}
+}
+
+func TestStatusCountsAndClock(t *testing.T) {
+	units := []Unit{
+		{Side: SideBlue, Status: "TRACKING"},
+		{Side: SideBlue, Status: "IDLE"},
+		{Side: SideBlue, Status: "TRACKING"},
+		{Side: SideRed, Status: "HOSTILE"},
+	}
+	if got := StatusCounts(units, SideBlue); got != "2 TRACKING, 1 IDLE" {
+		t.Errorf("blue counts = %q", got)
+	}
+	if got := StatusCounts(nil, SideRed); got != "none" {
+		t.Errorf("empty counts = %q", got)
+	}
+
+	if got := formatClock(Snapshot{ElapsedSeconds: 90}); got != "T+1m30s" {
+		t.Errorf("clock without duration = %q", got)
+	}
+	if got := formatClock(Snapshot{ElapsedSeconds: 90, DurationSeconds: 300}); got != "T+1m30s / 5m0s (3m30s left)" {
+		t.Errorf("clock with duration = %q", got)
+	}
+	if got := formatClock(Snapshot{ElapsedSeconds: 310, DurationSeconds: 300}); got != "T+5m10s / 5m0s (0s left)" {
+		t.Errorf("overrun clock = %q", got)
+	}
+}
@@ -0,0 +1,287 @@
+package tiles
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// Tiling settings
+const (
+	Extent       = 4096 // Tile coordinate range
+	MaxZoom      = 22
+	buffer       = 64       // Extent units drawn past each tile edge so geometry doesn't clip at the seams
+	heatmapCells = 64       // Heatmap cells across a tile
+	maxLatitude  = 85.05113 // Web Mercator's limit
+)
+
+// indexed is a feature projected to Web Mercator, where the world spans 0..1 on both axes
+type indexed struct {
+	Feature
+	points                 [][2]float64
+	minX, minY, maxX, maxY float64
+}
+
+// Recording is a loaded run, ready to be cut into tiles
+type Recording struct {
+	Name   string
+	layers map[string][]indexed
+	bounds [4]float64 // West, south, east, north
+}
+
+// NewRecording indexes features for tiling
+func NewRecording(features []Feature) *Recording {
+	r := &Recording{layers: make(map[string][]indexed)}
+	r.bounds = [4]float64{180, 90, -180, -90}
+	for _, f := range features {
+		entry := indexed{Feature: f, minX: 1, minY: 1}
+		for _, c := range f.Coordinates {
+			x, y := project(c[0], c[1])
+			entry.points = append(entry.points, [2]float64{x, y})
+			entry.minX, entry.maxX = math.Min(entry.minX, x), math.Max(entry.maxX, x)
+			entry.minY, entry.maxY = math.Min(entry.minY, y), math.Max(entry.maxY, y)
+
+			r.bounds[0], r.bounds[2] = math.Min(r.bounds[0], c[0]), math.Max(r.bounds[2], c[0])
+			r.bounds[1], r.bounds[3] = math.Min(r.bounds[1], c[1]), math.Max(r.bounds[3], c[1])
+		}
+		r.layers[f.Layer] = append(r.layers[f.Layer], entry)
+	}
+	return r
+}
+
+// Bounds returns the recording's extent as west, south, east, north
+func (r *Recording) Bounds() [4]float64 {
+	return r.bounds
+}
+
+// Layers returns the recorded layer names in tile order, followed by the heatmap when
+// there are point features to bin
+func (r *Recording) Layers() []string {
+	names := make([]string, 0, len(r.layers)+1)
+	for name := range r.layers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	if len(r.pointLayers()) > 0 {
+		names = append(names, LayerHeatmap)
+	}
+	return names
+}
+
+// Fields returns each layer's property names with their TileJSON types
+func (r *Recording) Fields() map[string]map[string]string {
+	fields := make(map[string]map[string]string, len(r.layers)+1)
+	for name, features := range r.layers {
+		fields[name] = make(map[string]string)
+		for _, f := range features {
+			for key, value := range f.Properties {
+				switch value.(type) {
+				case float64, int, int64:
+					fields[name][key] = "Number"
+				case bool:
+					fields[name][key] = "Boolean"
+				default:
+					fields[name][key] = "String"
+				}
+			}
+		}
+	}
+	if points := r.pointLayers(); len(points) > 0 {
+		fields[LayerHeatmap] = map[string]string{"count": "Number"}
+		for _, name := range points {
+			fields[LayerHeatmap][name] = "Number"
+		}
+	}
+	return fields
+}
+
+// pointLayers returns the layers with point features, which feed the heatmap
+func (r *Recording) pointLayers() []string {
+	var names []string
+	for name, features := range r.layers {
+		for _, f := range features {
+			if f.Type == Point {
+				names = append(names, name)
+				break
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Tile encodes tile z/x/y as a Mapbox Vector Tile. Tiles the run doesn't reach are empty.
+func (r *Recording) Tile(z, x, y int) ([]byte, error) {
+	if z < 0 || z > MaxZoom || x < 0 || y < 0 || x >= 1<<z || y >= 1<<z {
+		return nil, fmt.Errorf("tile %d/%d/%d is out of range", z, x, y)
+	}
+	t := newTileCutter(z, x, y)
+
+	var out []byte
+	for _, name := range r.Layers() {
+		var layer *layerBuilder
+		if name == LayerHeatmap {
+			layer = r.heatmap(t)
+		} else {
+			layer = newLayerBuilder(name)
+			for _, f := range r.layers[name] {
+				t.cut(layer, f)
+			}
+		}
+		out = layer.appendTo(out)
+	}
+	return out, nil
+}
+
+// heatmap bins the point features inside the tile
+func (r *Recording) heatmap(t *tileCutter) *layerBuilder {
+	type cell struct{ x, y int }
+	counts := make(map[cell]map[string]int)
+	for _, name := range r.pointLayers() {
+		for _, f := range r.layers[name] {
+			if f.Type != Point {
+				continue
+			}
+			for _, p := range f.points {
+				// Bin only within the tile itself so neighbours don't count a point twice
+				tx, ty := (p[0]*t.n-t.x)*heatmapCells, (p[1]*t.n-t.y)*heatmapCells
+				if tx < 0 || ty < 0 || tx >= heatmapCells || ty >= heatmapCells {
+					continue
+				}
+				c := cell{int(tx), int(ty)}
+				if counts[c] == nil {
+					counts[c] = make(map[string]int)
+				}
+				counts[c][name]++
+				counts[c]["count"]++
+			}
+		}
+	}
+
+	cells := make([]cell, 0, len(counts))
+	for c := range counts {
+		cells = append(cells, c)
+	}
+	sort.Slice(cells, func(i, j int) bool {
+		if cells[i].y != cells[j].y {
+			return cells[i].y < cells[j].y
+		}
+		return cells[i].x < cells[j].x
+	})
+
+	layer := newLayerBuilder(LayerHeatmap)
+	const size = Extent / heatmapCells
+	for _, c := range cells {
+		props := make(map[string]interface{}, len(counts[c]))
+		for key, count := range counts[c] {
+			props[key] = count
+		}
+		center := [2]int32{int32(c.x*size + size/2), int32(c.y*size + size/2)}
+		layer.add(geomPoint, encodePoints([][2]int32{center}), props)
+	}
+	return layer
+}
+
+// tileCutter converts projected geometry into one tile's coordinates
+type tileCutter struct {
+	n, x, y                float64
+	minX, minY, maxX, maxY float64 // Buffered tile bounds in Web Mercator
+}
+
+func newTileCutter(z, x, y int) *tileCutter {
+	n := float64(uint(1) << z)
+	pad := float64(buffer) / Extent / n
+	return &tileCutter{
+		n: n, x: float64(x), y: float64(y),
+		minX: float64(x)/n - pad, minY: float64(y)/n - pad,
+		maxX: float64(x+1)/n + pad, maxY: float64(y+1)/n + pad,
+	}
+}
+
+// overlaps reports whether a projected bounding box touches the buffered tile
+func (t *tileCutter) overlaps(minX, minY, maxX, maxY float64) bool {
+	return maxX >= t.minX && minX <= t.maxX && maxY >= t.minY && minY <= t.maxY
+}
+
+// quantize converts a projected point to tile coordinates
+func (t *tileCutter) quantize(p [2]float64) [2]int32 {
+	return [2]int32{
+		int32(math.Round((p[0]*t.n - t.x) * Extent)),
+		int32(math.Round((p[1]*t.n - t.y) * Extent)),
+	}
+}
+
+// cut adds the part of f inside the buffered tile to layer. Lines are split where they
+// leave the tile; polygons are kept whole since the envelopes recorded are small.
+func (t *tileCutter) cut(layer *layerBuilder, f indexed) {
+	if !t.overlaps(f.minX, f.minY, f.maxX, f.maxY) {
+		return
+	}
+
+	switch f.Type {
+	case Point:
+		var points [][2]int32
+		for _, p := range f.points {
+			if t.overlaps(p[0], p[1], p[0], p[1]) {
+				points = append(points, t.quantize(p))
+			}
+		}
+		if len(points) > 0 {
+			layer.add(geomPoint, encodePoints(points), f.Properties)
+		}
+
+	case LineString:
+		var parts [][][2]int32
+		var part [][2]int32
+		for i := 1; i < len(f.points); i++ {
+			a, b := f.points[i-1], f.points[i]
+			if !t.overlaps(math.Min(a[0], b[0]), math.Min(a[1], b[1]), math.Max(a[0], b[0]), math.Max(a[1], b[1])) {
+				parts, part = appendPart(parts, part), nil
+				continue
+			}
+			if len(part) == 0 {
+				part = appendDistinct(part, t.quantize(a))
+			}
+			part = appendDistinct(part, t.quantize(b))
+		}
+		if parts = appendPart(parts, part); len(parts) > 0 {
+			layer.add(geomLineString, encodeLines(parts), f.Properties)
+		}
+
+	case Polygon:
+		var ring [][2]int32
+		for _, p := range f.points {
+			ring = appendDistinct(ring, t.quantize(p))
+		}
+		if len(ring) > 1 && ring[0] == ring[len(ring)-1] {
+			ring = ring[:len(ring)-1]
+		}
+		if len(ring) < 3 {
+			return // Smaller than a tile unit at this zoom
+		}
+		layer.add(geomPolygon, encodeRing(ring), f.Properties)
+	}
+}
+
+// appendDistinct appends p unless it repeats the last point
+func appendDistinct(points [][2]int32, p [2]int32) [][2]int32 {
+	if len(points) > 0 && points[len(points)-1] == p {
+		return points
+	}
+	return append(points, p)
+}
+
+// appendPart keeps a line part that still has length after quantizing
+func appendPart(parts [][][2]int32, part [][2]int32) [][][2]int32 {
+	if len(part) >= 2 {
+		return append(parts, part)
+	}
+	return parts
+}
+
+// project converts WGS84 degrees to Web Mercator, with the world spanning 0..1
+func project(lon, lat float64) (float64, float64) {
+	lat = math.Max(math.Min(lat, maxLatitude), -maxLatitude)
+	sin := math.Sin(lat * math.Pi / 180)
+	return (lon + 180) / 360, 0.5 - math.Log((1+sin)/(1-sin))/(4*math.Pi)
+}
@@ -0,0 +1,238 @@
+package tiles
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"math"
+	"sort"
+)
+
+// Mapbox Vector Tile 2.1 encoding. The protobuf messages are small enough to write by hand:
+//
+//	Tile    { repeated Layer layers = 3; }
+//	Layer   { uint32 version = 15; string name = 1; repeated Feature features = 2;
+//	          repeated string keys = 3; repeated Value values = 4; uint32 extent = 5; }
+//	Feature { repeated uint32 tags = 2 [packed]; GeomType type = 3; repeated uint32 geometry = 4 [packed]; }
+//	Value   { string string_value = 1; double double_value = 3; uint64 uint_value = 5;
+//	          sint64 sint_value = 6; bool bool_value = 7; }
+
+// Geometry types
+const (
+	geomPoint      = 1
+	geomLineString = 2
+	geomPolygon    = 3
+)
+
+// Geometry commands
+const (
+	cmdMoveTo    = 1
+	cmdLineTo    = 2
+	cmdClosePath = 7
+)
+
+// Protobuf wire types
+const (
+	wireVarint  = 0
+	wireFixed64 = 1
+	wireBytes   = 2
+)
+
+// layerBuilder collects one layer's features and its shared key and value tables
+type layerBuilder struct {
+	name     string
+	features [][]byte
+	keys     []string
+	keyIndex map[string]uint32
+	values   [][]byte
+	valIndex map[string]uint32
+}
+
+func newLayerBuilder(name string) *layerBuilder {
+	return &layerBuilder{name: name, keyIndex: make(map[string]uint32), valIndex: make(map[string]uint32)}
+}
+
+// add appends a feature with encoded geometry
+func (l *layerBuilder) add(geomType uint64, geometry []uint32, props map[string]interface{}) {
+	keys := make([]string, 0, len(props))
+	for key := range props {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	tags := make([]uint32, 0, 2*len(keys))
+	for _, key := range keys {
+		value := encodeValue(props[key])
+		if value == nil {
+			continue
+		}
+		k, ok := l.keyIndex[key]
+		if !ok {
+			k = uint32(len(l.keys))
+			l.keyIndex[key] = k
+			l.keys = append(l.keys, key)
+		}
+		v, ok := l.valIndex[string(value)]
+		if !ok {
+			v = uint32(len(l.values))
+			l.valIndex[string(value)] = v
+			l.values = append(l.values, value)
+		}
+		tags = append(tags, k, v)
+	}
+
+	var feature []byte
+	feature = appendPacked(feature, 2, tags)
+	feature = appendVarintField(feature, 3, geomType)
+	feature = appendPacked(feature, 4, geometry)
+	l.features = append(l.features, feature)
+}
+
+// appendTo appends the layer to a tile, leaving empty layers out
+func (l *layerBuilder) appendTo(tile []byte) []byte {
+	if len(l.features) == 0 {
+		return tile
+	}
+
+	var layer []byte
+	layer = appendVarintField(layer, 15, 2)
+	layer = appendBytesField(layer, 1, []byte(l.name))
+	for _, feature := range l.features {
+		layer = appendBytesField(layer, 2, feature)
+	}
+	for _, key := range l.keys {
+		layer = appendBytesField(layer, 3, []byte(key))
+	}
+	for _, value := range l.values {
+		layer = appendBytesField(layer, 4, value)
+	}
+	layer = appendVarintField(layer, 5, Extent)
+	return appendBytesField(tile, 3, layer)
+}
+
+// encodeValue encodes a property as a Value message, or nil for a null property.
+// Whole numbers are written as integers so they filter cleanly in QGIS.
+func encodeValue(v interface{}) []byte {
+	switch v := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return appendBytesField(nil, 1, []byte(v))
+	case bool:
+		b := uint64(0)
+		if v {
+			b = 1
+		}
+		return appendVarintField(nil, 7, b)
+	case int:
+		return encodeValue(float64(v))
+	case int64:
+		return encodeValue(float64(v))
+	case float64:
+		if v == math.Trunc(v) && math.Abs(v) < 1<<53 {
+			if v >= 0 {
+				return appendVarintField(nil, 5, uint64(v))
+			}
+			n := int64(v)
+			return appendVarintField(nil, 6, uint64((n<<1)^(n>>63)))
+		}
+		b := appendTag(nil, 3, wireFixed64)
+		return binary.LittleEndian.AppendUint64(b, math.Float64bits(v))
+	default:
+		// Nested values are flattened to JSON text
+		data, err := json.Marshal(v)
+		if err != nil {
+			return nil
+		}
+		return appendBytesField(nil, 1, data)
+	}
+}
+
+// geometryWriter encodes commands with coordinates relative to a running cursor
+type geometryWriter struct {
+	out    []uint32
+	cursor [2]int32
+}
+
+func (g *geometryWriter) command(id, count int) {
+	g.out = append(g.out, uint32(id&0x7|count<<3))
+}
+
+func (g *geometryWriter) points(points [][2]int32) {
+	for _, p := range points {
+		g.out = append(g.out, zigzag(p[0]-g.cursor[0]), zigzag(p[1]-g.cursor[1]))
+		g.cursor = p
+	}
+}
+
+// encodePoints encodes one or more points
+func encodePoints(points [][2]int32) []uint32 {
+	var g geometryWriter
+	g.command(cmdMoveTo, len(points))
+	g.points(points)
+	return g.out
+}
+
+// encodeLines encodes one or more lines of at least two points each
+func encodeLines(lines [][][2]int32) []uint32 {
+	var g geometryWriter
+	for _, line := range lines {
+		g.command(cmdMoveTo, 1)
+		g.points(line[:1])
+		g.command(cmdLineTo, len(line)-1)
+		g.points(line[1:])
+	}
+	return g.out
+}
+
+// encodeRing encodes a polygon's exterior ring, given without its closing point. Exterior
+// rings wind clockwise in tile coordinates, where y points down.
+func encodeRing(ring [][2]int32) []uint32 {
+	area := 0.0
+	for i := range ring {
+		a, b := ring[i], ring[(i+1)%len(ring)]
+		area += float64(a[0])*float64(b[1]) - float64(b[0])*float64(a[1])
+	}
+	if area < 0 {
+		reversed := make([][2]int32, len(ring))
+		for i, p := range ring {
+			reversed[len(ring)-1-i] = p
+		}
+		ring = reversed
+	}
+
+	var g geometryWriter
+	g.command(cmdMoveTo, 1)
+	g.points(ring[:1])
+	g.command(cmdLineTo, len(ring)-1)
+	g.points(ring[1:])
+	g.command(cmdClosePath, 1)
+	return g.out
+}
+
+func zigzag(n int32) uint32 {
+	return uint32((n << 1) ^ (n >> 31))
+}
+
+func appendTag(b []byte, field, wire int) []byte {
+	return binary.AppendUvarint(b, uint64(field<<3|wire))
+}
+
+func appendVarintField(b []byte, field int, v uint64) []byte {
+	return binary.AppendUvarint(appendTag(b, field, wireVarint), v)
+}
+
+func appendBytesField(b []byte, field int, data []byte) []byte {
+	b = binary.AppendUvarint(appendTag(b, field, wireBytes), uint64(len(data)))
+	return append(b, data...)
+}
+
+func appendPacked(b []byte, field int, values []uint32) []byte {
+	if len(values) == 0 {
+		return b
+	}
+	var packed []byte
+	for _, v := range values {
+		packed = binary.AppendUvarint(packed, uint64(v))
+	}
+	return appendBytesField(b, field, packed)
+}
@@ -0,0 +1,147 @@
+// Package tiles serves a recorded run as Mapbox vector tiles, so analysts can explore
+// large runs in QGIS or kepler.gl without exporting the whole run as GeoJSON.
+//
+// A simulation writes its geometry to a recording (gzipped JSON lines, one Feature per
+// line) as it runs. The server loads a recording and cuts tiles on request:
+//
+//	GET /tilejson.json           TileJSON describing the layers and the run's bounds
+//	GET /tiles/{z}/{x}/{y}.mvt   A vector tile
+//
+// Every recorded layer is served as-is, plus a heatmap layer binning the recording's
+// point features into a grid sized to the zoom level.
+package tiles
+
+import (
+	"bufio"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Layers written by the drone swarm simulation
+const (
+	LayerTracks      = "tracks"      // Flight paths
+	LayerEngagements = "engagements" // Where shots were taken
+	LayerEvents      = "events"      // Detections and leakers
+	LayerCoverage    = "coverage"    // Sensor and weapon envelopes
+	LayerHeatmap     = "heatmap"     // Derived by the server from point features
+)
+
+// Geometry types
+const (
+	Point      = "Point"
+	LineString = "LineString"
+	Polygon    = "Polygon" // A single closed ring
+)
+
+// Feature is one recorded geometry. Coordinates are [lon, lat] in WGS84 degrees.
+type Feature struct {
+	Layer       string                 `json:"layer"`
+	Type        string                 `json:"type"`
+	Coordinates [][2]float64           `json:"coordinates"`
+	Properties  map[string]interface{} `json:"properties,omitempty"`
+}
+
+// Writer writes features to a recording
+type Writer struct {
+	file *os.File
+	gz   *gzip.Writer
+	buf  *bufio.Writer
+	enc  *json.Encoder
+}
+
+// Create starts a recording at path, gzipped when path ends in .gz
+func Create(path string) (*Writer, error) {
+	file, err := os.Create(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create recording: %w", err)
+	}
+
+	w := &Writer{file: file}
+	var out io.Writer = file
+	if strings.HasSuffix(path, ".gz") {
+		w.gz = gzip.NewWriter(file)
+		out = w.gz
+	}
+	w.buf = bufio.NewWriter(out)
+	w.enc = json.NewEncoder(w.buf)
+	return w, nil
+}
+
+// Write appends a feature
+func (w *Writer) Write(f Feature) error {
+	if err := checkFeature(f); err != nil {
+		return err
+	}
+	return w.enc.Encode(f)
+}
+
+// Close flushes and closes the recording
+func (w *Writer) Close() error {
+	err := w.buf.Flush()
+	if w.gz != nil {
+		err = errors.Join(err, w.gz.Close())
+	}
+	return errors.Join(err, w.file.Close())
+}
+
+// Load reads a recording written by Writer, gzipped or not
+func Load(path string) (*Recording, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open recording: %w", err)
+	}
+	defer file.Close()
+
+	reader := bufio.NewReader(file)
+	var in io.Reader = reader
+	if magic, err := reader.Peek(2); err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gz, err := gzip.NewReader(reader)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read recording %s: %w", path, err)
+		}
+		defer gz.Close()
+		in = gz
+	}
+
+	var features []Feature
+	dec := json.NewDecoder(in)
+	for line := 1; ; line++ {
+		var f Feature
+		if err := dec.Decode(&f); errors.Is(err, io.EOF) {
+			break
+		} else if err != nil {
+			return nil, fmt.Errorf("recording %s, feature %d: %w", path, line, err)
+		}
+		if err := checkFeature(f); err != nil {
+			return nil, fmt.Errorf("recording %s, feature %d: %w", path, line, err)
+		}
+		features = append(features, f)
+	}
+	if len(features) == 0 {
+		return nil, fmt.Errorf("recording %s has no features", path)
+	}
+	rec := NewRecording(features)
+	rec.Name = strings.TrimSuffix(strings.TrimSuffix(filepath.Base(path), ".gz"), ".jsonl")
+	return rec, nil
+}
+
+// checkFeature rejects features that can't be tiled
+func checkFeature(f Feature) error {
+	if f.Layer == "" || f.Layer == LayerHeatmap {
+		return fmt.Errorf("feature has an invalid layer %q", f.Layer)
+	}
+	minPoints := map[string]int{Point: 1, LineString: 2, Polygon: 3}[f.Type]
+	if minPoints == 0 {
+		return fmt.Errorf("%s feature has an unknown type %q", f.Layer, f.Type)
+	}
+	if len(f.Coordinates) < minPoints {
+		return fmt.Errorf("%s %s needs at least %d coordinates", f.Layer, f.Type, minPoints)
+	}
+	return nil
+}
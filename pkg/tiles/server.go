@@ -0,0 +1,113 @@
+package tiles
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// DefaultListenAddr is where the tile server listens unless told otherwise
+const DefaultListenAddr = ":7800"
+
+// Handler serves rec's tiles and TileJSON. Responses allow any origin so browser tools
+// such as kepler.gl can load them.
+func Handler(rec *Recording) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /tilejson.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(rec.tileJSON(baseURL(r)))
+	})
+	mux.HandleFunc("GET /tiles/{z}/{x}/{y}", func(w http.ResponseWriter, r *http.Request) {
+		z, errZ := strconv.Atoi(r.PathValue("z"))
+		x, errX := strconv.Atoi(r.PathValue("x"))
+		y, errY := strconv.Atoi(strings.TrimSuffix(strings.TrimSuffix(r.PathValue("y"), ".mvt"), ".pbf"))
+		if err := errors.Join(errZ, errX, errY); err != nil {
+			http.Error(w, "expected /tiles/{z}/{x}/{y}.mvt", http.StatusBadRequest)
+			return
+		}
+		tile, err := rec.Tile(z, x, y)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.Header().Set("Content-Type", "application/vnd.mapbox-vector-tile")
+		w.Header().Set("Cache-Control", "public, max-age=86400") // Recordings don't change
+		_, _ = w.Write(tile)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Access-Control-Allow-Origin", "*")
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// Serve serves rec at addr until the returned server is shut down
+func Serve(rec *Recording, addr string) *http.Server {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           Handler(rec),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("Tile server stopped: %v", err)
+		}
+	}()
+	logger.Infof("%s Serving %s at http://<host>%s/tilejson.json", logger.IconNetwork, rec.Name, addr)
+	return server
+}
+
+// baseURL is the address the client reached us on, so tile URLs work through proxies
+func baseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto != "" {
+		scheme = proto
+	}
+	return fmt.Sprintf("%s://%s", scheme, r.Host)
+}
+
+// vectorLayer describes a layer in TileJSON
+type vectorLayer struct {
+	ID      string            `json:"id"`
+	Fields  map[string]string `json:"fields"`
+	MinZoom int               `json:"minzoom"`
+	MaxZoom int               `json:"maxzoom"`
+}
+
+// tileJSON describes the recording in TileJSON 3.0
+func (r *Recording) tileJSON(base string) map[string]interface{} {
+	fields := r.Fields()
+	layers := make([]vectorLayer, 0, len(fields))
+	for _, name := range r.Layers() {
+		layers = append(layers, vectorLayer{ID: name, Fields: fields[name], MaxZoom: MaxZoom})
+	}
+
+	b := r.bounds
+	span := math.Max(b[2]-b[0], b[3]-b[1])
+	zoom := 14.0
+	if span > 0 {
+		zoom = math.Max(0, math.Min(zoom, math.Floor(math.Log2(360/span))))
+	}
+
+	return map[string]interface{}{
+		"tilejson":      "3.0.0",
+		"name":          r.Name,
+		"scheme":        "xyz",
+		"tiles":         []string{base + "/tiles/{z}/{x}/{y}.mvt"},
+		"minzoom":       0,
+		"maxzoom":       MaxZoom,
+		"bounds":        b,
+		"center":        []float64{(b[0] + b[2]) / 2, (b[1] + b[3]) / 2, zoom},
+		"vector_layers": layers,
+	}
+}
@@ -0,0 +1,170 @@
+package tiles
+
+import (
+	"encoding/binary"
+	"encoding/json"
+	"net/http/httptest"
+	"path/filepath"
+	"slices"
+	"testing"
+)
+
+func TestGeometryEncodingMatchesSpec(t *testing.T) {
+	// Examples from the Mapbox Vector Tile 2.1 specification, section 4.3.5
+	if got := encodePoints([][2]int32{{25, 17}}); !slices.Equal(got, []uint32{9, 50, 34}) {
+		t.Errorf("point: got %v", got)
+	}
+	if got := encodeLines([][][2]int32{{{2, 2}, {2, 10}, {10, 10}}}); !slices.Equal(got, []uint32{9, 4, 4, 18, 0, 16, 16, 0}) {
+		t.Errorf("linestring: got %v", got)
+	}
+	polygon := []uint32{9, 6, 12, 18, 10, 12, 24, 44, 15}
+	if got := encodeRing([][2]int32{{3, 6}, {8, 12}, {20, 34}}); !slices.Equal(got, polygon) {
+		t.Errorf("polygon: got %v", got)
+	}
+	// A ring wound the other way is reversed into an exterior ring
+	if got := encodeRing([][2]int32{{20, 34}, {8, 12}, {3, 6}}); !slices.Equal(got, polygon) {
+		t.Errorf("reversed polygon: got %v", got)
+	}
+}
+
+// decodedLayer is a layer read back from a tile
+type decodedLayer struct {
+	features int
+	keys     []string
+}
+
+// decodeTile reads the layer names, feature counts and keys from an encoded tile
+func decodeTile(t *testing.T, tile []byte) map[string]decodedLayer {
+	t.Helper()
+	layers := make(map[string]decodedLayer)
+	for _, layer := range fields(t, tile)[3] {
+		var decoded decodedLayer
+		msg := fields(t, layer)
+		decoded.features = len(msg[2])
+		for _, key := range msg[3] {
+			decoded.keys = append(decoded.keys, string(key))
+		}
+		layers[string(msg[1][0])] = decoded
+	}
+	return layers
+}
+
+// fields splits a message into its length-delimited fields, skipping varints
+func fields(t *testing.T, msg []byte) map[int][][]byte {
+	t.Helper()
+	out := make(map[int][][]byte)
+	for len(msg) > 0 {
+		tag, n := binary.Uvarint(msg)
+		msg = msg[n:]
+		switch tag & 0x7 {
+		case wireVarint:
+			_, n = binary.Uvarint(msg)
+			msg = msg[n:]
+		case wireBytes:
+			size, n := binary.Uvarint(msg)
+			out[int(tag>>3)] = append(out[int(tag>>3)], msg[n:n+int(size)])
+			msg = msg[n+int(size):]
+		default:
+			t.Fatalf("unexpected wire type %d", tag&0x7)
+		}
+	}
+	return out
+}
+
+func TestRecordingTiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Recording_test.jsonl.gz")
+	w, err := Create(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []Feature{
+		{Layer: LayerTracks, Type: LineString, Coordinates: [][2]float64{{-77.05, 38.90}, {-77.00, 38.90}, {-77.00, 38.95}},
+			Properties: map[string]interface{}{"track": "TK-1001", "wave": 1}},
+		{Layer: LayerEngagements, Type: Point, Coordinates: [][2]float64{{-77.00, 38.92}}, Properties: map[string]interface{}{"hit": true}},
+		{Layer: LayerEngagements, Type: Point, Coordinates: [][2]float64{{-77.00, 38.92}}, Properties: map[string]interface{}{"hit": false}},
+		{Layer: LayerCoverage, Type: Polygon, Coordinates: [][2]float64{{-77.01, 38.91}, {-76.99, 38.91}, {-77.00, 38.93}, {-77.01, 38.91}}},
+	} {
+		if err := w.Write(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := w.Write(Feature{Layer: LayerTracks, Type: LineString, Coordinates: [][2]float64{{0, 0}}}); err == nil {
+		t.Error("expected a one-point line to be rejected")
+	}
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	rec, err := Load(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rec.Name != "Recording_test" {
+		t.Errorf("expected the recording to be named after its file, got %q", rec.Name)
+	}
+	if want := []string{LayerCoverage, LayerEngagements, LayerTracks, LayerHeatmap}; !slices.Equal(rec.Layers(), want) {
+		t.Errorf("expected layers %v, got %v", want, rec.Layers())
+	}
+
+	// The whole run sits in one tile at zoom 12
+	tile, err := rec.Tile(12, 1171, 1566)
+	if err != nil {
+		t.Fatal(err)
+	}
+	layers := decodeTile(t, tile)
+	for name, want := range map[string]int{LayerTracks: 1, LayerEngagements: 2, LayerCoverage: 1, LayerHeatmap: 1} {
+		if layers[name].features != want {
+			t.Errorf("%s: expected %d features, got %d", name, want, layers[name].features)
+		}
+	}
+	if keys := layers[LayerHeatmap].keys; !slices.Equal(keys, []string{"count", LayerEngagements}) {
+		t.Errorf("expected heatmap cells to count by layer, got keys %v", keys)
+	}
+
+	// Neighbouring tiles outside the buffer are empty
+	if tile, _ := rec.Tile(12, 1160, 1566); len(tile) != 0 {
+		t.Errorf("expected an empty tile away from the run, got %d bytes", len(tile))
+	}
+	if _, err := rec.Tile(1, 2, 0); err == nil {
+		t.Error("expected an out of range tile to be rejected")
+	}
+}
+
+func TestHandler(t *testing.T) {
+	rec := NewRecording([]Feature{{Layer: LayerEngagements, Type: Point, Coordinates: [][2]float64{{-77, 38.9}}}})
+	server := httptest.NewServer(Handler(rec))
+	defer server.Close()
+
+	resp, err := server.Client().Get(server.URL + "/tilejson.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var tj struct {
+		Tiles        []string `json:"tiles"`
+		VectorLayers []struct {
+			ID string `json:"id"`
+		} `json:"vector_layers"`
+	}
+	err = json.NewDecoder(resp.Body).Decode(&tj)
+	resp.Body.Close()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(tj.Tiles) != 1 || tj.Tiles[0] != server.URL+"/tiles/{z}/{x}/{y}.mvt" || len(tj.VectorLayers) != 2 {
+		t.Errorf("unexpected TileJSON %+v", tj)
+	}
+
+	for path, status := range map[string]int{"/tiles/0/0/0.mvt": 200, "/tiles/0/1/0.mvt": 404, "/tiles/a/0/0.mvt": 400} {
+		resp, err := server.Client().Get(server.URL + path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		resp.Body.Close()
+		if resp.StatusCode != status {
+			t.Errorf("%s: expected %d, got %d", path, status, resp.StatusCode)
+		}
+		if status == 200 && resp.Header.Get("Content-Type") != "application/vnd.mapbox-vector-tile" {
+			t.Errorf("%s: unexpected content type %q", path, resp.Header.Get("Content-Type"))
+		}
+	}
+}
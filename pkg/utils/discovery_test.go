@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestDiscoverSimulations(t *testing.T) {
+	root, err := findProjectRoot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	files, err := filepath.Glob(filepath.Join(root, "cmd", "*", "simulation.yaml"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	simInfos, err := DiscoverSimulations()
+	if err != nil {
+		t.Fatalf("DiscoverSimulations: %v", err)
+	}
+	// A simulation.yaml that fails to parse is skipped with a warning
+	if len(simInfos) != len(files) {
+		t.Fatalf("discovered %d simulations from %d simulation.yaml files", len(simInfos), len(files))
+	}
+	for _, info := range simInfos {
+		if info.Config.Name == "" || len(info.Config.Parameters) == 0 {
+			t.Errorf("%s: expected a name and parameters", info.Path)
+		}
+		// Every default must pass its own schema, or runs without prompts fail
+		for _, param := range info.Config.Parameters {
+			if param.Default == nil {
+				continue
+			}
+			if _, err := param.Resolve(param.Default); err != nil {
+				t.Errorf("%s: default for %s: %v", info.Path, param.Name, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,57 @@
+package utils
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+// LoadParameterFile reads a YAML (or JSON) file of name: value pairs and converts each value
+// to its parameter's declared type, checking its range and options. Names the simulation doesn't declare are kept as read.
+func LoadParameterFile(path string, params []simulation.Parameter) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read parameters file: %w", err)
+	}
+
+	raw := make(map[string]interface{})
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse parameters file: %w", err)
+	}
+
+	return ConvertParameters(raw, params)
+}
+
+// ConvertParameters converts decoded name: value pairs to their parameters' declared
+// types and checks them against the schema. Names the simulation doesn't declare are
+// kept as given.
+func ConvertParameters(raw map[string]interface{}, params []simulation.Parameter) (map[string]interface{}, error) {
+	declared := make(map[string]simulation.Parameter, len(params))
+	for _, param := range params {
+		declared[param.Name] = param
+	}
+
+	result := make(map[string]interface{}, len(raw))
+	for name, value := range raw {
+		param, ok := declared[name]
+		if !ok {
+			result[name] = value
+			continue
+		}
+		converted, err := param.Resolve(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s: %w", name, err)
+		}
+		result[name] = converted
+	}
+	return result, nil
+}
+
+// ParseParameterValue converts a value given on the command line to a parameter's type
+// and checks it against the parameter's range and options
+func ParseParameterValue(value string, param simulation.Parameter) (interface{}, error) {
+	return param.Resolve(value)
+}
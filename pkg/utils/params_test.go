@@ -0,0 +1,122 @@
+package utils
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/simulation"
+)
+
+func TestLoadParameterFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "params.yaml")
+	content := "num_waves: 3\ncohesion_weight: 1\nsummary_interval: 30s\ntrack_gc_grace: 45\nverify_legion: \"true\"\nlaunch_sites: \"\"\ncustom: x\n"
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	params, err := LoadParameterFile(path, []simulation.Parameter{
+		{Name: "num_waves", Type: "integer"},
+		{Name: "cohesion_weight", Type: "float"},
+		{Name: "summary_interval", Type: "duration"},
+		{Name: "track_gc_grace", Type: "duration"},
+		{Name: "verify_legion", Type: "boolean"},
+		{Name: "launch_sites", Type: "string"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := map[string]interface{}{
+		"num_waves":        3,
+		"cohesion_weight":  1.0,
+		"summary_interval": 30 * time.Second,
+		"track_gc_grace":   45 * time.Second,
+		"verify_legion":    true,
+		"launch_sites":     "",
+		"custom":           "x",
+	}
+	for name, expected := range want {
+		if params[name] != expected {
+			t.Errorf("%s: expected %v (%T), got %v (%T)", name, expected, expected, params[name], params[name])
+		}
+	}
+
+	if err := os.WriteFile(path, []byte("num_waves: 2.5\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := LoadParameterFile(path, []simulation.Parameter{{Name: "num_waves", Type: "integer"}}); err == nil {
+		t.Error("expected an error for a fractional integer")
+	}
+}
+
+func TestLoadParameterFileJSON(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "params.json")
+	if err := os.WriteFile(path, []byte(`{"num_waves": 3, "duration": "2m", "verify_legion": true}`), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	params, err := LoadParameterFile(path, []simulation.Parameter{
+		{Name: "num_waves", Type: "integer"},
+		{Name: "duration", Type: "duration"},
+		{Name: "verify_legion", Type: "boolean"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params["num_waves"] != 3 || params["duration"] != 2*time.Minute || params["verify_legion"] != true {
+		t.Errorf("unexpected parameters: %v", params)
+	}
+}
+
+func TestParametersWithoutPrompts(t *testing.T) {
+	t.Setenv("LEGION_NUM_WAVES", "7")
+	params, err := ParametersWithoutPrompts([]simulation.Parameter{
+		{Name: "num_waves", Type: "integer", Default: 5},
+		{Name: "duration", Type: "duration", Default: "2m"},
+		{Name: "launch_sites", Type: "string"},
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if params["num_waves"] != 7 || params["duration"] != 2*time.Minute {
+		t.Errorf("unexpected parameters: %v", params)
+	}
+	if _, ok := params["launch_sites"]; ok {
+		t.Error("optional parameter without a default should be left out")
+	}
+
+	if _, err := ParametersWithoutPrompts([]simulation.Parameter{{Name: "api_key", Type: "string", Required: true}}); err == nil {
+		t.Error("expected an error for a required parameter without a value")
+	}
+}
+
+func TestConvertParametersValidatesSchema(t *testing.T) {
+	params := []simulation.Parameter{
+		{Name: "acceptable_leakage", Type: "float", Min: 0, Max: 1.0},
+		{Name: "log_level", Type: "string", Options: []string{"debug", "info"}},
+		{Name: "warmup", Type: "duration", Min: "1s"},
+	}
+
+	converted, err := ConvertParameters(map[string]interface{}{"acceptable_leakage": 1, "log_level": "", "warmup": 2}, params)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if converted["acceptable_leakage"] != 1.0 || converted["warmup"] != 2*time.Second {
+		t.Errorf("unexpected parameters: %v", converted)
+	}
+
+	for name, value := range map[string]interface{}{
+		"acceptable_leakage": 1.5,
+		"log_level":          "trace",
+		"warmup":             "500ms",
+	} {
+		if _, err := ConvertParameters(map[string]interface{}{name: value}, params); err == nil {
+			t.Errorf("expected %s=%v to be rejected", name, value)
+		}
+	}
+	if _, err := ParseParameterValue("-0.1", params[0]); err == nil {
+		t.Error("expected --set below the minimum to be rejected")
+	}
+}
@@ -3,9 +3,7 @@ package utils
 import (
 	"fmt"
 	"os"
-	"strconv"
 	"strings"
-	"time"
 
 	"github.com/AlecAivazis/survey/v2"
 	"github.com/picogrid/legion-simulations/pkg/simulation"
@@ -26,273 +24,150 @@ func PromptForParameters(params []simulation.Parameter) (map[string]interface{},
 	return result, nil
 }
 
-// promptForParameter prompts for a single parameter
-func promptForParameter(param simulation.Parameter) (interface{}, error) {
-	// Check if we should skip prompts entirely (for CI/automation)
-	if os.Getenv("LEGION_SKIP_PROMPTS") == "true" {
-		// Check for environment variable override
+// ParametersWithoutPrompts resolves parameters for runs without a terminal: each takes its
+// LEGION_* environment variable, or else its default converted to the parameter's type. A required parameter with neither is
+// an error; an optional one is left out.
+func ParametersWithoutPrompts(params []simulation.Parameter) (map[string]interface{}, error) {
+	result := make(map[string]interface{})
+	for _, param := range params {
 		envKey := "LEGION_" + strings.ToUpper(param.Name)
 		if envValue := os.Getenv(envKey); envValue != "" {
-			return parseEnvValue(envValue, param)
+			value, err := param.Resolve(envValue)
+			if err != nil {
+				return nil, fmt.Errorf("invalid %s: %w", envKey, err)
+			}
+			result[param.Name] = value
+			continue
 		}
-		// Use default if no env var set
 		if param.Default != nil {
-			return param.Default, nil
+			value, err := param.Resolve(param.Default)
+			if err != nil {
+				return nil, fmt.Errorf("invalid default for %s: %w", param.Name, err)
+			}
+			result[param.Name] = value
+			continue
 		}
 		if param.Required {
 			return nil, fmt.Errorf("required parameter %s not provided and no default available", param.Name)
 		}
 	}
-
-	// Check for environment variable to use as default
-	envKey := "LEGION_" + strings.ToUpper(param.Name)
-	if envValue := os.Getenv(envKey); envValue != "" {
-		// Update the default value from environment
-		parsed, err := parseEnvValue(envValue, param)
-		if err == nil {
-			param.Default = parsed
-		}
-	}
-
-	switch param.Type {
-	case "integer":
-		return promptInteger(param)
-	case "float":
-		return promptFloat(param)
-	case "string":
-		return promptString(param)
-	case "boolean":
-		return promptBoolean(param)
-	case "duration":
-		return promptDuration(param)
-	default:
-		return nil, fmt.Errorf("unsupported parameter type: %s", param.Type)
-	}
+	return result, nil
 }
 
-// parseEnvValue parses an environment variable value according to the parameter type
-func parseEnvValue(value string, param simulation.Parameter) (interface{}, error) {
+// promptForParameter builds a prompt from the parameter's schema: a confirm for a
+// boolean, a select for a string with options, and otherwise an input checked against
+// the type, range and options as it is typed, so a bad value asks again
+func promptForParameter(param simulation.Parameter) (interface{}, error) {
 	switch param.Type {
-	case "integer":
-		return strconv.Atoi(value)
-	case "float":
-		return strconv.ParseFloat(value, 64)
-	case "string":
-		return value, nil
-	case "boolean":
-		return strconv.ParseBool(value)
-	case "duration":
-		duration, err := time.ParseDuration(value)
-		if err != nil {
-			return nil, err
-		}
-		return duration, nil
+	case "integer", "float", "string", "boolean", "duration":
 	default:
 		return nil, fmt.Errorf("unsupported parameter type: %s", param.Type)
 	}
-}
-
-func promptInteger(param simulation.Parameter) (int, error) {
-	defaultStr := ""
-	if param.Default != nil {
-		switch v := param.Default.(type) {
-		case int:
-			defaultStr = strconv.Itoa(v)
-		case float64:
-			defaultStr = strconv.Itoa(int(v))
-		}
-	}
-
-	prompt := &survey.Input{
-		Message: param.Description,
-		Default: defaultStr,
-	}
 
-	var result string
-	if err := survey.AskOne(prompt, &result, survey.WithValidator(survey.Required)); err != nil {
-		return 0, err
-	}
-
-	value, err := strconv.Atoi(result)
-	if err != nil {
-		return 0, fmt.Errorf("invalid integer: %w", err)
-	}
+	envKey := "LEGION_" + strings.ToUpper(param.Name)
+	envValue := os.Getenv(envKey)
 
-	// Validate range
-	if param.Min != nil {
-		minRange := toInt(param.Min)
-		if value < minRange {
-			return 0, fmt.Errorf("value must be at least %d", minRange)
+	// Check if we should skip prompts entirely (for CI/automation)
+	if os.Getenv("LEGION_SKIP_PROMPTS") == "true" {
+		if envValue != "" {
+			return param.Resolve(envValue)
 		}
-	}
-	if param.Max != nil {
-		maxRange := toInt(param.Max)
-		if value > maxRange {
-			return 0, fmt.Errorf("value must be at most %d", maxRange)
+		if param.Default != nil {
+			return param.Resolve(param.Default)
 		}
-	}
-
-	return value, nil
-}
-
-func promptFloat(param simulation.Parameter) (float64, error) {
-	defaultStr := ""
-	if param.Default != nil {
-		defaultStr = fmt.Sprintf("%v", param.Default)
-	}
-
-	prompt := &survey.Input{
-		Message: param.Description,
-		Default: defaultStr,
-	}
-
-	var result string
-	if err := survey.AskOne(prompt, &result, survey.WithValidator(survey.Required)); err != nil {
-		return 0, err
-	}
-
-	value, err := strconv.ParseFloat(result, 64)
-	if err != nil {
-		return 0, fmt.Errorf("invalid number: %w", err)
-	}
-
-	// Validate range
-	if param.Min != nil {
-		minRange := toFloat64(param.Min)
-		if value < minRange {
-			return 0, fmt.Errorf("value must be at least %g", minRange)
+		if param.Required {
+			return nil, fmt.Errorf("required parameter %s not provided and no default available", param.Name)
 		}
 	}
-	if param.Max != nil {
-		maxRange := toFloat64(param.Max)
-		if value > maxRange {
-			return 0, fmt.Errorf("value must be at most %g", maxRange)
+
+	// An environment variable replaces the default offered
+	defaultValue := param.Default
+	if envValue != "" {
+		if parsed, err := param.Resolve(envValue); err == nil {
+			defaultValue = parsed
 		}
 	}
-
-	return value, nil
-}
-
-func promptString(param simulation.Parameter) (string, error) {
-	defaultStr := ""
-	if param.Default != nil {
-		defaultStr = fmt.Sprintf("%v", param.Default)
+	var defaultTyped interface{}
+	if defaultValue != nil {
+		defaultTyped, _ = param.Convert(defaultValue)
 	}
 
-	// If options are provided, use a select prompt
-	if len(param.Options) > 0 {
-		prompt := &survey.Select{
-			Message: param.Description,
-			Options: param.Options,
-			Default: defaultStr,
+	switch {
+	case param.Type == "boolean":
+		defaultBool, _ := defaultTyped.(bool)
+		var result bool
+		if err := survey.AskOne(&survey.Confirm{Message: param.Description, Default: defaultBool}, &result); err != nil {
+			return nil, err
 		}
+		return result, nil
 
+	case len(param.Options) > 0:
+		prompt := &survey.Select{Message: param.Description, Options: param.Options}
+		if defaultTyped != nil {
+			prompt.Default = fmt.Sprint(defaultTyped)
+		}
 		var result string
 		if err := survey.AskOne(prompt, &result); err != nil {
-			return "", err
+			return nil, err
 		}
-		return result, nil
+		return param.Resolve(result)
 	}
 
-	// Otherwise use input prompt
-	prompt := &survey.Input{
-		Message: param.Description,
-		Default: defaultStr,
+	prompt := &survey.Input{Message: param.Description, Help: parameterHelp(param)}
+	if param.Type == "duration" {
+		prompt.Message += " (e.g., 5m, 1h30m, 30s)"
 	}
-
-	var result string
-	var validators []survey.Validator
-	if param.Required {
-		validators = append(validators, survey.Required)
+	if defaultTyped != nil {
+		prompt.Default = fmt.Sprint(defaultTyped)
 	}
 
-	if err := survey.AskOne(prompt, &result, survey.WithValidator(survey.ComposeValidators(validators...))); err != nil {
-		return "", err
+	var result string
+	if err := survey.AskOne(prompt, &result, survey.WithValidator(parameterValidator(param))); err != nil {
+		return nil, err
 	}
-
-	return result, nil
+	return param.Resolve(result)
 }
 
-func promptBoolean(param simulation.Parameter) (bool, error) {
-	defaultBool := false
-	if param.Default != nil {
-		switch v := param.Default.(type) {
-		case bool:
-			defaultBool = v
-		case string:
-			defaultBool = v == "true" || v == "yes" || v == "1"
+// parameterValidator checks typed input against the parameter's schema
+func parameterValidator(param simulation.Parameter) survey.Validator {
+	return func(answer interface{}) error {
+		value, _ := answer.(string)
+		if strings.TrimSpace(value) == "" {
+			if param.Type == "string" && !param.Required {
+				return nil
+			}
+			return fmt.Errorf("a value is required")
 		}
-	}
-
-	prompt := &survey.Confirm{
-		Message: param.Description,
-		Default: defaultBool,
-	}
-
-	var result bool
-	if err := survey.AskOne(prompt, &result); err != nil {
-		return false, err
-	}
-
-	return result, nil
-}
-
-func promptDuration(param simulation.Parameter) (time.Duration, error) {
-	defaultStr := ""
-	if param.Default != nil {
-		defaultStr = fmt.Sprintf("%v", param.Default)
-	}
-
-	prompt := &survey.Input{
-		Message: param.Description + " (e.g., 5m, 1h30m, 30s)",
-		Default: defaultStr,
-	}
-
-	var result string
-	if err := survey.AskOne(prompt, &result, survey.WithValidator(func(val interface{}) error {
-		str := val.(string)
-		_, err := time.ParseDuration(str)
+		converted, err := param.Parse(value)
 		if err != nil {
-			return fmt.Errorf("invalid duration format (use formats like 5m, 1h30m, 30s)")
+			return fmt.Errorf("enter %s", typeDescription(param.Type))
 		}
-		return nil
-	})); err != nil {
-		return 0, err
-	}
-
-	duration, err := time.ParseDuration(result)
-	if err != nil {
-		return 0, fmt.Errorf("failed to parse duration: %w", err)
+		return param.Validate(converted)
 	}
-
-	return duration, nil
 }
 
-// Helper functions
-func toInt(v interface{}) int {
-	switch val := v.(type) {
-	case int:
-		return val
-	case float64:
-		return int(val)
-	case string:
-		i, _ := strconv.Atoi(val)
-		return i
-	default:
-		return 0
+// parameterHelp describes what the parameter accepts, shown on "?"
+func parameterHelp(param simulation.Parameter) string {
+	help := fmt.Sprintf("%s: %s", param.Name, typeDescription(param.Type))
+	if param.Min != nil {
+		help += fmt.Sprintf(", at least %v", param.Min)
+	}
+	if param.Max != nil {
+		help += fmt.Sprintf(", at most %v", param.Max)
 	}
+	return help
 }
 
-func toFloat64(v interface{}) float64 {
-	switch val := v.(type) {
-	case float64:
-		return val
-	case int:
-		return float64(val)
-	case string:
-		f, _ := strconv.ParseFloat(val, 64)
-		return f
+// typeDescription names what a parameter type accepts
+func typeDescription(paramType string) string {
+	switch paramType {
+	case "integer":
+		return "a whole number"
+	case "float":
+		return "a number"
+	case "duration":
+		return "a duration such as 5m, 1h30m or 30s"
 	default:
-		return 0
+		return "text"
 	}
 }
@@ -0,0 +1,88 @@
+package world
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// requestTimeout keeps a slow world service from stalling a simulation tick
+const requestTimeout = 2 * time.Second
+
+// Client is a World served by another process
+type Client struct {
+	baseURL string
+	http    *http.Client
+}
+
+// NewClient creates a client for the world service at baseURL
+func NewClient(baseURL string) *Client {
+	return &Client{baseURL: strings.TrimRight(baseURL, "/"), http: &http.Client{Timeout: requestTimeout}}
+}
+
+// Publish sends the assets in one request
+func (c *Client) Publish(ctx context.Context, assets ...Asset) error {
+	return c.do(ctx, http.MethodPut, "/v1/assets", assets, nil)
+}
+
+// Assets fetches every live asset
+func (c *Client) Assets(ctx context.Context) ([]Asset, error) {
+	var assets []Asset
+	if err := c.do(ctx, http.MethodGet, "/v1/assets", nil, &assets); err != nil {
+		return nil, err
+	}
+	return assets, nil
+}
+
+// Withdraw removes an owner's assets
+func (c *Client) Withdraw(ctx context.Context, owner string) error {
+	return c.do(ctx, http.MethodDelete, "/v1/assets?owner="+url.QueryEscape(owner), nil, nil)
+}
+
+// Hit records a strike on an asset
+func (c *Client) Hit(ctx context.Context, id, by string) error {
+	return c.do(ctx, http.MethodPost, "/v1/assets/"+url.PathEscape(id)+"/hits", hitRequest{By: by}, nil)
+}
+
+func (c *Client) do(ctx context.Context, method, path string, in, out interface{}) error {
+	var body io.Reader
+	if in != nil {
+		data, err := json.Marshal(in)
+		if err != nil {
+			return fmt.Errorf("failed to encode request: %w", err)
+		}
+		body = bytes.NewReader(data)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, method, c.baseURL+path, body)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to reach world service: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	if resp.StatusCode/100 != 2 {
+		msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		return fmt.Errorf("world service returned status %d: %s", resp.StatusCode, strings.TrimSpace(string(msg)))
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+package world
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/picogrid/legion-simulations/pkg/logger"
+)
+
+// ErrUnknownAsset is returned when striking an asset that isn't published
+var ErrUnknownAsset = errors.New("unknown asset")
+
+// Local is an in-process world. It is safe for concurrent use and can be served over
+// HTTP for simulations in other processes.
+type Local struct {
+	assets map[string]Asset
+	ttl    time.Duration
+	mu     sync.Mutex
+}
+
+// NewLocal creates an empty world that forgets assets not republished within ttl
+// (0 keeps them until withdrawn)
+func NewLocal(ttl time.Duration) *Local {
+	return &Local{assets: make(map[string]Asset), ttl: ttl}
+}
+
+// Publish adds or updates assets, keeping hits others recorded against them
+func (l *Local) Publish(_ context.Context, assets ...Asset) error {
+	now := time.Now()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for _, asset := range assets {
+		if asset.ID == "" || asset.Owner == "" {
+			return fmt.Errorf("asset %q: id and owner are required", asset.Name)
+		}
+		if existing, ok := l.assets[asset.ID]; ok {
+			asset.Hits, asset.LastHitBy = existing.Hits, existing.LastHitBy
+		}
+		asset.Updated = now
+		l.assets[asset.ID] = asset
+	}
+	return nil
+}
+
+// Assets returns the live assets ordered by owner and name
+func (l *Local) Assets(_ context.Context) ([]Asset, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.expire()
+
+	assets := make([]Asset, 0, len(l.assets))
+	for _, asset := range l.assets {
+		assets = append(assets, asset)
+	}
+	sort.Slice(assets, func(i, j int) bool {
+		if assets[i].Owner != assets[j].Owner {
+			return assets[i].Owner < assets[j].Owner
+		}
+		return assets[i].Name < assets[j].Name
+	})
+	return assets, nil
+}
+
+// Withdraw removes an owner's assets
+func (l *Local) Withdraw(_ context.Context, owner string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	for id, asset := range l.assets {
+		if asset.Owner == owner {
+			delete(l.assets, id)
+		}
+	}
+	return nil
+}
+
+// Hit records a strike on an asset
+func (l *Local) Hit(_ context.Context, id, by string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	asset, ok := l.assets[id]
+	if !ok {
+		return fmt.Errorf("%w %s", ErrUnknownAsset, id)
+	}
+	asset.Hits++
+	asset.LastHitBy = by
+	l.assets[id] = asset
+	return nil
+}
+
+// expire drops assets whose owner stopped publishing. Callers hold l.mu.
+func (l *Local) expire() {
+	if l.ttl <= 0 {
+		return
+	}
+	cutoff := time.Now().Add(-l.ttl)
+	for id, asset := range l.assets {
+		if asset.Updated.Before(cutoff) {
+			delete(l.assets, id)
+		}
+	}
+}
+
+// Handler serves the world endpoints
+func (l *Local) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /v1/assets", func(w http.ResponseWriter, r *http.Request) {
+		assets, _ := l.Assets(r.Context())
+		writeJSON(w, http.StatusOK, assets)
+	})
+	mux.HandleFunc("PUT /v1/assets", func(w http.ResponseWriter, r *http.Request) {
+		var assets []Asset
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4<<20)).Decode(&assets); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := l.Publish(r.Context(), assets...); err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("DELETE /v1/assets", func(w http.ResponseWriter, r *http.Request) {
+		owner := r.URL.Query().Get("owner")
+		if owner == "" {
+			http.Error(w, "owner is required", http.StatusBadRequest)
+			return
+		}
+		_ = l.Withdraw(r.Context(), owner)
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.HandleFunc("POST /v1/assets/{id}/hits", func(w http.ResponseWriter, r *http.Request) {
+		var req hitRequest
+		if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 4096)).Decode(&req); err != nil {
+			http.Error(w, fmt.Sprintf("invalid request body: %v", err), http.StatusBadRequest)
+			return
+		}
+		if err := l.Hit(r.Context(), r.PathValue("id"), req.By); err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		w.WriteHeader(http.StatusNoContent)
+	})
+	return mux
+}
+
+// Serve starts the world service in the background. Shut it down with the returned server.
+func (l *Local) Serve(addr string) *http.Server {
+	server := &http.Server{
+		Addr:              addr,
+		Handler:           l.Handler(),
+		ReadHeaderTimeout: 5 * time.Second,
+	}
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			logger.Errorf("World service stopped: %v", err)
+		}
+	}()
+	logger.Infof("%s Simulations can share this world with world_url=http://<host>%s", logger.IconNetwork, addr)
+	return server
+}
+
+// hitRequest is the body of a strike report
+type hitRequest struct {
+	By string `json:"by"`
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(v)
+}
@@ -0,0 +1,83 @@
+// Package world shares ground truth between simulations running at the same time in
+// the same area, so different scenarios can interact: a convoy published by one run
+// becomes a defended asset that another run's swarm attacks and its defenders protect.
+//
+// Each simulation publishes the assets it owns and reads everyone else's. A World is
+// either in-process (Local, for simulations embedded in one program with the runner
+// package) or remote (Client, for simulations in separate processes). `legion-sim world
+// serve` hosts a Local over HTTP:
+//
+//	GET    /v1/assets              every live asset
+//	PUT    /v1/assets              [{...}] publishes or updates assets
+//	DELETE /v1/assets?owner={name} withdraws an owner's assets
+//	POST   /v1/assets/{id}/hits    {"by": "..."} records a strike on an asset
+package world
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// DefaultListenAddr is the default world service address
+const DefaultListenAddr = ":7700"
+
+// DefaultTTL drops assets whose owner stopped publishing, e.g. after a crash
+const DefaultTTL = 30 * time.Second
+
+// Asset roles
+const (
+	RoleDefended = "defended" // Protected by its owner; other scenarios may attack it
+	RoleTraffic  = "traffic"  // Background movement for situational awareness
+	RoleThreat   = "threat"   // Hostile; other scenarios may avoid or engage it
+)
+
+// Asset is an entity a simulation shares with the others
+type Asset struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Owner     string    `json:"owner"` // Publishing simulation
+	Role      string    `json:"role"`
+	Lat       float64   `json:"lat"`
+	Lon       float64   `json:"lon"`
+	Alt       float64   `json:"alt"` // Meters MSL
+	Hits      int       `json:"hits,omitempty"`
+	LastHitBy string    `json:"last_hit_by,omitempty"`
+	Updated   time.Time `json:"updated"`
+}
+
+// World is shared state between concurrently running simulations
+type World interface {
+	// Publish adds or updates the caller's assets. Hits recorded by others are kept.
+	Publish(ctx context.Context, assets ...Asset) error
+	// Assets returns every live asset, including the caller's own
+	Assets(ctx context.Context) ([]Asset, error)
+	// Withdraw removes every asset an owner published
+	Withdraw(ctx context.Context, owner string) error
+	// Hit records a strike on another simulation's asset
+	Hit(ctx context.Context, id, by string) error
+}
+
+// shared is the process-wide world for simulations embedded in one program
+var shared = NewLocal(DefaultTTL)
+
+// Shared returns the in-process world every "local" simulation in this program uses
+func Shared() *Local {
+	return shared
+}
+
+// Open returns the world named by spec: "local" for the in-process world, or the
+// http(s) URL of a world service. An empty spec returns nil.
+func Open(spec string) (World, error) {
+	spec = strings.TrimSpace(spec)
+	switch {
+	case spec == "":
+		return nil, nil
+	case spec == "local":
+		return shared, nil
+	case strings.HasPrefix(spec, "http://"), strings.HasPrefix(spec, "https://"):
+		return NewClient(spec), nil
+	}
+	return nil, fmt.Errorf("world %q: expected \"local\" or an http(s) URL", spec)
+}
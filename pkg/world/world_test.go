@@ -0,0 +1,60 @@
+package world
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestWorldOverHTTP(t *testing.T) {
+	local := NewLocal(DefaultTTL)
+	server := httptest.NewServer(local.Handler())
+	defer server.Close()
+
+	ctx := context.Background()
+	convoy, swarm := NewClient(server.URL), World(local)
+
+	if err := convoy.Publish(ctx, Asset{ID: "c1", Name: "Convoy Lead", Owner: "Convoy", Role: RoleDefended, Lat: 40, Lon: -76}); err != nil {
+		t.Fatal(err)
+	}
+	if err := swarm.Hit(ctx, "c1", "Drone Swarm Combat"); err != nil {
+		t.Fatal(err)
+	}
+	// Republishing a moved asset keeps the strike
+	if err := convoy.Publish(ctx, Asset{ID: "c1", Name: "Convoy Lead", Owner: "Convoy", Role: RoleDefended, Lat: 40.01, Lon: -76}); err != nil {
+		t.Fatal(err)
+	}
+
+	assets, err := convoy.Assets(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(assets) != 1 || assets[0].Lat != 40.01 || assets[0].Hits != 1 || assets[0].LastHitBy != "Drone Swarm Combat" {
+		t.Fatalf("unexpected assets %+v", assets)
+	}
+
+	if err := convoy.Hit(ctx, "missing", "x"); err == nil {
+		t.Error("expected a strike on an unknown asset to fail")
+	}
+	if err := convoy.Withdraw(ctx, "Convoy"); err != nil {
+		t.Fatal(err)
+	}
+	if assets, _ := swarm.Assets(ctx); len(assets) != 0 {
+		t.Errorf("expected the convoy withdrawn, got %+v", assets)
+	}
+}
+
+func TestLocalExpiresStaleAssets(t *testing.T) {
+	local := NewLocal(time.Minute)
+	ctx := context.Background()
+	_ = local.Publish(ctx, Asset{ID: "a", Owner: "Crashed"})
+	local.assets["a"] = Asset{ID: "a", Owner: "Crashed", Updated: time.Now().Add(-2 * time.Minute)}
+
+	if assets, _ := local.Assets(ctx); len(assets) != 0 {
+		t.Errorf("expected a stale asset to expire, got %+v", assets)
+	}
+	if _, err := Open("ftp://nowhere"); err == nil {
+		t.Error("expected an unsupported world URL to be rejected")
+	}
+}